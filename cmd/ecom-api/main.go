@@ -27,7 +27,7 @@ func main() {
 	defer conn.Close()
 
 	client := pb.NewEcomClient(conn)
-	hdl := handler.NewHandler(client, os.Getenv("SECRET_KEY"))
+	hdl := handler.NewHandler(client, os.Getenv("SECRET_KEY"), os.Getenv("PAYMENT_WEBHOOK_SECRET"))
 	handler.RegisterRouters(hdl)
 
 	err = handler.Start(":8080")
@@ -1,33 +1,87 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"log"
 	"net"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/OrkhanMehbaliyev/ecom-golang/db"
 	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
 	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/server"
 	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/storer"
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"github.com/OrkhanMehbaliyev/ecom-golang/payments"
 	"github.com/joho/godotenv"
 	"google.golang.org/grpc"
 )
 
 func main() {
+	inMemory := flag.Bool("in-memory", false, "use the in-memory storer instead of MySQL")
+	autoMigrate := flag.Bool("auto-migrate", false, "apply pending database migrations on start")
+	paymentDelay := flag.Duration("payment-delay", 5*time.Second, "how long the fake payment provider keeps pending payments pending")
+	flag.Parse()
+
 	err := godotenv.Load("../../.env")
 	if err != nil {
 		log.Fatalf("error loading .env file: %s", err)
 	}
 
-	db, err := db.NewDatabase()
-	if err != nil {
-		log.Fatalf("error opening database: %v", err)
+	var st storer.Storer
+	if *inMemory {
+		st = storer.NewMemoryStorer()
+		log.Printf("using in-memory storer")
+	} else {
+		database, err := db.NewDatabase()
+		if err != nil {
+			log.Fatalf("error opening database: %v", err)
+		}
+		defer database.Close()
+		log.Printf("successfully connected to database")
+
+		if *autoMigrate {
+			m, err := db.NewMigrator(database.GetDB())
+			if err != nil {
+				log.Fatalf("error loading migrations: %v", err)
+			}
+			n, err := m.Up(context.Background())
+			if err != nil {
+				log.Fatalf("error migrating database: %v", err)
+			}
+			log.Printf("applied %d migrations", n)
+		}
+
+		st = storer.NewMySQLStorer(database.GetDB())
 	}
-	defer db.Close()
-	log.Printf("successfully connected to database")
 
-	st := storer.NewMySQLStorer(db.GetDB())
-	srv := server.NewServer(st)
+	// the fake provider reports on pending payments through the API's webhook
+	var notifier payments.Notifier
+	if url := os.Getenv("PAYMENT_WEBHOOK_URL"); url != "" {
+		notifier = payments.NewWebhookNotifier(url, os.Getenv("PAYMENT_WEBHOOK_SECRET"))
+	}
+	// lines of the seller's address are separated by semicolons
+	var sellerAddress storer.StringList
+	for _, l := range strings.Split(os.Getenv("SELLER_ADDRESS"), ";") {
+		if l = strings.TrimSpace(l); l != "" {
+			sellerAddress = append(sellerAddress, l)
+		}
+	}
+	seller := storer.Seller{
+		Name:    os.Getenv("SELLER_NAME"),
+		Address: sellerAddress,
+		Email:   os.Getenv("SELLER_EMAIL"),
+		TaxID:   os.Getenv("SELLER_TAX_ID"),
+	}
+	loyalty, err := loyaltyProgram()
+	if err != nil {
+		log.Fatalf("error configuring loyalty program: %v", err)
+	}
+	srv := server.NewServer(st, payments.NewFakeProvider(*paymentDelay, notifier), seller, loyalty)
 
 	grpcSrv := grpc.NewServer()
 	pb.RegisterEcomServer(grpcSrv, srv)
@@ -43,3 +97,55 @@ func main() {
 		log.Fatalf("failed to serve: %v", err)
 	}
 }
+
+// loyaltyProgram reads the loyalty program from the environment, keeping the
+// default for anything not set. Tiers are given as name:min_spend:multiplier,
+// separated by semicolons and lowest first, e.g. "silver:500:125;gold:2000:150".
+func loyaltyProgram() (server.LoyaltyProgram, error) {
+	lp := server.DefaultLoyaltyProgram()
+
+	if v := os.Getenv("LOYALTY_EARN_RATE"); v != "" {
+		rate, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || rate < 0 {
+			return lp, fmt.Errorf("invalid LOYALTY_EARN_RATE %q", v)
+		}
+		lp.EarnRate = rate
+	}
+	if v := os.Getenv("LOYALTY_POINT_VALUE"); v != "" {
+		value, err := money.Parse(v, money.DefaultCurrency)
+		if err != nil || value.Amount < 0 {
+			return lp, fmt.Errorf("invalid LOYALTY_POINT_VALUE %q", v)
+		}
+		lp.PointValue = value
+	}
+	if v := os.Getenv("LOYALTY_POINTS_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil || ttl < 0 {
+			return lp, fmt.Errorf("invalid LOYALTY_POINTS_TTL %q", v)
+		}
+		lp.PointsTTL = ttl
+	}
+	if v := os.Getenv("LOYALTY_TIERS"); v != "" {
+		lp.Tiers = nil
+		for _, t := range strings.Split(v, ";") {
+			parts := strings.Split(strings.TrimSpace(t), ":")
+			if len(parts) != 3 {
+				return lp, fmt.Errorf("invalid loyalty tier %q", t)
+			}
+			minSpend, err := money.Parse(parts[1], money.DefaultCurrency)
+			if err != nil {
+				return lp, fmt.Errorf("invalid minimum spend of loyalty tier %q: %w", t, err)
+			}
+			multiplier, err := strconv.ParseInt(parts[2], 10, 64)
+			if err != nil || multiplier < 0 {
+				return lp, fmt.Errorf("invalid multiplier of loyalty tier %q", t)
+			}
+			if n := len(lp.Tiers); n > 0 && minSpend.Cmp(lp.Tiers[n-1].MinSpend) <= 0 {
+				return lp, fmt.Errorf("loyalty tier %q must need more spend than the one before", t)
+			}
+			lp.Tiers = append(lp.Tiers, server.LoyaltyTier{Name: parts[0], MinSpend: minSpend, Multiplier: multiplier})
+		}
+	}
+
+	return lp, nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/db"
+)
+
+const usage = `usage: ecom-migrate <command>
+
+commands:
+  up         apply all pending migrations
+  down [N]   revert the last N migrations (default 1)
+  status     show the schema version and pending migrations
+  force V    set the schema version to V without running migrations
+`
+
+func main() {
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	database, err := db.NewDatabase()
+	if err != nil {
+		log.Fatalf("error opening database: %v", err)
+	}
+	defer database.Close()
+
+	m, err := db.NewMigrator(database.GetDB())
+	if err != nil {
+		log.Fatalf("error loading migrations: %v", err)
+	}
+
+	ctx := context.Background()
+	switch cmd, args := flag.Arg(0), flag.Args()[1:]; cmd {
+	case "up":
+		n, err := m.Up(ctx)
+		if err != nil {
+			log.Fatalf("migrating up: %v", err)
+		}
+		log.Printf("applied %d migrations", n)
+
+	case "down":
+		steps := 1
+		if len(args) > 0 {
+			steps, err = strconv.Atoi(args[0])
+			if err != nil || steps < 1 {
+				log.Fatalf("invalid number of migrations %q", args[0])
+			}
+		}
+		n, err := m.Down(ctx, steps)
+		if err != nil {
+			log.Fatalf("migrating down: %v", err)
+		}
+		log.Printf("reverted %d migrations", n)
+
+	case "status":
+		st, err := m.Status(ctx)
+		if err != nil {
+			log.Fatalf("getting status: %v", err)
+		}
+		fmt.Printf("version: %d", st.Version)
+		if st.Dirty {
+			fmt.Print(" (dirty)")
+		}
+		fmt.Println()
+		for _, mig := range st.Migrations {
+			state := "pending"
+			if mig.Applied {
+				state = "applied"
+			}
+			fmt.Printf("%-8s %d_%s\n", state, mig.Version, mig.Name)
+		}
+
+	case "force":
+		if len(args) == 0 {
+			log.Fatalf("force needs a version")
+		}
+		version, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			log.Fatalf("invalid version %q", args[0])
+		}
+		if err := m.Force(ctx, version); err != nil {
+			log.Fatalf("forcing version: %v", err)
+		}
+		log.Printf("forced version %d", version)
+
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,392 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Constants
+const (
+	// name of the MySQL advisory lock held while migrating
+	migrationLock = "ecom_schema_migrations"
+	// how long to wait for another instance to finish migrating
+	migrationLockTimeout = 5 * time.Minute
+)
+
+var (
+	ErrDirty         = errors.New("database is in a dirty state, fix it manually and run force")
+	ErrLocked        = errors.New("timed out waiting for the migration lock")
+	ErrNoMigration   = errors.New("no such migration")
+	migrationPattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+)
+
+// Migration is one versioned schema change read from db/migrations.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationState is a migration together with whether it is applied.
+type MigrationState struct {
+	Migration
+	Applied bool
+}
+
+// MigrationStatus describes the schema version of a database.
+type MigrationStatus struct {
+	Version    int64
+	Dirty      bool
+	Migrations []MigrationState
+}
+
+// Migrator applies the embedded migrations and records the schema version in
+// the schema_migrations table, one row holding the version of the last
+// applied migration and whether it failed half way. The table layout matches
+// golang-migrate, so databases migrated with its CLI are picked up as is.
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *sqlx.DB) (*Migrator, error) {
+	migrations, err := LoadMigrations(migrationFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// LoadMigrations reads every <version>_<name>.up.sql and .down.sql pair in
+// the migrations directory of fsys, ordered by version.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	paths, err := fs.Glob(fsys, "migrations/*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("error listing migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, p := range paths {
+		m := migrationPattern.FindStringSubmatch(path.Base(p))
+		if m == nil {
+			return nil, fmt.Errorf("error loading migrations: unexpected file name %q", p)
+		}
+
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing version of %q: %w", p, err)
+		}
+
+		b, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration: %w", err)
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		}
+		if m[3] == "up" {
+			mig.Up = string(b)
+		} else {
+			mig.Down = string(b)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("error loading migrations: %d_%s needs both an up and a down file", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// splitStatements splits a migration into single statements, since the
+// MySQL driver runs one statement per Exec. Semicolons inside quotes and
+// comments do not end a statement.
+func splitStatements(script string) []string {
+	var stmts []string
+	var b strings.Builder
+	var quote rune
+	lineComment := false
+
+	flush := func() {
+		if s := strings.TrimSpace(b.String()); s != "" {
+			stmts = append(stmts, s)
+		}
+		b.Reset()
+	}
+
+	runes := []rune(script)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case lineComment:
+			if r == '\n' {
+				lineComment = false
+			}
+			continue
+		case quote != 0:
+			if r == '\\' && i+1 < len(runes) {
+				b.WriteRune(r)
+				i++
+				r = runes[i]
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-', r == '#':
+			lineComment = true
+			continue
+		case r == ';':
+			flush()
+			continue
+		}
+		b.WriteRune(r)
+	}
+	flush()
+
+	return stmts
+}
+
+// Up applies every migration newer than the current version and returns how
+// many were applied.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	var applied int
+	err := m.withLock(ctx, func(conn *sqlx.Conn) error {
+		version, err := m.checkedVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			if mig.Version <= version {
+				continue
+			}
+
+			err := m.run(ctx, conn, mig.Version, mig.Up, mig.Version)
+			if err != nil {
+				return fmt.Errorf("error applying %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			applied++
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down reverts the last n applied migrations and returns how many were
+// reverted.
+func (m *Migrator) Down(ctx context.Context, n int) (int, error) {
+	var reverted int
+	err := m.withLock(ctx, func(conn *sqlx.Conn) error {
+		version, err := m.checkedVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && reverted < n; i-- {
+			mig := m.migrations[i]
+			if mig.Version > version {
+				continue
+			}
+
+			var prev int64
+			if i > 0 {
+				prev = m.migrations[i-1].Version
+			}
+
+			err := m.run(ctx, conn, mig.Version, mig.Down, prev)
+			if err != nil {
+				return fmt.Errorf("error reverting %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			reverted++
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status reports the current version and which migrations are applied.
+func (m *Migrator) Status(ctx context.Context) (*MigrationStatus, error) {
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting connection: %w", err)
+	}
+	defer conn.Close()
+
+	if err := ensureVersionTable(ctx, conn); err != nil {
+		return nil, err
+	}
+	version, dirty, err := currentVersion(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &MigrationStatus{Version: version, Dirty: dirty}
+	for _, mig := range m.migrations {
+		status.Migrations = append(status.Migrations, MigrationState{
+			Migration: mig,
+			Applied:   mig.Version <= version && !(dirty && mig.Version == version),
+		})
+	}
+
+	return status, nil
+}
+
+// Force records version as the current, clean schema version without running
+// any migration. It is used to recover after a migration failed half way and
+// the schema was repaired by hand. Version 0 means no migration is applied.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version != 0 && !m.hasVersion(version) {
+		return fmt.Errorf("error forcing version %d: %w", version, ErrNoMigration)
+	}
+
+	return m.withLock(ctx, func(conn *sqlx.Conn) error {
+		return setVersion(ctx, conn, version, false)
+	})
+}
+
+func (m *Migrator) hasVersion(version int64) bool {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// run executes script, marking the schema dirty at version while it runs and
+// clean at after once it succeeded. MySQL commits DDL implicitly, so a failed
+// script cannot be rolled back and leaves the dirty flag set.
+func (m *Migrator) run(ctx context.Context, conn *sqlx.Conn, version int64, script string, after int64) error {
+	err := setVersion(ctx, conn, version, true)
+	if err != nil {
+		return err
+	}
+
+	for _, stmt := range splitStatements(script) {
+		_, err := conn.ExecContext(ctx, stmt)
+		if err != nil {
+			return fmt.Errorf("error executing %q: %w", stmt, err)
+		}
+	}
+
+	return setVersion(ctx, conn, after, false)
+}
+
+// checkedVersion returns the current version, refusing to continue from a
+// dirty state.
+func (m *Migrator) checkedVersion(ctx context.Context, conn *sqlx.Conn) (int64, error) {
+	version, dirty, err := currentVersion(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("version %d: %w", version, ErrDirty)
+	}
+
+	return version, nil
+}
+
+// withLock runs fn on a single connection holding a MySQL advisory lock, so
+// that concurrently starting instances migrate one after the other.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sqlx.Conn) error) error {
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting connection: %w", err)
+	}
+	defer conn.Close()
+
+	var locked sql.NullInt64
+	err = conn.GetContext(ctx, &locked, "SELECT GET_LOCK(?, ?)", migrationLock, int(migrationLockTimeout/time.Second))
+	if err != nil {
+		return fmt.Errorf("error acquiring migration lock: %w", err)
+	}
+	if locked.Int64 != 1 {
+		return ErrLocked
+	}
+	defer conn.ExecContext(context.WithoutCancel(ctx), "SELECT RELEASE_LOCK(?)", migrationLock)
+
+	if err := ensureVersionTable(ctx, conn); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+func ensureVersionTable(ctx context.Context, conn *sqlx.Conn) error {
+	_, err := conn.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)")
+	if err != nil {
+		return fmt.Errorf("error creating schema_migrations table: %w", err)
+	}
+
+	return nil
+}
+
+func currentVersion(ctx context.Context, conn *sqlx.Conn) (int64, bool, error) {
+	var v struct {
+		Version int64 `db:"version"`
+		Dirty   bool  `db:"dirty"`
+	}
+	err := conn.GetContext(ctx, &v, "SELECT version, dirty FROM schema_migrations LIMIT 1")
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("error getting schema version: %w", err)
+	}
+
+	return v.Version, v.Dirty, nil
+}
+
+func setVersion(ctx context.Context, conn *sqlx.Conn, version int64, dirty bool) error {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM schema_migrations")
+	if err == nil && version > 0 {
+		_, err = tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, dirty) VALUES (?, ?)", version, dirty)
+	}
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("error setting schema version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing schema version: %w", err)
+	}
+
+	return nil
+}
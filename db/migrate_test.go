@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := LoadMigrations(migrationFiles)
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	require.Equal(t, int64(20250519192108), migrations[0].Version)
+	require.Equal(t, "init_schema", migrations[0].Name)
+	for i := 1; i < len(migrations); i++ {
+		require.Less(t, migrations[i-1].Version, migrations[i].Version)
+	}
+
+	_, err = LoadMigrations(fstest.MapFS{
+		"migrations/1_only_up.up.sql": {Data: []byte("SELECT 1;")},
+	})
+	require.Error(t, err)
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- create things; carefully
+CREATE TABLE a (id int);
+INSERT INTO a VALUES ('x;y'), ('it\'s; fine');
+
+# trailing comment;
+ALTER TABLE a ADD COLUMN b int`
+
+	require.Equal(t, []string{
+		"CREATE TABLE a (id int)",
+		`INSERT INTO a VALUES ('x;y'), ('it\'s; fine')`,
+		"ALTER TABLE a ADD COLUMN b int",
+	}, splitStatements(script))
+}
+
+func TestMigratorUp(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	m := &Migrator{
+		db: sqlx.NewDb(mockDB, "sqlmock"),
+		migrations: []Migration{
+			{Version: 1, Name: "first", Up: "CREATE TABLE a (id int);", Down: "DROP TABLE a;"},
+			{Version: 2, Name: "second", Up: "CREATE TABLE b (id int); CREATE TABLE c (id int);", Down: "DROP TABLE c; DROP TABLE b;"},
+		},
+	}
+
+	expectVersion := func(version int64, dirty bool) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM schema_migrations").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO schema_migrations (version, dirty) VALUES (?, ?)").WithArgs(version, dirty).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+
+	mock.ExpectQuery("SELECT GET_LOCK(?, ?)").WithArgs(migrationLock, 300).WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(1))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version, dirty FROM schema_migrations LIMIT 1").WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(1, false))
+	expectVersion(2, true)
+	mock.ExpectExec("CREATE TABLE b (id int)").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE c (id int)").WillReturnResult(sqlmock.NewResult(0, 0))
+	expectVersion(2, false)
+	mock.ExpectExec("SELECT RELEASE_LOCK(?)").WithArgs(migrationLock).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	n, err := m.Up(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigratorDirty(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	m := &Migrator{
+		db:         sqlx.NewDb(mockDB, "sqlmock"),
+		migrations: []Migration{{Version: 1, Name: "first", Up: "CREATE TABLE a (id int);", Down: "DROP TABLE a;"}},
+	}
+
+	mock.ExpectQuery("SELECT GET_LOCK(?, ?)").WithArgs(migrationLock, 300).WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(1))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version, dirty FROM schema_migrations LIMIT 1").WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(1, true))
+	mock.ExpectExec("SELECT RELEASE_LOCK(?)").WithArgs(migrationLock).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err = m.Down(context.Background(), 1)
+	require.ErrorIs(t, err, ErrDirty)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
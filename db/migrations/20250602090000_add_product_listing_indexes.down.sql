@@ -0,0 +1,5 @@
+DROP INDEX `products_created_at_id_idx` ON `products`;
+DROP INDEX `products_price_id_idx` ON `products`;
+DROP INDEX `products_rating_id_idx` ON `products`;
+DROP INDEX `products_name_id_idx` ON `products`;
+DROP INDEX `products_category_idx` ON `products`;
@@ -0,0 +1,5 @@
+CREATE INDEX `products_created_at_id_idx` ON `products` (`created_at`, `id`);
+CREATE INDEX `products_price_id_idx` ON `products` (`price`, `id`);
+CREATE INDEX `products_rating_id_idx` ON `products` (`rating`, `id`);
+CREATE INDEX `products_name_id_idx` ON `products` (`name`, `id`);
+CREATE INDEX `products_category_idx` ON `products` (`category`);
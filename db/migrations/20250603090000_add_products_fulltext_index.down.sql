@@ -0,0 +1,2 @@
+ALTER TABLE `products`
+    DROP INDEX `products_search_idx`;
@@ -0,0 +1,2 @@
+ALTER TABLE `products`
+    ADD FULLTEXT INDEX `products_search_idx` (`name`, `description`, `category`);
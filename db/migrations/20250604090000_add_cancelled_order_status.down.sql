@@ -0,0 +1,6 @@
+-- cancelled orders never shipped, so they go back to pending; the old enum
+-- has no status for an order that will not be fulfilled
+UPDATE `orders` SET `status`='pending' WHERE `status`='cancelled';
+
+ALTER TABLE `orders`
+    MODIFY COLUMN `status` ENUM('pending', 'shipped', 'delivered') NOT NULL DEFAULT 'pending';
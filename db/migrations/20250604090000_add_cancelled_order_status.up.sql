@@ -0,0 +1,2 @@
+ALTER TABLE `orders`
+    MODIFY COLUMN `status` ENUM('pending', 'shipped', 'delivered', 'cancelled') NOT NULL DEFAULT 'pending';
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS order_status_history;
+
+UPDATE `orders` SET `status`='cancelled' WHERE `status` IN ('paid', 'processing', 'refunded');
+
+ALTER TABLE `orders`
+    MODIFY COLUMN `status` ENUM('pending', 'shipped', 'delivered', 'cancelled') NOT NULL DEFAULT 'pending';
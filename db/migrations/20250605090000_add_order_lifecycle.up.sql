@@ -0,0 +1,16 @@
+ALTER TABLE `orders`
+    MODIFY COLUMN `status` ENUM('pending', 'paid', 'processing', 'shipped', 'delivered', 'cancelled', 'refunded') NOT NULL DEFAULT 'pending';
+
+CREATE TABLE `order_status_history` (
+  `id` int PRIMARY KEY AUTO_INCREMENT,
+  `order_id` int NOT NULL,
+  `from_status` varchar(32) NOT NULL,
+  `to_status` varchar(32) NOT NULL,
+  `reason` varchar(512) NOT NULL DEFAULT '',
+  `actor_id` int NOT NULL,
+  `actor_role` enum('customer', 'admin') NOT NULL,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE `order_status_history`
+    ADD CONSTRAINT `order_status_history_order_id_fk` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`);
@@ -0,0 +1 @@
+DROP INDEX `orders_user_id_created_at_id_idx` ON `orders`;
@@ -0,0 +1,5 @@
+DROP INDEX `notification_events_queue_due_idx` ON `notification_events_queue`;
+
+ALTER TABLE `notification_events_queue`
+    DROP COLUMN `locked_until`,
+    MODIFY COLUMN `attempts` int;
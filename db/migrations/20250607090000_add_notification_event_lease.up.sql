@@ -0,0 +1,7 @@
+UPDATE `notification_events_queue` SET `attempts`=0 WHERE `attempts` IS NULL;
+
+ALTER TABLE `notification_events_queue`
+    MODIFY COLUMN `attempts` int NOT NULL DEFAULT 0,
+    ADD COLUMN `locked_until` datetime;
+
+CREATE INDEX `notification_events_queue_due_idx` ON `notification_events_queue` (`attempts`, `locked_until`, `created_at`);
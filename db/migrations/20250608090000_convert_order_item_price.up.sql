@@ -0,0 +1 @@
+ALTER TABLE `order_items` MODIFY COLUMN `price` decimal(10,2) NOT NULL;
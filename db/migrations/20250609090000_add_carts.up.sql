@@ -0,0 +1,24 @@
+CREATE TABLE `carts` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `user_id` int NOT NULL,
+  `created_at` datetime DEFAULT (now()),
+  `updated_at` datetime,
+  UNIQUE(`user_id`)
+);
+
+CREATE TABLE `cart_items` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `cart_id` int NOT NULL,
+  `product_id` int NOT NULL,
+  `quantity` int NOT NULL,
+  `created_at` datetime DEFAULT (now()),
+  `updated_at` datetime,
+  UNIQUE(`cart_id`, `product_id`)
+);
+
+ALTER TABLE `carts`
+    ADD CONSTRAINT `carts_user_id_fk` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE;
+
+ALTER TABLE `cart_items`
+    ADD CONSTRAINT `cart_items_cart_id_fk` FOREIGN KEY (`cart_id`) REFERENCES `carts` (`id`) ON DELETE CASCADE,
+    ADD CONSTRAINT `cart_items_product_id_fk` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE;
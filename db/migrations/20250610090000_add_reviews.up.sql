@@ -0,0 +1,19 @@
+CREATE TABLE `reviews` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `product_id` int NOT NULL,
+  `user_id` int NOT NULL,
+  `rating` tinyint NOT NULL,
+  `title` varchar(255) NOT NULL,
+  `body` text NOT NULL,
+  `hidden` bool NOT NULL DEFAULT false,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime,
+  UNIQUE(`product_id`, `user_id`),
+  CHECK (`rating` BETWEEN 1 AND 5)
+);
+
+CREATE INDEX `reviews_product_listing_idx` ON `reviews` (`product_id`, `hidden`, `created_at`, `id`);
+
+ALTER TABLE `reviews`
+    ADD CONSTRAINT `reviews_product_id_fk` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE,
+    ADD CONSTRAINT `reviews_user_id_fk` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE;
@@ -0,0 +1,22 @@
+ALTER TABLE `products`
+    DROP INDEX `products_search_idx`;
+
+ALTER TABLE `products` ADD COLUMN `category` varchar(255) NOT NULL DEFAULT '' AFTER `image`;
+
+UPDATE `products`
+JOIN `categories` ON `categories`.`id` = `products`.`category_id`
+SET `products`.`category` = `categories`.`name`;
+
+ALTER TABLE `products` ALTER COLUMN `category` DROP DEFAULT;
+
+ALTER TABLE `products`
+    DROP FOREIGN KEY `products_category_id_fk`;
+
+ALTER TABLE `products` DROP COLUMN `category_id`;
+
+CREATE INDEX `products_category_idx` ON `products` (`category`);
+
+ALTER TABLE `products`
+    ADD FULLTEXT INDEX `products_search_idx` (`name`, `description`, `category`);
+
+DROP TABLE IF EXISTS categories;
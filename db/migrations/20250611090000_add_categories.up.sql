@@ -0,0 +1,44 @@
+CREATE TABLE `categories` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `parent_id` int,
+  `name` varchar(255) NOT NULL,
+  `slug` varchar(255) NOT NULL UNIQUE,
+  `sort_order` int NOT NULL DEFAULT 0,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime
+);
+
+ALTER TABLE `categories`
+    ADD CONSTRAINT `categories_parent_id_fk` FOREIGN KEY (`parent_id`) REFERENCES `categories` (`id`);
+
+-- one top level category per distinct slug, so "Shoes", "shoes" and
+-- "Shoes " end up in the same category
+INSERT INTO `categories` (`name`, `slug`)
+SELECT MIN(`name`), `slug`
+FROM (
+    SELECT TRIM(`category`) AS `name`,
+           TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(TRIM(`category`)), '[^a-z0-9]+', '-')) AS `slug`
+    FROM `products`
+) AS `distinct_categories`
+WHERE `slug` <> ''
+GROUP BY `slug`
+ORDER BY `slug`;
+
+ALTER TABLE `products` ADD COLUMN `category_id` int AFTER `image`;
+
+UPDATE `products`
+JOIN `categories` ON `categories`.`slug` = TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(TRIM(`products`.`category`)), '[^a-z0-9]+', '-'))
+SET `products`.`category_id` = `categories`.`id`;
+
+ALTER TABLE `products`
+    ADD CONSTRAINT `products_category_id_fk` FOREIGN KEY (`category_id`) REFERENCES `categories` (`id`);
+
+ALTER TABLE `products`
+    DROP INDEX `products_search_idx`;
+
+DROP INDEX `products_category_idx` ON `products`;
+
+ALTER TABLE `products` DROP COLUMN `category`;
+
+ALTER TABLE `products`
+    ADD FULLTEXT INDEX `products_search_idx` (`name`, `description`);
@@ -0,0 +1,20 @@
+-- collapse variant lines of a cart into one line per product
+DELETE `a` FROM `cart_items` `a`
+JOIN `cart_items` `b` ON `b`.`cart_id` = `a`.`cart_id` AND `b`.`product_id` = `a`.`product_id` AND `b`.`id` < `a`.`id`;
+
+CREATE UNIQUE INDEX `cart_id` ON `cart_items` (`cart_id`, `product_id`);
+
+DROP INDEX `cart_items_cart_id_product_id_variant_id_idx` ON `cart_items`;
+
+ALTER TABLE `cart_items` DROP COLUMN `variant_id`;
+
+ALTER TABLE `order_items`
+    DROP FOREIGN KEY `order_items_variant_id_fk`;
+
+ALTER TABLE `order_items`
+    DROP COLUMN `variant_id`,
+    DROP COLUMN `sku`;
+
+DROP TABLE IF EXISTS product_variants;
+
+ALTER TABLE `products` DROP COLUMN `options`;
@@ -0,0 +1,32 @@
+ALTER TABLE `products` ADD COLUMN `options` json AFTER `description`;
+
+CREATE TABLE `product_variants` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `product_id` int NOT NULL,
+  `sku` varchar(64) NOT NULL UNIQUE,
+  `options` json NOT NULL,
+  `price` decimal(10,2),
+  `image` varchar(255) NOT NULL DEFAULT '',
+  `count_in_stock` int NOT NULL DEFAULT 0,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime
+);
+
+ALTER TABLE `product_variants`
+    ADD CONSTRAINT `product_variants_product_id_fk` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE;
+
+ALTER TABLE `order_items`
+    ADD COLUMN `variant_id` int AFTER `product_id`,
+    ADD COLUMN `sku` varchar(64) NOT NULL DEFAULT '' AFTER `variant_id`;
+
+ALTER TABLE `order_items`
+    ADD CONSTRAINT `order_items_variant_id_fk` FOREIGN KEY (`variant_id`) REFERENCES `product_variants` (`id`);
+
+-- 0 for products without variants. It is part of the unique key, so it is
+-- not nullable and not a foreign key; deleting a variant removes its cart
+-- items explicitly.
+ALTER TABLE `cart_items` ADD COLUMN `variant_id` int NOT NULL DEFAULT 0 AFTER `product_id`;
+
+CREATE UNIQUE INDEX `cart_items_cart_id_product_id_variant_id_idx` ON `cart_items` (`cart_id`, `product_id`, `variant_id`);
+
+DROP INDEX `cart_id` ON `cart_items`;
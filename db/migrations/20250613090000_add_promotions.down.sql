@@ -0,0 +1,7 @@
+ALTER TABLE `orders`
+    DROP COLUMN `discounts`,
+    DROP COLUMN `coupon_code`,
+    DROP COLUMN `discount_price`;
+
+DROP TABLE IF EXISTS promotion_redemptions;
+DROP TABLE IF EXISTS promotions;
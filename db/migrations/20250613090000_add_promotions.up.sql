@@ -0,0 +1,48 @@
+-- Promotions without a code apply automatically to every qualifying order;
+-- those with a code only when the customer enters it.
+CREATE TABLE `promotions` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) NOT NULL,
+  `code` varchar(64) UNIQUE,
+  `type` enum('percentage', 'fixed_amount', 'free_shipping', 'buy_x_get_y') NOT NULL,
+  `discount_rate` int NOT NULL DEFAULT 0,
+  `discount_amount` decimal(10,2) NOT NULL DEFAULT 0,
+  `buy_quantity` int NOT NULL DEFAULT 0,
+  `get_quantity` int NOT NULL DEFAULT 0,
+  `product_id` int,
+  `category_id` int,
+  `min_subtotal` decimal(10,2) NOT NULL DEFAULT 0,
+  `starts_at` datetime,
+  `ends_at` datetime,
+  `usage_limit` int NOT NULL DEFAULT 0,
+  `per_customer_limit` int NOT NULL DEFAULT 0,
+  `active` bool NOT NULL DEFAULT true,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime
+);
+
+ALTER TABLE `promotions`
+    ADD CONSTRAINT `promotions_product_id_fk` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`),
+    ADD CONSTRAINT `promotions_category_id_fk` FOREIGN KEY (`category_id`) REFERENCES `categories` (`id`);
+
+CREATE TABLE `promotion_redemptions` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `promotion_id` int NOT NULL,
+  `order_id` int NOT NULL,
+  `user_id` int NOT NULL,
+  `amount` decimal(10,2) NOT NULL,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX `promotion_redemptions_promotion_id_user_id_idx` ON `promotion_redemptions` (`promotion_id`, `user_id`);
+
+ALTER TABLE `promotion_redemptions`
+    ADD CONSTRAINT `promotion_redemptions_promotion_id_fk` FOREIGN KEY (`promotion_id`) REFERENCES `promotions` (`id`),
+    ADD CONSTRAINT `promotion_redemptions_order_id_fk` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`),
+    ADD CONSTRAINT `promotion_redemptions_user_id_fk` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE;
+
+-- discounts holds the breakdown shown on the order; discount_price is its sum.
+ALTER TABLE `orders`
+    ADD COLUMN `discount_price` decimal(10,2) NOT NULL DEFAULT 0 AFTER `shipping_price`,
+    ADD COLUMN `coupon_code` varchar(64) NOT NULL DEFAULT '' AFTER `discount_price`,
+    ADD COLUMN `discounts` json AFTER `coupon_code`;
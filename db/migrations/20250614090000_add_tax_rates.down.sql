@@ -0,0 +1,13 @@
+ALTER TABLE `order_items`
+    DROP COLUMN `tax_inclusive`,
+    DROP COLUMN `tax_price`,
+    DROP COLUMN `tax_jurisdiction`,
+    DROP COLUMN `tax_rate`;
+
+ALTER TABLE `orders` DROP COLUMN `shipping_address`;
+
+ALTER TABLE `users` DROP COLUMN `tax_exempt`;
+
+ALTER TABLE `products` DROP COLUMN `tax_class`;
+
+DROP TABLE IF EXISTS tax_rates;
@@ -0,0 +1,27 @@
+-- A rate applies to products of its tax class shipped to its country and
+-- region. An empty region covers the whole country, and an empty country
+-- every destination without a more specific rate.
+CREATE TABLE `tax_rates` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `country` char(2) NOT NULL DEFAULT '',
+  `region` varchar(64) NOT NULL DEFAULT '',
+  `tax_class` varchar(32) NOT NULL DEFAULT 'standard',
+  `name` varchar(255) NOT NULL,
+  `rate` int NOT NULL,
+  `inclusive` bool NOT NULL DEFAULT false,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime,
+  UNIQUE KEY `tax_rates_country_region_tax_class_idx` (`country`, `region`, `tax_class`)
+);
+
+ALTER TABLE `products` ADD COLUMN `tax_class` varchar(32) NOT NULL DEFAULT 'standard' AFTER `options`;
+
+ALTER TABLE `users` ADD COLUMN `tax_exempt` bool NOT NULL DEFAULT false AFTER `is_admin`;
+
+ALTER TABLE `orders` ADD COLUMN `shipping_address` json AFTER `payment_method`;
+
+ALTER TABLE `order_items`
+    ADD COLUMN `tax_rate` int NOT NULL DEFAULT 0 AFTER `sku`,
+    ADD COLUMN `tax_jurisdiction` varchar(80) NOT NULL DEFAULT '' AFTER `tax_rate`,
+    ADD COLUMN `tax_price` decimal(10,2) NOT NULL DEFAULT 0 AFTER `tax_jurisdiction`,
+    ADD COLUMN `tax_inclusive` bool NOT NULL DEFAULT false AFTER `tax_price`;
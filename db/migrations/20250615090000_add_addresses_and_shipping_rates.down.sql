@@ -0,0 +1,9 @@
+ALTER TABLE `orders`
+    DROP COLUMN `shipping_method`,
+    DROP COLUMN `billing_address`;
+
+ALTER TABLE `products` DROP COLUMN `weight`;
+
+DROP TABLE IF EXISTS shipping_methods;
+DROP TABLE IF EXISTS shipping_zones;
+DROP TABLE IF EXISTS addresses;
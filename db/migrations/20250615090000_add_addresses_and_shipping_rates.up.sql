@@ -0,0 +1,53 @@
+CREATE TABLE `addresses` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `user_id` int NOT NULL,
+  `label` varchar(64) NOT NULL DEFAULT '',
+  `name` varchar(255) NOT NULL,
+  `line1` varchar(255) NOT NULL,
+  `line2` varchar(255) NOT NULL DEFAULT '',
+  `city` varchar(255) NOT NULL,
+  `region` varchar(64) NOT NULL DEFAULT '',
+  `postal_code` varchar(32) NOT NULL DEFAULT '',
+  `country` char(2) NOT NULL,
+  `is_default` bool NOT NULL DEFAULT false,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime,
+  KEY `addresses_user_id_idx` (`user_id`)
+);
+
+ALTER TABLE `addresses`
+    ADD CONSTRAINT `addresses_user_id_fk` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE;
+
+-- countries and postal_prefixes are JSON arrays; a zone without countries
+-- covers every destination no other zone does
+CREATE TABLE `shipping_zones` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) NOT NULL,
+  `countries` json NOT NULL,
+  `postal_prefixes` json NOT NULL,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime
+);
+
+-- tiers is a JSON array of {"min", "price"} ordered by min, in minor units
+-- of the subtotal or in grams depending on basis
+CREATE TABLE `shipping_methods` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `zone_id` int NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `basis` enum('subtotal', 'weight') NOT NULL DEFAULT 'subtotal',
+  `tiers` json NOT NULL,
+  `free_above` decimal(10,2),
+  `active` bool NOT NULL DEFAULT true,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime
+);
+
+ALTER TABLE `shipping_methods`
+    ADD CONSTRAINT `shipping_methods_zone_id_fk` FOREIGN KEY (`zone_id`) REFERENCES `shipping_zones` (`id`) ON DELETE CASCADE;
+
+ALTER TABLE `products` ADD COLUMN `weight` int NOT NULL DEFAULT 0 AFTER `tax_class`;
+
+ALTER TABLE `orders`
+    ADD COLUMN `billing_address` json AFTER `shipping_address`,
+    ADD COLUMN `shipping_method` varchar(255) NOT NULL DEFAULT '' AFTER `billing_address`;
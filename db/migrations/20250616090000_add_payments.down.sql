@@ -0,0 +1,6 @@
+DELETE FROM `order_status_history` WHERE `actor_role`='system';
+
+ALTER TABLE `order_status_history`
+    MODIFY COLUMN `actor_role` enum('customer', 'admin') NOT NULL;
+
+DROP TABLE IF EXISTS payments;
@@ -0,0 +1,21 @@
+-- one row per attempt to pay an order; reference is the provider's id of the
+-- payment, empty until the provider has answered
+CREATE TABLE `payments` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `order_id` int NOT NULL,
+  `provider` varchar(32) NOT NULL,
+  `reference` varchar(255) NOT NULL DEFAULT '',
+  `status` enum('pending', 'authorized', 'captured', 'declined', 'voided', 'refunded', 'failed') NOT NULL DEFAULT 'pending',
+  `amount` decimal(10,2) NOT NULL,
+  `message` varchar(512) NOT NULL DEFAULT '',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime,
+  KEY `payments_order_id_idx` (`order_id`),
+  KEY `payments_reference_idx` (`provider`, `reference`)
+);
+
+ALTER TABLE `payments`
+    ADD CONSTRAINT `payments_order_id_fk` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`);
+
+ALTER TABLE `order_status_history`
+    MODIFY COLUMN `actor_role` enum('customer', 'admin', 'system') NOT NULL;
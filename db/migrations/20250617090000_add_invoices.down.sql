@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS credit_notes;
+DROP TABLE IF EXISTS invoices;
+DROP TABLE IF EXISTS document_sequences;
@@ -0,0 +1,50 @@
+-- the last number handed out per kind of document; numbers are taken in the
+-- same transaction as the document is inserted, so they have no gaps
+CREATE TABLE `document_sequences` (
+  `name` varchar(32) PRIMARY KEY NOT NULL,
+  `last_number` int NOT NULL DEFAULT 0
+);
+
+INSERT INTO `document_sequences` (`name`) VALUES ('invoice'), ('credit_note');
+
+-- invoices snapshot everything they show, and outlive the orders they are
+-- for, which is why order_id is not a foreign key
+CREATE TABLE `invoices` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `number` varchar(32) NOT NULL,
+  `order_id` int NOT NULL,
+  `user_id` int NOT NULL,
+  `seller` json NOT NULL,
+  `buyer_name` varchar(255) NOT NULL,
+  `buyer_email` varchar(255) NOT NULL,
+  `billing_address` json,
+  `shipping_address` json,
+  `items` json NOT NULL,
+  `items_price` decimal(10,2) NOT NULL,
+  `discount_price` decimal(10,2) NOT NULL,
+  `shipping_price` decimal(10,2) NOT NULL,
+  `tax_price` decimal(10,2) NOT NULL,
+  `total_price` decimal(10,2) NOT NULL,
+  `issued_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE KEY `invoices_number_key` (`number`),
+  UNIQUE KEY `invoices_order_id_key` (`order_id`)
+);
+
+CREATE TABLE `credit_notes` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `number` varchar(32) NOT NULL,
+  `invoice_id` int NOT NULL,
+  `reason` varchar(512) NOT NULL DEFAULT '',
+  `items` json NOT NULL,
+  `items_price` decimal(10,2) NOT NULL,
+  `discount_price` decimal(10,2) NOT NULL,
+  `shipping_price` decimal(10,2) NOT NULL,
+  `tax_price` decimal(10,2) NOT NULL,
+  `total_price` decimal(10,2) NOT NULL,
+  `issued_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE KEY `credit_notes_number_key` (`number`),
+  KEY `credit_notes_invoice_id_idx` (`invoice_id`)
+);
+
+ALTER TABLE `credit_notes`
+    ADD CONSTRAINT `credit_notes_invoice_id_fk` FOREIGN KEY (`invoice_id`) REFERENCES `invoices` (`id`);
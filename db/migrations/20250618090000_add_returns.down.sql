@@ -0,0 +1,8 @@
+ALTER TABLE `notification_events_queue`
+    DROP COLUMN `return_status`,
+    DROP COLUMN `return_id`;
+
+ALTER TABLE `payments`
+    DROP COLUMN `refunded_amount`;
+
+DROP TABLE IF EXISTS returns;
@@ -0,0 +1,33 @@
+-- a customer's request to send back quantity units of an order item;
+-- refund_amount is the item's share of what was paid for them
+CREATE TABLE `returns` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `order_id` int NOT NULL,
+  `order_item_id` int NOT NULL,
+  `user_id` int NOT NULL,
+  `quantity` int NOT NULL,
+  `reason` varchar(512) NOT NULL,
+  `photos_url` varchar(1024) NOT NULL DEFAULT '',
+  `status` enum('requested', 'approved', 'rejected', 'received', 'refunded') NOT NULL DEFAULT 'requested',
+  `note` varchar(512) NOT NULL DEFAULT '',
+  `restocked` boolean NOT NULL DEFAULT false,
+  `refund_amount` decimal(10,2) NOT NULL,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime,
+  KEY `returns_order_id_idx` (`order_id`),
+  KEY `returns_order_item_id_idx` (`order_item_id`),
+  KEY `returns_status_idx` (`status`)
+);
+
+ALTER TABLE `returns`
+    ADD CONSTRAINT `returns_order_id_fk` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`),
+    ADD CONSTRAINT `returns_order_item_id_fk` FOREIGN KEY (`order_item_id`) REFERENCES `order_items` (`id`);
+
+-- what was refunded of a captured payment so far, as refunds may be partial
+ALTER TABLE `payments`
+    ADD COLUMN `refunded_amount` decimal(10,2) NOT NULL DEFAULT 0;
+
+-- return events are about an order's return rather than the order itself
+ALTER TABLE `notification_events_queue`
+    ADD COLUMN `return_id` int,
+    ADD COLUMN `return_status` varchar(32) NOT NULL DEFAULT '';
@@ -0,0 +1,28 @@
+-- share_token is set while a wishlist is shared and cleared when it stops
+-- being shared, which revokes the links handed out
+CREATE TABLE `wishlists` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `user_id` int NOT NULL,
+  `name` varchar(128) NOT NULL,
+  `share_token` char(32),
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime,
+  UNIQUE(`user_id`, `name`),
+  UNIQUE(`share_token`)
+);
+
+CREATE TABLE `wishlist_items` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `wishlist_id` int NOT NULL,
+  `product_id` int NOT NULL,
+  `variant_id` int NOT NULL DEFAULT 0,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE(`wishlist_id`, `product_id`, `variant_id`)
+);
+
+ALTER TABLE `wishlists`
+    ADD CONSTRAINT `wishlists_user_id_fk` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE;
+
+ALTER TABLE `wishlist_items`
+    ADD CONSTRAINT `wishlist_items_wishlist_id_fk` FOREIGN KEY (`wishlist_id`) REFERENCES `wishlists` (`id`) ON DELETE CASCADE,
+    ADD CONSTRAINT `wishlist_items_product_id_fk` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE;
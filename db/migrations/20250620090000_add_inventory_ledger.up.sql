@@ -0,0 +1,63 @@
+CREATE TABLE `warehouses` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `code` varchar(32) NOT NULL UNIQUE,
+  `name` varchar(255) NOT NULL,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime
+);
+
+-- the main warehouse takes the opening stock of new products and variants
+-- and returned items that are restocked
+INSERT INTO `warehouses` (`id`, `code`, `name`) VALUES (1, 'MAIN', 'Main warehouse');
+
+-- variant_id is 0 for products without variants, as in cart_items
+CREATE TABLE `stock_levels` (
+  `warehouse_id` int NOT NULL,
+  `product_id` int NOT NULL,
+  `variant_id` int NOT NULL DEFAULT 0,
+  `quantity` int NOT NULL DEFAULT 0,
+  PRIMARY KEY (`warehouse_id`, `product_id`, `variant_id`),
+  KEY `stock_levels_product_id_variant_id_idx` (`product_id`, `variant_id`)
+);
+
+-- append-only; quantity is negative for stock leaving the warehouse and
+-- other_warehouse_id names the other side of a transfer
+CREATE TABLE `stock_movements` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `warehouse_id` int NOT NULL,
+  `product_id` int NOT NULL,
+  `variant_id` int NOT NULL DEFAULT 0,
+  `kind` enum('receipt', 'sale', 'return', 'adjustment', 'transfer') NOT NULL,
+  `quantity` int NOT NULL,
+  `reason` varchar(255) NOT NULL DEFAULT '',
+  `actor_id` int,
+  `order_id` int,
+  `other_warehouse_id` int,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  KEY `stock_movements_product_id_id_idx` (`product_id`, `id`),
+  KEY `stock_movements_order_id_idx` (`order_id`)
+);
+
+ALTER TABLE `stock_levels`
+    ADD CONSTRAINT `stock_levels_warehouse_id_fk` FOREIGN KEY (`warehouse_id`) REFERENCES `warehouses` (`id`),
+    ADD CONSTRAINT `stock_levels_product_id_fk` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE;
+
+ALTER TABLE `stock_movements`
+    ADD CONSTRAINT `stock_movements_warehouse_id_fk` FOREIGN KEY (`warehouse_id`) REFERENCES `warehouses` (`id`),
+    ADD CONSTRAINT `stock_movements_product_id_fk` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE,
+    ADD CONSTRAINT `stock_movements_actor_id_fk` FOREIGN KEY (`actor_id`) REFERENCES `users` (`id`) ON DELETE SET NULL,
+    ADD CONSTRAINT `stock_movements_order_id_fk` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE SET NULL,
+    ADD CONSTRAINT `stock_movements_other_warehouse_id_fk` FOREIGN KEY (`other_warehouse_id`) REFERENCES `warehouses` (`id`);
+
+-- the stock on hand opens the ledger in the main warehouse: products without
+-- variants at their own count, products with variants at their variants'
+INSERT INTO `stock_levels` (`warehouse_id`, `product_id`, `variant_id`, `quantity`)
+SELECT 1, `id`, 0, `count_in_stock` FROM `products` p
+WHERE `count_in_stock` > 0 AND NOT EXISTS (SELECT 1 FROM `product_variants` v WHERE v.`product_id` = p.`id`);
+
+INSERT INTO `stock_levels` (`warehouse_id`, `product_id`, `variant_id`, `quantity`)
+SELECT 1, `product_id`, `id`, `count_in_stock` FROM `product_variants`
+WHERE `count_in_stock` > 0;
+
+INSERT INTO `stock_movements` (`warehouse_id`, `product_id`, `variant_id`, `kind`, `quantity`, `reason`)
+SELECT `warehouse_id`, `product_id`, `variant_id`, 'adjustment', `quantity`, 'opening balance' FROM `stock_levels`;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS store_credit_transactions;
+DROP TABLE IF EXISTS store_credits;
+DROP TABLE IF EXISTS gift_card_transactions;
+DROP TABLE IF EXISTS gift_cards;
+
+ALTER TABLE `products`
+    DROP COLUMN `gift_card`;
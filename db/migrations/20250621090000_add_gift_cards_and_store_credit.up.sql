@@ -0,0 +1,74 @@
+-- gift cards are sold in orders as products marked gift_card, one card per
+-- unit, or issued by admins
+ALTER TABLE `products`
+    ADD COLUMN `gift_card` boolean NOT NULL DEFAULT false AFTER `weight`;
+
+-- purchaser_id and order_id are set for cards bought in an order, issued_by
+-- for cards an admin issued; balance is what is left to spend
+CREATE TABLE `gift_cards` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `code` varchar(32) NOT NULL UNIQUE,
+  `initial_amount` decimal(10,2) NOT NULL,
+  `balance` decimal(10,2) NOT NULL,
+  `expires_at` datetime,
+  `purchaser_id` int,
+  `order_id` int,
+  `issued_by` int,
+  `note` varchar(255) NOT NULL DEFAULT '',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime,
+  KEY `gift_cards_purchaser_id_idx` (`purchaser_id`),
+  KEY `gift_cards_order_id_idx` (`order_id`)
+);
+
+-- append-only; amount is negative for money taken off the card
+CREATE TABLE `gift_card_transactions` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `gift_card_id` int NOT NULL,
+  `kind` enum('issue', 'redeem', 'refund', 'adjustment') NOT NULL,
+  `amount` decimal(10,2) NOT NULL,
+  `order_id` int,
+  `actor_id` int,
+  `reason` varchar(255) NOT NULL DEFAULT '',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  KEY `gift_card_transactions_gift_card_id_idx` (`gift_card_id`)
+);
+
+-- one wallet per user, created with its first transaction
+CREATE TABLE `store_credits` (
+  `user_id` int PRIMARY KEY NOT NULL,
+  `balance` decimal(10,2) NOT NULL DEFAULT 0,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime
+);
+
+-- append-only, like gift_card_transactions
+CREATE TABLE `store_credit_transactions` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `user_id` int NOT NULL,
+  `kind` enum('redeem', 'refund', 'adjustment') NOT NULL,
+  `amount` decimal(10,2) NOT NULL,
+  `order_id` int,
+  `actor_id` int,
+  `reason` varchar(255) NOT NULL DEFAULT '',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  KEY `store_credit_transactions_user_id_idx` (`user_id`)
+);
+
+ALTER TABLE `gift_cards`
+    ADD CONSTRAINT `gift_cards_purchaser_id_fk` FOREIGN KEY (`purchaser_id`) REFERENCES `users` (`id`) ON DELETE SET NULL,
+    ADD CONSTRAINT `gift_cards_order_id_fk` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE SET NULL,
+    ADD CONSTRAINT `gift_cards_issued_by_fk` FOREIGN KEY (`issued_by`) REFERENCES `users` (`id`) ON DELETE SET NULL;
+
+ALTER TABLE `gift_card_transactions`
+    ADD CONSTRAINT `gift_card_transactions_gift_card_id_fk` FOREIGN KEY (`gift_card_id`) REFERENCES `gift_cards` (`id`) ON DELETE CASCADE,
+    ADD CONSTRAINT `gift_card_transactions_order_id_fk` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE SET NULL,
+    ADD CONSTRAINT `gift_card_transactions_actor_id_fk` FOREIGN KEY (`actor_id`) REFERENCES `users` (`id`) ON DELETE SET NULL;
+
+ALTER TABLE `store_credits`
+    ADD CONSTRAINT `store_credits_user_id_fk` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE;
+
+ALTER TABLE `store_credit_transactions`
+    ADD CONSTRAINT `store_credit_transactions_user_id_fk` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE,
+    ADD CONSTRAINT `store_credit_transactions_order_id_fk` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE SET NULL,
+    ADD CONSTRAINT `store_credit_transactions_actor_id_fk` FOREIGN KEY (`actor_id`) REFERENCES `users` (`id`) ON DELETE SET NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS loyalty_transactions;
+DROP TABLE IF EXISTS loyalty_accounts;
@@ -0,0 +1,32 @@
+-- one points balance per user, created with its first transaction; it may
+-- go below zero when points spent already are reversed
+CREATE TABLE `loyalty_accounts` (
+  `user_id` int PRIMARY KEY NOT NULL,
+  `balance` int NOT NULL DEFAULT 0,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime
+);
+
+-- append-only; points is negative for points taken off. Points earned or
+-- given back expire at expires_at, oldest first. spend is what the customer
+-- spent to earn them, and counts towards their tier
+CREATE TABLE `loyalty_transactions` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `user_id` int NOT NULL,
+  `kind` enum('earn', 'redeem', 'refund', 'reverse', 'expire') NOT NULL,
+  `points` int NOT NULL,
+  `spend` decimal(10,2) NOT NULL DEFAULT 0,
+  `order_id` int,
+  `reason` varchar(255) NOT NULL DEFAULT '',
+  `expires_at` datetime,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  KEY `loyalty_transactions_user_id_idx` (`user_id`),
+  KEY `loyalty_transactions_order_id_idx` (`order_id`)
+);
+
+ALTER TABLE `loyalty_accounts`
+    ADD CONSTRAINT `loyalty_accounts_user_id_fk` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE;
+
+ALTER TABLE `loyalty_transactions`
+    ADD CONSTRAINT `loyalty_transactions_user_id_fk` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE,
+    ADD CONSTRAINT `loyalty_transactions_order_id_fk` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE SET NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE `order_items` DROP FOREIGN KEY `order_items_scheduled_price_id_fk`;
+
+ALTER TABLE `order_items` DROP COLUMN `scheduled_price_id`;
+
+DROP TABLE IF EXISTS product_prices;
@@ -0,0 +1,27 @@
+-- prices a product, or one of its variants when variant_id is set, sells at
+-- from starts_at until ends_at instead of its own. A flash sale sells no
+-- more than quantity_cap units at its price; quantity_sold counts the units
+-- ordered at it and goes back down when such orders are cancelled
+CREATE TABLE `product_prices` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `product_id` int NOT NULL,
+  `variant_id` int,
+  `price` decimal(10,2) NOT NULL,
+  `starts_at` datetime NOT NULL,
+  `ends_at` datetime NOT NULL,
+  `quantity_cap` int,
+  `quantity_sold` int NOT NULL DEFAULT 0,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime,
+  KEY `product_prices_product_id_ends_at_idx` (`product_id`, `ends_at`)
+);
+
+ALTER TABLE `product_prices`
+    ADD CONSTRAINT `product_prices_product_id_fk` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE,
+    ADD CONSTRAINT `product_prices_variant_id_fk` FOREIGN KEY (`variant_id`) REFERENCES `product_variants` (`id`) ON DELETE CASCADE;
+
+-- the scheduled price an order line was sold at, if any
+ALTER TABLE `order_items` ADD COLUMN `scheduled_price_id` int AFTER `sku`;
+
+ALTER TABLE `order_items`
+    ADD CONSTRAINT `order_items_scheduled_price_id_fk` FOREIGN KEY (`scheduled_price_id`) REFERENCES `product_prices` (`id`) ON DELETE SET NULL;
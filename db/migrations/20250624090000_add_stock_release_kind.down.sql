@@ -0,0 +1,4 @@
+UPDATE `stock_movements` SET `kind`='sale' WHERE `kind`='release';
+
+ALTER TABLE `stock_movements`
+    MODIFY COLUMN `kind` enum('receipt', 'sale', 'return', 'adjustment', 'transfer') NOT NULL;
@@ -0,0 +1,6 @@
+-- stock put back by cancelled, refunded and deleted orders is recorded as a
+-- release rather than as a sale with a positive quantity
+ALTER TABLE `stock_movements`
+    MODIFY COLUMN `kind` enum('receipt', 'sale', 'release', 'return', 'adjustment', 'transfer') NOT NULL;
+
+UPDATE `stock_movements` SET `kind`='release' WHERE `kind`='sale' AND `quantity`>0;
@@ -0,0 +1,14 @@
+-- one row per refund of a captured payment; refund_key names what it
+-- refunds, e.g. "order 5" or "return 3", and a payment is refunded for a
+-- key only once
+CREATE TABLE `payment_refunds` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `payment_id` int NOT NULL,
+  `refund_key` varchar(64) NOT NULL,
+  `amount` decimal(10,2) NOT NULL,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE KEY `payment_refunds_payment_id_key_idx` (`payment_id`, `refund_key`)
+);
+
+ALTER TABLE `payment_refunds`
+    ADD CONSTRAINT `payment_refunds_payment_id_fk` FOREIGN KEY (`payment_id`) REFERENCES `payments` (`id`) ON DELETE CASCADE;
@@ -0,0 +1,11 @@
+-- the part of the order's item discounts taken off a line, so returns give
+-- back what was paid for the units. Older orders spread their discount over
+-- lines by their totals, as returns did before; shipping waivers cannot be
+-- told apart from it there
+ALTER TABLE `order_items` ADD COLUMN `discount_price` decimal(10,2) NOT NULL DEFAULT 0 AFTER `scheduled_price_id`;
+
+UPDATE `order_items` `oi`
+    JOIN `orders` `o` ON `o`.`id`=`oi`.`order_id`
+    JOIN (SELECT `order_id`, SUM(`price`*`quantity`) AS `total` FROM `order_items` GROUP BY `order_id`) `t` ON `t`.`order_id`=`oi`.`order_id`
+SET `oi`.`discount_price`=ROUND(`o`.`discount_price`*`oi`.`price`*`oi`.`quantity`/`t`.`total`, 2)
+WHERE `o`.`discount_price`>0 AND `t`.`total`>0;
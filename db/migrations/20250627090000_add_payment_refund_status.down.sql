@@ -0,0 +1 @@
+ALTER TABLE `payment_refunds` DROP COLUMN `status`;
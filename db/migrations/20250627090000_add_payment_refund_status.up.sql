@@ -0,0 +1,5 @@
+-- refunds the payment provider carries out are recorded pending before it is
+-- asked to and applied once it did, so a refund it never carried out is
+-- retried rather than lost; refunds recorded so far were all carried out
+ALTER TABLE `payment_refunds`
+    ADD COLUMN `status` enum('pending', 'applied') NOT NULL DEFAULT 'applied' AFTER `amount`;
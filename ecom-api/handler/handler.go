@@ -1,31 +1,43 @@
 package handler
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
+	"github.com/OrkhanMehbaliyev/ecom-golang/invoice"
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"github.com/OrkhanMehbaliyev/ecom-golang/payments"
 	"github.com/OrkhanMehbaliyev/ecom-golang/token"
 	"github.com/OrkhanMehbaliyev/ecom-golang/util"
 	"github.com/go-chi/chi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type handler struct {
-	ctx        context.Context
-	client     pb.EcomClient
-	TokenMaker *token.JWTMaker
+	ctx           context.Context
+	client        pb.EcomClient
+	TokenMaker    *token.JWTMaker
+	webhookSecret string
 }
 
-func NewHandler(client pb.EcomClient, secretKey string) *handler {
+// NewHandler returns the API handler. webhookSecret is shared with the
+// payment provider to sign its webhooks.
+func NewHandler(client pb.EcomClient, secretKey, webhookSecret string) *handler {
 	return &handler{
-		ctx:        context.Background(),
-		client:     client,
-		TokenMaker: token.NewJWTMaker(secretKey),
+		ctx:           context.Background(),
+		client:        client,
+		TokenMaker:    token.NewJWTMaker(secretKey),
+		webhookSecret: webhookSecret,
 	}
 }
 
@@ -38,7 +50,7 @@ func (h *handler) createProduct(w http.ResponseWriter, r *http.Request) {
 
 	product, err := h.client.CreateProduct(h.ctx, toPBProductReq(p))
 	if err != nil {
-		http.Error(w, "error creating product", http.StatusInternalServerError)
+		rpcError(w, err, "error creating product")
 		return
 	}
 
@@ -70,15 +82,2157 @@ func (h *handler) getProduct(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *handler) listProducts(w http.ResponseWriter, r *http.Request) {
-	lpr, err := h.client.ListProducts(h.ctx, &pb.ProductReq{})
+	h.writeProducts(w, r, r.URL.Query().Get("category"))
+}
+
+// listCategoryProducts lists the products of a category and of all its
+// subcategories.
+func (h *handler) listCategoryProducts(w http.ResponseWriter, r *http.Request) {
+	h.writeProducts(w, r, chi.URLParam(r, "slug"))
+}
+
+func (h *handler) writeProducts(w http.ResponseWriter, r *http.Request, categorySlug string) {
+	req, err := parseListProductsReq(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.CategorySlug = categorySlug
+
+	lpr, err := h.client.ListProducts(h.ctx, req)
+	if err != nil {
+		rpcError(w, err, "error listing products")
+		return
+	}
+
+	res := ListProductRes{
+		Products:   []ProductRes{},
+		NextCursor: lpr.GetNextCursor(),
+	}
+	for _, p := range lpr.GetProducts() {
+		res.Products = append(res.Products, toProductRes(p))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(res)
+}
+
+func parseListProductsReq(r *http.Request) (*pb.ListProductsReq, error) {
+	q := r.URL.Query()
+	req := &pb.ListProductsReq{
+		Cursor: q.Get("cursor"),
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid limit %q", v)
+		}
+		req.Limit = int32(limit)
+	}
+
+	switch q.Get("sort_by") {
+	case "", "created_at":
+		req.SortBy = pb.ProductSortBy_CREATED_AT
+	case "price":
+		req.SortBy = pb.ProductSortBy_PRICE
+	case "rating":
+		req.SortBy = pb.ProductSortBy_RATING
+	case "name":
+		req.SortBy = pb.ProductSortBy_NAME
+	default:
+		return nil, fmt.Errorf("invalid sort_by %q", q.Get("sort_by"))
+	}
+
+	switch q.Get("order") {
+	case "", "asc":
+		req.SortOrder = pb.SortOrder_ASC
+	case "desc":
+		req.SortOrder = pb.SortOrder_DESC
+	default:
+		return nil, fmt.Errorf("invalid order %q", q.Get("order"))
+	}
+
+	var err error
+	if req.MinPrice, err = parsePriceParam(q.Get("min_price")); err != nil {
+		return nil, fmt.Errorf("invalid min_price: %w", err)
+	}
+	if req.MaxPrice, err = parsePriceParam(q.Get("max_price")); err != nil {
+		return nil, fmt.Errorf("invalid max_price: %w", err)
+	}
+
+	if v := q.Get("min_rating"); v != "" {
+		rating, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min_rating %q", v)
+		}
+		req.MinRating = &rating
+	}
+
+	if v := q.Get("in_stock"); v != "" {
+		inStock, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid in_stock %q", v)
+		}
+		req.InStock = inStock
+	}
+
+	return req, nil
+}
+
+// parsePriceParam reads a decimal price such as "19.99" in the store's
+// currency.
+func parsePriceParam(v string) (*pb.Money, error) {
+	if v == "" {
+		return nil, nil
+	}
+
+	price, err := money.Parse(v, money.DefaultCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBMoney(&price), nil
+}
+
+func (h *handler) searchProducts(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if strings.TrimSpace(q) == "" {
+		http.Error(w, "missing search query", http.StatusBadRequest)
+		return
+	}
+
+	var limit int64
+	if v := r.URL.Query().Get("limit"); v != "" {
+		var err error
+		limit, err = strconv.ParseInt(v, 10, 32)
+		if err != nil || limit < 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	sr, err := h.client.SearchProducts(h.ctx, &pb.SearchProductsReq{
+		Query: q,
+		Limit: int32(limit),
+	})
+	if err != nil {
+		http.Error(w, "error searching products", http.StatusInternalServerError)
+		return
+	}
+
+	res := SearchProductsRes{
+		Hits: []ProductSearchHit{},
+	}
+	for _, hit := range sr.GetHits() {
+		res.Hits = append(res.Hits, ProductSearchHit{
+			Product:    toProductRes(hit.GetProduct()),
+			Score:      hit.GetScore(),
+			Highlights: hit.GetHighlights(),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) updateProduct(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	var p ProductReq
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+	p.ID = i
+
+	updated, err := h.client.UpdateProduct(h.ctx, toPBProductReq(p))
+	if err != nil {
+		rpcError(w, err, "error updating product")
+		return
+	}
+
+	res := toProductRes(updated)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) deleteProduct(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.client.DeleteProduct(h.ctx, &pb.ProductReq{Id: i})
+	if err != nil {
+		http.Error(w, "error deleting product", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) createVariant(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	var req VariantReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	pv := toPBVariantReq(req)
+	pv.ProductId = productID
+	variant, err := h.client.CreateVariant(h.ctx, pv)
+	if err != nil {
+		rpcError(w, err, "error creating variant")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toVariantRes(variant))
+}
+
+func (h *handler) updateVariant(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+	variantID, err := strconv.ParseInt(chi.URLParam(r, "variantID"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing variant id", http.StatusBadRequest)
+		return
+	}
+
+	var req VariantReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	pv := toPBVariantReq(req)
+	pv.Id = variantID
+	pv.ProductId = productID
+	variant, err := h.client.UpdateVariant(h.ctx, pv)
+	if err != nil {
+		rpcError(w, err, "error updating variant")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toVariantRes(variant))
+}
+
+func (h *handler) deleteVariant(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+	variantID, err := strconv.ParseInt(chi.URLParam(r, "variantID"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing variant id", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.client.DeleteVariant(h.ctx, &pb.VariantReq{Id: variantID, ProductId: productID})
+	if err != nil {
+		rpcError(w, err, "error deleting variant")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) createScheduledPrice(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	var req ScheduledPriceReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	psp := toPBScheduledPriceReq(req)
+	psp.ProductId = productID
+	sp, err := h.client.CreateScheduledPrice(h.ctx, psp)
+	if err != nil {
+		rpcError(w, err, "error creating scheduled price")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toScheduledPriceRes(sp))
+}
+
+func (h *handler) listScheduledPrices(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	prices, err := h.client.ListScheduledPrices(h.ctx, &pb.ScheduledPriceReq{ProductId: productID})
+	if err != nil {
+		rpcError(w, err, "error listing scheduled prices")
+		return
+	}
+
+	res := ListScheduledPricesRes{
+		Prices: make([]ScheduledPriceRes, 0, len(prices.GetPrices())),
+	}
+	for _, sp := range prices.GetPrices() {
+		res.Prices = append(res.Prices, toScheduledPriceRes(sp))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) updateScheduledPrice(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+	priceID, err := strconv.ParseInt(chi.URLParam(r, "priceID"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing price id", http.StatusBadRequest)
+		return
+	}
+
+	var req ScheduledPriceReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	psp := toPBScheduledPriceReq(req)
+	psp.Id = priceID
+	psp.ProductId = productID
+	sp, err := h.client.UpdateScheduledPrice(h.ctx, psp)
+	if err != nil {
+		rpcError(w, err, "error updating scheduled price")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toScheduledPriceRes(sp))
+}
+
+func (h *handler) deleteScheduledPrice(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+	priceID, err := strconv.ParseInt(chi.URLParam(r, "priceID"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing price id", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.client.DeleteScheduledPrice(h.ctx, &pb.ScheduledPriceReq{Id: priceID, ProductId: productID})
+	if err != nil {
+		rpcError(w, err, "error deleting scheduled price")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) createWarehouse(w http.ResponseWriter, r *http.Request) {
+	var req WarehouseReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	wh, err := h.client.CreateWarehouse(h.ctx, &pb.WarehouseReq{Code: req.Code, Name: req.Name})
+	if err != nil {
+		rpcError(w, err, "error creating warehouse")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toWarehouseRes(wh))
+}
+
+func (h *handler) listWarehouses(w http.ResponseWriter, r *http.Request) {
+	lw, err := h.client.ListWarehouses(h.ctx, &pb.ListWarehousesReq{})
+	if err != nil {
+		rpcError(w, err, "error listing warehouses")
+		return
+	}
+
+	res := ListWarehousesRes{
+		Warehouses: make([]WarehouseRes, 0, len(lw.GetWarehouses())),
+	}
+	for _, wh := range lw.GetWarehouses() {
+		res.Warehouses = append(res.Warehouses, toWarehouseRes(wh))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) updateWarehouse(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	var req WarehouseReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	wh, err := h.client.UpdateWarehouse(h.ctx, &pb.WarehouseReq{Id: id, Code: req.Code, Name: req.Name})
+	if err != nil {
+		rpcError(w, err, "error updating warehouse")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toWarehouseRes(wh))
+}
+
+func (h *handler) getProductStock(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	stock, err := h.client.GetProductStock(h.ctx, &pb.StockReq{ProductId: productID})
+	if err != nil {
+		rpcError(w, err, "error getting stock")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toStockRes(stock))
+}
+
+// adjustStock records a receipt or an adjustment in the inventory ledger in
+// the name of the admin making it.
+func (h *handler) adjustStock(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	var req StockAdjustmentReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	stock, err := h.client.AdjustStock(h.ctx, &pb.StockReq{
+		ProductId:   productID,
+		VariantId:   req.VariantID,
+		WarehouseId: req.WarehouseID,
+		Kind:        req.Kind,
+		Quantity:    req.Quantity,
+		Reason:      req.Reason,
+		ActorId:     claims.ID,
+	})
+	if err != nil {
+		rpcError(w, err, "error adjusting stock")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toStockRes(stock))
+}
+
+func (h *handler) transferStock(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	var req StockTransferReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	stock, err := h.client.TransferStock(h.ctx, &pb.StockReq{
+		ProductId:     productID,
+		VariantId:     req.VariantID,
+		WarehouseId:   req.FromWarehouseID,
+		ToWarehouseId: req.ToWarehouseID,
+		Quantity:      req.Quantity,
+		Reason:        req.Reason,
+		ActorId:       claims.ID,
+	})
+	if err != nil {
+		rpcError(w, err, "error transferring stock")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toStockRes(stock))
+}
+
+// listStockMovements pages through the inventory ledger of a product,
+// newest first, optionally in one warehouse.
+func (h *handler) listStockMovements(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	req := &pb.ListStockMovementsReq{ProductId: productID}
+	for name, dst := range map[string]*int64{
+		"warehouse_id": &req.WarehouseId,
+		"before_id":    &req.BeforeId,
+		"limit":        &req.Limit,
+	} {
+		v := r.URL.Query().Get(name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			http.Error(w, fmt.Sprintf("invalid %s %q", name, v), http.StatusBadRequest)
+			return
+		}
+		*dst = n
+	}
+
+	lm, err := h.client.ListStockMovements(h.ctx, req)
+	if err != nil {
+		rpcError(w, err, "error listing stock movements")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toListStockMovementsRes(lm))
+}
+
+func (h *handler) createCategory(w http.ResponseWriter, r *http.Request) {
+	var req CategoryReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	category, err := h.client.CreateCategory(h.ctx, toPBCategoryReq(req))
+	if err != nil {
+		rpcError(w, err, "error creating category")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toCategoryRes(category))
+}
+
+func (h *handler) listCategories(w http.ResponseWriter, r *http.Request) {
+	lc, err := h.client.ListCategories(h.ctx, &pb.ListCategoriesReq{})
+	if err != nil {
+		rpcError(w, err, "error listing categories")
+		return
+	}
+
+	res := ListCategoriesRes{
+		Categories: make([]CategoryRes, 0, len(lc.GetCategories())),
+	}
+	for _, c := range lc.GetCategories() {
+		res.Categories = append(res.Categories, toCategoryRes(c))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) getCategory(w http.ResponseWriter, r *http.Request) {
+	category, err := h.client.GetCategory(h.ctx, &pb.CategoryReq{Slug: chi.URLParam(r, "slug")})
+	if err != nil {
+		rpcError(w, err, "error getting category")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toCategoryRes(category))
+}
+
+func (h *handler) updateCategory(w http.ResponseWriter, r *http.Request) {
+	var req CategoryReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	// categories are addressed by slug, which the update itself may change
+	current, err := h.client.GetCategory(h.ctx, &pb.CategoryReq{Slug: chi.URLParam(r, "slug")})
+	if err != nil {
+		rpcError(w, err, "error updating category")
+		return
+	}
+
+	pbReq := toPBCategoryReq(req)
+	pbReq.Id = current.GetId()
+	category, err := h.client.UpdateCategory(h.ctx, pbReq)
+	if err != nil {
+		rpcError(w, err, "error updating category")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toCategoryRes(category))
+}
+
+func (h *handler) deleteCategory(w http.ResponseWriter, r *http.Request) {
+	current, err := h.client.GetCategory(h.ctx, &pb.CategoryReq{Slug: chi.URLParam(r, "slug")})
+	if err != nil {
+		rpcError(w, err, "error deleting category")
+		return
+	}
+
+	_, err = h.client.DeleteCategory(h.ctx, &pb.CategoryReq{Id: current.GetId()})
+	if err != nil {
+		rpcError(w, err, "error deleting category")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) createPromotion(w http.ResponseWriter, r *http.Request) {
+	var req PromotionReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	pp, err := toPBPromotionReq(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	promotion, err := h.client.CreatePromotion(h.ctx, pp)
+	if err != nil {
+		rpcError(w, err, "error creating promotion")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toPromotionRes(promotion))
+}
+
+func (h *handler) listPromotions(w http.ResponseWriter, r *http.Request) {
+	lp, err := h.client.ListPromotions(h.ctx, &pb.ListPromotionsReq{})
+	if err != nil {
+		rpcError(w, err, "error listing promotions")
+		return
+	}
+
+	res := ListPromotionsRes{
+		Promotions: make([]PromotionRes, 0, len(lp.GetPromotions())),
+	}
+	for _, p := range lp.GetPromotions() {
+		res.Promotions = append(res.Promotions, toPromotionRes(p))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) getPromotion(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	promotion, err := h.client.GetPromotion(h.ctx, &pb.PromotionReq{Id: id})
+	if err != nil {
+		rpcError(w, err, "error getting promotion")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toPromotionRes(promotion))
+}
+
+// updatePromotion replaces a promotion, so the body is a full PromotionReq.
+func (h *handler) updatePromotion(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	var req PromotionReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	pp, err := toPBPromotionReq(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	pp.Id = id
+	promotion, err := h.client.UpdatePromotion(h.ctx, pp)
+	if err != nil {
+		rpcError(w, err, "error updating promotion")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toPromotionRes(promotion))
+}
+
+func (h *handler) deletePromotion(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.client.DeletePromotion(h.ctx, &pb.PromotionReq{Id: id})
+	if err != nil {
+		rpcError(w, err, "error deleting promotion")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) createTaxRate(w http.ResponseWriter, r *http.Request) {
+	var req TaxRateReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	rate, err := h.client.CreateTaxRate(h.ctx, toPBTaxRateReq(req))
+	if err != nil {
+		rpcError(w, err, "error creating tax rate")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toTaxRateRes(rate))
+}
+
+func (h *handler) listTaxRates(w http.ResponseWriter, r *http.Request) {
+	lt, err := h.client.ListTaxRates(h.ctx, &pb.ListTaxRatesReq{})
+	if err != nil {
+		rpcError(w, err, "error listing tax rates")
+		return
+	}
+
+	res := ListTaxRatesRes{
+		TaxRates: make([]TaxRateRes, 0, len(lt.GetTaxRates())),
+	}
+	for _, t := range lt.GetTaxRates() {
+		res.TaxRates = append(res.TaxRates, toTaxRateRes(t))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(res)
+}
+
+// updateTaxRate replaces a tax rate, so the body is a full TaxRateReq.
+func (h *handler) updateTaxRate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	var req TaxRateReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	tr := toPBTaxRateReq(req)
+	tr.Id = id
+	rate, err := h.client.UpdateTaxRate(h.ctx, tr)
+	if err != nil {
+		rpcError(w, err, "error updating tax rate")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toTaxRateRes(rate))
+}
+
+func (h *handler) deleteTaxRate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.client.DeleteTaxRate(h.ctx, &pb.TaxRateReq{Id: id})
+	if err != nil {
+		rpcError(w, err, "error deleting tax rate")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) createShippingZone(w http.ResponseWriter, r *http.Request) {
+	var req ShippingZoneReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	zone, err := h.client.CreateShippingZone(h.ctx, toPBShippingZoneReq(req))
+	if err != nil {
+		rpcError(w, err, "error creating shipping zone")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toShippingZoneRes(zone))
+}
+
+func (h *handler) listShippingZones(w http.ResponseWriter, r *http.Request) {
+	lz, err := h.client.ListShippingZones(h.ctx, &pb.ListShippingZonesReq{})
+	if err != nil {
+		rpcError(w, err, "error listing shipping zones")
+		return
+	}
+
+	res := ListShippingZonesRes{
+		Zones: make([]ShippingZoneRes, 0, len(lz.GetZones())),
+	}
+	for _, z := range lz.GetZones() {
+		res.Zones = append(res.Zones, toShippingZoneRes(z))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(res)
+}
+
+// updateShippingZone replaces a zone, so the body is a full ShippingZoneReq.
+func (h *handler) updateShippingZone(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	var req ShippingZoneReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	sz := toPBShippingZoneReq(req)
+	sz.Id = id
+	zone, err := h.client.UpdateShippingZone(h.ctx, sz)
+	if err != nil {
+		rpcError(w, err, "error updating shipping zone")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toShippingZoneRes(zone))
+}
+
+func (h *handler) deleteShippingZone(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.client.DeleteShippingZone(h.ctx, &pb.ShippingZoneReq{Id: id})
+	if err != nil {
+		rpcError(w, err, "error deleting shipping zone")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) createShippingMethod(w http.ResponseWriter, r *http.Request) {
+	zoneID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	var req ShippingMethodReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	sm, err := toPBShippingMethodReq(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sm.ZoneId = zoneID
+	method, err := h.client.CreateShippingMethod(h.ctx, sm)
+	if err != nil {
+		rpcError(w, err, "error creating shipping method")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toShippingMethodRes(method))
+}
+
+// updateShippingMethod replaces a shipping method, so the body is a full
+// ShippingMethodReq. The method stays in its zone.
+func (h *handler) updateShippingMethod(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	var req ShippingMethodReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	sm, err := toPBShippingMethodReq(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sm.Id = id
+	method, err := h.client.UpdateShippingMethod(h.ctx, sm)
+	if err != nil {
+		rpcError(w, err, "error updating shipping method")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toShippingMethodRes(method))
+}
+
+func (h *handler) deleteShippingMethod(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.client.DeleteShippingMethod(h.ctx, &pb.ShippingMethodReq{Id: id})
+	if err != nil {
+		rpcError(w, err, "error deleting shipping method")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// quoteShipping prices the ways the cart can ship. An empty body quotes
+// shipping to the default address.
+func (h *handler) quoteShipping(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	var req ShippingQuoteReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	quote, err := h.client.QuoteShipping(h.ctx, &pb.ShippingQuoteReq{
+		UserId:    claims.ID,
+		AddressId: req.AddressID,
+		Address:   toPBAddress(req.Address),
+	})
+	if err != nil {
+		rpcError(w, err, "error quoting shipping")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toShippingQuoteRes(quote))
+}
+
+func toTimePtr(t time.Time) *time.Time {
+	return &t
+}
+
+// rpcError writes err to w. Errors the gRPC server attributes to the request
+// are passed through with their message; anything else is reported as msg.
+func rpcError(w http.ResponseWriter, err error, msg string) {
+	st := status.Convert(err)
+
+	var code int
+	switch st.Code() {
+	case codes.InvalidArgument, codes.OutOfRange:
+		code = http.StatusBadRequest
+	case codes.NotFound:
+		code = http.StatusNotFound
+	case codes.AlreadyExists, codes.FailedPrecondition, codes.Aborted:
+		code = http.StatusConflict
+	case codes.PermissionDenied:
+		code = http.StatusForbidden
+	case codes.Unauthenticated:
+		code = http.StatusUnauthorized
+	default:
+		http.Error(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	http.Error(w, st.Message(), code)
+}
+
+func (h *handler) createOrder(w http.ResponseWriter, r *http.Request) {
+	var order OrderReq
+	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+	so := toPBOrderReq(order)
+	so.UserId = claims.ID
+	so.UserEmail = claims.Email
+
+	createdOrder, err := h.client.CreateOrder(h.ctx, so)
+	if err != nil {
+		rpcError(w, err, "error creating order")
+		return
+	}
+
+	res := toOrderRes(createdOrder)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) listOrders(w http.ResponseWriter, r *http.Request) {
+	lo, err := h.client.ListOrders(h.ctx, &pb.OrderReq{})
+	if err != nil {
+		http.Error(w, "error listing orders", http.StatusInternalServerError)
+		return
+	}
+
+	var res []OrderRes
+	for _, o := range lo.GetOrders() {
+		res = append(res, toOrderRes(o))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) updateOrderStatus(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	var order OrderReq
+	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	status, err := toPBOrderStatus(OrderStatus(order.Status))
+	if err != nil {
+		http.Error(w, "unknown order status", http.StatusBadRequest)
+		return
+	}
+
+	uo, err := h.client.UpdateOrderStatus(h.ctx, &pb.OrderReq{
+		Id:                  order.ID,
+		UserId:              claims.ID,
+		UserEmail:           claims.Email,
+		Status:              status,
+		Reason:              order.Reason,
+		IsAdmin:             claims.IsAdmin,
+		RefundToStoreCredit: order.RefundToStoreCredit,
+	})
+	if err != nil {
+		rpcError(w, err, "failed to update the order status")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toOrderRes(uo))
+}
+
+func (h *handler) cancelOrder(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	// the reason is optional, so an empty body is fine
+	var req CancelOrderReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	uo, err := h.client.UpdateOrderStatus(h.ctx, &pb.OrderReq{
+		Id:        id,
+		UserId:    claims.ID,
+		UserEmail: claims.Email,
+		Status:    pb.OrderStatus_CANCELLED,
+		Reason:    req.Reason,
+		IsAdmin:   claims.IsAdmin,
+	})
+	if err != nil {
+		rpcError(w, err, "failed to cancel the order")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toOrderRes(uo))
+}
+
+func (h *handler) listOrderStatusChanges(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.client.ListOrderStatusChanges(h.ctx, &pb.OrderReq{
+		Id:      id,
+		UserId:  claims.ID,
+		IsAdmin: claims.IsAdmin,
+	})
+	if err != nil {
+		rpcError(w, err, "error listing order status history")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toOrderStatusChanges(res.GetChanges()))
+}
+
+// payOrder pays an order. A declined payment is answered with 402 Payment
+// Required and a pending one with 202 Accepted; the order moves to paid once
+// the payment is captured.
+func (h *handler) payOrder(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	var req PayOrderReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	p, err := h.client.PayOrder(h.ctx, &pb.PaymentReq{
+		OrderId: id,
+		UserId:  claims.ID,
+		IsAdmin: claims.IsAdmin,
+		Source:  req.Source,
+	})
+	if err != nil {
+		rpcError(w, err, "error paying order")
+		return
+	}
+
+	code := http.StatusOK
+	switch p.GetStatus() {
+	case string(payments.StatusDeclined):
+		code = http.StatusPaymentRequired
+	case string(payments.StatusPending):
+		code = http.StatusAccepted
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(toPaymentRes(p))
+}
+
+func (h *handler) listOrderPayments(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.client.ListOrderPayments(h.ctx, &pb.PaymentReq{
+		OrderId: id,
+		UserId:  claims.ID,
+		IsAdmin: claims.IsAdmin,
+	})
+	if err != nil {
+		rpcError(w, err, "error listing payments")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toPaymentsRes(res.GetPayments()))
+}
+
+// createReturn requests the return of an item of a delivered order of the
+// caller's.
+func (h *handler) createReturn(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	var req ReturnReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	ret, err := h.client.CreateReturn(h.ctx, &pb.ReturnReq{
+		OrderId:     id,
+		OrderItemId: req.OrderItemID,
+		UserId:      claims.ID,
+		IsAdmin:     claims.IsAdmin,
+		Quantity:    req.Quantity,
+		Reason:      req.Reason,
+		PhotosUrl:   req.PhotosURL,
+	})
+	if err != nil {
+		rpcError(w, err, "error creating return")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toReturnRes(ret))
+}
+
+func (h *handler) listOrderReturns(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.client.ListOrderReturns(h.ctx, &pb.OrderReq{
+		Id:      id,
+		UserId:  claims.ID,
+		IsAdmin: claims.IsAdmin,
+	})
+	if err != nil {
+		rpcError(w, err, "error listing returns")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toReturnsRes(res.GetReturns()))
+}
+
+// listReturns lists every return, or those in ?status, oldest first.
+func (h *handler) listReturns(w http.ResponseWriter, r *http.Request) {
+	res, err := h.client.ListReturns(h.ctx, &pb.ListReturnsReq{Status: r.URL.Query().Get("status")})
+	if err != nil {
+		rpcError(w, err, "error listing returns")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toReturnsRes(res.GetReturns()))
+}
+
+// updateReturnStatus moves a return along. Refunding a return refunds its
+// amount to the order's payment.
+func (h *handler) updateReturnStatus(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	var req UpdateReturnStatusReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	ret, err := h.client.UpdateReturnStatus(h.ctx, &pb.ReturnReq{
+		Id:                  id,
+		UserId:              claims.ID,
+		IsAdmin:             claims.IsAdmin,
+		Status:              req.Status,
+		Note:                req.Note,
+		Restock:             req.Restock,
+		RefundToStoreCredit: req.RefundToStoreCredit,
+	})
+	if err != nil {
+		rpcError(w, err, "error updating return status")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toReturnRes(ret))
+}
+
+// paymentWebhook receives a payment provider's asynchronous events, the
+// provider being named in the path. The body must be signed with the shared
+// webhook secret.
+func (h *handler) paymentWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "error reading request body", http.StatusBadRequest)
+		return
+	}
+
+	if h.webhookSecret == "" || !payments.VerifySignature(h.webhookSecret, body, r.Header.Get(payments.SignatureHeader)) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var e payments.Event
+	if err := json.Unmarshal(body, &e); err != nil {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.client.HandlePaymentEvent(h.ctx, &pb.PaymentEventReq{
+		Provider:  chi.URLParam(r, "provider"),
+		Reference: e.Reference,
+		Status:    string(e.Status),
+		Message:   e.Message,
+	})
+	if err != nil {
+		rpcError(w, err, "error handling payment event")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// createGiftCard issues a gift card in the name of the admin issuing it.
+func (h *handler) createGiftCard(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	var req GiftCardReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	gr := toPBGiftCardReq(req)
+	gr.UserId = claims.ID
+	g, err := h.client.CreateGiftCard(h.ctx, gr)
+	if err != nil {
+		rpcError(w, err, "error creating gift card")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toGiftCardRes(g))
+}
+
+// getGiftCard checks the balance of a gift card. Admins and the card's
+// purchaser also get its history.
+func (h *handler) getGiftCard(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	g, err := h.client.GetGiftCard(h.ctx, &pb.GiftCardReq{
+		Code:    chi.URLParam(r, "code"),
+		UserId:  claims.ID,
+		IsAdmin: claims.IsAdmin,
+	})
+	if err != nil {
+		rpcError(w, err, "error getting gift card")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toGiftCardRes(g))
+}
+
+func (h *handler) updateGiftCard(w http.ResponseWriter, r *http.Request) {
+	var req GiftCardReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	gr := toPBGiftCardReq(req)
+	gr.Code = chi.URLParam(r, "code")
+	g, err := h.client.UpdateGiftCard(h.ctx, gr)
+	if err != nil {
+		rpcError(w, err, "error updating gift card")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toGiftCardRes(g))
+}
+
+func (h *handler) adjustGiftCard(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	var req BalanceAdjustmentReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	g, err := h.client.AdjustGiftCard(h.ctx, &pb.GiftCardReq{
+		Code:    chi.URLParam(r, "code"),
+		Amount:  toPBMoney(req.Amount),
+		Reason:  req.Reason,
+		UserId:  claims.ID,
+		IsAdmin: claims.IsAdmin,
+	})
+	if err != nil {
+		rpcError(w, err, "error adjusting gift card")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toGiftCardRes(g))
+}
+
+// listMyGiftCards lists the gift cards the user bought.
+func (h *handler) listMyGiftCards(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	lg, err := h.client.ListGiftCards(h.ctx, &pb.GiftCardReq{UserId: claims.ID})
+	if err != nil {
+		rpcError(w, err, "error listing gift cards")
+		return
+	}
+
+	res := ListGiftCardsRes{
+		GiftCards: make([]GiftCardRes, 0, len(lg.GetGiftCards())),
+	}
+	for _, g := range lg.GetGiftCards() {
+		res.GiftCards = append(res.GiftCards, toGiftCardRes(g))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) getMyStoreCredit(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+	h.writeStoreCredit(w, claims.ID)
+}
+
+func (h *handler) getUserStoreCredit(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+	h.writeStoreCredit(w, id)
+}
+
+func (h *handler) writeStoreCredit(w http.ResponseWriter, userID int64) {
+	sc, err := h.client.GetStoreCredit(h.ctx, &pb.StoreCreditReq{UserId: userID})
+	if err != nil {
+		rpcError(w, err, "error getting store credit")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toStoreCreditRes(sc))
+}
+
+// getMyLoyalty returns the user's loyalty points, tier and points history.
+func (h *handler) getMyLoyalty(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	l, err := h.client.GetLoyalty(h.ctx, &pb.LoyaltyReq{UserId: claims.ID})
+	if err != nil {
+		rpcError(w, err, "error getting loyalty points")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toLoyaltyRes(l))
+}
+
+// adjustStoreCredit grants a user store credit, or takes it off, in the name
+// of the admin making the change.
+func (h *handler) adjustStoreCredit(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	var req BalanceAdjustmentReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	sc, err := h.client.AdjustStoreCredit(h.ctx, &pb.StoreCreditReq{
+		UserId:  id,
+		Amount:  toPBMoney(req.Amount),
+		Reason:  req.Reason,
+		ActorId: claims.ID,
+	})
+	if err != nil {
+		rpcError(w, err, "error adjusting store credit")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toStoreCreditRes(sc))
+}
+
+func (h *handler) getMyOrder(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	order, err := h.client.GetOrder(h.ctx, &pb.OrderReq{Id: id, UserId: claims.ID})
+	if err != nil {
+		rpcError(w, err, "error getting order")
+		return
+	}
+
+	res := toOrderRes(order)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(res)
+}
+
+// getOrderInvoice downloads the invoice of a paid order, as PDF unless
+// ?format=html is asked for.
+func (h *handler) getOrderInvoice(w http.ResponseWriter, r *http.Request) {
+	inv, ok := h.fetchOrderInvoice(w, r)
+	if !ok {
+		return
+	}
+
+	writeDocument(w, r, toInvoiceDocument(inv))
+}
+
+// getOrderCreditNote downloads a credit note issued against the invoice of
+// an order, in the same formats as the invoice.
+func (h *handler) getOrderCreditNote(w http.ResponseWriter, r *http.Request) {
+	inv, ok := h.fetchOrderInvoice(w, r)
+	if !ok {
+		return
+	}
+
+	number := chi.URLParam(r, "number")
+	for _, n := range inv.GetCreditNotes() {
+		if n.GetNumber() == number {
+			writeDocument(w, r, toCreditNoteDocument(inv, n))
+			return
+		}
+	}
+
+	http.Error(w, fmt.Sprintf("credit note %s not found", number), http.StatusNotFound)
+}
+
+// fetchOrderInvoice gets the invoice of the order in the path, writing the
+// error response if it cannot.
+func (h *handler) fetchOrderInvoice(w http.ResponseWriter, r *http.Request) (*pb.InvoiceRes, bool) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return nil, false
+	}
+
+	inv, err := h.client.GetOrderInvoice(h.ctx, &pb.OrderReq{
+		Id:      id,
+		UserId:  claims.ID,
+		IsAdmin: claims.IsAdmin,
+	})
+	if err != nil {
+		rpcError(w, err, "error getting invoice")
+		return nil, false
+	}
+
+	return inv, true
+}
+
+// writeDocument renders d in the format asked for by ?format, pdf or html,
+// defaulting to pdf.
+func writeDocument(w http.ResponseWriter, r *http.Request, d *invoice.Document) {
+	var buf bytes.Buffer
+	var err error
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "pdf":
+		err = invoice.RenderPDF(&buf, d)
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", d.Number+".pdf"))
+	case "html":
+		err = invoice.RenderHTML(&buf, d)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	default:
+		http.Error(w, fmt.Sprintf("unsupported format %q", format), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		w.Header().Del("Content-Disposition")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	buf.WriteTo(w)
+}
+
+func (h *handler) listMyOrders(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	req, err := parseListUserOrdersReq(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.UserId = claims.ID
+
+	lo, err := h.client.ListUserOrders(h.ctx, req)
+	if err != nil {
+		rpcError(w, err, "error listing orders")
+		return
+	}
+
+	res := ListOrderRes{
+		Orders:     make([]OrderRes, 0, len(lo.GetOrders())),
+		NextCursor: lo.GetNextCursor(),
+	}
+	for _, o := range lo.GetOrders() {
+		res.Orders = append(res.Orders, toOrderRes(o))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(res)
+}
+
+// parseListUserOrdersReq reads the cursor, limit, status, from and to query
+// parameters. Dates are RFC 3339 timestamps or plain YYYY-MM-DD dates.
+func parseListUserOrdersReq(r *http.Request) (*pb.ListUserOrdersReq, error) {
+	q := r.URL.Query()
+	req := &pb.ListUserOrdersReq{
+		Cursor: q.Get("cursor"),
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid limit %q", v)
+		}
+		req.Limit = int32(limit)
+	}
+
+	if v := q.Get("status"); v != "" {
+		st, err := toPBOrderStatus(OrderStatus(v))
+		if err != nil {
+			return nil, fmt.Errorf("invalid status %q", v)
+		}
+		req.Status = &st
+	}
+
+	var err error
+	if req.From, err = parseDateParam(q.Get("from")); err != nil {
+		return nil, fmt.Errorf("invalid from: %w", err)
+	}
+	if req.To, err = parseDateParam(q.Get("to")); err != nil {
+		return nil, fmt.Errorf("invalid to: %w", err)
+	}
+
+	return req, nil
+}
+
+func parseDateParam(v string) (*timestamppb.Timestamp, error) {
+	if v == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		t, err = time.Parse(time.DateOnly, v)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a date", v)
+		}
+	}
+
+	return timestamppb.New(t), nil
+}
+
+func (h *handler) deleteOrder(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+	_, err = h.client.DeleteOrder(h.ctx, &pb.OrderReq{Id: i, UserId: claims.ID, IsAdmin: claims.IsAdmin})
+	if err != nil {
+		rpcError(w, err, "error deleting order")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) createReview(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	var req ReviewReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.client.CreateReview(h.ctx, &pb.ReviewReq{
+		ProductId: productID,
+		UserId:    claims.ID,
+		Rating:    req.Rating,
+		Title:     req.Title,
+		Body:      req.Body,
+	})
+	if err != nil {
+		rpcError(w, err, "error creating review")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toReviewRes(review))
+}
+
+func (h *handler) listProductReviews(w http.ResponseWriter, r *http.Request) {
+	h.writeProductReviews(w, r, false)
+}
+
+// listAllProductReviews lists hidden reviews too, for moderation.
+func (h *handler) listAllProductReviews(w http.ResponseWriter, r *http.Request) {
+	h.writeProductReviews(w, r, true)
+}
+
+func (h *handler) writeProductReviews(w http.ResponseWriter, r *http.Request, includeHidden bool) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	req := &pb.ListReviewsReq{
+		ProductId:     productID,
+		Cursor:        r.URL.Query().Get("cursor"),
+		IncludeHidden: includeHidden,
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || limit < 0 {
+			http.Error(w, fmt.Sprintf("invalid limit %q", v), http.StatusBadRequest)
+			return
+		}
+		req.Limit = int32(limit)
+	}
+
+	lr, err := h.client.ListProductReviews(h.ctx, req)
+	if err != nil {
+		rpcError(w, err, "error listing reviews")
+		return
+	}
+
+	res := ListReviewsRes{
+		Reviews:    make([]ReviewRes, 0, len(lr.GetReviews())),
+		NextCursor: lr.GetNextCursor(),
+	}
+	for _, review := range lr.GetReviews() {
+		res.Reviews = append(res.Reviews, toReviewRes(review))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) updateReview(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	var req ReviewReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.client.UpdateReview(h.ctx, &pb.ReviewReq{
+		Id:     id,
+		UserId: claims.ID,
+		Rating: req.Rating,
+		Title:  req.Title,
+		Body:   req.Body,
+	})
+	if err != nil {
+		rpcError(w, err, "error updating review")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toReviewRes(review))
+}
+
+func (h *handler) deleteReview(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.client.DeleteReview(h.ctx, &pb.ReviewReq{
+		Id:      id,
+		UserId:  claims.ID,
+		IsAdmin: claims.IsAdmin,
+	})
+	if err != nil {
+		rpcError(w, err, "error deleting review")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) moderateReview(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	var req ModerateReviewReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.client.ModerateReview(h.ctx, &pb.ReviewReq{
+		Id:      id,
+		UserId:  claims.ID,
+		IsAdmin: claims.IsAdmin,
+		Hidden:  req.Hidden,
+	})
+	if err != nil {
+		rpcError(w, err, "error moderating review")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toReviewRes(review))
+}
+
+func (h *handler) getCart(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	cart, err := h.client.GetCart(h.ctx, &pb.CartReq{UserId: claims.ID})
+	if err != nil {
+		rpcError(w, err, "error getting cart")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toCartRes(cart))
+}
+
+func (h *handler) addCartItem(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	var req CartItemReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	cart, err := h.client.AddCartItem(h.ctx, &pb.CartReq{
+		UserId:    claims.ID,
+		ProductId: req.ProductID,
+		VariantId: req.VariantID,
+		Quantity:  req.Quantity,
+	})
 	if err != nil {
-		http.Error(w, "error listing products", http.StatusInternalServerError)
+		rpcError(w, err, "error adding cart item")
 		return
 	}
 
-	var res []ProductRes
-	for _, p := range lpr.GetProducts() {
-		res = append(res, toProductRes(p))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toCartRes(cart))
+}
+
+func (h *handler) updateCartItem(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	productID, err := strconv.ParseInt(chi.URLParam(r, "productID"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing product id", http.StatusBadRequest)
+		return
+	}
+	variantID, err := parseVariantID(r)
+	if err != nil {
+		http.Error(w, "error parsing variant id", http.StatusBadRequest)
+		return
+	}
+
+	var req CartItemReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	cart, err := h.client.UpdateCartItem(h.ctx, &pb.CartReq{
+		UserId:    claims.ID,
+		ProductId: productID,
+		VariantId: variantID,
+		Quantity:  req.Quantity,
+	})
+	if err != nil {
+		rpcError(w, err, "error updating cart item")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toCartRes(cart))
+}
+
+func (h *handler) removeCartItem(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	productID, err := strconv.ParseInt(chi.URLParam(r, "productID"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing product id", http.StatusBadRequest)
+		return
+	}
+	variantID, err := parseVariantID(r)
+	if err != nil {
+		http.Error(w, "error parsing variant id", http.StatusBadRequest)
+		return
+	}
+
+	cart, err := h.client.RemoveCartItem(h.ctx, &pb.CartReq{UserId: claims.ID, ProductId: productID, VariantId: variantID})
+	if err != nil {
+		rpcError(w, err, "error removing cart item")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toCartRes(cart))
+}
+
+// parseVariantID reads the variant of a cart item from the variant_id query
+// parameter, which is left out for products without variants.
+func parseVariantID(r *http.Request) (int64, error) {
+	v := r.URL.Query().Get("variant_id")
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(v, 10, 64)
+}
+
+func (h *handler) clearCart(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	cart, err := h.client.ClearCart(h.ctx, &pb.CartReq{UserId: claims.ID})
+	if err != nil {
+		rpcError(w, err, "error clearing cart")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toCartRes(cart))
+}
+
+func (h *handler) checkoutCart(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	var req CheckoutCartReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	order, err := h.client.CheckoutCart(h.ctx, &pb.CheckoutCartReq{
+		UserId:            claims.ID,
+		UserEmail:         claims.Email,
+		PaymentMethod:     req.PaymentMethod,
+		ShippingAddress:   toPBAddress(req.ShippingAddress),
+		ShippingAddressId: req.ShippingAddressID,
+		BillingAddress:    toPBAddress(req.BillingAddress),
+		BillingAddressId:  req.BillingAddressID,
+		ShippingMethodId:  req.ShippingMethodID,
+		CouponCode:        req.CouponCode,
+		TotalPrice:        toPBMoney(req.TotalPrice),
+		GiftCardCodes:     req.GiftCardCodes,
+		StoreCredit:       toPBMoney(req.StoreCredit),
+		LoyaltyPoints:     req.LoyaltyPoints,
+	})
+	if err != nil {
+		rpcError(w, err, "error checking out cart")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toOrderRes(order))
+}
+
+func (h *handler) createAddress(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	var req AddressReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	ar := toPBAddressReq(req)
+	ar.UserId = claims.ID
+	address, err := h.client.CreateAddress(h.ctx, ar)
+	if err != nil {
+		rpcError(w, err, "error creating address")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toAddressRes(address))
+}
+
+func (h *handler) listAddresses(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	la, err := h.client.ListAddresses(h.ctx, &pb.AddressReq{UserId: claims.ID})
+	if err != nil {
+		rpcError(w, err, "error listing addresses")
+		return
+	}
+
+	res := ListAddressesRes{
+		Addresses: make([]AddressRes, 0, len(la.GetAddresses())),
+	}
+	for _, a := range la.GetAddresses() {
+		res.Addresses = append(res.Addresses, toAddressRes(a))
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -86,158 +2240,286 @@ func (h *handler) listProducts(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(res)
 }
 
-func (h *handler) updateProduct(w http.ResponseWriter, r *http.Request) {
-	id := chi.URLParam(r, "id")
-	i, err := strconv.ParseInt(id, 10, 64)
+// updateAddress replaces a saved address, so the body is a full AddressReq.
+func (h *handler) updateAddress(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
 	if err != nil {
 		http.Error(w, "error parsing id", http.StatusBadRequest)
 		return
 	}
 
-	var p ProductReq
-	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
-		http.Error(w, "error decoding request body", http.StatusBadRequest)
+	var req AddressReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
 		return
 	}
-	p.ID = i
 
-	updated, err := h.client.UpdateProduct(h.ctx, toPBProductReq(p))
+	ar := toPBAddressReq(req)
+	ar.Id = id
+	ar.UserId = claims.ID
+	address, err := h.client.UpdateAddress(h.ctx, ar)
 	if err != nil {
-		http.Error(w, "error updating product", http.StatusInternalServerError)
+		rpcError(w, err, "error updating address")
 		return
 	}
 
-	res := toProductRes(updated)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(res)
+	json.NewEncoder(w).Encode(toAddressRes(address))
 }
 
-func (h *handler) deleteProduct(w http.ResponseWriter, r *http.Request) {
-	id := chi.URLParam(r, "id")
-	i, err := strconv.ParseInt(id, 10, 64)
+func (h *handler) deleteAddress(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
 	if err != nil {
 		http.Error(w, "error parsing id", http.StatusBadRequest)
 		return
 	}
 
-	_, err = h.client.DeleteProduct(h.ctx, &pb.ProductReq{Id: i})
+	_, err = h.client.DeleteAddress(h.ctx, &pb.AddressReq{Id: id, UserId: claims.ID})
 	if err != nil {
-		http.Error(w, "error deleting product", http.StatusInternalServerError)
+		rpcError(w, err, "error deleting address")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func toTimePtr(t time.Time) *time.Time {
-	return &t
-}
+func (h *handler) createWishlist(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
 
-func (h *handler) createOrder(w http.ResponseWriter, r *http.Request) {
-	var order OrderReq
-	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+	var req WishlistReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "error parsing request body", http.StatusBadRequest)
 		return
 	}
 
+	wl, err := h.client.CreateWishlist(h.ctx, &pb.WishlistReq{
+		UserId: claims.ID,
+		Name:   req.Name,
+		Shared: req.Shared,
+	})
+	if err != nil {
+		rpcError(w, err, "error creating wishlist")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toWishlistRes(wl))
+}
+
+func (h *handler) listWishlists(w http.ResponseWriter, r *http.Request) {
 	claims := r.Context().Value(authKey{}).(*token.UserClaims)
-	so := toPBOrderReq(order)
-	so.UserId = claims.ID
-	so.UserEmail = claims.Email
 
-	createdOrder, err := h.client.CreateOrder(h.ctx, so)
+	lw, err := h.client.ListWishlists(h.ctx, &pb.WishlistReq{UserId: claims.ID})
 	if err != nil {
-		http.Error(w, "error creating order: %w", http.StatusInternalServerError)
+		rpcError(w, err, "error listing wishlists")
 		return
 	}
 
-	res := toOrderRes(createdOrder)
+	res := ListWishlistsRes{
+		Wishlists: make([]WishlistRes, 0, len(lw.GetWishlists())),
+	}
+	for _, wl := range lw.GetWishlists() {
+		res.Wishlists = append(res.Wishlists, toWishlistRes(wl))
+	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(res)
 }
 
-func (h *handler) listOrders(w http.ResponseWriter, r *http.Request) {
-	lo, err := h.client.ListOrders(h.ctx, &pb.OrderReq{})
+func (h *handler) getWishlist(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
 	if err != nil {
-		http.Error(w, "error listing orders", http.StatusInternalServerError)
+		http.Error(w, "error parsing id", http.StatusBadRequest)
 		return
 	}
 
-	var res []OrderRes
-	for _, o := range lo.GetOrders() {
-		res = append(res, toOrderRes(o))
+	wl, err := h.client.GetWishlist(h.ctx, &pb.WishlistReq{Id: id, UserId: claims.ID})
+	if err != nil {
+		rpcError(w, err, "error getting wishlist")
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(res)
+	json.NewEncoder(w).Encode(toWishlistRes(wl))
 }
 
-func (h *handler) updateOrderStatus(w http.ResponseWriter, r *http.Request) {
+// updateWishlist renames a wishlist or turns sharing on or off. Sharing a
+// wishlist again hands out a new link; the old one stays dead.
+func (h *handler) updateWishlist(w http.ResponseWriter, r *http.Request) {
 	claims := r.Context().Value(authKey{}).(*token.UserClaims)
 
-	var order OrderReq
-	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	var req WishlistReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "error parsing request body", http.StatusBadRequest)
 		return
 	}
 
-	status, err := toPBOrderStatus(OrderStatus(order.Status))
+	wl, err := h.client.UpdateWishlist(h.ctx, &pb.WishlistReq{
+		Id:     id,
+		UserId: claims.ID,
+		Name:   req.Name,
+		Shared: req.Shared,
+	})
 	if err != nil {
-		http.Error(w, "unknown order status", http.StatusBadRequest)
+		rpcError(w, err, "error updating wishlist")
 		return
 	}
 
-	uo, err := h.client.UpdateOrderStatus(h.ctx, &pb.OrderReq{
-		Id:        order.ID,
-		UserId:    claims.ID,
-		UserEmail: claims.Email,
-		Status:    status,
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toWishlistRes(wl))
+}
+
+func (h *handler) deleteWishlist(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.client.DeleteWishlist(h.ctx, &pb.WishlistReq{Id: id, UserId: claims.ID})
+	if err != nil {
+		rpcError(w, err, "error deleting wishlist")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) addWishlistItem(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	var req WishlistItemReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	wl, err := h.client.AddWishlistItem(h.ctx, &pb.WishlistItemReq{
+		WishlistId: id,
+		UserId:     claims.ID,
+		ProductId:  req.ProductID,
+		VariantId:  req.VariantID,
 	})
 	if err != nil {
-		http.Error(w, "failed to update the order status", http.StatusInternalServerError)
-		fmt.Printf("error: %s", err)
+		rpcError(w, err, "error adding wishlist item")
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(toOrderRes(uo))
+	json.NewEncoder(w).Encode(toWishlistRes(wl))
 }
 
-func (h *handler) getOrder(w http.ResponseWriter, r *http.Request) {
-	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+func (h *handler) removeWishlistItem(w http.ResponseWriter, r *http.Request) {
+	req, ok := parseWishlistItemReq(w, r)
+	if !ok {
+		return
+	}
 
-	order, err := h.client.GetOrder(h.ctx, &pb.OrderReq{UserId: claims.ID})
+	wl, err := h.client.RemoveWishlistItem(h.ctx, req)
 	if err != nil {
-		http.Error(w, "error getting order", http.StatusInternalServerError)
+		rpcError(w, err, "error removing wishlist item")
 		return
 	}
 
-	res := toOrderRes(order)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(res)
+	json.NewEncoder(w).Encode(toWishlistRes(wl))
 }
 
-func (h *handler) deleteOrder(w http.ResponseWriter, r *http.Request) {
-	id := chi.URLParam(r, "id")
-	i, err := strconv.ParseInt(id, 10, 64)
+// moveWishlistItemToCart adds a wishlist item to the cart and takes it off
+// the wishlist, answering with the cart.
+func (h *handler) moveWishlistItemToCart(w http.ResponseWriter, r *http.Request) {
+	req, ok := parseWishlistItemReq(w, r)
+	if !ok {
+		return
+	}
+
+	// the quantity is optional, so an empty body is fine
+	var body WishlistItemReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
+		return
+	}
+	req.Quantity = body.Quantity
+
+	cart, err := h.client.MoveWishlistItemToCart(h.ctx, req)
 	if err != nil {
-		http.Error(w, "error parsing id", http.StatusBadRequest)
+		rpcError(w, err, "error moving wishlist item to cart")
 		return
 	}
 
-	_, err = h.client.DeleteOrder(h.ctx, &pb.OrderReq{Id: i})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toCartRes(cart))
+}
+
+// parseWishlistItemReq reads the wishlist and product of a wishlist item
+// from the path and its variant from the query, writing the error response
+// if it cannot.
+func parseWishlistItemReq(w http.ResponseWriter, r *http.Request) (*pb.WishlistItemReq, bool) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return nil, false
+	}
+	productID, err := strconv.ParseInt(chi.URLParam(r, "productID"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing product id", http.StatusBadRequest)
+		return nil, false
+	}
+	variantID, err := parseVariantID(r)
+	if err != nil {
+		http.Error(w, "error parsing variant id", http.StatusBadRequest)
+		return nil, false
+	}
+
+	return &pb.WishlistItemReq{
+		WishlistId: id,
+		UserId:     claims.ID,
+		ProductId:  productID,
+		VariantId:  variantID,
+	}, true
+}
+
+// getSharedWishlist shows a shared wishlist to anyone who has its link.
+func (h *handler) getSharedWishlist(w http.ResponseWriter, r *http.Request) {
+	wl, err := h.client.GetSharedWishlist(h.ctx, &pb.SharedWishlistReq{ShareToken: chi.URLParam(r, "token")})
 	if err != nil {
-		http.Error(w, "error deleting order", http.StatusInternalServerError)
+		rpcError(w, err, "error getting wishlist")
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toWishlistRes(wl))
 }
 
 func (h *handler) createUser(w http.ResponseWriter, r *http.Request) {
@@ -324,6 +2606,32 @@ func (h *handler) deleteUser(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// setUserTaxExemption flags a customer as exempt from tax, e.g. a business
+// that has shown a resale certificate, or clears the flag.
+func (h *handler) setUserTaxExemption(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing id", http.StatusBadRequest)
+		return
+	}
+
+	var req TaxExemptionReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.client.SetUserTaxExempt(h.ctx, &pb.UserReq{Id: id, TaxExempt: req.TaxExempt})
+	if err != nil {
+		rpcError(w, err, "error setting tax exemption")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toUserRes(updated))
+}
+
 func (h *handler) loginUser(w http.ResponseWriter, r *http.Request) {
 	var u LoginUserReq
 	if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
@@ -5,42 +5,297 @@ import (
 	"strings"
 
 	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
+	"github.com/OrkhanMehbaliyev/ecom-golang/invoice"
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+func toPBMoney(m *money.Money) *pb.Money {
+	if m == nil {
+		return nil
+	}
+
+	return &pb.Money{
+		Amount:   m.Amount,
+		Currency: m.Currency,
+	}
+}
+
+func toMoney(m *pb.Money) money.Money {
+	return money.New(m.GetAmount(), m.GetCurrency())
+}
+
+func toMoneyPtr(m *pb.Money) *money.Money {
+	if m == nil {
+		return nil
+	}
+
+	v := toMoney(m)
+	return &v
+}
+
 func toPBProductReq(p ProductReq) *pb.ProductReq {
 	return &pb.ProductReq{
 		Id:           p.ID,
 		Name:         p.Name,
 		Image:        p.Image,
-		Category:     p.Category,
+		CategoryId:   p.CategoryID,
 		Description:  p.Description,
-		Rating:       p.Rating,
-		NumReviews:   p.NumReviews,
-		Price:        p.Price,
+		Options:      p.Options,
+		TaxClass:     p.TaxClass,
+		Weight:       p.Weight,
+		GiftCard:     p.GiftCard,
+		Price:        toPBMoney(p.Price),
 		CountInStock: p.CountInStock,
 	}
 }
 
 func toProductRes(p *pb.ProductRes) ProductRes {
-	return ProductRes{
-		Name:         p.Name,
-		Image:        p.Image,
-		Category:     p.Category,
-		Description:  p.Description,
-		Rating:       p.Rating,
-		NumReviews:   p.NumReviews,
-		Price:        p.Price,
-		CountInStock: p.CountInStock,
+	res := ProductRes{
+		ID:             p.Id,
+		Name:           p.Name,
+		Image:          p.Image,
+		CategoryID:     toIDPtr(p.GetCategoryId()),
+		Description:    p.Description,
+		Options:        p.GetOptions(),
+		TaxClass:       p.GetTaxClass(),
+		Weight:         p.GetWeight(),
+		GiftCard:       p.GetGiftCard(),
+		Rating:         p.Rating,
+		NumReviews:     p.NumReviews,
+		Price:          toMoney(p.Price),
+		CompareAtPrice: toMoneyPtr(p.GetCompareAtPrice()),
+		CountInStock:   p.CountInStock,
+		Variants:       make([]VariantRes, 0, len(p.GetVariants())),
+		CreatedAt:      p.GetCreatedAt().AsTime(),
+	}
+	if p.UpdatedAt != nil {
+		res.UpdatedAt = toTimePtr(p.GetUpdatedAt().AsTime())
 	}
+	for _, v := range p.GetVariants() {
+		res.Variants = append(res.Variants, toVariantRes(v))
+	}
+
+	return res
+}
+
+func toPBVariantReq(v VariantReq) *pb.VariantReq {
+	return &pb.VariantReq{
+		Sku:          v.SKU,
+		Options:      v.Options,
+		Price:        toPBMoney(v.Price),
+		InheritPrice: v.InheritPrice,
+		Image:        v.Image,
+		CountInStock: v.CountInStock,
+	}
+}
+
+func toVariantRes(v *pb.VariantRes) VariantRes {
+	res := VariantRes{
+		ID:             v.GetId(),
+		ProductID:      v.GetProductId(),
+		SKU:            v.GetSku(),
+		Options:        v.GetOptions(),
+		Price:          toMoney(v.GetPrice()),
+		CompareAtPrice: toMoneyPtr(v.GetCompareAtPrice()),
+		Image:          v.GetImage(),
+		CountInStock:   v.GetCountInStock(),
+		CreatedAt:      v.GetCreatedAt().AsTime(),
+	}
+	if v.UpdatedAt != nil {
+		res.UpdatedAt = toTimePtr(v.GetUpdatedAt().AsTime())
+	}
+
+	return res
+}
+
+func toPBScheduledPriceReq(sp ScheduledPriceReq) *pb.ScheduledPriceReq {
+	res := &pb.ScheduledPriceReq{
+		VariantId:   sp.VariantID,
+		Price:       toPBMoney(sp.Price),
+		QuantityCap: sp.QuantityCap,
+	}
+	if sp.StartsAt != nil {
+		res.StartsAt = timestamppb.New(*sp.StartsAt)
+	}
+	if sp.EndsAt != nil {
+		res.EndsAt = timestamppb.New(*sp.EndsAt)
+	}
+
+	return res
+}
+
+func toScheduledPriceRes(sp *pb.ScheduledPriceRes) ScheduledPriceRes {
+	res := ScheduledPriceRes{
+		ID:           sp.GetId(),
+		ProductID:    sp.GetProductId(),
+		VariantID:    toIDPtr(sp.GetVariantId()),
+		Price:        toMoney(sp.GetPrice()),
+		StartsAt:     sp.GetStartsAt().AsTime(),
+		EndsAt:       sp.GetEndsAt().AsTime(),
+		QuantitySold: sp.GetQuantitySold(),
+		Running:      sp.GetRunning(),
+		CreatedAt:    sp.GetCreatedAt().AsTime(),
+	}
+	if limit := sp.GetQuantityCap(); limit != 0 {
+		res.QuantityCap = &limit
+	}
+	if sp.UpdatedAt != nil {
+		res.UpdatedAt = toTimePtr(sp.GetUpdatedAt().AsTime())
+	}
+
+	return res
+}
+
+func toWarehouseRes(w *pb.WarehouseRes) WarehouseRes {
+	res := WarehouseRes{
+		ID:        w.GetId(),
+		Code:      w.GetCode(),
+		Name:      w.GetName(),
+		CreatedAt: w.GetCreatedAt().AsTime(),
+	}
+	if w.UpdatedAt != nil {
+		res.UpdatedAt = toTimePtr(w.GetUpdatedAt().AsTime())
+	}
+
+	return res
+}
+
+func toStockRes(s *pb.StockRes) StockRes {
+	res := StockRes{
+		ProductID:    s.GetProductId(),
+		CountInStock: s.GetCountInStock(),
+		Levels:       make([]StockLevel, 0, len(s.GetLevels())),
+	}
+	for _, l := range s.GetLevels() {
+		res.Levels = append(res.Levels, StockLevel{
+			WarehouseID:   l.GetWarehouseId(),
+			WarehouseCode: l.GetWarehouseCode(),
+			VariantID:     toIDPtr(l.GetVariantId()),
+			SKU:           l.GetSku(),
+			Quantity:      l.GetQuantity(),
+		})
+	}
+
+	return res
+}
+
+func toListStockMovementsRes(lm *pb.ListStockMovementsRes) ListStockMovementsRes {
+	res := ListStockMovementsRes{
+		Movements:    make([]StockMovement, 0, len(lm.GetMovements())),
+		NextBeforeID: lm.GetNextBeforeId(),
+	}
+	for _, m := range lm.GetMovements() {
+		res.Movements = append(res.Movements, StockMovement{
+			ID:               m.GetId(),
+			WarehouseID:      m.GetWarehouseId(),
+			VariantID:        toIDPtr(m.GetVariantId()),
+			Kind:             m.GetKind(),
+			Quantity:         m.GetQuantity(),
+			Reason:           m.GetReason(),
+			ActorID:          toIDPtr(m.GetActorId()),
+			OrderID:          toIDPtr(m.GetOrderId()),
+			OtherWarehouseID: toIDPtr(m.GetOtherWarehouseId()),
+			CreatedAt:        m.GetCreatedAt().AsTime(),
+		})
+	}
+
+	return res
+}
+
+// toIDPtr maps the zero id used on the wire for "none" to null.
+func toIDPtr(id int64) *int64 {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
+
+func fromIDPtr(id *int64) int64 {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
+
+func toPBCategoryReq(c CategoryReq) *pb.CategoryReq {
+	return &pb.CategoryReq{
+		Name:      c.Name,
+		Slug:      c.Slug,
+		ParentId:  c.ParentID,
+		SortOrder: c.SortOrder,
+	}
+}
+
+func toCategoryRes(c *pb.CategoryRes) CategoryRes {
+	res := CategoryRes{
+		ID:        c.GetId(),
+		ParentID:  toIDPtr(c.GetParentId()),
+		Name:      c.GetName(),
+		Slug:      c.GetSlug(),
+		SortOrder: c.GetSortOrder(),
+		CreatedAt: c.GetCreatedAt().AsTime(),
+		Children:  make([]CategoryRes, 0, len(c.GetChildren())),
+	}
+	if c.UpdatedAt != nil {
+		res.UpdatedAt = toTimePtr(c.GetUpdatedAt().AsTime())
+	}
+	for _, child := range c.GetChildren() {
+		res.Children = append(res.Children, toCategoryRes(child))
+	}
+
+	return res
 }
 
 func toPBOrderReq(o OrderReq) *pb.OrderReq {
 	return &pb.OrderReq{
-		PaymentMethod: o.PaymentMethod,
-		TaxPrice:      o.TaxPrice,
-		ShippingPrice: o.ShippingPrice,
-		TotalPrice:    o.TotalPrice,
-		Items:         toPBOrderItems(o.Items),
+		PaymentMethod:     o.PaymentMethod,
+		ShippingAddress:   toPBAddress(o.ShippingAddress),
+		ShippingAddressId: o.ShippingAddressID,
+		BillingAddress:    toPBAddress(o.BillingAddress),
+		BillingAddressId:  o.BillingAddressID,
+		ShippingMethodId:  o.ShippingMethodID,
+		CouponCode:        o.CouponCode,
+		TaxPrice:          toPBMoney(o.TaxPrice),
+		ShippingPrice:     toPBMoney(o.ShippingPrice),
+		DiscountPrice:     toPBMoney(o.DiscountPrice),
+		TotalPrice:        toPBMoney(o.TotalPrice),
+		GiftCardCodes:     o.GiftCardCodes,
+		StoreCredit:       toPBMoney(o.StoreCredit),
+		LoyaltyPoints:     o.LoyaltyPoints,
+		Items:             toPBOrderItems(o.Items),
+	}
+}
+
+func toPBAddress(a *Address) *pb.Address {
+	if a == nil {
+		return nil
+	}
+
+	return &pb.Address{
+		Name:       a.Name,
+		Line1:      a.Line1,
+		Line2:      a.Line2,
+		City:       a.City,
+		Region:     a.Region,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+	}
+}
+
+func toAddress(a *pb.Address) *Address {
+	if a == nil {
+		return nil
+	}
+
+	return &Address{
+		Name:       a.GetName(),
+		Line1:      a.GetLine1(),
+		Line2:      a.GetLine2(),
+		City:       a.GetCity(),
+		Region:     a.GetRegion(),
+		PostalCode: a.GetPostalCode(),
+		Country:    a.GetCountry(),
 	}
 }
 
@@ -51,8 +306,9 @@ func toPBOrderItems(oi []*OrderItem) []*pb.OrderItem {
 			Name:      i.Name,
 			Quantity:  i.Quantity,
 			Image:     i.Image,
-			Price:     i.Price,
+			Price:     toPBMoney(i.Price),
 			ProductId: i.ProductID,
+			VariantId: fromIDPtr(i.VariantID),
 		})
 	}
 	return res
@@ -61,50 +317,346 @@ func toPBOrderItems(oi []*OrderItem) []*pb.OrderItem {
 type OrderStatus string
 
 const (
-	Pending   OrderStatus = "pending"
-	Shipped   OrderStatus = "shipped"
-	Delivered OrderStatus = "delivered"
+	Pending    OrderStatus = "pending"
+	Paid       OrderStatus = "paid"
+	Processing OrderStatus = "processing"
+	Shipped    OrderStatus = "shipped"
+	Delivered  OrderStatus = "delivered"
+	Cancelled  OrderStatus = "cancelled"
+	Refunded   OrderStatus = "refunded"
 )
 
 func toPBOrderStatus(s OrderStatus) (pb.OrderStatus, error) {
 	switch s {
 	case Pending:
 		return pb.OrderStatus_PENDING, nil
+	case Paid:
+		return pb.OrderStatus_PAID, nil
+	case Processing:
+		return pb.OrderStatus_PROCESSING, nil
 	case Shipped:
 		return pb.OrderStatus_SHIPPED, nil
 	case Delivered:
 		return pb.OrderStatus_DELIVERED, nil
+	case Cancelled:
+		return pb.OrderStatus_CANCELLED, nil
+	case Refunded:
+		return pb.OrderStatus_REFUNDED, nil
 	default:
 		return 0, fmt.Errorf("unknown error status: %s", s)
 	}
 }
 
 func toOrderRes(o *pb.OrderRes) OrderRes {
-	return OrderRes{
-		ID:            o.Id,
-		PaymentMethod: o.PaymentMethod,
-		TaxPrice:      o.TaxPrice,
-		ShippingPrice: o.ShippingPrice,
-		TotalPrice:    o.TotalPrice,
-		Items:         toOrderItems(o.Items),
-		Status:        strings.ToLower(o.GetStatus().String()),
+	res := OrderRes{
+		ID:              o.Id,
+		PaymentMethod:   o.PaymentMethod,
+		ShippingAddress: toAddress(o.GetShippingAddress()),
+		BillingAddress:  toAddress(o.GetBillingAddress()),
+		ShippingMethod:  o.GetShippingMethod(),
+		ItemsPrice:      toMoney(o.ItemsPrice),
+		TaxPrice:        toMoney(o.TaxPrice),
+		ShippingPrice:   toMoney(o.ShippingPrice),
+		DiscountPrice:   toMoney(o.DiscountPrice),
+		Discounts:       make([]OrderDiscount, 0, len(o.GetDiscounts())),
+		CouponCode:      o.GetCouponCode(),
+		TotalPrice:      toMoney(o.TotalPrice),
+		AmountDue:       toMoneyPtr(o.GetAmountDue()),
+		Items:           toOrderItems(o.Items),
+		Status:          strings.ToLower(o.GetStatus().String()),
+		CreatedAt:       o.GetCreatedAt().AsTime(),
+	}
+	for _, d := range o.GetDiscounts() {
+		res.Discounts = append(res.Discounts, OrderDiscount{
+			PromotionID: d.GetPromotionId(),
+			Name:        d.GetName(),
+			Code:        d.GetCode(),
+			Amount:      toMoney(d.GetAmount()),
+		})
+	}
+	if o.UpdatedAt != nil {
+		res.UpdatedAt = toTimePtr(o.GetUpdatedAt().AsTime())
 	}
+
+	return res
 }
 
 func toOrderItems(oi []*pb.OrderItem) []*OrderItem {
 	var res []*OrderItem
 	for _, i := range oi {
 		res = append(res, &OrderItem{
-			Name:      i.Name,
-			Quantity:  i.Quantity,
-			Image:     i.Image,
-			Price:     i.Price,
-			ProductID: i.ProductId,
+			ID:              i.GetId(),
+			Name:            i.Name,
+			Quantity:        i.Quantity,
+			Image:           i.Image,
+			Price:           toMoneyPtr(i.Price),
+			ProductID:       i.ProductId,
+			VariantID:       toIDPtr(i.GetVariantId()),
+			SKU:             i.GetSku(),
+			LineTotal:       toMoneyPtr(i.LineTotal),
+			TaxRate:         i.GetTaxRate(),
+			TaxJurisdiction: i.GetTaxJurisdiction(),
+			TaxPrice:        toMoneyPtr(i.GetTaxPrice()),
+			TaxInclusive:    i.GetTaxInclusive(),
+		})
+	}
+	return res
+}
+
+func toReviewRes(r *pb.ReviewRes) ReviewRes {
+	res := ReviewRes{
+		ID:        r.GetId(),
+		ProductID: r.GetProductId(),
+		UserID:    r.GetUserId(),
+		Rating:    r.GetRating(),
+		Title:     r.GetTitle(),
+		Body:      r.GetBody(),
+		Hidden:    r.GetHidden(),
+		CreatedAt: r.GetCreatedAt().AsTime(),
+	}
+	if r.UpdatedAt != nil {
+		res.UpdatedAt = toTimePtr(r.GetUpdatedAt().AsTime())
+	}
+
+	return res
+}
+
+func toCartRes(c *pb.CartRes) CartRes {
+	res := CartRes{
+		Items:      make([]CartItem, 0, len(c.GetItems())),
+		ItemsPrice: toMoney(c.GetItemsPrice()),
+	}
+	for _, i := range c.GetItems() {
+		res.Items = append(res.Items, CartItem{
+			ProductID:      i.GetProductId(),
+			VariantID:      toIDPtr(i.GetVariantId()),
+			SKU:            i.GetSku(),
+			Quantity:       i.GetQuantity(),
+			Name:           i.GetName(),
+			Image:          i.GetImage(),
+			Price:          toMoneyPtr(i.GetPrice()),
+			CompareAtPrice: toMoneyPtr(i.GetCompareAtPrice()),
+			LineTotal:      toMoneyPtr(i.GetLineTotal()),
+			CountInStock:   i.GetCountInStock(),
+			Available:      i.GetAvailable(),
+		})
+	}
+	if c.UpdatedAt != nil {
+		res.UpdatedAt = toTimePtr(c.GetUpdatedAt().AsTime())
+	}
+
+	return res
+}
+
+func toWishlistRes(wl *pb.WishlistRes) WishlistRes {
+	res := WishlistRes{
+		ID:        wl.GetId(),
+		Name:      wl.GetName(),
+		Shared:    wl.GetShareToken() != "",
+		Items:     make([]WishlistItem, 0, len(wl.GetItems())),
+		CreatedAt: wl.GetCreatedAt().AsTime(),
+	}
+	if t := wl.GetShareToken(); t != "" {
+		res.SharePath = sharedWishlistPath + t
+	}
+	for _, i := range wl.GetItems() {
+		res.Items = append(res.Items, WishlistItem{
+			ProductID:      i.GetProductId(),
+			VariantID:      toIDPtr(i.GetVariantId()),
+			SKU:            i.GetSku(),
+			Name:           i.GetName(),
+			Image:          i.GetImage(),
+			Price:          toMoneyPtr(i.GetPrice()),
+			CompareAtPrice: toMoneyPtr(i.GetCompareAtPrice()),
+			CountInStock:   i.GetCountInStock(),
+			Available:      i.GetAvailable(),
+			AddedAt:        i.GetAddedAt().AsTime(),
+		})
+	}
+	if wl.UpdatedAt != nil {
+		res.UpdatedAt = toTimePtr(wl.GetUpdatedAt().AsTime())
+	}
+
+	return res
+}
+
+func toOrderStatusChanges(changes []*pb.OrderStatusChange) []OrderStatusChange {
+	res := make([]OrderStatusChange, 0, len(changes))
+	for _, c := range changes {
+		res = append(res, OrderStatusChange{
+			FromStatus: strings.ToLower(c.GetFromStatus().String()),
+			ToStatus:   strings.ToLower(c.GetToStatus().String()),
+			Reason:     c.Reason,
+			ActorID:    c.ActorId,
+			ActorRole:  c.ActorRole,
+			CreatedAt:  c.CreatedAt.AsTime(),
 		})
 	}
 	return res
 }
 
+func toPaymentRes(p *pb.PaymentRes) PaymentRes {
+	res := PaymentRes{
+		ID:             p.GetId(),
+		OrderID:        p.GetOrderId(),
+		Provider:       p.GetProvider(),
+		Reference:      p.GetReference(),
+		Status:         p.GetStatus(),
+		Amount:         toMoney(p.GetAmount()),
+		RefundedAmount: toMoney(p.GetRefundedAmount()),
+		Message:        p.GetMessage(),
+		CreatedAt:      p.GetCreatedAt().AsTime(),
+	}
+	if p.GetUpdatedAt() != nil {
+		t := p.GetUpdatedAt().AsTime()
+		res.UpdatedAt = &t
+	}
+
+	return res
+}
+
+func toPBGiftCardReq(g GiftCardReq) *pb.GiftCardReq {
+	req := &pb.GiftCardReq{
+		Code:   g.Code,
+		Amount: toPBMoney(g.Amount),
+		Note:   g.Note,
+	}
+	if g.ExpiresAt != nil {
+		req.ExpiresAt = timestamppb.New(*g.ExpiresAt)
+	}
+
+	return req
+}
+
+func toGiftCardRes(g *pb.GiftCardRes) GiftCardRes {
+	res := GiftCardRes{
+		ID:          g.GetId(),
+		Code:        g.GetCode(),
+		Balance:     toMoney(g.GetBalance()),
+		Expired:     g.GetExpired(),
+		PurchaserID: toIDPtr(g.GetPurchaserId()),
+		OrderID:     toIDPtr(g.GetOrderId()),
+		IssuedBy:    toIDPtr(g.GetIssuedBy()),
+		Note:        g.GetNote(),
+	}
+	if g.ExpiresAt != nil {
+		res.ExpiresAt = toTimePtr(g.GetExpiresAt().AsTime())
+	}
+	// only set for admins and the card's purchaser
+	if g.InitialAmount != nil {
+		res.InitialAmount = toMoneyPtr(g.GetInitialAmount())
+		res.CreatedAt = toTimePtr(g.GetCreatedAt().AsTime())
+	}
+	if g.UpdatedAt != nil {
+		res.UpdatedAt = toTimePtr(g.GetUpdatedAt().AsTime())
+	}
+	for _, t := range g.GetTransactions() {
+		res.Transactions = append(res.Transactions, BalanceTransaction{
+			ID:        t.GetId(),
+			Kind:      t.GetKind(),
+			Amount:    toMoney(t.GetAmount()),
+			OrderID:   toIDPtr(t.GetOrderId()),
+			ActorID:   toIDPtr(t.GetActorId()),
+			Reason:    t.GetReason(),
+			CreatedAt: t.GetCreatedAt().AsTime(),
+		})
+	}
+
+	return res
+}
+
+func toStoreCreditRes(sc *pb.StoreCreditRes) StoreCreditRes {
+	res := StoreCreditRes{
+		UserID:       sc.GetUserId(),
+		Balance:      toMoney(sc.GetBalance()),
+		Transactions: make([]BalanceTransaction, 0, len(sc.GetTransactions())),
+	}
+	for _, t := range sc.GetTransactions() {
+		res.Transactions = append(res.Transactions, BalanceTransaction{
+			ID:        t.GetId(),
+			Kind:      t.GetKind(),
+			Amount:    toMoney(t.GetAmount()),
+			OrderID:   toIDPtr(t.GetOrderId()),
+			ActorID:   toIDPtr(t.GetActorId()),
+			Reason:    t.GetReason(),
+			CreatedAt: t.GetCreatedAt().AsTime(),
+		})
+	}
+
+	return res
+}
+
+func toLoyaltyRes(l *pb.LoyaltyRes) LoyaltyRes {
+	res := LoyaltyRes{
+		Points:       l.GetPoints(),
+		PointsValue:  toMoney(l.GetPointsValue()),
+		Tier:         l.GetTier(),
+		YearSpend:    toMoney(l.GetYearSpend()),
+		NextTier:     l.GetNextTier(),
+		EarnRate:     l.GetEarnRate(),
+		Multiplier:   l.GetMultiplier(),
+		Transactions: make([]LoyaltyTransaction, 0, len(l.GetTransactions())),
+	}
+	if l.NextTierSpend != nil {
+		res.NextTierSpend = toMoneyPtr(l.GetNextTierSpend())
+	}
+	for _, t := range l.GetTransactions() {
+		lt := LoyaltyTransaction{
+			ID:        t.GetId(),
+			Kind:      t.GetKind(),
+			Points:    t.GetPoints(),
+			OrderID:   toIDPtr(t.GetOrderId()),
+			Reason:    t.GetReason(),
+			CreatedAt: t.GetCreatedAt().AsTime(),
+		}
+		if t.ExpiresAt != nil {
+			lt.ExpiresAt = toTimePtr(t.GetExpiresAt().AsTime())
+		}
+		res.Transactions = append(res.Transactions, lt)
+	}
+
+	return res
+}
+
+func toPaymentsRes(ps []*pb.PaymentRes) []PaymentRes {
+	res := make([]PaymentRes, 0, len(ps))
+	for _, p := range ps {
+		res = append(res, toPaymentRes(p))
+	}
+	return res
+}
+
+func toReturnRes(r *pb.ReturnRes) ReturnRes {
+	res := ReturnRes{
+		ID:           r.GetId(),
+		OrderID:      r.GetOrderId(),
+		OrderItemID:  r.GetOrderItemId(),
+		UserID:       r.GetUserId(),
+		Quantity:     r.GetQuantity(),
+		Reason:       r.GetReason(),
+		PhotosURL:    r.GetPhotosUrl(),
+		Status:       r.GetStatus(),
+		Note:         r.GetNote(),
+		Restocked:    r.GetRestocked(),
+		RefundAmount: toMoney(r.GetRefundAmount()),
+		CreatedAt:    r.GetCreatedAt().AsTime(),
+	}
+	if r.GetUpdatedAt() != nil {
+		t := r.GetUpdatedAt().AsTime()
+		res.UpdatedAt = &t
+	}
+
+	return res
+}
+
+func toReturnsRes(rs []*pb.ReturnRes) []ReturnRes {
+	res := make([]ReturnRes, 0, len(rs))
+	for _, r := range rs {
+		res = append(res, toReturnRes(r))
+	}
+	return res
+}
+
 func toPBUserReq(u UserReq) *pb.UserReq {
 	return &pb.UserReq{
 		Name:     u.Name,
@@ -116,8 +668,337 @@ func toPBUserReq(u UserReq) *pb.UserReq {
 
 func toUserRes(u *pb.UserRes) UserRes {
 	return UserRes{
-		Name:    u.Name,
-		Email:   u.Email,
-		IsAdmin: u.IsAdmin,
+		Name:      u.Name,
+		Email:     u.Email,
+		IsAdmin:   u.IsAdmin,
+		TaxExempt: u.GetTaxExempt(),
+	}
+}
+
+func toPBTaxRateReq(t TaxRateReq) *pb.TaxRateReq {
+	return &pb.TaxRateReq{
+		Country:   t.Country,
+		Region:    t.Region,
+		TaxClass:  t.TaxClass,
+		Name:      t.Name,
+		Rate:      t.Rate,
+		Inclusive: t.Inclusive,
+	}
+}
+
+func toTaxRateRes(t *pb.TaxRateRes) TaxRateRes {
+	res := TaxRateRes{
+		ID:        t.GetId(),
+		Country:   t.GetCountry(),
+		Region:    t.GetRegion(),
+		TaxClass:  t.GetTaxClass(),
+		Name:      t.GetName(),
+		Rate:      t.GetRate(),
+		Inclusive: t.GetInclusive(),
+		CreatedAt: t.GetCreatedAt().AsTime(),
+	}
+	if t.UpdatedAt != nil {
+		res.UpdatedAt = toTimePtr(t.GetUpdatedAt().AsTime())
+	}
+
+	return res
+}
+
+func toPBPromotionReq(p PromotionReq) (*pb.PromotionReq, error) {
+	t, ok := pb.PromotionType_value[strings.ToUpper(p.Type)]
+	if !ok {
+		return nil, fmt.Errorf("unknown promotion type: %s", p.Type)
+	}
+
+	res := &pb.PromotionReq{
+		Name:             p.Name,
+		Code:             p.Code,
+		Type:             pb.PromotionType(t),
+		DiscountRate:     p.DiscountRate,
+		DiscountAmount:   toPBMoney(p.DiscountAmount),
+		BuyQuantity:      p.BuyQuantity,
+		GetQuantity:      p.GetQuantity,
+		ProductId:        p.ProductID,
+		CategoryId:       p.CategoryID,
+		MinSubtotal:      toPBMoney(p.MinSubtotal),
+		UsageLimit:       p.UsageLimit,
+		PerCustomerLimit: p.PerCustomerLimit,
+		Active:           p.Active,
+	}
+	if p.StartsAt != nil {
+		res.StartsAt = timestamppb.New(*p.StartsAt)
+	}
+	if p.EndsAt != nil {
+		res.EndsAt = timestamppb.New(*p.EndsAt)
+	}
+
+	return res, nil
+}
+
+func toPromotionRes(p *pb.PromotionRes) PromotionRes {
+	res := PromotionRes{
+		ID:               p.GetId(),
+		Name:             p.GetName(),
+		Code:             p.GetCode(),
+		Type:             strings.ToLower(p.GetType().String()),
+		DiscountRate:     p.GetDiscountRate(),
+		DiscountAmount:   toMoney(p.GetDiscountAmount()),
+		BuyQuantity:      p.GetBuyQuantity(),
+		GetQuantity:      p.GetGetQuantity(),
+		ProductID:        toIDPtr(p.GetProductId()),
+		CategoryID:       toIDPtr(p.GetCategoryId()),
+		MinSubtotal:      toMoney(p.GetMinSubtotal()),
+		UsageLimit:       p.GetUsageLimit(),
+		PerCustomerLimit: p.GetPerCustomerLimit(),
+		Active:           p.GetActive(),
+		TimesUsed:        p.GetTimesUsed(),
+		CreatedAt:        p.GetCreatedAt().AsTime(),
+	}
+	if p.StartsAt != nil {
+		res.StartsAt = toTimePtr(p.GetStartsAt().AsTime())
+	}
+	if p.EndsAt != nil {
+		res.EndsAt = toTimePtr(p.GetEndsAt().AsTime())
+	}
+	if p.UpdatedAt != nil {
+		res.UpdatedAt = toTimePtr(p.GetUpdatedAt().AsTime())
+	}
+
+	return res
+}
+
+func toPBAddressReq(a AddressReq) *pb.AddressReq {
+	return &pb.AddressReq{
+		Label:     a.Label,
+		Address:   toPBAddress(&a.Address),
+		IsDefault: a.IsDefault,
+	}
+}
+
+func toAddressRes(a *pb.AddressRes) AddressRes {
+	res := AddressRes{
+		ID:        a.GetId(),
+		Label:     a.GetLabel(),
+		IsDefault: a.GetIsDefault(),
+		CreatedAt: a.GetCreatedAt().AsTime(),
+	}
+	if addr := toAddress(a.GetAddress()); addr != nil {
+		res.Address = *addr
 	}
+	if a.UpdatedAt != nil {
+		res.UpdatedAt = toTimePtr(a.GetUpdatedAt().AsTime())
+	}
+
+	return res
+}
+
+func toPBShippingZoneReq(z ShippingZoneReq) *pb.ShippingZoneReq {
+	return &pb.ShippingZoneReq{
+		Name:           z.Name,
+		Countries:      z.Countries,
+		PostalPrefixes: z.PostalPrefixes,
+	}
+}
+
+func toShippingZoneRes(z *pb.ShippingZoneRes) ShippingZoneRes {
+	res := ShippingZoneRes{
+		ID:             z.GetId(),
+		Name:           z.GetName(),
+		Countries:      z.GetCountries(),
+		PostalPrefixes: z.GetPostalPrefixes(),
+		Methods:        make([]ShippingMethodRes, 0, len(z.GetMethods())),
+		CreatedAt:      z.GetCreatedAt().AsTime(),
+	}
+	for _, m := range z.GetMethods() {
+		res.Methods = append(res.Methods, toShippingMethodRes(m))
+	}
+	if z.UpdatedAt != nil {
+		res.UpdatedAt = toTimePtr(z.GetUpdatedAt().AsTime())
+	}
+
+	return res
+}
+
+func toPBShippingMethodReq(m ShippingMethodReq) (*pb.ShippingMethodReq, error) {
+	basis := pb.ShippingBasis_SUBTOTAL
+	if m.Basis != "" {
+		b, ok := pb.ShippingBasis_value[strings.ToUpper(m.Basis)]
+		if !ok {
+			return nil, fmt.Errorf("unknown shipping basis: %s", m.Basis)
+		}
+		basis = pb.ShippingBasis(b)
+	}
+
+	res := &pb.ShippingMethodReq{
+		Name:      m.Name,
+		Basis:     basis,
+		FreeAbove: toPBMoney(m.FreeAbove),
+		Active:    m.Active,
+	}
+	for _, t := range m.Tiers {
+		res.Tiers = append(res.Tiers, &pb.ShippingTier{
+			Min:   t.Min,
+			Price: toPBMoney(&t.Price),
+		})
+	}
+
+	return res, nil
+}
+
+func toShippingMethodRes(m *pb.ShippingMethodRes) ShippingMethodRes {
+	res := ShippingMethodRes{
+		ID:        m.GetId(),
+		ZoneID:    m.GetZoneId(),
+		Name:      m.GetName(),
+		Basis:     strings.ToLower(m.GetBasis().String()),
+		Tiers:     make([]ShippingTier, 0, len(m.GetTiers())),
+		FreeAbove: toMoneyPtr(m.GetFreeAbove()),
+		Active:    m.GetActive(),
+		CreatedAt: m.GetCreatedAt().AsTime(),
+	}
+	for _, t := range m.GetTiers() {
+		res.Tiers = append(res.Tiers, ShippingTier{
+			Min:   t.GetMin(),
+			Price: toMoney(t.GetPrice()),
+		})
+	}
+	if m.UpdatedAt != nil {
+		res.UpdatedAt = toTimePtr(m.GetUpdatedAt().AsTime())
+	}
+
+	return res
+}
+
+func toShippingQuoteRes(q *pb.ShippingQuoteRes) ShippingQuoteRes {
+	res := ShippingQuoteRes{
+		Address: toAddress(q.GetAddress()),
+		Options: make([]ShippingOption, 0, len(q.GetOptions())),
+	}
+	for _, o := range q.GetOptions() {
+		res.Options = append(res.Options, ShippingOption{
+			MethodID: o.GetMethodId(),
+			Name:     o.GetName(),
+			Zone:     o.GetZone(),
+			Price:    toMoney(o.GetPrice()),
+		})
+	}
+
+	return res
+}
+
+// toInvoiceDocument lays out an invoice for rendering.
+func toInvoiceDocument(inv *pb.InvoiceRes) *invoice.Document {
+	d := &invoice.Document{
+		Title:    "Invoice",
+		Number:   inv.GetNumber(),
+		IssuedAt: inv.GetIssuedAt().AsTime(),
+		Seller:   toSellerParty(inv.GetSeller()),
+		Buyer:    toAddressParty(inv.GetBillingAddress()),
+		ShipTo:   toAddressParty(inv.GetShippingAddress()),
+		Lines:    toDocumentLines(inv.GetLines()),
+	}
+	d.Buyer.Email = inv.GetBuyerEmail()
+	if d.Buyer.Name == "" {
+		d.Buyer.Name = inv.GetBuyerName()
+	}
+	d.Totals, d.Notes = documentTotals(inv.GetLines(), inv.GetItemsPrice(), inv.GetDiscountPrice(), inv.GetShippingPrice(), inv.GetTaxPrice(), inv.GetTotalPrice())
+
+	return d
+}
+
+// toCreditNoteDocument lays out credit note n, issued against inv, for
+// rendering. The parties are those of the invoice.
+func toCreditNoteDocument(inv *pb.InvoiceRes, n *pb.CreditNote) *invoice.Document {
+	d := toInvoiceDocument(inv)
+	d.Title = "Credit note"
+	d.Number = n.GetNumber()
+	d.Reference = "Credit for invoice " + inv.GetNumber()
+	d.IssuedAt = n.GetIssuedAt().AsTime()
+	d.Lines = toDocumentLines(n.GetLines())
+	d.Totals, d.Notes = documentTotals(n.GetLines(), n.GetItemsPrice(), n.GetDiscountPrice(), n.GetShippingPrice(), n.GetTaxPrice(), n.GetTotalPrice())
+	if n.GetReason() != "" {
+		d.Notes = strings.TrimSpace("Reason: " + n.GetReason() + ". " + d.Notes)
+	}
+
+	return d
+}
+
+func toSellerParty(s *pb.Seller) invoice.Party {
+	return invoice.Party{
+		Name:    s.GetName(),
+		Address: s.GetAddress(),
+		Email:   s.GetEmail(),
+		TaxID:   s.GetTaxId(),
+	}
+}
+
+// toAddressParty prints a as a party, the name on the address being the
+// party's name.
+func toAddressParty(a *pb.Address) invoice.Party {
+	if a == nil {
+		return invoice.Party{}
+	}
+
+	p := invoice.Party{Name: a.GetName()}
+	for _, l := range []string{
+		a.GetLine1(),
+		a.GetLine2(),
+		strings.TrimSpace(strings.Join([]string{a.GetCity(), a.GetRegion(), a.GetPostalCode()}, " ")),
+		a.GetCountry(),
+	} {
+		if l != "" {
+			p.Address = append(p.Address, l)
+		}
+	}
+
+	return p
+}
+
+func toDocumentLines(ls []*pb.InvoiceLine) []invoice.Line {
+	res := make([]invoice.Line, 0, len(ls))
+	for _, l := range ls {
+		desc := l.GetDescription()
+		if l.GetSku() != "" {
+			desc += " (" + l.GetSku() + ")"
+		}
+		res = append(res, invoice.Line{
+			Description: desc,
+			Quantity:    l.GetQuantity(),
+			UnitPrice:   toMoney(l.GetUnitPrice()),
+			TaxRate:     l.GetTaxRate(),
+			Tax:         toMoney(l.GetTaxPrice()),
+			Amount:      toMoney(l.GetTotal()),
+		})
+	}
+
+	return res
+}
+
+// documentTotals returns the totals printed below lines, and a note on the
+// tax already contained in tax-inclusive prices. Such tax is part of the
+// subtotal, so the tax total only shows the tax added on top of it.
+func documentTotals(lines []*pb.InvoiceLine, items, discount, shipping, tax, total *pb.Money) ([]invoice.Total, string) {
+	included := money.New(0, total.GetCurrency())
+	for _, l := range lines {
+		if l.GetTaxInclusive() {
+			included = included.Add(toMoney(l.GetTaxPrice()))
+		}
+	}
+
+	totals := []invoice.Total{{Label: "Subtotal", Amount: toMoney(items)}}
+	if d := toMoney(discount); !d.IsZero() {
+		totals = append(totals, invoice.Total{Label: "Discount", Amount: money.New(-d.Amount, d.Currency)})
+	}
+	totals = append(totals,
+		invoice.Total{Label: "Shipping", Amount: toMoney(shipping)},
+		invoice.Total{Label: "Tax", Amount: toMoney(tax).Sub(included)},
+		invoice.Total{Label: "Total", Amount: toMoney(total)},
+	)
+
+	var note string
+	if !included.IsZero() {
+		note = fmt.Sprintf("Prices include %s %s of tax.", included, included.Currency)
+	}
+
+	return totals, note
 }
@@ -8,6 +8,10 @@ import (
 
 var r *chi.Mux
 
+// sharedWishlistPath is where shared wishlists are viewed, followed by their
+// share token.
+const sharedWishlistPath = "/wishlists/shared/"
+
 func RegisterRouters(handler *handler) *chi.Mux {
 	r = chi.NewRouter()
 	tokenMaker := handler.TokenMaker
@@ -18,22 +22,138 @@ func RegisterRouters(handler *handler) *chi.Mux {
 
 	r.Route("/products", func(r chi.Router) {
 		r.Get("/", handler.listProducts)
+		r.Get("/search", handler.searchProducts)
 		r.With(adminMiddleware).Post("/", handler.createProduct)
 
 		r.Route("/{id}", func(r chi.Router) {
 			r.Get("/", handler.getProduct)
+			r.Get("/reviews", handler.listProductReviews)
+			r.With(authMiddleware).Post("/reviews", handler.createReview)
+			r.With(adminMiddleware).Get("/reviews/all", handler.listAllProductReviews)
 
 			r.Group(func(r chi.Router) {
 				r.Use(adminMiddleware)
 				r.Patch("/", handler.updateProduct)
 				r.Delete("/", handler.deleteProduct)
+				r.Post("/variants", handler.createVariant)
+				r.Patch("/variants/{variantID}", handler.updateVariant)
+				r.Delete("/variants/{variantID}", handler.deleteVariant)
+				r.Get("/prices", handler.listScheduledPrices)
+				r.Post("/prices", handler.createScheduledPrice)
+				r.Put("/prices/{priceID}", handler.updateScheduledPrice)
+				r.Delete("/prices/{priceID}", handler.deleteScheduledPrice)
+				r.Get("/stock", handler.getProductStock)
+				r.Post("/stock/adjustments", handler.adjustStock)
+				r.Post("/stock/transfers", handler.transferStock)
+				r.Get("/stock/movements", handler.listStockMovements)
 			})
 		})
 	})
 
+	r.Route("/categories", func(r chi.Router) {
+		r.Get("/", handler.listCategories)
+		r.With(adminMiddleware).Post("/", handler.createCategory)
+
+		r.Route("/{slug}", func(r chi.Router) {
+			r.Get("/", handler.getCategory)
+			r.Get("/products", handler.listCategoryProducts)
+
+			r.Group(func(r chi.Router) {
+				r.Use(adminMiddleware)
+				r.Patch("/", handler.updateCategory)
+				r.Delete("/", handler.deleteCategory)
+			})
+		})
+	})
+
+	r.Route("/promotions", func(r chi.Router) {
+		r.Use(adminMiddleware)
+		r.Get("/", handler.listPromotions)
+		r.Post("/", handler.createPromotion)
+		r.Get("/{id}", handler.getPromotion)
+		r.Put("/{id}", handler.updatePromotion)
+		r.Delete("/{id}", handler.deletePromotion)
+	})
+
+	r.Route("/warehouses", func(r chi.Router) {
+		r.Use(adminMiddleware)
+		r.Get("/", handler.listWarehouses)
+		r.Post("/", handler.createWarehouse)
+		r.Patch("/{id}", handler.updateWarehouse)
+	})
+
+	r.Route("/tax-rates", func(r chi.Router) {
+		r.Use(adminMiddleware)
+		r.Get("/", handler.listTaxRates)
+		r.Post("/", handler.createTaxRate)
+		r.Put("/{id}", handler.updateTaxRate)
+		r.Delete("/{id}", handler.deleteTaxRate)
+	})
+
+	r.Route("/shipping", func(r chi.Router) {
+		r.With(authMiddleware).Post("/quote", handler.quoteShipping)
+
+		r.Group(func(r chi.Router) {
+			r.Use(adminMiddleware)
+			r.Get("/zones", handler.listShippingZones)
+			r.Post("/zones", handler.createShippingZone)
+			r.Put("/zones/{id}", handler.updateShippingZone)
+			r.Delete("/zones/{id}", handler.deleteShippingZone)
+			r.Post("/zones/{id}/methods", handler.createShippingMethod)
+			r.Put("/methods/{id}", handler.updateShippingMethod)
+			r.Delete("/methods/{id}", handler.deleteShippingMethod)
+		})
+	})
+
+	// signed by the provider rather than authenticated
+	r.Post("/payments/webhooks/{provider}", handler.paymentWebhook)
+
+	// anyone with the link of a shared wishlist may view it
+	r.Get(sharedWishlistPath+"{token}", handler.getSharedWishlist)
+
 	r.Group(func(r chi.Router) {
 		r.Use(authMiddleware)
-		r.Get("/myorder", handler.getOrder)
+		r.Route("/me/orders", func(r chi.Router) {
+			r.Get("/", handler.listMyOrders)
+			r.Get("/{id}", handler.getMyOrder)
+			r.Get("/{id}/invoice", handler.getOrderInvoice)
+			r.Get("/{id}/credit-notes/{number}", handler.getOrderCreditNote)
+			r.Post("/{id}/returns", handler.createReturn)
+			r.Get("/{id}/returns", handler.listOrderReturns)
+		})
+
+		r.Route("/me/wishlists", func(r chi.Router) {
+			r.Get("/", handler.listWishlists)
+			r.Post("/", handler.createWishlist)
+			r.Get("/{id}", handler.getWishlist)
+			r.Patch("/{id}", handler.updateWishlist)
+			r.Delete("/{id}", handler.deleteWishlist)
+			r.Post("/{id}/items", handler.addWishlistItem)
+			r.Delete("/{id}/items/{productID}", handler.removeWishlistItem)
+			r.Post("/{id}/items/{productID}/move-to-cart", handler.moveWishlistItemToCart)
+		})
+
+		r.Get("/me/gift-cards", handler.listMyGiftCards)
+		r.Get("/me/store-credit", handler.getMyStoreCredit)
+		r.Get("/me/loyalty", handler.getMyLoyalty)
+
+		r.Route("/gift-cards", func(r chi.Router) {
+			r.Get("/{code}", handler.getGiftCard)
+
+			r.Group(func(r chi.Router) {
+				r.Use(adminMiddleware)
+				r.Post("/", handler.createGiftCard)
+				r.Put("/{code}", handler.updateGiftCard)
+				r.Post("/{code}/adjustments", handler.adjustGiftCard)
+			})
+		})
+
+		r.Route("/me/addresses", func(r chi.Router) {
+			r.Get("/", handler.listAddresses)
+			r.Post("/", handler.createAddress)
+			r.Put("/{id}", handler.updateAddress)
+			r.Delete("/{id}", handler.deleteAddress)
+		})
 
 		r.Route("/orders", func(r chi.Router) {
 			r.Post("/", handler.createOrder)
@@ -42,8 +162,36 @@ func RegisterRouters(handler *handler) *chi.Mux {
 
 			r.Route("/{id}", func(r chi.Router) {
 				r.Delete("/", handler.deleteOrder)
+				r.Post("/cancel", handler.cancelOrder)
+				r.Get("/history", handler.listOrderStatusChanges)
+				r.Post("/pay", handler.payOrder)
+				r.Get("/payments", handler.listOrderPayments)
+				r.With(adminMiddleware).Get("/invoice", handler.getOrderInvoice)
+				r.With(adminMiddleware).Get("/credit-notes/{number}", handler.getOrderCreditNote)
+				r.With(adminMiddleware).Get("/returns", handler.listOrderReturns)
 			})
 		})
+
+		r.Route("/returns", func(r chi.Router) {
+			r.Use(adminMiddleware)
+			r.Get("/", handler.listReturns)
+			r.Patch("/{id}/status", handler.updateReturnStatus)
+		})
+
+		r.Route("/reviews/{id}", func(r chi.Router) {
+			r.Patch("/", handler.updateReview)
+			r.Delete("/", handler.deleteReview)
+			r.With(adminMiddleware).Patch("/visibility", handler.moderateReview)
+		})
+
+		r.Route("/cart", func(r chi.Router) {
+			r.Get("/", handler.getCart)
+			r.Delete("/", handler.clearCart)
+			r.Post("/checkout", handler.checkoutCart)
+			r.Post("/items", handler.addCartItem)
+			r.Patch("/items/{productID}", handler.updateCartItem)
+			r.Delete("/items/{productID}", handler.removeCartItem)
+		})
 	})
 
 	r.Route("/users", func(r chi.Router) {
@@ -55,6 +203,9 @@ func RegisterRouters(handler *handler) *chi.Mux {
 			r.Get("/", handler.listUser)
 			r.Route("/{id}", func(r chi.Router) {
 				r.Delete("/", handler.deleteUser)
+				r.Patch("/tax-exemption", handler.setUserTaxExemption)
+				r.Get("/store-credit", handler.getUserStoreCredit)
+				r.Post("/store-credit/adjustments", handler.adjustStoreCredit)
 			})
 		})
 
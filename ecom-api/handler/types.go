@@ -1,61 +1,757 @@
 package handler
 
-import "time"
+import (
+	"time"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+)
 
 type ProductReq struct {
-	ID           int64   `json:"id"`
-	Name         string  `json:"name"`
-	Image        string  `json:"image"`
-	Category     string  `json:"category"`
-	Description  string  `json:"description"`
-	Rating       int64   `json:"rating"`
-	NumReviews   int64   `json:"num_reviews"`
-	Price        float32 `json:"price"`
-	CountInStock int64   `json:"count_in_stock"`
+	ID           int64        `json:"id"`
+	Name         string       `json:"name"`
+	Image        string       `json:"image"`
+	CategoryID   int64        `json:"category_id"`
+	Description  string       `json:"description"`
+	Options      []string     `json:"options"`
+	TaxClass     string       `json:"tax_class"`
+	Weight       *int64       `json:"weight"`
+	GiftCard     *bool        `json:"gift_card"`
+	Price        *money.Money `json:"price"`
+	CountInStock int64        `json:"count_in_stock"`
 }
 
 type ProductRes struct {
-	ID           int64      `json:"id"`
-	Name         string     `json:"name"`
-	Image        string     `json:"image"`
-	Category     string     `json:"category"`
-	Description  string     `json:"description"`
-	Rating       int64      `json:"rating"`
-	NumReviews   int64      `json:"num_reviews"`
-	Price        float32    `json:"price"`
-	CountInStock int64      `json:"count_in_stock"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    *time.Time `json:"updated_at"`
+	ID             int64        `json:"id"`
+	Name           string       `json:"name"`
+	Image          string       `json:"image"`
+	CategoryID     *int64       `json:"category_id"`
+	Description    string       `json:"description"`
+	Options        []string     `json:"options"`
+	TaxClass       string       `json:"tax_class"`
+	Weight         int64        `json:"weight"`
+	GiftCard       bool         `json:"gift_card"`
+	Rating         int64        `json:"rating"`
+	NumReviews     int64        `json:"num_reviews"`
+	Price          money.Money  `json:"price"`
+	CompareAtPrice *money.Money `json:"compare_at_price,omitempty"`
+	CountInStock   int64        `json:"count_in_stock"`
+	Variants       []VariantRes `json:"variants"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      *time.Time   `json:"updated_at"`
+}
+
+// VariantReq creates or updates a variant. Options must give a value for
+// every option of the product. On update, omitted fields are left alone and
+// inherit_price drops the price override. count_in_stock is the opening
+// stock; later changes are stock adjustments.
+type VariantReq struct {
+	SKU          string            `json:"sku"`
+	Options      map[string]string `json:"options"`
+	Price        *money.Money      `json:"price"`
+	InheritPrice bool              `json:"inherit_price"`
+	Image        *string           `json:"image"`
+	CountInStock *int64            `json:"count_in_stock"`
+}
+
+// VariantRes carries the price and image the variant sells at, which are the
+// product's unless the variant overrides them.
+type VariantRes struct {
+	ID             int64             `json:"id"`
+	ProductID      int64             `json:"product_id"`
+	SKU            string            `json:"sku"`
+	Options        map[string]string `json:"options"`
+	Price          money.Money       `json:"price"`
+	CompareAtPrice *money.Money      `json:"compare_at_price,omitempty"`
+	Image          string            `json:"image"`
+	CountInStock   int64             `json:"count_in_stock"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      *time.Time        `json:"updated_at"`
+}
+
+// ScheduledPriceReq creates or replaces a price the product, or one of its
+// variants, sells at from starts_at until ends_at. A flash sale sells no
+// more than quantity_cap units at it. The lowest running price applies.
+type ScheduledPriceReq struct {
+	VariantID   int64        `json:"variant_id"`
+	Price       *money.Money `json:"price"`
+	StartsAt    *time.Time   `json:"starts_at"`
+	EndsAt      *time.Time   `json:"ends_at"`
+	QuantityCap *int64       `json:"quantity_cap"`
+}
+
+type ScheduledPriceRes struct {
+	ID           int64       `json:"id"`
+	ProductID    int64       `json:"product_id"`
+	VariantID    *int64      `json:"variant_id"`
+	Price        money.Money `json:"price"`
+	StartsAt     time.Time   `json:"starts_at"`
+	EndsAt       time.Time   `json:"ends_at"`
+	QuantityCap  *int64      `json:"quantity_cap"`
+	QuantitySold int64       `json:"quantity_sold"`
+	Running      bool        `json:"running"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    *time.Time  `json:"updated_at"`
+}
+
+type ListScheduledPricesRes struct {
+	Prices []ScheduledPriceRes `json:"prices"`
+}
+
+type WarehouseReq struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type WarehouseRes struct {
+	ID        int64      `json:"id"`
+	Code      string     `json:"code"`
+	Name      string     `json:"name"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+type ListWarehousesRes struct {
+	Warehouses []WarehouseRes `json:"warehouses"`
+}
+
+// StockAdjustmentReq records a receipt or, by default, an adjustment of the
+// stock of a product or variant. warehouse_id defaults to the main
+// warehouse.
+type StockAdjustmentReq struct {
+	VariantID   int64  `json:"variant_id"`
+	WarehouseID int64  `json:"warehouse_id"`
+	Kind        string `json:"kind"`
+	Quantity    int64  `json:"quantity"`
+	Reason      string `json:"reason"`
+}
+
+// StockTransferReq moves stock between warehouses. from_warehouse_id
+// defaults to the main warehouse.
+type StockTransferReq struct {
+	VariantID       int64  `json:"variant_id"`
+	FromWarehouseID int64  `json:"from_warehouse_id"`
+	ToWarehouseID   int64  `json:"to_warehouse_id"`
+	Quantity        int64  `json:"quantity"`
+	Reason          string `json:"reason"`
+}
+
+type StockLevel struct {
+	WarehouseID   int64  `json:"warehouse_id"`
+	WarehouseCode string `json:"warehouse_code"`
+	VariantID     *int64 `json:"variant_id"`
+	SKU           string `json:"sku,omitempty"`
+	Quantity      int64  `json:"quantity"`
+}
+
+type StockRes struct {
+	ProductID    int64        `json:"product_id"`
+	CountInStock int64        `json:"count_in_stock"`
+	Levels       []StockLevel `json:"levels"`
+}
+
+type StockMovement struct {
+	ID               int64     `json:"id"`
+	WarehouseID      int64     `json:"warehouse_id"`
+	VariantID        *int64    `json:"variant_id"`
+	Kind             string    `json:"kind"`
+	Quantity         int64     `json:"quantity"`
+	Reason           string    `json:"reason"`
+	ActorID          *int64    `json:"actor_id"`
+	OrderID          *int64    `json:"order_id"`
+	OtherWarehouseID *int64    `json:"other_warehouse_id"`
+	CreatedAt        time.Time `json:"created_at"`
 }
 
+type ListStockMovementsRes struct {
+	Movements    []StockMovement `json:"movements"`
+	NextBeforeID int64           `json:"next_before_id,omitempty"`
+}
+
+type ListProductRes struct {
+	Products   []ProductRes `json:"products"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+}
+
+type ProductSearchHit struct {
+	Product    ProductRes        `json:"product"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+type SearchProductsRes struct {
+	Hits []ProductSearchHit `json:"hits"`
+}
+
+// CategoryReq creates or updates a category. On update, omitted fields are
+// left alone and a parent_id of 0 moves the category to the top level.
+type CategoryReq struct {
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	ParentID  *int64 `json:"parent_id"`
+	SortOrder *int64 `json:"sort_order"`
+}
+
+type CategoryRes struct {
+	ID        int64         `json:"id"`
+	ParentID  *int64        `json:"parent_id"`
+	Name      string        `json:"name"`
+	Slug      string        `json:"slug"`
+	SortOrder int64         `json:"sort_order"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt *time.Time    `json:"updated_at"`
+	Children  []CategoryRes `json:"children"`
+}
+
+type ListCategoriesRes struct {
+	Categories []CategoryRes `json:"categories"`
+}
+
+// OrderReq carries the items to order. Prices are computed by the server;
+// totals sent by the client are optional and only checked against them.
+// Amounts are in minor units, e.g. {"amount": 1999, "currency": "USD"}. Tax
+// is computed from the shipping address. Addresses are either sent or
+// picked from the address book by id; without one the order ships to the
+// default address and is billed to the shipping address. Shipping is the
+// cheapest method unless shipping_method_id picks one from a shipping quote.
+// Gift cards pay what they can of the total in the order given, then up to
+// store_credit of the customer's store credit and up to loyalty_points of
+// their points what is left. On refund,
+// refund_to_store_credit refunds card payments into store credit instead.
 type OrderReq struct {
-	ID            int64        `json:"id"`
-	Items         []*OrderItem `json:"items"`
-	PaymentMethod string       `json:"payment_method"`
-	TaxPrice      float64      `json:"tax_price"`
-	ShippingPrice float64      `json:"shipping_price"`
-	TotalPrice    float64      `json:"total_price"`
-	Status        string       `json:"status"`
+	ID                int64        `json:"id"`
+	Items             []*OrderItem `json:"items"`
+	PaymentMethod     string       `json:"payment_method"`
+	ShippingAddress   *Address     `json:"shipping_address"`
+	ShippingAddressID int64        `json:"shipping_address_id"`
+	BillingAddress    *Address     `json:"billing_address"`
+	BillingAddressID  int64        `json:"billing_address_id"`
+	ShippingMethodID  int64        `json:"shipping_method_id"`
+	CouponCode        string       `json:"coupon_code"`
+	TaxPrice          *money.Money `json:"tax_price"`
+	ShippingPrice     *money.Money `json:"shipping_price"`
+	DiscountPrice     *money.Money `json:"discount_price"`
+	TotalPrice        *money.Money `json:"total_price"`
+	GiftCardCodes     []string     `json:"gift_card_codes"`
+	StoreCredit       *money.Money `json:"store_credit"`
+	LoyaltyPoints     int64        `json:"loyalty_points"`
+	Status            string       `json:"status"`
+	Reason            string       `json:"reason"`
+	// RefundToStoreCredit is only read when changing the status
+	RefundToStoreCredit bool `json:"refund_to_store_credit"`
 }
 
+// OrderRes breaks the price of an order down. TaxPrice includes the tax
+// contained in tax-inclusive item prices, which is not added to the total.
+// AmountDue is what gift cards and store credit left to pay of a new order.
 type OrderRes struct {
-	ID            int64        `json:"id"`
-	Items         []*OrderItem `json:"items"`
-	PaymentMethod string       `json:"payment_method"`
-	TaxPrice      float64      `json:"tax_price"`
-	ShippingPrice float64      `json:"shipping_price"`
-	TotalPrice    float64      `json:"total_price"`
-	Status        string       `json:"status"`
-	CreatedAt     time.Time    `json:"created_at"`
-	UpdatedAt     *time.Time   `json:"updated_at"`
+	ID              int64           `json:"id"`
+	Items           []*OrderItem    `json:"items"`
+	PaymentMethod   string          `json:"payment_method"`
+	ShippingAddress *Address        `json:"shipping_address"`
+	BillingAddress  *Address        `json:"billing_address"`
+	ShippingMethod  string          `json:"shipping_method"`
+	CouponCode      string          `json:"coupon_code,omitempty"`
+	ItemsPrice      money.Money     `json:"items_price"`
+	TaxPrice        money.Money     `json:"tax_price"`
+	ShippingPrice   money.Money     `json:"shipping_price"`
+	DiscountPrice   money.Money     `json:"discount_price"`
+	Discounts       []OrderDiscount `json:"discounts"`
+	TotalPrice      money.Money     `json:"total_price"`
+	AmountDue       *money.Money    `json:"amount_due,omitempty"`
+	Status          string          `json:"status"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       *time.Time      `json:"updated_at"`
+}
+
+// Address is a postal address. Country is an ISO 3166-1 alpha-2 code, e.g.
+// "US", and region a subdivision of it, e.g. "CA".
+type Address struct {
+	Name       string `json:"name"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+}
+
+// AddressReq saves an address to the address book, replacing it on update.
+// The first address saved is the default until another is made the default.
+type AddressReq struct {
+	Label     string  `json:"label"`
+	Address   Address `json:"address"`
+	IsDefault bool    `json:"is_default"`
+}
+
+type AddressRes struct {
+	ID        int64      `json:"id"`
+	Label     string     `json:"label"`
+	Address   Address    `json:"address"`
+	IsDefault bool       `json:"is_default"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+type ListAddressesRes struct {
+	Addresses []AddressRes `json:"addresses"`
+}
+
+// OrderDiscount is one promotion applied to an order.
+type OrderDiscount struct {
+	PromotionID int64       `json:"promotion_id"`
+	Name        string      `json:"name"`
+	Code        string      `json:"code,omitempty"`
+	Amount      money.Money `json:"amount"`
+}
+
+// PromotionReq creates or replaces a promotion. Type is one of percentage,
+// fixed_amount, free_shipping and buy_x_get_y; discount_rate is in basis
+// points, so 1500 is 15%. Promotions without a code apply automatically.
+type PromotionReq struct {
+	Name             string       `json:"name"`
+	Code             string       `json:"code"`
+	Type             string       `json:"type"`
+	DiscountRate     int64        `json:"discount_rate"`
+	DiscountAmount   *money.Money `json:"discount_amount"`
+	BuyQuantity      int64        `json:"buy_quantity"`
+	GetQuantity      int64        `json:"get_quantity"`
+	ProductID        int64        `json:"product_id"`
+	CategoryID       int64        `json:"category_id"`
+	MinSubtotal      *money.Money `json:"min_subtotal"`
+	StartsAt         *time.Time   `json:"starts_at"`
+	EndsAt           *time.Time   `json:"ends_at"`
+	UsageLimit       int64        `json:"usage_limit"`
+	PerCustomerLimit int64        `json:"per_customer_limit"`
+	Active           *bool        `json:"active"`
+}
+
+type PromotionRes struct {
+	ID               int64       `json:"id"`
+	Name             string      `json:"name"`
+	Code             string      `json:"code,omitempty"`
+	Type             string      `json:"type"`
+	DiscountRate     int64       `json:"discount_rate"`
+	DiscountAmount   money.Money `json:"discount_amount"`
+	BuyQuantity      int64       `json:"buy_quantity"`
+	GetQuantity      int64       `json:"get_quantity"`
+	ProductID        *int64      `json:"product_id"`
+	CategoryID       *int64      `json:"category_id"`
+	MinSubtotal      money.Money `json:"min_subtotal"`
+	StartsAt         *time.Time  `json:"starts_at"`
+	EndsAt           *time.Time  `json:"ends_at"`
+	UsageLimit       int64       `json:"usage_limit"`
+	PerCustomerLimit int64       `json:"per_customer_limit"`
+	Active           bool        `json:"active"`
+	TimesUsed        int64       `json:"times_used"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        *time.Time  `json:"updated_at"`
+}
+
+type ListPromotionsRes struct {
+	Promotions []PromotionRes `json:"promotions"`
+}
+
+// TaxRateReq creates or replaces a tax rate. rate is in basis points, so
+// 825 is 8.25%. Leave region empty for a rate covering the whole country,
+// and country too for one covering every destination. Inclusive rates are
+// contained in the catalog prices instead of added on top.
+type TaxRateReq struct {
+	Country   string `json:"country"`
+	Region    string `json:"region"`
+	TaxClass  string `json:"tax_class"`
+	Name      string `json:"name"`
+	Rate      int64  `json:"rate"`
+	Inclusive bool   `json:"inclusive"`
+}
+
+type TaxRateRes struct {
+	ID        int64      `json:"id"`
+	Country   string     `json:"country"`
+	Region    string     `json:"region"`
+	TaxClass  string     `json:"tax_class"`
+	Name      string     `json:"name"`
+	Rate      int64      `json:"rate"`
+	Inclusive bool       `json:"inclusive"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+type ListTaxRatesRes struct {
+	TaxRates []TaxRateRes `json:"tax_rates"`
+}
+
+// ShippingZoneReq creates or replaces a shipping zone. An address is in the
+// zone when its country is listed and, if postal prefixes are given, its
+// postal code starts with one of them. A zone without countries covers every
+// destination no other zone does.
+type ShippingZoneReq struct {
+	Name           string   `json:"name"`
+	Countries      []string `json:"countries"`
+	PostalPrefixes []string `json:"postal_prefixes"`
+}
+
+type ShippingZoneRes struct {
+	ID             int64               `json:"id"`
+	Name           string              `json:"name"`
+	Countries      []string            `json:"countries"`
+	PostalPrefixes []string            `json:"postal_prefixes"`
+	Methods        []ShippingMethodRes `json:"methods"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      *time.Time          `json:"updated_at"`
+}
+
+type ListShippingZonesRes struct {
+	Zones []ShippingZoneRes `json:"zones"`
+}
+
+// ShippingTier prices orders from min up to the next tier's min. min is in
+// minor units of the items subtotal or in grams, depending on the method's
+// basis.
+type ShippingTier struct {
+	Min   int64       `json:"min"`
+	Price money.Money `json:"price"`
 }
 
+// ShippingMethodReq creates or replaces a shipping method. Basis is subtotal
+// or weight. Orders with a subtotal of at least free_above ship for free.
+type ShippingMethodReq struct {
+	Name      string         `json:"name"`
+	Basis     string         `json:"basis"`
+	Tiers     []ShippingTier `json:"tiers"`
+	FreeAbove *money.Money   `json:"free_above"`
+	Active    *bool          `json:"active"`
+}
+
+type ShippingMethodRes struct {
+	ID        int64          `json:"id"`
+	ZoneID    int64          `json:"zone_id"`
+	Name      string         `json:"name"`
+	Basis     string         `json:"basis"`
+	Tiers     []ShippingTier `json:"tiers"`
+	FreeAbove *money.Money   `json:"free_above"`
+	Active    bool           `json:"active"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt *time.Time     `json:"updated_at"`
+}
+
+// ShippingQuoteReq names the address to quote shipping of the cart to, by
+// id or in full. Without one the default address is used.
+type ShippingQuoteReq struct {
+	AddressID int64    `json:"address_id"`
+	Address   *Address `json:"address"`
+}
+
+// ShippingOption is a way the cart can ship. Its method_id can be sent with
+// the order; it is 0 for the flat rate charged while no zones are set up.
+type ShippingOption struct {
+	MethodID int64       `json:"method_id"`
+	Name     string      `json:"name"`
+	Zone     string      `json:"zone"`
+	Price    money.Money `json:"price"`
+}
+
+type ShippingQuoteRes struct {
+	Address *Address         `json:"address"`
+	Options []ShippingOption `json:"options"`
+}
+
+type TaxExemptionReq struct {
+	TaxExempt bool `json:"tax_exempt"`
+}
+
+type ListOrderRes struct {
+	Orders     []OrderRes `json:"orders"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+type OrderStatusChange struct {
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Reason     string    `json:"reason"`
+	ActorID    int64     `json:"actor_id"`
+	ActorRole  string    `json:"actor_role"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type CancelOrderReq struct {
+	Reason string `json:"reason"`
+}
+
+// PayOrderReq pays an order. Source is what the payment provider charges,
+// e.g. a card token.
+type PayOrderReq struct {
+	Source string `json:"source"`
+}
+
+// PaymentRes is a payment. RefundedAmount is how much of Amount has been
+// refunded, e.g. for returned items.
+type PaymentRes struct {
+	ID             int64       `json:"id"`
+	OrderID        int64       `json:"order_id"`
+	Provider       string      `json:"provider"`
+	Reference      string      `json:"reference"`
+	Status         string      `json:"status"`
+	Amount         money.Money `json:"amount"`
+	RefundedAmount money.Money `json:"refunded_amount"`
+	Message        string      `json:"message,omitempty"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      *time.Time  `json:"updated_at"`
+}
+
+// ReturnReq asks to return Quantity units of an item of a delivered order.
+// PhotosURL optionally links to photos of the item.
+type ReturnReq struct {
+	OrderItemID int64  `json:"order_item_id"`
+	Quantity    int64  `json:"quantity"`
+	Reason      string `json:"reason"`
+	PhotosURL   string `json:"photos_url"`
+}
+
+// UpdateReturnStatusReq moves a return along: requested returns are
+// approved or rejected, approved ones received and received ones refunded.
+// Restock puts the units back in stock and is only allowed when receiving.
+// RefundToStoreCredit refunds card payments into store credit.
+type UpdateReturnStatusReq struct {
+	Status              string `json:"status"`
+	Note                string `json:"note"`
+	Restock             bool   `json:"restock"`
+	RefundToStoreCredit bool   `json:"refund_to_store_credit"`
+}
+
+type ReturnRes struct {
+	ID           int64       `json:"id"`
+	OrderID      int64       `json:"order_id"`
+	OrderItemID  int64       `json:"order_item_id"`
+	UserID       int64       `json:"user_id"`
+	Quantity     int64       `json:"quantity"`
+	Reason       string      `json:"reason"`
+	PhotosURL    string      `json:"photos_url,omitempty"`
+	Status       string      `json:"status"`
+	Note         string      `json:"note,omitempty"`
+	Restocked    bool        `json:"restocked"`
+	RefundAmount money.Money `json:"refund_amount"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    *time.Time  `json:"updated_at"`
+}
+
+// OrderItem names a variant for products that come in variants. The tax
+// fields are set on placed orders: the rate in basis points, where it was
+// taken from, e.g. "US-CA", and the tax of the line.
 type OrderItem struct {
-	Name      string  `json:"name"`
-	Quantity  int64   `json:"quantity"`
-	Image     string  `json:"image"`
-	Price     float64 `json:"price"`
-	ProductID int64   `json:"product_id"`
+	ID              int64        `json:"id,omitempty"`
+	Name            string       `json:"name"`
+	Quantity        int64        `json:"quantity"`
+	Image           string       `json:"image"`
+	Price           *money.Money `json:"price"`
+	ProductID       int64        `json:"product_id"`
+	VariantID       *int64       `json:"variant_id"`
+	SKU             string       `json:"sku"`
+	LineTotal       *money.Money `json:"line_total"`
+	TaxRate         int64        `json:"tax_rate,omitempty"`
+	TaxJurisdiction string       `json:"tax_jurisdiction,omitempty"`
+	TaxPrice        *money.Money `json:"tax_price,omitempty"`
+	TaxInclusive    bool         `json:"tax_inclusive,omitempty"`
+}
+
+type ReviewReq struct {
+	Rating int64  `json:"rating"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+}
+
+type ReviewRes struct {
+	ID        int64      `json:"id"`
+	ProductID int64      `json:"product_id"`
+	UserID    int64      `json:"user_id"`
+	Rating    int64      `json:"rating"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Hidden    bool       `json:"hidden"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+type ListReviewsRes struct {
+	Reviews    []ReviewRes `json:"reviews"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+type ModerateReviewReq struct {
+	Hidden bool `json:"hidden"`
+}
+
+type CartItemReq struct {
+	ProductID int64 `json:"product_id"`
+	VariantID int64 `json:"variant_id"`
+	Quantity  int64 `json:"quantity"`
+}
+
+// CartItem is priced at the current catalog price. Available is false when
+// the product or variant is gone or has fewer units in stock than the cart
+// holds.
+type CartItem struct {
+	ProductID      int64        `json:"product_id"`
+	VariantID      *int64       `json:"variant_id"`
+	SKU            string       `json:"sku"`
+	Quantity       int64        `json:"quantity"`
+	Name           string       `json:"name"`
+	Image          string       `json:"image"`
+	Price          *money.Money `json:"price"`
+	CompareAtPrice *money.Money `json:"compare_at_price,omitempty"`
+	LineTotal      *money.Money `json:"line_total"`
+	CountInStock   int64        `json:"count_in_stock"`
+	Available      bool         `json:"available"`
+}
+
+type CartRes struct {
+	Items      []CartItem  `json:"items"`
+	ItemsPrice money.Money `json:"items_price"`
+	UpdatedAt  *time.Time  `json:"updated_at"`
+}
+
+// WishlistReq creates or updates a wishlist. On update, an empty Name keeps
+// the name and a missing Shared leaves sharing as it is.
+type WishlistReq struct {
+	Name   string `json:"name"`
+	Shared *bool  `json:"shared"`
+}
+
+// WishlistItemReq adds a product or variant to a wishlist. Quantity is only
+// read when moving an item to the cart, and defaults to 1.
+type WishlistItemReq struct {
+	ProductID int64 `json:"product_id"`
+	VariantID int64 `json:"variant_id"`
+	Quantity  int64 `json:"quantity"`
+}
+
+// WishlistItem is shown at the current catalog price. Available is false
+// when the product or variant is gone or out of stock.
+type WishlistItem struct {
+	ProductID      int64        `json:"product_id"`
+	VariantID      *int64       `json:"variant_id"`
+	SKU            string       `json:"sku"`
+	Name           string       `json:"name"`
+	Image          string       `json:"image"`
+	Price          *money.Money `json:"price"`
+	CompareAtPrice *money.Money `json:"compare_at_price,omitempty"`
+	CountInStock   int64        `json:"count_in_stock"`
+	Available      bool         `json:"available"`
+	AddedAt        time.Time    `json:"added_at"`
+}
+
+// WishlistRes is a wishlist. SharePath is where anyone can view it while it
+// is shared; it is left out of shared views.
+type WishlistRes struct {
+	ID        int64          `json:"id"`
+	Name      string         `json:"name"`
+	Shared    bool           `json:"shared"`
+	SharePath string         `json:"share_path,omitempty"`
+	Items     []WishlistItem `json:"items"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt *time.Time     `json:"updated_at"`
+}
+
+type ListWishlistsRes struct {
+	Wishlists []WishlistRes `json:"wishlists"`
+}
+
+// CheckoutCartReq optionally carries the total the client showed, which is
+// checked against the server's price like OrderReq totals. Addresses and
+// the shipping method are chosen as for OrderReq, and so are the gift cards
+// store credit and loyalty points that pay for it.
+type CheckoutCartReq struct {
+	PaymentMethod     string       `json:"payment_method"`
+	ShippingAddress   *Address     `json:"shipping_address"`
+	ShippingAddressID int64        `json:"shipping_address_id"`
+	BillingAddress    *Address     `json:"billing_address"`
+	BillingAddressID  int64        `json:"billing_address_id"`
+	ShippingMethodID  int64        `json:"shipping_method_id"`
+	CouponCode        string       `json:"coupon_code"`
+	TotalPrice        *money.Money `json:"total_price"`
+	GiftCardCodes     []string     `json:"gift_card_codes"`
+	StoreCredit       *money.Money `json:"store_credit"`
+	LoyaltyPoints     int64        `json:"loyalty_points"`
+}
+
+// GiftCardReq issues a gift card worth amount, or on update replaces its
+// expiry and note. A code is generated when none is given.
+type GiftCardReq struct {
+	Code      string       `json:"code"`
+	Amount    *money.Money `json:"amount"`
+	ExpiresAt *time.Time   `json:"expires_at"`
+	Note      string       `json:"note"`
+}
+
+// BalanceAdjustmentReq adds a signed amount to a gift card or store credit
+// balance.
+type BalanceAdjustmentReq struct {
+	Amount *money.Money `json:"amount"`
+	Reason string       `json:"reason"`
+}
+
+// BalanceTransaction is a change to a gift card or store credit balance.
+type BalanceTransaction struct {
+	ID        int64       `json:"id"`
+	Kind      string      `json:"kind"`
+	Amount    money.Money `json:"amount"`
+	OrderID   *int64      `json:"order_id"`
+	ActorID   *int64      `json:"actor_id"`
+	Reason    string      `json:"reason"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// GiftCardRes is a gift card. Checking the balance of a card someone else
+// bought only returns its code, balance and expiry.
+type GiftCardRes struct {
+	ID            int64                `json:"id,omitempty"`
+	Code          string               `json:"code"`
+	InitialAmount *money.Money         `json:"initial_amount,omitempty"`
+	Balance       money.Money          `json:"balance"`
+	ExpiresAt     *time.Time           `json:"expires_at"`
+	Expired       bool                 `json:"expired"`
+	PurchaserID   *int64               `json:"purchaser_id,omitempty"`
+	OrderID       *int64               `json:"order_id,omitempty"`
+	IssuedBy      *int64               `json:"issued_by,omitempty"`
+	Note          string               `json:"note,omitempty"`
+	Transactions  []BalanceTransaction `json:"transactions,omitempty"`
+	CreatedAt     *time.Time           `json:"created_at,omitempty"`
+	UpdatedAt     *time.Time           `json:"updated_at,omitempty"`
+}
+
+type ListGiftCardsRes struct {
+	GiftCards []GiftCardRes `json:"gift_cards"`
+}
+
+type StoreCreditRes struct {
+	UserID       int64                `json:"user_id"`
+	Balance      money.Money          `json:"balance"`
+	Transactions []BalanceTransaction `json:"transactions"`
+}
+
+// LoyaltyTransaction is a change to a user's loyalty points.
+type LoyaltyTransaction struct {
+	ID        int64      `json:"id"`
+	Kind      string     `json:"kind"`
+	Points    int64      `json:"points"`
+	OrderID   *int64     `json:"order_id"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// LoyaltyRes is a user's loyalty points, what they pay at checkout, and
+// their tier, which follows what they spent over the last year. NextTier is
+// empty at the top tier.
+type LoyaltyRes struct {
+	Points        int64                `json:"points"`
+	PointsValue   money.Money          `json:"points_value"`
+	Tier          string               `json:"tier"`
+	YearSpend     money.Money          `json:"year_spend"`
+	NextTier      string               `json:"next_tier,omitempty"`
+	NextTierSpend *money.Money         `json:"next_tier_spend,omitempty"`
+	EarnRate      int64                `json:"earn_rate"`
+	Multiplier    int64                `json:"multiplier"`
+	Transactions  []LoyaltyTransaction `json:"transactions"`
 }
 
 type UserReq struct {
@@ -66,9 +762,10 @@ type UserReq struct {
 }
 
 type UserRes struct {
-	Name    string `json:"name"`
-	Email   string `json:"email"`
-	IsAdmin bool   `json:"is_admin"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	IsAdmin   bool   `json:"is_admin"`
+	TaxExempt bool   `json:"tax_exempt"`
 }
 
 type ListUserRes struct {
@@ -22,12 +22,212 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ProductSortBy int32
+
+const (
+	ProductSortBy_CREATED_AT ProductSortBy = 0
+	ProductSortBy_PRICE      ProductSortBy = 1
+	ProductSortBy_RATING     ProductSortBy = 2
+	ProductSortBy_NAME       ProductSortBy = 3
+)
+
+// Enum value maps for ProductSortBy.
+var (
+	ProductSortBy_name = map[int32]string{
+		0: "CREATED_AT",
+		1: "PRICE",
+		2: "RATING",
+		3: "NAME",
+	}
+	ProductSortBy_value = map[string]int32{
+		"CREATED_AT": 0,
+		"PRICE":      1,
+		"RATING":     2,
+		"NAME":       3,
+	}
+)
+
+func (x ProductSortBy) Enum() *ProductSortBy {
+	p := new(ProductSortBy)
+	*p = x
+	return p
+}
+
+func (x ProductSortBy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProductSortBy) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_enumTypes[0].Descriptor()
+}
+
+func (ProductSortBy) Type() protoreflect.EnumType {
+	return &file_api_proto_enumTypes[0]
+}
+
+func (x ProductSortBy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProductSortBy.Descriptor instead.
+func (ProductSortBy) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{0}
+}
+
+type SortOrder int32
+
+const (
+	SortOrder_ASC  SortOrder = 0
+	SortOrder_DESC SortOrder = 1
+)
+
+// Enum value maps for SortOrder.
+var (
+	SortOrder_name = map[int32]string{
+		0: "ASC",
+		1: "DESC",
+	}
+	SortOrder_value = map[string]int32{
+		"ASC":  0,
+		"DESC": 1,
+	}
+)
+
+func (x SortOrder) Enum() *SortOrder {
+	p := new(SortOrder)
+	*p = x
+	return p
+}
+
+func (x SortOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SortOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_enumTypes[1].Descriptor()
+}
+
+func (SortOrder) Type() protoreflect.EnumType {
+	return &file_api_proto_enumTypes[1]
+}
+
+func (x SortOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SortOrder.Descriptor instead.
+func (SortOrder) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{1}
+}
+
+type PromotionType int32
+
+const (
+	PromotionType_PERCENTAGE    PromotionType = 0
+	PromotionType_FIXED_AMOUNT  PromotionType = 1
+	PromotionType_FREE_SHIPPING PromotionType = 2
+	PromotionType_BUY_X_GET_Y   PromotionType = 3
+)
+
+// Enum value maps for PromotionType.
+var (
+	PromotionType_name = map[int32]string{
+		0: "PERCENTAGE",
+		1: "FIXED_AMOUNT",
+		2: "FREE_SHIPPING",
+		3: "BUY_X_GET_Y",
+	}
+	PromotionType_value = map[string]int32{
+		"PERCENTAGE":    0,
+		"FIXED_AMOUNT":  1,
+		"FREE_SHIPPING": 2,
+		"BUY_X_GET_Y":   3,
+	}
+)
+
+func (x PromotionType) Enum() *PromotionType {
+	p := new(PromotionType)
+	*p = x
+	return p
+}
+
+func (x PromotionType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PromotionType) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_enumTypes[2].Descriptor()
+}
+
+func (PromotionType) Type() protoreflect.EnumType {
+	return &file_api_proto_enumTypes[2]
+}
+
+func (x PromotionType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PromotionType.Descriptor instead.
+func (PromotionType) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{2}
+}
+
+type ShippingBasis int32
+
+const (
+	ShippingBasis_SUBTOTAL ShippingBasis = 0
+	ShippingBasis_WEIGHT   ShippingBasis = 1
+)
+
+// Enum value maps for ShippingBasis.
+var (
+	ShippingBasis_name = map[int32]string{
+		0: "SUBTOTAL",
+		1: "WEIGHT",
+	}
+	ShippingBasis_value = map[string]int32{
+		"SUBTOTAL": 0,
+		"WEIGHT":   1,
+	}
+)
+
+func (x ShippingBasis) Enum() *ShippingBasis {
+	p := new(ShippingBasis)
+	*p = x
+	return p
+}
+
+func (x ShippingBasis) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ShippingBasis) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_enumTypes[3].Descriptor()
+}
+
+func (ShippingBasis) Type() protoreflect.EnumType {
+	return &file_api_proto_enumTypes[3]
+}
+
+func (x ShippingBasis) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ShippingBasis.Descriptor instead.
+func (ShippingBasis) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{3}
+}
+
 type OrderStatus int32
 
 const (
-	OrderStatus_PENDING   OrderStatus = 0
-	OrderStatus_SHIPPED   OrderStatus = 1
-	OrderStatus_DELIVERED OrderStatus = 2
+	OrderStatus_PENDING    OrderStatus = 0
+	OrderStatus_SHIPPED    OrderStatus = 1
+	OrderStatus_DELIVERED  OrderStatus = 2
+	OrderStatus_CANCELLED  OrderStatus = 3
+	OrderStatus_PAID       OrderStatus = 4
+	OrderStatus_PROCESSING OrderStatus = 5
+	OrderStatus_REFUNDED   OrderStatus = 6
 )
 
 // Enum value maps for OrderStatus.
@@ -36,11 +236,19 @@ var (
 		0: "PENDING",
 		1: "SHIPPED",
 		2: "DELIVERED",
+		3: "CANCELLED",
+		4: "PAID",
+		5: "PROCESSING",
+		6: "REFUNDED",
 	}
 	OrderStatus_value = map[string]int32{
-		"PENDING":   0,
-		"SHIPPED":   1,
-		"DELIVERED": 2,
+		"PENDING":    0,
+		"SHIPPED":    1,
+		"DELIVERED":  2,
+		"CANCELLED":  3,
+		"PAID":       4,
+		"PROCESSING": 5,
+		"REFUNDED":   6,
 	}
 )
 
@@ -55,11 +263,11 @@ func (x OrderStatus) String() string {
 }
 
 func (OrderStatus) Descriptor() protoreflect.EnumDescriptor {
-	return file_api_proto_enumTypes[0].Descriptor()
+	return file_api_proto_enumTypes[4].Descriptor()
 }
 
 func (OrderStatus) Type() protoreflect.EnumType {
-	return &file_api_proto_enumTypes[0]
+	return &file_api_proto_enumTypes[4]
 }
 
 func (x OrderStatus) Number() protoreflect.EnumNumber {
@@ -68,7 +276,7 @@ func (x OrderStatus) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use OrderStatus.Descriptor instead.
 func (OrderStatus) EnumDescriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{0}
+	return file_api_proto_rawDescGZIP(), []int{4}
 }
 
 type NotificationResponseType int32
@@ -101,11 +309,11 @@ func (x NotificationResponseType) String() string {
 }
 
 func (NotificationResponseType) Descriptor() protoreflect.EnumDescriptor {
-	return file_api_proto_enumTypes[1].Descriptor()
+	return file_api_proto_enumTypes[5].Descriptor()
 }
 
 func (NotificationResponseType) Type() protoreflect.EnumType {
-	return &file_api_proto_enumTypes[1]
+	return &file_api_proto_enumTypes[5]
 }
 
 func (x NotificationResponseType) Number() protoreflect.EnumNumber {
@@ -114,27 +322,90 @@ func (x NotificationResponseType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use NotificationResponseType.Descriptor instead.
 func (NotificationResponseType) EnumDescriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{1}
+	return file_api_proto_rawDescGZIP(), []int{5}
 }
 
-type ProductReq struct {
+// Money is an exact amount in the minor unit of its currency, e.g. 1999 USD
+// cents for 19.99.
+type Money struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Image         string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
-	Category      string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
-	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
-	Rating        int64                  `protobuf:"varint,6,opt,name=rating,proto3" json:"rating,omitempty"`
-	NumReviews    int64                  `protobuf:"varint,7,opt,name=num_reviews,json=numReviews,proto3" json:"num_reviews,omitempty"`
-	Price         float32                `protobuf:"fixed32,8,opt,name=price,proto3" json:"price,omitempty"`
-	CountInStock  int64                  `protobuf:"varint,9,opt,name=count_in_stock,json=countInStock,proto3" json:"count_in_stock,omitempty"`
+	Amount        int64                  `protobuf:"varint,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	mi := &file_api_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Money) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Money) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type ProductReq struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Image       string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
+	Description string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	// the opening stock received into the main warehouse on create; stock
+	// changes afterwards go through AdjustStock
+	CountInStock int64  `protobuf:"varint,9,opt,name=count_in_stock,json=countInStock,proto3" json:"count_in_stock,omitempty"`
+	Price        *Money `protobuf:"bytes,10,opt,name=price,proto3" json:"price,omitempty"`
+	CategoryId   int64  `protobuf:"varint,11,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	// option axes such as "size" and "color" that the variants vary in
+	Options []string `protobuf:"bytes,12,rep,name=options,proto3" json:"options,omitempty"`
+	// the tax class the product is taxed as, "standard" when empty
+	TaxClass string `protobuf:"bytes,13,opt,name=tax_class,json=taxClass,proto3" json:"tax_class,omitempty"`
+	// shipping weight in grams, for shipping rates by weight
+	Weight *int64 `protobuf:"varint,14,opt,name=weight,proto3,oneof" json:"weight,omitempty"`
+	// buying a gift card product issues a gift card worth its price for
+	// every unit once the order is paid
+	GiftCard      *bool `protobuf:"varint,15,opt,name=gift_card,json=giftCard,proto3,oneof" json:"gift_card,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ProductReq) Reset() {
 	*x = ProductReq{}
-	mi := &file_api_proto_msgTypes[0]
+	mi := &file_api_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -146,7 +417,7 @@ func (x *ProductReq) String() string {
 func (*ProductReq) ProtoMessage() {}
 
 func (x *ProductReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[0]
+	mi := &file_api_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -159,7 +430,7 @@ func (x *ProductReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProductReq.ProtoReflect.Descriptor instead.
 func (*ProductReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{0}
+	return file_api_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *ProductReq) GetId() int64 {
@@ -183,80 +454,7268 @@ func (x *ProductReq) GetImage() string {
 	return ""
 }
 
-func (x *ProductReq) GetCategory() string {
+func (x *ProductReq) GetDescription() string {
 	if x != nil {
-		return x.Category
+		return x.Description
 	}
 	return ""
 }
 
-func (x *ProductReq) GetDescription() string {
+func (x *ProductReq) GetCountInStock() int64 {
 	if x != nil {
-		return x.Description
+		return x.CountInStock
+	}
+	return 0
+}
+
+func (x *ProductReq) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *ProductReq) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+func (x *ProductReq) GetOptions() []string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *ProductReq) GetTaxClass() string {
+	if x != nil {
+		return x.TaxClass
+	}
+	return ""
+}
+
+func (x *ProductReq) GetWeight() int64 {
+	if x != nil && x.Weight != nil {
+		return *x.Weight
+	}
+	return 0
+}
+
+func (x *ProductReq) GetGiftCard() bool {
+	if x != nil && x.GiftCard != nil {
+		return *x.GiftCard
+	}
+	return false
+}
+
+type ProductRes struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name         string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Image        string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
+	Description  string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	Rating       int64                  `protobuf:"varint,6,opt,name=rating,proto3" json:"rating,omitempty"`
+	NumReviews   int64                  `protobuf:"varint,7,opt,name=num_reviews,json=numReviews,proto3" json:"num_reviews,omitempty"`
+	CountInStock int64                  `protobuf:"varint,9,opt,name=count_in_stock,json=countInStock,proto3" json:"count_in_stock,omitempty"`
+	CreatedAt    *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt    *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Price        *Money                 `protobuf:"bytes,12,opt,name=price,proto3" json:"price,omitempty"`
+	// 0 when the product is uncategorized
+	CategoryId int64    `protobuf:"varint,13,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	Options    []string `protobuf:"bytes,14,rep,name=options,proto3" json:"options,omitempty"`
+	// the variant matrix, empty for products sold as a single item
+	Variants []*VariantRes `protobuf:"bytes,15,rep,name=variants,proto3" json:"variants,omitempty"`
+	TaxClass string        `protobuf:"bytes,16,opt,name=tax_class,json=taxClass,proto3" json:"tax_class,omitempty"`
+	Weight   int64         `protobuf:"varint,17,opt,name=weight,proto3" json:"weight,omitempty"`
+	GiftCard bool          `protobuf:"varint,18,opt,name=gift_card,json=giftCard,proto3" json:"gift_card,omitempty"`
+	// price is what the product sells at now; while a scheduled price is
+	// running, compare_at_price is its own price
+	CompareAtPrice *Money `protobuf:"bytes,19,opt,name=compare_at_price,json=compareAtPrice,proto3" json:"compare_at_price,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ProductRes) Reset() {
+	*x = ProductRes{}
+	mi := &file_api_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductRes) ProtoMessage() {}
+
+func (x *ProductRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductRes.ProtoReflect.Descriptor instead.
+func (*ProductRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ProductRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ProductRes) GetName() string {
+	if x != nil {
+		return x.Name
 	}
 	return ""
 }
 
-func (x *ProductReq) GetRating() int64 {
+func (x *ProductRes) GetImage() string {
 	if x != nil {
-		return x.Rating
+		return x.Image
+	}
+	return ""
+}
+
+func (x *ProductRes) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ProductRes) GetRating() int64 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *ProductRes) GetNumReviews() int64 {
+	if x != nil {
+		return x.NumReviews
+	}
+	return 0
+}
+
+func (x *ProductRes) GetCountInStock() int64 {
+	if x != nil {
+		return x.CountInStock
+	}
+	return 0
+}
+
+func (x *ProductRes) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ProductRes) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *ProductRes) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *ProductRes) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+func (x *ProductRes) GetOptions() []string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *ProductRes) GetVariants() []*VariantRes {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *ProductRes) GetTaxClass() string {
+	if x != nil {
+		return x.TaxClass
+	}
+	return ""
+}
+
+func (x *ProductRes) GetWeight() int64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *ProductRes) GetGiftCard() bool {
+	if x != nil {
+		return x.GiftCard
+	}
+	return false
+}
+
+func (x *ProductRes) GetCompareAtPrice() *Money {
+	if x != nil {
+		return x.CompareAtPrice
+	}
+	return nil
+}
+
+// VariantReq creates or updates a variant of a product. options maps every
+// option axis of the product to a value. On update, unset fields are left
+// alone and inherit_price drops the price override. count_in_stock is the
+// opening stock on create only, as for products.
+type VariantReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId     int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Sku           string                 `protobuf:"bytes,3,opt,name=sku,proto3" json:"sku,omitempty"`
+	Options       map[string]string      `protobuf:"bytes,4,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Price         *Money                 `protobuf:"bytes,5,opt,name=price,proto3" json:"price,omitempty"`
+	Image         *string                `protobuf:"bytes,6,opt,name=image,proto3,oneof" json:"image,omitempty"`
+	CountInStock  *int64                 `protobuf:"varint,7,opt,name=count_in_stock,json=countInStock,proto3,oneof" json:"count_in_stock,omitempty"`
+	InheritPrice  bool                   `protobuf:"varint,8,opt,name=inherit_price,json=inheritPrice,proto3" json:"inherit_price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VariantReq) Reset() {
+	*x = VariantReq{}
+	mi := &file_api_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VariantReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VariantReq) ProtoMessage() {}
+
+func (x *VariantReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VariantReq.ProtoReflect.Descriptor instead.
+func (*VariantReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *VariantReq) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *VariantReq) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *VariantReq) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *VariantReq) GetOptions() map[string]string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *VariantReq) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *VariantReq) GetImage() string {
+	if x != nil && x.Image != nil {
+		return *x.Image
+	}
+	return ""
+}
+
+func (x *VariantReq) GetCountInStock() int64 {
+	if x != nil && x.CountInStock != nil {
+		return *x.CountInStock
+	}
+	return 0
+}
+
+func (x *VariantReq) GetInheritPrice() bool {
+	if x != nil {
+		return x.InheritPrice
+	}
+	return false
+}
+
+// VariantRes carries the effective price and image, falling back to the
+// product's when the variant does not override them. compare_at_price is set
+// as for products.
+type VariantRes struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId      int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Sku            string                 `protobuf:"bytes,3,opt,name=sku,proto3" json:"sku,omitempty"`
+	Options        map[string]string      `protobuf:"bytes,4,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Price          *Money                 `protobuf:"bytes,5,opt,name=price,proto3" json:"price,omitempty"`
+	Image          string                 `protobuf:"bytes,6,opt,name=image,proto3" json:"image,omitempty"`
+	CountInStock   int64                  `protobuf:"varint,7,opt,name=count_in_stock,json=countInStock,proto3" json:"count_in_stock,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	CompareAtPrice *Money                 `protobuf:"bytes,10,opt,name=compare_at_price,json=compareAtPrice,proto3" json:"compare_at_price,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *VariantRes) Reset() {
+	*x = VariantRes{}
+	mi := &file_api_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VariantRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VariantRes) ProtoMessage() {}
+
+func (x *VariantRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VariantRes.ProtoReflect.Descriptor instead.
+func (*VariantRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *VariantRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *VariantRes) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *VariantRes) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *VariantRes) GetOptions() map[string]string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *VariantRes) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *VariantRes) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *VariantRes) GetCountInStock() int64 {
+	if x != nil {
+		return x.CountInStock
+	}
+	return 0
+}
+
+func (x *VariantRes) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *VariantRes) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *VariantRes) GetCompareAtPrice() *Money {
+	if x != nil {
+		return x.CompareAtPrice
+	}
+	return nil
+}
+
+// ScheduledPriceReq creates or replaces a price a product, or one of its
+// variants when variant_id is set, sells at from starts_at until ends_at.
+// A flash sale sells no more than quantity_cap units at it. The lowest of
+// the prices running at a time applies; a variant with its own price only
+// takes the prices scheduled for it.
+type ScheduledPriceReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId     int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId     int64                  `protobuf:"varint,3,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Price         *Money                 `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	StartsAt      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`
+	EndsAt        *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`
+	QuantityCap   *int64                 `protobuf:"varint,7,opt,name=quantity_cap,json=quantityCap,proto3,oneof" json:"quantity_cap,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduledPriceReq) Reset() {
+	*x = ScheduledPriceReq{}
+	mi := &file_api_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledPriceReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledPriceReq) ProtoMessage() {}
+
+func (x *ScheduledPriceReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledPriceReq.ProtoReflect.Descriptor instead.
+func (*ScheduledPriceReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ScheduledPriceReq) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ScheduledPriceReq) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *ScheduledPriceReq) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *ScheduledPriceReq) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *ScheduledPriceReq) GetStartsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartsAt
+	}
+	return nil
+}
+
+func (x *ScheduledPriceReq) GetEndsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndsAt
+	}
+	return nil
+}
+
+func (x *ScheduledPriceReq) GetQuantityCap() int64 {
+	if x != nil && x.QuantityCap != nil {
+		return *x.QuantityCap
+	}
+	return 0
+}
+
+// ScheduledPriceRes is a scheduled price. quantity_cap is 0 for no cap, and
+// quantity_sold counts the units ordered at it.
+type ScheduledPriceRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId     int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId     int64                  `protobuf:"varint,3,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Price         *Money                 `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	StartsAt      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`
+	EndsAt        *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`
+	QuantityCap   int64                  `protobuf:"varint,7,opt,name=quantity_cap,json=quantityCap,proto3" json:"quantity_cap,omitempty"`
+	QuantitySold  int64                  `protobuf:"varint,8,opt,name=quantity_sold,json=quantitySold,proto3" json:"quantity_sold,omitempty"`
+	Running       bool                   `protobuf:"varint,9,opt,name=running,proto3" json:"running,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduledPriceRes) Reset() {
+	*x = ScheduledPriceRes{}
+	mi := &file_api_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledPriceRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledPriceRes) ProtoMessage() {}
+
+func (x *ScheduledPriceRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledPriceRes.ProtoReflect.Descriptor instead.
+func (*ScheduledPriceRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ScheduledPriceRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ScheduledPriceRes) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *ScheduledPriceRes) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *ScheduledPriceRes) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *ScheduledPriceRes) GetStartsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartsAt
+	}
+	return nil
+}
+
+func (x *ScheduledPriceRes) GetEndsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndsAt
+	}
+	return nil
+}
+
+func (x *ScheduledPriceRes) GetQuantityCap() int64 {
+	if x != nil {
+		return x.QuantityCap
+	}
+	return 0
+}
+
+func (x *ScheduledPriceRes) GetQuantitySold() int64 {
+	if x != nil {
+		return x.QuantitySold
+	}
+	return 0
+}
+
+func (x *ScheduledPriceRes) GetRunning() bool {
+	if x != nil {
+		return x.Running
+	}
+	return false
+}
+
+func (x *ScheduledPriceRes) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ScheduledPriceRes) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ListScheduledPricesRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prices        []*ScheduledPriceRes   `protobuf:"bytes,1,rep,name=prices,proto3" json:"prices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScheduledPricesRes) Reset() {
+	*x = ListScheduledPricesRes{}
+	mi := &file_api_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScheduledPricesRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScheduledPricesRes) ProtoMessage() {}
+
+func (x *ListScheduledPricesRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScheduledPricesRes.ProtoReflect.Descriptor instead.
+func (*ListScheduledPricesRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListScheduledPricesRes) GetPrices() []*ScheduledPriceRes {
+	if x != nil {
+		return x.Prices
+	}
+	return nil
+}
+
+// WarehouseReq creates or updates a warehouse. On update, empty fields are
+// left alone.
+type WarehouseReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WarehouseReq) Reset() {
+	*x = WarehouseReq{}
+	mi := &file_api_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WarehouseReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WarehouseReq) ProtoMessage() {}
+
+func (x *WarehouseReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WarehouseReq.ProtoReflect.Descriptor instead.
+func (*WarehouseReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *WarehouseReq) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *WarehouseReq) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *WarehouseReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type WarehouseRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WarehouseRes) Reset() {
+	*x = WarehouseRes{}
+	mi := &file_api_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WarehouseRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WarehouseRes) ProtoMessage() {}
+
+func (x *WarehouseRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WarehouseRes.ProtoReflect.Descriptor instead.
+func (*WarehouseRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WarehouseRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *WarehouseRes) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *WarehouseRes) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WarehouseRes) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *WarehouseRes) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ListWarehousesReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWarehousesReq) Reset() {
+	*x = ListWarehousesReq{}
+	mi := &file_api_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWarehousesReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWarehousesReq) ProtoMessage() {}
+
+func (x *ListWarehousesReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWarehousesReq.ProtoReflect.Descriptor instead.
+func (*ListWarehousesReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{10}
+}
+
+type ListWarehousesRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Warehouses    []*WarehouseRes        `protobuf:"bytes,1,rep,name=warehouses,proto3" json:"warehouses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWarehousesRes) Reset() {
+	*x = ListWarehousesRes{}
+	mi := &file_api_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWarehousesRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWarehousesRes) ProtoMessage() {}
+
+func (x *ListWarehousesRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWarehousesRes.ProtoReflect.Descriptor instead.
+func (*ListWarehousesRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListWarehousesRes) GetWarehouses() []*WarehouseRes {
+	if x != nil {
+		return x.Warehouses
+	}
+	return nil
+}
+
+// StockReq looks up, adjusts or transfers the stock of a product, or of one
+// of its variants. warehouse_id defaults to the main warehouse. kind is
+// "receipt" or "adjustment", the default; quantity is signed for
+// adjustments and positive for receipts and transfers.
+type StockReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId     int64                  `protobuf:"varint,2,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	WarehouseId   int64                  `protobuf:"varint,3,opt,name=warehouse_id,json=warehouseId,proto3" json:"warehouse_id,omitempty"`
+	ToWarehouseId int64                  `protobuf:"varint,4,opt,name=to_warehouse_id,json=toWarehouseId,proto3" json:"to_warehouse_id,omitempty"`
+	Quantity      int64                  `protobuf:"varint,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Kind          string                 `protobuf:"bytes,6,opt,name=kind,proto3" json:"kind,omitempty"`
+	Reason        string                 `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`
+	ActorId       int64                  `protobuf:"varint,8,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockReq) Reset() {
+	*x = StockReq{}
+	mi := &file_api_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockReq) ProtoMessage() {}
+
+func (x *StockReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockReq.ProtoReflect.Descriptor instead.
+func (*StockReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *StockReq) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *StockReq) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *StockReq) GetWarehouseId() int64 {
+	if x != nil {
+		return x.WarehouseId
+	}
+	return 0
+}
+
+func (x *StockReq) GetToWarehouseId() int64 {
+	if x != nil {
+		return x.ToWarehouseId
+	}
+	return 0
+}
+
+func (x *StockReq) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *StockReq) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *StockReq) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *StockReq) GetActorId() int64 {
+	if x != nil {
+		return x.ActorId
+	}
+	return 0
+}
+
+type StockLevel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WarehouseId   int64                  `protobuf:"varint,1,opt,name=warehouse_id,json=warehouseId,proto3" json:"warehouse_id,omitempty"`
+	WarehouseCode string                 `protobuf:"bytes,2,opt,name=warehouse_code,json=warehouseCode,proto3" json:"warehouse_code,omitempty"`
+	VariantId     int64                  `protobuf:"varint,3,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Sku           string                 `protobuf:"bytes,4,opt,name=sku,proto3" json:"sku,omitempty"`
+	Quantity      int64                  `protobuf:"varint,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockLevel) Reset() {
+	*x = StockLevel{}
+	mi := &file_api_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockLevel) ProtoMessage() {}
+
+func (x *StockLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockLevel.ProtoReflect.Descriptor instead.
+func (*StockLevel) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StockLevel) GetWarehouseId() int64 {
+	if x != nil {
+		return x.WarehouseId
+	}
+	return 0
+}
+
+func (x *StockLevel) GetWarehouseCode() string {
+	if x != nil {
+		return x.WarehouseCode
+	}
+	return ""
+}
+
+func (x *StockLevel) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *StockLevel) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *StockLevel) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+// StockRes is the stock of a product in every warehouse that holds some.
+// count_in_stock is their sum.
+type StockRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	CountInStock  int64                  `protobuf:"varint,2,opt,name=count_in_stock,json=countInStock,proto3" json:"count_in_stock,omitempty"`
+	Levels        []*StockLevel          `protobuf:"bytes,3,rep,name=levels,proto3" json:"levels,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockRes) Reset() {
+	*x = StockRes{}
+	mi := &file_api_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockRes) ProtoMessage() {}
+
+func (x *StockRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockRes.ProtoReflect.Descriptor instead.
+func (*StockRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *StockRes) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *StockRes) GetCountInStock() int64 {
+	if x != nil {
+		return x.CountInStock
+	}
+	return 0
+}
+
+func (x *StockRes) GetLevels() []*StockLevel {
+	if x != nil {
+		return x.Levels
+	}
+	return nil
+}
+
+// ListStockMovementsReq pages through the movements of a product, newest
+// first. before_id is the next_before_id of the previous page.
+type ListStockMovementsReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	WarehouseId   int64                  `protobuf:"varint,2,opt,name=warehouse_id,json=warehouseId,proto3" json:"warehouse_id,omitempty"`
+	BeforeId      int64                  `protobuf:"varint,3,opt,name=before_id,json=beforeId,proto3" json:"before_id,omitempty"`
+	Limit         int64                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStockMovementsReq) Reset() {
+	*x = ListStockMovementsReq{}
+	mi := &file_api_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStockMovementsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStockMovementsReq) ProtoMessage() {}
+
+func (x *ListStockMovementsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStockMovementsReq.ProtoReflect.Descriptor instead.
+func (*ListStockMovementsReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListStockMovementsReq) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *ListStockMovementsReq) GetWarehouseId() int64 {
+	if x != nil {
+		return x.WarehouseId
+	}
+	return 0
+}
+
+func (x *ListStockMovementsReq) GetBeforeId() int64 {
+	if x != nil {
+		return x.BeforeId
+	}
+	return 0
+}
+
+func (x *ListStockMovementsReq) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type StockMovement struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	WarehouseId int64                  `protobuf:"varint,2,opt,name=warehouse_id,json=warehouseId,proto3" json:"warehouse_id,omitempty"`
+	ProductId   int64                  `protobuf:"varint,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId   int64                  `protobuf:"varint,4,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Kind        string                 `protobuf:"bytes,5,opt,name=kind,proto3" json:"kind,omitempty"`
+	Quantity    int64                  `protobuf:"varint,6,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Reason      string                 `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`
+	ActorId     int64                  `protobuf:"varint,8,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	OrderId     int64                  `protobuf:"varint,9,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	// the other side of a transfer
+	OtherWarehouseId int64                  `protobuf:"varint,10,opt,name=other_warehouse_id,json=otherWarehouseId,proto3" json:"other_warehouse_id,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *StockMovement) Reset() {
+	*x = StockMovement{}
+	mi := &file_api_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockMovement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockMovement) ProtoMessage() {}
+
+func (x *StockMovement) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockMovement.ProtoReflect.Descriptor instead.
+func (*StockMovement) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *StockMovement) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *StockMovement) GetWarehouseId() int64 {
+	if x != nil {
+		return x.WarehouseId
+	}
+	return 0
+}
+
+func (x *StockMovement) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *StockMovement) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *StockMovement) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *StockMovement) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *StockMovement) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *StockMovement) GetActorId() int64 {
+	if x != nil {
+		return x.ActorId
+	}
+	return 0
+}
+
+func (x *StockMovement) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *StockMovement) GetOtherWarehouseId() int64 {
+	if x != nil {
+		return x.OtherWarehouseId
+	}
+	return 0
+}
+
+func (x *StockMovement) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListStockMovementsRes struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Movements []*StockMovement       `protobuf:"bytes,1,rep,name=movements,proto3" json:"movements,omitempty"`
+	// 0 on the last page
+	NextBeforeId  int64 `protobuf:"varint,2,opt,name=next_before_id,json=nextBeforeId,proto3" json:"next_before_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStockMovementsRes) Reset() {
+	*x = ListStockMovementsRes{}
+	mi := &file_api_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStockMovementsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStockMovementsRes) ProtoMessage() {}
+
+func (x *ListStockMovementsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStockMovementsRes.ProtoReflect.Descriptor instead.
+func (*ListStockMovementsRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListStockMovementsRes) GetMovements() []*StockMovement {
+	if x != nil {
+		return x.Movements
+	}
+	return nil
+}
+
+func (x *ListStockMovementsRes) GetNextBeforeId() int64 {
+	if x != nil {
+		return x.NextBeforeId
+	}
+	return 0
+}
+
+// CategoryReq creates, updates or looks up a category. On update, unset
+// fields are left alone and a parent_id of 0 moves the category to the top
+// level.
+type CategoryReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Slug          string                 `protobuf:"bytes,2,opt,name=slug,proto3" json:"slug,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	ParentId      *int64                 `protobuf:"varint,4,opt,name=parent_id,json=parentId,proto3,oneof" json:"parent_id,omitempty"`
+	SortOrder     *int64                 `protobuf:"varint,5,opt,name=sort_order,json=sortOrder,proto3,oneof" json:"sort_order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoryReq) Reset() {
+	*x = CategoryReq{}
+	mi := &file_api_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryReq) ProtoMessage() {}
+
+func (x *CategoryReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryReq.ProtoReflect.Descriptor instead.
+func (*CategoryReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CategoryReq) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *CategoryReq) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *CategoryReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CategoryReq) GetParentId() int64 {
+	if x != nil && x.ParentId != nil {
+		return *x.ParentId
+	}
+	return 0
+}
+
+func (x *CategoryReq) GetSortOrder() int64 {
+	if x != nil && x.SortOrder != nil {
+		return *x.SortOrder
+	}
+	return 0
+}
+
+type CategoryRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ParentId      int64                  `protobuf:"varint,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Slug          string                 `protobuf:"bytes,4,opt,name=slug,proto3" json:"slug,omitempty"`
+	SortOrder     int64                  `protobuf:"varint,5,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Children      []*CategoryRes         `protobuf:"bytes,8,rep,name=children,proto3" json:"children,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoryRes) Reset() {
+	*x = CategoryRes{}
+	mi := &file_api_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryRes) ProtoMessage() {}
+
+func (x *CategoryRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryRes.ProtoReflect.Descriptor instead.
+func (*CategoryRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CategoryRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *CategoryRes) GetParentId() int64 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+func (x *CategoryRes) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CategoryRes) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *CategoryRes) GetSortOrder() int64 {
+	if x != nil {
+		return x.SortOrder
+	}
+	return 0
+}
+
+func (x *CategoryRes) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *CategoryRes) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *CategoryRes) GetChildren() []*CategoryRes {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+type ListCategoriesReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCategoriesReq) Reset() {
+	*x = ListCategoriesReq{}
+	mi := &file_api_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCategoriesReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCategoriesReq) ProtoMessage() {}
+
+func (x *ListCategoriesReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCategoriesReq.ProtoReflect.Descriptor instead.
+func (*ListCategoriesReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{20}
+}
+
+// ListCategoriesRes holds the top level categories with their descendants
+// nested in children.
+type ListCategoriesRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Categories    []*CategoryRes         `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCategoriesRes) Reset() {
+	*x = ListCategoriesRes{}
+	mi := &file_api_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCategoriesRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCategoriesRes) ProtoMessage() {}
+
+func (x *ListCategoriesRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCategoriesRes.ProtoReflect.Descriptor instead.
+func (*ListCategoriesRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListCategoriesRes) GetCategories() []*CategoryRes {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+type ListProductsReq struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Cursor    string                 `protobuf:"bytes,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit     int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	SortBy    ProductSortBy          `protobuf:"varint,3,opt,name=sort_by,json=sortBy,proto3,enum=pb.ProductSortBy" json:"sort_by,omitempty"`
+	SortOrder SortOrder              `protobuf:"varint,4,opt,name=sort_order,json=sortOrder,proto3,enum=pb.SortOrder" json:"sort_order,omitempty"`
+	MinRating *int64                 `protobuf:"varint,8,opt,name=min_rating,json=minRating,proto3,oneof" json:"min_rating,omitempty"`
+	InStock   bool                   `protobuf:"varint,9,opt,name=in_stock,json=inStock,proto3" json:"in_stock,omitempty"`
+	MinPrice  *Money                 `protobuf:"bytes,10,opt,name=min_price,json=minPrice,proto3" json:"min_price,omitempty"`
+	MaxPrice  *Money                 `protobuf:"bytes,11,opt,name=max_price,json=maxPrice,proto3" json:"max_price,omitempty"`
+	// lists products of the category and all of its descendants
+	CategorySlug  string `protobuf:"bytes,12,opt,name=category_slug,json=categorySlug,proto3" json:"category_slug,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProductsReq) Reset() {
+	*x = ListProductsReq{}
+	mi := &file_api_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProductsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductsReq) ProtoMessage() {}
+
+func (x *ListProductsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductsReq.ProtoReflect.Descriptor instead.
+func (*ListProductsReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListProductsReq) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListProductsReq) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListProductsReq) GetSortBy() ProductSortBy {
+	if x != nil {
+		return x.SortBy
+	}
+	return ProductSortBy_CREATED_AT
+}
+
+func (x *ListProductsReq) GetSortOrder() SortOrder {
+	if x != nil {
+		return x.SortOrder
+	}
+	return SortOrder_ASC
+}
+
+func (x *ListProductsReq) GetMinRating() int64 {
+	if x != nil && x.MinRating != nil {
+		return *x.MinRating
+	}
+	return 0
+}
+
+func (x *ListProductsReq) GetInStock() bool {
+	if x != nil {
+		return x.InStock
+	}
+	return false
+}
+
+func (x *ListProductsReq) GetMinPrice() *Money {
+	if x != nil {
+		return x.MinPrice
+	}
+	return nil
+}
+
+func (x *ListProductsReq) GetMaxPrice() *Money {
+	if x != nil {
+		return x.MaxPrice
+	}
+	return nil
+}
+
+func (x *ListProductsReq) GetCategorySlug() string {
+	if x != nil {
+		return x.CategorySlug
+	}
+	return ""
+}
+
+type ListProductRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Products      []*ProductRes          `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProductRes) Reset() {
+	*x = ListProductRes{}
+	mi := &file_api_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProductRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductRes) ProtoMessage() {}
+
+func (x *ListProductRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductRes.ProtoReflect.Descriptor instead.
+func (*ListProductRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListProductRes) GetProducts() []*ProductRes {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+func (x *ListProductRes) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type SearchProductsReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchProductsReq) Reset() {
+	*x = SearchProductsReq{}
+	mi := &file_api_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchProductsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchProductsReq) ProtoMessage() {}
+
+func (x *SearchProductsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchProductsReq.ProtoReflect.Descriptor instead.
+func (*SearchProductsReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SearchProductsReq) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchProductsReq) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ProductSearchHit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *ProductRes            `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	Score         float64                `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	Highlights    map[string]string      `protobuf:"bytes,3,rep,name=highlights,proto3" json:"highlights,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductSearchHit) Reset() {
+	*x = ProductSearchHit{}
+	mi := &file_api_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductSearchHit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductSearchHit) ProtoMessage() {}
+
+func (x *ProductSearchHit) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductSearchHit.ProtoReflect.Descriptor instead.
+func (*ProductSearchHit) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ProductSearchHit) GetProduct() *ProductRes {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *ProductSearchHit) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *ProductSearchHit) GetHighlights() map[string]string {
+	if x != nil {
+		return x.Highlights
+	}
+	return nil
+}
+
+// PromotionReq creates or replaces a promotion. Promotions without a code
+// apply automatically. discount_rate is in basis points (1000 is 10%). A
+// promotion is scoped to product_id or category_id when set. Zero limits and
+// an unset min_subtotal, starts_at or ends_at mean no limit. A new promotion
+// is active unless active is false.
+type PromotionReq struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name             string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Code             string                 `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"`
+	Type             PromotionType          `protobuf:"varint,4,opt,name=type,proto3,enum=pb.PromotionType" json:"type,omitempty"`
+	DiscountRate     int64                  `protobuf:"varint,5,opt,name=discount_rate,json=discountRate,proto3" json:"discount_rate,omitempty"`
+	DiscountAmount   *Money                 `protobuf:"bytes,6,opt,name=discount_amount,json=discountAmount,proto3" json:"discount_amount,omitempty"`
+	BuyQuantity      int64                  `protobuf:"varint,7,opt,name=buy_quantity,json=buyQuantity,proto3" json:"buy_quantity,omitempty"`
+	GetQuantity      int64                  `protobuf:"varint,8,opt,name=get_quantity,json=getQuantity,proto3" json:"get_quantity,omitempty"`
+	ProductId        int64                  `protobuf:"varint,9,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	CategoryId       int64                  `protobuf:"varint,10,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	MinSubtotal      *Money                 `protobuf:"bytes,11,opt,name=min_subtotal,json=minSubtotal,proto3" json:"min_subtotal,omitempty"`
+	StartsAt         *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`
+	EndsAt           *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`
+	UsageLimit       int64                  `protobuf:"varint,14,opt,name=usage_limit,json=usageLimit,proto3" json:"usage_limit,omitempty"`
+	PerCustomerLimit int64                  `protobuf:"varint,15,opt,name=per_customer_limit,json=perCustomerLimit,proto3" json:"per_customer_limit,omitempty"`
+	Active           *bool                  `protobuf:"varint,16,opt,name=active,proto3,oneof" json:"active,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *PromotionReq) Reset() {
+	*x = PromotionReq{}
+	mi := &file_api_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PromotionReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PromotionReq) ProtoMessage() {}
+
+func (x *PromotionReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PromotionReq.ProtoReflect.Descriptor instead.
+func (*PromotionReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *PromotionReq) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PromotionReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PromotionReq) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *PromotionReq) GetType() PromotionType {
+	if x != nil {
+		return x.Type
+	}
+	return PromotionType_PERCENTAGE
+}
+
+func (x *PromotionReq) GetDiscountRate() int64 {
+	if x != nil {
+		return x.DiscountRate
+	}
+	return 0
+}
+
+func (x *PromotionReq) GetDiscountAmount() *Money {
+	if x != nil {
+		return x.DiscountAmount
+	}
+	return nil
+}
+
+func (x *PromotionReq) GetBuyQuantity() int64 {
+	if x != nil {
+		return x.BuyQuantity
+	}
+	return 0
+}
+
+func (x *PromotionReq) GetGetQuantity() int64 {
+	if x != nil {
+		return x.GetQuantity
+	}
+	return 0
+}
+
+func (x *PromotionReq) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *PromotionReq) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+func (x *PromotionReq) GetMinSubtotal() *Money {
+	if x != nil {
+		return x.MinSubtotal
+	}
+	return nil
+}
+
+func (x *PromotionReq) GetStartsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartsAt
+	}
+	return nil
+}
+
+func (x *PromotionReq) GetEndsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndsAt
+	}
+	return nil
+}
+
+func (x *PromotionReq) GetUsageLimit() int64 {
+	if x != nil {
+		return x.UsageLimit
+	}
+	return 0
+}
+
+func (x *PromotionReq) GetPerCustomerLimit() int64 {
+	if x != nil {
+		return x.PerCustomerLimit
+	}
+	return 0
+}
+
+func (x *PromotionReq) GetActive() bool {
+	if x != nil && x.Active != nil {
+		return *x.Active
+	}
+	return false
+}
+
+type PromotionRes struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name             string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Code             string                 `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"`
+	Type             PromotionType          `protobuf:"varint,4,opt,name=type,proto3,enum=pb.PromotionType" json:"type,omitempty"`
+	DiscountRate     int64                  `protobuf:"varint,5,opt,name=discount_rate,json=discountRate,proto3" json:"discount_rate,omitempty"`
+	DiscountAmount   *Money                 `protobuf:"bytes,6,opt,name=discount_amount,json=discountAmount,proto3" json:"discount_amount,omitempty"`
+	BuyQuantity      int64                  `protobuf:"varint,7,opt,name=buy_quantity,json=buyQuantity,proto3" json:"buy_quantity,omitempty"`
+	GetQuantity      int64                  `protobuf:"varint,8,opt,name=get_quantity,json=getQuantity,proto3" json:"get_quantity,omitempty"`
+	ProductId        int64                  `protobuf:"varint,9,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	CategoryId       int64                  `protobuf:"varint,10,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	MinSubtotal      *Money                 `protobuf:"bytes,11,opt,name=min_subtotal,json=minSubtotal,proto3" json:"min_subtotal,omitempty"`
+	StartsAt         *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`
+	EndsAt           *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`
+	UsageLimit       int64                  `protobuf:"varint,14,opt,name=usage_limit,json=usageLimit,proto3" json:"usage_limit,omitempty"`
+	PerCustomerLimit int64                  `protobuf:"varint,15,opt,name=per_customer_limit,json=perCustomerLimit,proto3" json:"per_customer_limit,omitempty"`
+	Active           bool                   `protobuf:"varint,16,opt,name=active,proto3" json:"active,omitempty"`
+	TimesUsed        int64                  `protobuf:"varint,17,opt,name=times_used,json=timesUsed,proto3" json:"times_used,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt        *timestamppb.Timestamp `protobuf:"bytes,19,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *PromotionRes) Reset() {
+	*x = PromotionRes{}
+	mi := &file_api_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PromotionRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PromotionRes) ProtoMessage() {}
+
+func (x *PromotionRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PromotionRes.ProtoReflect.Descriptor instead.
+func (*PromotionRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *PromotionRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PromotionRes) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PromotionRes) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *PromotionRes) GetType() PromotionType {
+	if x != nil {
+		return x.Type
+	}
+	return PromotionType_PERCENTAGE
+}
+
+func (x *PromotionRes) GetDiscountRate() int64 {
+	if x != nil {
+		return x.DiscountRate
+	}
+	return 0
+}
+
+func (x *PromotionRes) GetDiscountAmount() *Money {
+	if x != nil {
+		return x.DiscountAmount
+	}
+	return nil
+}
+
+func (x *PromotionRes) GetBuyQuantity() int64 {
+	if x != nil {
+		return x.BuyQuantity
+	}
+	return 0
+}
+
+func (x *PromotionRes) GetGetQuantity() int64 {
+	if x != nil {
+		return x.GetQuantity
+	}
+	return 0
+}
+
+func (x *PromotionRes) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *PromotionRes) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+func (x *PromotionRes) GetMinSubtotal() *Money {
+	if x != nil {
+		return x.MinSubtotal
+	}
+	return nil
+}
+
+func (x *PromotionRes) GetStartsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartsAt
+	}
+	return nil
+}
+
+func (x *PromotionRes) GetEndsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndsAt
+	}
+	return nil
+}
+
+func (x *PromotionRes) GetUsageLimit() int64 {
+	if x != nil {
+		return x.UsageLimit
+	}
+	return 0
+}
+
+func (x *PromotionRes) GetPerCustomerLimit() int64 {
+	if x != nil {
+		return x.PerCustomerLimit
+	}
+	return 0
+}
+
+func (x *PromotionRes) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *PromotionRes) GetTimesUsed() int64 {
+	if x != nil {
+		return x.TimesUsed
+	}
+	return 0
+}
+
+func (x *PromotionRes) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *PromotionRes) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ListPromotionsReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPromotionsReq) Reset() {
+	*x = ListPromotionsReq{}
+	mi := &file_api_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPromotionsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPromotionsReq) ProtoMessage() {}
+
+func (x *ListPromotionsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPromotionsReq.ProtoReflect.Descriptor instead.
+func (*ListPromotionsReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{28}
+}
+
+type ListPromotionsRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Promotions    []*PromotionRes        `protobuf:"bytes,1,rep,name=promotions,proto3" json:"promotions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPromotionsRes) Reset() {
+	*x = ListPromotionsRes{}
+	mi := &file_api_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPromotionsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPromotionsRes) ProtoMessage() {}
+
+func (x *ListPromotionsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPromotionsRes.ProtoReflect.Descriptor instead.
+func (*ListPromotionsRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListPromotionsRes) GetPromotions() []*PromotionRes {
+	if x != nil {
+		return x.Promotions
+	}
+	return nil
+}
+
+// TaxRateReq creates or replaces a tax rate. rate is in basis points (2000
+// is 20%). An empty region covers the whole country and an empty country
+// every destination; inclusive rates are contained in catalog prices.
+type TaxRateReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Country       string                 `protobuf:"bytes,2,opt,name=country,proto3" json:"country,omitempty"`
+	Region        string                 `protobuf:"bytes,3,opt,name=region,proto3" json:"region,omitempty"`
+	TaxClass      string                 `protobuf:"bytes,4,opt,name=tax_class,json=taxClass,proto3" json:"tax_class,omitempty"`
+	Name          string                 `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	Rate          int64                  `protobuf:"varint,6,opt,name=rate,proto3" json:"rate,omitempty"`
+	Inclusive     bool                   `protobuf:"varint,7,opt,name=inclusive,proto3" json:"inclusive,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaxRateReq) Reset() {
+	*x = TaxRateReq{}
+	mi := &file_api_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaxRateReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaxRateReq) ProtoMessage() {}
+
+func (x *TaxRateReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaxRateReq.ProtoReflect.Descriptor instead.
+func (*TaxRateReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *TaxRateReq) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TaxRateReq) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *TaxRateReq) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *TaxRateReq) GetTaxClass() string {
+	if x != nil {
+		return x.TaxClass
+	}
+	return ""
+}
+
+func (x *TaxRateReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TaxRateReq) GetRate() int64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *TaxRateReq) GetInclusive() bool {
+	if x != nil {
+		return x.Inclusive
+	}
+	return false
+}
+
+type TaxRateRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Country       string                 `protobuf:"bytes,2,opt,name=country,proto3" json:"country,omitempty"`
+	Region        string                 `protobuf:"bytes,3,opt,name=region,proto3" json:"region,omitempty"`
+	TaxClass      string                 `protobuf:"bytes,4,opt,name=tax_class,json=taxClass,proto3" json:"tax_class,omitempty"`
+	Name          string                 `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	Rate          int64                  `protobuf:"varint,6,opt,name=rate,proto3" json:"rate,omitempty"`
+	Inclusive     bool                   `protobuf:"varint,7,opt,name=inclusive,proto3" json:"inclusive,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaxRateRes) Reset() {
+	*x = TaxRateRes{}
+	mi := &file_api_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaxRateRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaxRateRes) ProtoMessage() {}
+
+func (x *TaxRateRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaxRateRes.ProtoReflect.Descriptor instead.
+func (*TaxRateRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *TaxRateRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TaxRateRes) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *TaxRateRes) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *TaxRateRes) GetTaxClass() string {
+	if x != nil {
+		return x.TaxClass
+	}
+	return ""
+}
+
+func (x *TaxRateRes) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TaxRateRes) GetRate() int64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *TaxRateRes) GetInclusive() bool {
+	if x != nil {
+		return x.Inclusive
+	}
+	return false
+}
+
+func (x *TaxRateRes) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *TaxRateRes) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ListTaxRatesReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTaxRatesReq) Reset() {
+	*x = ListTaxRatesReq{}
+	mi := &file_api_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTaxRatesReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTaxRatesReq) ProtoMessage() {}
+
+func (x *ListTaxRatesReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTaxRatesReq.ProtoReflect.Descriptor instead.
+func (*ListTaxRatesReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{32}
+}
+
+type ListTaxRatesRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaxRates      []*TaxRateRes          `protobuf:"bytes,1,rep,name=tax_rates,json=taxRates,proto3" json:"tax_rates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTaxRatesRes) Reset() {
+	*x = ListTaxRatesRes{}
+	mi := &file_api_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTaxRatesRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTaxRatesRes) ProtoMessage() {}
+
+func (x *ListTaxRatesRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTaxRatesRes.ProtoReflect.Descriptor instead.
+func (*ListTaxRatesRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ListTaxRatesRes) GetTaxRates() []*TaxRateRes {
+	if x != nil {
+		return x.TaxRates
+	}
+	return nil
+}
+
+// AddressReq saves an address in a user's address book. The first address
+// saved becomes the default, which orders ship to when they name none.
+type AddressReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Label         string                 `protobuf:"bytes,3,opt,name=label,proto3" json:"label,omitempty"`
+	Address       *Address               `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`
+	IsDefault     bool                   `protobuf:"varint,5,opt,name=is_default,json=isDefault,proto3" json:"is_default,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddressReq) Reset() {
+	*x = AddressReq{}
+	mi := &file_api_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddressReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddressReq) ProtoMessage() {}
+
+func (x *AddressReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddressReq.ProtoReflect.Descriptor instead.
+func (*AddressReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *AddressReq) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AddressReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *AddressReq) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *AddressReq) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *AddressReq) GetIsDefault() bool {
+	if x != nil {
+		return x.IsDefault
+	}
+	return false
+}
+
+type AddressRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Label         string                 `protobuf:"bytes,3,opt,name=label,proto3" json:"label,omitempty"`
+	Address       *Address               `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`
+	IsDefault     bool                   `protobuf:"varint,5,opt,name=is_default,json=isDefault,proto3" json:"is_default,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddressRes) Reset() {
+	*x = AddressRes{}
+	mi := &file_api_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddressRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddressRes) ProtoMessage() {}
+
+func (x *AddressRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddressRes.ProtoReflect.Descriptor instead.
+func (*AddressRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *AddressRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AddressRes) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *AddressRes) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *AddressRes) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *AddressRes) GetIsDefault() bool {
+	if x != nil {
+		return x.IsDefault
+	}
+	return false
+}
+
+func (x *AddressRes) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *AddressRes) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ListAddressesRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Addresses     []*AddressRes          `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAddressesRes) Reset() {
+	*x = ListAddressesRes{}
+	mi := &file_api_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAddressesRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAddressesRes) ProtoMessage() {}
+
+func (x *ListAddressesRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAddressesRes.ProtoReflect.Descriptor instead.
+func (*ListAddressesRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ListAddressesRes) GetAddresses() []*AddressRes {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+// ShippingZoneReq creates or replaces a shipping zone. An address is in a
+// zone when its country is one of countries and, if the zone has postal
+// prefixes, its postal code starts with one of them. A zone without
+// countries covers every destination no other zone does.
+type ShippingZoneReq struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Countries      []string               `protobuf:"bytes,3,rep,name=countries,proto3" json:"countries,omitempty"`
+	PostalPrefixes []string               `protobuf:"bytes,4,rep,name=postal_prefixes,json=postalPrefixes,proto3" json:"postal_prefixes,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ShippingZoneReq) Reset() {
+	*x = ShippingZoneReq{}
+	mi := &file_api_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShippingZoneReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShippingZoneReq) ProtoMessage() {}
+
+func (x *ShippingZoneReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShippingZoneReq.ProtoReflect.Descriptor instead.
+func (*ShippingZoneReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ShippingZoneReq) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ShippingZoneReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ShippingZoneReq) GetCountries() []string {
+	if x != nil {
+		return x.Countries
+	}
+	return nil
+}
+
+func (x *ShippingZoneReq) GetPostalPrefixes() []string {
+	if x != nil {
+		return x.PostalPrefixes
+	}
+	return nil
+}
+
+type ShippingZoneRes struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Countries      []string               `protobuf:"bytes,3,rep,name=countries,proto3" json:"countries,omitempty"`
+	PostalPrefixes []string               `protobuf:"bytes,4,rep,name=postal_prefixes,json=postalPrefixes,proto3" json:"postal_prefixes,omitempty"`
+	Methods        []*ShippingMethodRes   `protobuf:"bytes,5,rep,name=methods,proto3" json:"methods,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ShippingZoneRes) Reset() {
+	*x = ShippingZoneRes{}
+	mi := &file_api_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShippingZoneRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShippingZoneRes) ProtoMessage() {}
+
+func (x *ShippingZoneRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShippingZoneRes.ProtoReflect.Descriptor instead.
+func (*ShippingZoneRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ShippingZoneRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ShippingZoneRes) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ShippingZoneRes) GetCountries() []string {
+	if x != nil {
+		return x.Countries
+	}
+	return nil
+}
+
+func (x *ShippingZoneRes) GetPostalPrefixes() []string {
+	if x != nil {
+		return x.PostalPrefixes
+	}
+	return nil
+}
+
+func (x *ShippingZoneRes) GetMethods() []*ShippingMethodRes {
+	if x != nil {
+		return x.Methods
+	}
+	return nil
+}
+
+func (x *ShippingZoneRes) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ShippingZoneRes) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ListShippingZonesReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListShippingZonesReq) Reset() {
+	*x = ListShippingZonesReq{}
+	mi := &file_api_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListShippingZonesReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListShippingZonesReq) ProtoMessage() {}
+
+func (x *ListShippingZonesReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListShippingZonesReq.ProtoReflect.Descriptor instead.
+func (*ListShippingZonesReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{39}
+}
+
+type ListShippingZonesRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Zones         []*ShippingZoneRes     `protobuf:"bytes,1,rep,name=zones,proto3" json:"zones,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListShippingZonesRes) Reset() {
+	*x = ListShippingZonesRes{}
+	mi := &file_api_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListShippingZonesRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListShippingZonesRes) ProtoMessage() {}
+
+func (x *ListShippingZonesRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListShippingZonesRes.ProtoReflect.Descriptor instead.
+func (*ListShippingZonesRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ListShippingZonesRes) GetZones() []*ShippingZoneRes {
+	if x != nil {
+		return x.Zones
+	}
+	return nil
+}
+
+// ShippingTier prices orders from min upwards, min being in minor units of
+// the subtotal or in grams depending on the method's basis.
+type ShippingTier struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Min           int64                  `protobuf:"varint,1,opt,name=min,proto3" json:"min,omitempty"`
+	Price         *Money                 `protobuf:"bytes,2,opt,name=price,proto3" json:"price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShippingTier) Reset() {
+	*x = ShippingTier{}
+	mi := &file_api_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShippingTier) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShippingTier) ProtoMessage() {}
+
+func (x *ShippingTier) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShippingTier.ProtoReflect.Descriptor instead.
+func (*ShippingTier) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ShippingTier) GetMin() int64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *ShippingTier) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+// ShippingMethodReq creates or replaces a shipping method of a zone. An
+// order is charged the price of the highest tier it reaches and cannot use
+// the method below the first one. Orders with a subtotal of at least
+// free_above ship for free.
+type ShippingMethodReq struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ZoneId    int64                  `protobuf:"varint,2,opt,name=zone_id,json=zoneId,proto3" json:"zone_id,omitempty"`
+	Name      string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Basis     ShippingBasis          `protobuf:"varint,4,opt,name=basis,proto3,enum=pb.ShippingBasis" json:"basis,omitempty"`
+	Tiers     []*ShippingTier        `protobuf:"bytes,5,rep,name=tiers,proto3" json:"tiers,omitempty"`
+	FreeAbove *Money                 `protobuf:"bytes,6,opt,name=free_above,json=freeAbove,proto3" json:"free_above,omitempty"`
+	// defaults to true
+	Active        *bool `protobuf:"varint,7,opt,name=active,proto3,oneof" json:"active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShippingMethodReq) Reset() {
+	*x = ShippingMethodReq{}
+	mi := &file_api_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShippingMethodReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShippingMethodReq) ProtoMessage() {}
+
+func (x *ShippingMethodReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShippingMethodReq.ProtoReflect.Descriptor instead.
+func (*ShippingMethodReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ShippingMethodReq) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ShippingMethodReq) GetZoneId() int64 {
+	if x != nil {
+		return x.ZoneId
+	}
+	return 0
+}
+
+func (x *ShippingMethodReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ShippingMethodReq) GetBasis() ShippingBasis {
+	if x != nil {
+		return x.Basis
+	}
+	return ShippingBasis_SUBTOTAL
+}
+
+func (x *ShippingMethodReq) GetTiers() []*ShippingTier {
+	if x != nil {
+		return x.Tiers
+	}
+	return nil
+}
+
+func (x *ShippingMethodReq) GetFreeAbove() *Money {
+	if x != nil {
+		return x.FreeAbove
+	}
+	return nil
+}
+
+func (x *ShippingMethodReq) GetActive() bool {
+	if x != nil && x.Active != nil {
+		return *x.Active
+	}
+	return false
+}
+
+type ShippingMethodRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ZoneId        int64                  `protobuf:"varint,2,opt,name=zone_id,json=zoneId,proto3" json:"zone_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Basis         ShippingBasis          `protobuf:"varint,4,opt,name=basis,proto3,enum=pb.ShippingBasis" json:"basis,omitempty"`
+	Tiers         []*ShippingTier        `protobuf:"bytes,5,rep,name=tiers,proto3" json:"tiers,omitempty"`
+	FreeAbove     *Money                 `protobuf:"bytes,6,opt,name=free_above,json=freeAbove,proto3" json:"free_above,omitempty"`
+	Active        bool                   `protobuf:"varint,7,opt,name=active,proto3" json:"active,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShippingMethodRes) Reset() {
+	*x = ShippingMethodRes{}
+	mi := &file_api_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShippingMethodRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShippingMethodRes) ProtoMessage() {}
+
+func (x *ShippingMethodRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShippingMethodRes.ProtoReflect.Descriptor instead.
+func (*ShippingMethodRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ShippingMethodRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ShippingMethodRes) GetZoneId() int64 {
+	if x != nil {
+		return x.ZoneId
+	}
+	return 0
+}
+
+func (x *ShippingMethodRes) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ShippingMethodRes) GetBasis() ShippingBasis {
+	if x != nil {
+		return x.Basis
+	}
+	return ShippingBasis_SUBTOTAL
+}
+
+func (x *ShippingMethodRes) GetTiers() []*ShippingTier {
+	if x != nil {
+		return x.Tiers
+	}
+	return nil
+}
+
+func (x *ShippingMethodRes) GetFreeAbove() *Money {
+	if x != nil {
+		return x.FreeAbove
+	}
+	return nil
+}
+
+func (x *ShippingMethodRes) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *ShippingMethodRes) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ShippingMethodRes) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// ShippingQuoteReq asks what shipping the user's cart would cost to a saved
+// address, an address given inline or else the user's default address.
+type ShippingQuoteReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AddressId     int64                  `protobuf:"varint,2,opt,name=address_id,json=addressId,proto3" json:"address_id,omitempty"`
+	Address       *Address               `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShippingQuoteReq) Reset() {
+	*x = ShippingQuoteReq{}
+	mi := &file_api_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShippingQuoteReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShippingQuoteReq) ProtoMessage() {}
+
+func (x *ShippingQuoteReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShippingQuoteReq.ProtoReflect.Descriptor instead.
+func (*ShippingQuoteReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ShippingQuoteReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ShippingQuoteReq) GetAddressId() int64 {
+	if x != nil {
+		return x.AddressId
+	}
+	return 0
+}
+
+func (x *ShippingQuoteReq) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+// ShippingOption is a way an order can ship. method_id is 0 for the flat
+// rate charged while no shipping zones are configured.
+type ShippingOption struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MethodId      int64                  `protobuf:"varint,1,opt,name=method_id,json=methodId,proto3" json:"method_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Zone          string                 `protobuf:"bytes,3,opt,name=zone,proto3" json:"zone,omitempty"`
+	Price         *Money                 `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShippingOption) Reset() {
+	*x = ShippingOption{}
+	mi := &file_api_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShippingOption) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShippingOption) ProtoMessage() {}
+
+func (x *ShippingOption) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShippingOption.ProtoReflect.Descriptor instead.
+func (*ShippingOption) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ShippingOption) GetMethodId() int64 {
+	if x != nil {
+		return x.MethodId
+	}
+	return 0
+}
+
+func (x *ShippingOption) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ShippingOption) GetZone() string {
+	if x != nil {
+		return x.Zone
+	}
+	return ""
+}
+
+func (x *ShippingOption) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+type ShippingQuoteRes struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Address *Address               `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	// cheapest first
+	Options       []*ShippingOption `protobuf:"bytes,2,rep,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShippingQuoteRes) Reset() {
+	*x = ShippingQuoteRes{}
+	mi := &file_api_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShippingQuoteRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShippingQuoteRes) ProtoMessage() {}
+
+func (x *ShippingQuoteRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShippingQuoteRes.ProtoReflect.Descriptor instead.
+func (*ShippingQuoteRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ShippingQuoteRes) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *ShippingQuoteRes) GetOptions() []*ShippingOption {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type SearchProductsRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hits          []*ProductSearchHit    `protobuf:"bytes,1,rep,name=hits,proto3" json:"hits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchProductsRes) Reset() {
+	*x = SearchProductsRes{}
+	mi := &file_api_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchProductsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchProductsRes) ProtoMessage() {}
+
+func (x *SearchProductsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchProductsRes.ProtoReflect.Descriptor instead.
+func (*SearchProductsRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *SearchProductsRes) GetHits() []*ProductSearchHit {
+	if x != nil {
+		return x.Hits
+	}
+	return nil
+}
+
+type OrderItem struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Name      string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Quantity  int64                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Image     string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
+	ProductId int64                  `protobuf:"varint,5,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Price     *Money                 `protobuf:"bytes,7,opt,name=price,proto3" json:"price,omitempty"`
+	LineTotal *Money                 `protobuf:"bytes,8,opt,name=line_total,json=lineTotal,proto3" json:"line_total,omitempty"`
+	// required for products with variants
+	VariantId int64  `protobuf:"varint,9,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Sku       string `protobuf:"bytes,10,opt,name=sku,proto3" json:"sku,omitempty"`
+	// the tax the line was charged, set by the server: the rate in basis
+	// points, the jurisdiction it applies in and whether it is contained in
+	// the price
+	TaxRate         int64  `protobuf:"varint,11,opt,name=tax_rate,json=taxRate,proto3" json:"tax_rate,omitempty"`
+	TaxJurisdiction string `protobuf:"bytes,12,opt,name=tax_jurisdiction,json=taxJurisdiction,proto3" json:"tax_jurisdiction,omitempty"`
+	TaxPrice        *Money `protobuf:"bytes,13,opt,name=tax_price,json=taxPrice,proto3" json:"tax_price,omitempty"`
+	TaxInclusive    bool   `protobuf:"varint,14,opt,name=tax_inclusive,json=taxInclusive,proto3" json:"tax_inclusive,omitempty"`
+	// set by the server; names the item in return requests
+	Id            int64 `protobuf:"varint,15,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderItem) Reset() {
+	*x = OrderItem{}
+	mi := &file_api_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderItem) ProtoMessage() {}
+
+func (x *OrderItem) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderItem.ProtoReflect.Descriptor instead.
+func (*OrderItem) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *OrderItem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *OrderItem) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderItem) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *OrderItem) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *OrderItem) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *OrderItem) GetLineTotal() *Money {
+	if x != nil {
+		return x.LineTotal
+	}
+	return nil
+}
+
+func (x *OrderItem) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *OrderItem) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *OrderItem) GetTaxRate() int64 {
+	if x != nil {
+		return x.TaxRate
+	}
+	return 0
+}
+
+func (x *OrderItem) GetTaxJurisdiction() string {
+	if x != nil {
+		return x.TaxJurisdiction
+	}
+	return ""
+}
+
+func (x *OrderItem) GetTaxPrice() *Money {
+	if x != nil {
+		return x.TaxPrice
+	}
+	return nil
+}
+
+func (x *OrderItem) GetTaxInclusive() bool {
+	if x != nil {
+		return x.TaxInclusive
+	}
+	return false
+}
+
+func (x *OrderItem) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// Address is a postal address. country is an ISO 3166-1 alpha-2 code and
+// region a subdivision of it, such as "CA" for California.
+type Address struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Line1         string                 `protobuf:"bytes,2,opt,name=line1,proto3" json:"line1,omitempty"`
+	Line2         string                 `protobuf:"bytes,3,opt,name=line2,proto3" json:"line2,omitempty"`
+	City          string                 `protobuf:"bytes,4,opt,name=city,proto3" json:"city,omitempty"`
+	Region        string                 `protobuf:"bytes,5,opt,name=region,proto3" json:"region,omitempty"`
+	PostalCode    string                 `protobuf:"bytes,6,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	Country       string                 `protobuf:"bytes,7,opt,name=country,proto3" json:"country,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	mi := &file_api_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *Address) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Address) GetLine1() string {
+	if x != nil {
+		return x.Line1
+	}
+	return ""
+}
+
+func (x *Address) GetLine2() string {
+	if x != nil {
+		return x.Line2
+	}
+	return ""
+}
+
+func (x *Address) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Address) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *Address) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *Address) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+type OrderReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Items         []*OrderItem           `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	PaymentMethod string                 `protobuf:"bytes,3,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	UserId        int64                  `protobuf:"varint,7,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	UserEmail     string                 `protobuf:"bytes,8,opt,name=user_email,json=userEmail,proto3" json:"user_email,omitempty"`
+	Status        OrderStatus            `protobuf:"varint,9,opt,name=status,proto3,enum=pb.OrderStatus" json:"status,omitempty"`
+	Reason        string                 `protobuf:"bytes,10,opt,name=reason,proto3" json:"reason,omitempty"`
+	IsAdmin       bool                   `protobuf:"varint,11,opt,name=is_admin,json=isAdmin,proto3" json:"is_admin,omitempty"`
+	TaxPrice      *Money                 `protobuf:"bytes,12,opt,name=tax_price,json=taxPrice,proto3" json:"tax_price,omitempty"`
+	ShippingPrice *Money                 `protobuf:"bytes,13,opt,name=shipping_price,json=shippingPrice,proto3" json:"shipping_price,omitempty"`
+	TotalPrice    *Money                 `protobuf:"bytes,14,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
+	// a code of a promotion to apply on top of the automatic ones
+	CouponCode    string `protobuf:"bytes,15,opt,name=coupon_code,json=couponCode,proto3" json:"coupon_code,omitempty"`
+	DiscountPrice *Money `protobuf:"bytes,16,opt,name=discount_price,json=discountPrice,proto3" json:"discount_price,omitempty"`
+	// the address the order ships to and taxes are computed for, given
+	// inline or as the id of a saved address; the user's default address
+	// when neither is set
+	ShippingAddress   *Address `protobuf:"bytes,17,opt,name=shipping_address,json=shippingAddress,proto3" json:"shipping_address,omitempty"`
+	ShippingAddressId int64    `protobuf:"varint,18,opt,name=shipping_address_id,json=shippingAddressId,proto3" json:"shipping_address_id,omitempty"`
+	// the billing address, the shipping address when neither is set
+	BillingAddress   *Address `protobuf:"bytes,19,opt,name=billing_address,json=billingAddress,proto3" json:"billing_address,omitempty"`
+	BillingAddressId int64    `protobuf:"varint,20,opt,name=billing_address_id,json=billingAddressId,proto3" json:"billing_address_id,omitempty"`
+	// the cheapest available method when not set
+	ShippingMethodId int64 `protobuf:"varint,21,opt,name=shipping_method_id,json=shippingMethodId,proto3" json:"shipping_method_id,omitempty"`
+	// gift cards that pay the order, used in the order given up to their
+	// balances, and at most how much of the user's store credit pays what
+	// they leave
+	GiftCardCodes []string `protobuf:"bytes,22,rep,name=gift_card_codes,json=giftCardCodes,proto3" json:"gift_card_codes,omitempty"`
+	StoreCredit   *Money   `protobuf:"bytes,23,opt,name=store_credit,json=storeCredit,proto3" json:"store_credit,omitempty"`
+	// on refund, refunds what was paid through the payment provider into
+	// the user's store credit instead
+	RefundToStoreCredit bool `protobuf:"varint,24,opt,name=refund_to_store_credit,json=refundToStoreCredit,proto3" json:"refund_to_store_credit,omitempty"`
+	// at most how many of the user's loyalty points pay what gift cards and
+	// store credit leave
+	LoyaltyPoints int64 `protobuf:"varint,25,opt,name=loyalty_points,json=loyaltyPoints,proto3" json:"loyalty_points,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderReq) Reset() {
+	*x = OrderReq{}
+	mi := &file_api_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderReq) ProtoMessage() {}
+
+func (x *OrderReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderReq.ProtoReflect.Descriptor instead.
+func (*OrderReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *OrderReq) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *OrderReq) GetItems() []*OrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *OrderReq) GetPaymentMethod() string {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return ""
+}
+
+func (x *OrderReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *OrderReq) GetUserEmail() string {
+	if x != nil {
+		return x.UserEmail
+	}
+	return ""
+}
+
+func (x *OrderReq) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_PENDING
+}
+
+func (x *OrderReq) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *OrderReq) GetIsAdmin() bool {
+	if x != nil {
+		return x.IsAdmin
+	}
+	return false
+}
+
+func (x *OrderReq) GetTaxPrice() *Money {
+	if x != nil {
+		return x.TaxPrice
+	}
+	return nil
+}
+
+func (x *OrderReq) GetShippingPrice() *Money {
+	if x != nil {
+		return x.ShippingPrice
+	}
+	return nil
+}
+
+func (x *OrderReq) GetTotalPrice() *Money {
+	if x != nil {
+		return x.TotalPrice
+	}
+	return nil
+}
+
+func (x *OrderReq) GetCouponCode() string {
+	if x != nil {
+		return x.CouponCode
+	}
+	return ""
+}
+
+func (x *OrderReq) GetDiscountPrice() *Money {
+	if x != nil {
+		return x.DiscountPrice
+	}
+	return nil
+}
+
+func (x *OrderReq) GetShippingAddress() *Address {
+	if x != nil {
+		return x.ShippingAddress
+	}
+	return nil
+}
+
+func (x *OrderReq) GetShippingAddressId() int64 {
+	if x != nil {
+		return x.ShippingAddressId
+	}
+	return 0
+}
+
+func (x *OrderReq) GetBillingAddress() *Address {
+	if x != nil {
+		return x.BillingAddress
+	}
+	return nil
+}
+
+func (x *OrderReq) GetBillingAddressId() int64 {
+	if x != nil {
+		return x.BillingAddressId
+	}
+	return 0
+}
+
+func (x *OrderReq) GetShippingMethodId() int64 {
+	if x != nil {
+		return x.ShippingMethodId
+	}
+	return 0
+}
+
+func (x *OrderReq) GetGiftCardCodes() []string {
+	if x != nil {
+		return x.GiftCardCodes
+	}
+	return nil
+}
+
+func (x *OrderReq) GetStoreCredit() *Money {
+	if x != nil {
+		return x.StoreCredit
+	}
+	return nil
+}
+
+func (x *OrderReq) GetRefundToStoreCredit() bool {
+	if x != nil {
+		return x.RefundToStoreCredit
+	}
+	return false
+}
+
+func (x *OrderReq) GetLoyaltyPoints() int64 {
+	if x != nil {
+		return x.LoyaltyPoints
+	}
+	return 0
+}
+
+type OrderRes struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Items           []*OrderItem           `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	PaymentMethod   string                 `protobuf:"bytes,3,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	UserId          int64                  `protobuf:"varint,7,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt       *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Status          OrderStatus            `protobuf:"varint,10,opt,name=status,proto3,enum=pb.OrderStatus" json:"status,omitempty"`
+	TaxPrice        *Money                 `protobuf:"bytes,12,opt,name=tax_price,json=taxPrice,proto3" json:"tax_price,omitempty"`
+	ShippingPrice   *Money                 `protobuf:"bytes,13,opt,name=shipping_price,json=shippingPrice,proto3" json:"shipping_price,omitempty"`
+	TotalPrice      *Money                 `protobuf:"bytes,14,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
+	ItemsPrice      *Money                 `protobuf:"bytes,15,opt,name=items_price,json=itemsPrice,proto3" json:"items_price,omitempty"`
+	DiscountPrice   *Money                 `protobuf:"bytes,16,opt,name=discount_price,json=discountPrice,proto3" json:"discount_price,omitempty"`
+	CouponCode      string                 `protobuf:"bytes,17,opt,name=coupon_code,json=couponCode,proto3" json:"coupon_code,omitempty"`
+	Discounts       []*OrderDiscount       `protobuf:"bytes,18,rep,name=discounts,proto3" json:"discounts,omitempty"`
+	ShippingAddress *Address               `protobuf:"bytes,19,opt,name=shipping_address,json=shippingAddress,proto3" json:"shipping_address,omitempty"`
+	BillingAddress  *Address               `protobuf:"bytes,20,opt,name=billing_address,json=billingAddress,proto3" json:"billing_address,omitempty"`
+	ShippingMethod  string                 `protobuf:"bytes,21,opt,name=shipping_method,json=shippingMethod,proto3" json:"shipping_method,omitempty"`
+	// what is left to pay after gift cards and store credit, set on create
+	AmountDue     *Money `protobuf:"bytes,22,opt,name=amount_due,json=amountDue,proto3" json:"amount_due,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderRes) Reset() {
+	*x = OrderRes{}
+	mi := &file_api_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderRes) ProtoMessage() {}
+
+func (x *OrderRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderRes.ProtoReflect.Descriptor instead.
+func (*OrderRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *OrderRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *OrderRes) GetItems() []*OrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *OrderRes) GetPaymentMethod() string {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return ""
+}
+
+func (x *OrderRes) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *OrderRes) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *OrderRes) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *OrderRes) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_PENDING
+}
+
+func (x *OrderRes) GetTaxPrice() *Money {
+	if x != nil {
+		return x.TaxPrice
+	}
+	return nil
+}
+
+func (x *OrderRes) GetShippingPrice() *Money {
+	if x != nil {
+		return x.ShippingPrice
+	}
+	return nil
+}
+
+func (x *OrderRes) GetTotalPrice() *Money {
+	if x != nil {
+		return x.TotalPrice
+	}
+	return nil
+}
+
+func (x *OrderRes) GetItemsPrice() *Money {
+	if x != nil {
+		return x.ItemsPrice
+	}
+	return nil
+}
+
+func (x *OrderRes) GetDiscountPrice() *Money {
+	if x != nil {
+		return x.DiscountPrice
+	}
+	return nil
+}
+
+func (x *OrderRes) GetCouponCode() string {
+	if x != nil {
+		return x.CouponCode
+	}
+	return ""
+}
+
+func (x *OrderRes) GetDiscounts() []*OrderDiscount {
+	if x != nil {
+		return x.Discounts
+	}
+	return nil
+}
+
+func (x *OrderRes) GetShippingAddress() *Address {
+	if x != nil {
+		return x.ShippingAddress
+	}
+	return nil
+}
+
+func (x *OrderRes) GetBillingAddress() *Address {
+	if x != nil {
+		return x.BillingAddress
+	}
+	return nil
+}
+
+func (x *OrderRes) GetShippingMethod() string {
+	if x != nil {
+		return x.ShippingMethod
+	}
+	return ""
+}
+
+func (x *OrderRes) GetAmountDue() *Money {
+	if x != nil {
+		return x.AmountDue
+	}
+	return nil
+}
+
+// OrderDiscount is one line of an order's discount breakdown.
+type OrderDiscount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PromotionId   int64                  `protobuf:"varint,1,opt,name=promotion_id,json=promotionId,proto3" json:"promotion_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Code          string                 `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"`
+	Amount        *Money                 `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderDiscount) Reset() {
+	*x = OrderDiscount{}
+	mi := &file_api_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderDiscount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderDiscount) ProtoMessage() {}
+
+func (x *OrderDiscount) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderDiscount.ProtoReflect.Descriptor instead.
+func (*OrderDiscount) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *OrderDiscount) GetPromotionId() int64 {
+	if x != nil {
+		return x.PromotionId
+	}
+	return 0
+}
+
+func (x *OrderDiscount) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *OrderDiscount) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *OrderDiscount) GetAmount() *Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+type ListOrderRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*OrderRes            `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrderRes) Reset() {
+	*x = ListOrderRes{}
+	mi := &file_api_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrderRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrderRes) ProtoMessage() {}
+
+func (x *ListOrderRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrderRes.ProtoReflect.Descriptor instead.
+func (*ListOrderRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ListOrderRes) GetOrders() []*OrderRes {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+func (x *ListOrderRes) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type ListUserOrdersReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Cursor        string                 `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Status        *OrderStatus           `protobuf:"varint,4,opt,name=status,proto3,enum=pb.OrderStatus,oneof" json:"status,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserOrdersReq) Reset() {
+	*x = ListUserOrdersReq{}
+	mi := &file_api_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserOrdersReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserOrdersReq) ProtoMessage() {}
+
+func (x *ListUserOrdersReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserOrdersReq.ProtoReflect.Descriptor instead.
+func (*ListUserOrdersReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ListUserOrdersReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ListUserOrdersReq) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListUserOrdersReq) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListUserOrdersReq) GetStatus() OrderStatus {
+	if x != nil && x.Status != nil {
+		return *x.Status
+	}
+	return OrderStatus_PENDING
+}
+
+func (x *ListUserOrdersReq) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListUserOrdersReq) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type OrderStatusChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId       int64                  `protobuf:"varint,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	FromStatus    OrderStatus            `protobuf:"varint,3,opt,name=from_status,json=fromStatus,proto3,enum=pb.OrderStatus" json:"from_status,omitempty"`
+	ToStatus      OrderStatus            `protobuf:"varint,4,opt,name=to_status,json=toStatus,proto3,enum=pb.OrderStatus" json:"to_status,omitempty"`
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	ActorId       int64                  `protobuf:"varint,6,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	ActorRole     string                 `protobuf:"bytes,7,opt,name=actor_role,json=actorRole,proto3" json:"actor_role,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderStatusChange) Reset() {
+	*x = OrderStatusChange{}
+	mi := &file_api_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderStatusChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderStatusChange) ProtoMessage() {}
+
+func (x *OrderStatusChange) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderStatusChange.ProtoReflect.Descriptor instead.
+func (*OrderStatusChange) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *OrderStatusChange) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *OrderStatusChange) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *OrderStatusChange) GetFromStatus() OrderStatus {
+	if x != nil {
+		return x.FromStatus
+	}
+	return OrderStatus_PENDING
+}
+
+func (x *OrderStatusChange) GetToStatus() OrderStatus {
+	if x != nil {
+		return x.ToStatus
+	}
+	return OrderStatus_PENDING
+}
+
+func (x *OrderStatusChange) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *OrderStatusChange) GetActorId() int64 {
+	if x != nil {
+		return x.ActorId
+	}
+	return 0
+}
+
+func (x *OrderStatusChange) GetActorRole() string {
+	if x != nil {
+		return x.ActorRole
+	}
+	return ""
+}
+
+func (x *OrderStatusChange) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListOrderStatusChangesRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changes       []*OrderStatusChange   `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrderStatusChangesRes) Reset() {
+	*x = ListOrderStatusChangesRes{}
+	mi := &file_api_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrderStatusChangesRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrderStatusChangesRes) ProtoMessage() {}
+
+func (x *ListOrderStatusChangesRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrderStatusChangesRes.ProtoReflect.Descriptor instead.
+func (*ListOrderStatusChangesRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *ListOrderStatusChangesRes) GetChanges() []*OrderStatusChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+// PaymentReq pays a pending order with the configured payment provider.
+// source is what the provider charges, e.g. a card token.
+type PaymentReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	IsAdmin       bool                   `protobuf:"varint,3,opt,name=is_admin,json=isAdmin,proto3" json:"is_admin,omitempty"`
+	Source        string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentReq) Reset() {
+	*x = PaymentReq{}
+	mi := &file_api_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentReq) ProtoMessage() {}
+
+func (x *PaymentReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentReq.ProtoReflect.Descriptor instead.
+func (*PaymentReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *PaymentReq) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *PaymentReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *PaymentReq) GetIsAdmin() bool {
+	if x != nil {
+		return x.IsAdmin
+	}
+	return false
+}
+
+func (x *PaymentReq) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+// PaymentRes is one attempt to pay an order. status is one of pending,
+// authorized, captured, declined, voided, refunded and failed; message
+// explains declines and failures.
+type PaymentRes struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId        int64                  `protobuf:"varint,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Provider       string                 `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
+	Reference      string                 `protobuf:"bytes,4,opt,name=reference,proto3" json:"reference,omitempty"`
+	Status         string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Amount         *Money                 `protobuf:"bytes,6,opt,name=amount,proto3" json:"amount,omitempty"`
+	Message        string                 `protobuf:"bytes,7,opt,name=message,proto3" json:"message,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	RefundedAmount *Money                 `protobuf:"bytes,10,opt,name=refunded_amount,json=refundedAmount,proto3" json:"refunded_amount,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PaymentRes) Reset() {
+	*x = PaymentRes{}
+	mi := &file_api_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentRes) ProtoMessage() {}
+
+func (x *PaymentRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentRes.ProtoReflect.Descriptor instead.
+func (*PaymentRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *PaymentRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PaymentRes) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *PaymentRes) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *PaymentRes) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *PaymentRes) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PaymentRes) GetAmount() *Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *PaymentRes) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *PaymentRes) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *PaymentRes) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *PaymentRes) GetRefundedAmount() *Money {
+	if x != nil {
+		return x.RefundedAmount
+	}
+	return nil
+}
+
+type ListPaymentsRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payments      []*PaymentRes          `protobuf:"bytes,1,rep,name=payments,proto3" json:"payments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPaymentsRes) Reset() {
+	*x = ListPaymentsRes{}
+	mi := &file_api_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPaymentsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPaymentsRes) ProtoMessage() {}
+
+func (x *ListPaymentsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPaymentsRes.ProtoReflect.Descriptor instead.
+func (*ListPaymentsRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *ListPaymentsRes) GetPayments() []*PaymentRes {
+	if x != nil {
+		return x.Payments
+	}
+	return nil
+}
+
+// InvoiceLine is an order line as invoiced. total is the unit price times
+// the quantity, which includes tax_price when tax_inclusive.
+type InvoiceLine struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Description     string                 `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+	Sku             string                 `protobuf:"bytes,2,opt,name=sku,proto3" json:"sku,omitempty"`
+	Quantity        int64                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	UnitPrice       *Money                 `protobuf:"bytes,4,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	TaxRate         int64                  `protobuf:"varint,5,opt,name=tax_rate,json=taxRate,proto3" json:"tax_rate,omitempty"`
+	TaxJurisdiction string                 `protobuf:"bytes,6,opt,name=tax_jurisdiction,json=taxJurisdiction,proto3" json:"tax_jurisdiction,omitempty"`
+	TaxPrice        *Money                 `protobuf:"bytes,7,opt,name=tax_price,json=taxPrice,proto3" json:"tax_price,omitempty"`
+	TaxInclusive    bool                   `protobuf:"varint,8,opt,name=tax_inclusive,json=taxInclusive,proto3" json:"tax_inclusive,omitempty"`
+	Total           *Money                 `protobuf:"bytes,9,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *InvoiceLine) Reset() {
+	*x = InvoiceLine{}
+	mi := &file_api_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvoiceLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvoiceLine) ProtoMessage() {}
+
+func (x *InvoiceLine) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvoiceLine.ProtoReflect.Descriptor instead.
+func (*InvoiceLine) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *InvoiceLine) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *InvoiceLine) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *InvoiceLine) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *InvoiceLine) GetUnitPrice() *Money {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return nil
+}
+
+func (x *InvoiceLine) GetTaxRate() int64 {
+	if x != nil {
+		return x.TaxRate
+	}
+	return 0
+}
+
+func (x *InvoiceLine) GetTaxJurisdiction() string {
+	if x != nil {
+		return x.TaxJurisdiction
+	}
+	return ""
+}
+
+func (x *InvoiceLine) GetTaxPrice() *Money {
+	if x != nil {
+		return x.TaxPrice
+	}
+	return nil
+}
+
+func (x *InvoiceLine) GetTaxInclusive() bool {
+	if x != nil {
+		return x.TaxInclusive
+	}
+	return false
+}
+
+func (x *InvoiceLine) GetTotal() *Money {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+// Seller is who issues invoices, as printed on them.
+type Seller struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Address       []string               `protobuf:"bytes,2,rep,name=address,proto3" json:"address,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	TaxId         string                 `protobuf:"bytes,4,opt,name=tax_id,json=taxId,proto3" json:"tax_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Seller) Reset() {
+	*x = Seller{}
+	mi := &file_api_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Seller) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Seller) ProtoMessage() {}
+
+func (x *Seller) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Seller.ProtoReflect.Descriptor instead.
+func (*Seller) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *Seller) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Seller) GetAddress() []string {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *Seller) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Seller) GetTaxId() string {
+	if x != nil {
+		return x.TaxId
+	}
+	return ""
+}
+
+// CreditNote cancels an invoice, in part or in full. Its amounts are
+// credited to the buyer.
+type CreditNote struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Number        string                 `protobuf:"bytes,2,opt,name=number,proto3" json:"number,omitempty"`
+	InvoiceId     int64                  `protobuf:"varint,3,opt,name=invoice_id,json=invoiceId,proto3" json:"invoice_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Lines         []*InvoiceLine         `protobuf:"bytes,5,rep,name=lines,proto3" json:"lines,omitempty"`
+	ItemsPrice    *Money                 `protobuf:"bytes,6,opt,name=items_price,json=itemsPrice,proto3" json:"items_price,omitempty"`
+	DiscountPrice *Money                 `protobuf:"bytes,7,opt,name=discount_price,json=discountPrice,proto3" json:"discount_price,omitempty"`
+	ShippingPrice *Money                 `protobuf:"bytes,8,opt,name=shipping_price,json=shippingPrice,proto3" json:"shipping_price,omitempty"`
+	TaxPrice      *Money                 `protobuf:"bytes,9,opt,name=tax_price,json=taxPrice,proto3" json:"tax_price,omitempty"`
+	TotalPrice    *Money                 `protobuf:"bytes,10,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
+	IssuedAt      *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreditNote) Reset() {
+	*x = CreditNote{}
+	mi := &file_api_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreditNote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreditNote) ProtoMessage() {}
+
+func (x *CreditNote) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreditNote.ProtoReflect.Descriptor instead.
+func (*CreditNote) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *CreditNote) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *CreditNote) GetNumber() string {
+	if x != nil {
+		return x.Number
+	}
+	return ""
+}
+
+func (x *CreditNote) GetInvoiceId() int64 {
+	if x != nil {
+		return x.InvoiceId
+	}
+	return 0
+}
+
+func (x *CreditNote) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *CreditNote) GetLines() []*InvoiceLine {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *CreditNote) GetItemsPrice() *Money {
+	if x != nil {
+		return x.ItemsPrice
+	}
+	return nil
+}
+
+func (x *CreditNote) GetDiscountPrice() *Money {
+	if x != nil {
+		return x.DiscountPrice
+	}
+	return nil
+}
+
+func (x *CreditNote) GetShippingPrice() *Money {
+	if x != nil {
+		return x.ShippingPrice
+	}
+	return nil
+}
+
+func (x *CreditNote) GetTaxPrice() *Money {
+	if x != nil {
+		return x.TaxPrice
+	}
+	return nil
+}
+
+func (x *CreditNote) GetTotalPrice() *Money {
+	if x != nil {
+		return x.TotalPrice
+	}
+	return nil
+}
+
+func (x *CreditNote) GetIssuedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IssuedAt
+	}
+	return nil
+}
+
+// InvoiceRes is the invoice of a paid order, together with the credit notes
+// issued against it.
+type InvoiceRes struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Number          string                 `protobuf:"bytes,2,opt,name=number,proto3" json:"number,omitempty"`
+	OrderId         int64                  `protobuf:"varint,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId          int64                  `protobuf:"varint,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Seller          *Seller                `protobuf:"bytes,5,opt,name=seller,proto3" json:"seller,omitempty"`
+	BuyerName       string                 `protobuf:"bytes,6,opt,name=buyer_name,json=buyerName,proto3" json:"buyer_name,omitempty"`
+	BuyerEmail      string                 `protobuf:"bytes,7,opt,name=buyer_email,json=buyerEmail,proto3" json:"buyer_email,omitempty"`
+	BillingAddress  *Address               `protobuf:"bytes,8,opt,name=billing_address,json=billingAddress,proto3" json:"billing_address,omitempty"`
+	ShippingAddress *Address               `protobuf:"bytes,9,opt,name=shipping_address,json=shippingAddress,proto3" json:"shipping_address,omitempty"`
+	Lines           []*InvoiceLine         `protobuf:"bytes,10,rep,name=lines,proto3" json:"lines,omitempty"`
+	ItemsPrice      *Money                 `protobuf:"bytes,11,opt,name=items_price,json=itemsPrice,proto3" json:"items_price,omitempty"`
+	DiscountPrice   *Money                 `protobuf:"bytes,12,opt,name=discount_price,json=discountPrice,proto3" json:"discount_price,omitempty"`
+	ShippingPrice   *Money                 `protobuf:"bytes,13,opt,name=shipping_price,json=shippingPrice,proto3" json:"shipping_price,omitempty"`
+	TaxPrice        *Money                 `protobuf:"bytes,14,opt,name=tax_price,json=taxPrice,proto3" json:"tax_price,omitempty"`
+	TotalPrice      *Money                 `protobuf:"bytes,15,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
+	IssuedAt        *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`
+	CreditNotes     []*CreditNote          `protobuf:"bytes,17,rep,name=credit_notes,json=creditNotes,proto3" json:"credit_notes,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *InvoiceRes) Reset() {
+	*x = InvoiceRes{}
+	mi := &file_api_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvoiceRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvoiceRes) ProtoMessage() {}
+
+func (x *InvoiceRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvoiceRes.ProtoReflect.Descriptor instead.
+func (*InvoiceRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *InvoiceRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *InvoiceRes) GetNumber() string {
+	if x != nil {
+		return x.Number
+	}
+	return ""
+}
+
+func (x *InvoiceRes) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *InvoiceRes) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *InvoiceRes) GetSeller() *Seller {
+	if x != nil {
+		return x.Seller
+	}
+	return nil
+}
+
+func (x *InvoiceRes) GetBuyerName() string {
+	if x != nil {
+		return x.BuyerName
+	}
+	return ""
+}
+
+func (x *InvoiceRes) GetBuyerEmail() string {
+	if x != nil {
+		return x.BuyerEmail
+	}
+	return ""
+}
+
+func (x *InvoiceRes) GetBillingAddress() *Address {
+	if x != nil {
+		return x.BillingAddress
+	}
+	return nil
+}
+
+func (x *InvoiceRes) GetShippingAddress() *Address {
+	if x != nil {
+		return x.ShippingAddress
+	}
+	return nil
+}
+
+func (x *InvoiceRes) GetLines() []*InvoiceLine {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *InvoiceRes) GetItemsPrice() *Money {
+	if x != nil {
+		return x.ItemsPrice
+	}
+	return nil
+}
+
+func (x *InvoiceRes) GetDiscountPrice() *Money {
+	if x != nil {
+		return x.DiscountPrice
+	}
+	return nil
+}
+
+func (x *InvoiceRes) GetShippingPrice() *Money {
+	if x != nil {
+		return x.ShippingPrice
+	}
+	return nil
+}
+
+func (x *InvoiceRes) GetTaxPrice() *Money {
+	if x != nil {
+		return x.TaxPrice
+	}
+	return nil
+}
+
+func (x *InvoiceRes) GetTotalPrice() *Money {
+	if x != nil {
+		return x.TotalPrice
+	}
+	return nil
+}
+
+func (x *InvoiceRes) GetIssuedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IssuedAt
+	}
+	return nil
+}
+
+func (x *InvoiceRes) GetCreditNotes() []*CreditNote {
+	if x != nil {
+		return x.CreditNotes
+	}
+	return nil
+}
+
+// ReturnReq requests the return of quantity units of an order item, or moves
+// a return along: status is then approved, rejected, received or refunded,
+// and restock puts received units back into stock.
+type ReturnReq struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId     int64                  `protobuf:"varint,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	OrderItemId int64                  `protobuf:"varint,3,opt,name=order_item_id,json=orderItemId,proto3" json:"order_item_id,omitempty"`
+	UserId      int64                  `protobuf:"varint,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	IsAdmin     bool                   `protobuf:"varint,5,opt,name=is_admin,json=isAdmin,proto3" json:"is_admin,omitempty"`
+	Quantity    int64                  `protobuf:"varint,6,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Reason      string                 `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`
+	PhotosUrl   string                 `protobuf:"bytes,8,opt,name=photos_url,json=photosUrl,proto3" json:"photos_url,omitempty"`
+	Status      string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`
+	Note        string                 `protobuf:"bytes,10,opt,name=note,proto3" json:"note,omitempty"`
+	Restock     bool                   `protobuf:"varint,11,opt,name=restock,proto3" json:"restock,omitempty"`
+	// refunds what was paid through the payment provider into the user's
+	// store credit instead
+	RefundToStoreCredit bool `protobuf:"varint,12,opt,name=refund_to_store_credit,json=refundToStoreCredit,proto3" json:"refund_to_store_credit,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ReturnReq) Reset() {
+	*x = ReturnReq{}
+	mi := &file_api_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReturnReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReturnReq) ProtoMessage() {}
+
+func (x *ReturnReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReturnReq.ProtoReflect.Descriptor instead.
+func (*ReturnReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *ReturnReq) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ReturnReq) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *ReturnReq) GetOrderItemId() int64 {
+	if x != nil {
+		return x.OrderItemId
+	}
+	return 0
+}
+
+func (x *ReturnReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ReturnReq) GetIsAdmin() bool {
+	if x != nil {
+		return x.IsAdmin
+	}
+	return false
+}
+
+func (x *ReturnReq) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *ReturnReq) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ReturnReq) GetPhotosUrl() string {
+	if x != nil {
+		return x.PhotosUrl
+	}
+	return ""
+}
+
+func (x *ReturnReq) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ReturnReq) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *ReturnReq) GetRestock() bool {
+	if x != nil {
+		return x.Restock
+	}
+	return false
+}
+
+func (x *ReturnReq) GetRefundToStoreCredit() bool {
+	if x != nil {
+		return x.RefundToStoreCredit
+	}
+	return false
+}
+
+// ReturnRes is a return. status is one of requested, approved, rejected,
+// received and refunded; refund_amount is what the customer gets back.
+type ReturnRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId       int64                  `protobuf:"varint,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	OrderItemId   int64                  `protobuf:"varint,3,opt,name=order_item_id,json=orderItemId,proto3" json:"order_item_id,omitempty"`
+	UserId        int64                  `protobuf:"varint,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Quantity      int64                  `protobuf:"varint,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Reason        string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	PhotosUrl     string                 `protobuf:"bytes,7,opt,name=photos_url,json=photosUrl,proto3" json:"photos_url,omitempty"`
+	Status        string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`
+	Note          string                 `protobuf:"bytes,9,opt,name=note,proto3" json:"note,omitempty"`
+	Restocked     bool                   `protobuf:"varint,10,opt,name=restocked,proto3" json:"restocked,omitempty"`
+	RefundAmount  *Money                 `protobuf:"bytes,11,opt,name=refund_amount,json=refundAmount,proto3" json:"refund_amount,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReturnRes) Reset() {
+	*x = ReturnRes{}
+	mi := &file_api_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReturnRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReturnRes) ProtoMessage() {}
+
+func (x *ReturnRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReturnRes.ProtoReflect.Descriptor instead.
+func (*ReturnRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *ReturnRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ReturnRes) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *ReturnRes) GetOrderItemId() int64 {
+	if x != nil {
+		return x.OrderItemId
+	}
+	return 0
+}
+
+func (x *ReturnRes) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ReturnRes) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *ReturnRes) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ReturnRes) GetPhotosUrl() string {
+	if x != nil {
+		return x.PhotosUrl
+	}
+	return ""
+}
+
+func (x *ReturnRes) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ReturnRes) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *ReturnRes) GetRestocked() bool {
+	if x != nil {
+		return x.Restocked
+	}
+	return false
+}
+
+func (x *ReturnRes) GetRefundAmount() *Money {
+	if x != nil {
+		return x.RefundAmount
+	}
+	return nil
+}
+
+func (x *ReturnRes) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ReturnRes) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// ListReturnsReq lists the returns in status, or every return if it is
+// empty.
+type ListReturnsReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReturnsReq) Reset() {
+	*x = ListReturnsReq{}
+	mi := &file_api_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReturnsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReturnsReq) ProtoMessage() {}
+
+func (x *ListReturnsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReturnsReq.ProtoReflect.Descriptor instead.
+func (*ListReturnsReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *ListReturnsReq) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ListReturnsRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Returns       []*ReturnRes           `protobuf:"bytes,1,rep,name=returns,proto3" json:"returns,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReturnsRes) Reset() {
+	*x = ListReturnsRes{}
+	mi := &file_api_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReturnsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReturnsRes) ProtoMessage() {}
+
+func (x *ListReturnsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReturnsRes.ProtoReflect.Descriptor instead.
+func (*ListReturnsRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *ListReturnsRes) GetReturns() []*ReturnRes {
+	if x != nil {
+		return x.Returns
+	}
+	return nil
+}
+
+// GiftCardReq issues a gift card, or names one by its code. Codes are
+// generated when left empty on issue. amount is the value issued, or the
+// signed change to the balance on adjustment. On update, expires_at and
+// note replace what the card had; an unset expires_at never expires.
+type GiftCardReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Amount        *Money                 `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Note          string                 `protobuf:"bytes,4,opt,name=note,proto3" json:"note,omitempty"`
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	UserId        int64                  `protobuf:"varint,6,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	IsAdmin       bool                   `protobuf:"varint,7,opt,name=is_admin,json=isAdmin,proto3" json:"is_admin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GiftCardReq) Reset() {
+	*x = GiftCardReq{}
+	mi := &file_api_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GiftCardReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GiftCardReq) ProtoMessage() {}
+
+func (x *GiftCardReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GiftCardReq.ProtoReflect.Descriptor instead.
+func (*GiftCardReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *GiftCardReq) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *GiftCardReq) GetAmount() *Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *GiftCardReq) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *GiftCardReq) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *GiftCardReq) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *GiftCardReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *GiftCardReq) GetIsAdmin() bool {
+	if x != nil {
+		return x.IsAdmin
+	}
+	return false
+}
+
+// GiftCardTransaction is a change to the balance of a gift card. kind is
+// one of issue, redeem, refund and adjustment.
+type GiftCardTransaction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Amount        *Money                 `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	OrderId       int64                  `protobuf:"varint,4,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	ActorId       int64                  `protobuf:"varint,5,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GiftCardTransaction) Reset() {
+	*x = GiftCardTransaction{}
+	mi := &file_api_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GiftCardTransaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GiftCardTransaction) ProtoMessage() {}
+
+func (x *GiftCardTransaction) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GiftCardTransaction.ProtoReflect.Descriptor instead.
+func (*GiftCardTransaction) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *GiftCardTransaction) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GiftCardTransaction) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *GiftCardTransaction) GetAmount() *Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *GiftCardTransaction) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *GiftCardTransaction) GetActorId() int64 {
+	if x != nil {
+		return x.ActorId
+	}
+	return 0
+}
+
+func (x *GiftCardTransaction) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *GiftCardTransaction) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// GiftCardRes is a gift card. Customers checking a card only see its code,
+// balance and expiry; the rest is for admins and the card's purchaser.
+type GiftCardRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	InitialAmount *Money                 `protobuf:"bytes,3,opt,name=initial_amount,json=initialAmount,proto3" json:"initial_amount,omitempty"`
+	Balance       *Money                 `protobuf:"bytes,4,opt,name=balance,proto3" json:"balance,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Expired       bool                   `protobuf:"varint,6,opt,name=expired,proto3" json:"expired,omitempty"`
+	PurchaserId   int64                  `protobuf:"varint,7,opt,name=purchaser_id,json=purchaserId,proto3" json:"purchaser_id,omitempty"`
+	OrderId       int64                  `protobuf:"varint,8,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	IssuedBy      int64                  `protobuf:"varint,9,opt,name=issued_by,json=issuedBy,proto3" json:"issued_by,omitempty"`
+	Note          string                 `protobuf:"bytes,10,opt,name=note,proto3" json:"note,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Transactions  []*GiftCardTransaction `protobuf:"bytes,13,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GiftCardRes) Reset() {
+	*x = GiftCardRes{}
+	mi := &file_api_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GiftCardRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GiftCardRes) ProtoMessage() {}
+
+func (x *GiftCardRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GiftCardRes.ProtoReflect.Descriptor instead.
+func (*GiftCardRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *GiftCardRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GiftCardRes) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *GiftCardRes) GetInitialAmount() *Money {
+	if x != nil {
+		return x.InitialAmount
+	}
+	return nil
+}
+
+func (x *GiftCardRes) GetBalance() *Money {
+	if x != nil {
+		return x.Balance
+	}
+	return nil
+}
+
+func (x *GiftCardRes) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *GiftCardRes) GetExpired() bool {
+	if x != nil {
+		return x.Expired
+	}
+	return false
+}
+
+func (x *GiftCardRes) GetPurchaserId() int64 {
+	if x != nil {
+		return x.PurchaserId
+	}
+	return 0
+}
+
+func (x *GiftCardRes) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *GiftCardRes) GetIssuedBy() int64 {
+	if x != nil {
+		return x.IssuedBy
+	}
+	return 0
+}
+
+func (x *GiftCardRes) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *GiftCardRes) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *GiftCardRes) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *GiftCardRes) GetTransactions() []*GiftCardTransaction {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+type ListGiftCardsRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GiftCards     []*GiftCardRes         `protobuf:"bytes,1,rep,name=gift_cards,json=giftCards,proto3" json:"gift_cards,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGiftCardsRes) Reset() {
+	*x = ListGiftCardsRes{}
+	mi := &file_api_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGiftCardsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGiftCardsRes) ProtoMessage() {}
+
+func (x *ListGiftCardsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGiftCardsRes.ProtoReflect.Descriptor instead.
+func (*ListGiftCardsRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *ListGiftCardsRes) GetGiftCards() []*GiftCardRes {
+	if x != nil {
+		return x.GiftCards
+	}
+	return nil
+}
+
+// StoreCreditReq names a user's store credit, and on adjustment changes
+// its balance by the signed amount. actor_id is the admin adjusting it.
+type StoreCreditReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Amount        *Money                 `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	ActorId       int64                  `protobuf:"varint,4,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreCreditReq) Reset() {
+	*x = StoreCreditReq{}
+	mi := &file_api_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreCreditReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreCreditReq) ProtoMessage() {}
+
+func (x *StoreCreditReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreCreditReq.ProtoReflect.Descriptor instead.
+func (*StoreCreditReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *StoreCreditReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *StoreCreditReq) GetAmount() *Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *StoreCreditReq) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *StoreCreditReq) GetActorId() int64 {
+	if x != nil {
+		return x.ActorId
+	}
+	return 0
+}
+
+// StoreCreditTransaction is a change to a user's store credit. kind is one
+// of redeem, refund and adjustment.
+type StoreCreditTransaction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Amount        *Money                 `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	OrderId       int64                  `protobuf:"varint,4,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	ActorId       int64                  `protobuf:"varint,5,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreCreditTransaction) Reset() {
+	*x = StoreCreditTransaction{}
+	mi := &file_api_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreCreditTransaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreCreditTransaction) ProtoMessage() {}
+
+func (x *StoreCreditTransaction) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreCreditTransaction.ProtoReflect.Descriptor instead.
+func (*StoreCreditTransaction) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *StoreCreditTransaction) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *StoreCreditTransaction) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *StoreCreditTransaction) GetAmount() *Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *StoreCreditTransaction) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *StoreCreditTransaction) GetActorId() int64 {
+	if x != nil {
+		return x.ActorId
+	}
+	return 0
+}
+
+func (x *StoreCreditTransaction) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *StoreCreditTransaction) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type StoreCreditRes struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	UserId        int64                     `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Balance       *Money                    `protobuf:"bytes,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	Transactions  []*StoreCreditTransaction `protobuf:"bytes,3,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreCreditRes) Reset() {
+	*x = StoreCreditRes{}
+	mi := &file_api_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreCreditRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreCreditRes) ProtoMessage() {}
+
+func (x *StoreCreditRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreCreditRes.ProtoReflect.Descriptor instead.
+func (*StoreCreditRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *StoreCreditRes) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *StoreCreditRes) GetBalance() *Money {
+	if x != nil {
+		return x.Balance
+	}
+	return nil
+}
+
+func (x *StoreCreditRes) GetTransactions() []*StoreCreditTransaction {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+type LoyaltyReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoyaltyReq) Reset() {
+	*x = LoyaltyReq{}
+	mi := &file_api_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoyaltyReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoyaltyReq) ProtoMessage() {}
+
+func (x *LoyaltyReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoyaltyReq.ProtoReflect.Descriptor instead.
+func (*LoyaltyReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *LoyaltyReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+// LoyaltyTransaction is a change to a user's loyalty points. kind is one of
+// earn, redeem, refund, reverse and expire.
+type LoyaltyTransaction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Points        int64                  `protobuf:"varint,3,opt,name=points,proto3" json:"points,omitempty"`
+	OrderId       int64                  `protobuf:"varint,4,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoyaltyTransaction) Reset() {
+	*x = LoyaltyTransaction{}
+	mi := &file_api_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoyaltyTransaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoyaltyTransaction) ProtoMessage() {}
+
+func (x *LoyaltyTransaction) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoyaltyTransaction.ProtoReflect.Descriptor instead.
+func (*LoyaltyTransaction) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *LoyaltyTransaction) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *LoyaltyTransaction) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *LoyaltyTransaction) GetPoints() int64 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+func (x *LoyaltyTransaction) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *LoyaltyTransaction) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *LoyaltyTransaction) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *LoyaltyTransaction) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// LoyaltyRes is a user's loyalty points and what they are worth at checkout,
+// and their tier, which follows what they spent over the last year. The next
+// tier is empty at the top tier; next_tier_spend is what is left to spend
+// to reach it.
+type LoyaltyRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Points        int64                  `protobuf:"varint,2,opt,name=points,proto3" json:"points,omitempty"`
+	PointsValue   *Money                 `protobuf:"bytes,3,opt,name=points_value,json=pointsValue,proto3" json:"points_value,omitempty"`
+	Tier          string                 `protobuf:"bytes,4,opt,name=tier,proto3" json:"tier,omitempty"`
+	YearSpend     *Money                 `protobuf:"bytes,5,opt,name=year_spend,json=yearSpend,proto3" json:"year_spend,omitempty"`
+	NextTier      string                 `protobuf:"bytes,6,opt,name=next_tier,json=nextTier,proto3" json:"next_tier,omitempty"`
+	NextTierSpend *Money                 `protobuf:"bytes,7,opt,name=next_tier_spend,json=nextTierSpend,proto3" json:"next_tier_spend,omitempty"`
+	// points earned per currency unit spent, scaled by the tier's multiplier
+	// in percent
+	EarnRate      int64                 `protobuf:"varint,8,opt,name=earn_rate,json=earnRate,proto3" json:"earn_rate,omitempty"`
+	Multiplier    int64                 `protobuf:"varint,9,opt,name=multiplier,proto3" json:"multiplier,omitempty"`
+	Transactions  []*LoyaltyTransaction `protobuf:"bytes,10,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoyaltyRes) Reset() {
+	*x = LoyaltyRes{}
+	mi := &file_api_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoyaltyRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoyaltyRes) ProtoMessage() {}
+
+func (x *LoyaltyRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoyaltyRes.ProtoReflect.Descriptor instead.
+func (*LoyaltyRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *LoyaltyRes) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *LoyaltyRes) GetPoints() int64 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+func (x *LoyaltyRes) GetPointsValue() *Money {
+	if x != nil {
+		return x.PointsValue
+	}
+	return nil
+}
+
+func (x *LoyaltyRes) GetTier() string {
+	if x != nil {
+		return x.Tier
+	}
+	return ""
+}
+
+func (x *LoyaltyRes) GetYearSpend() *Money {
+	if x != nil {
+		return x.YearSpend
+	}
+	return nil
+}
+
+func (x *LoyaltyRes) GetNextTier() string {
+	if x != nil {
+		return x.NextTier
+	}
+	return ""
+}
+
+func (x *LoyaltyRes) GetNextTierSpend() *Money {
+	if x != nil {
+		return x.NextTierSpend
+	}
+	return nil
+}
+
+func (x *LoyaltyRes) GetEarnRate() int64 {
+	if x != nil {
+		return x.EarnRate
+	}
+	return 0
+}
+
+func (x *LoyaltyRes) GetMultiplier() int64 {
+	if x != nil {
+		return x.Multiplier
+	}
+	return 0
+}
+
+func (x *LoyaltyRes) GetTransactions() []*LoyaltyTransaction {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+// PaymentEventReq is a provider's asynchronous report of a payment's new
+// status, received through its webhook.
+type PaymentEventReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Reference     string                 `protobuf:"bytes,2,opt,name=reference,proto3" json:"reference,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentEventReq) Reset() {
+	*x = PaymentEventReq{}
+	mi := &file_api_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentEventReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentEventReq) ProtoMessage() {}
+
+func (x *PaymentEventReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentEventReq.ProtoReflect.Descriptor instead.
+func (*PaymentEventReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *PaymentEventReq) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *PaymentEventReq) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *PaymentEventReq) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PaymentEventReq) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ReviewReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId     int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	UserId        int64                  `protobuf:"varint,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Rating        int64                  `protobuf:"varint,4,opt,name=rating,proto3" json:"rating,omitempty"`
+	Title         string                 `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`
+	Body          string                 `protobuf:"bytes,6,opt,name=body,proto3" json:"body,omitempty"`
+	IsAdmin       bool                   `protobuf:"varint,7,opt,name=is_admin,json=isAdmin,proto3" json:"is_admin,omitempty"`
+	Hidden        bool                   `protobuf:"varint,8,opt,name=hidden,proto3" json:"hidden,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewReq) Reset() {
+	*x = ReviewReq{}
+	mi := &file_api_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewReq) ProtoMessage() {}
+
+func (x *ReviewReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewReq.ProtoReflect.Descriptor instead.
+func (*ReviewReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *ReviewReq) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ReviewReq) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *ReviewReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ReviewReq) GetRating() int64 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *ReviewReq) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ReviewReq) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *ReviewReq) GetIsAdmin() bool {
+	if x != nil {
+		return x.IsAdmin
+	}
+	return false
+}
+
+func (x *ReviewReq) GetHidden() bool {
+	if x != nil {
+		return x.Hidden
+	}
+	return false
+}
+
+type ReviewRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId     int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	UserId        int64                  `protobuf:"varint,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Rating        int64                  `protobuf:"varint,4,opt,name=rating,proto3" json:"rating,omitempty"`
+	Title         string                 `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`
+	Body          string                 `protobuf:"bytes,6,opt,name=body,proto3" json:"body,omitempty"`
+	Hidden        bool                   `protobuf:"varint,7,opt,name=hidden,proto3" json:"hidden,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewRes) Reset() {
+	*x = ReviewRes{}
+	mi := &file_api_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewRes) ProtoMessage() {}
+
+func (x *ReviewRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewRes.ProtoReflect.Descriptor instead.
+func (*ReviewRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *ReviewRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ReviewRes) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *ReviewRes) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ReviewRes) GetRating() int64 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *ReviewRes) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ReviewRes) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *ReviewRes) GetHidden() bool {
+	if x != nil {
+		return x.Hidden
+	}
+	return false
+}
+
+func (x *ReviewRes) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ReviewRes) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ListReviewsReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Cursor        string                 `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	IncludeHidden bool                   `protobuf:"varint,4,opt,name=include_hidden,json=includeHidden,proto3" json:"include_hidden,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewsReq) Reset() {
+	*x = ListReviewsReq{}
+	mi := &file_api_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewsReq) ProtoMessage() {}
+
+func (x *ListReviewsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewsReq.ProtoReflect.Descriptor instead.
+func (*ListReviewsReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *ListReviewsReq) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *ListReviewsReq) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListReviewsReq) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListReviewsReq) GetIncludeHidden() bool {
+	if x != nil {
+		return x.IncludeHidden
+	}
+	return false
+}
+
+type ListReviewsRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reviews       []*ReviewRes           `protobuf:"bytes,1,rep,name=reviews,proto3" json:"reviews,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewsRes) Reset() {
+	*x = ListReviewsRes{}
+	mi := &file_api_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewsRes) ProtoMessage() {}
+
+func (x *ListReviewsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewsRes.ProtoReflect.Descriptor instead.
+func (*ListReviewsRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *ListReviewsRes) GetReviews() []*ReviewRes {
+	if x != nil {
+		return x.Reviews
+	}
+	return nil
+}
+
+func (x *ListReviewsRes) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type CartReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ProductId     int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      int64                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	VariantId     int64                  `protobuf:"varint,4,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CartReq) Reset() {
+	*x = CartReq{}
+	mi := &file_api_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CartReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CartReq) ProtoMessage() {}
+
+func (x *CartReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CartReq.ProtoReflect.Descriptor instead.
+func (*CartReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *CartReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CartReq) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *CartReq) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *CartReq) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+// CartItem is a product or variant in a cart priced at the current catalog
+// price, with compare_at_price set as for products. available is false
+// when it is gone or has fewer units in stock than the cart holds.
+type CartItem struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ProductId      int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity       int64                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Name           string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Image          string                 `protobuf:"bytes,4,opt,name=image,proto3" json:"image,omitempty"`
+	Price          *Money                 `protobuf:"bytes,5,opt,name=price,proto3" json:"price,omitempty"`
+	LineTotal      *Money                 `protobuf:"bytes,6,opt,name=line_total,json=lineTotal,proto3" json:"line_total,omitempty"`
+	CountInStock   int64                  `protobuf:"varint,7,opt,name=count_in_stock,json=countInStock,proto3" json:"count_in_stock,omitempty"`
+	Available      bool                   `protobuf:"varint,8,opt,name=available,proto3" json:"available,omitempty"`
+	VariantId      int64                  `protobuf:"varint,9,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Sku            string                 `protobuf:"bytes,10,opt,name=sku,proto3" json:"sku,omitempty"`
+	CompareAtPrice *Money                 `protobuf:"bytes,11,opt,name=compare_at_price,json=compareAtPrice,proto3" json:"compare_at_price,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CartItem) Reset() {
+	*x = CartItem{}
+	mi := &file_api_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CartItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CartItem) ProtoMessage() {}
+
+func (x *CartItem) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CartItem.ProtoReflect.Descriptor instead.
+func (*CartItem) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *CartItem) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *CartItem) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *CartItem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CartItem) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *CartItem) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *CartItem) GetLineTotal() *Money {
+	if x != nil {
+		return x.LineTotal
+	}
+	return nil
+}
+
+func (x *CartItem) GetCountInStock() int64 {
+	if x != nil {
+		return x.CountInStock
+	}
+	return 0
+}
+
+func (x *CartItem) GetAvailable() bool {
+	if x != nil {
+		return x.Available
+	}
+	return false
+}
+
+func (x *CartItem) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *CartItem) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *CartItem) GetCompareAtPrice() *Money {
+	if x != nil {
+		return x.CompareAtPrice
+	}
+	return nil
+}
+
+type CartRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Items         []*CartItem            `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	ItemsPrice    *Money                 `protobuf:"bytes,3,opt,name=items_price,json=itemsPrice,proto3" json:"items_price,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CartRes) Reset() {
+	*x = CartRes{}
+	mi := &file_api_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CartRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CartRes) ProtoMessage() {}
+
+func (x *CartRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CartRes.ProtoReflect.Descriptor instead.
+func (*CartRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *CartRes) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
 	}
 	return 0
 }
 
-func (x *ProductReq) GetNumReviews() int64 {
+func (x *CartRes) GetItems() []*CartItem {
 	if x != nil {
-		return x.NumReviews
+		return x.Items
 	}
-	return 0
+	return nil
 }
 
-func (x *ProductReq) GetPrice() float32 {
+func (x *CartRes) GetItemsPrice() *Money {
 	if x != nil {
-		return x.Price
+		return x.ItemsPrice
 	}
-	return 0
+	return nil
 }
 
-func (x *ProductReq) GetCountInStock() int64 {
+func (x *CartRes) GetUpdatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.CountInStock
+		return x.UpdatedAt
 	}
-	return 0
+	return nil
 }
 
-type ProductRes struct {
+type CheckoutCartReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Image         string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
-	Category      string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
-	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
-	Rating        int64                  `protobuf:"varint,6,opt,name=rating,proto3" json:"rating,omitempty"`
-	NumReviews    int64                  `protobuf:"varint,7,opt,name=num_reviews,json=numReviews,proto3" json:"num_reviews,omitempty"`
-	Price         float32                `protobuf:"fixed32,8,opt,name=price,proto3" json:"price,omitempty"`
-	CountInStock  int64                  `protobuf:"varint,9,opt,name=count_in_stock,json=countInStock,proto3" json:"count_in_stock,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	UserEmail     string                 `protobuf:"bytes,2,opt,name=user_email,json=userEmail,proto3" json:"user_email,omitempty"`
+	PaymentMethod string                 `protobuf:"bytes,3,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	TotalPrice    *Money                 `protobuf:"bytes,4,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
+	CouponCode    string                 `protobuf:"bytes,5,opt,name=coupon_code,json=couponCode,proto3" json:"coupon_code,omitempty"`
+	// addresses and shipping method, as in OrderReq
+	ShippingAddress   *Address `protobuf:"bytes,6,opt,name=shipping_address,json=shippingAddress,proto3" json:"shipping_address,omitempty"`
+	ShippingAddressId int64    `protobuf:"varint,7,opt,name=shipping_address_id,json=shippingAddressId,proto3" json:"shipping_address_id,omitempty"`
+	BillingAddress    *Address `protobuf:"bytes,8,opt,name=billing_address,json=billingAddress,proto3" json:"billing_address,omitempty"`
+	BillingAddressId  int64    `protobuf:"varint,9,opt,name=billing_address_id,json=billingAddressId,proto3" json:"billing_address_id,omitempty"`
+	ShippingMethodId  int64    `protobuf:"varint,10,opt,name=shipping_method_id,json=shippingMethodId,proto3" json:"shipping_method_id,omitempty"`
+	// gift cards, store credit and loyalty points, as in OrderReq
+	GiftCardCodes []string `protobuf:"bytes,11,rep,name=gift_card_codes,json=giftCardCodes,proto3" json:"gift_card_codes,omitempty"`
+	StoreCredit   *Money   `protobuf:"bytes,12,opt,name=store_credit,json=storeCredit,proto3" json:"store_credit,omitempty"`
+	LoyaltyPoints int64    `protobuf:"varint,13,opt,name=loyalty_points,json=loyaltyPoints,proto3" json:"loyalty_points,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ProductRes) Reset() {
-	*x = ProductRes{}
-	mi := &file_api_proto_msgTypes[1]
+func (x *CheckoutCartReq) Reset() {
+	*x = CheckoutCartReq{}
+	mi := &file_api_proto_msgTypes[86]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ProductRes) String() string {
+func (x *CheckoutCartReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProductRes) ProtoMessage() {}
+func (*CheckoutCartReq) ProtoMessage() {}
 
-func (x *ProductRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[1]
+func (x *CheckoutCartReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[86]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -267,110 +7726,129 @@ func (x *ProductRes) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProductRes.ProtoReflect.Descriptor instead.
-func (*ProductRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use CheckoutCartReq.ProtoReflect.Descriptor instead.
+func (*CheckoutCartReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{86}
 }
 
-func (x *ProductRes) GetId() int64 {
+func (x *CheckoutCartReq) GetUserId() int64 {
 	if x != nil {
-		return x.Id
+		return x.UserId
 	}
 	return 0
 }
 
-func (x *ProductRes) GetName() string {
+func (x *CheckoutCartReq) GetUserEmail() string {
 	if x != nil {
-		return x.Name
+		return x.UserEmail
 	}
 	return ""
 }
 
-func (x *ProductRes) GetImage() string {
+func (x *CheckoutCartReq) GetPaymentMethod() string {
 	if x != nil {
-		return x.Image
+		return x.PaymentMethod
 	}
 	return ""
 }
 
-func (x *ProductRes) GetCategory() string {
+func (x *CheckoutCartReq) GetTotalPrice() *Money {
 	if x != nil {
-		return x.Category
+		return x.TotalPrice
 	}
-	return ""
+	return nil
 }
 
-func (x *ProductRes) GetDescription() string {
+func (x *CheckoutCartReq) GetCouponCode() string {
 	if x != nil {
-		return x.Description
+		return x.CouponCode
 	}
 	return ""
 }
 
-func (x *ProductRes) GetRating() int64 {
+func (x *CheckoutCartReq) GetShippingAddress() *Address {
 	if x != nil {
-		return x.Rating
+		return x.ShippingAddress
 	}
-	return 0
+	return nil
 }
 
-func (x *ProductRes) GetNumReviews() int64 {
+func (x *CheckoutCartReq) GetShippingAddressId() int64 {
 	if x != nil {
-		return x.NumReviews
+		return x.ShippingAddressId
 	}
 	return 0
 }
 
-func (x *ProductRes) GetPrice() float32 {
+func (x *CheckoutCartReq) GetBillingAddress() *Address {
 	if x != nil {
-		return x.Price
+		return x.BillingAddress
+	}
+	return nil
+}
+
+func (x *CheckoutCartReq) GetBillingAddressId() int64 {
+	if x != nil {
+		return x.BillingAddressId
 	}
 	return 0
 }
 
-func (x *ProductRes) GetCountInStock() int64 {
+func (x *CheckoutCartReq) GetShippingMethodId() int64 {
 	if x != nil {
-		return x.CountInStock
+		return x.ShippingMethodId
 	}
 	return 0
 }
 
-func (x *ProductRes) GetCreatedAt() *timestamppb.Timestamp {
+func (x *CheckoutCartReq) GetGiftCardCodes() []string {
 	if x != nil {
-		return x.CreatedAt
+		return x.GiftCardCodes
 	}
 	return nil
 }
 
-func (x *ProductRes) GetUpdatedAt() *timestamppb.Timestamp {
+func (x *CheckoutCartReq) GetStoreCredit() *Money {
 	if x != nil {
-		return x.UpdatedAt
+		return x.StoreCredit
 	}
 	return nil
 }
 
-type ListProductRes struct {
+func (x *CheckoutCartReq) GetLoyaltyPoints() int64 {
+	if x != nil {
+		return x.LoyaltyPoints
+	}
+	return 0
+}
+
+// WishlistReq names one of the user's wishlists. shared turns sharing on
+// or off; a wishlist that starts being shared gets a new share token.
+type WishlistReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Products      []*ProductRes          `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Shared        *bool                  `protobuf:"varint,4,opt,name=shared,proto3,oneof" json:"shared,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListProductRes) Reset() {
-	*x = ListProductRes{}
-	mi := &file_api_proto_msgTypes[2]
+func (x *WishlistReq) Reset() {
+	*x = WishlistReq{}
+	mi := &file_api_proto_msgTypes[87]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListProductRes) String() string {
+func (x *WishlistReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListProductRes) ProtoMessage() {}
+func (*WishlistReq) ProtoMessage() {}
 
-func (x *ListProductRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[2]
+func (x *WishlistReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[87]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -381,44 +7859,67 @@ func (x *ListProductRes) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListProductRes.ProtoReflect.Descriptor instead.
-func (*ListProductRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use WishlistReq.ProtoReflect.Descriptor instead.
+func (*WishlistReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{87}
 }
 
-func (x *ListProductRes) GetProducts() []*ProductRes {
+func (x *WishlistReq) GetId() int64 {
 	if x != nil {
-		return x.Products
+		return x.Id
 	}
-	return nil
+	return 0
 }
 
-type OrderItem struct {
+func (x *WishlistReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *WishlistReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WishlistReq) GetShared() bool {
+	if x != nil && x.Shared != nil {
+		return *x.Shared
+	}
+	return false
+}
+
+// WishlistItemReq names a product or variant on a wishlist. quantity is
+// how many units moving it to the cart adds, 1 when left out.
+type WishlistItemReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Quantity      int64                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	Image         string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
-	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
-	ProductId     int64                  `protobuf:"varint,5,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	WishlistId    int64                  `protobuf:"varint,1,opt,name=wishlist_id,json=wishlistId,proto3" json:"wishlist_id,omitempty"`
+	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ProductId     int64                  `protobuf:"varint,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId     int64                  `protobuf:"varint,4,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Quantity      int64                  `protobuf:"varint,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OrderItem) Reset() {
-	*x = OrderItem{}
-	mi := &file_api_proto_msgTypes[3]
+func (x *WishlistItemReq) Reset() {
+	*x = WishlistItemReq{}
+	mi := &file_api_proto_msgTypes[88]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrderItem) String() string {
+func (x *WishlistItemReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrderItem) ProtoMessage() {}
+func (*WishlistItemReq) ProtoMessage() {}
 
-func (x *OrderItem) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[3]
+func (x *WishlistItemReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[88]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -429,76 +7930,80 @@ func (x *OrderItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrderItem.ProtoReflect.Descriptor instead.
-func (*OrderItem) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use WishlistItemReq.ProtoReflect.Descriptor instead.
+func (*WishlistItemReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{88}
 }
 
-func (x *OrderItem) GetName() string {
+func (x *WishlistItemReq) GetWishlistId() int64 {
 	if x != nil {
-		return x.Name
+		return x.WishlistId
 	}
-	return ""
+	return 0
 }
 
-func (x *OrderItem) GetQuantity() int64 {
+func (x *WishlistItemReq) GetUserId() int64 {
 	if x != nil {
-		return x.Quantity
+		return x.UserId
 	}
 	return 0
 }
 
-func (x *OrderItem) GetImage() string {
+func (x *WishlistItemReq) GetProductId() int64 {
 	if x != nil {
-		return x.Image
+		return x.ProductId
 	}
-	return ""
+	return 0
 }
 
-func (x *OrderItem) GetPrice() float64 {
+func (x *WishlistItemReq) GetVariantId() int64 {
 	if x != nil {
-		return x.Price
+		return x.VariantId
 	}
 	return 0
 }
 
-func (x *OrderItem) GetProductId() int64 {
+func (x *WishlistItemReq) GetQuantity() int64 {
 	if x != nil {
-		return x.ProductId
+		return x.Quantity
 	}
 	return 0
 }
 
-type OrderReq struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Items         []*OrderItem           `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
-	PaymentMethod string                 `protobuf:"bytes,3,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
-	TaxPrice      float64                `protobuf:"fixed64,4,opt,name=tax_price,json=taxPrice,proto3" json:"tax_price,omitempty"`
-	ShippingPrice float64                `protobuf:"fixed64,5,opt,name=shipping_price,json=shippingPrice,proto3" json:"shipping_price,omitempty"`
-	TotalPrice    float64                `protobuf:"fixed64,6,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
-	UserId        int64                  `protobuf:"varint,7,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	UserEmail     string                 `protobuf:"bytes,8,opt,name=user_email,json=userEmail,proto3" json:"user_email,omitempty"`
-	Status        OrderStatus            `protobuf:"varint,9,opt,name=status,proto3,enum=pb.OrderStatus" json:"status,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *OrderReq) Reset() {
-	*x = OrderReq{}
-	mi := &file_api_proto_msgTypes[4]
+// WishlistItem is a product or variant on a wishlist at its current
+// catalog price, with compare_at_price set as for products. available is
+// false when it is gone or out of stock.
+type WishlistItem struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ProductId      int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId      int64                  `protobuf:"varint,2,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Name           string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Image          string                 `protobuf:"bytes,4,opt,name=image,proto3" json:"image,omitempty"`
+	Sku            string                 `protobuf:"bytes,5,opt,name=sku,proto3" json:"sku,omitempty"`
+	Price          *Money                 `protobuf:"bytes,6,opt,name=price,proto3" json:"price,omitempty"`
+	CountInStock   int64                  `protobuf:"varint,7,opt,name=count_in_stock,json=countInStock,proto3" json:"count_in_stock,omitempty"`
+	Available      bool                   `protobuf:"varint,8,opt,name=available,proto3" json:"available,omitempty"`
+	AddedAt        *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=added_at,json=addedAt,proto3" json:"added_at,omitempty"`
+	CompareAtPrice *Money                 `protobuf:"bytes,10,opt,name=compare_at_price,json=compareAtPrice,proto3" json:"compare_at_price,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WishlistItem) Reset() {
+	*x = WishlistItem{}
+	mi := &file_api_proto_msgTypes[89]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrderReq) String() string {
+func (x *WishlistItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrderReq) ProtoMessage() {}
+func (*WishlistItem) ProtoMessage() {}
 
-func (x *OrderReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[4]
+func (x *WishlistItem) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[89]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -509,105 +8014,111 @@ func (x *OrderReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrderReq.ProtoReflect.Descriptor instead.
-func (*OrderReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use WishlistItem.ProtoReflect.Descriptor instead.
+func (*WishlistItem) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{89}
 }
 
-func (x *OrderReq) GetId() int64 {
+func (x *WishlistItem) GetProductId() int64 {
 	if x != nil {
-		return x.Id
+		return x.ProductId
 	}
 	return 0
 }
 
-func (x *OrderReq) GetItems() []*OrderItem {
+func (x *WishlistItem) GetVariantId() int64 {
 	if x != nil {
-		return x.Items
+		return x.VariantId
 	}
-	return nil
+	return 0
 }
 
-func (x *OrderReq) GetPaymentMethod() string {
+func (x *WishlistItem) GetName() string {
 	if x != nil {
-		return x.PaymentMethod
+		return x.Name
 	}
 	return ""
 }
 
-func (x *OrderReq) GetTaxPrice() float64 {
+func (x *WishlistItem) GetImage() string {
 	if x != nil {
-		return x.TaxPrice
+		return x.Image
 	}
-	return 0
+	return ""
 }
 
-func (x *OrderReq) GetShippingPrice() float64 {
+func (x *WishlistItem) GetSku() string {
 	if x != nil {
-		return x.ShippingPrice
+		return x.Sku
 	}
-	return 0
+	return ""
 }
 
-func (x *OrderReq) GetTotalPrice() float64 {
+func (x *WishlistItem) GetPrice() *Money {
 	if x != nil {
-		return x.TotalPrice
+		return x.Price
 	}
-	return 0
+	return nil
 }
 
-func (x *OrderReq) GetUserId() int64 {
+func (x *WishlistItem) GetCountInStock() int64 {
 	if x != nil {
-		return x.UserId
+		return x.CountInStock
 	}
 	return 0
 }
 
-func (x *OrderReq) GetUserEmail() string {
+func (x *WishlistItem) GetAvailable() bool {
 	if x != nil {
-		return x.UserEmail
+		return x.Available
 	}
-	return ""
+	return false
 }
 
-func (x *OrderReq) GetStatus() OrderStatus {
+func (x *WishlistItem) GetAddedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Status
+		return x.AddedAt
 	}
-	return OrderStatus_PENDING
+	return nil
 }
 
-type OrderRes struct {
+func (x *WishlistItem) GetCompareAtPrice() *Money {
+	if x != nil {
+		return x.CompareAtPrice
+	}
+	return nil
+}
+
+// WishlistRes is a wishlist. share_token is set while it is shared and
+// left out of shared views, as is user_id.
+type WishlistRes struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Items         []*OrderItem           `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
-	PaymentMethod string                 `protobuf:"bytes,3,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
-	TaxPrice      float64                `protobuf:"fixed64,4,opt,name=tax_price,json=taxPrice,proto3" json:"tax_price,omitempty"`
-	ShippingPrice float64                `protobuf:"fixed64,5,opt,name=shipping_price,json=shippingPrice,proto3" json:"shipping_price,omitempty"`
-	TotalPrice    float64                `protobuf:"fixed64,6,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
-	UserId        int64                  `protobuf:"varint,7,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	Status        OrderStatus            `protobuf:"varint,10,opt,name=status,proto3,enum=pb.OrderStatus" json:"status,omitempty"`
+	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	ShareToken    string                 `protobuf:"bytes,4,opt,name=share_token,json=shareToken,proto3" json:"share_token,omitempty"`
+	Items         []*WishlistItem        `protobuf:"bytes,5,rep,name=items,proto3" json:"items,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OrderRes) Reset() {
-	*x = OrderRes{}
-	mi := &file_api_proto_msgTypes[5]
+func (x *WishlistRes) Reset() {
+	*x = WishlistRes{}
+	mi := &file_api_proto_msgTypes[90]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrderRes) String() string {
+func (x *WishlistRes) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrderRes) ProtoMessage() {}
+func (*WishlistRes) ProtoMessage() {}
 
-func (x *OrderRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[5]
+func (x *WishlistRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[90]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -618,103 +8129,126 @@ func (x *OrderRes) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrderRes.ProtoReflect.Descriptor instead.
-func (*OrderRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use WishlistRes.ProtoReflect.Descriptor instead.
+func (*WishlistRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{90}
 }
 
-func (x *OrderRes) GetId() int64 {
+func (x *WishlistRes) GetId() int64 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-func (x *OrderRes) GetItems() []*OrderItem {
+func (x *WishlistRes) GetUserId() int64 {
 	if x != nil {
-		return x.Items
+		return x.UserId
 	}
-	return nil
+	return 0
 }
 
-func (x *OrderRes) GetPaymentMethod() string {
+func (x *WishlistRes) GetName() string {
 	if x != nil {
-		return x.PaymentMethod
+		return x.Name
 	}
 	return ""
 }
 
-func (x *OrderRes) GetTaxPrice() float64 {
+func (x *WishlistRes) GetShareToken() string {
 	if x != nil {
-		return x.TaxPrice
+		return x.ShareToken
 	}
-	return 0
+	return ""
 }
 
-func (x *OrderRes) GetShippingPrice() float64 {
+func (x *WishlistRes) GetItems() []*WishlistItem {
 	if x != nil {
-		return x.ShippingPrice
+		return x.Items
 	}
-	return 0
+	return nil
 }
 
-func (x *OrderRes) GetTotalPrice() float64 {
+func (x *WishlistRes) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.TotalPrice
+		return x.CreatedAt
 	}
-	return 0
+	return nil
 }
 
-func (x *OrderRes) GetUserId() int64 {
+func (x *WishlistRes) GetUpdatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.UserId
+		return x.UpdatedAt
 	}
-	return 0
+	return nil
 }
 
-func (x *OrderRes) GetCreatedAt() *timestamppb.Timestamp {
-	if x != nil {
-		return x.CreatedAt
-	}
-	return nil
+type ListWishlistsRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Wishlists     []*WishlistRes         `protobuf:"bytes,1,rep,name=wishlists,proto3" json:"wishlists,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OrderRes) GetUpdatedAt() *timestamppb.Timestamp {
+func (x *ListWishlistsRes) Reset() {
+	*x = ListWishlistsRes{}
+	mi := &file_api_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWishlistsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWishlistsRes) ProtoMessage() {}
+
+func (x *ListWishlistsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[91]
 	if x != nil {
-		return x.UpdatedAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWishlistsRes.ProtoReflect.Descriptor instead.
+func (*ListWishlistsRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{91}
 }
 
-func (x *OrderRes) GetStatus() OrderStatus {
+func (x *ListWishlistsRes) GetWishlists() []*WishlistRes {
 	if x != nil {
-		return x.Status
+		return x.Wishlists
 	}
-	return OrderStatus_PENDING
+	return nil
 }
 
-type ListOrderRes struct {
+type SharedWishlistReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Orders        []*OrderRes            `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	ShareToken    string                 `protobuf:"bytes,1,opt,name=share_token,json=shareToken,proto3" json:"share_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListOrderRes) Reset() {
-	*x = ListOrderRes{}
-	mi := &file_api_proto_msgTypes[6]
+func (x *SharedWishlistReq) Reset() {
+	*x = SharedWishlistReq{}
+	mi := &file_api_proto_msgTypes[92]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListOrderRes) String() string {
+func (x *SharedWishlistReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListOrderRes) ProtoMessage() {}
+func (*SharedWishlistReq) ProtoMessage() {}
 
-func (x *ListOrderRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[6]
+func (x *SharedWishlistReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[92]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -725,16 +8259,16 @@ func (x *ListOrderRes) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListOrderRes.ProtoReflect.Descriptor instead.
-func (*ListOrderRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use SharedWishlistReq.ProtoReflect.Descriptor instead.
+func (*SharedWishlistReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{92}
 }
 
-func (x *ListOrderRes) GetOrders() []*OrderRes {
+func (x *SharedWishlistReq) GetShareToken() string {
 	if x != nil {
-		return x.Orders
+		return x.ShareToken
 	}
-	return nil
+	return ""
 }
 
 type UserReq struct {
@@ -744,13 +8278,14 @@ type UserReq struct {
 	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
 	Password      string                 `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
 	IsAdmin       bool                   `protobuf:"varint,5,opt,name=is_admin,json=isAdmin,proto3" json:"is_admin,omitempty"`
+	TaxExempt     bool                   `protobuf:"varint,6,opt,name=tax_exempt,json=taxExempt,proto3" json:"tax_exempt,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UserReq) Reset() {
 	*x = UserReq{}
-	mi := &file_api_proto_msgTypes[7]
+	mi := &file_api_proto_msgTypes[93]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -762,7 +8297,7 @@ func (x *UserReq) String() string {
 func (*UserReq) ProtoMessage() {}
 
 func (x *UserReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[7]
+	mi := &file_api_proto_msgTypes[93]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -775,7 +8310,7 @@ func (x *UserReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserReq.ProtoReflect.Descriptor instead.
 func (*UserReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{7}
+	return file_api_proto_rawDescGZIP(), []int{93}
 }
 
 func (x *UserReq) GetId() int64 {
@@ -813,6 +8348,13 @@ func (x *UserReq) GetIsAdmin() bool {
 	return false
 }
 
+func (x *UserReq) GetTaxExempt() bool {
+	if x != nil {
+		return x.TaxExempt
+	}
+	return false
+}
+
 type UserRes struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -821,13 +8363,14 @@ type UserRes struct {
 	Password      string                 `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
 	IsAdmin       bool                   `protobuf:"varint,5,opt,name=is_admin,json=isAdmin,proto3" json:"is_admin,omitempty"`
 	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	TaxExempt     bool                   `protobuf:"varint,7,opt,name=tax_exempt,json=taxExempt,proto3" json:"tax_exempt,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UserRes) Reset() {
 	*x = UserRes{}
-	mi := &file_api_proto_msgTypes[8]
+	mi := &file_api_proto_msgTypes[94]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -839,7 +8382,7 @@ func (x *UserRes) String() string {
 func (*UserRes) ProtoMessage() {}
 
 func (x *UserRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[8]
+	mi := &file_api_proto_msgTypes[94]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -852,7 +8395,7 @@ func (x *UserRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserRes.ProtoReflect.Descriptor instead.
 func (*UserRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{8}
+	return file_api_proto_rawDescGZIP(), []int{94}
 }
 
 func (x *UserRes) GetId() int64 {
@@ -897,6 +8440,13 @@ func (x *UserRes) GetCreatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *UserRes) GetTaxExempt() bool {
+	if x != nil {
+		return x.TaxExempt
+	}
+	return false
+}
+
 type ListUserRes struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Users         []*UserRes             `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
@@ -906,7 +8456,7 @@ type ListUserRes struct {
 
 func (x *ListUserRes) Reset() {
 	*x = ListUserRes{}
-	mi := &file_api_proto_msgTypes[9]
+	mi := &file_api_proto_msgTypes[95]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -918,7 +8468,7 @@ func (x *ListUserRes) String() string {
 func (*ListUserRes) ProtoMessage() {}
 
 func (x *ListUserRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[9]
+	mi := &file_api_proto_msgTypes[95]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -931,7 +8481,7 @@ func (x *ListUserRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListUserRes.ProtoReflect.Descriptor instead.
 func (*ListUserRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{9}
+	return file_api_proto_rawDescGZIP(), []int{95}
 }
 
 func (x *ListUserRes) GetUsers() []*UserRes {
@@ -954,7 +8504,7 @@ type SessionReq struct {
 
 func (x *SessionReq) Reset() {
 	*x = SessionReq{}
-	mi := &file_api_proto_msgTypes[10]
+	mi := &file_api_proto_msgTypes[96]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -966,7 +8516,7 @@ func (x *SessionReq) String() string {
 func (*SessionReq) ProtoMessage() {}
 
 func (x *SessionReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[10]
+	mi := &file_api_proto_msgTypes[96]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -979,7 +8529,7 @@ func (x *SessionReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SessionReq.ProtoReflect.Descriptor instead.
 func (*SessionReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{10}
+	return file_api_proto_rawDescGZIP(), []int{96}
 }
 
 func (x *SessionReq) GetId() string {
@@ -1030,7 +8580,7 @@ type SessionRes struct {
 
 func (x *SessionRes) Reset() {
 	*x = SessionRes{}
-	mi := &file_api_proto_msgTypes[11]
+	mi := &file_api_proto_msgTypes[97]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1042,7 +8592,7 @@ func (x *SessionRes) String() string {
 func (*SessionRes) ProtoMessage() {}
 
 func (x *SessionRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[11]
+	mi := &file_api_proto_msgTypes[97]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1055,7 +8605,7 @@ func (x *SessionRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SessionRes.ProtoReflect.Descriptor instead.
 func (*SessionRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{11}
+	return file_api_proto_rawDescGZIP(), []int{97}
 }
 
 func (x *SessionRes) GetId() string {
@@ -1094,20 +8644,23 @@ func (x *SessionRes) GetExpiresAt() *timestamppb.Timestamp {
 }
 
 type NotificationEvent struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	UserEmail     string                 `protobuf:"bytes,2,opt,name=user_email,json=userEmail,proto3" json:"user_email,omitempty"`
-	OrderStatus   OrderStatus            `protobuf:"varint,3,opt,name=order_status,json=orderStatus,proto3,enum=pb.OrderStatus" json:"order_status,omitempty"`
-	OrderId       int64                  `protobuf:"varint,4,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
-	StateId       int64                  `protobuf:"varint,5,opt,name=state_id,json=stateId,proto3" json:"state_id,omitempty"`
-	Attempts      int64                  `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserEmail   string                 `protobuf:"bytes,2,opt,name=user_email,json=userEmail,proto3" json:"user_email,omitempty"`
+	OrderStatus OrderStatus            `protobuf:"varint,3,opt,name=order_status,json=orderStatus,proto3,enum=pb.OrderStatus" json:"order_status,omitempty"`
+	OrderId     int64                  `protobuf:"varint,4,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	StateId     int64                  `protobuf:"varint,5,opt,name=state_id,json=stateId,proto3" json:"state_id,omitempty"`
+	Attempts    int64                  `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	// set on events about a return of the order
+	ReturnId      int64  `protobuf:"varint,7,opt,name=return_id,json=returnId,proto3" json:"return_id,omitempty"`
+	ReturnStatus  string `protobuf:"bytes,8,opt,name=return_status,json=returnStatus,proto3" json:"return_status,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *NotificationEvent) Reset() {
 	*x = NotificationEvent{}
-	mi := &file_api_proto_msgTypes[12]
+	mi := &file_api_proto_msgTypes[98]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1119,7 +8672,7 @@ func (x *NotificationEvent) String() string {
 func (*NotificationEvent) ProtoMessage() {}
 
 func (x *NotificationEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[12]
+	mi := &file_api_proto_msgTypes[98]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1132,7 +8685,7 @@ func (x *NotificationEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NotificationEvent.ProtoReflect.Descriptor instead.
 func (*NotificationEvent) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{12}
+	return file_api_proto_rawDescGZIP(), []int{98}
 }
 
 func (x *NotificationEvent) GetId() int64 {
@@ -1177,6 +8730,20 @@ func (x *NotificationEvent) GetAttempts() int64 {
 	return 0
 }
 
+func (x *NotificationEvent) GetReturnId() int64 {
+	if x != nil {
+		return x.ReturnId
+	}
+	return 0
+}
+
+func (x *NotificationEvent) GetReturnStatus() string {
+	if x != nil {
+		return x.ReturnStatus
+	}
+	return ""
+}
+
 type ListNotificationEventsReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -1185,7 +8752,7 @@ type ListNotificationEventsReq struct {
 
 func (x *ListNotificationEventsReq) Reset() {
 	*x = ListNotificationEventsReq{}
-	mi := &file_api_proto_msgTypes[13]
+	mi := &file_api_proto_msgTypes[99]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1197,7 +8764,7 @@ func (x *ListNotificationEventsReq) String() string {
 func (*ListNotificationEventsReq) ProtoMessage() {}
 
 func (x *ListNotificationEventsReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[13]
+	mi := &file_api_proto_msgTypes[99]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1210,7 +8777,59 @@ func (x *ListNotificationEventsReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListNotificationEventsReq.ProtoReflect.Descriptor instead.
 func (*ListNotificationEventsReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{13}
+	return file_api_proto_rawDescGZIP(), []int{99}
+}
+
+type ClaimNotificationEventsReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	LeaseSeconds  int64                  `protobuf:"varint,2,opt,name=lease_seconds,json=leaseSeconds,proto3" json:"lease_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClaimNotificationEventsReq) Reset() {
+	*x = ClaimNotificationEventsReq{}
+	mi := &file_api_proto_msgTypes[100]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClaimNotificationEventsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClaimNotificationEventsReq) ProtoMessage() {}
+
+func (x *ClaimNotificationEventsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[100]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClaimNotificationEventsReq.ProtoReflect.Descriptor instead.
+func (*ClaimNotificationEventsReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{100}
+}
+
+func (x *ClaimNotificationEventsReq) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ClaimNotificationEventsReq) GetLeaseSeconds() int64 {
+	if x != nil {
+		return x.LeaseSeconds
+	}
+	return 0
 }
 
 type ListNotificationEventsRes struct {
@@ -1222,7 +8841,7 @@ type ListNotificationEventsRes struct {
 
 func (x *ListNotificationEventsRes) Reset() {
 	*x = ListNotificationEventsRes{}
-	mi := &file_api_proto_msgTypes[14]
+	mi := &file_api_proto_msgTypes[101]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1234,7 +8853,7 @@ func (x *ListNotificationEventsRes) String() string {
 func (*ListNotificationEventsRes) ProtoMessage() {}
 
 func (x *ListNotificationEventsRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[14]
+	mi := &file_api_proto_msgTypes[101]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1247,7 +8866,7 @@ func (x *ListNotificationEventsRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListNotificationEventsRes.ProtoReflect.Descriptor instead.
 func (*ListNotificationEventsRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{14}
+	return file_api_proto_rawDescGZIP(), []int{101}
 }
 
 func (x *ListNotificationEventsRes) GetEvents() []*NotificationEvent {
@@ -1270,7 +8889,7 @@ type UpdateNotificationEventReq struct {
 
 func (x *UpdateNotificationEventReq) Reset() {
 	*x = UpdateNotificationEventReq{}
-	mi := &file_api_proto_msgTypes[15]
+	mi := &file_api_proto_msgTypes[102]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1282,7 +8901,7 @@ func (x *UpdateNotificationEventReq) String() string {
 func (*UpdateNotificationEventReq) ProtoMessage() {}
 
 func (x *UpdateNotificationEventReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[15]
+	mi := &file_api_proto_msgTypes[102]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1295,7 +8914,7 @@ func (x *UpdateNotificationEventReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateNotificationEventReq.ProtoReflect.Descriptor instead.
 func (*UpdateNotificationEventReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{15}
+	return file_api_proto_rawDescGZIP(), []int{102}
 }
 
 func (x *UpdateNotificationEventReq) GetId() int64 {
@@ -1342,7 +8961,7 @@ type UpdateNotificationEventRes struct {
 
 func (x *UpdateNotificationEventRes) Reset() {
 	*x = UpdateNotificationEventRes{}
-	mi := &file_api_proto_msgTypes[16]
+	mi := &file_api_proto_msgTypes[103]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1354,7 +8973,7 @@ func (x *UpdateNotificationEventRes) String() string {
 func (*UpdateNotificationEventRes) ProtoMessage() {}
 
 func (x *UpdateNotificationEventRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[16]
+	mi := &file_api_proto_msgTypes[103]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1367,7 +8986,7 @@ func (x *UpdateNotificationEventRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateNotificationEventRes.ProtoReflect.Descriptor instead.
 func (*UpdateNotificationEventRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{16}
+	return file_api_proto_rawDescGZIP(), []int{103}
 }
 
 func (x *UpdateNotificationEventRes) GetSucceeded() bool {
@@ -1381,80 +9000,854 @@ var File_api_proto protoreflect.FileDescriptor
 
 const file_api_proto_rawDesc = "" +
 	"\n" +
-	"\tapi.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\"\xf9\x01\n" +
+	"\tapi.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\";\n" +
+	"\x05Money\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x03R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\"\xf7\x02\n" +
 	"\n" +
 	"ProductReq\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
-	"\x05image\x18\x03 \x01(\tR\x05image\x12\x1a\n" +
-	"\bcategory\x18\x04 \x01(\tR\bcategory\x12 \n" +
-	"\vdescription\x18\x05 \x01(\tR\vdescription\x12\x16\n" +
-	"\x06rating\x18\x06 \x01(\x03R\x06rating\x12\x1f\n" +
-	"\vnum_reviews\x18\a \x01(\x03R\n" +
-	"numReviews\x12\x14\n" +
-	"\x05price\x18\b \x01(\x02R\x05price\x12$\n" +
-	"\x0ecount_in_stock\x18\t \x01(\x03R\fcountInStock\"\xef\x02\n" +
+	"\x05image\x18\x03 \x01(\tR\x05image\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12$\n" +
+	"\x0ecount_in_stock\x18\t \x01(\x03R\fcountInStock\x12\x1f\n" +
+	"\x05price\x18\n" +
+	" \x01(\v2\t.pb.MoneyR\x05price\x12\x1f\n" +
+	"\vcategory_id\x18\v \x01(\x03R\n" +
+	"categoryId\x12\x18\n" +
+	"\aoptions\x18\f \x03(\tR\aoptions\x12\x1b\n" +
+	"\ttax_class\x18\r \x01(\tR\btaxClass\x12\x1b\n" +
+	"\x06weight\x18\x0e \x01(\x03H\x00R\x06weight\x88\x01\x01\x12 \n" +
+	"\tgift_card\x18\x0f \x01(\bH\x01R\bgiftCard\x88\x01\x01B\t\n" +
+	"\a_weightB\f\n" +
+	"\n" +
+	"_gift_cardJ\x04\b\x04\x10\x05J\x04\b\x06\x10\aJ\x04\b\a\x10\bJ\x04\b\b\x10\t\"\xd8\x04\n" +
 	"\n" +
 	"ProductRes\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
-	"\x05image\x18\x03 \x01(\tR\x05image\x12\x1a\n" +
-	"\bcategory\x18\x04 \x01(\tR\bcategory\x12 \n" +
+	"\x05image\x18\x03 \x01(\tR\x05image\x12 \n" +
 	"\vdescription\x18\x05 \x01(\tR\vdescription\x12\x16\n" +
 	"\x06rating\x18\x06 \x01(\x03R\x06rating\x12\x1f\n" +
 	"\vnum_reviews\x18\a \x01(\x03R\n" +
-	"numReviews\x12\x14\n" +
-	"\x05price\x18\b \x01(\x02R\x05price\x12$\n" +
+	"numReviews\x12$\n" +
 	"\x0ecount_in_stock\x18\t \x01(\x03R\fcountInStock\x129\n" +
 	"\n" +
 	"created_at\x18\n" +
 	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"<\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1f\n" +
+	"\x05price\x18\f \x01(\v2\t.pb.MoneyR\x05price\x12\x1f\n" +
+	"\vcategory_id\x18\r \x01(\x03R\n" +
+	"categoryId\x12\x18\n" +
+	"\aoptions\x18\x0e \x03(\tR\aoptions\x12*\n" +
+	"\bvariants\x18\x0f \x03(\v2\x0e.pb.VariantResR\bvariants\x12\x1b\n" +
+	"\ttax_class\x18\x10 \x01(\tR\btaxClass\x12\x16\n" +
+	"\x06weight\x18\x11 \x01(\x03R\x06weight\x12\x1b\n" +
+	"\tgift_card\x18\x12 \x01(\bR\bgiftCard\x123\n" +
+	"\x10compare_at_price\x18\x13 \x01(\v2\t.pb.MoneyR\x0ecompareAtPriceJ\x04\b\x04\x10\x05J\x04\b\b\x10\t\"\xe9\x02\n" +
+	"\n" +
+	"VariantReq\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x10\n" +
+	"\x03sku\x18\x03 \x01(\tR\x03sku\x125\n" +
+	"\aoptions\x18\x04 \x03(\v2\x1b.pb.VariantReq.OptionsEntryR\aoptions\x12\x1f\n" +
+	"\x05price\x18\x05 \x01(\v2\t.pb.MoneyR\x05price\x12\x19\n" +
+	"\x05image\x18\x06 \x01(\tH\x00R\x05image\x88\x01\x01\x12)\n" +
+	"\x0ecount_in_stock\x18\a \x01(\x03H\x01R\fcountInStock\x88\x01\x01\x12#\n" +
+	"\rinherit_price\x18\b \x01(\bR\finheritPrice\x1a:\n" +
+	"\fOptionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\b\n" +
+	"\x06_imageB\x11\n" +
+	"\x0f_count_in_stock\"\xc8\x03\n" +
+	"\n" +
+	"VariantRes\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x10\n" +
+	"\x03sku\x18\x03 \x01(\tR\x03sku\x125\n" +
+	"\aoptions\x18\x04 \x03(\v2\x1b.pb.VariantRes.OptionsEntryR\aoptions\x12\x1f\n" +
+	"\x05price\x18\x05 \x01(\v2\t.pb.MoneyR\x05price\x12\x14\n" +
+	"\x05image\x18\x06 \x01(\tR\x05image\x12$\n" +
+	"\x0ecount_in_stock\x18\a \x01(\x03R\fcountInStock\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x123\n" +
+	"\x10compare_at_price\x18\n" +
+	" \x01(\v2\t.pb.MoneyR\x0ecompareAtPrice\x1a:\n" +
+	"\fOptionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa9\x02\n" +
+	"\x11ScheduledPriceReq\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x03 \x01(\x03R\tvariantId\x12\x1f\n" +
+	"\x05price\x18\x04 \x01(\v2\t.pb.MoneyR\x05price\x127\n" +
+	"\tstarts_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\bstartsAt\x123\n" +
+	"\aends_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x06endsAt\x12&\n" +
+	"\fquantity_cap\x18\a \x01(\x03H\x00R\vquantityCap\x88\x01\x01B\x0f\n" +
+	"\r_quantity_cap\"\xc8\x03\n" +
+	"\x11ScheduledPriceRes\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x03 \x01(\x03R\tvariantId\x12\x1f\n" +
+	"\x05price\x18\x04 \x01(\v2\t.pb.MoneyR\x05price\x127\n" +
+	"\tstarts_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\bstartsAt\x123\n" +
+	"\aends_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x06endsAt\x12!\n" +
+	"\fquantity_cap\x18\a \x01(\x03R\vquantityCap\x12#\n" +
+	"\rquantity_sold\x18\b \x01(\x03R\fquantitySold\x12\x18\n" +
+	"\arunning\x18\t \x01(\bR\arunning\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"G\n" +
+	"\x16ListScheduledPricesRes\x12-\n" +
+	"\x06prices\x18\x01 \x03(\v2\x15.pb.ScheduledPriceResR\x06prices\"F\n" +
+	"\fWarehouseReq\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\"\xbc\x01\n" +
+	"\fWarehouseRes\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x13\n" +
+	"\x11ListWarehousesReq\"E\n" +
+	"\x11ListWarehousesRes\x120\n" +
+	"\n" +
+	"warehouses\x18\x01 \x03(\v2\x10.pb.WarehouseResR\n" +
+	"warehouses\"\xf6\x01\n" +
+	"\bStockReq\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x02 \x01(\x03R\tvariantId\x12!\n" +
+	"\fwarehouse_id\x18\x03 \x01(\x03R\vwarehouseId\x12&\n" +
+	"\x0fto_warehouse_id\x18\x04 \x01(\x03R\rtoWarehouseId\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\x03R\bquantity\x12\x12\n" +
+	"\x04kind\x18\x06 \x01(\tR\x04kind\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reason\x12\x19\n" +
+	"\bactor_id\x18\b \x01(\x03R\aactorId\"\xa3\x01\n" +
+	"\n" +
+	"StockLevel\x12!\n" +
+	"\fwarehouse_id\x18\x01 \x01(\x03R\vwarehouseId\x12%\n" +
+	"\x0ewarehouse_code\x18\x02 \x01(\tR\rwarehouseCode\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x03 \x01(\x03R\tvariantId\x12\x10\n" +
+	"\x03sku\x18\x04 \x01(\tR\x03sku\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\x03R\bquantity\"w\n" +
+	"\bStockRes\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12$\n" +
+	"\x0ecount_in_stock\x18\x02 \x01(\x03R\fcountInStock\x12&\n" +
+	"\x06levels\x18\x03 \x03(\v2\x0e.pb.StockLevelR\x06levels\"\x8c\x01\n" +
+	"\x15ListStockMovementsReq\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12!\n" +
+	"\fwarehouse_id\x18\x02 \x01(\x03R\vwarehouseId\x12\x1b\n" +
+	"\tbefore_id\x18\x03 \x01(\x03R\bbeforeId\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x03R\x05limit\"\xe7\x02\n" +
+	"\rStockMovement\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12!\n" +
+	"\fwarehouse_id\x18\x02 \x01(\x03R\vwarehouseId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x03 \x01(\x03R\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x04 \x01(\x03R\tvariantId\x12\x12\n" +
+	"\x04kind\x18\x05 \x01(\tR\x04kind\x12\x1a\n" +
+	"\bquantity\x18\x06 \x01(\x03R\bquantity\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reason\x12\x19\n" +
+	"\bactor_id\x18\b \x01(\x03R\aactorId\x12\x19\n" +
+	"\border_id\x18\t \x01(\x03R\aorderId\x12,\n" +
+	"\x12other_warehouse_id\x18\n" +
+	" \x01(\x03R\x10otherWarehouseId\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"n\n" +
+	"\x15ListStockMovementsRes\x12/\n" +
+	"\tmovements\x18\x01 \x03(\v2\x11.pb.StockMovementR\tmovements\x12$\n" +
+	"\x0enext_before_id\x18\x02 \x01(\x03R\fnextBeforeId\"\xa8\x01\n" +
+	"\vCategoryReq\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04slug\x18\x02 \x01(\tR\x04slug\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12 \n" +
+	"\tparent_id\x18\x04 \x01(\x03H\x00R\bparentId\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"sort_order\x18\x05 \x01(\x03H\x01R\tsortOrder\x88\x01\x01B\f\n" +
+	"\n" +
+	"_parent_idB\r\n" +
+	"\v_sort_order\"\xa4\x02\n" +
+	"\vCategoryRes\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1b\n" +
+	"\tparent_id\x18\x02 \x01(\x03R\bparentId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x12\n" +
+	"\x04slug\x18\x04 \x01(\tR\x04slug\x12\x1d\n" +
+	"\n" +
+	"sort_order\x18\x05 \x01(\x03R\tsortOrder\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12+\n" +
+	"\bchildren\x18\b \x03(\v2\x0f.pb.CategoryResR\bchildren\"\x13\n" +
+	"\x11ListCategoriesReq\"D\n" +
+	"\x11ListCategoriesRes\x12/\n" +
+	"\n" +
+	"categories\x18\x01 \x03(\v2\x0f.pb.CategoryResR\n" +
+	"categories\"\xee\x02\n" +
+	"\x0fListProductsReq\x12\x16\n" +
+	"\x06cursor\x18\x01 \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12*\n" +
+	"\asort_by\x18\x03 \x01(\x0e2\x11.pb.ProductSortByR\x06sortBy\x12,\n" +
+	"\n" +
+	"sort_order\x18\x04 \x01(\x0e2\r.pb.SortOrderR\tsortOrder\x12\"\n" +
+	"\n" +
+	"min_rating\x18\b \x01(\x03H\x00R\tminRating\x88\x01\x01\x12\x19\n" +
+	"\bin_stock\x18\t \x01(\bR\ainStock\x12&\n" +
+	"\tmin_price\x18\n" +
+	" \x01(\v2\t.pb.MoneyR\bminPrice\x12&\n" +
+	"\tmax_price\x18\v \x01(\v2\t.pb.MoneyR\bmaxPrice\x12#\n" +
+	"\rcategory_slug\x18\f \x01(\tR\fcategorySlugB\r\n" +
+	"\v_min_ratingJ\x04\b\x05\x10\x06J\x04\b\x06\x10\aJ\x04\b\a\x10\b\"]\n" +
 	"\x0eListProductRes\x12*\n" +
-	"\bproducts\x18\x01 \x03(\v2\x0e.pb.ProductResR\bproducts\"\x86\x01\n" +
+	"\bproducts\x18\x01 \x03(\v2\x0e.pb.ProductResR\bproducts\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"?\n" +
+	"\x11SearchProductsReq\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"\xd7\x01\n" +
+	"\x10ProductSearchHit\x12(\n" +
+	"\aproduct\x18\x01 \x01(\v2\x0e.pb.ProductResR\aproduct\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\x12D\n" +
+	"\n" +
+	"highlights\x18\x03 \x03(\v2$.pb.ProductSearchHit.HighlightsEntryR\n" +
+	"highlights\x1a=\n" +
+	"\x0fHighlightsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xdf\x04\n" +
+	"\fPromotionReq\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\tR\x04code\x12%\n" +
+	"\x04type\x18\x04 \x01(\x0e2\x11.pb.PromotionTypeR\x04type\x12#\n" +
+	"\rdiscount_rate\x18\x05 \x01(\x03R\fdiscountRate\x122\n" +
+	"\x0fdiscount_amount\x18\x06 \x01(\v2\t.pb.MoneyR\x0ediscountAmount\x12!\n" +
+	"\fbuy_quantity\x18\a \x01(\x03R\vbuyQuantity\x12!\n" +
+	"\fget_quantity\x18\b \x01(\x03R\vgetQuantity\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\t \x01(\x03R\tproductId\x12\x1f\n" +
+	"\vcategory_id\x18\n" +
+	" \x01(\x03R\n" +
+	"categoryId\x12,\n" +
+	"\fmin_subtotal\x18\v \x01(\v2\t.pb.MoneyR\vminSubtotal\x127\n" +
+	"\tstarts_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\bstartsAt\x123\n" +
+	"\aends_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\x06endsAt\x12\x1f\n" +
+	"\vusage_limit\x18\x0e \x01(\x03R\n" +
+	"usageLimit\x12,\n" +
+	"\x12per_customer_limit\x18\x0f \x01(\x03R\x10perCustomerLimit\x12\x1b\n" +
+	"\x06active\x18\x10 \x01(\bH\x00R\x06active\x88\x01\x01B\t\n" +
+	"\a_active\"\xe4\x05\n" +
+	"\fPromotionRes\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\tR\x04code\x12%\n" +
+	"\x04type\x18\x04 \x01(\x0e2\x11.pb.PromotionTypeR\x04type\x12#\n" +
+	"\rdiscount_rate\x18\x05 \x01(\x03R\fdiscountRate\x122\n" +
+	"\x0fdiscount_amount\x18\x06 \x01(\v2\t.pb.MoneyR\x0ediscountAmount\x12!\n" +
+	"\fbuy_quantity\x18\a \x01(\x03R\vbuyQuantity\x12!\n" +
+	"\fget_quantity\x18\b \x01(\x03R\vgetQuantity\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\t \x01(\x03R\tproductId\x12\x1f\n" +
+	"\vcategory_id\x18\n" +
+	" \x01(\x03R\n" +
+	"categoryId\x12,\n" +
+	"\fmin_subtotal\x18\v \x01(\v2\t.pb.MoneyR\vminSubtotal\x127\n" +
+	"\tstarts_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\bstartsAt\x123\n" +
+	"\aends_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\x06endsAt\x12\x1f\n" +
+	"\vusage_limit\x18\x0e \x01(\x03R\n" +
+	"usageLimit\x12,\n" +
+	"\x12per_customer_limit\x18\x0f \x01(\x03R\x10perCustomerLimit\x12\x16\n" +
+	"\x06active\x18\x10 \x01(\bR\x06active\x12\x1d\n" +
+	"\n" +
+	"times_used\x18\x11 \x01(\x03R\ttimesUsed\x129\n" +
+	"\n" +
+	"created_at\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x13 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x13\n" +
+	"\x11ListPromotionsReq\"E\n" +
+	"\x11ListPromotionsRes\x120\n" +
+	"\n" +
+	"promotions\x18\x01 \x03(\v2\x10.pb.PromotionResR\n" +
+	"promotions\"\xb1\x01\n" +
+	"\n" +
+	"TaxRateReq\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
+	"\acountry\x18\x02 \x01(\tR\acountry\x12\x16\n" +
+	"\x06region\x18\x03 \x01(\tR\x06region\x12\x1b\n" +
+	"\ttax_class\x18\x04 \x01(\tR\btaxClass\x12\x12\n" +
+	"\x04name\x18\x05 \x01(\tR\x04name\x12\x12\n" +
+	"\x04rate\x18\x06 \x01(\x03R\x04rate\x12\x1c\n" +
+	"\tinclusive\x18\a \x01(\bR\tinclusive\"\xa7\x02\n" +
+	"\n" +
+	"TaxRateRes\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
+	"\acountry\x18\x02 \x01(\tR\acountry\x12\x16\n" +
+	"\x06region\x18\x03 \x01(\tR\x06region\x12\x1b\n" +
+	"\ttax_class\x18\x04 \x01(\tR\btaxClass\x12\x12\n" +
+	"\x04name\x18\x05 \x01(\tR\x04name\x12\x12\n" +
+	"\x04rate\x18\x06 \x01(\x03R\x04rate\x12\x1c\n" +
+	"\tinclusive\x18\a \x01(\bR\tinclusive\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x11\n" +
+	"\x0fListTaxRatesReq\">\n" +
+	"\x0fListTaxRatesRes\x12+\n" +
+	"\ttax_rates\x18\x01 \x03(\v2\x0e.pb.TaxRateResR\btaxRates\"\x91\x01\n" +
+	"\n" +
+	"AddressReq\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12\x14\n" +
+	"\x05label\x18\x03 \x01(\tR\x05label\x12%\n" +
+	"\aaddress\x18\x04 \x01(\v2\v.pb.AddressR\aaddress\x12\x1d\n" +
+	"\n" +
+	"is_default\x18\x05 \x01(\bR\tisDefault\"\x87\x02\n" +
+	"\n" +
+	"AddressRes\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12\x14\n" +
+	"\x05label\x18\x03 \x01(\tR\x05label\x12%\n" +
+	"\aaddress\x18\x04 \x01(\v2\v.pb.AddressR\aaddress\x12\x1d\n" +
+	"\n" +
+	"is_default\x18\x05 \x01(\bR\tisDefault\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"@\n" +
+	"\x10ListAddressesRes\x12,\n" +
+	"\taddresses\x18\x01 \x03(\v2\x0e.pb.AddressResR\taddresses\"|\n" +
+	"\x0fShippingZoneReq\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1c\n" +
+	"\tcountries\x18\x03 \x03(\tR\tcountries\x12'\n" +
+	"\x0fpostal_prefixes\x18\x04 \x03(\tR\x0epostalPrefixes\"\xa3\x02\n" +
+	"\x0fShippingZoneRes\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1c\n" +
+	"\tcountries\x18\x03 \x03(\tR\tcountries\x12'\n" +
+	"\x0fpostal_prefixes\x18\x04 \x03(\tR\x0epostalPrefixes\x12/\n" +
+	"\amethods\x18\x05 \x03(\v2\x15.pb.ShippingMethodResR\amethods\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x16\n" +
+	"\x14ListShippingZonesReq\"A\n" +
+	"\x14ListShippingZonesRes\x12)\n" +
+	"\x05zones\x18\x01 \x03(\v2\x13.pb.ShippingZoneResR\x05zones\"A\n" +
+	"\fShippingTier\x12\x10\n" +
+	"\x03min\x18\x01 \x01(\x03R\x03min\x12\x1f\n" +
+	"\x05price\x18\x02 \x01(\v2\t.pb.MoneyR\x05price\"\xf3\x01\n" +
+	"\x11ShippingMethodReq\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\azone_id\x18\x02 \x01(\x03R\x06zoneId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12'\n" +
+	"\x05basis\x18\x04 \x01(\x0e2\x11.pb.ShippingBasisR\x05basis\x12&\n" +
+	"\x05tiers\x18\x05 \x03(\v2\x10.pb.ShippingTierR\x05tiers\x12(\n" +
+	"\n" +
+	"free_above\x18\x06 \x01(\v2\t.pb.MoneyR\tfreeAbove\x12\x1b\n" +
+	"\x06active\x18\a \x01(\bH\x00R\x06active\x88\x01\x01B\t\n" +
+	"\a_active\"\xd9\x02\n" +
+	"\x11ShippingMethodRes\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\azone_id\x18\x02 \x01(\x03R\x06zoneId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12'\n" +
+	"\x05basis\x18\x04 \x01(\x0e2\x11.pb.ShippingBasisR\x05basis\x12&\n" +
+	"\x05tiers\x18\x05 \x03(\v2\x10.pb.ShippingTierR\x05tiers\x12(\n" +
+	"\n" +
+	"free_above\x18\x06 \x01(\v2\t.pb.MoneyR\tfreeAbove\x12\x16\n" +
+	"\x06active\x18\a \x01(\bR\x06active\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"q\n" +
+	"\x10ShippingQuoteReq\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1d\n" +
+	"\n" +
+	"address_id\x18\x02 \x01(\x03R\taddressId\x12%\n" +
+	"\aaddress\x18\x03 \x01(\v2\v.pb.AddressR\aaddress\"v\n" +
+	"\x0eShippingOption\x12\x1b\n" +
+	"\tmethod_id\x18\x01 \x01(\x03R\bmethodId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04zone\x18\x03 \x01(\tR\x04zone\x12\x1f\n" +
+	"\x05price\x18\x04 \x01(\v2\t.pb.MoneyR\x05price\"g\n" +
+	"\x10ShippingQuoteRes\x12%\n" +
+	"\aaddress\x18\x01 \x01(\v2\v.pb.AddressR\aaddress\x12,\n" +
+	"\aoptions\x18\x02 \x03(\v2\x12.pb.ShippingOptionR\aoptions\"=\n" +
+	"\x11SearchProductsRes\x12(\n" +
+	"\x04hits\x18\x01 \x03(\v2\x14.pb.ProductSearchHitR\x04hits\"\x9b\x03\n" +
 	"\tOrderItem\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
 	"\bquantity\x18\x02 \x01(\x03R\bquantity\x12\x14\n" +
-	"\x05image\x18\x03 \x01(\tR\x05image\x12\x14\n" +
-	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x1d\n" +
+	"\x05image\x18\x03 \x01(\tR\x05image\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x05 \x01(\x03R\tproductId\x12\x1f\n" +
+	"\x05price\x18\a \x01(\v2\t.pb.MoneyR\x05price\x12(\n" +
 	"\n" +
-	"product_id\x18\x05 \x01(\x03R\tproductId\"\xac\x02\n" +
+	"line_total\x18\b \x01(\v2\t.pb.MoneyR\tlineTotal\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\t \x01(\x03R\tvariantId\x12\x10\n" +
+	"\x03sku\x18\n" +
+	" \x01(\tR\x03sku\x12\x19\n" +
+	"\btax_rate\x18\v \x01(\x03R\ataxRate\x12)\n" +
+	"\x10tax_jurisdiction\x18\f \x01(\tR\x0ftaxJurisdiction\x12&\n" +
+	"\ttax_price\x18\r \x01(\v2\t.pb.MoneyR\btaxPrice\x12#\n" +
+	"\rtax_inclusive\x18\x0e \x01(\bR\ftaxInclusive\x12\x0e\n" +
+	"\x02id\x18\x0f \x01(\x03R\x02idJ\x04\b\x04\x10\x05J\x04\b\x06\x10\a\"\xb0\x01\n" +
+	"\aAddress\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05line1\x18\x02 \x01(\tR\x05line1\x12\x14\n" +
+	"\x05line2\x18\x03 \x01(\tR\x05line2\x12\x12\n" +
+	"\x04city\x18\x04 \x01(\tR\x04city\x12\x16\n" +
+	"\x06region\x18\x05 \x01(\tR\x06region\x12\x1f\n" +
+	"\vpostal_code\x18\x06 \x01(\tR\n" +
+	"postalCode\x12\x18\n" +
+	"\acountry\x18\a \x01(\tR\acountry\"\x91\a\n" +
 	"\bOrderReq\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12#\n" +
 	"\x05items\x18\x02 \x03(\v2\r.pb.OrderItemR\x05items\x12%\n" +
-	"\x0epayment_method\x18\x03 \x01(\tR\rpaymentMethod\x12\x1b\n" +
-	"\ttax_price\x18\x04 \x01(\x01R\btaxPrice\x12%\n" +
-	"\x0eshipping_price\x18\x05 \x01(\x01R\rshippingPrice\x12\x1f\n" +
-	"\vtotal_price\x18\x06 \x01(\x01R\n" +
-	"totalPrice\x12\x17\n" +
+	"\x0epayment_method\x18\x03 \x01(\tR\rpaymentMethod\x12\x17\n" +
 	"\auser_id\x18\a \x01(\x03R\x06userId\x12\x1d\n" +
 	"\n" +
 	"user_email\x18\b \x01(\tR\tuserEmail\x12'\n" +
-	"\x06status\x18\t \x01(\x0e2\x0f.pb.OrderStatusR\x06status\"\x83\x03\n" +
+	"\x06status\x18\t \x01(\x0e2\x0f.pb.OrderStatusR\x06status\x12\x16\n" +
+	"\x06reason\x18\n" +
+	" \x01(\tR\x06reason\x12\x19\n" +
+	"\bis_admin\x18\v \x01(\bR\aisAdmin\x12&\n" +
+	"\ttax_price\x18\f \x01(\v2\t.pb.MoneyR\btaxPrice\x120\n" +
+	"\x0eshipping_price\x18\r \x01(\v2\t.pb.MoneyR\rshippingPrice\x12*\n" +
+	"\vtotal_price\x18\x0e \x01(\v2\t.pb.MoneyR\n" +
+	"totalPrice\x12\x1f\n" +
+	"\vcoupon_code\x18\x0f \x01(\tR\n" +
+	"couponCode\x120\n" +
+	"\x0ediscount_price\x18\x10 \x01(\v2\t.pb.MoneyR\rdiscountPrice\x126\n" +
+	"\x10shipping_address\x18\x11 \x01(\v2\v.pb.AddressR\x0fshippingAddress\x12.\n" +
+	"\x13shipping_address_id\x18\x12 \x01(\x03R\x11shippingAddressId\x124\n" +
+	"\x0fbilling_address\x18\x13 \x01(\v2\v.pb.AddressR\x0ebillingAddress\x12,\n" +
+	"\x12billing_address_id\x18\x14 \x01(\x03R\x10billingAddressId\x12,\n" +
+	"\x12shipping_method_id\x18\x15 \x01(\x03R\x10shippingMethodId\x12&\n" +
+	"\x0fgift_card_codes\x18\x16 \x03(\tR\rgiftCardCodes\x12,\n" +
+	"\fstore_credit\x18\x17 \x01(\v2\t.pb.MoneyR\vstoreCredit\x123\n" +
+	"\x16refund_to_store_credit\x18\x18 \x01(\bR\x13refundToStoreCredit\x12%\n" +
+	"\x0eloyalty_points\x18\x19 \x01(\x03R\rloyaltyPointsJ\x04\b\x04\x10\x05J\x04\b\x05\x10\x06J\x04\b\x06\x10\a\"\xad\x06\n" +
 	"\bOrderRes\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12#\n" +
 	"\x05items\x18\x02 \x03(\v2\r.pb.OrderItemR\x05items\x12%\n" +
-	"\x0epayment_method\x18\x03 \x01(\tR\rpaymentMethod\x12\x1b\n" +
-	"\ttax_price\x18\x04 \x01(\x01R\btaxPrice\x12%\n" +
-	"\x0eshipping_price\x18\x05 \x01(\x01R\rshippingPrice\x12\x1f\n" +
-	"\vtotal_price\x18\x06 \x01(\x01R\n" +
-	"totalPrice\x12\x17\n" +
+	"\x0epayment_method\x18\x03 \x01(\tR\rpaymentMethod\x12\x17\n" +
 	"\auser_id\x18\a \x01(\x03R\x06userId\x129\n" +
 	"\n" +
 	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
 	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12'\n" +
 	"\x06status\x18\n" +
-	" \x01(\x0e2\x0f.pb.OrderStatusR\x06status\"4\n" +
+	" \x01(\x0e2\x0f.pb.OrderStatusR\x06status\x12&\n" +
+	"\ttax_price\x18\f \x01(\v2\t.pb.MoneyR\btaxPrice\x120\n" +
+	"\x0eshipping_price\x18\r \x01(\v2\t.pb.MoneyR\rshippingPrice\x12*\n" +
+	"\vtotal_price\x18\x0e \x01(\v2\t.pb.MoneyR\n" +
+	"totalPrice\x12*\n" +
+	"\vitems_price\x18\x0f \x01(\v2\t.pb.MoneyR\n" +
+	"itemsPrice\x120\n" +
+	"\x0ediscount_price\x18\x10 \x01(\v2\t.pb.MoneyR\rdiscountPrice\x12\x1f\n" +
+	"\vcoupon_code\x18\x11 \x01(\tR\n" +
+	"couponCode\x12/\n" +
+	"\tdiscounts\x18\x12 \x03(\v2\x11.pb.OrderDiscountR\tdiscounts\x126\n" +
+	"\x10shipping_address\x18\x13 \x01(\v2\v.pb.AddressR\x0fshippingAddress\x124\n" +
+	"\x0fbilling_address\x18\x14 \x01(\v2\v.pb.AddressR\x0ebillingAddress\x12'\n" +
+	"\x0fshipping_method\x18\x15 \x01(\tR\x0eshippingMethod\x12(\n" +
+	"\n" +
+	"amount_due\x18\x16 \x01(\v2\t.pb.MoneyR\tamountDueJ\x04\b\x04\x10\x05J\x04\b\x05\x10\x06J\x04\b\x06\x10\aJ\x04\b\v\x10\f\"}\n" +
+	"\rOrderDiscount\x12!\n" +
+	"\fpromotion_id\x18\x01 \x01(\x03R\vpromotionId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\tR\x04code\x12!\n" +
+	"\x06amount\x18\x04 \x01(\v2\t.pb.MoneyR\x06amount\"U\n" +
 	"\fListOrderRes\x12$\n" +
-	"\x06orders\x18\x01 \x03(\v2\f.pb.OrderResR\x06orders\"z\n" +
+	"\x06orders\x18\x01 \x03(\v2\f.pb.OrderResR\x06orders\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"\xef\x01\n" +
+	"\x11ListUserOrdersReq\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x16\n" +
+	"\x06cursor\x18\x02 \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12,\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x0f.pb.OrderStatusH\x00R\x06status\x88\x01\x01\x12.\n" +
+	"\x04from\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x02toB\t\n" +
+	"\a_status\"\xab\x02\n" +
+	"\x11OrderStatusChange\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\x03R\aorderId\x120\n" +
+	"\vfrom_status\x18\x03 \x01(\x0e2\x0f.pb.OrderStatusR\n" +
+	"fromStatus\x12,\n" +
+	"\tto_status\x18\x04 \x01(\x0e2\x0f.pb.OrderStatusR\btoStatus\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12\x19\n" +
+	"\bactor_id\x18\x06 \x01(\x03R\aactorId\x12\x1d\n" +
+	"\n" +
+	"actor_role\x18\a \x01(\tR\tactorRole\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"L\n" +
+	"\x19ListOrderStatusChangesRes\x12/\n" +
+	"\achanges\x18\x01 \x03(\v2\x15.pb.OrderStatusChangeR\achanges\"s\n" +
+	"\n" +
+	"PaymentReq\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12\x19\n" +
+	"\bis_admin\x18\x03 \x01(\bR\aisAdmin\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\"\xf0\x02\n" +
+	"\n" +
+	"PaymentRes\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\x03R\aorderId\x12\x1a\n" +
+	"\bprovider\x18\x03 \x01(\tR\bprovider\x12\x1c\n" +
+	"\treference\x18\x04 \x01(\tR\treference\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12!\n" +
+	"\x06amount\x18\x06 \x01(\v2\t.pb.MoneyR\x06amount\x12\x18\n" +
+	"\amessage\x18\a \x01(\tR\amessage\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x122\n" +
+	"\x0frefunded_amount\x18\n" +
+	" \x01(\v2\t.pb.MoneyR\x0erefundedAmount\"=\n" +
+	"\x0fListPaymentsRes\x12*\n" +
+	"\bpayments\x18\x01 \x03(\v2\x0e.pb.PaymentResR\bpayments\"\xbb\x02\n" +
+	"\vInvoiceLine\x12 \n" +
+	"\vdescription\x18\x01 \x01(\tR\vdescription\x12\x10\n" +
+	"\x03sku\x18\x02 \x01(\tR\x03sku\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x03R\bquantity\x12(\n" +
+	"\n" +
+	"unit_price\x18\x04 \x01(\v2\t.pb.MoneyR\tunitPrice\x12\x19\n" +
+	"\btax_rate\x18\x05 \x01(\x03R\ataxRate\x12)\n" +
+	"\x10tax_jurisdiction\x18\x06 \x01(\tR\x0ftaxJurisdiction\x12&\n" +
+	"\ttax_price\x18\a \x01(\v2\t.pb.MoneyR\btaxPrice\x12#\n" +
+	"\rtax_inclusive\x18\b \x01(\bR\ftaxInclusive\x12\x1f\n" +
+	"\x05total\x18\t \x01(\v2\t.pb.MoneyR\x05total\"c\n" +
+	"\x06Seller\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aaddress\x18\x02 \x03(\tR\aaddress\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x15\n" +
+	"\x06tax_id\x18\x04 \x01(\tR\x05taxId\"\xaf\x03\n" +
+	"\n" +
+	"CreditNote\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x16\n" +
+	"\x06number\x18\x02 \x01(\tR\x06number\x12\x1d\n" +
+	"\n" +
+	"invoice_id\x18\x03 \x01(\x03R\tinvoiceId\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12%\n" +
+	"\x05lines\x18\x05 \x03(\v2\x0f.pb.InvoiceLineR\x05lines\x12*\n" +
+	"\vitems_price\x18\x06 \x01(\v2\t.pb.MoneyR\n" +
+	"itemsPrice\x120\n" +
+	"\x0ediscount_price\x18\a \x01(\v2\t.pb.MoneyR\rdiscountPrice\x120\n" +
+	"\x0eshipping_price\x18\b \x01(\v2\t.pb.MoneyR\rshippingPrice\x12&\n" +
+	"\ttax_price\x18\t \x01(\v2\t.pb.MoneyR\btaxPrice\x12*\n" +
+	"\vtotal_price\x18\n" +
+	" \x01(\v2\t.pb.MoneyR\n" +
+	"totalPrice\x127\n" +
+	"\tissued_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\bissuedAt\"\xb1\x05\n" +
+	"\n" +
+	"InvoiceRes\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x16\n" +
+	"\x06number\x18\x02 \x01(\tR\x06number\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\x03R\aorderId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\x03R\x06userId\x12\"\n" +
+	"\x06seller\x18\x05 \x01(\v2\n" +
+	".pb.SellerR\x06seller\x12\x1d\n" +
+	"\n" +
+	"buyer_name\x18\x06 \x01(\tR\tbuyerName\x12\x1f\n" +
+	"\vbuyer_email\x18\a \x01(\tR\n" +
+	"buyerEmail\x124\n" +
+	"\x0fbilling_address\x18\b \x01(\v2\v.pb.AddressR\x0ebillingAddress\x126\n" +
+	"\x10shipping_address\x18\t \x01(\v2\v.pb.AddressR\x0fshippingAddress\x12%\n" +
+	"\x05lines\x18\n" +
+	" \x03(\v2\x0f.pb.InvoiceLineR\x05lines\x12*\n" +
+	"\vitems_price\x18\v \x01(\v2\t.pb.MoneyR\n" +
+	"itemsPrice\x120\n" +
+	"\x0ediscount_price\x18\f \x01(\v2\t.pb.MoneyR\rdiscountPrice\x120\n" +
+	"\x0eshipping_price\x18\r \x01(\v2\t.pb.MoneyR\rshippingPrice\x12&\n" +
+	"\ttax_price\x18\x0e \x01(\v2\t.pb.MoneyR\btaxPrice\x12*\n" +
+	"\vtotal_price\x18\x0f \x01(\v2\t.pb.MoneyR\n" +
+	"totalPrice\x127\n" +
+	"\tissued_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\bissuedAt\x121\n" +
+	"\fcredit_notes\x18\x11 \x03(\v2\x0e.pb.CreditNoteR\vcreditNotes\"\xdc\x02\n" +
+	"\tReturnReq\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\x03R\aorderId\x12\"\n" +
+	"\rorder_item_id\x18\x03 \x01(\x03R\vorderItemId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\x03R\x06userId\x12\x19\n" +
+	"\bis_admin\x18\x05 \x01(\bR\aisAdmin\x12\x1a\n" +
+	"\bquantity\x18\x06 \x01(\x03R\bquantity\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"photos_url\x18\b \x01(\tR\tphotosUrl\x12\x16\n" +
+	"\x06status\x18\t \x01(\tR\x06status\x12\x12\n" +
+	"\x04note\x18\n" +
+	" \x01(\tR\x04note\x12\x18\n" +
+	"\arestock\x18\v \x01(\bR\arestock\x123\n" +
+	"\x16refund_to_store_credit\x18\f \x01(\bR\x13refundToStoreCredit\"\xb6\x03\n" +
+	"\tReturnRes\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\x03R\aorderId\x12\"\n" +
+	"\rorder_item_id\x18\x03 \x01(\x03R\vorderItemId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\x03R\x06userId\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\x03R\bquantity\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"photos_url\x18\a \x01(\tR\tphotosUrl\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\x12\x12\n" +
+	"\x04note\x18\t \x01(\tR\x04note\x12\x1c\n" +
+	"\trestocked\x18\n" +
+	" \x01(\bR\trestocked\x12.\n" +
+	"\rrefund_amount\x18\v \x01(\v2\t.pb.MoneyR\frefundAmount\x129\n" +
+	"\n" +
+	"created_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"(\n" +
+	"\x0eListReturnsReq\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\"9\n" +
+	"\x0eListReturnsRes\x12'\n" +
+	"\areturns\x18\x01 \x03(\v2\r.pb.ReturnResR\areturns\"\xdf\x01\n" +
+	"\vGiftCardReq\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12!\n" +
+	"\x06amount\x18\x02 \x01(\v2\t.pb.MoneyR\x06amount\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x12\n" +
+	"\x04note\x18\x04 \x01(\tR\x04note\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12\x17\n" +
+	"\auser_id\x18\x06 \x01(\x03R\x06userId\x12\x19\n" +
+	"\bis_admin\x18\a \x01(\bR\aisAdmin\"\xe5\x01\n" +
+	"\x13GiftCardTransaction\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12!\n" +
+	"\x06amount\x18\x03 \x01(\v2\t.pb.MoneyR\x06amount\x12\x19\n" +
+	"\border_id\x18\x04 \x01(\x03R\aorderId\x12\x19\n" +
+	"\bactor_id\x18\x05 \x01(\x03R\aactorId\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xff\x03\n" +
+	"\vGiftCardRes\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x120\n" +
+	"\x0einitial_amount\x18\x03 \x01(\v2\t.pb.MoneyR\rinitialAmount\x12#\n" +
+	"\abalance\x18\x04 \x01(\v2\t.pb.MoneyR\abalance\x129\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x18\n" +
+	"\aexpired\x18\x06 \x01(\bR\aexpired\x12!\n" +
+	"\fpurchaser_id\x18\a \x01(\x03R\vpurchaserId\x12\x19\n" +
+	"\border_id\x18\b \x01(\x03R\aorderId\x12\x1b\n" +
+	"\tissued_by\x18\t \x01(\x03R\bissuedBy\x12\x12\n" +
+	"\x04note\x18\n" +
+	" \x01(\tR\x04note\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12;\n" +
+	"\ftransactions\x18\r \x03(\v2\x17.pb.GiftCardTransactionR\ftransactions\"B\n" +
+	"\x10ListGiftCardsRes\x12.\n" +
+	"\n" +
+	"gift_cards\x18\x01 \x03(\v2\x0f.pb.GiftCardResR\tgiftCards\"\x7f\n" +
+	"\x0eStoreCreditReq\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12!\n" +
+	"\x06amount\x18\x02 \x01(\v2\t.pb.MoneyR\x06amount\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x19\n" +
+	"\bactor_id\x18\x04 \x01(\x03R\aactorId\"\xe8\x01\n" +
+	"\x16StoreCreditTransaction\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12!\n" +
+	"\x06amount\x18\x03 \x01(\v2\t.pb.MoneyR\x06amount\x12\x19\n" +
+	"\border_id\x18\x04 \x01(\x03R\aorderId\x12\x19\n" +
+	"\bactor_id\x18\x05 \x01(\x03R\aactorId\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x8e\x01\n" +
+	"\x0eStoreCreditRes\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12#\n" +
+	"\abalance\x18\x02 \x01(\v2\t.pb.MoneyR\abalance\x12>\n" +
+	"\ftransactions\x18\x03 \x03(\v2\x1a.pb.StoreCreditTransactionR\ftransactions\"%\n" +
+	"\n" +
+	"LoyaltyReq\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\"\xf9\x01\n" +
+	"\x12LoyaltyTransaction\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x16\n" +
+	"\x06points\x18\x03 \x01(\x03R\x06points\x12\x19\n" +
+	"\border_id\x18\x04 \x01(\x03R\aorderId\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xf2\x02\n" +
+	"\n" +
+	"LoyaltyRes\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x16\n" +
+	"\x06points\x18\x02 \x01(\x03R\x06points\x12,\n" +
+	"\fpoints_value\x18\x03 \x01(\v2\t.pb.MoneyR\vpointsValue\x12\x12\n" +
+	"\x04tier\x18\x04 \x01(\tR\x04tier\x12(\n" +
+	"\n" +
+	"year_spend\x18\x05 \x01(\v2\t.pb.MoneyR\tyearSpend\x12\x1b\n" +
+	"\tnext_tier\x18\x06 \x01(\tR\bnextTier\x121\n" +
+	"\x0fnext_tier_spend\x18\a \x01(\v2\t.pb.MoneyR\rnextTierSpend\x12\x1b\n" +
+	"\tearn_rate\x18\b \x01(\x03R\bearnRate\x12\x1e\n" +
+	"\n" +
+	"multiplier\x18\t \x01(\x03R\n" +
+	"multiplier\x12:\n" +
+	"\ftransactions\x18\n" +
+	" \x03(\v2\x16.pb.LoyaltyTransactionR\ftransactions\"}\n" +
+	"\x0fPaymentEventReq\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x1c\n" +
+	"\treference\x18\x02 \x01(\tR\treference\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\"\xc8\x01\n" +
+	"\tReviewReq\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\x03R\x06userId\x12\x16\n" +
+	"\x06rating\x18\x04 \x01(\x03R\x06rating\x12\x14\n" +
+	"\x05title\x18\x05 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\x06 \x01(\tR\x04body\x12\x19\n" +
+	"\bis_admin\x18\a \x01(\bR\aisAdmin\x12\x16\n" +
+	"\x06hidden\x18\b \x01(\bR\x06hidden\"\xa3\x02\n" +
+	"\tReviewRes\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\x03R\x06userId\x12\x16\n" +
+	"\x06rating\x18\x04 \x01(\x03R\x06rating\x12\x14\n" +
+	"\x05title\x18\x05 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\x06 \x01(\tR\x04body\x12\x16\n" +
+	"\x06hidden\x18\a \x01(\bR\x06hidden\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x84\x01\n" +
+	"\x0eListReviewsReq\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x16\n" +
+	"\x06cursor\x18\x02 \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12%\n" +
+	"\x0einclude_hidden\x18\x04 \x01(\bR\rincludeHidden\"Z\n" +
+	"\x0eListReviewsRes\x12'\n" +
+	"\areviews\x18\x01 \x03(\v2\r.pb.ReviewResR\areviews\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"|\n" +
+	"\aCartReq\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x03R\bquantity\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x04 \x01(\x03R\tvariantId\"\xe4\x02\n" +
+	"\bCartItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x03R\bquantity\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x14\n" +
+	"\x05image\x18\x04 \x01(\tR\x05image\x12\x1f\n" +
+	"\x05price\x18\x05 \x01(\v2\t.pb.MoneyR\x05price\x12(\n" +
+	"\n" +
+	"line_total\x18\x06 \x01(\v2\t.pb.MoneyR\tlineTotal\x12$\n" +
+	"\x0ecount_in_stock\x18\a \x01(\x03R\fcountInStock\x12\x1c\n" +
+	"\tavailable\x18\b \x01(\bR\tavailable\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\t \x01(\x03R\tvariantId\x12\x10\n" +
+	"\x03sku\x18\n" +
+	" \x01(\tR\x03sku\x123\n" +
+	"\x10compare_at_price\x18\v \x01(\v2\t.pb.MoneyR\x0ecompareAtPrice\"\xad\x01\n" +
+	"\aCartRes\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\"\n" +
+	"\x05items\x18\x02 \x03(\v2\f.pb.CartItemR\x05items\x12*\n" +
+	"\vitems_price\x18\x03 \x01(\v2\t.pb.MoneyR\n" +
+	"itemsPrice\x129\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xb4\x04\n" +
+	"\x0fCheckoutCartReq\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1d\n" +
+	"\n" +
+	"user_email\x18\x02 \x01(\tR\tuserEmail\x12%\n" +
+	"\x0epayment_method\x18\x03 \x01(\tR\rpaymentMethod\x12*\n" +
+	"\vtotal_price\x18\x04 \x01(\v2\t.pb.MoneyR\n" +
+	"totalPrice\x12\x1f\n" +
+	"\vcoupon_code\x18\x05 \x01(\tR\n" +
+	"couponCode\x126\n" +
+	"\x10shipping_address\x18\x06 \x01(\v2\v.pb.AddressR\x0fshippingAddress\x12.\n" +
+	"\x13shipping_address_id\x18\a \x01(\x03R\x11shippingAddressId\x124\n" +
+	"\x0fbilling_address\x18\b \x01(\v2\v.pb.AddressR\x0ebillingAddress\x12,\n" +
+	"\x12billing_address_id\x18\t \x01(\x03R\x10billingAddressId\x12,\n" +
+	"\x12shipping_method_id\x18\n" +
+	" \x01(\x03R\x10shippingMethodId\x12&\n" +
+	"\x0fgift_card_codes\x18\v \x03(\tR\rgiftCardCodes\x12,\n" +
+	"\fstore_credit\x18\f \x01(\v2\t.pb.MoneyR\vstoreCredit\x12%\n" +
+	"\x0eloyalty_points\x18\r \x01(\x03R\rloyaltyPoints\"r\n" +
+	"\vWishlistReq\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1b\n" +
+	"\x06shared\x18\x04 \x01(\bH\x00R\x06shared\x88\x01\x01B\t\n" +
+	"\a_shared\"\xa5\x01\n" +
+	"\x0fWishlistItemReq\x12\x1f\n" +
+	"\vwishlist_id\x18\x01 \x01(\x03R\n" +
+	"wishlistId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x03 \x01(\x03R\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x04 \x01(\x03R\tvariantId\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\x03R\bquantity\"\xd9\x02\n" +
+	"\fWishlistItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x02 \x01(\x03R\tvariantId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x14\n" +
+	"\x05image\x18\x04 \x01(\tR\x05image\x12\x10\n" +
+	"\x03sku\x18\x05 \x01(\tR\x03sku\x12\x1f\n" +
+	"\x05price\x18\x06 \x01(\v2\t.pb.MoneyR\x05price\x12$\n" +
+	"\x0ecount_in_stock\x18\a \x01(\x03R\fcountInStock\x12\x1c\n" +
+	"\tavailable\x18\b \x01(\bR\tavailable\x125\n" +
+	"\badded_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\aaddedAt\x123\n" +
+	"\x10compare_at_price\x18\n" +
+	" \x01(\v2\t.pb.MoneyR\x0ecompareAtPrice\"\x89\x02\n" +
+	"\vWishlistRes\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1f\n" +
+	"\vshare_token\x18\x04 \x01(\tR\n" +
+	"shareToken\x12&\n" +
+	"\x05items\x18\x05 \x03(\v2\x10.pb.WishlistItemR\x05items\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"A\n" +
+	"\x10ListWishlistsRes\x12-\n" +
+	"\twishlists\x18\x01 \x03(\v2\x0f.pb.WishlistResR\twishlists\"4\n" +
+	"\x11SharedWishlistReq\x12\x1f\n" +
+	"\vshare_token\x18\x01 \x01(\tR\n" +
+	"shareToken\"\x99\x01\n" +
 	"\aUserReq\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
 	"\x05email\x18\x03 \x01(\tR\x05email\x12\x1a\n" +
 	"\bpassword\x18\x04 \x01(\tR\bpassword\x12\x19\n" +
-	"\bis_admin\x18\x05 \x01(\bR\aisAdmin\"\xb5\x01\n" +
+	"\bis_admin\x18\x05 \x01(\bR\aisAdmin\x12\x1d\n" +
+	"\n" +
+	"tax_exempt\x18\x06 \x01(\bR\ttaxExempt\"\xd4\x01\n" +
 	"\aUserRes\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
@@ -1462,7 +9855,9 @@ const file_api_proto_rawDesc = "" +
 	"\bpassword\x18\x04 \x01(\tR\bpassword\x12\x19\n" +
 	"\bis_admin\x18\x05 \x01(\bR\aisAdmin\x129\n" +
 	"\n" +
-	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"0\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"tax_exempt\x18\a \x01(\bR\ttaxExempt\"0\n" +
 	"\vListUserRes\x12!\n" +
 	"\x05users\x18\x01 \x03(\v2\v.pb.UserResR\x05users\"\xba\x01\n" +
 	"\n" +
@@ -1484,7 +9879,7 @@ const file_api_proto_rawDesc = "" +
 	"\n" +
 	"is_revoked\x18\x04 \x01(\bR\tisRevoked\x129\n" +
 	"\n" +
-	"expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"\xc8\x01\n" +
+	"expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"\x8a\x02\n" +
 	"\x11NotificationEvent\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
 	"\n" +
@@ -1492,8 +9887,13 @@ const file_api_proto_rawDesc = "" +
 	"\forder_status\x18\x03 \x01(\x0e2\x0f.pb.OrderStatusR\vorderStatus\x12\x19\n" +
 	"\border_id\x18\x04 \x01(\x03R\aorderId\x12\x19\n" +
 	"\bstate_id\x18\x05 \x01(\x03R\astateId\x12\x1a\n" +
-	"\battempts\x18\x06 \x01(\x03R\battempts\"\x1b\n" +
-	"\x19ListNotificationEventsReq\"J\n" +
+	"\battempts\x18\x06 \x01(\x03R\battempts\x12\x1b\n" +
+	"\treturn_id\x18\a \x01(\x03R\breturnId\x12#\n" +
+	"\rreturn_status\x18\b \x01(\tR\freturnStatus\"\x1b\n" +
+	"\x19ListNotificationEventsReq\"W\n" +
+	"\x1aClaimNotificationEventsReq\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12#\n" +
+	"\rlease_seconds\x18\x02 \x01(\x03R\fleaseSeconds\"J\n" +
 	"\x19ListNotificationEventsRes\x12-\n" +
 	"\x06events\x18\x01 \x03(\v2\x15.pb.NotificationEventR\x06events\"\xbf\x01\n" +
 	"\x1aUpdateNotificationEventReq\x12\x0e\n" +
@@ -1503,27 +9903,132 @@ const file_api_proto_rawDesc = "" +
 	"\rresponse_type\x18\x04 \x01(\x0e2\x1c.pb.NotificationResponseTypeR\fresponseType\x12\x18\n" +
 	"\amessage\x18\x05 \x01(\tR\amessage\":\n" +
 	"\x1aUpdateNotificationEventRes\x12\x1c\n" +
-	"\tsucceeded\x18\x01 \x01(\bR\tsucceeded*6\n" +
+	"\tsucceeded\x18\x01 \x01(\bR\tsucceeded*@\n" +
+	"\rProductSortBy\x12\x0e\n" +
+	"\n" +
+	"CREATED_AT\x10\x00\x12\t\n" +
+	"\x05PRICE\x10\x01\x12\n" +
+	"\n" +
+	"\x06RATING\x10\x02\x12\b\n" +
+	"\x04NAME\x10\x03*\x1e\n" +
+	"\tSortOrder\x12\a\n" +
+	"\x03ASC\x10\x00\x12\b\n" +
+	"\x04DESC\x10\x01*U\n" +
+	"\rPromotionType\x12\x0e\n" +
+	"\n" +
+	"PERCENTAGE\x10\x00\x12\x10\n" +
+	"\fFIXED_AMOUNT\x10\x01\x12\x11\n" +
+	"\rFREE_SHIPPING\x10\x02\x12\x0f\n" +
+	"\vBUY_X_GET_Y\x10\x03*)\n" +
+	"\rShippingBasis\x12\f\n" +
+	"\bSUBTOTAL\x10\x00\x12\n" +
+	"\n" +
+	"\x06WEIGHT\x10\x01*m\n" +
 	"\vOrderStatus\x12\v\n" +
 	"\aPENDING\x10\x00\x12\v\n" +
 	"\aSHIPPED\x10\x01\x12\r\n" +
-	"\tDELIVERED\x10\x02*4\n" +
+	"\tDELIVERED\x10\x02\x12\r\n" +
+	"\tCANCELLED\x10\x03\x12\b\n" +
+	"\x04PAID\x10\x04\x12\x0e\n" +
+	"\n" +
+	"PROCESSING\x10\x05\x12\f\n" +
+	"\bREFUNDED\x10\x06*4\n" +
 	"\x18NotificationResponseType\x12\v\n" +
 	"\aSUCCESS\x10\x00\x12\v\n" +
-	"\aFAILURE\x10\x012\xbe\b\n" +
+	"\aFAILURE\x10\x012\xea,\n" +
 	"\x04ecom\x121\n" +
 	"\rCreateProduct\x12\x0e.pb.ProductReq\x1a\x0e.pb.ProductRes\"\x00\x12.\n" +
 	"\n" +
-	"GetProduct\x12\x0e.pb.ProductReq\x1a\x0e.pb.ProductRes\"\x00\x124\n" +
-	"\fListProducts\x12\x0e.pb.ProductReq\x1a\x12.pb.ListProductRes\"\x00\x121\n" +
+	"GetProduct\x12\x0e.pb.ProductReq\x1a\x0e.pb.ProductRes\"\x00\x129\n" +
+	"\fListProducts\x12\x13.pb.ListProductsReq\x1a\x12.pb.ListProductRes\"\x00\x12@\n" +
+	"\x0eSearchProducts\x12\x15.pb.SearchProductsReq\x1a\x15.pb.SearchProductsRes\"\x00\x121\n" +
 	"\rUpdateProduct\x12\x0e.pb.ProductReq\x1a\x0e.pb.ProductRes\"\x00\x121\n" +
-	"\rDeleteProduct\x12\x0e.pb.ProductReq\x1a\x0e.pb.ProductRes\"\x00\x12+\n" +
+	"\rDeleteProduct\x12\x0e.pb.ProductReq\x1a\x0e.pb.ProductRes\"\x00\x121\n" +
+	"\rCreateVariant\x12\x0e.pb.VariantReq\x1a\x0e.pb.VariantRes\"\x00\x121\n" +
+	"\rUpdateVariant\x12\x0e.pb.VariantReq\x1a\x0e.pb.VariantRes\"\x00\x121\n" +
+	"\rDeleteVariant\x12\x0e.pb.VariantReq\x1a\x0e.pb.VariantRes\"\x00\x12F\n" +
+	"\x14CreateScheduledPrice\x12\x15.pb.ScheduledPriceReq\x1a\x15.pb.ScheduledPriceRes\"\x00\x12J\n" +
+	"\x13ListScheduledPrices\x12\x15.pb.ScheduledPriceReq\x1a\x1a.pb.ListScheduledPricesRes\"\x00\x12F\n" +
+	"\x14UpdateScheduledPrice\x12\x15.pb.ScheduledPriceReq\x1a\x15.pb.ScheduledPriceRes\"\x00\x12F\n" +
+	"\x14DeleteScheduledPrice\x12\x15.pb.ScheduledPriceReq\x1a\x15.pb.ScheduledPriceRes\"\x00\x127\n" +
+	"\x0fCreateWarehouse\x12\x10.pb.WarehouseReq\x1a\x10.pb.WarehouseRes\"\x00\x12@\n" +
+	"\x0eListWarehouses\x12\x15.pb.ListWarehousesReq\x1a\x15.pb.ListWarehousesRes\"\x00\x127\n" +
+	"\x0fUpdateWarehouse\x12\x10.pb.WarehouseReq\x1a\x10.pb.WarehouseRes\"\x00\x12/\n" +
+	"\x0fGetProductStock\x12\f.pb.StockReq\x1a\f.pb.StockRes\"\x00\x12+\n" +
+	"\vAdjustStock\x12\f.pb.StockReq\x1a\f.pb.StockRes\"\x00\x12-\n" +
+	"\rTransferStock\x12\f.pb.StockReq\x1a\f.pb.StockRes\"\x00\x12L\n" +
+	"\x12ListStockMovements\x12\x19.pb.ListStockMovementsReq\x1a\x19.pb.ListStockMovementsRes\"\x00\x124\n" +
+	"\x0eCreateCategory\x12\x0f.pb.CategoryReq\x1a\x0f.pb.CategoryRes\"\x00\x121\n" +
+	"\vGetCategory\x12\x0f.pb.CategoryReq\x1a\x0f.pb.CategoryRes\"\x00\x12@\n" +
+	"\x0eListCategories\x12\x15.pb.ListCategoriesReq\x1a\x15.pb.ListCategoriesRes\"\x00\x124\n" +
+	"\x0eUpdateCategory\x12\x0f.pb.CategoryReq\x1a\x0f.pb.CategoryRes\"\x00\x124\n" +
+	"\x0eDeleteCategory\x12\x0f.pb.CategoryReq\x1a\x0f.pb.CategoryRes\"\x00\x127\n" +
+	"\x0fCreatePromotion\x12\x10.pb.PromotionReq\x1a\x10.pb.PromotionRes\"\x00\x124\n" +
+	"\fGetPromotion\x12\x10.pb.PromotionReq\x1a\x10.pb.PromotionRes\"\x00\x12@\n" +
+	"\x0eListPromotions\x12\x15.pb.ListPromotionsReq\x1a\x15.pb.ListPromotionsRes\"\x00\x127\n" +
+	"\x0fUpdatePromotion\x12\x10.pb.PromotionReq\x1a\x10.pb.PromotionRes\"\x00\x127\n" +
+	"\x0fDeletePromotion\x12\x10.pb.PromotionReq\x1a\x10.pb.PromotionRes\"\x00\x121\n" +
+	"\rCreateTaxRate\x12\x0e.pb.TaxRateReq\x1a\x0e.pb.TaxRateRes\"\x00\x12:\n" +
+	"\fListTaxRates\x12\x13.pb.ListTaxRatesReq\x1a\x13.pb.ListTaxRatesRes\"\x00\x121\n" +
+	"\rUpdateTaxRate\x12\x0e.pb.TaxRateReq\x1a\x0e.pb.TaxRateRes\"\x00\x121\n" +
+	"\rDeleteTaxRate\x12\x0e.pb.TaxRateReq\x1a\x0e.pb.TaxRateRes\"\x00\x121\n" +
+	"\rCreateAddress\x12\x0e.pb.AddressReq\x1a\x0e.pb.AddressRes\"\x00\x127\n" +
+	"\rListAddresses\x12\x0e.pb.AddressReq\x1a\x14.pb.ListAddressesRes\"\x00\x121\n" +
+	"\rUpdateAddress\x12\x0e.pb.AddressReq\x1a\x0e.pb.AddressRes\"\x00\x121\n" +
+	"\rDeleteAddress\x12\x0e.pb.AddressReq\x1a\x0e.pb.AddressRes\"\x00\x12@\n" +
+	"\x12CreateShippingZone\x12\x13.pb.ShippingZoneReq\x1a\x13.pb.ShippingZoneRes\"\x00\x12I\n" +
+	"\x11ListShippingZones\x12\x18.pb.ListShippingZonesReq\x1a\x18.pb.ListShippingZonesRes\"\x00\x12@\n" +
+	"\x12UpdateShippingZone\x12\x13.pb.ShippingZoneReq\x1a\x13.pb.ShippingZoneRes\"\x00\x12@\n" +
+	"\x12DeleteShippingZone\x12\x13.pb.ShippingZoneReq\x1a\x13.pb.ShippingZoneRes\"\x00\x12F\n" +
+	"\x14CreateShippingMethod\x12\x15.pb.ShippingMethodReq\x1a\x15.pb.ShippingMethodRes\"\x00\x12F\n" +
+	"\x14UpdateShippingMethod\x12\x15.pb.ShippingMethodReq\x1a\x15.pb.ShippingMethodRes\"\x00\x12F\n" +
+	"\x14DeleteShippingMethod\x12\x15.pb.ShippingMethodReq\x1a\x15.pb.ShippingMethodRes\"\x00\x12=\n" +
+	"\rQuoteShipping\x12\x14.pb.ShippingQuoteReq\x1a\x14.pb.ShippingQuoteRes\"\x00\x12+\n" +
 	"\vCreateOrder\x12\f.pb.OrderReq\x1a\f.pb.OrderRes\"\x00\x12(\n" +
 	"\bGetOrder\x12\f.pb.OrderReq\x1a\f.pb.OrderRes\"\x00\x12.\n" +
 	"\n" +
-	"ListOrders\x12\f.pb.OrderReq\x1a\x10.pb.ListOrderRes\"\x00\x121\n" +
-	"\x11UpdateOrderStatus\x12\f.pb.OrderReq\x1a\f.pb.OrderRes\"\x00\x12+\n" +
-	"\vDeleteOrder\x12\f.pb.OrderReq\x1a\f.pb.OrderRes\"\x00\x12(\n" +
+	"ListOrders\x12\f.pb.OrderReq\x1a\x10.pb.ListOrderRes\"\x00\x12;\n" +
+	"\x0eListUserOrders\x12\x15.pb.ListUserOrdersReq\x1a\x10.pb.ListOrderRes\"\x00\x121\n" +
+	"\x11UpdateOrderStatus\x12\f.pb.OrderReq\x1a\f.pb.OrderRes\"\x00\x12G\n" +
+	"\x16ListOrderStatusChanges\x12\f.pb.OrderReq\x1a\x1d.pb.ListOrderStatusChangesRes\"\x00\x12+\n" +
+	"\vDeleteOrder\x12\f.pb.OrderReq\x1a\f.pb.OrderRes\"\x00\x121\n" +
+	"\x0fGetOrderInvoice\x12\f.pb.OrderReq\x1a\x0e.pb.InvoiceRes\"\x00\x12,\n" +
+	"\bPayOrder\x12\x0e.pb.PaymentReq\x1a\x0e.pb.PaymentRes\"\x00\x12:\n" +
+	"\x11ListOrderPayments\x12\x0e.pb.PaymentReq\x1a\x13.pb.ListPaymentsRes\"\x00\x12;\n" +
+	"\x12HandlePaymentEvent\x12\x13.pb.PaymentEventReq\x1a\x0e.pb.PaymentRes\"\x00\x124\n" +
+	"\x0eCreateGiftCard\x12\x0f.pb.GiftCardReq\x1a\x0f.pb.GiftCardRes\"\x00\x121\n" +
+	"\vGetGiftCard\x12\x0f.pb.GiftCardReq\x1a\x0f.pb.GiftCardRes\"\x00\x128\n" +
+	"\rListGiftCards\x12\x0f.pb.GiftCardReq\x1a\x14.pb.ListGiftCardsRes\"\x00\x124\n" +
+	"\x0eUpdateGiftCard\x12\x0f.pb.GiftCardReq\x1a\x0f.pb.GiftCardRes\"\x00\x124\n" +
+	"\x0eAdjustGiftCard\x12\x0f.pb.GiftCardReq\x1a\x0f.pb.GiftCardRes\"\x00\x12:\n" +
+	"\x0eGetStoreCredit\x12\x12.pb.StoreCreditReq\x1a\x12.pb.StoreCreditRes\"\x00\x12=\n" +
+	"\x11AdjustStoreCredit\x12\x12.pb.StoreCreditReq\x1a\x12.pb.StoreCreditRes\"\x00\x12.\n" +
+	"\n" +
+	"GetLoyalty\x12\x0e.pb.LoyaltyReq\x1a\x0e.pb.LoyaltyRes\"\x00\x12.\n" +
+	"\fCreateReturn\x12\r.pb.ReturnReq\x1a\r.pb.ReturnRes\"\x00\x126\n" +
+	"\x10ListOrderReturns\x12\f.pb.OrderReq\x1a\x12.pb.ListReturnsRes\"\x00\x127\n" +
+	"\vListReturns\x12\x12.pb.ListReturnsReq\x1a\x12.pb.ListReturnsRes\"\x00\x124\n" +
+	"\x12UpdateReturnStatus\x12\r.pb.ReturnReq\x1a\r.pb.ReturnRes\"\x00\x12.\n" +
+	"\fCreateReview\x12\r.pb.ReviewReq\x1a\r.pb.ReviewRes\"\x00\x12>\n" +
+	"\x12ListProductReviews\x12\x12.pb.ListReviewsReq\x1a\x12.pb.ListReviewsRes\"\x00\x12.\n" +
+	"\fUpdateReview\x12\r.pb.ReviewReq\x1a\r.pb.ReviewRes\"\x00\x12.\n" +
+	"\fDeleteReview\x12\r.pb.ReviewReq\x1a\r.pb.ReviewRes\"\x00\x120\n" +
+	"\x0eModerateReview\x12\r.pb.ReviewReq\x1a\r.pb.ReviewRes\"\x00\x12%\n" +
+	"\aGetCart\x12\v.pb.CartReq\x1a\v.pb.CartRes\"\x00\x12)\n" +
+	"\vAddCartItem\x12\v.pb.CartReq\x1a\v.pb.CartRes\"\x00\x12,\n" +
+	"\x0eUpdateCartItem\x12\v.pb.CartReq\x1a\v.pb.CartRes\"\x00\x12,\n" +
+	"\x0eRemoveCartItem\x12\v.pb.CartReq\x1a\v.pb.CartRes\"\x00\x12'\n" +
+	"\tClearCart\x12\v.pb.CartReq\x1a\v.pb.CartRes\"\x00\x123\n" +
+	"\fCheckoutCart\x12\x13.pb.CheckoutCartReq\x1a\f.pb.OrderRes\"\x00\x124\n" +
+	"\x0eCreateWishlist\x12\x0f.pb.WishlistReq\x1a\x0f.pb.WishlistRes\"\x00\x121\n" +
+	"\vGetWishlist\x12\x0f.pb.WishlistReq\x1a\x0f.pb.WishlistRes\"\x00\x128\n" +
+	"\rListWishlists\x12\x0f.pb.WishlistReq\x1a\x14.pb.ListWishlistsRes\"\x00\x124\n" +
+	"\x0eUpdateWishlist\x12\x0f.pb.WishlistReq\x1a\x0f.pb.WishlistRes\"\x00\x124\n" +
+	"\x0eDeleteWishlist\x12\x0f.pb.WishlistReq\x1a\x0f.pb.WishlistRes\"\x00\x129\n" +
+	"\x0fAddWishlistItem\x12\x13.pb.WishlistItemReq\x1a\x0f.pb.WishlistRes\"\x00\x12<\n" +
+	"\x12RemoveWishlistItem\x12\x13.pb.WishlistItemReq\x1a\x0f.pb.WishlistRes\"\x00\x12<\n" +
+	"\x16MoveWishlistItemToCart\x12\x13.pb.WishlistItemReq\x1a\v.pb.CartRes\"\x00\x12=\n" +
+	"\x11GetSharedWishlist\x12\x15.pb.SharedWishlistReq\x1a\x0f.pb.WishlistRes\"\x00\x12(\n" +
 	"\n" +
 	"CreateUser\x12\v.pb.UserReq\x1a\v.pb.UserRes\"\x00\x12%\n" +
 	"\aGetUser\x12\v.pb.UserReq\x1a\v.pb.UserRes\"\x00\x12+\n" +
@@ -1531,13 +10036,15 @@ const file_api_proto_rawDesc = "" +
 	"\n" +
 	"UpdateUser\x12\v.pb.UserReq\x1a\v.pb.UserRes\"\x00\x12(\n" +
 	"\n" +
-	"DeleteUser\x12\v.pb.UserReq\x1a\v.pb.UserRes\"\x00\x121\n" +
+	"DeleteUser\x12\v.pb.UserReq\x1a\v.pb.UserRes\"\x00\x12.\n" +
+	"\x10SetUserTaxExempt\x12\v.pb.UserReq\x1a\v.pb.UserRes\"\x00\x121\n" +
 	"\rCreateSession\x12\x0e.pb.SessionReq\x1a\x0e.pb.SessionRes\"\x00\x12.\n" +
 	"\n" +
 	"GetSession\x12\x0e.pb.SessionReq\x1a\x0e.pb.SessionRes\"\x00\x121\n" +
 	"\rRevokeSession\x12\x0e.pb.SessionReq\x1a\x0e.pb.SessionRes\"\x00\x121\n" +
 	"\rDeleteSession\x12\x0e.pb.SessionReq\x1a\x0e.pb.SessionRes\"\x00\x12X\n" +
-	"\x16ListNotificationEvents\x12\x1d.pb.ListNotificationEventsReq\x1a\x1d.pb.ListNotificationEventsRes\"\x00\x12[\n" +
+	"\x16ListNotificationEvents\x12\x1d.pb.ListNotificationEventsReq\x1a\x1d.pb.ListNotificationEventsRes\"\x00\x12Z\n" +
+	"\x17ClaimNotificationEvents\x12\x1e.pb.ClaimNotificationEventsReq\x1a\x1d.pb.ListNotificationEventsRes\"\x00\x12[\n" +
 	"\x17UpdateNotificationEvent\x12\x1e.pb.UpdateNotificationEventReq\x1a\x1e.pb.UpdateNotificationEventRes\"\x00B6Z4github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pbb\x06proto3"
 
 var (
@@ -1552,95 +10059,525 @@ func file_api_proto_rawDescGZIP() []byte {
 	return file_api_proto_rawDescData
 }
 
-var file_api_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_api_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_api_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_api_proto_msgTypes = make([]protoimpl.MessageInfo, 107)
 var file_api_proto_goTypes = []any{
-	(OrderStatus)(0),                   // 0: pb.OrderStatus
-	(NotificationResponseType)(0),      // 1: pb.NotificationResponseType
-	(*ProductReq)(nil),                 // 2: pb.ProductReq
-	(*ProductRes)(nil),                 // 3: pb.ProductRes
-	(*ListProductRes)(nil),             // 4: pb.ListProductRes
-	(*OrderItem)(nil),                  // 5: pb.OrderItem
-	(*OrderReq)(nil),                   // 6: pb.OrderReq
-	(*OrderRes)(nil),                   // 7: pb.OrderRes
-	(*ListOrderRes)(nil),               // 8: pb.ListOrderRes
-	(*UserReq)(nil),                    // 9: pb.UserReq
-	(*UserRes)(nil),                    // 10: pb.UserRes
-	(*ListUserRes)(nil),                // 11: pb.ListUserRes
-	(*SessionReq)(nil),                 // 12: pb.SessionReq
-	(*SessionRes)(nil),                 // 13: pb.SessionRes
-	(*NotificationEvent)(nil),          // 14: pb.NotificationEvent
-	(*ListNotificationEventsReq)(nil),  // 15: pb.ListNotificationEventsReq
-	(*ListNotificationEventsRes)(nil),  // 16: pb.ListNotificationEventsRes
-	(*UpdateNotificationEventReq)(nil), // 17: pb.UpdateNotificationEventReq
-	(*UpdateNotificationEventRes)(nil), // 18: pb.UpdateNotificationEventRes
-	(*timestamppb.Timestamp)(nil),      // 19: google.protobuf.Timestamp
+	(ProductSortBy)(0),                 // 0: pb.ProductSortBy
+	(SortOrder)(0),                     // 1: pb.SortOrder
+	(PromotionType)(0),                 // 2: pb.PromotionType
+	(ShippingBasis)(0),                 // 3: pb.ShippingBasis
+	(OrderStatus)(0),                   // 4: pb.OrderStatus
+	(NotificationResponseType)(0),      // 5: pb.NotificationResponseType
+	(*Money)(nil),                      // 6: pb.Money
+	(*ProductReq)(nil),                 // 7: pb.ProductReq
+	(*ProductRes)(nil),                 // 8: pb.ProductRes
+	(*VariantReq)(nil),                 // 9: pb.VariantReq
+	(*VariantRes)(nil),                 // 10: pb.VariantRes
+	(*ScheduledPriceReq)(nil),          // 11: pb.ScheduledPriceReq
+	(*ScheduledPriceRes)(nil),          // 12: pb.ScheduledPriceRes
+	(*ListScheduledPricesRes)(nil),     // 13: pb.ListScheduledPricesRes
+	(*WarehouseReq)(nil),               // 14: pb.WarehouseReq
+	(*WarehouseRes)(nil),               // 15: pb.WarehouseRes
+	(*ListWarehousesReq)(nil),          // 16: pb.ListWarehousesReq
+	(*ListWarehousesRes)(nil),          // 17: pb.ListWarehousesRes
+	(*StockReq)(nil),                   // 18: pb.StockReq
+	(*StockLevel)(nil),                 // 19: pb.StockLevel
+	(*StockRes)(nil),                   // 20: pb.StockRes
+	(*ListStockMovementsReq)(nil),      // 21: pb.ListStockMovementsReq
+	(*StockMovement)(nil),              // 22: pb.StockMovement
+	(*ListStockMovementsRes)(nil),      // 23: pb.ListStockMovementsRes
+	(*CategoryReq)(nil),                // 24: pb.CategoryReq
+	(*CategoryRes)(nil),                // 25: pb.CategoryRes
+	(*ListCategoriesReq)(nil),          // 26: pb.ListCategoriesReq
+	(*ListCategoriesRes)(nil),          // 27: pb.ListCategoriesRes
+	(*ListProductsReq)(nil),            // 28: pb.ListProductsReq
+	(*ListProductRes)(nil),             // 29: pb.ListProductRes
+	(*SearchProductsReq)(nil),          // 30: pb.SearchProductsReq
+	(*ProductSearchHit)(nil),           // 31: pb.ProductSearchHit
+	(*PromotionReq)(nil),               // 32: pb.PromotionReq
+	(*PromotionRes)(nil),               // 33: pb.PromotionRes
+	(*ListPromotionsReq)(nil),          // 34: pb.ListPromotionsReq
+	(*ListPromotionsRes)(nil),          // 35: pb.ListPromotionsRes
+	(*TaxRateReq)(nil),                 // 36: pb.TaxRateReq
+	(*TaxRateRes)(nil),                 // 37: pb.TaxRateRes
+	(*ListTaxRatesReq)(nil),            // 38: pb.ListTaxRatesReq
+	(*ListTaxRatesRes)(nil),            // 39: pb.ListTaxRatesRes
+	(*AddressReq)(nil),                 // 40: pb.AddressReq
+	(*AddressRes)(nil),                 // 41: pb.AddressRes
+	(*ListAddressesRes)(nil),           // 42: pb.ListAddressesRes
+	(*ShippingZoneReq)(nil),            // 43: pb.ShippingZoneReq
+	(*ShippingZoneRes)(nil),            // 44: pb.ShippingZoneRes
+	(*ListShippingZonesReq)(nil),       // 45: pb.ListShippingZonesReq
+	(*ListShippingZonesRes)(nil),       // 46: pb.ListShippingZonesRes
+	(*ShippingTier)(nil),               // 47: pb.ShippingTier
+	(*ShippingMethodReq)(nil),          // 48: pb.ShippingMethodReq
+	(*ShippingMethodRes)(nil),          // 49: pb.ShippingMethodRes
+	(*ShippingQuoteReq)(nil),           // 50: pb.ShippingQuoteReq
+	(*ShippingOption)(nil),             // 51: pb.ShippingOption
+	(*ShippingQuoteRes)(nil),           // 52: pb.ShippingQuoteRes
+	(*SearchProductsRes)(nil),          // 53: pb.SearchProductsRes
+	(*OrderItem)(nil),                  // 54: pb.OrderItem
+	(*Address)(nil),                    // 55: pb.Address
+	(*OrderReq)(nil),                   // 56: pb.OrderReq
+	(*OrderRes)(nil),                   // 57: pb.OrderRes
+	(*OrderDiscount)(nil),              // 58: pb.OrderDiscount
+	(*ListOrderRes)(nil),               // 59: pb.ListOrderRes
+	(*ListUserOrdersReq)(nil),          // 60: pb.ListUserOrdersReq
+	(*OrderStatusChange)(nil),          // 61: pb.OrderStatusChange
+	(*ListOrderStatusChangesRes)(nil),  // 62: pb.ListOrderStatusChangesRes
+	(*PaymentReq)(nil),                 // 63: pb.PaymentReq
+	(*PaymentRes)(nil),                 // 64: pb.PaymentRes
+	(*ListPaymentsRes)(nil),            // 65: pb.ListPaymentsRes
+	(*InvoiceLine)(nil),                // 66: pb.InvoiceLine
+	(*Seller)(nil),                     // 67: pb.Seller
+	(*CreditNote)(nil),                 // 68: pb.CreditNote
+	(*InvoiceRes)(nil),                 // 69: pb.InvoiceRes
+	(*ReturnReq)(nil),                  // 70: pb.ReturnReq
+	(*ReturnRes)(nil),                  // 71: pb.ReturnRes
+	(*ListReturnsReq)(nil),             // 72: pb.ListReturnsReq
+	(*ListReturnsRes)(nil),             // 73: pb.ListReturnsRes
+	(*GiftCardReq)(nil),                // 74: pb.GiftCardReq
+	(*GiftCardTransaction)(nil),        // 75: pb.GiftCardTransaction
+	(*GiftCardRes)(nil),                // 76: pb.GiftCardRes
+	(*ListGiftCardsRes)(nil),           // 77: pb.ListGiftCardsRes
+	(*StoreCreditReq)(nil),             // 78: pb.StoreCreditReq
+	(*StoreCreditTransaction)(nil),     // 79: pb.StoreCreditTransaction
+	(*StoreCreditRes)(nil),             // 80: pb.StoreCreditRes
+	(*LoyaltyReq)(nil),                 // 81: pb.LoyaltyReq
+	(*LoyaltyTransaction)(nil),         // 82: pb.LoyaltyTransaction
+	(*LoyaltyRes)(nil),                 // 83: pb.LoyaltyRes
+	(*PaymentEventReq)(nil),            // 84: pb.PaymentEventReq
+	(*ReviewReq)(nil),                  // 85: pb.ReviewReq
+	(*ReviewRes)(nil),                  // 86: pb.ReviewRes
+	(*ListReviewsReq)(nil),             // 87: pb.ListReviewsReq
+	(*ListReviewsRes)(nil),             // 88: pb.ListReviewsRes
+	(*CartReq)(nil),                    // 89: pb.CartReq
+	(*CartItem)(nil),                   // 90: pb.CartItem
+	(*CartRes)(nil),                    // 91: pb.CartRes
+	(*CheckoutCartReq)(nil),            // 92: pb.CheckoutCartReq
+	(*WishlistReq)(nil),                // 93: pb.WishlistReq
+	(*WishlistItemReq)(nil),            // 94: pb.WishlistItemReq
+	(*WishlistItem)(nil),               // 95: pb.WishlistItem
+	(*WishlistRes)(nil),                // 96: pb.WishlistRes
+	(*ListWishlistsRes)(nil),           // 97: pb.ListWishlistsRes
+	(*SharedWishlistReq)(nil),          // 98: pb.SharedWishlistReq
+	(*UserReq)(nil),                    // 99: pb.UserReq
+	(*UserRes)(nil),                    // 100: pb.UserRes
+	(*ListUserRes)(nil),                // 101: pb.ListUserRes
+	(*SessionReq)(nil),                 // 102: pb.SessionReq
+	(*SessionRes)(nil),                 // 103: pb.SessionRes
+	(*NotificationEvent)(nil),          // 104: pb.NotificationEvent
+	(*ListNotificationEventsReq)(nil),  // 105: pb.ListNotificationEventsReq
+	(*ClaimNotificationEventsReq)(nil), // 106: pb.ClaimNotificationEventsReq
+	(*ListNotificationEventsRes)(nil),  // 107: pb.ListNotificationEventsRes
+	(*UpdateNotificationEventReq)(nil), // 108: pb.UpdateNotificationEventReq
+	(*UpdateNotificationEventRes)(nil), // 109: pb.UpdateNotificationEventRes
+	nil,                                // 110: pb.VariantReq.OptionsEntry
+	nil,                                // 111: pb.VariantRes.OptionsEntry
+	nil,                                // 112: pb.ProductSearchHit.HighlightsEntry
+	(*timestamppb.Timestamp)(nil),      // 113: google.protobuf.Timestamp
 }
 var file_api_proto_depIdxs = []int32{
-	19, // 0: pb.ProductRes.created_at:type_name -> google.protobuf.Timestamp
-	19, // 1: pb.ProductRes.updated_at:type_name -> google.protobuf.Timestamp
-	3,  // 2: pb.ListProductRes.products:type_name -> pb.ProductRes
-	5,  // 3: pb.OrderReq.items:type_name -> pb.OrderItem
-	0,  // 4: pb.OrderReq.status:type_name -> pb.OrderStatus
-	5,  // 5: pb.OrderRes.items:type_name -> pb.OrderItem
-	19, // 6: pb.OrderRes.created_at:type_name -> google.protobuf.Timestamp
-	19, // 7: pb.OrderRes.updated_at:type_name -> google.protobuf.Timestamp
-	0,  // 8: pb.OrderRes.status:type_name -> pb.OrderStatus
-	7,  // 9: pb.ListOrderRes.orders:type_name -> pb.OrderRes
-	19, // 10: pb.UserRes.created_at:type_name -> google.protobuf.Timestamp
-	10, // 11: pb.ListUserRes.users:type_name -> pb.UserRes
-	19, // 12: pb.SessionReq.expires_at:type_name -> google.protobuf.Timestamp
-	19, // 13: pb.SessionRes.expires_at:type_name -> google.protobuf.Timestamp
-	0,  // 14: pb.NotificationEvent.order_status:type_name -> pb.OrderStatus
-	14, // 15: pb.ListNotificationEventsRes.events:type_name -> pb.NotificationEvent
-	1,  // 16: pb.UpdateNotificationEventReq.response_type:type_name -> pb.NotificationResponseType
-	2,  // 17: pb.ecom.CreateProduct:input_type -> pb.ProductReq
-	2,  // 18: pb.ecom.GetProduct:input_type -> pb.ProductReq
-	2,  // 19: pb.ecom.ListProducts:input_type -> pb.ProductReq
-	2,  // 20: pb.ecom.UpdateProduct:input_type -> pb.ProductReq
-	2,  // 21: pb.ecom.DeleteProduct:input_type -> pb.ProductReq
-	6,  // 22: pb.ecom.CreateOrder:input_type -> pb.OrderReq
-	6,  // 23: pb.ecom.GetOrder:input_type -> pb.OrderReq
-	6,  // 24: pb.ecom.ListOrders:input_type -> pb.OrderReq
-	6,  // 25: pb.ecom.UpdateOrderStatus:input_type -> pb.OrderReq
-	6,  // 26: pb.ecom.DeleteOrder:input_type -> pb.OrderReq
-	9,  // 27: pb.ecom.CreateUser:input_type -> pb.UserReq
-	9,  // 28: pb.ecom.GetUser:input_type -> pb.UserReq
-	9,  // 29: pb.ecom.ListUsers:input_type -> pb.UserReq
-	9,  // 30: pb.ecom.UpdateUser:input_type -> pb.UserReq
-	9,  // 31: pb.ecom.DeleteUser:input_type -> pb.UserReq
-	12, // 32: pb.ecom.CreateSession:input_type -> pb.SessionReq
-	12, // 33: pb.ecom.GetSession:input_type -> pb.SessionReq
-	12, // 34: pb.ecom.RevokeSession:input_type -> pb.SessionReq
-	12, // 35: pb.ecom.DeleteSession:input_type -> pb.SessionReq
-	15, // 36: pb.ecom.ListNotificationEvents:input_type -> pb.ListNotificationEventsReq
-	17, // 37: pb.ecom.UpdateNotificationEvent:input_type -> pb.UpdateNotificationEventReq
-	3,  // 38: pb.ecom.CreateProduct:output_type -> pb.ProductRes
-	3,  // 39: pb.ecom.GetProduct:output_type -> pb.ProductRes
-	4,  // 40: pb.ecom.ListProducts:output_type -> pb.ListProductRes
-	3,  // 41: pb.ecom.UpdateProduct:output_type -> pb.ProductRes
-	3,  // 42: pb.ecom.DeleteProduct:output_type -> pb.ProductRes
-	7,  // 43: pb.ecom.CreateOrder:output_type -> pb.OrderRes
-	7,  // 44: pb.ecom.GetOrder:output_type -> pb.OrderRes
-	8,  // 45: pb.ecom.ListOrders:output_type -> pb.ListOrderRes
-	7,  // 46: pb.ecom.UpdateOrderStatus:output_type -> pb.OrderRes
-	7,  // 47: pb.ecom.DeleteOrder:output_type -> pb.OrderRes
-	10, // 48: pb.ecom.CreateUser:output_type -> pb.UserRes
-	10, // 49: pb.ecom.GetUser:output_type -> pb.UserRes
-	11, // 50: pb.ecom.ListUsers:output_type -> pb.ListUserRes
-	10, // 51: pb.ecom.UpdateUser:output_type -> pb.UserRes
-	10, // 52: pb.ecom.DeleteUser:output_type -> pb.UserRes
-	13, // 53: pb.ecom.CreateSession:output_type -> pb.SessionRes
-	13, // 54: pb.ecom.GetSession:output_type -> pb.SessionRes
-	13, // 55: pb.ecom.RevokeSession:output_type -> pb.SessionRes
-	13, // 56: pb.ecom.DeleteSession:output_type -> pb.SessionRes
-	16, // 57: pb.ecom.ListNotificationEvents:output_type -> pb.ListNotificationEventsRes
-	18, // 58: pb.ecom.UpdateNotificationEvent:output_type -> pb.UpdateNotificationEventRes
-	38, // [38:59] is the sub-list for method output_type
-	17, // [17:38] is the sub-list for method input_type
-	17, // [17:17] is the sub-list for extension type_name
-	17, // [17:17] is the sub-list for extension extendee
-	0,  // [0:17] is the sub-list for field type_name
+	6,   // 0: pb.ProductReq.price:type_name -> pb.Money
+	113, // 1: pb.ProductRes.created_at:type_name -> google.protobuf.Timestamp
+	113, // 2: pb.ProductRes.updated_at:type_name -> google.protobuf.Timestamp
+	6,   // 3: pb.ProductRes.price:type_name -> pb.Money
+	10,  // 4: pb.ProductRes.variants:type_name -> pb.VariantRes
+	6,   // 5: pb.ProductRes.compare_at_price:type_name -> pb.Money
+	110, // 6: pb.VariantReq.options:type_name -> pb.VariantReq.OptionsEntry
+	6,   // 7: pb.VariantReq.price:type_name -> pb.Money
+	111, // 8: pb.VariantRes.options:type_name -> pb.VariantRes.OptionsEntry
+	6,   // 9: pb.VariantRes.price:type_name -> pb.Money
+	113, // 10: pb.VariantRes.created_at:type_name -> google.protobuf.Timestamp
+	113, // 11: pb.VariantRes.updated_at:type_name -> google.protobuf.Timestamp
+	6,   // 12: pb.VariantRes.compare_at_price:type_name -> pb.Money
+	6,   // 13: pb.ScheduledPriceReq.price:type_name -> pb.Money
+	113, // 14: pb.ScheduledPriceReq.starts_at:type_name -> google.protobuf.Timestamp
+	113, // 15: pb.ScheduledPriceReq.ends_at:type_name -> google.protobuf.Timestamp
+	6,   // 16: pb.ScheduledPriceRes.price:type_name -> pb.Money
+	113, // 17: pb.ScheduledPriceRes.starts_at:type_name -> google.protobuf.Timestamp
+	113, // 18: pb.ScheduledPriceRes.ends_at:type_name -> google.protobuf.Timestamp
+	113, // 19: pb.ScheduledPriceRes.created_at:type_name -> google.protobuf.Timestamp
+	113, // 20: pb.ScheduledPriceRes.updated_at:type_name -> google.protobuf.Timestamp
+	12,  // 21: pb.ListScheduledPricesRes.prices:type_name -> pb.ScheduledPriceRes
+	113, // 22: pb.WarehouseRes.created_at:type_name -> google.protobuf.Timestamp
+	113, // 23: pb.WarehouseRes.updated_at:type_name -> google.protobuf.Timestamp
+	15,  // 24: pb.ListWarehousesRes.warehouses:type_name -> pb.WarehouseRes
+	19,  // 25: pb.StockRes.levels:type_name -> pb.StockLevel
+	113, // 26: pb.StockMovement.created_at:type_name -> google.protobuf.Timestamp
+	22,  // 27: pb.ListStockMovementsRes.movements:type_name -> pb.StockMovement
+	113, // 28: pb.CategoryRes.created_at:type_name -> google.protobuf.Timestamp
+	113, // 29: pb.CategoryRes.updated_at:type_name -> google.protobuf.Timestamp
+	25,  // 30: pb.CategoryRes.children:type_name -> pb.CategoryRes
+	25,  // 31: pb.ListCategoriesRes.categories:type_name -> pb.CategoryRes
+	0,   // 32: pb.ListProductsReq.sort_by:type_name -> pb.ProductSortBy
+	1,   // 33: pb.ListProductsReq.sort_order:type_name -> pb.SortOrder
+	6,   // 34: pb.ListProductsReq.min_price:type_name -> pb.Money
+	6,   // 35: pb.ListProductsReq.max_price:type_name -> pb.Money
+	8,   // 36: pb.ListProductRes.products:type_name -> pb.ProductRes
+	8,   // 37: pb.ProductSearchHit.product:type_name -> pb.ProductRes
+	112, // 38: pb.ProductSearchHit.highlights:type_name -> pb.ProductSearchHit.HighlightsEntry
+	2,   // 39: pb.PromotionReq.type:type_name -> pb.PromotionType
+	6,   // 40: pb.PromotionReq.discount_amount:type_name -> pb.Money
+	6,   // 41: pb.PromotionReq.min_subtotal:type_name -> pb.Money
+	113, // 42: pb.PromotionReq.starts_at:type_name -> google.protobuf.Timestamp
+	113, // 43: pb.PromotionReq.ends_at:type_name -> google.protobuf.Timestamp
+	2,   // 44: pb.PromotionRes.type:type_name -> pb.PromotionType
+	6,   // 45: pb.PromotionRes.discount_amount:type_name -> pb.Money
+	6,   // 46: pb.PromotionRes.min_subtotal:type_name -> pb.Money
+	113, // 47: pb.PromotionRes.starts_at:type_name -> google.protobuf.Timestamp
+	113, // 48: pb.PromotionRes.ends_at:type_name -> google.protobuf.Timestamp
+	113, // 49: pb.PromotionRes.created_at:type_name -> google.protobuf.Timestamp
+	113, // 50: pb.PromotionRes.updated_at:type_name -> google.protobuf.Timestamp
+	33,  // 51: pb.ListPromotionsRes.promotions:type_name -> pb.PromotionRes
+	113, // 52: pb.TaxRateRes.created_at:type_name -> google.protobuf.Timestamp
+	113, // 53: pb.TaxRateRes.updated_at:type_name -> google.protobuf.Timestamp
+	37,  // 54: pb.ListTaxRatesRes.tax_rates:type_name -> pb.TaxRateRes
+	55,  // 55: pb.AddressReq.address:type_name -> pb.Address
+	55,  // 56: pb.AddressRes.address:type_name -> pb.Address
+	113, // 57: pb.AddressRes.created_at:type_name -> google.protobuf.Timestamp
+	113, // 58: pb.AddressRes.updated_at:type_name -> google.protobuf.Timestamp
+	41,  // 59: pb.ListAddressesRes.addresses:type_name -> pb.AddressRes
+	49,  // 60: pb.ShippingZoneRes.methods:type_name -> pb.ShippingMethodRes
+	113, // 61: pb.ShippingZoneRes.created_at:type_name -> google.protobuf.Timestamp
+	113, // 62: pb.ShippingZoneRes.updated_at:type_name -> google.protobuf.Timestamp
+	44,  // 63: pb.ListShippingZonesRes.zones:type_name -> pb.ShippingZoneRes
+	6,   // 64: pb.ShippingTier.price:type_name -> pb.Money
+	3,   // 65: pb.ShippingMethodReq.basis:type_name -> pb.ShippingBasis
+	47,  // 66: pb.ShippingMethodReq.tiers:type_name -> pb.ShippingTier
+	6,   // 67: pb.ShippingMethodReq.free_above:type_name -> pb.Money
+	3,   // 68: pb.ShippingMethodRes.basis:type_name -> pb.ShippingBasis
+	47,  // 69: pb.ShippingMethodRes.tiers:type_name -> pb.ShippingTier
+	6,   // 70: pb.ShippingMethodRes.free_above:type_name -> pb.Money
+	113, // 71: pb.ShippingMethodRes.created_at:type_name -> google.protobuf.Timestamp
+	113, // 72: pb.ShippingMethodRes.updated_at:type_name -> google.protobuf.Timestamp
+	55,  // 73: pb.ShippingQuoteReq.address:type_name -> pb.Address
+	6,   // 74: pb.ShippingOption.price:type_name -> pb.Money
+	55,  // 75: pb.ShippingQuoteRes.address:type_name -> pb.Address
+	51,  // 76: pb.ShippingQuoteRes.options:type_name -> pb.ShippingOption
+	31,  // 77: pb.SearchProductsRes.hits:type_name -> pb.ProductSearchHit
+	6,   // 78: pb.OrderItem.price:type_name -> pb.Money
+	6,   // 79: pb.OrderItem.line_total:type_name -> pb.Money
+	6,   // 80: pb.OrderItem.tax_price:type_name -> pb.Money
+	54,  // 81: pb.OrderReq.items:type_name -> pb.OrderItem
+	4,   // 82: pb.OrderReq.status:type_name -> pb.OrderStatus
+	6,   // 83: pb.OrderReq.tax_price:type_name -> pb.Money
+	6,   // 84: pb.OrderReq.shipping_price:type_name -> pb.Money
+	6,   // 85: pb.OrderReq.total_price:type_name -> pb.Money
+	6,   // 86: pb.OrderReq.discount_price:type_name -> pb.Money
+	55,  // 87: pb.OrderReq.shipping_address:type_name -> pb.Address
+	55,  // 88: pb.OrderReq.billing_address:type_name -> pb.Address
+	6,   // 89: pb.OrderReq.store_credit:type_name -> pb.Money
+	54,  // 90: pb.OrderRes.items:type_name -> pb.OrderItem
+	113, // 91: pb.OrderRes.created_at:type_name -> google.protobuf.Timestamp
+	113, // 92: pb.OrderRes.updated_at:type_name -> google.protobuf.Timestamp
+	4,   // 93: pb.OrderRes.status:type_name -> pb.OrderStatus
+	6,   // 94: pb.OrderRes.tax_price:type_name -> pb.Money
+	6,   // 95: pb.OrderRes.shipping_price:type_name -> pb.Money
+	6,   // 96: pb.OrderRes.total_price:type_name -> pb.Money
+	6,   // 97: pb.OrderRes.items_price:type_name -> pb.Money
+	6,   // 98: pb.OrderRes.discount_price:type_name -> pb.Money
+	58,  // 99: pb.OrderRes.discounts:type_name -> pb.OrderDiscount
+	55,  // 100: pb.OrderRes.shipping_address:type_name -> pb.Address
+	55,  // 101: pb.OrderRes.billing_address:type_name -> pb.Address
+	6,   // 102: pb.OrderRes.amount_due:type_name -> pb.Money
+	6,   // 103: pb.OrderDiscount.amount:type_name -> pb.Money
+	57,  // 104: pb.ListOrderRes.orders:type_name -> pb.OrderRes
+	4,   // 105: pb.ListUserOrdersReq.status:type_name -> pb.OrderStatus
+	113, // 106: pb.ListUserOrdersReq.from:type_name -> google.protobuf.Timestamp
+	113, // 107: pb.ListUserOrdersReq.to:type_name -> google.protobuf.Timestamp
+	4,   // 108: pb.OrderStatusChange.from_status:type_name -> pb.OrderStatus
+	4,   // 109: pb.OrderStatusChange.to_status:type_name -> pb.OrderStatus
+	113, // 110: pb.OrderStatusChange.created_at:type_name -> google.protobuf.Timestamp
+	61,  // 111: pb.ListOrderStatusChangesRes.changes:type_name -> pb.OrderStatusChange
+	6,   // 112: pb.PaymentRes.amount:type_name -> pb.Money
+	113, // 113: pb.PaymentRes.created_at:type_name -> google.protobuf.Timestamp
+	113, // 114: pb.PaymentRes.updated_at:type_name -> google.protobuf.Timestamp
+	6,   // 115: pb.PaymentRes.refunded_amount:type_name -> pb.Money
+	64,  // 116: pb.ListPaymentsRes.payments:type_name -> pb.PaymentRes
+	6,   // 117: pb.InvoiceLine.unit_price:type_name -> pb.Money
+	6,   // 118: pb.InvoiceLine.tax_price:type_name -> pb.Money
+	6,   // 119: pb.InvoiceLine.total:type_name -> pb.Money
+	66,  // 120: pb.CreditNote.lines:type_name -> pb.InvoiceLine
+	6,   // 121: pb.CreditNote.items_price:type_name -> pb.Money
+	6,   // 122: pb.CreditNote.discount_price:type_name -> pb.Money
+	6,   // 123: pb.CreditNote.shipping_price:type_name -> pb.Money
+	6,   // 124: pb.CreditNote.tax_price:type_name -> pb.Money
+	6,   // 125: pb.CreditNote.total_price:type_name -> pb.Money
+	113, // 126: pb.CreditNote.issued_at:type_name -> google.protobuf.Timestamp
+	67,  // 127: pb.InvoiceRes.seller:type_name -> pb.Seller
+	55,  // 128: pb.InvoiceRes.billing_address:type_name -> pb.Address
+	55,  // 129: pb.InvoiceRes.shipping_address:type_name -> pb.Address
+	66,  // 130: pb.InvoiceRes.lines:type_name -> pb.InvoiceLine
+	6,   // 131: pb.InvoiceRes.items_price:type_name -> pb.Money
+	6,   // 132: pb.InvoiceRes.discount_price:type_name -> pb.Money
+	6,   // 133: pb.InvoiceRes.shipping_price:type_name -> pb.Money
+	6,   // 134: pb.InvoiceRes.tax_price:type_name -> pb.Money
+	6,   // 135: pb.InvoiceRes.total_price:type_name -> pb.Money
+	113, // 136: pb.InvoiceRes.issued_at:type_name -> google.protobuf.Timestamp
+	68,  // 137: pb.InvoiceRes.credit_notes:type_name -> pb.CreditNote
+	6,   // 138: pb.ReturnRes.refund_amount:type_name -> pb.Money
+	113, // 139: pb.ReturnRes.created_at:type_name -> google.protobuf.Timestamp
+	113, // 140: pb.ReturnRes.updated_at:type_name -> google.protobuf.Timestamp
+	71,  // 141: pb.ListReturnsRes.returns:type_name -> pb.ReturnRes
+	6,   // 142: pb.GiftCardReq.amount:type_name -> pb.Money
+	113, // 143: pb.GiftCardReq.expires_at:type_name -> google.protobuf.Timestamp
+	6,   // 144: pb.GiftCardTransaction.amount:type_name -> pb.Money
+	113, // 145: pb.GiftCardTransaction.created_at:type_name -> google.protobuf.Timestamp
+	6,   // 146: pb.GiftCardRes.initial_amount:type_name -> pb.Money
+	6,   // 147: pb.GiftCardRes.balance:type_name -> pb.Money
+	113, // 148: pb.GiftCardRes.expires_at:type_name -> google.protobuf.Timestamp
+	113, // 149: pb.GiftCardRes.created_at:type_name -> google.protobuf.Timestamp
+	113, // 150: pb.GiftCardRes.updated_at:type_name -> google.protobuf.Timestamp
+	75,  // 151: pb.GiftCardRes.transactions:type_name -> pb.GiftCardTransaction
+	76,  // 152: pb.ListGiftCardsRes.gift_cards:type_name -> pb.GiftCardRes
+	6,   // 153: pb.StoreCreditReq.amount:type_name -> pb.Money
+	6,   // 154: pb.StoreCreditTransaction.amount:type_name -> pb.Money
+	113, // 155: pb.StoreCreditTransaction.created_at:type_name -> google.protobuf.Timestamp
+	6,   // 156: pb.StoreCreditRes.balance:type_name -> pb.Money
+	79,  // 157: pb.StoreCreditRes.transactions:type_name -> pb.StoreCreditTransaction
+	113, // 158: pb.LoyaltyTransaction.expires_at:type_name -> google.protobuf.Timestamp
+	113, // 159: pb.LoyaltyTransaction.created_at:type_name -> google.protobuf.Timestamp
+	6,   // 160: pb.LoyaltyRes.points_value:type_name -> pb.Money
+	6,   // 161: pb.LoyaltyRes.year_spend:type_name -> pb.Money
+	6,   // 162: pb.LoyaltyRes.next_tier_spend:type_name -> pb.Money
+	82,  // 163: pb.LoyaltyRes.transactions:type_name -> pb.LoyaltyTransaction
+	113, // 164: pb.ReviewRes.created_at:type_name -> google.protobuf.Timestamp
+	113, // 165: pb.ReviewRes.updated_at:type_name -> google.protobuf.Timestamp
+	86,  // 166: pb.ListReviewsRes.reviews:type_name -> pb.ReviewRes
+	6,   // 167: pb.CartItem.price:type_name -> pb.Money
+	6,   // 168: pb.CartItem.line_total:type_name -> pb.Money
+	6,   // 169: pb.CartItem.compare_at_price:type_name -> pb.Money
+	90,  // 170: pb.CartRes.items:type_name -> pb.CartItem
+	6,   // 171: pb.CartRes.items_price:type_name -> pb.Money
+	113, // 172: pb.CartRes.updated_at:type_name -> google.protobuf.Timestamp
+	6,   // 173: pb.CheckoutCartReq.total_price:type_name -> pb.Money
+	55,  // 174: pb.CheckoutCartReq.shipping_address:type_name -> pb.Address
+	55,  // 175: pb.CheckoutCartReq.billing_address:type_name -> pb.Address
+	6,   // 176: pb.CheckoutCartReq.store_credit:type_name -> pb.Money
+	6,   // 177: pb.WishlistItem.price:type_name -> pb.Money
+	113, // 178: pb.WishlistItem.added_at:type_name -> google.protobuf.Timestamp
+	6,   // 179: pb.WishlistItem.compare_at_price:type_name -> pb.Money
+	95,  // 180: pb.WishlistRes.items:type_name -> pb.WishlistItem
+	113, // 181: pb.WishlistRes.created_at:type_name -> google.protobuf.Timestamp
+	113, // 182: pb.WishlistRes.updated_at:type_name -> google.protobuf.Timestamp
+	96,  // 183: pb.ListWishlistsRes.wishlists:type_name -> pb.WishlistRes
+	113, // 184: pb.UserRes.created_at:type_name -> google.protobuf.Timestamp
+	100, // 185: pb.ListUserRes.users:type_name -> pb.UserRes
+	113, // 186: pb.SessionReq.expires_at:type_name -> google.protobuf.Timestamp
+	113, // 187: pb.SessionRes.expires_at:type_name -> google.protobuf.Timestamp
+	4,   // 188: pb.NotificationEvent.order_status:type_name -> pb.OrderStatus
+	104, // 189: pb.ListNotificationEventsRes.events:type_name -> pb.NotificationEvent
+	5,   // 190: pb.UpdateNotificationEventReq.response_type:type_name -> pb.NotificationResponseType
+	7,   // 191: pb.ecom.CreateProduct:input_type -> pb.ProductReq
+	7,   // 192: pb.ecom.GetProduct:input_type -> pb.ProductReq
+	28,  // 193: pb.ecom.ListProducts:input_type -> pb.ListProductsReq
+	30,  // 194: pb.ecom.SearchProducts:input_type -> pb.SearchProductsReq
+	7,   // 195: pb.ecom.UpdateProduct:input_type -> pb.ProductReq
+	7,   // 196: pb.ecom.DeleteProduct:input_type -> pb.ProductReq
+	9,   // 197: pb.ecom.CreateVariant:input_type -> pb.VariantReq
+	9,   // 198: pb.ecom.UpdateVariant:input_type -> pb.VariantReq
+	9,   // 199: pb.ecom.DeleteVariant:input_type -> pb.VariantReq
+	11,  // 200: pb.ecom.CreateScheduledPrice:input_type -> pb.ScheduledPriceReq
+	11,  // 201: pb.ecom.ListScheduledPrices:input_type -> pb.ScheduledPriceReq
+	11,  // 202: pb.ecom.UpdateScheduledPrice:input_type -> pb.ScheduledPriceReq
+	11,  // 203: pb.ecom.DeleteScheduledPrice:input_type -> pb.ScheduledPriceReq
+	14,  // 204: pb.ecom.CreateWarehouse:input_type -> pb.WarehouseReq
+	16,  // 205: pb.ecom.ListWarehouses:input_type -> pb.ListWarehousesReq
+	14,  // 206: pb.ecom.UpdateWarehouse:input_type -> pb.WarehouseReq
+	18,  // 207: pb.ecom.GetProductStock:input_type -> pb.StockReq
+	18,  // 208: pb.ecom.AdjustStock:input_type -> pb.StockReq
+	18,  // 209: pb.ecom.TransferStock:input_type -> pb.StockReq
+	21,  // 210: pb.ecom.ListStockMovements:input_type -> pb.ListStockMovementsReq
+	24,  // 211: pb.ecom.CreateCategory:input_type -> pb.CategoryReq
+	24,  // 212: pb.ecom.GetCategory:input_type -> pb.CategoryReq
+	26,  // 213: pb.ecom.ListCategories:input_type -> pb.ListCategoriesReq
+	24,  // 214: pb.ecom.UpdateCategory:input_type -> pb.CategoryReq
+	24,  // 215: pb.ecom.DeleteCategory:input_type -> pb.CategoryReq
+	32,  // 216: pb.ecom.CreatePromotion:input_type -> pb.PromotionReq
+	32,  // 217: pb.ecom.GetPromotion:input_type -> pb.PromotionReq
+	34,  // 218: pb.ecom.ListPromotions:input_type -> pb.ListPromotionsReq
+	32,  // 219: pb.ecom.UpdatePromotion:input_type -> pb.PromotionReq
+	32,  // 220: pb.ecom.DeletePromotion:input_type -> pb.PromotionReq
+	36,  // 221: pb.ecom.CreateTaxRate:input_type -> pb.TaxRateReq
+	38,  // 222: pb.ecom.ListTaxRates:input_type -> pb.ListTaxRatesReq
+	36,  // 223: pb.ecom.UpdateTaxRate:input_type -> pb.TaxRateReq
+	36,  // 224: pb.ecom.DeleteTaxRate:input_type -> pb.TaxRateReq
+	40,  // 225: pb.ecom.CreateAddress:input_type -> pb.AddressReq
+	40,  // 226: pb.ecom.ListAddresses:input_type -> pb.AddressReq
+	40,  // 227: pb.ecom.UpdateAddress:input_type -> pb.AddressReq
+	40,  // 228: pb.ecom.DeleteAddress:input_type -> pb.AddressReq
+	43,  // 229: pb.ecom.CreateShippingZone:input_type -> pb.ShippingZoneReq
+	45,  // 230: pb.ecom.ListShippingZones:input_type -> pb.ListShippingZonesReq
+	43,  // 231: pb.ecom.UpdateShippingZone:input_type -> pb.ShippingZoneReq
+	43,  // 232: pb.ecom.DeleteShippingZone:input_type -> pb.ShippingZoneReq
+	48,  // 233: pb.ecom.CreateShippingMethod:input_type -> pb.ShippingMethodReq
+	48,  // 234: pb.ecom.UpdateShippingMethod:input_type -> pb.ShippingMethodReq
+	48,  // 235: pb.ecom.DeleteShippingMethod:input_type -> pb.ShippingMethodReq
+	50,  // 236: pb.ecom.QuoteShipping:input_type -> pb.ShippingQuoteReq
+	56,  // 237: pb.ecom.CreateOrder:input_type -> pb.OrderReq
+	56,  // 238: pb.ecom.GetOrder:input_type -> pb.OrderReq
+	56,  // 239: pb.ecom.ListOrders:input_type -> pb.OrderReq
+	60,  // 240: pb.ecom.ListUserOrders:input_type -> pb.ListUserOrdersReq
+	56,  // 241: pb.ecom.UpdateOrderStatus:input_type -> pb.OrderReq
+	56,  // 242: pb.ecom.ListOrderStatusChanges:input_type -> pb.OrderReq
+	56,  // 243: pb.ecom.DeleteOrder:input_type -> pb.OrderReq
+	56,  // 244: pb.ecom.GetOrderInvoice:input_type -> pb.OrderReq
+	63,  // 245: pb.ecom.PayOrder:input_type -> pb.PaymentReq
+	63,  // 246: pb.ecom.ListOrderPayments:input_type -> pb.PaymentReq
+	84,  // 247: pb.ecom.HandlePaymentEvent:input_type -> pb.PaymentEventReq
+	74,  // 248: pb.ecom.CreateGiftCard:input_type -> pb.GiftCardReq
+	74,  // 249: pb.ecom.GetGiftCard:input_type -> pb.GiftCardReq
+	74,  // 250: pb.ecom.ListGiftCards:input_type -> pb.GiftCardReq
+	74,  // 251: pb.ecom.UpdateGiftCard:input_type -> pb.GiftCardReq
+	74,  // 252: pb.ecom.AdjustGiftCard:input_type -> pb.GiftCardReq
+	78,  // 253: pb.ecom.GetStoreCredit:input_type -> pb.StoreCreditReq
+	78,  // 254: pb.ecom.AdjustStoreCredit:input_type -> pb.StoreCreditReq
+	81,  // 255: pb.ecom.GetLoyalty:input_type -> pb.LoyaltyReq
+	70,  // 256: pb.ecom.CreateReturn:input_type -> pb.ReturnReq
+	56,  // 257: pb.ecom.ListOrderReturns:input_type -> pb.OrderReq
+	72,  // 258: pb.ecom.ListReturns:input_type -> pb.ListReturnsReq
+	70,  // 259: pb.ecom.UpdateReturnStatus:input_type -> pb.ReturnReq
+	85,  // 260: pb.ecom.CreateReview:input_type -> pb.ReviewReq
+	87,  // 261: pb.ecom.ListProductReviews:input_type -> pb.ListReviewsReq
+	85,  // 262: pb.ecom.UpdateReview:input_type -> pb.ReviewReq
+	85,  // 263: pb.ecom.DeleteReview:input_type -> pb.ReviewReq
+	85,  // 264: pb.ecom.ModerateReview:input_type -> pb.ReviewReq
+	89,  // 265: pb.ecom.GetCart:input_type -> pb.CartReq
+	89,  // 266: pb.ecom.AddCartItem:input_type -> pb.CartReq
+	89,  // 267: pb.ecom.UpdateCartItem:input_type -> pb.CartReq
+	89,  // 268: pb.ecom.RemoveCartItem:input_type -> pb.CartReq
+	89,  // 269: pb.ecom.ClearCart:input_type -> pb.CartReq
+	92,  // 270: pb.ecom.CheckoutCart:input_type -> pb.CheckoutCartReq
+	93,  // 271: pb.ecom.CreateWishlist:input_type -> pb.WishlistReq
+	93,  // 272: pb.ecom.GetWishlist:input_type -> pb.WishlistReq
+	93,  // 273: pb.ecom.ListWishlists:input_type -> pb.WishlistReq
+	93,  // 274: pb.ecom.UpdateWishlist:input_type -> pb.WishlistReq
+	93,  // 275: pb.ecom.DeleteWishlist:input_type -> pb.WishlistReq
+	94,  // 276: pb.ecom.AddWishlistItem:input_type -> pb.WishlistItemReq
+	94,  // 277: pb.ecom.RemoveWishlistItem:input_type -> pb.WishlistItemReq
+	94,  // 278: pb.ecom.MoveWishlistItemToCart:input_type -> pb.WishlistItemReq
+	98,  // 279: pb.ecom.GetSharedWishlist:input_type -> pb.SharedWishlistReq
+	99,  // 280: pb.ecom.CreateUser:input_type -> pb.UserReq
+	99,  // 281: pb.ecom.GetUser:input_type -> pb.UserReq
+	99,  // 282: pb.ecom.ListUsers:input_type -> pb.UserReq
+	99,  // 283: pb.ecom.UpdateUser:input_type -> pb.UserReq
+	99,  // 284: pb.ecom.DeleteUser:input_type -> pb.UserReq
+	99,  // 285: pb.ecom.SetUserTaxExempt:input_type -> pb.UserReq
+	102, // 286: pb.ecom.CreateSession:input_type -> pb.SessionReq
+	102, // 287: pb.ecom.GetSession:input_type -> pb.SessionReq
+	102, // 288: pb.ecom.RevokeSession:input_type -> pb.SessionReq
+	102, // 289: pb.ecom.DeleteSession:input_type -> pb.SessionReq
+	105, // 290: pb.ecom.ListNotificationEvents:input_type -> pb.ListNotificationEventsReq
+	106, // 291: pb.ecom.ClaimNotificationEvents:input_type -> pb.ClaimNotificationEventsReq
+	108, // 292: pb.ecom.UpdateNotificationEvent:input_type -> pb.UpdateNotificationEventReq
+	8,   // 293: pb.ecom.CreateProduct:output_type -> pb.ProductRes
+	8,   // 294: pb.ecom.GetProduct:output_type -> pb.ProductRes
+	29,  // 295: pb.ecom.ListProducts:output_type -> pb.ListProductRes
+	53,  // 296: pb.ecom.SearchProducts:output_type -> pb.SearchProductsRes
+	8,   // 297: pb.ecom.UpdateProduct:output_type -> pb.ProductRes
+	8,   // 298: pb.ecom.DeleteProduct:output_type -> pb.ProductRes
+	10,  // 299: pb.ecom.CreateVariant:output_type -> pb.VariantRes
+	10,  // 300: pb.ecom.UpdateVariant:output_type -> pb.VariantRes
+	10,  // 301: pb.ecom.DeleteVariant:output_type -> pb.VariantRes
+	12,  // 302: pb.ecom.CreateScheduledPrice:output_type -> pb.ScheduledPriceRes
+	13,  // 303: pb.ecom.ListScheduledPrices:output_type -> pb.ListScheduledPricesRes
+	12,  // 304: pb.ecom.UpdateScheduledPrice:output_type -> pb.ScheduledPriceRes
+	12,  // 305: pb.ecom.DeleteScheduledPrice:output_type -> pb.ScheduledPriceRes
+	15,  // 306: pb.ecom.CreateWarehouse:output_type -> pb.WarehouseRes
+	17,  // 307: pb.ecom.ListWarehouses:output_type -> pb.ListWarehousesRes
+	15,  // 308: pb.ecom.UpdateWarehouse:output_type -> pb.WarehouseRes
+	20,  // 309: pb.ecom.GetProductStock:output_type -> pb.StockRes
+	20,  // 310: pb.ecom.AdjustStock:output_type -> pb.StockRes
+	20,  // 311: pb.ecom.TransferStock:output_type -> pb.StockRes
+	23,  // 312: pb.ecom.ListStockMovements:output_type -> pb.ListStockMovementsRes
+	25,  // 313: pb.ecom.CreateCategory:output_type -> pb.CategoryRes
+	25,  // 314: pb.ecom.GetCategory:output_type -> pb.CategoryRes
+	27,  // 315: pb.ecom.ListCategories:output_type -> pb.ListCategoriesRes
+	25,  // 316: pb.ecom.UpdateCategory:output_type -> pb.CategoryRes
+	25,  // 317: pb.ecom.DeleteCategory:output_type -> pb.CategoryRes
+	33,  // 318: pb.ecom.CreatePromotion:output_type -> pb.PromotionRes
+	33,  // 319: pb.ecom.GetPromotion:output_type -> pb.PromotionRes
+	35,  // 320: pb.ecom.ListPromotions:output_type -> pb.ListPromotionsRes
+	33,  // 321: pb.ecom.UpdatePromotion:output_type -> pb.PromotionRes
+	33,  // 322: pb.ecom.DeletePromotion:output_type -> pb.PromotionRes
+	37,  // 323: pb.ecom.CreateTaxRate:output_type -> pb.TaxRateRes
+	39,  // 324: pb.ecom.ListTaxRates:output_type -> pb.ListTaxRatesRes
+	37,  // 325: pb.ecom.UpdateTaxRate:output_type -> pb.TaxRateRes
+	37,  // 326: pb.ecom.DeleteTaxRate:output_type -> pb.TaxRateRes
+	41,  // 327: pb.ecom.CreateAddress:output_type -> pb.AddressRes
+	42,  // 328: pb.ecom.ListAddresses:output_type -> pb.ListAddressesRes
+	41,  // 329: pb.ecom.UpdateAddress:output_type -> pb.AddressRes
+	41,  // 330: pb.ecom.DeleteAddress:output_type -> pb.AddressRes
+	44,  // 331: pb.ecom.CreateShippingZone:output_type -> pb.ShippingZoneRes
+	46,  // 332: pb.ecom.ListShippingZones:output_type -> pb.ListShippingZonesRes
+	44,  // 333: pb.ecom.UpdateShippingZone:output_type -> pb.ShippingZoneRes
+	44,  // 334: pb.ecom.DeleteShippingZone:output_type -> pb.ShippingZoneRes
+	49,  // 335: pb.ecom.CreateShippingMethod:output_type -> pb.ShippingMethodRes
+	49,  // 336: pb.ecom.UpdateShippingMethod:output_type -> pb.ShippingMethodRes
+	49,  // 337: pb.ecom.DeleteShippingMethod:output_type -> pb.ShippingMethodRes
+	52,  // 338: pb.ecom.QuoteShipping:output_type -> pb.ShippingQuoteRes
+	57,  // 339: pb.ecom.CreateOrder:output_type -> pb.OrderRes
+	57,  // 340: pb.ecom.GetOrder:output_type -> pb.OrderRes
+	59,  // 341: pb.ecom.ListOrders:output_type -> pb.ListOrderRes
+	59,  // 342: pb.ecom.ListUserOrders:output_type -> pb.ListOrderRes
+	57,  // 343: pb.ecom.UpdateOrderStatus:output_type -> pb.OrderRes
+	62,  // 344: pb.ecom.ListOrderStatusChanges:output_type -> pb.ListOrderStatusChangesRes
+	57,  // 345: pb.ecom.DeleteOrder:output_type -> pb.OrderRes
+	69,  // 346: pb.ecom.GetOrderInvoice:output_type -> pb.InvoiceRes
+	64,  // 347: pb.ecom.PayOrder:output_type -> pb.PaymentRes
+	65,  // 348: pb.ecom.ListOrderPayments:output_type -> pb.ListPaymentsRes
+	64,  // 349: pb.ecom.HandlePaymentEvent:output_type -> pb.PaymentRes
+	76,  // 350: pb.ecom.CreateGiftCard:output_type -> pb.GiftCardRes
+	76,  // 351: pb.ecom.GetGiftCard:output_type -> pb.GiftCardRes
+	77,  // 352: pb.ecom.ListGiftCards:output_type -> pb.ListGiftCardsRes
+	76,  // 353: pb.ecom.UpdateGiftCard:output_type -> pb.GiftCardRes
+	76,  // 354: pb.ecom.AdjustGiftCard:output_type -> pb.GiftCardRes
+	80,  // 355: pb.ecom.GetStoreCredit:output_type -> pb.StoreCreditRes
+	80,  // 356: pb.ecom.AdjustStoreCredit:output_type -> pb.StoreCreditRes
+	83,  // 357: pb.ecom.GetLoyalty:output_type -> pb.LoyaltyRes
+	71,  // 358: pb.ecom.CreateReturn:output_type -> pb.ReturnRes
+	73,  // 359: pb.ecom.ListOrderReturns:output_type -> pb.ListReturnsRes
+	73,  // 360: pb.ecom.ListReturns:output_type -> pb.ListReturnsRes
+	71,  // 361: pb.ecom.UpdateReturnStatus:output_type -> pb.ReturnRes
+	86,  // 362: pb.ecom.CreateReview:output_type -> pb.ReviewRes
+	88,  // 363: pb.ecom.ListProductReviews:output_type -> pb.ListReviewsRes
+	86,  // 364: pb.ecom.UpdateReview:output_type -> pb.ReviewRes
+	86,  // 365: pb.ecom.DeleteReview:output_type -> pb.ReviewRes
+	86,  // 366: pb.ecom.ModerateReview:output_type -> pb.ReviewRes
+	91,  // 367: pb.ecom.GetCart:output_type -> pb.CartRes
+	91,  // 368: pb.ecom.AddCartItem:output_type -> pb.CartRes
+	91,  // 369: pb.ecom.UpdateCartItem:output_type -> pb.CartRes
+	91,  // 370: pb.ecom.RemoveCartItem:output_type -> pb.CartRes
+	91,  // 371: pb.ecom.ClearCart:output_type -> pb.CartRes
+	57,  // 372: pb.ecom.CheckoutCart:output_type -> pb.OrderRes
+	96,  // 373: pb.ecom.CreateWishlist:output_type -> pb.WishlistRes
+	96,  // 374: pb.ecom.GetWishlist:output_type -> pb.WishlistRes
+	97,  // 375: pb.ecom.ListWishlists:output_type -> pb.ListWishlistsRes
+	96,  // 376: pb.ecom.UpdateWishlist:output_type -> pb.WishlistRes
+	96,  // 377: pb.ecom.DeleteWishlist:output_type -> pb.WishlistRes
+	96,  // 378: pb.ecom.AddWishlistItem:output_type -> pb.WishlistRes
+	96,  // 379: pb.ecom.RemoveWishlistItem:output_type -> pb.WishlistRes
+	91,  // 380: pb.ecom.MoveWishlistItemToCart:output_type -> pb.CartRes
+	96,  // 381: pb.ecom.GetSharedWishlist:output_type -> pb.WishlistRes
+	100, // 382: pb.ecom.CreateUser:output_type -> pb.UserRes
+	100, // 383: pb.ecom.GetUser:output_type -> pb.UserRes
+	101, // 384: pb.ecom.ListUsers:output_type -> pb.ListUserRes
+	100, // 385: pb.ecom.UpdateUser:output_type -> pb.UserRes
+	100, // 386: pb.ecom.DeleteUser:output_type -> pb.UserRes
+	100, // 387: pb.ecom.SetUserTaxExempt:output_type -> pb.UserRes
+	103, // 388: pb.ecom.CreateSession:output_type -> pb.SessionRes
+	103, // 389: pb.ecom.GetSession:output_type -> pb.SessionRes
+	103, // 390: pb.ecom.RevokeSession:output_type -> pb.SessionRes
+	103, // 391: pb.ecom.DeleteSession:output_type -> pb.SessionRes
+	107, // 392: pb.ecom.ListNotificationEvents:output_type -> pb.ListNotificationEventsRes
+	107, // 393: pb.ecom.ClaimNotificationEvents:output_type -> pb.ListNotificationEventsRes
+	109, // 394: pb.ecom.UpdateNotificationEvent:output_type -> pb.UpdateNotificationEventRes
+	293, // [293:395] is the sub-list for method output_type
+	191, // [191:293] is the sub-list for method input_type
+	191, // [191:191] is the sub-list for extension type_name
+	191, // [191:191] is the sub-list for extension extendee
+	0,   // [0:191] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_init() }
@@ -1648,13 +10585,22 @@ func file_api_proto_init() {
 	if File_api_proto != nil {
 		return
 	}
+	file_api_proto_msgTypes[1].OneofWrappers = []any{}
+	file_api_proto_msgTypes[3].OneofWrappers = []any{}
+	file_api_proto_msgTypes[5].OneofWrappers = []any{}
+	file_api_proto_msgTypes[18].OneofWrappers = []any{}
+	file_api_proto_msgTypes[22].OneofWrappers = []any{}
+	file_api_proto_msgTypes[26].OneofWrappers = []any{}
+	file_api_proto_msgTypes[42].OneofWrappers = []any{}
+	file_api_proto_msgTypes[54].OneofWrappers = []any{}
+	file_api_proto_msgTypes[87].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_rawDesc), len(file_api_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   17,
+			NumEnums:      6,
+			NumMessages:   107,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -22,23 +22,104 @@ const (
 	Ecom_CreateProduct_FullMethodName           = "/pb.ecom/CreateProduct"
 	Ecom_GetProduct_FullMethodName              = "/pb.ecom/GetProduct"
 	Ecom_ListProducts_FullMethodName            = "/pb.ecom/ListProducts"
+	Ecom_SearchProducts_FullMethodName          = "/pb.ecom/SearchProducts"
 	Ecom_UpdateProduct_FullMethodName           = "/pb.ecom/UpdateProduct"
 	Ecom_DeleteProduct_FullMethodName           = "/pb.ecom/DeleteProduct"
+	Ecom_CreateVariant_FullMethodName           = "/pb.ecom/CreateVariant"
+	Ecom_UpdateVariant_FullMethodName           = "/pb.ecom/UpdateVariant"
+	Ecom_DeleteVariant_FullMethodName           = "/pb.ecom/DeleteVariant"
+	Ecom_CreateScheduledPrice_FullMethodName    = "/pb.ecom/CreateScheduledPrice"
+	Ecom_ListScheduledPrices_FullMethodName     = "/pb.ecom/ListScheduledPrices"
+	Ecom_UpdateScheduledPrice_FullMethodName    = "/pb.ecom/UpdateScheduledPrice"
+	Ecom_DeleteScheduledPrice_FullMethodName    = "/pb.ecom/DeleteScheduledPrice"
+	Ecom_CreateWarehouse_FullMethodName         = "/pb.ecom/CreateWarehouse"
+	Ecom_ListWarehouses_FullMethodName          = "/pb.ecom/ListWarehouses"
+	Ecom_UpdateWarehouse_FullMethodName         = "/pb.ecom/UpdateWarehouse"
+	Ecom_GetProductStock_FullMethodName         = "/pb.ecom/GetProductStock"
+	Ecom_AdjustStock_FullMethodName             = "/pb.ecom/AdjustStock"
+	Ecom_TransferStock_FullMethodName           = "/pb.ecom/TransferStock"
+	Ecom_ListStockMovements_FullMethodName      = "/pb.ecom/ListStockMovements"
+	Ecom_CreateCategory_FullMethodName          = "/pb.ecom/CreateCategory"
+	Ecom_GetCategory_FullMethodName             = "/pb.ecom/GetCategory"
+	Ecom_ListCategories_FullMethodName          = "/pb.ecom/ListCategories"
+	Ecom_UpdateCategory_FullMethodName          = "/pb.ecom/UpdateCategory"
+	Ecom_DeleteCategory_FullMethodName          = "/pb.ecom/DeleteCategory"
+	Ecom_CreatePromotion_FullMethodName         = "/pb.ecom/CreatePromotion"
+	Ecom_GetPromotion_FullMethodName            = "/pb.ecom/GetPromotion"
+	Ecom_ListPromotions_FullMethodName          = "/pb.ecom/ListPromotions"
+	Ecom_UpdatePromotion_FullMethodName         = "/pb.ecom/UpdatePromotion"
+	Ecom_DeletePromotion_FullMethodName         = "/pb.ecom/DeletePromotion"
+	Ecom_CreateTaxRate_FullMethodName           = "/pb.ecom/CreateTaxRate"
+	Ecom_ListTaxRates_FullMethodName            = "/pb.ecom/ListTaxRates"
+	Ecom_UpdateTaxRate_FullMethodName           = "/pb.ecom/UpdateTaxRate"
+	Ecom_DeleteTaxRate_FullMethodName           = "/pb.ecom/DeleteTaxRate"
+	Ecom_CreateAddress_FullMethodName           = "/pb.ecom/CreateAddress"
+	Ecom_ListAddresses_FullMethodName           = "/pb.ecom/ListAddresses"
+	Ecom_UpdateAddress_FullMethodName           = "/pb.ecom/UpdateAddress"
+	Ecom_DeleteAddress_FullMethodName           = "/pb.ecom/DeleteAddress"
+	Ecom_CreateShippingZone_FullMethodName      = "/pb.ecom/CreateShippingZone"
+	Ecom_ListShippingZones_FullMethodName       = "/pb.ecom/ListShippingZones"
+	Ecom_UpdateShippingZone_FullMethodName      = "/pb.ecom/UpdateShippingZone"
+	Ecom_DeleteShippingZone_FullMethodName      = "/pb.ecom/DeleteShippingZone"
+	Ecom_CreateShippingMethod_FullMethodName    = "/pb.ecom/CreateShippingMethod"
+	Ecom_UpdateShippingMethod_FullMethodName    = "/pb.ecom/UpdateShippingMethod"
+	Ecom_DeleteShippingMethod_FullMethodName    = "/pb.ecom/DeleteShippingMethod"
+	Ecom_QuoteShipping_FullMethodName           = "/pb.ecom/QuoteShipping"
 	Ecom_CreateOrder_FullMethodName             = "/pb.ecom/CreateOrder"
 	Ecom_GetOrder_FullMethodName                = "/pb.ecom/GetOrder"
 	Ecom_ListOrders_FullMethodName              = "/pb.ecom/ListOrders"
+	Ecom_ListUserOrders_FullMethodName          = "/pb.ecom/ListUserOrders"
 	Ecom_UpdateOrderStatus_FullMethodName       = "/pb.ecom/UpdateOrderStatus"
+	Ecom_ListOrderStatusChanges_FullMethodName  = "/pb.ecom/ListOrderStatusChanges"
 	Ecom_DeleteOrder_FullMethodName             = "/pb.ecom/DeleteOrder"
+	Ecom_GetOrderInvoice_FullMethodName         = "/pb.ecom/GetOrderInvoice"
+	Ecom_PayOrder_FullMethodName                = "/pb.ecom/PayOrder"
+	Ecom_ListOrderPayments_FullMethodName       = "/pb.ecom/ListOrderPayments"
+	Ecom_HandlePaymentEvent_FullMethodName      = "/pb.ecom/HandlePaymentEvent"
+	Ecom_CreateGiftCard_FullMethodName          = "/pb.ecom/CreateGiftCard"
+	Ecom_GetGiftCard_FullMethodName             = "/pb.ecom/GetGiftCard"
+	Ecom_ListGiftCards_FullMethodName           = "/pb.ecom/ListGiftCards"
+	Ecom_UpdateGiftCard_FullMethodName          = "/pb.ecom/UpdateGiftCard"
+	Ecom_AdjustGiftCard_FullMethodName          = "/pb.ecom/AdjustGiftCard"
+	Ecom_GetStoreCredit_FullMethodName          = "/pb.ecom/GetStoreCredit"
+	Ecom_AdjustStoreCredit_FullMethodName       = "/pb.ecom/AdjustStoreCredit"
+	Ecom_GetLoyalty_FullMethodName              = "/pb.ecom/GetLoyalty"
+	Ecom_CreateReturn_FullMethodName            = "/pb.ecom/CreateReturn"
+	Ecom_ListOrderReturns_FullMethodName        = "/pb.ecom/ListOrderReturns"
+	Ecom_ListReturns_FullMethodName             = "/pb.ecom/ListReturns"
+	Ecom_UpdateReturnStatus_FullMethodName      = "/pb.ecom/UpdateReturnStatus"
+	Ecom_CreateReview_FullMethodName            = "/pb.ecom/CreateReview"
+	Ecom_ListProductReviews_FullMethodName      = "/pb.ecom/ListProductReviews"
+	Ecom_UpdateReview_FullMethodName            = "/pb.ecom/UpdateReview"
+	Ecom_DeleteReview_FullMethodName            = "/pb.ecom/DeleteReview"
+	Ecom_ModerateReview_FullMethodName          = "/pb.ecom/ModerateReview"
+	Ecom_GetCart_FullMethodName                 = "/pb.ecom/GetCart"
+	Ecom_AddCartItem_FullMethodName             = "/pb.ecom/AddCartItem"
+	Ecom_UpdateCartItem_FullMethodName          = "/pb.ecom/UpdateCartItem"
+	Ecom_RemoveCartItem_FullMethodName          = "/pb.ecom/RemoveCartItem"
+	Ecom_ClearCart_FullMethodName               = "/pb.ecom/ClearCart"
+	Ecom_CheckoutCart_FullMethodName            = "/pb.ecom/CheckoutCart"
+	Ecom_CreateWishlist_FullMethodName          = "/pb.ecom/CreateWishlist"
+	Ecom_GetWishlist_FullMethodName             = "/pb.ecom/GetWishlist"
+	Ecom_ListWishlists_FullMethodName           = "/pb.ecom/ListWishlists"
+	Ecom_UpdateWishlist_FullMethodName          = "/pb.ecom/UpdateWishlist"
+	Ecom_DeleteWishlist_FullMethodName          = "/pb.ecom/DeleteWishlist"
+	Ecom_AddWishlistItem_FullMethodName         = "/pb.ecom/AddWishlistItem"
+	Ecom_RemoveWishlistItem_FullMethodName      = "/pb.ecom/RemoveWishlistItem"
+	Ecom_MoveWishlistItemToCart_FullMethodName  = "/pb.ecom/MoveWishlistItemToCart"
+	Ecom_GetSharedWishlist_FullMethodName       = "/pb.ecom/GetSharedWishlist"
 	Ecom_CreateUser_FullMethodName              = "/pb.ecom/CreateUser"
 	Ecom_GetUser_FullMethodName                 = "/pb.ecom/GetUser"
 	Ecom_ListUsers_FullMethodName               = "/pb.ecom/ListUsers"
 	Ecom_UpdateUser_FullMethodName              = "/pb.ecom/UpdateUser"
 	Ecom_DeleteUser_FullMethodName              = "/pb.ecom/DeleteUser"
+	Ecom_SetUserTaxExempt_FullMethodName        = "/pb.ecom/SetUserTaxExempt"
 	Ecom_CreateSession_FullMethodName           = "/pb.ecom/CreateSession"
 	Ecom_GetSession_FullMethodName              = "/pb.ecom/GetSession"
 	Ecom_RevokeSession_FullMethodName           = "/pb.ecom/RevokeSession"
 	Ecom_DeleteSession_FullMethodName           = "/pb.ecom/DeleteSession"
 	Ecom_ListNotificationEvents_FullMethodName  = "/pb.ecom/ListNotificationEvents"
+	Ecom_ClaimNotificationEvents_FullMethodName = "/pb.ecom/ClaimNotificationEvents"
 	Ecom_UpdateNotificationEvent_FullMethodName = "/pb.ecom/UpdateNotificationEvent"
 )
 
@@ -48,24 +129,105 @@ const (
 type EcomClient interface {
 	CreateProduct(ctx context.Context, in *ProductReq, opts ...grpc.CallOption) (*ProductRes, error)
 	GetProduct(ctx context.Context, in *ProductReq, opts ...grpc.CallOption) (*ProductRes, error)
-	ListProducts(ctx context.Context, in *ProductReq, opts ...grpc.CallOption) (*ListProductRes, error)
+	ListProducts(ctx context.Context, in *ListProductsReq, opts ...grpc.CallOption) (*ListProductRes, error)
+	SearchProducts(ctx context.Context, in *SearchProductsReq, opts ...grpc.CallOption) (*SearchProductsRes, error)
 	UpdateProduct(ctx context.Context, in *ProductReq, opts ...grpc.CallOption) (*ProductRes, error)
 	DeleteProduct(ctx context.Context, in *ProductReq, opts ...grpc.CallOption) (*ProductRes, error)
+	CreateVariant(ctx context.Context, in *VariantReq, opts ...grpc.CallOption) (*VariantRes, error)
+	UpdateVariant(ctx context.Context, in *VariantReq, opts ...grpc.CallOption) (*VariantRes, error)
+	DeleteVariant(ctx context.Context, in *VariantReq, opts ...grpc.CallOption) (*VariantRes, error)
+	CreateScheduledPrice(ctx context.Context, in *ScheduledPriceReq, opts ...grpc.CallOption) (*ScheduledPriceRes, error)
+	ListScheduledPrices(ctx context.Context, in *ScheduledPriceReq, opts ...grpc.CallOption) (*ListScheduledPricesRes, error)
+	UpdateScheduledPrice(ctx context.Context, in *ScheduledPriceReq, opts ...grpc.CallOption) (*ScheduledPriceRes, error)
+	DeleteScheduledPrice(ctx context.Context, in *ScheduledPriceReq, opts ...grpc.CallOption) (*ScheduledPriceRes, error)
+	CreateWarehouse(ctx context.Context, in *WarehouseReq, opts ...grpc.CallOption) (*WarehouseRes, error)
+	ListWarehouses(ctx context.Context, in *ListWarehousesReq, opts ...grpc.CallOption) (*ListWarehousesRes, error)
+	UpdateWarehouse(ctx context.Context, in *WarehouseReq, opts ...grpc.CallOption) (*WarehouseRes, error)
+	GetProductStock(ctx context.Context, in *StockReq, opts ...grpc.CallOption) (*StockRes, error)
+	AdjustStock(ctx context.Context, in *StockReq, opts ...grpc.CallOption) (*StockRes, error)
+	TransferStock(ctx context.Context, in *StockReq, opts ...grpc.CallOption) (*StockRes, error)
+	ListStockMovements(ctx context.Context, in *ListStockMovementsReq, opts ...grpc.CallOption) (*ListStockMovementsRes, error)
+	CreateCategory(ctx context.Context, in *CategoryReq, opts ...grpc.CallOption) (*CategoryRes, error)
+	GetCategory(ctx context.Context, in *CategoryReq, opts ...grpc.CallOption) (*CategoryRes, error)
+	ListCategories(ctx context.Context, in *ListCategoriesReq, opts ...grpc.CallOption) (*ListCategoriesRes, error)
+	UpdateCategory(ctx context.Context, in *CategoryReq, opts ...grpc.CallOption) (*CategoryRes, error)
+	DeleteCategory(ctx context.Context, in *CategoryReq, opts ...grpc.CallOption) (*CategoryRes, error)
+	CreatePromotion(ctx context.Context, in *PromotionReq, opts ...grpc.CallOption) (*PromotionRes, error)
+	GetPromotion(ctx context.Context, in *PromotionReq, opts ...grpc.CallOption) (*PromotionRes, error)
+	ListPromotions(ctx context.Context, in *ListPromotionsReq, opts ...grpc.CallOption) (*ListPromotionsRes, error)
+	UpdatePromotion(ctx context.Context, in *PromotionReq, opts ...grpc.CallOption) (*PromotionRes, error)
+	DeletePromotion(ctx context.Context, in *PromotionReq, opts ...grpc.CallOption) (*PromotionRes, error)
+	CreateTaxRate(ctx context.Context, in *TaxRateReq, opts ...grpc.CallOption) (*TaxRateRes, error)
+	ListTaxRates(ctx context.Context, in *ListTaxRatesReq, opts ...grpc.CallOption) (*ListTaxRatesRes, error)
+	UpdateTaxRate(ctx context.Context, in *TaxRateReq, opts ...grpc.CallOption) (*TaxRateRes, error)
+	DeleteTaxRate(ctx context.Context, in *TaxRateReq, opts ...grpc.CallOption) (*TaxRateRes, error)
+	CreateAddress(ctx context.Context, in *AddressReq, opts ...grpc.CallOption) (*AddressRes, error)
+	ListAddresses(ctx context.Context, in *AddressReq, opts ...grpc.CallOption) (*ListAddressesRes, error)
+	UpdateAddress(ctx context.Context, in *AddressReq, opts ...grpc.CallOption) (*AddressRes, error)
+	DeleteAddress(ctx context.Context, in *AddressReq, opts ...grpc.CallOption) (*AddressRes, error)
+	CreateShippingZone(ctx context.Context, in *ShippingZoneReq, opts ...grpc.CallOption) (*ShippingZoneRes, error)
+	ListShippingZones(ctx context.Context, in *ListShippingZonesReq, opts ...grpc.CallOption) (*ListShippingZonesRes, error)
+	UpdateShippingZone(ctx context.Context, in *ShippingZoneReq, opts ...grpc.CallOption) (*ShippingZoneRes, error)
+	DeleteShippingZone(ctx context.Context, in *ShippingZoneReq, opts ...grpc.CallOption) (*ShippingZoneRes, error)
+	CreateShippingMethod(ctx context.Context, in *ShippingMethodReq, opts ...grpc.CallOption) (*ShippingMethodRes, error)
+	UpdateShippingMethod(ctx context.Context, in *ShippingMethodReq, opts ...grpc.CallOption) (*ShippingMethodRes, error)
+	DeleteShippingMethod(ctx context.Context, in *ShippingMethodReq, opts ...grpc.CallOption) (*ShippingMethodRes, error)
+	QuoteShipping(ctx context.Context, in *ShippingQuoteReq, opts ...grpc.CallOption) (*ShippingQuoteRes, error)
 	CreateOrder(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*OrderRes, error)
 	GetOrder(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*OrderRes, error)
 	ListOrders(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*ListOrderRes, error)
+	ListUserOrders(ctx context.Context, in *ListUserOrdersReq, opts ...grpc.CallOption) (*ListOrderRes, error)
 	UpdateOrderStatus(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*OrderRes, error)
+	ListOrderStatusChanges(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*ListOrderStatusChangesRes, error)
 	DeleteOrder(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*OrderRes, error)
+	GetOrderInvoice(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*InvoiceRes, error)
+	PayOrder(ctx context.Context, in *PaymentReq, opts ...grpc.CallOption) (*PaymentRes, error)
+	ListOrderPayments(ctx context.Context, in *PaymentReq, opts ...grpc.CallOption) (*ListPaymentsRes, error)
+	HandlePaymentEvent(ctx context.Context, in *PaymentEventReq, opts ...grpc.CallOption) (*PaymentRes, error)
+	CreateGiftCard(ctx context.Context, in *GiftCardReq, opts ...grpc.CallOption) (*GiftCardRes, error)
+	GetGiftCard(ctx context.Context, in *GiftCardReq, opts ...grpc.CallOption) (*GiftCardRes, error)
+	ListGiftCards(ctx context.Context, in *GiftCardReq, opts ...grpc.CallOption) (*ListGiftCardsRes, error)
+	UpdateGiftCard(ctx context.Context, in *GiftCardReq, opts ...grpc.CallOption) (*GiftCardRes, error)
+	AdjustGiftCard(ctx context.Context, in *GiftCardReq, opts ...grpc.CallOption) (*GiftCardRes, error)
+	GetStoreCredit(ctx context.Context, in *StoreCreditReq, opts ...grpc.CallOption) (*StoreCreditRes, error)
+	AdjustStoreCredit(ctx context.Context, in *StoreCreditReq, opts ...grpc.CallOption) (*StoreCreditRes, error)
+	GetLoyalty(ctx context.Context, in *LoyaltyReq, opts ...grpc.CallOption) (*LoyaltyRes, error)
+	CreateReturn(ctx context.Context, in *ReturnReq, opts ...grpc.CallOption) (*ReturnRes, error)
+	ListOrderReturns(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*ListReturnsRes, error)
+	ListReturns(ctx context.Context, in *ListReturnsReq, opts ...grpc.CallOption) (*ListReturnsRes, error)
+	UpdateReturnStatus(ctx context.Context, in *ReturnReq, opts ...grpc.CallOption) (*ReturnRes, error)
+	CreateReview(ctx context.Context, in *ReviewReq, opts ...grpc.CallOption) (*ReviewRes, error)
+	ListProductReviews(ctx context.Context, in *ListReviewsReq, opts ...grpc.CallOption) (*ListReviewsRes, error)
+	UpdateReview(ctx context.Context, in *ReviewReq, opts ...grpc.CallOption) (*ReviewRes, error)
+	DeleteReview(ctx context.Context, in *ReviewReq, opts ...grpc.CallOption) (*ReviewRes, error)
+	ModerateReview(ctx context.Context, in *ReviewReq, opts ...grpc.CallOption) (*ReviewRes, error)
+	GetCart(ctx context.Context, in *CartReq, opts ...grpc.CallOption) (*CartRes, error)
+	AddCartItem(ctx context.Context, in *CartReq, opts ...grpc.CallOption) (*CartRes, error)
+	UpdateCartItem(ctx context.Context, in *CartReq, opts ...grpc.CallOption) (*CartRes, error)
+	RemoveCartItem(ctx context.Context, in *CartReq, opts ...grpc.CallOption) (*CartRes, error)
+	ClearCart(ctx context.Context, in *CartReq, opts ...grpc.CallOption) (*CartRes, error)
+	CheckoutCart(ctx context.Context, in *CheckoutCartReq, opts ...grpc.CallOption) (*OrderRes, error)
+	CreateWishlist(ctx context.Context, in *WishlistReq, opts ...grpc.CallOption) (*WishlistRes, error)
+	GetWishlist(ctx context.Context, in *WishlistReq, opts ...grpc.CallOption) (*WishlistRes, error)
+	ListWishlists(ctx context.Context, in *WishlistReq, opts ...grpc.CallOption) (*ListWishlistsRes, error)
+	UpdateWishlist(ctx context.Context, in *WishlistReq, opts ...grpc.CallOption) (*WishlistRes, error)
+	DeleteWishlist(ctx context.Context, in *WishlistReq, opts ...grpc.CallOption) (*WishlistRes, error)
+	AddWishlistItem(ctx context.Context, in *WishlistItemReq, opts ...grpc.CallOption) (*WishlistRes, error)
+	RemoveWishlistItem(ctx context.Context, in *WishlistItemReq, opts ...grpc.CallOption) (*WishlistRes, error)
+	MoveWishlistItemToCart(ctx context.Context, in *WishlistItemReq, opts ...grpc.CallOption) (*CartRes, error)
+	GetSharedWishlist(ctx context.Context, in *SharedWishlistReq, opts ...grpc.CallOption) (*WishlistRes, error)
 	CreateUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error)
 	GetUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error)
 	ListUsers(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*ListUserRes, error)
 	UpdateUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error)
 	DeleteUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error)
+	SetUserTaxExempt(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error)
 	CreateSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error)
 	GetSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error)
 	RevokeSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error)
 	DeleteSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error)
 	ListNotificationEvents(ctx context.Context, in *ListNotificationEventsReq, opts ...grpc.CallOption) (*ListNotificationEventsRes, error)
+	ClaimNotificationEvents(ctx context.Context, in *ClaimNotificationEventsReq, opts ...grpc.CallOption) (*ListNotificationEventsRes, error)
 	UpdateNotificationEvent(ctx context.Context, in *UpdateNotificationEventReq, opts ...grpc.CallOption) (*UpdateNotificationEventRes, error)
 }
 
@@ -97,7 +259,7 @@ func (c *ecomClient) GetProduct(ctx context.Context, in *ProductReq, opts ...grp
 	return out, nil
 }
 
-func (c *ecomClient) ListProducts(ctx context.Context, in *ProductReq, opts ...grpc.CallOption) (*ListProductRes, error) {
+func (c *ecomClient) ListProducts(ctx context.Context, in *ListProductsReq, opts ...grpc.CallOption) (*ListProductRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListProductRes)
 	err := c.cc.Invoke(ctx, Ecom_ListProducts_FullMethodName, in, out, cOpts...)
@@ -107,6 +269,16 @@ func (c *ecomClient) ListProducts(ctx context.Context, in *ProductReq, opts ...g
 	return out, nil
 }
 
+func (c *ecomClient) SearchProducts(ctx context.Context, in *SearchProductsReq, opts ...grpc.CallOption) (*SearchProductsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchProductsRes)
+	err := c.cc.Invoke(ctx, Ecom_SearchProducts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *ecomClient) UpdateProduct(ctx context.Context, in *ProductReq, opts ...grpc.CallOption) (*ProductRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ProductRes)
@@ -127,461 +299,3007 @@ func (c *ecomClient) DeleteProduct(ctx context.Context, in *ProductReq, opts ...
 	return out, nil
 }
 
-func (c *ecomClient) CreateOrder(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*OrderRes, error) {
+func (c *ecomClient) CreateVariant(ctx context.Context, in *VariantReq, opts ...grpc.CallOption) (*VariantRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(OrderRes)
-	err := c.cc.Invoke(ctx, Ecom_CreateOrder_FullMethodName, in, out, cOpts...)
+	out := new(VariantRes)
+	err := c.cc.Invoke(ctx, Ecom_CreateVariant_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecomClient) GetOrder(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*OrderRes, error) {
+func (c *ecomClient) UpdateVariant(ctx context.Context, in *VariantReq, opts ...grpc.CallOption) (*VariantRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(OrderRes)
-	err := c.cc.Invoke(ctx, Ecom_GetOrder_FullMethodName, in, out, cOpts...)
+	out := new(VariantRes)
+	err := c.cc.Invoke(ctx, Ecom_UpdateVariant_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecomClient) ListOrders(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*ListOrderRes, error) {
+func (c *ecomClient) DeleteVariant(ctx context.Context, in *VariantReq, opts ...grpc.CallOption) (*VariantRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(ListOrderRes)
-	err := c.cc.Invoke(ctx, Ecom_ListOrders_FullMethodName, in, out, cOpts...)
+	out := new(VariantRes)
+	err := c.cc.Invoke(ctx, Ecom_DeleteVariant_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecomClient) UpdateOrderStatus(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*OrderRes, error) {
+func (c *ecomClient) CreateScheduledPrice(ctx context.Context, in *ScheduledPriceReq, opts ...grpc.CallOption) (*ScheduledPriceRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(OrderRes)
-	err := c.cc.Invoke(ctx, Ecom_UpdateOrderStatus_FullMethodName, in, out, cOpts...)
+	out := new(ScheduledPriceRes)
+	err := c.cc.Invoke(ctx, Ecom_CreateScheduledPrice_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecomClient) DeleteOrder(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*OrderRes, error) {
+func (c *ecomClient) ListScheduledPrices(ctx context.Context, in *ScheduledPriceReq, opts ...grpc.CallOption) (*ListScheduledPricesRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(OrderRes)
-	err := c.cc.Invoke(ctx, Ecom_DeleteOrder_FullMethodName, in, out, cOpts...)
+	out := new(ListScheduledPricesRes)
+	err := c.cc.Invoke(ctx, Ecom_ListScheduledPrices_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecomClient) CreateUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error) {
+func (c *ecomClient) UpdateScheduledPrice(ctx context.Context, in *ScheduledPriceReq, opts ...grpc.CallOption) (*ScheduledPriceRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(UserRes)
-	err := c.cc.Invoke(ctx, Ecom_CreateUser_FullMethodName, in, out, cOpts...)
+	out := new(ScheduledPriceRes)
+	err := c.cc.Invoke(ctx, Ecom_UpdateScheduledPrice_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecomClient) GetUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error) {
+func (c *ecomClient) DeleteScheduledPrice(ctx context.Context, in *ScheduledPriceReq, opts ...grpc.CallOption) (*ScheduledPriceRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(UserRes)
-	err := c.cc.Invoke(ctx, Ecom_GetUser_FullMethodName, in, out, cOpts...)
+	out := new(ScheduledPriceRes)
+	err := c.cc.Invoke(ctx, Ecom_DeleteScheduledPrice_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecomClient) ListUsers(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*ListUserRes, error) {
+func (c *ecomClient) CreateWarehouse(ctx context.Context, in *WarehouseReq, opts ...grpc.CallOption) (*WarehouseRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(ListUserRes)
-	err := c.cc.Invoke(ctx, Ecom_ListUsers_FullMethodName, in, out, cOpts...)
+	out := new(WarehouseRes)
+	err := c.cc.Invoke(ctx, Ecom_CreateWarehouse_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecomClient) UpdateUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error) {
+func (c *ecomClient) ListWarehouses(ctx context.Context, in *ListWarehousesReq, opts ...grpc.CallOption) (*ListWarehousesRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(UserRes)
-	err := c.cc.Invoke(ctx, Ecom_UpdateUser_FullMethodName, in, out, cOpts...)
+	out := new(ListWarehousesRes)
+	err := c.cc.Invoke(ctx, Ecom_ListWarehouses_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecomClient) DeleteUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error) {
+func (c *ecomClient) UpdateWarehouse(ctx context.Context, in *WarehouseReq, opts ...grpc.CallOption) (*WarehouseRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(UserRes)
-	err := c.cc.Invoke(ctx, Ecom_DeleteUser_FullMethodName, in, out, cOpts...)
+	out := new(WarehouseRes)
+	err := c.cc.Invoke(ctx, Ecom_UpdateWarehouse_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecomClient) CreateSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error) {
+func (c *ecomClient) GetProductStock(ctx context.Context, in *StockReq, opts ...grpc.CallOption) (*StockRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(SessionRes)
-	err := c.cc.Invoke(ctx, Ecom_CreateSession_FullMethodName, in, out, cOpts...)
+	out := new(StockRes)
+	err := c.cc.Invoke(ctx, Ecom_GetProductStock_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecomClient) GetSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error) {
+func (c *ecomClient) AdjustStock(ctx context.Context, in *StockReq, opts ...grpc.CallOption) (*StockRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(SessionRes)
-	err := c.cc.Invoke(ctx, Ecom_GetSession_FullMethodName, in, out, cOpts...)
+	out := new(StockRes)
+	err := c.cc.Invoke(ctx, Ecom_AdjustStock_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecomClient) RevokeSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error) {
+func (c *ecomClient) TransferStock(ctx context.Context, in *StockReq, opts ...grpc.CallOption) (*StockRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(SessionRes)
-	err := c.cc.Invoke(ctx, Ecom_RevokeSession_FullMethodName, in, out, cOpts...)
+	out := new(StockRes)
+	err := c.cc.Invoke(ctx, Ecom_TransferStock_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecomClient) DeleteSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error) {
+func (c *ecomClient) ListStockMovements(ctx context.Context, in *ListStockMovementsReq, opts ...grpc.CallOption) (*ListStockMovementsRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(SessionRes)
-	err := c.cc.Invoke(ctx, Ecom_DeleteSession_FullMethodName, in, out, cOpts...)
+	out := new(ListStockMovementsRes)
+	err := c.cc.Invoke(ctx, Ecom_ListStockMovements_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecomClient) ListNotificationEvents(ctx context.Context, in *ListNotificationEventsReq, opts ...grpc.CallOption) (*ListNotificationEventsRes, error) {
+func (c *ecomClient) CreateCategory(ctx context.Context, in *CategoryReq, opts ...grpc.CallOption) (*CategoryRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(ListNotificationEventsRes)
-	err := c.cc.Invoke(ctx, Ecom_ListNotificationEvents_FullMethodName, in, out, cOpts...)
+	out := new(CategoryRes)
+	err := c.cc.Invoke(ctx, Ecom_CreateCategory_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecomClient) UpdateNotificationEvent(ctx context.Context, in *UpdateNotificationEventReq, opts ...grpc.CallOption) (*UpdateNotificationEventRes, error) {
+func (c *ecomClient) GetCategory(ctx context.Context, in *CategoryReq, opts ...grpc.CallOption) (*CategoryRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(UpdateNotificationEventRes)
-	err := c.cc.Invoke(ctx, Ecom_UpdateNotificationEvent_FullMethodName, in, out, cOpts...)
+	out := new(CategoryRes)
+	err := c.cc.Invoke(ctx, Ecom_GetCategory_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// EcomServer is the server API for Ecom service.
-// All implementations must embed UnimplementedEcomServer
-// for forward compatibility.
-type EcomServer interface {
-	CreateProduct(context.Context, *ProductReq) (*ProductRes, error)
-	GetProduct(context.Context, *ProductReq) (*ProductRes, error)
-	ListProducts(context.Context, *ProductReq) (*ListProductRes, error)
-	UpdateProduct(context.Context, *ProductReq) (*ProductRes, error)
-	DeleteProduct(context.Context, *ProductReq) (*ProductRes, error)
-	CreateOrder(context.Context, *OrderReq) (*OrderRes, error)
-	GetOrder(context.Context, *OrderReq) (*OrderRes, error)
-	ListOrders(context.Context, *OrderReq) (*ListOrderRes, error)
-	UpdateOrderStatus(context.Context, *OrderReq) (*OrderRes, error)
-	DeleteOrder(context.Context, *OrderReq) (*OrderRes, error)
-	CreateUser(context.Context, *UserReq) (*UserRes, error)
-	GetUser(context.Context, *UserReq) (*UserRes, error)
-	ListUsers(context.Context, *UserReq) (*ListUserRes, error)
-	UpdateUser(context.Context, *UserReq) (*UserRes, error)
-	DeleteUser(context.Context, *UserReq) (*UserRes, error)
-	CreateSession(context.Context, *SessionReq) (*SessionRes, error)
-	GetSession(context.Context, *SessionReq) (*SessionRes, error)
-	RevokeSession(context.Context, *SessionReq) (*SessionRes, error)
-	DeleteSession(context.Context, *SessionReq) (*SessionRes, error)
-	ListNotificationEvents(context.Context, *ListNotificationEventsReq) (*ListNotificationEventsRes, error)
-	UpdateNotificationEvent(context.Context, *UpdateNotificationEventReq) (*UpdateNotificationEventRes, error)
-	mustEmbedUnimplementedEcomServer()
+func (c *ecomClient) ListCategories(ctx context.Context, in *ListCategoriesReq, opts ...grpc.CallOption) (*ListCategoriesRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCategoriesRes)
+	err := c.cc.Invoke(ctx, Ecom_ListCategories_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-// UnimplementedEcomServer must be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedEcomServer struct{}
-
-func (UnimplementedEcomServer) CreateProduct(context.Context, *ProductReq) (*ProductRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateProduct not implemented")
-}
-func (UnimplementedEcomServer) GetProduct(context.Context, *ProductReq) (*ProductRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetProduct not implemented")
+func (c *ecomClient) UpdateCategory(ctx context.Context, in *CategoryReq, opts ...grpc.CallOption) (*CategoryRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CategoryRes)
+	err := c.cc.Invoke(ctx, Ecom_UpdateCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcomServer) ListProducts(context.Context, *ProductReq) (*ListProductRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListProducts not implemented")
+
+func (c *ecomClient) DeleteCategory(ctx context.Context, in *CategoryReq, opts ...grpc.CallOption) (*CategoryRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CategoryRes)
+	err := c.cc.Invoke(ctx, Ecom_DeleteCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcomServer) UpdateProduct(context.Context, *ProductReq) (*ProductRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UpdateProduct not implemented")
+
+func (c *ecomClient) CreatePromotion(ctx context.Context, in *PromotionReq, opts ...grpc.CallOption) (*PromotionRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PromotionRes)
+	err := c.cc.Invoke(ctx, Ecom_CreatePromotion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcomServer) DeleteProduct(context.Context, *ProductReq) (*ProductRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteProduct not implemented")
+
+func (c *ecomClient) GetPromotion(ctx context.Context, in *PromotionReq, opts ...grpc.CallOption) (*PromotionRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PromotionRes)
+	err := c.cc.Invoke(ctx, Ecom_GetPromotion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcomServer) CreateOrder(context.Context, *OrderReq) (*OrderRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateOrder not implemented")
+
+func (c *ecomClient) ListPromotions(ctx context.Context, in *ListPromotionsReq, opts ...grpc.CallOption) (*ListPromotionsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPromotionsRes)
+	err := c.cc.Invoke(ctx, Ecom_ListPromotions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcomServer) GetOrder(context.Context, *OrderReq) (*OrderRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetOrder not implemented")
+
+func (c *ecomClient) UpdatePromotion(ctx context.Context, in *PromotionReq, opts ...grpc.CallOption) (*PromotionRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PromotionRes)
+	err := c.cc.Invoke(ctx, Ecom_UpdatePromotion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcomServer) ListOrders(context.Context, *OrderReq) (*ListOrderRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListOrders not implemented")
+
+func (c *ecomClient) DeletePromotion(ctx context.Context, in *PromotionReq, opts ...grpc.CallOption) (*PromotionRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PromotionRes)
+	err := c.cc.Invoke(ctx, Ecom_DeletePromotion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcomServer) UpdateOrderStatus(context.Context, *OrderReq) (*OrderRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UpdateOrderStatus not implemented")
+
+func (c *ecomClient) CreateTaxRate(ctx context.Context, in *TaxRateReq, opts ...grpc.CallOption) (*TaxRateRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaxRateRes)
+	err := c.cc.Invoke(ctx, Ecom_CreateTaxRate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcomServer) DeleteOrder(context.Context, *OrderReq) (*OrderRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteOrder not implemented")
+
+func (c *ecomClient) ListTaxRates(ctx context.Context, in *ListTaxRatesReq, opts ...grpc.CallOption) (*ListTaxRatesRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTaxRatesRes)
+	err := c.cc.Invoke(ctx, Ecom_ListTaxRates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcomServer) CreateUser(context.Context, *UserReq) (*UserRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+
+func (c *ecomClient) UpdateTaxRate(ctx context.Context, in *TaxRateReq, opts ...grpc.CallOption) (*TaxRateRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaxRateRes)
+	err := c.cc.Invoke(ctx, Ecom_UpdateTaxRate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcomServer) GetUser(context.Context, *UserReq) (*UserRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
+
+func (c *ecomClient) DeleteTaxRate(ctx context.Context, in *TaxRateReq, opts ...grpc.CallOption) (*TaxRateRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaxRateRes)
+	err := c.cc.Invoke(ctx, Ecom_DeleteTaxRate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcomServer) ListUsers(context.Context, *UserReq) (*ListUserRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
+
+func (c *ecomClient) CreateAddress(ctx context.Context, in *AddressReq, opts ...grpc.CallOption) (*AddressRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddressRes)
+	err := c.cc.Invoke(ctx, Ecom_CreateAddress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcomServer) UpdateUser(context.Context, *UserReq) (*UserRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UpdateUser not implemented")
+
+func (c *ecomClient) ListAddresses(ctx context.Context, in *AddressReq, opts ...grpc.CallOption) (*ListAddressesRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAddressesRes)
+	err := c.cc.Invoke(ctx, Ecom_ListAddresses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcomServer) DeleteUser(context.Context, *UserReq) (*UserRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
+
+func (c *ecomClient) UpdateAddress(ctx context.Context, in *AddressReq, opts ...grpc.CallOption) (*AddressRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddressRes)
+	err := c.cc.Invoke(ctx, Ecom_UpdateAddress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcomServer) CreateSession(context.Context, *SessionReq) (*SessionRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateSession not implemented")
+
+func (c *ecomClient) DeleteAddress(ctx context.Context, in *AddressReq, opts ...grpc.CallOption) (*AddressRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddressRes)
+	err := c.cc.Invoke(ctx, Ecom_DeleteAddress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcomServer) GetSession(context.Context, *SessionReq) (*SessionRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetSession not implemented")
+
+func (c *ecomClient) CreateShippingZone(ctx context.Context, in *ShippingZoneReq, opts ...grpc.CallOption) (*ShippingZoneRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShippingZoneRes)
+	err := c.cc.Invoke(ctx, Ecom_CreateShippingZone_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcomServer) RevokeSession(context.Context, *SessionReq) (*SessionRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RevokeSession not implemented")
+
+func (c *ecomClient) ListShippingZones(ctx context.Context, in *ListShippingZonesReq, opts ...grpc.CallOption) (*ListShippingZonesRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListShippingZonesRes)
+	err := c.cc.Invoke(ctx, Ecom_ListShippingZones_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcomServer) DeleteSession(context.Context, *SessionReq) (*SessionRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteSession not implemented")
+
+func (c *ecomClient) UpdateShippingZone(ctx context.Context, in *ShippingZoneReq, opts ...grpc.CallOption) (*ShippingZoneRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShippingZoneRes)
+	err := c.cc.Invoke(ctx, Ecom_UpdateShippingZone_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcomServer) ListNotificationEvents(context.Context, *ListNotificationEventsReq) (*ListNotificationEventsRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListNotificationEvents not implemented")
+
+func (c *ecomClient) DeleteShippingZone(ctx context.Context, in *ShippingZoneReq, opts ...grpc.CallOption) (*ShippingZoneRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShippingZoneRes)
+	err := c.cc.Invoke(ctx, Ecom_DeleteShippingZone_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcomServer) UpdateNotificationEvent(context.Context, *UpdateNotificationEventReq) (*UpdateNotificationEventRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UpdateNotificationEvent not implemented")
+
+func (c *ecomClient) CreateShippingMethod(ctx context.Context, in *ShippingMethodReq, opts ...grpc.CallOption) (*ShippingMethodRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShippingMethodRes)
+	err := c.cc.Invoke(ctx, Ecom_CreateShippingMethod_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcomServer) mustEmbedUnimplementedEcomServer() {}
-func (UnimplementedEcomServer) testEmbeddedByValue()              {}
 
-// UnsafeEcomServer may be embedded to opt out of forward compatibility for this service.
+func (c *ecomClient) UpdateShippingMethod(ctx context.Context, in *ShippingMethodReq, opts ...grpc.CallOption) (*ShippingMethodRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShippingMethodRes)
+	err := c.cc.Invoke(ctx, Ecom_UpdateShippingMethod_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) DeleteShippingMethod(ctx context.Context, in *ShippingMethodReq, opts ...grpc.CallOption) (*ShippingMethodRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShippingMethodRes)
+	err := c.cc.Invoke(ctx, Ecom_DeleteShippingMethod_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) QuoteShipping(ctx context.Context, in *ShippingQuoteReq, opts ...grpc.CallOption) (*ShippingQuoteRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShippingQuoteRes)
+	err := c.cc.Invoke(ctx, Ecom_QuoteShipping_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) CreateOrder(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*OrderRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderRes)
+	err := c.cc.Invoke(ctx, Ecom_CreateOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) GetOrder(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*OrderRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderRes)
+	err := c.cc.Invoke(ctx, Ecom_GetOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) ListOrders(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*ListOrderRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOrderRes)
+	err := c.cc.Invoke(ctx, Ecom_ListOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) ListUserOrders(ctx context.Context, in *ListUserOrdersReq, opts ...grpc.CallOption) (*ListOrderRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOrderRes)
+	err := c.cc.Invoke(ctx, Ecom_ListUserOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) UpdateOrderStatus(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*OrderRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderRes)
+	err := c.cc.Invoke(ctx, Ecom_UpdateOrderStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) ListOrderStatusChanges(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*ListOrderStatusChangesRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOrderStatusChangesRes)
+	err := c.cc.Invoke(ctx, Ecom_ListOrderStatusChanges_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) DeleteOrder(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*OrderRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderRes)
+	err := c.cc.Invoke(ctx, Ecom_DeleteOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) GetOrderInvoice(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*InvoiceRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InvoiceRes)
+	err := c.cc.Invoke(ctx, Ecom_GetOrderInvoice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) PayOrder(ctx context.Context, in *PaymentReq, opts ...grpc.CallOption) (*PaymentRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PaymentRes)
+	err := c.cc.Invoke(ctx, Ecom_PayOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) ListOrderPayments(ctx context.Context, in *PaymentReq, opts ...grpc.CallOption) (*ListPaymentsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPaymentsRes)
+	err := c.cc.Invoke(ctx, Ecom_ListOrderPayments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) HandlePaymentEvent(ctx context.Context, in *PaymentEventReq, opts ...grpc.CallOption) (*PaymentRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PaymentRes)
+	err := c.cc.Invoke(ctx, Ecom_HandlePaymentEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) CreateGiftCard(ctx context.Context, in *GiftCardReq, opts ...grpc.CallOption) (*GiftCardRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GiftCardRes)
+	err := c.cc.Invoke(ctx, Ecom_CreateGiftCard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) GetGiftCard(ctx context.Context, in *GiftCardReq, opts ...grpc.CallOption) (*GiftCardRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GiftCardRes)
+	err := c.cc.Invoke(ctx, Ecom_GetGiftCard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) ListGiftCards(ctx context.Context, in *GiftCardReq, opts ...grpc.CallOption) (*ListGiftCardsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListGiftCardsRes)
+	err := c.cc.Invoke(ctx, Ecom_ListGiftCards_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) UpdateGiftCard(ctx context.Context, in *GiftCardReq, opts ...grpc.CallOption) (*GiftCardRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GiftCardRes)
+	err := c.cc.Invoke(ctx, Ecom_UpdateGiftCard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) AdjustGiftCard(ctx context.Context, in *GiftCardReq, opts ...grpc.CallOption) (*GiftCardRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GiftCardRes)
+	err := c.cc.Invoke(ctx, Ecom_AdjustGiftCard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) GetStoreCredit(ctx context.Context, in *StoreCreditReq, opts ...grpc.CallOption) (*StoreCreditRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StoreCreditRes)
+	err := c.cc.Invoke(ctx, Ecom_GetStoreCredit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) AdjustStoreCredit(ctx context.Context, in *StoreCreditReq, opts ...grpc.CallOption) (*StoreCreditRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StoreCreditRes)
+	err := c.cc.Invoke(ctx, Ecom_AdjustStoreCredit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) GetLoyalty(ctx context.Context, in *LoyaltyReq, opts ...grpc.CallOption) (*LoyaltyRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LoyaltyRes)
+	err := c.cc.Invoke(ctx, Ecom_GetLoyalty_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) CreateReturn(ctx context.Context, in *ReturnReq, opts ...grpc.CallOption) (*ReturnRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReturnRes)
+	err := c.cc.Invoke(ctx, Ecom_CreateReturn_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) ListOrderReturns(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*ListReturnsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListReturnsRes)
+	err := c.cc.Invoke(ctx, Ecom_ListOrderReturns_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) ListReturns(ctx context.Context, in *ListReturnsReq, opts ...grpc.CallOption) (*ListReturnsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListReturnsRes)
+	err := c.cc.Invoke(ctx, Ecom_ListReturns_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) UpdateReturnStatus(ctx context.Context, in *ReturnReq, opts ...grpc.CallOption) (*ReturnRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReturnRes)
+	err := c.cc.Invoke(ctx, Ecom_UpdateReturnStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) CreateReview(ctx context.Context, in *ReviewReq, opts ...grpc.CallOption) (*ReviewRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReviewRes)
+	err := c.cc.Invoke(ctx, Ecom_CreateReview_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) ListProductReviews(ctx context.Context, in *ListReviewsReq, opts ...grpc.CallOption) (*ListReviewsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListReviewsRes)
+	err := c.cc.Invoke(ctx, Ecom_ListProductReviews_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) UpdateReview(ctx context.Context, in *ReviewReq, opts ...grpc.CallOption) (*ReviewRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReviewRes)
+	err := c.cc.Invoke(ctx, Ecom_UpdateReview_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) DeleteReview(ctx context.Context, in *ReviewReq, opts ...grpc.CallOption) (*ReviewRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReviewRes)
+	err := c.cc.Invoke(ctx, Ecom_DeleteReview_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) ModerateReview(ctx context.Context, in *ReviewReq, opts ...grpc.CallOption) (*ReviewRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReviewRes)
+	err := c.cc.Invoke(ctx, Ecom_ModerateReview_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) GetCart(ctx context.Context, in *CartReq, opts ...grpc.CallOption) (*CartRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CartRes)
+	err := c.cc.Invoke(ctx, Ecom_GetCart_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) AddCartItem(ctx context.Context, in *CartReq, opts ...grpc.CallOption) (*CartRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CartRes)
+	err := c.cc.Invoke(ctx, Ecom_AddCartItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) UpdateCartItem(ctx context.Context, in *CartReq, opts ...grpc.CallOption) (*CartRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CartRes)
+	err := c.cc.Invoke(ctx, Ecom_UpdateCartItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) RemoveCartItem(ctx context.Context, in *CartReq, opts ...grpc.CallOption) (*CartRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CartRes)
+	err := c.cc.Invoke(ctx, Ecom_RemoveCartItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) ClearCart(ctx context.Context, in *CartReq, opts ...grpc.CallOption) (*CartRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CartRes)
+	err := c.cc.Invoke(ctx, Ecom_ClearCart_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) CheckoutCart(ctx context.Context, in *CheckoutCartReq, opts ...grpc.CallOption) (*OrderRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderRes)
+	err := c.cc.Invoke(ctx, Ecom_CheckoutCart_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) CreateWishlist(ctx context.Context, in *WishlistReq, opts ...grpc.CallOption) (*WishlistRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WishlistRes)
+	err := c.cc.Invoke(ctx, Ecom_CreateWishlist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) GetWishlist(ctx context.Context, in *WishlistReq, opts ...grpc.CallOption) (*WishlistRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WishlistRes)
+	err := c.cc.Invoke(ctx, Ecom_GetWishlist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) ListWishlists(ctx context.Context, in *WishlistReq, opts ...grpc.CallOption) (*ListWishlistsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWishlistsRes)
+	err := c.cc.Invoke(ctx, Ecom_ListWishlists_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) UpdateWishlist(ctx context.Context, in *WishlistReq, opts ...grpc.CallOption) (*WishlistRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WishlistRes)
+	err := c.cc.Invoke(ctx, Ecom_UpdateWishlist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) DeleteWishlist(ctx context.Context, in *WishlistReq, opts ...grpc.CallOption) (*WishlistRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WishlistRes)
+	err := c.cc.Invoke(ctx, Ecom_DeleteWishlist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) AddWishlistItem(ctx context.Context, in *WishlistItemReq, opts ...grpc.CallOption) (*WishlistRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WishlistRes)
+	err := c.cc.Invoke(ctx, Ecom_AddWishlistItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) RemoveWishlistItem(ctx context.Context, in *WishlistItemReq, opts ...grpc.CallOption) (*WishlistRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WishlistRes)
+	err := c.cc.Invoke(ctx, Ecom_RemoveWishlistItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) MoveWishlistItemToCart(ctx context.Context, in *WishlistItemReq, opts ...grpc.CallOption) (*CartRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CartRes)
+	err := c.cc.Invoke(ctx, Ecom_MoveWishlistItemToCart_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) GetSharedWishlist(ctx context.Context, in *SharedWishlistReq, opts ...grpc.CallOption) (*WishlistRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WishlistRes)
+	err := c.cc.Invoke(ctx, Ecom_GetSharedWishlist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) CreateUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserRes)
+	err := c.cc.Invoke(ctx, Ecom_CreateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) GetUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserRes)
+	err := c.cc.Invoke(ctx, Ecom_GetUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) ListUsers(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*ListUserRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUserRes)
+	err := c.cc.Invoke(ctx, Ecom_ListUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) UpdateUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserRes)
+	err := c.cc.Invoke(ctx, Ecom_UpdateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) DeleteUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserRes)
+	err := c.cc.Invoke(ctx, Ecom_DeleteUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) SetUserTaxExempt(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserRes)
+	err := c.cc.Invoke(ctx, Ecom_SetUserTaxExempt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) CreateSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionRes)
+	err := c.cc.Invoke(ctx, Ecom_CreateSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) GetSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionRes)
+	err := c.cc.Invoke(ctx, Ecom_GetSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) RevokeSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionRes)
+	err := c.cc.Invoke(ctx, Ecom_RevokeSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) DeleteSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionRes)
+	err := c.cc.Invoke(ctx, Ecom_DeleteSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) ListNotificationEvents(ctx context.Context, in *ListNotificationEventsReq, opts ...grpc.CallOption) (*ListNotificationEventsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNotificationEventsRes)
+	err := c.cc.Invoke(ctx, Ecom_ListNotificationEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) ClaimNotificationEvents(ctx context.Context, in *ClaimNotificationEventsReq, opts ...grpc.CallOption) (*ListNotificationEventsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNotificationEventsRes)
+	err := c.cc.Invoke(ctx, Ecom_ClaimNotificationEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecomClient) UpdateNotificationEvent(ctx context.Context, in *UpdateNotificationEventReq, opts ...grpc.CallOption) (*UpdateNotificationEventRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateNotificationEventRes)
+	err := c.cc.Invoke(ctx, Ecom_UpdateNotificationEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EcomServer is the server API for Ecom service.
+// All implementations must embed UnimplementedEcomServer
+// for forward compatibility.
+type EcomServer interface {
+	CreateProduct(context.Context, *ProductReq) (*ProductRes, error)
+	GetProduct(context.Context, *ProductReq) (*ProductRes, error)
+	ListProducts(context.Context, *ListProductsReq) (*ListProductRes, error)
+	SearchProducts(context.Context, *SearchProductsReq) (*SearchProductsRes, error)
+	UpdateProduct(context.Context, *ProductReq) (*ProductRes, error)
+	DeleteProduct(context.Context, *ProductReq) (*ProductRes, error)
+	CreateVariant(context.Context, *VariantReq) (*VariantRes, error)
+	UpdateVariant(context.Context, *VariantReq) (*VariantRes, error)
+	DeleteVariant(context.Context, *VariantReq) (*VariantRes, error)
+	CreateScheduledPrice(context.Context, *ScheduledPriceReq) (*ScheduledPriceRes, error)
+	ListScheduledPrices(context.Context, *ScheduledPriceReq) (*ListScheduledPricesRes, error)
+	UpdateScheduledPrice(context.Context, *ScheduledPriceReq) (*ScheduledPriceRes, error)
+	DeleteScheduledPrice(context.Context, *ScheduledPriceReq) (*ScheduledPriceRes, error)
+	CreateWarehouse(context.Context, *WarehouseReq) (*WarehouseRes, error)
+	ListWarehouses(context.Context, *ListWarehousesReq) (*ListWarehousesRes, error)
+	UpdateWarehouse(context.Context, *WarehouseReq) (*WarehouseRes, error)
+	GetProductStock(context.Context, *StockReq) (*StockRes, error)
+	AdjustStock(context.Context, *StockReq) (*StockRes, error)
+	TransferStock(context.Context, *StockReq) (*StockRes, error)
+	ListStockMovements(context.Context, *ListStockMovementsReq) (*ListStockMovementsRes, error)
+	CreateCategory(context.Context, *CategoryReq) (*CategoryRes, error)
+	GetCategory(context.Context, *CategoryReq) (*CategoryRes, error)
+	ListCategories(context.Context, *ListCategoriesReq) (*ListCategoriesRes, error)
+	UpdateCategory(context.Context, *CategoryReq) (*CategoryRes, error)
+	DeleteCategory(context.Context, *CategoryReq) (*CategoryRes, error)
+	CreatePromotion(context.Context, *PromotionReq) (*PromotionRes, error)
+	GetPromotion(context.Context, *PromotionReq) (*PromotionRes, error)
+	ListPromotions(context.Context, *ListPromotionsReq) (*ListPromotionsRes, error)
+	UpdatePromotion(context.Context, *PromotionReq) (*PromotionRes, error)
+	DeletePromotion(context.Context, *PromotionReq) (*PromotionRes, error)
+	CreateTaxRate(context.Context, *TaxRateReq) (*TaxRateRes, error)
+	ListTaxRates(context.Context, *ListTaxRatesReq) (*ListTaxRatesRes, error)
+	UpdateTaxRate(context.Context, *TaxRateReq) (*TaxRateRes, error)
+	DeleteTaxRate(context.Context, *TaxRateReq) (*TaxRateRes, error)
+	CreateAddress(context.Context, *AddressReq) (*AddressRes, error)
+	ListAddresses(context.Context, *AddressReq) (*ListAddressesRes, error)
+	UpdateAddress(context.Context, *AddressReq) (*AddressRes, error)
+	DeleteAddress(context.Context, *AddressReq) (*AddressRes, error)
+	CreateShippingZone(context.Context, *ShippingZoneReq) (*ShippingZoneRes, error)
+	ListShippingZones(context.Context, *ListShippingZonesReq) (*ListShippingZonesRes, error)
+	UpdateShippingZone(context.Context, *ShippingZoneReq) (*ShippingZoneRes, error)
+	DeleteShippingZone(context.Context, *ShippingZoneReq) (*ShippingZoneRes, error)
+	CreateShippingMethod(context.Context, *ShippingMethodReq) (*ShippingMethodRes, error)
+	UpdateShippingMethod(context.Context, *ShippingMethodReq) (*ShippingMethodRes, error)
+	DeleteShippingMethod(context.Context, *ShippingMethodReq) (*ShippingMethodRes, error)
+	QuoteShipping(context.Context, *ShippingQuoteReq) (*ShippingQuoteRes, error)
+	CreateOrder(context.Context, *OrderReq) (*OrderRes, error)
+	GetOrder(context.Context, *OrderReq) (*OrderRes, error)
+	ListOrders(context.Context, *OrderReq) (*ListOrderRes, error)
+	ListUserOrders(context.Context, *ListUserOrdersReq) (*ListOrderRes, error)
+	UpdateOrderStatus(context.Context, *OrderReq) (*OrderRes, error)
+	ListOrderStatusChanges(context.Context, *OrderReq) (*ListOrderStatusChangesRes, error)
+	DeleteOrder(context.Context, *OrderReq) (*OrderRes, error)
+	GetOrderInvoice(context.Context, *OrderReq) (*InvoiceRes, error)
+	PayOrder(context.Context, *PaymentReq) (*PaymentRes, error)
+	ListOrderPayments(context.Context, *PaymentReq) (*ListPaymentsRes, error)
+	HandlePaymentEvent(context.Context, *PaymentEventReq) (*PaymentRes, error)
+	CreateGiftCard(context.Context, *GiftCardReq) (*GiftCardRes, error)
+	GetGiftCard(context.Context, *GiftCardReq) (*GiftCardRes, error)
+	ListGiftCards(context.Context, *GiftCardReq) (*ListGiftCardsRes, error)
+	UpdateGiftCard(context.Context, *GiftCardReq) (*GiftCardRes, error)
+	AdjustGiftCard(context.Context, *GiftCardReq) (*GiftCardRes, error)
+	GetStoreCredit(context.Context, *StoreCreditReq) (*StoreCreditRes, error)
+	AdjustStoreCredit(context.Context, *StoreCreditReq) (*StoreCreditRes, error)
+	GetLoyalty(context.Context, *LoyaltyReq) (*LoyaltyRes, error)
+	CreateReturn(context.Context, *ReturnReq) (*ReturnRes, error)
+	ListOrderReturns(context.Context, *OrderReq) (*ListReturnsRes, error)
+	ListReturns(context.Context, *ListReturnsReq) (*ListReturnsRes, error)
+	UpdateReturnStatus(context.Context, *ReturnReq) (*ReturnRes, error)
+	CreateReview(context.Context, *ReviewReq) (*ReviewRes, error)
+	ListProductReviews(context.Context, *ListReviewsReq) (*ListReviewsRes, error)
+	UpdateReview(context.Context, *ReviewReq) (*ReviewRes, error)
+	DeleteReview(context.Context, *ReviewReq) (*ReviewRes, error)
+	ModerateReview(context.Context, *ReviewReq) (*ReviewRes, error)
+	GetCart(context.Context, *CartReq) (*CartRes, error)
+	AddCartItem(context.Context, *CartReq) (*CartRes, error)
+	UpdateCartItem(context.Context, *CartReq) (*CartRes, error)
+	RemoveCartItem(context.Context, *CartReq) (*CartRes, error)
+	ClearCart(context.Context, *CartReq) (*CartRes, error)
+	CheckoutCart(context.Context, *CheckoutCartReq) (*OrderRes, error)
+	CreateWishlist(context.Context, *WishlistReq) (*WishlistRes, error)
+	GetWishlist(context.Context, *WishlistReq) (*WishlistRes, error)
+	ListWishlists(context.Context, *WishlistReq) (*ListWishlistsRes, error)
+	UpdateWishlist(context.Context, *WishlistReq) (*WishlistRes, error)
+	DeleteWishlist(context.Context, *WishlistReq) (*WishlistRes, error)
+	AddWishlistItem(context.Context, *WishlistItemReq) (*WishlistRes, error)
+	RemoveWishlistItem(context.Context, *WishlistItemReq) (*WishlistRes, error)
+	MoveWishlistItemToCart(context.Context, *WishlistItemReq) (*CartRes, error)
+	GetSharedWishlist(context.Context, *SharedWishlistReq) (*WishlistRes, error)
+	CreateUser(context.Context, *UserReq) (*UserRes, error)
+	GetUser(context.Context, *UserReq) (*UserRes, error)
+	ListUsers(context.Context, *UserReq) (*ListUserRes, error)
+	UpdateUser(context.Context, *UserReq) (*UserRes, error)
+	DeleteUser(context.Context, *UserReq) (*UserRes, error)
+	SetUserTaxExempt(context.Context, *UserReq) (*UserRes, error)
+	CreateSession(context.Context, *SessionReq) (*SessionRes, error)
+	GetSession(context.Context, *SessionReq) (*SessionRes, error)
+	RevokeSession(context.Context, *SessionReq) (*SessionRes, error)
+	DeleteSession(context.Context, *SessionReq) (*SessionRes, error)
+	ListNotificationEvents(context.Context, *ListNotificationEventsReq) (*ListNotificationEventsRes, error)
+	ClaimNotificationEvents(context.Context, *ClaimNotificationEventsReq) (*ListNotificationEventsRes, error)
+	UpdateNotificationEvent(context.Context, *UpdateNotificationEventReq) (*UpdateNotificationEventRes, error)
+	mustEmbedUnimplementedEcomServer()
+}
+
+// UnimplementedEcomServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedEcomServer struct{}
+
+func (UnimplementedEcomServer) CreateProduct(context.Context, *ProductReq) (*ProductRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateProduct not implemented")
+}
+func (UnimplementedEcomServer) GetProduct(context.Context, *ProductReq) (*ProductRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProduct not implemented")
+}
+func (UnimplementedEcomServer) ListProducts(context.Context, *ListProductsReq) (*ListProductRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProducts not implemented")
+}
+func (UnimplementedEcomServer) SearchProducts(context.Context, *SearchProductsReq) (*SearchProductsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchProducts not implemented")
+}
+func (UnimplementedEcomServer) UpdateProduct(context.Context, *ProductReq) (*ProductRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProduct not implemented")
+}
+func (UnimplementedEcomServer) DeleteProduct(context.Context, *ProductReq) (*ProductRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteProduct not implemented")
+}
+func (UnimplementedEcomServer) CreateVariant(context.Context, *VariantReq) (*VariantRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateVariant not implemented")
+}
+func (UnimplementedEcomServer) UpdateVariant(context.Context, *VariantReq) (*VariantRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateVariant not implemented")
+}
+func (UnimplementedEcomServer) DeleteVariant(context.Context, *VariantReq) (*VariantRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteVariant not implemented")
+}
+func (UnimplementedEcomServer) CreateScheduledPrice(context.Context, *ScheduledPriceReq) (*ScheduledPriceRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateScheduledPrice not implemented")
+}
+func (UnimplementedEcomServer) ListScheduledPrices(context.Context, *ScheduledPriceReq) (*ListScheduledPricesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListScheduledPrices not implemented")
+}
+func (UnimplementedEcomServer) UpdateScheduledPrice(context.Context, *ScheduledPriceReq) (*ScheduledPriceRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateScheduledPrice not implemented")
+}
+func (UnimplementedEcomServer) DeleteScheduledPrice(context.Context, *ScheduledPriceReq) (*ScheduledPriceRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteScheduledPrice not implemented")
+}
+func (UnimplementedEcomServer) CreateWarehouse(context.Context, *WarehouseReq) (*WarehouseRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWarehouse not implemented")
+}
+func (UnimplementedEcomServer) ListWarehouses(context.Context, *ListWarehousesReq) (*ListWarehousesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWarehouses not implemented")
+}
+func (UnimplementedEcomServer) UpdateWarehouse(context.Context, *WarehouseReq) (*WarehouseRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateWarehouse not implemented")
+}
+func (UnimplementedEcomServer) GetProductStock(context.Context, *StockReq) (*StockRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProductStock not implemented")
+}
+func (UnimplementedEcomServer) AdjustStock(context.Context, *StockReq) (*StockRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdjustStock not implemented")
+}
+func (UnimplementedEcomServer) TransferStock(context.Context, *StockReq) (*StockRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferStock not implemented")
+}
+func (UnimplementedEcomServer) ListStockMovements(context.Context, *ListStockMovementsReq) (*ListStockMovementsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListStockMovements not implemented")
+}
+func (UnimplementedEcomServer) CreateCategory(context.Context, *CategoryReq) (*CategoryRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCategory not implemented")
+}
+func (UnimplementedEcomServer) GetCategory(context.Context, *CategoryReq) (*CategoryRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCategory not implemented")
+}
+func (UnimplementedEcomServer) ListCategories(context.Context, *ListCategoriesReq) (*ListCategoriesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCategories not implemented")
+}
+func (UnimplementedEcomServer) UpdateCategory(context.Context, *CategoryReq) (*CategoryRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCategory not implemented")
+}
+func (UnimplementedEcomServer) DeleteCategory(context.Context, *CategoryReq) (*CategoryRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCategory not implemented")
+}
+func (UnimplementedEcomServer) CreatePromotion(context.Context, *PromotionReq) (*PromotionRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePromotion not implemented")
+}
+func (UnimplementedEcomServer) GetPromotion(context.Context, *PromotionReq) (*PromotionRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPromotion not implemented")
+}
+func (UnimplementedEcomServer) ListPromotions(context.Context, *ListPromotionsReq) (*ListPromotionsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPromotions not implemented")
+}
+func (UnimplementedEcomServer) UpdatePromotion(context.Context, *PromotionReq) (*PromotionRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePromotion not implemented")
+}
+func (UnimplementedEcomServer) DeletePromotion(context.Context, *PromotionReq) (*PromotionRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePromotion not implemented")
+}
+func (UnimplementedEcomServer) CreateTaxRate(context.Context, *TaxRateReq) (*TaxRateRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTaxRate not implemented")
+}
+func (UnimplementedEcomServer) ListTaxRates(context.Context, *ListTaxRatesReq) (*ListTaxRatesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTaxRates not implemented")
+}
+func (UnimplementedEcomServer) UpdateTaxRate(context.Context, *TaxRateReq) (*TaxRateRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTaxRate not implemented")
+}
+func (UnimplementedEcomServer) DeleteTaxRate(context.Context, *TaxRateReq) (*TaxRateRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTaxRate not implemented")
+}
+func (UnimplementedEcomServer) CreateAddress(context.Context, *AddressReq) (*AddressRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAddress not implemented")
+}
+func (UnimplementedEcomServer) ListAddresses(context.Context, *AddressReq) (*ListAddressesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAddresses not implemented")
+}
+func (UnimplementedEcomServer) UpdateAddress(context.Context, *AddressReq) (*AddressRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAddress not implemented")
+}
+func (UnimplementedEcomServer) DeleteAddress(context.Context, *AddressReq) (*AddressRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAddress not implemented")
+}
+func (UnimplementedEcomServer) CreateShippingZone(context.Context, *ShippingZoneReq) (*ShippingZoneRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateShippingZone not implemented")
+}
+func (UnimplementedEcomServer) ListShippingZones(context.Context, *ListShippingZonesReq) (*ListShippingZonesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListShippingZones not implemented")
+}
+func (UnimplementedEcomServer) UpdateShippingZone(context.Context, *ShippingZoneReq) (*ShippingZoneRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateShippingZone not implemented")
+}
+func (UnimplementedEcomServer) DeleteShippingZone(context.Context, *ShippingZoneReq) (*ShippingZoneRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteShippingZone not implemented")
+}
+func (UnimplementedEcomServer) CreateShippingMethod(context.Context, *ShippingMethodReq) (*ShippingMethodRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateShippingMethod not implemented")
+}
+func (UnimplementedEcomServer) UpdateShippingMethod(context.Context, *ShippingMethodReq) (*ShippingMethodRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateShippingMethod not implemented")
+}
+func (UnimplementedEcomServer) DeleteShippingMethod(context.Context, *ShippingMethodReq) (*ShippingMethodRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteShippingMethod not implemented")
+}
+func (UnimplementedEcomServer) QuoteShipping(context.Context, *ShippingQuoteReq) (*ShippingQuoteRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QuoteShipping not implemented")
+}
+func (UnimplementedEcomServer) CreateOrder(context.Context, *OrderReq) (*OrderRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateOrder not implemented")
+}
+func (UnimplementedEcomServer) GetOrder(context.Context, *OrderReq) (*OrderRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrder not implemented")
+}
+func (UnimplementedEcomServer) ListOrders(context.Context, *OrderReq) (*ListOrderRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOrders not implemented")
+}
+func (UnimplementedEcomServer) ListUserOrders(context.Context, *ListUserOrdersReq) (*ListOrderRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUserOrders not implemented")
+}
+func (UnimplementedEcomServer) UpdateOrderStatus(context.Context, *OrderReq) (*OrderRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateOrderStatus not implemented")
+}
+func (UnimplementedEcomServer) ListOrderStatusChanges(context.Context, *OrderReq) (*ListOrderStatusChangesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOrderStatusChanges not implemented")
+}
+func (UnimplementedEcomServer) DeleteOrder(context.Context, *OrderReq) (*OrderRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteOrder not implemented")
+}
+func (UnimplementedEcomServer) GetOrderInvoice(context.Context, *OrderReq) (*InvoiceRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrderInvoice not implemented")
+}
+func (UnimplementedEcomServer) PayOrder(context.Context, *PaymentReq) (*PaymentRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PayOrder not implemented")
+}
+func (UnimplementedEcomServer) ListOrderPayments(context.Context, *PaymentReq) (*ListPaymentsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOrderPayments not implemented")
+}
+func (UnimplementedEcomServer) HandlePaymentEvent(context.Context, *PaymentEventReq) (*PaymentRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HandlePaymentEvent not implemented")
+}
+func (UnimplementedEcomServer) CreateGiftCard(context.Context, *GiftCardReq) (*GiftCardRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGiftCard not implemented")
+}
+func (UnimplementedEcomServer) GetGiftCard(context.Context, *GiftCardReq) (*GiftCardRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGiftCard not implemented")
+}
+func (UnimplementedEcomServer) ListGiftCards(context.Context, *GiftCardReq) (*ListGiftCardsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGiftCards not implemented")
+}
+func (UnimplementedEcomServer) UpdateGiftCard(context.Context, *GiftCardReq) (*GiftCardRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateGiftCard not implemented")
+}
+func (UnimplementedEcomServer) AdjustGiftCard(context.Context, *GiftCardReq) (*GiftCardRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdjustGiftCard not implemented")
+}
+func (UnimplementedEcomServer) GetStoreCredit(context.Context, *StoreCreditReq) (*StoreCreditRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStoreCredit not implemented")
+}
+func (UnimplementedEcomServer) AdjustStoreCredit(context.Context, *StoreCreditReq) (*StoreCreditRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdjustStoreCredit not implemented")
+}
+func (UnimplementedEcomServer) GetLoyalty(context.Context, *LoyaltyReq) (*LoyaltyRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLoyalty not implemented")
+}
+func (UnimplementedEcomServer) CreateReturn(context.Context, *ReturnReq) (*ReturnRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateReturn not implemented")
+}
+func (UnimplementedEcomServer) ListOrderReturns(context.Context, *OrderReq) (*ListReturnsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOrderReturns not implemented")
+}
+func (UnimplementedEcomServer) ListReturns(context.Context, *ListReturnsReq) (*ListReturnsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReturns not implemented")
+}
+func (UnimplementedEcomServer) UpdateReturnStatus(context.Context, *ReturnReq) (*ReturnRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateReturnStatus not implemented")
+}
+func (UnimplementedEcomServer) CreateReview(context.Context, *ReviewReq) (*ReviewRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateReview not implemented")
+}
+func (UnimplementedEcomServer) ListProductReviews(context.Context, *ListReviewsReq) (*ListReviewsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProductReviews not implemented")
+}
+func (UnimplementedEcomServer) UpdateReview(context.Context, *ReviewReq) (*ReviewRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateReview not implemented")
+}
+func (UnimplementedEcomServer) DeleteReview(context.Context, *ReviewReq) (*ReviewRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteReview not implemented")
+}
+func (UnimplementedEcomServer) ModerateReview(context.Context, *ReviewReq) (*ReviewRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModerateReview not implemented")
+}
+func (UnimplementedEcomServer) GetCart(context.Context, *CartReq) (*CartRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCart not implemented")
+}
+func (UnimplementedEcomServer) AddCartItem(context.Context, *CartReq) (*CartRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddCartItem not implemented")
+}
+func (UnimplementedEcomServer) UpdateCartItem(context.Context, *CartReq) (*CartRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCartItem not implemented")
+}
+func (UnimplementedEcomServer) RemoveCartItem(context.Context, *CartReq) (*CartRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveCartItem not implemented")
+}
+func (UnimplementedEcomServer) ClearCart(context.Context, *CartReq) (*CartRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearCart not implemented")
+}
+func (UnimplementedEcomServer) CheckoutCart(context.Context, *CheckoutCartReq) (*OrderRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckoutCart not implemented")
+}
+func (UnimplementedEcomServer) CreateWishlist(context.Context, *WishlistReq) (*WishlistRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWishlist not implemented")
+}
+func (UnimplementedEcomServer) GetWishlist(context.Context, *WishlistReq) (*WishlistRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWishlist not implemented")
+}
+func (UnimplementedEcomServer) ListWishlists(context.Context, *WishlistReq) (*ListWishlistsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWishlists not implemented")
+}
+func (UnimplementedEcomServer) UpdateWishlist(context.Context, *WishlistReq) (*WishlistRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateWishlist not implemented")
+}
+func (UnimplementedEcomServer) DeleteWishlist(context.Context, *WishlistReq) (*WishlistRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWishlist not implemented")
+}
+func (UnimplementedEcomServer) AddWishlistItem(context.Context, *WishlistItemReq) (*WishlistRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddWishlistItem not implemented")
+}
+func (UnimplementedEcomServer) RemoveWishlistItem(context.Context, *WishlistItemReq) (*WishlistRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveWishlistItem not implemented")
+}
+func (UnimplementedEcomServer) MoveWishlistItemToCart(context.Context, *WishlistItemReq) (*CartRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveWishlistItemToCart not implemented")
+}
+func (UnimplementedEcomServer) GetSharedWishlist(context.Context, *SharedWishlistReq) (*WishlistRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSharedWishlist not implemented")
+}
+func (UnimplementedEcomServer) CreateUser(context.Context, *UserReq) (*UserRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedEcomServer) GetUser(context.Context, *UserReq) (*UserRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
+}
+func (UnimplementedEcomServer) ListUsers(context.Context, *UserReq) (*ListUserRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
+}
+func (UnimplementedEcomServer) UpdateUser(context.Context, *UserReq) (*UserRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUser not implemented")
+}
+func (UnimplementedEcomServer) DeleteUser(context.Context, *UserReq) (*UserRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
+}
+func (UnimplementedEcomServer) SetUserTaxExempt(context.Context, *UserReq) (*UserRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUserTaxExempt not implemented")
+}
+func (UnimplementedEcomServer) CreateSession(context.Context, *SessionReq) (*SessionRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSession not implemented")
+}
+func (UnimplementedEcomServer) GetSession(context.Context, *SessionReq) (*SessionRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSession not implemented")
+}
+func (UnimplementedEcomServer) RevokeSession(context.Context, *SessionReq) (*SessionRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeSession not implemented")
+}
+func (UnimplementedEcomServer) DeleteSession(context.Context, *SessionReq) (*SessionRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSession not implemented")
+}
+func (UnimplementedEcomServer) ListNotificationEvents(context.Context, *ListNotificationEventsReq) (*ListNotificationEventsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNotificationEvents not implemented")
+}
+func (UnimplementedEcomServer) ClaimNotificationEvents(context.Context, *ClaimNotificationEventsReq) (*ListNotificationEventsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClaimNotificationEvents not implemented")
+}
+func (UnimplementedEcomServer) UpdateNotificationEvent(context.Context, *UpdateNotificationEventReq) (*UpdateNotificationEventRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateNotificationEvent not implemented")
+}
+func (UnimplementedEcomServer) mustEmbedUnimplementedEcomServer() {}
+func (UnimplementedEcomServer) testEmbeddedByValue()              {}
+
+// UnsafeEcomServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to EcomServer will
 // result in compilation errors.
 type UnsafeEcomServer interface {
 	mustEmbedUnimplementedEcomServer()
 }
 
-func RegisterEcomServer(s grpc.ServiceRegistrar, srv EcomServer) {
-	// If the following call pancis, it indicates UnimplementedEcomServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
+func RegisterEcomServer(s grpc.ServiceRegistrar, srv EcomServer) {
+	// If the following call pancis, it indicates UnimplementedEcomServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Ecom_ServiceDesc, srv)
+}
+
+func _Ecom_CreateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProductReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).CreateProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_CreateProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).CreateProduct(ctx, req.(*ProductReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_GetProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProductReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).GetProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_GetProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).GetProduct(ctx, req.(*ProductReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_ListProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProductsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).ListProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_ListProducts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).ListProducts(ctx, req.(*ListProductsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_SearchProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchProductsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).SearchProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_SearchProducts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).SearchProducts(ctx, req.(*SearchProductsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_UpdateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProductReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).UpdateProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_UpdateProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).UpdateProduct(ctx, req.(*ProductReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_DeleteProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProductReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).DeleteProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_DeleteProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).DeleteProduct(ctx, req.(*ProductReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_CreateVariant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VariantReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).CreateVariant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_CreateVariant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).CreateVariant(ctx, req.(*VariantReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_UpdateVariant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VariantReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).UpdateVariant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_UpdateVariant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).UpdateVariant(ctx, req.(*VariantReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_DeleteVariant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VariantReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).DeleteVariant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_DeleteVariant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).DeleteVariant(ctx, req.(*VariantReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_CreateScheduledPrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduledPriceReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).CreateScheduledPrice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_CreateScheduledPrice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).CreateScheduledPrice(ctx, req.(*ScheduledPriceReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_ListScheduledPrices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduledPriceReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).ListScheduledPrices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_ListScheduledPrices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).ListScheduledPrices(ctx, req.(*ScheduledPriceReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_UpdateScheduledPrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduledPriceReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).UpdateScheduledPrice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_UpdateScheduledPrice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).UpdateScheduledPrice(ctx, req.(*ScheduledPriceReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_DeleteScheduledPrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduledPriceReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).DeleteScheduledPrice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_DeleteScheduledPrice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).DeleteScheduledPrice(ctx, req.(*ScheduledPriceReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_CreateWarehouse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WarehouseReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).CreateWarehouse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_CreateWarehouse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).CreateWarehouse(ctx, req.(*WarehouseReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_ListWarehouses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWarehousesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).ListWarehouses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_ListWarehouses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).ListWarehouses(ctx, req.(*ListWarehousesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_UpdateWarehouse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WarehouseReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).UpdateWarehouse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_UpdateWarehouse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).UpdateWarehouse(ctx, req.(*WarehouseReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_GetProductStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StockReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).GetProductStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_GetProductStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).GetProductStock(ctx, req.(*StockReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_AdjustStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StockReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).AdjustStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_AdjustStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).AdjustStock(ctx, req.(*StockReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_TransferStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StockReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).TransferStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_TransferStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).TransferStock(ctx, req.(*StockReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_ListStockMovements_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListStockMovementsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).ListStockMovements(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_ListStockMovements_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).ListStockMovements(ctx, req.(*ListStockMovementsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_CreateCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CategoryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).CreateCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_CreateCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).CreateCategory(ctx, req.(*CategoryReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_GetCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CategoryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).GetCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_GetCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).GetCategory(ctx, req.(*CategoryReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_ListCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCategoriesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).ListCategories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_ListCategories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).ListCategories(ctx, req.(*ListCategoriesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_UpdateCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CategoryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).UpdateCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_UpdateCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).UpdateCategory(ctx, req.(*CategoryReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_DeleteCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CategoryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).DeleteCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_DeleteCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).DeleteCategory(ctx, req.(*CategoryReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_CreatePromotion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PromotionReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).CreatePromotion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_CreatePromotion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).CreatePromotion(ctx, req.(*PromotionReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_GetPromotion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PromotionReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).GetPromotion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_GetPromotion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).GetPromotion(ctx, req.(*PromotionReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_ListPromotions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPromotionsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).ListPromotions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_ListPromotions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).ListPromotions(ctx, req.(*ListPromotionsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_UpdatePromotion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PromotionReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).UpdatePromotion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_UpdatePromotion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).UpdatePromotion(ctx, req.(*PromotionReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_DeletePromotion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PromotionReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).DeletePromotion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_DeletePromotion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).DeletePromotion(ctx, req.(*PromotionReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_CreateTaxRate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaxRateReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).CreateTaxRate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_CreateTaxRate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).CreateTaxRate(ctx, req.(*TaxRateReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_ListTaxRates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTaxRatesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).ListTaxRates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_ListTaxRates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).ListTaxRates(ctx, req.(*ListTaxRatesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_UpdateTaxRate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaxRateReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).UpdateTaxRate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_UpdateTaxRate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).UpdateTaxRate(ctx, req.(*TaxRateReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_DeleteTaxRate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaxRateReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).DeleteTaxRate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_DeleteTaxRate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).DeleteTaxRate(ctx, req.(*TaxRateReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_CreateAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).CreateAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_CreateAddress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).CreateAddress(ctx, req.(*AddressReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_ListAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).ListAddresses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_ListAddresses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).ListAddresses(ctx, req.(*AddressReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_UpdateAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).UpdateAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_UpdateAddress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).UpdateAddress(ctx, req.(*AddressReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_DeleteAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).DeleteAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_DeleteAddress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).DeleteAddress(ctx, req.(*AddressReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_CreateShippingZone_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShippingZoneReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).CreateShippingZone(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_CreateShippingZone_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).CreateShippingZone(ctx, req.(*ShippingZoneReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_ListShippingZones_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListShippingZonesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).ListShippingZones(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_ListShippingZones_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).ListShippingZones(ctx, req.(*ListShippingZonesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_UpdateShippingZone_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShippingZoneReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).UpdateShippingZone(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_UpdateShippingZone_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).UpdateShippingZone(ctx, req.(*ShippingZoneReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_DeleteShippingZone_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShippingZoneReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).DeleteShippingZone(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_DeleteShippingZone_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).DeleteShippingZone(ctx, req.(*ShippingZoneReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_CreateShippingMethod_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShippingMethodReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).CreateShippingMethod(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_CreateShippingMethod_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).CreateShippingMethod(ctx, req.(*ShippingMethodReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_UpdateShippingMethod_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShippingMethodReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).UpdateShippingMethod(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_UpdateShippingMethod_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).UpdateShippingMethod(ctx, req.(*ShippingMethodReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_DeleteShippingMethod_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShippingMethodReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).DeleteShippingMethod(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_DeleteShippingMethod_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).DeleteShippingMethod(ctx, req.(*ShippingMethodReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_QuoteShipping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShippingQuoteReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).QuoteShipping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_QuoteShipping_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).QuoteShipping(ctx, req.(*ShippingQuoteReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_CreateOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).CreateOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_CreateOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).CreateOrder(ctx, req.(*OrderReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).GetOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_GetOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).GetOrder(ctx, req.(*OrderReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_ListOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).ListOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_ListOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).ListOrders(ctx, req.(*OrderReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_ListUserOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUserOrdersReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).ListUserOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_ListUserOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).ListUserOrders(ctx, req.(*ListUserOrdersReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_UpdateOrderStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).UpdateOrderStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_UpdateOrderStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).UpdateOrderStatus(ctx, req.(*OrderReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_ListOrderStatusChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).ListOrderStatusChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_ListOrderStatusChanges_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).ListOrderStatusChanges(ctx, req.(*OrderReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_DeleteOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).DeleteOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_DeleteOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).DeleteOrder(ctx, req.(*OrderReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_GetOrderInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).GetOrderInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_GetOrderInvoice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).GetOrderInvoice(ctx, req.(*OrderReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_PayOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaymentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).PayOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_PayOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).PayOrder(ctx, req.(*PaymentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_ListOrderPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaymentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).ListOrderPayments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_ListOrderPayments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).ListOrderPayments(ctx, req.(*PaymentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_HandlePaymentEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaymentEventReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).HandlePaymentEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_HandlePaymentEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).HandlePaymentEvent(ctx, req.(*PaymentEventReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_CreateGiftCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GiftCardReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).CreateGiftCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_CreateGiftCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).CreateGiftCard(ctx, req.(*GiftCardReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_GetGiftCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GiftCardReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).GetGiftCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_GetGiftCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).GetGiftCard(ctx, req.(*GiftCardReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_ListGiftCards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GiftCardReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).ListGiftCards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_ListGiftCards_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).ListGiftCards(ctx, req.(*GiftCardReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_UpdateGiftCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GiftCardReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).UpdateGiftCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_UpdateGiftCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).UpdateGiftCard(ctx, req.(*GiftCardReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_AdjustGiftCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GiftCardReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).AdjustGiftCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_AdjustGiftCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).AdjustGiftCard(ctx, req.(*GiftCardReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_GetStoreCredit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StoreCreditReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).GetStoreCredit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_GetStoreCredit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).GetStoreCredit(ctx, req.(*StoreCreditReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_AdjustStoreCredit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StoreCreditReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).AdjustStoreCredit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_AdjustStoreCredit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).AdjustStoreCredit(ctx, req.(*StoreCreditReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_GetLoyalty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoyaltyReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).GetLoyalty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_GetLoyalty_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).GetLoyalty(ctx, req.(*LoyaltyReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_CreateReturn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReturnReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).CreateReturn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_CreateReturn_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).CreateReturn(ctx, req.(*ReturnReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_ListOrderReturns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).ListOrderReturns(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_ListOrderReturns_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).ListOrderReturns(ctx, req.(*OrderReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_ListReturns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReturnsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).ListReturns(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_ListReturns_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).ListReturns(ctx, req.(*ListReturnsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_UpdateReturnStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReturnReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).UpdateReturnStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_UpdateReturnStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).UpdateReturnStatus(ctx, req.(*ReturnReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_CreateReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).CreateReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_CreateReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).CreateReview(ctx, req.(*ReviewReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_ListProductReviews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReviewsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).ListProductReviews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_ListProductReviews_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).ListProductReviews(ctx, req.(*ListReviewsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_UpdateReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).UpdateReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_UpdateReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).UpdateReview(ctx, req.(*ReviewReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_DeleteReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).DeleteReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_DeleteReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).DeleteReview(ctx, req.(*ReviewReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_ModerateReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).ModerateReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_ModerateReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).ModerateReview(ctx, req.(*ReviewReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_GetCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).GetCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_GetCart_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).GetCart(ctx, req.(*CartReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_AddCartItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).AddCartItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_AddCartItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).AddCartItem(ctx, req.(*CartReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_UpdateCartItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).UpdateCartItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_UpdateCartItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).UpdateCartItem(ctx, req.(*CartReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_RemoveCartItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).RemoveCartItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_RemoveCartItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).RemoveCartItem(ctx, req.(*CartReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecom_ClearCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartReq)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	s.RegisterService(&Ecom_ServiceDesc, srv)
+	if interceptor == nil {
+		return srv.(EcomServer).ClearCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_ClearCart_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).ClearCart(ctx, req.(*CartReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Ecom_CreateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ProductReq)
+func _Ecom_CheckoutCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckoutCartReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(EcomServer).CreateProduct(ctx, in)
+		return srv.(EcomServer).CheckoutCart(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Ecom_CreateProduct_FullMethodName,
+		FullMethod: Ecom_CheckoutCart_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EcomServer).CreateProduct(ctx, req.(*ProductReq))
+		return srv.(EcomServer).CheckoutCart(ctx, req.(*CheckoutCartReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Ecom_GetProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ProductReq)
+func _Ecom_CreateWishlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WishlistReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(EcomServer).GetProduct(ctx, in)
+		return srv.(EcomServer).CreateWishlist(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Ecom_GetProduct_FullMethodName,
+		FullMethod: Ecom_CreateWishlist_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EcomServer).GetProduct(ctx, req.(*ProductReq))
+		return srv.(EcomServer).CreateWishlist(ctx, req.(*WishlistReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Ecom_ListProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ProductReq)
+func _Ecom_GetWishlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WishlistReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(EcomServer).ListProducts(ctx, in)
+		return srv.(EcomServer).GetWishlist(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Ecom_ListProducts_FullMethodName,
+		FullMethod: Ecom_GetWishlist_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EcomServer).ListProducts(ctx, req.(*ProductReq))
+		return srv.(EcomServer).GetWishlist(ctx, req.(*WishlistReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Ecom_UpdateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ProductReq)
+func _Ecom_ListWishlists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WishlistReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(EcomServer).UpdateProduct(ctx, in)
+		return srv.(EcomServer).ListWishlists(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Ecom_UpdateProduct_FullMethodName,
+		FullMethod: Ecom_ListWishlists_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EcomServer).UpdateProduct(ctx, req.(*ProductReq))
+		return srv.(EcomServer).ListWishlists(ctx, req.(*WishlistReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Ecom_DeleteProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ProductReq)
+func _Ecom_UpdateWishlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WishlistReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(EcomServer).DeleteProduct(ctx, in)
+		return srv.(EcomServer).UpdateWishlist(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Ecom_DeleteProduct_FullMethodName,
+		FullMethod: Ecom_UpdateWishlist_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EcomServer).DeleteProduct(ctx, req.(*ProductReq))
+		return srv.(EcomServer).UpdateWishlist(ctx, req.(*WishlistReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Ecom_CreateOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(OrderReq)
+func _Ecom_DeleteWishlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WishlistReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(EcomServer).CreateOrder(ctx, in)
+		return srv.(EcomServer).DeleteWishlist(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Ecom_CreateOrder_FullMethodName,
+		FullMethod: Ecom_DeleteWishlist_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EcomServer).CreateOrder(ctx, req.(*OrderReq))
+		return srv.(EcomServer).DeleteWishlist(ctx, req.(*WishlistReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Ecom_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(OrderReq)
+func _Ecom_AddWishlistItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WishlistItemReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(EcomServer).GetOrder(ctx, in)
+		return srv.(EcomServer).AddWishlistItem(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Ecom_GetOrder_FullMethodName,
+		FullMethod: Ecom_AddWishlistItem_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EcomServer).GetOrder(ctx, req.(*OrderReq))
+		return srv.(EcomServer).AddWishlistItem(ctx, req.(*WishlistItemReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Ecom_ListOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(OrderReq)
+func _Ecom_RemoveWishlistItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WishlistItemReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(EcomServer).ListOrders(ctx, in)
+		return srv.(EcomServer).RemoveWishlistItem(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Ecom_ListOrders_FullMethodName,
+		FullMethod: Ecom_RemoveWishlistItem_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EcomServer).ListOrders(ctx, req.(*OrderReq))
+		return srv.(EcomServer).RemoveWishlistItem(ctx, req.(*WishlistItemReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Ecom_UpdateOrderStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(OrderReq)
+func _Ecom_MoveWishlistItemToCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WishlistItemReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(EcomServer).UpdateOrderStatus(ctx, in)
+		return srv.(EcomServer).MoveWishlistItemToCart(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Ecom_UpdateOrderStatus_FullMethodName,
+		FullMethod: Ecom_MoveWishlistItemToCart_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EcomServer).UpdateOrderStatus(ctx, req.(*OrderReq))
+		return srv.(EcomServer).MoveWishlistItemToCart(ctx, req.(*WishlistItemReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Ecom_DeleteOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(OrderReq)
+func _Ecom_GetSharedWishlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SharedWishlistReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(EcomServer).DeleteOrder(ctx, in)
+		return srv.(EcomServer).GetSharedWishlist(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Ecom_DeleteOrder_FullMethodName,
+		FullMethod: Ecom_GetSharedWishlist_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EcomServer).DeleteOrder(ctx, req.(*OrderReq))
+		return srv.(EcomServer).GetSharedWishlist(ctx, req.(*SharedWishlistReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -676,6 +3394,24 @@ func _Ecom_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Ecom_SetUserTaxExempt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).SetUserTaxExempt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_SetUserTaxExempt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).SetUserTaxExempt(ctx, req.(*UserReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Ecom_CreateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SessionReq)
 	if err := dec(in); err != nil {
@@ -766,6 +3502,24 @@ func _Ecom_ListNotificationEvents_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Ecom_ClaimNotificationEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClaimNotificationEventsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcomServer).ClaimNotificationEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecom_ClaimNotificationEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcomServer).ClaimNotificationEvents(ctx, req.(*ClaimNotificationEventsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Ecom_UpdateNotificationEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdateNotificationEventReq)
 	if err := dec(in); err != nil {
@@ -803,6 +3557,10 @@ var Ecom_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListProducts",
 			Handler:    _Ecom_ListProducts_Handler,
 		},
+		{
+			MethodName: "SearchProducts",
+			Handler:    _Ecom_SearchProducts_Handler,
+		},
 		{
 			MethodName: "UpdateProduct",
 			Handler:    _Ecom_UpdateProduct_Handler,
@@ -811,6 +3569,166 @@ var Ecom_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteProduct",
 			Handler:    _Ecom_DeleteProduct_Handler,
 		},
+		{
+			MethodName: "CreateVariant",
+			Handler:    _Ecom_CreateVariant_Handler,
+		},
+		{
+			MethodName: "UpdateVariant",
+			Handler:    _Ecom_UpdateVariant_Handler,
+		},
+		{
+			MethodName: "DeleteVariant",
+			Handler:    _Ecom_DeleteVariant_Handler,
+		},
+		{
+			MethodName: "CreateScheduledPrice",
+			Handler:    _Ecom_CreateScheduledPrice_Handler,
+		},
+		{
+			MethodName: "ListScheduledPrices",
+			Handler:    _Ecom_ListScheduledPrices_Handler,
+		},
+		{
+			MethodName: "UpdateScheduledPrice",
+			Handler:    _Ecom_UpdateScheduledPrice_Handler,
+		},
+		{
+			MethodName: "DeleteScheduledPrice",
+			Handler:    _Ecom_DeleteScheduledPrice_Handler,
+		},
+		{
+			MethodName: "CreateWarehouse",
+			Handler:    _Ecom_CreateWarehouse_Handler,
+		},
+		{
+			MethodName: "ListWarehouses",
+			Handler:    _Ecom_ListWarehouses_Handler,
+		},
+		{
+			MethodName: "UpdateWarehouse",
+			Handler:    _Ecom_UpdateWarehouse_Handler,
+		},
+		{
+			MethodName: "GetProductStock",
+			Handler:    _Ecom_GetProductStock_Handler,
+		},
+		{
+			MethodName: "AdjustStock",
+			Handler:    _Ecom_AdjustStock_Handler,
+		},
+		{
+			MethodName: "TransferStock",
+			Handler:    _Ecom_TransferStock_Handler,
+		},
+		{
+			MethodName: "ListStockMovements",
+			Handler:    _Ecom_ListStockMovements_Handler,
+		},
+		{
+			MethodName: "CreateCategory",
+			Handler:    _Ecom_CreateCategory_Handler,
+		},
+		{
+			MethodName: "GetCategory",
+			Handler:    _Ecom_GetCategory_Handler,
+		},
+		{
+			MethodName: "ListCategories",
+			Handler:    _Ecom_ListCategories_Handler,
+		},
+		{
+			MethodName: "UpdateCategory",
+			Handler:    _Ecom_UpdateCategory_Handler,
+		},
+		{
+			MethodName: "DeleteCategory",
+			Handler:    _Ecom_DeleteCategory_Handler,
+		},
+		{
+			MethodName: "CreatePromotion",
+			Handler:    _Ecom_CreatePromotion_Handler,
+		},
+		{
+			MethodName: "GetPromotion",
+			Handler:    _Ecom_GetPromotion_Handler,
+		},
+		{
+			MethodName: "ListPromotions",
+			Handler:    _Ecom_ListPromotions_Handler,
+		},
+		{
+			MethodName: "UpdatePromotion",
+			Handler:    _Ecom_UpdatePromotion_Handler,
+		},
+		{
+			MethodName: "DeletePromotion",
+			Handler:    _Ecom_DeletePromotion_Handler,
+		},
+		{
+			MethodName: "CreateTaxRate",
+			Handler:    _Ecom_CreateTaxRate_Handler,
+		},
+		{
+			MethodName: "ListTaxRates",
+			Handler:    _Ecom_ListTaxRates_Handler,
+		},
+		{
+			MethodName: "UpdateTaxRate",
+			Handler:    _Ecom_UpdateTaxRate_Handler,
+		},
+		{
+			MethodName: "DeleteTaxRate",
+			Handler:    _Ecom_DeleteTaxRate_Handler,
+		},
+		{
+			MethodName: "CreateAddress",
+			Handler:    _Ecom_CreateAddress_Handler,
+		},
+		{
+			MethodName: "ListAddresses",
+			Handler:    _Ecom_ListAddresses_Handler,
+		},
+		{
+			MethodName: "UpdateAddress",
+			Handler:    _Ecom_UpdateAddress_Handler,
+		},
+		{
+			MethodName: "DeleteAddress",
+			Handler:    _Ecom_DeleteAddress_Handler,
+		},
+		{
+			MethodName: "CreateShippingZone",
+			Handler:    _Ecom_CreateShippingZone_Handler,
+		},
+		{
+			MethodName: "ListShippingZones",
+			Handler:    _Ecom_ListShippingZones_Handler,
+		},
+		{
+			MethodName: "UpdateShippingZone",
+			Handler:    _Ecom_UpdateShippingZone_Handler,
+		},
+		{
+			MethodName: "DeleteShippingZone",
+			Handler:    _Ecom_DeleteShippingZone_Handler,
+		},
+		{
+			MethodName: "CreateShippingMethod",
+			Handler:    _Ecom_CreateShippingMethod_Handler,
+		},
+		{
+			MethodName: "UpdateShippingMethod",
+			Handler:    _Ecom_UpdateShippingMethod_Handler,
+		},
+		{
+			MethodName: "DeleteShippingMethod",
+			Handler:    _Ecom_DeleteShippingMethod_Handler,
+		},
+		{
+			MethodName: "QuoteShipping",
+			Handler:    _Ecom_QuoteShipping_Handler,
+		},
 		{
 			MethodName: "CreateOrder",
 			Handler:    _Ecom_CreateOrder_Handler,
@@ -823,14 +3741,166 @@ var Ecom_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListOrders",
 			Handler:    _Ecom_ListOrders_Handler,
 		},
+		{
+			MethodName: "ListUserOrders",
+			Handler:    _Ecom_ListUserOrders_Handler,
+		},
 		{
 			MethodName: "UpdateOrderStatus",
 			Handler:    _Ecom_UpdateOrderStatus_Handler,
 		},
+		{
+			MethodName: "ListOrderStatusChanges",
+			Handler:    _Ecom_ListOrderStatusChanges_Handler,
+		},
 		{
 			MethodName: "DeleteOrder",
 			Handler:    _Ecom_DeleteOrder_Handler,
 		},
+		{
+			MethodName: "GetOrderInvoice",
+			Handler:    _Ecom_GetOrderInvoice_Handler,
+		},
+		{
+			MethodName: "PayOrder",
+			Handler:    _Ecom_PayOrder_Handler,
+		},
+		{
+			MethodName: "ListOrderPayments",
+			Handler:    _Ecom_ListOrderPayments_Handler,
+		},
+		{
+			MethodName: "HandlePaymentEvent",
+			Handler:    _Ecom_HandlePaymentEvent_Handler,
+		},
+		{
+			MethodName: "CreateGiftCard",
+			Handler:    _Ecom_CreateGiftCard_Handler,
+		},
+		{
+			MethodName: "GetGiftCard",
+			Handler:    _Ecom_GetGiftCard_Handler,
+		},
+		{
+			MethodName: "ListGiftCards",
+			Handler:    _Ecom_ListGiftCards_Handler,
+		},
+		{
+			MethodName: "UpdateGiftCard",
+			Handler:    _Ecom_UpdateGiftCard_Handler,
+		},
+		{
+			MethodName: "AdjustGiftCard",
+			Handler:    _Ecom_AdjustGiftCard_Handler,
+		},
+		{
+			MethodName: "GetStoreCredit",
+			Handler:    _Ecom_GetStoreCredit_Handler,
+		},
+		{
+			MethodName: "AdjustStoreCredit",
+			Handler:    _Ecom_AdjustStoreCredit_Handler,
+		},
+		{
+			MethodName: "GetLoyalty",
+			Handler:    _Ecom_GetLoyalty_Handler,
+		},
+		{
+			MethodName: "CreateReturn",
+			Handler:    _Ecom_CreateReturn_Handler,
+		},
+		{
+			MethodName: "ListOrderReturns",
+			Handler:    _Ecom_ListOrderReturns_Handler,
+		},
+		{
+			MethodName: "ListReturns",
+			Handler:    _Ecom_ListReturns_Handler,
+		},
+		{
+			MethodName: "UpdateReturnStatus",
+			Handler:    _Ecom_UpdateReturnStatus_Handler,
+		},
+		{
+			MethodName: "CreateReview",
+			Handler:    _Ecom_CreateReview_Handler,
+		},
+		{
+			MethodName: "ListProductReviews",
+			Handler:    _Ecom_ListProductReviews_Handler,
+		},
+		{
+			MethodName: "UpdateReview",
+			Handler:    _Ecom_UpdateReview_Handler,
+		},
+		{
+			MethodName: "DeleteReview",
+			Handler:    _Ecom_DeleteReview_Handler,
+		},
+		{
+			MethodName: "ModerateReview",
+			Handler:    _Ecom_ModerateReview_Handler,
+		},
+		{
+			MethodName: "GetCart",
+			Handler:    _Ecom_GetCart_Handler,
+		},
+		{
+			MethodName: "AddCartItem",
+			Handler:    _Ecom_AddCartItem_Handler,
+		},
+		{
+			MethodName: "UpdateCartItem",
+			Handler:    _Ecom_UpdateCartItem_Handler,
+		},
+		{
+			MethodName: "RemoveCartItem",
+			Handler:    _Ecom_RemoveCartItem_Handler,
+		},
+		{
+			MethodName: "ClearCart",
+			Handler:    _Ecom_ClearCart_Handler,
+		},
+		{
+			MethodName: "CheckoutCart",
+			Handler:    _Ecom_CheckoutCart_Handler,
+		},
+		{
+			MethodName: "CreateWishlist",
+			Handler:    _Ecom_CreateWishlist_Handler,
+		},
+		{
+			MethodName: "GetWishlist",
+			Handler:    _Ecom_GetWishlist_Handler,
+		},
+		{
+			MethodName: "ListWishlists",
+			Handler:    _Ecom_ListWishlists_Handler,
+		},
+		{
+			MethodName: "UpdateWishlist",
+			Handler:    _Ecom_UpdateWishlist_Handler,
+		},
+		{
+			MethodName: "DeleteWishlist",
+			Handler:    _Ecom_DeleteWishlist_Handler,
+		},
+		{
+			MethodName: "AddWishlistItem",
+			Handler:    _Ecom_AddWishlistItem_Handler,
+		},
+		{
+			MethodName: "RemoveWishlistItem",
+			Handler:    _Ecom_RemoveWishlistItem_Handler,
+		},
+		{
+			MethodName: "MoveWishlistItemToCart",
+			Handler:    _Ecom_MoveWishlistItemToCart_Handler,
+		},
+		{
+			MethodName: "GetSharedWishlist",
+			Handler:    _Ecom_GetSharedWishlist_Handler,
+		},
 		{
 			MethodName: "CreateUser",
 			Handler:    _Ecom_CreateUser_Handler,
@@ -851,6 +3921,10 @@ var Ecom_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteUser",
 			Handler:    _Ecom_DeleteUser_Handler,
 		},
+		{
+			MethodName: "SetUserTaxExempt",
+			Handler:    _Ecom_SetUserTaxExempt_Handler,
+		},
 		{
 			MethodName: "CreateSession",
 			Handler:    _Ecom_CreateSession_Handler,
@@ -871,6 +3945,10 @@ var Ecom_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListNotificationEvents",
 			Handler:    _Ecom_ListNotificationEvents_Handler,
 		},
+		{
+			MethodName: "ClaimNotificationEvents",
+			Handler:    _Ecom_ClaimNotificationEvents_Handler,
+		},
 		{
 			MethodName: "UpdateNotificationEvent",
 			Handler:    _Ecom_UpdateNotificationEvent_Handler,
@@ -0,0 +1,230 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/storer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Constants
+const (
+	maxAddressLabel = 64
+)
+
+// CreateAddress saves an address to the user's address book. The first
+// address saved becomes the default.
+func (s *Server) CreateAddress(ctx context.Context, req *pb.AddressReq) (*pb.AddressRes, error) {
+	a, err := toStorerUserAddress(req)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.storer.ListUserAddresses(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	a.IsDefault = a.IsDefault || len(existing) == 0
+
+	created, err := s.storer.CreateAddress(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getAddressRes(ctx, created.ID)
+}
+
+// ListAddresses returns the user's address book, default address first.
+func (s *Server) ListAddresses(ctx context.Context, req *pb.AddressReq) (*pb.ListAddressesRes, error) {
+	addresses, err := s.storer.ListUserAddresses(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.ListAddressesRes{
+		Addresses: make([]*pb.AddressRes, 0, len(addresses)),
+	}
+	for _, a := range addresses {
+		res.Addresses = append(res.Addresses, toPBAddressRes(a))
+	}
+
+	return res, nil
+}
+
+// UpdateAddress replaces a saved address. Orders keep the address they were
+// placed with. The default address stays the default until another one is
+// made the default.
+func (s *Server) UpdateAddress(ctx context.Context, req *pb.AddressReq) (*pb.AddressRes, error) {
+	existing, err := s.getOwnAddress(ctx, req.GetUserId(), req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := toStorerUserAddress(req)
+	if err != nil {
+		return nil, err
+	}
+	a.ID = existing.ID
+	a.IsDefault = a.IsDefault || existing.IsDefault
+	a.CreatedAt = existing.CreatedAt
+	a.UpdatedAt = toTimePtr(time.Now())
+	if _, err := s.storer.UpdateAddress(ctx, a); err != nil {
+		return nil, err
+	}
+
+	return s.getAddressRes(ctx, a.ID)
+}
+
+// DeleteAddress removes a saved address. When it was the default, the
+// oldest address left becomes the default.
+func (s *Server) DeleteAddress(ctx context.Context, req *pb.AddressReq) (*pb.AddressRes, error) {
+	a, err := s.getOwnAddress(ctx, req.GetUserId(), req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storer.DeleteAddress(ctx, a.ID); err != nil {
+		return nil, err
+	}
+
+	if a.IsDefault {
+		left, err := s.storer.ListUserAddresses(ctx, a.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if len(left) > 0 {
+			next := left[0]
+			next.IsDefault = true
+			if _, err := s.storer.UpdateAddress(ctx, next); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &pb.AddressRes{}, nil
+}
+
+// getOwnAddress loads a saved address, which must belong to userID.
+func (s *Server) getOwnAddress(ctx context.Context, userID, id int64) (*storer.UserAddress, error) {
+	a, err := s.storer.GetAddress(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "address %d not found", id)
+		}
+		return nil, err
+	}
+
+	if a.UserID != userID {
+		return nil, status.Errorf(codes.PermissionDenied, "address %d belongs to another user", id)
+	}
+
+	return a, nil
+}
+
+func (s *Server) getAddressRes(ctx context.Context, id int64) (*pb.AddressRes, error) {
+	a, err := s.storer.GetAddress(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBAddressRes(a), nil
+}
+
+// defaultAddress returns the user's default address, or nil when the address
+// book is empty.
+func (s *Server) defaultAddress(ctx context.Context, userID int64) (*storer.Address, error) {
+	addresses, err := s.storer.ListUserAddresses(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+
+	return addresses[0].Address(), nil
+}
+
+// orderAddresses resolves the shipping and billing addresses of an order. A
+// saved address is referred to by id; otherwise the address sent with the
+// order is used. Shipping falls back to the customer's default address and
+// billing to the shipping address.
+func (s *Server) orderAddresses(ctx context.Context, o *pb.OrderReq) (shipping, billing *storer.Address, err error) {
+	switch {
+	case o.GetShippingAddressId() != 0:
+		a, err := s.getOwnAddress(ctx, o.GetUserId(), o.GetShippingAddressId())
+		if err != nil {
+			return nil, nil, err
+		}
+		shipping = a.Address()
+	case o.GetShippingAddress() != nil:
+		if shipping, err = toStorerAddress(o.GetShippingAddress()); err != nil {
+			return nil, nil, err
+		}
+	default:
+		if shipping, err = s.defaultAddress(ctx, o.GetUserId()); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	switch {
+	case o.GetBillingAddressId() != 0:
+		a, err := s.getOwnAddress(ctx, o.GetUserId(), o.GetBillingAddressId())
+		if err != nil {
+			return nil, nil, err
+		}
+		billing = a.Address()
+	case o.GetBillingAddress() != nil:
+		if billing, err = toStorerAddress(o.GetBillingAddress()); err != nil {
+			return nil, nil, err
+		}
+	case shipping != nil:
+		c := *shipping
+		billing = &c
+	}
+
+	return shipping, billing, nil
+}
+
+// toStorerUserAddress validates an address to save. Unlike addresses sent
+// with an order, saved ones must be complete enough to ship to.
+func toStorerUserAddress(req *pb.AddressReq) (*storer.UserAddress, error) {
+	if req.GetAddress() == nil {
+		return nil, status.Error(codes.InvalidArgument, "address is empty")
+	}
+	addr, err := toStorerAddress(req.GetAddress())
+	if err != nil {
+		return nil, err
+	}
+
+	label := strings.TrimSpace(req.GetLabel())
+	switch {
+	case utf8.RuneCountInString(label) > maxAddressLabel:
+		return nil, status.Errorf(codes.InvalidArgument, "address label is longer than %d characters", maxAddressLabel)
+	case addr.Name == "":
+		return nil, status.Error(codes.InvalidArgument, "address name is empty")
+	case addr.Line1 == "":
+		return nil, status.Error(codes.InvalidArgument, "address line1 is empty")
+	case addr.City == "":
+		return nil, status.Error(codes.InvalidArgument, "address city is empty")
+	}
+
+	return &storer.UserAddress{
+		UserID:     req.GetUserId(),
+		Label:      label,
+		Name:       addr.Name,
+		Line1:      addr.Line1,
+		Line2:      addr.Line2,
+		City:       addr.City,
+		Region:     addr.Region,
+		PostalCode: addr.PostalCode,
+		Country:    addr.Country,
+		IsDefault:  req.GetIsDefault(),
+	}, nil
+}
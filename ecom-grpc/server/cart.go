@@ -0,0 +1,244 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/storer"
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// GetCart returns the user's cart priced at the current catalog prices.
+func (s *Server) GetCart(ctx context.Context, req *pb.CartReq) (*pb.CartRes, error) {
+	return s.getCartRes(ctx, req.GetUserId())
+}
+
+// AddCartItem adds units of a product or variant on top of those already in
+// the cart.
+func (s *Server) AddCartItem(ctx context.Context, req *pb.CartReq) (*pb.CartRes, error) {
+	if req.GetQuantity() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid quantity %d", req.GetQuantity())
+	}
+
+	cart, err := s.storer.GetCart(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	quantity := req.GetQuantity()
+	if ci := findCartItem(cart, req.GetProductId(), req.GetVariantId()); ci != nil {
+		quantity += ci.Quantity
+	}
+	if err := s.setCartItem(ctx, req.GetUserId(), req.GetProductId(), req.GetVariantId(), quantity); err != nil {
+		return nil, err
+	}
+
+	return s.getCartRes(ctx, req.GetUserId())
+}
+
+// UpdateCartItem sets the quantity of a product or variant that is already in
+// the cart.
+func (s *Server) UpdateCartItem(ctx context.Context, req *pb.CartReq) (*pb.CartRes, error) {
+	if req.GetQuantity() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid quantity %d", req.GetQuantity())
+	}
+
+	cart, err := s.storer.GetCart(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if findCartItem(cart, req.GetProductId(), req.GetVariantId()) == nil {
+		return nil, notInCart(req)
+	}
+	if err := s.setCartItem(ctx, req.GetUserId(), req.GetProductId(), req.GetVariantId(), req.GetQuantity()); err != nil {
+		return nil, err
+	}
+
+	return s.getCartRes(ctx, req.GetUserId())
+}
+
+func (s *Server) RemoveCartItem(ctx context.Context, req *pb.CartReq) (*pb.CartRes, error) {
+	err := s.storer.RemoveCartItem(ctx, req.GetUserId(), req.GetProductId(), req.GetVariantId())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notInCart(req)
+		}
+		return nil, err
+	}
+
+	return s.getCartRes(ctx, req.GetUserId())
+}
+
+func (s *Server) ClearCart(ctx context.Context, req *pb.CartReq) (*pb.CartRes, error) {
+	if err := s.storer.ClearCart(ctx, req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	return s.getCartRes(ctx, req.GetUserId())
+}
+
+// CheckoutCart places an order for everything in the cart through
+// CreateOrder, so it is priced and its stock reserved like any other order,
+// and then empties the cart.
+func (s *Server) CheckoutCart(ctx context.Context, req *pb.CheckoutCartReq) (*pb.OrderRes, error) {
+	cart, err := s.storer.GetCart(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, status.Error(codes.FailedPrecondition, "cart is empty")
+	}
+
+	items := make([]*pb.OrderItem, 0, len(cart.Items))
+	for _, ci := range cart.Items {
+		items = append(items, &pb.OrderItem{
+			ProductId: ci.ProductID,
+			VariantId: ci.VariantID,
+			Quantity:  ci.Quantity,
+		})
+	}
+
+	order, err := s.CreateOrder(ctx, &pb.OrderReq{
+		UserId:            req.GetUserId(),
+		UserEmail:         req.GetUserEmail(),
+		PaymentMethod:     req.GetPaymentMethod(),
+		Items:             items,
+		TotalPrice:        req.GetTotalPrice(),
+		CouponCode:        req.GetCouponCode(),
+		ShippingAddress:   req.GetShippingAddress(),
+		ShippingAddressId: req.GetShippingAddressId(),
+		BillingAddress:    req.GetBillingAddress(),
+		BillingAddressId:  req.GetBillingAddressId(),
+		ShippingMethodId:  req.GetShippingMethodId(),
+		GiftCardCodes:     req.GetGiftCardCodes(),
+		StoreCredit:       req.GetStoreCredit(),
+		LoyaltyPoints:     req.GetLoyaltyPoints(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the order is placed at this point, so failing the call would invite a
+	// retry that orders everything twice
+	if err := s.storer.ClearCart(ctx, req.GetUserId()); err != nil {
+		log.Printf("error clearing cart of user %d after order %d: %v", req.GetUserId(), order.GetId(), err)
+	}
+
+	return order, nil
+}
+
+// setCartItem checks the product or variant exists and has enough stock
+// before setting its quantity in the cart.
+func (s *Server) setCartItem(ctx context.Context, userID, productID, variantID, quantity int64) error {
+	if quantity > maxLineQuantity {
+		return status.Errorf(codes.InvalidArgument, "quantity of product %d is more than %d", productID, maxLineQuantity)
+	}
+
+	p, err := s.getProduct(ctx, productID)
+	if err != nil {
+		return err
+	}
+	variants, err := s.storer.ListProductVariants(ctx, []int64{p.ID})
+	if err != nil {
+		return err
+	}
+	v, err := resolveVariant(p, variants, variantID)
+	if err != nil {
+		return err
+	}
+	if stock := variantStock(p, v); quantity > stock {
+		return status.Errorf(codes.FailedPrecondition, "requested %d of product %q (id %d), %d in stock", quantity, variantName(p, v), p.ID, stock)
+	}
+
+	return s.storer.SetCartItem(ctx, userID, productID, variantID, quantity)
+}
+
+func findCartItem(c *storer.Cart, productID, variantID int64) *storer.CartItem {
+	for i := range c.Items {
+		if c.Items[i].ProductID == productID && c.Items[i].VariantID == variantID {
+			return &c.Items[i]
+		}
+	}
+	return nil
+}
+
+func notInCart(req *pb.CartReq) error {
+	if req.GetVariantId() != 0 {
+		return status.Errorf(codes.NotFound, "variant %d of product %d is not in the cart", req.GetVariantId(), req.GetProductId())
+	}
+	return status.Errorf(codes.NotFound, "product %d is not in the cart", req.GetProductId())
+}
+
+// getCartRes loads the cart and prices it from the catalog.
+func (s *Server) getCartRes(ctx context.Context, userID int64) (*pb.CartRes, error) {
+	cart, err := s.storer.GetCart(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(cart.Items))
+	for _, ci := range cart.Items {
+		ids = append(ids, ci.ProductID)
+	}
+	products, err := s.storer.GetProducts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*storer.Product, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+	variants, err := s.productVariants(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	sps, err := s.runningPrices(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.CartRes{UserId: userID}
+	itemsPrice := money.New(0, money.DefaultCurrency)
+	for _, ci := range cart.Items {
+		item := &pb.CartItem{
+			ProductId: ci.ProductID,
+			VariantId: ci.VariantID,
+			Quantity:  ci.Quantity,
+		}
+		// a line whose variant was dropped, or that misses one the product
+		// has gained, stays in the cart unavailable until it is replaced
+		if p, ok := byID[ci.ProductID]; ok {
+			if v, err := resolveVariant(p, variants[p.ID], ci.VariantID); err == nil {
+				price, _ := sps.price(p, v)
+				stock := variantStock(p, v)
+				total := price.Mul(ci.Quantity)
+				item.Name = variantName(p, v)
+				item.Image = variantImage(p, v)
+				item.Price = toPBMoney(price)
+				item.CompareAtPrice = sps.compareAtPrice(p, v)
+				item.LineTotal = toPBMoney(total)
+				item.CountInStock = stock
+				item.Available = ci.Quantity <= stock
+				if v != nil {
+					item.Sku = v.SKU
+				}
+				itemsPrice = itemsPrice.Add(total)
+			}
+		}
+		res.Items = append(res.Items, item)
+	}
+	res.ItemsPrice = toPBMoney(itemsPrice)
+	if cart.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*cart.UpdatedAt)
+	} else if cart.ID != 0 {
+		res.UpdatedAt = timestamppb.New(cart.CreatedAt)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,298 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/storer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Constants
+const (
+	maxCategoryName = 255
+	maxCategorySlug = 255
+)
+
+var (
+	slugPattern  = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	nonSlugRunes = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// slugify derives a slug from a category name the same way the migration
+// that introduced categories did: lower case ASCII letters and digits with
+// anything else collapsed into single dashes.
+func slugify(name string) string {
+	return strings.Trim(nonSlugRunes.ReplaceAllString(strings.ToLower(strings.TrimSpace(name)), "-"), "-")
+}
+
+// CreateCategory adds a category under req.ParentId, or at the top level. The
+// slug is derived from the name unless given.
+func (s *Server) CreateCategory(ctx context.Context, req *pb.CategoryReq) (*pb.CategoryRes, error) {
+	c := &storer.Category{
+		ParentID:  toIDPtr(req.GetParentId()),
+		Name:      strings.TrimSpace(req.GetName()),
+		Slug:      req.GetSlug(),
+		SortOrder: req.GetSortOrder(),
+	}
+	if c.Slug == "" {
+		c.Slug = slugify(c.Name)
+	}
+	if err := checkCategory(c); err != nil {
+		return nil, err
+	}
+	if c.ParentID != nil {
+		if _, err := s.getCategory(ctx, *c.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := s.storer.CreateCategory(ctx, c)
+	if err != nil {
+		if errors.Is(err, storer.ErrCategoryExists) {
+			return nil, status.Errorf(codes.AlreadyExists, "category slug %q is already taken", c.Slug)
+		}
+		return nil, err
+	}
+
+	return s.GetCategory(ctx, &pb.CategoryReq{Id: created.ID})
+}
+
+// GetCategory looks a category up by id, or by slug when no id is given, and
+// returns it with its subcategories.
+func (s *Server) GetCategory(ctx context.Context, req *pb.CategoryReq) (*pb.CategoryRes, error) {
+	c, err := s.lookupCategory(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := s.categoryTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return tree.toPB(c), nil
+}
+
+// ListCategories returns the whole category tree.
+func (s *Server) ListCategories(ctx context.Context, req *pb.ListCategoriesReq) (*pb.ListCategoriesRes, error) {
+	tree, err := s.categoryTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.ListCategoriesRes{
+		Categories: make([]*pb.CategoryRes, 0, len(tree.children[0])),
+	}
+	for _, c := range tree.children[0] {
+		res.Categories = append(res.Categories, tree.toPB(c))
+	}
+
+	return res, nil
+}
+
+// UpdateCategory renames, re-slugs, reorders or moves a category. A category
+// cannot be moved under itself or one of its descendants.
+func (s *Server) UpdateCategory(ctx context.Context, req *pb.CategoryReq) (*pb.CategoryRes, error) {
+	c, err := s.getCategory(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetName() != "" {
+		c.Name = strings.TrimSpace(req.GetName())
+	}
+	if req.GetSlug() != "" {
+		c.Slug = req.GetSlug()
+	}
+	if req.SortOrder != nil {
+		c.SortOrder = req.GetSortOrder()
+	}
+	if req.ParentId != nil {
+		c.ParentID = toIDPtr(req.GetParentId())
+	}
+	if err := checkCategory(c); err != nil {
+		return nil, err
+	}
+
+	if c.ParentID != nil {
+		tree, err := s.categoryTree(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := tree.byID[*c.ParentID]; !ok {
+			return nil, status.Errorf(codes.NotFound, "category %d not found", *c.ParentID)
+		}
+		for _, id := range tree.subtreeIDs(c.ID) {
+			if id == *c.ParentID {
+				return nil, status.Errorf(codes.InvalidArgument, "category %d cannot be moved under itself or its subcategory %d", c.ID, id)
+			}
+		}
+	}
+
+	c.UpdatedAt = toTimePtr(time.Now())
+	if _, err := s.storer.UpdateCategory(ctx, c); err != nil {
+		if errors.Is(err, storer.ErrCategoryExists) {
+			return nil, status.Errorf(codes.AlreadyExists, "category slug %q is already taken", c.Slug)
+		}
+		return nil, err
+	}
+
+	return s.GetCategory(ctx, &pb.CategoryReq{Id: c.ID})
+}
+
+// DeleteCategory deletes a category that has no subcategories and no
+// products left.
+func (s *Server) DeleteCategory(ctx context.Context, req *pb.CategoryReq) (*pb.CategoryRes, error) {
+	if _, err := s.getCategory(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	err := s.storer.DeleteCategory(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, storer.ErrCategoryInUse) {
+			return nil, status.Errorf(codes.FailedPrecondition, "category %d still has subcategories or products", req.GetId())
+		}
+		return nil, err
+	}
+
+	return &pb.CategoryRes{}, nil
+}
+
+func (s *Server) getCategory(ctx context.Context, id int64) (*storer.Category, error) {
+	c, err := s.storer.GetCategory(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "category %d not found", id)
+		}
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (s *Server) lookupCategory(ctx context.Context, req *pb.CategoryReq) (*storer.Category, error) {
+	if req.GetId() != 0 {
+		return s.getCategory(ctx, req.GetId())
+	}
+
+	c, err := s.storer.GetCategoryBySlug(ctx, req.GetSlug())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "category %q not found", req.GetSlug())
+		}
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// categoryIDsBySlug returns the ids of the category with the given slug and
+// of all its descendants.
+func (s *Server) categoryIDsBySlug(ctx context.Context, slug string) ([]int64, error) {
+	c, err := s.lookupCategory(ctx, &pb.CategoryReq{Slug: slug})
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := s.categoryTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return tree.subtreeIDs(c.ID), nil
+}
+
+// checkProductCategory makes sure a product is not filed under a category
+// that does not exist.
+func (s *Server) checkProductCategory(ctx context.Context, id *int64) error {
+	if id == nil {
+		return nil
+	}
+
+	_, err := s.getCategory(ctx, *id)
+	return err
+}
+
+func checkCategory(c *storer.Category) error {
+	switch {
+	case c.Name == "":
+		return status.Error(codes.InvalidArgument, "category name is empty")
+	case utf8.RuneCountInString(c.Name) > maxCategoryName:
+		return status.Errorf(codes.InvalidArgument, "category name is longer than %d characters", maxCategoryName)
+	case !slugPattern.MatchString(c.Slug):
+		return status.Errorf(codes.InvalidArgument, "invalid category slug %q, use lower case letters, digits and dashes", c.Slug)
+	case len(c.Slug) > maxCategorySlug:
+		return status.Errorf(codes.InvalidArgument, "category slug is longer than %d characters", maxCategorySlug)
+	}
+
+	return nil
+}
+
+// categoryTree is the category table indexed for walking: children holds the
+// subcategories of every category in display order, with the top level
+// categories under 0.
+type categoryTree struct {
+	byID     map[int64]*storer.Category
+	children map[int64][]*storer.Category
+}
+
+func (s *Server) categoryTree(ctx context.Context) (*categoryTree, error) {
+	categories, err := s.storer.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &categoryTree{
+		byID:     make(map[int64]*storer.Category, len(categories)),
+		children: make(map[int64][]*storer.Category),
+	}
+	for _, c := range categories {
+		tree.byID[c.ID] = c
+		parent := fromIDPtr(c.ParentID)
+		tree.children[parent] = append(tree.children[parent], c)
+	}
+
+	return tree, nil
+}
+
+// subtreeIDs returns id followed by the ids of all its descendants.
+func (t *categoryTree) subtreeIDs(id int64) []int64 {
+	ids := []int64{id}
+	seen := map[int64]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		for _, c := range t.children[ids[i]] {
+			// a cycle can only come from concurrent moves, never loop on it
+			if !seen[c.ID] {
+				seen[c.ID] = true
+				ids = append(ids, c.ID)
+			}
+		}
+	}
+
+	return ids
+}
+
+// toPB maps c with its descendants nested in children.
+func (t *categoryTree) toPB(c *storer.Category) *pb.CategoryRes {
+	return t.toPBSeen(c, map[int64]bool{})
+}
+
+func (t *categoryTree) toPBSeen(c *storer.Category, seen map[int64]bool) *pb.CategoryRes {
+	seen[c.ID] = true
+	res := toPBCategoryRes(c)
+	for _, child := range t.children[c.ID] {
+		if !seen[child.ID] {
+			res.Children = append(res.Children, t.toPBSeen(child, seen))
+		}
+	}
+
+	return res
+}
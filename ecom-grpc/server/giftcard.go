@@ -0,0 +1,493 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/storer"
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"github.com/OrkhanMehbaliyev/ecom-golang/payments"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Constants
+const (
+	// generated codes are giftCardCodeGroups groups of giftCardCodeGroupLen
+	// characters, e.g. "K7QX-2MPA-9RTD-HW4C"
+	giftCardCodeGroups   = 4
+	giftCardCodeGroupLen = 4
+	// letters and digits less those easily mistaken for one another; there
+	// are 32 of them, so a random byte picks one without bias
+	giftCardCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	minGiftCardCode      = 6
+	maxGiftCardCode      = 32
+	maxGiftCardNote      = 255
+	// how many generated codes issuing a card tries before giving up
+	giftCardCodeAttempts = 3
+)
+
+// CreateGiftCard issues a gift card worth amount. A code is generated unless
+// one is given.
+func (s *Server) CreateGiftCard(ctx context.Context, req *pb.GiftCardReq) (*pb.GiftCardRes, error) {
+	if err := checkCurrency(req.GetAmount()); err != nil {
+		return nil, err
+	}
+	amount := toMoney(req.GetAmount())
+	if amount.Amount <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid gift card amount %s", amount)
+	}
+	code := normalizeGiftCardCode(req.GetCode())
+	if code != "" {
+		if err := checkGiftCardCode(code); err != nil {
+			return nil, err
+		}
+	}
+	note, err := giftCardNote(req.GetNote())
+	if err != nil {
+		return nil, err
+	}
+
+	g := &storer.GiftCard{
+		InitialAmount: amount,
+		IssuedBy:      toIDPtr(req.GetUserId()),
+		Note:          note,
+	}
+	if req.ExpiresAt != nil {
+		g.ExpiresAt = toTimePtr(req.GetExpiresAt().AsTime())
+		if g.Expired(time.Now()) {
+			return nil, status.Error(codes.InvalidArgument, "gift card would be expired already")
+		}
+	}
+
+	created, err := s.issueGiftCard(ctx, g, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getGiftCardRes(ctx, created.ID)
+}
+
+// GetGiftCard looks a gift card up by its code. Anyone with the code can
+// check its balance; admins and the card's purchaser see all of it,
+// transactions included.
+func (s *Server) GetGiftCard(ctx context.Context, req *pb.GiftCardReq) (*pb.GiftCardRes, error) {
+	g, err := s.getGiftCard(ctx, req.GetCode())
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.GetIsAdmin() && fromIDPtr(g.PurchaserID) != req.GetUserId() {
+		return toPBGiftCardBalance(g), nil
+	}
+
+	return s.getGiftCardRes(ctx, g.ID)
+}
+
+// ListGiftCards returns the gift cards a user bought, newest first.
+func (s *Server) ListGiftCards(ctx context.Context, req *pb.GiftCardReq) (*pb.ListGiftCardsRes, error) {
+	cards, err := s.storer.ListUserGiftCards(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.ListGiftCardsRes{
+		GiftCards: make([]*pb.GiftCardRes, 0, len(cards)),
+	}
+	for _, g := range cards {
+		res.GiftCards = append(res.GiftCards, toPBGiftCardRes(g))
+	}
+
+	return res, nil
+}
+
+// UpdateGiftCard replaces the expiry and note of a gift card. Its balance
+// only changes through AdjustGiftCard.
+func (s *Server) UpdateGiftCard(ctx context.Context, req *pb.GiftCardReq) (*pb.GiftCardRes, error) {
+	g, err := s.getGiftCard(ctx, req.GetCode())
+	if err != nil {
+		return nil, err
+	}
+
+	if g.Note, err = giftCardNote(req.GetNote()); err != nil {
+		return nil, err
+	}
+	g.ExpiresAt = nil
+	if req.ExpiresAt != nil {
+		g.ExpiresAt = toTimePtr(req.GetExpiresAt().AsTime())
+	}
+	g.UpdatedAt = toTimePtr(time.Now())
+
+	if _, err := s.storer.UpdateGiftCard(ctx, g); err != nil {
+		return nil, err
+	}
+
+	return s.getGiftCardRes(ctx, g.ID)
+}
+
+// AdjustGiftCard adds the signed amount to the balance of a gift card, which
+// cannot go below zero. The reason is kept in the card's transactions.
+func (s *Server) AdjustGiftCard(ctx context.Context, req *pb.GiftCardReq) (*pb.GiftCardRes, error) {
+	amount, reason, err := balanceAdjustment(req.GetAmount(), req.GetReason())
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := s.getGiftCard(ctx, req.GetCode())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.storer.RecordGiftCardTransaction(ctx, &storer.GiftCardTransaction{
+		GiftCardID: g.ID,
+		Kind:       storer.BalanceAdjustment,
+		Amount:     amount,
+		ActorID:    toIDPtr(req.GetUserId()),
+		Reason:     reason,
+	})
+	if errors.Is(err, storer.ErrInsufficientBalance) {
+		return nil, status.Errorf(codes.FailedPrecondition, "gift card %s has less than %s left", g.Code, amount.Mul(-1))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getGiftCardRes(ctx, g.ID)
+}
+
+// GetStoreCredit returns a user's store credit balance and its transactions,
+// oldest first.
+func (s *Server) GetStoreCredit(ctx context.Context, req *pb.StoreCreditReq) (*pb.StoreCreditRes, error) {
+	balance, err := s.storer.GetStoreCredit(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	txs, err := s.storer.ListStoreCreditTransactions(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.StoreCreditRes{
+		UserId:       req.GetUserId(),
+		Balance:      toPBMoney(balance),
+		Transactions: make([]*pb.StoreCreditTransaction, 0, len(txs)),
+	}
+	for _, t := range txs {
+		res.Transactions = append(res.Transactions, toPBStoreCreditTransaction(t))
+	}
+
+	return res, nil
+}
+
+// AdjustStoreCredit grants a user store credit, or takes it off with a
+// negative amount. The balance cannot go below zero.
+func (s *Server) AdjustStoreCredit(ctx context.Context, req *pb.StoreCreditReq) (*pb.StoreCreditRes, error) {
+	amount, reason, err := balanceAdjustment(req.GetAmount(), req.GetReason())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.storer.GetUserByID(ctx, req.GetUserId()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "user %d not found", req.GetUserId())
+		}
+		return nil, err
+	}
+
+	err = s.storer.RecordStoreCreditTransaction(ctx, &storer.StoreCreditTransaction{
+		UserID:  req.GetUserId(),
+		Kind:    storer.BalanceAdjustment,
+		Amount:  amount,
+		ActorID: toIDPtr(req.GetActorId()),
+		Reason:  reason,
+	})
+	if errors.Is(err, storer.ErrInsufficientBalance) {
+		return nil, status.Errorf(codes.FailedPrecondition, "user %d has less than %s of store credit", req.GetUserId(), amount.Mul(-1))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetStoreCredit(ctx, req)
+}
+
+// checkoutTenders splits what gift cards, store credit and loyalty points
+// pay of an order's total: each card in turn pays up to its balance, then
+// the user's store credit pays up to credit of what the cards leave, and up
+// to points of their loyalty points what is left after that. Cards that are
+// expired or spent are refused rather than skipped, so the customer is not
+// charged more than they expect.
+func (s *Server) checkoutTenders(ctx context.Context, userID int64, cardCodes []string, credit money.Money, points int64, total money.Money) ([]*storer.Payment, error) {
+	var tenders []*storer.Payment
+	left := total
+	seen := make(map[string]bool, len(cardCodes))
+	for _, c := range cardCodes {
+		code := normalizeGiftCardCode(c)
+		if seen[code] {
+			return nil, status.Errorf(codes.InvalidArgument, "gift card %s is given twice", code)
+		}
+		seen[code] = true
+
+		g, err := s.getGiftCard(ctx, code)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case g.Expired(time.Now()):
+			return nil, status.Errorf(codes.FailedPrecondition, "gift card %s has expired", g.Code)
+		case g.Balance.Amount <= 0:
+			return nil, status.Errorf(codes.FailedPrecondition, "gift card %s has no balance left", g.Code)
+		case left.Amount <= 0:
+			return nil, status.Errorf(codes.InvalidArgument, "order is paid in full before gift card %s", g.Code)
+		}
+
+		amount := minMoney(g.Balance, left)
+		tenders = append(tenders, &storer.Payment{
+			Provider:  storer.GiftCardProvider,
+			Reference: g.Code,
+			Amount:    amount,
+		})
+		left = left.Sub(amount)
+	}
+
+	if credit.Amount < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid store credit amount %s", credit)
+	}
+	if credit.Amount > 0 && left.Amount > 0 {
+		balance, err := s.storer.GetStoreCredit(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if balance.Cmp(credit) < 0 {
+			return nil, status.Errorf(codes.FailedPrecondition, "user %d has only %s of store credit", userID, balance)
+		}
+
+		amount := minMoney(credit, left)
+		tenders = append(tenders, &storer.Payment{
+			Provider: storer.StoreCreditProvider,
+			Amount:   amount,
+		})
+		left = left.Sub(amount)
+	}
+
+	tender, err := s.loyaltyTender(ctx, userID, points, left)
+	if err != nil {
+		return nil, err
+	}
+	if tender != nil {
+		tenders = append(tenders, tender)
+	}
+
+	return tenders, nil
+}
+
+// amountDue returns what is left to pay of total once the captured payments
+// in ps, less their refunds, are taken off.
+func amountDue(total money.Money, ps []*storer.Payment) money.Money {
+	due := total
+	for _, p := range ps {
+		if p.Status == payments.StatusCaptured {
+			due = due.Sub(p.Amount.Sub(p.RefundedAmount))
+		}
+	}
+	return due
+}
+
+// purchasedGiftCards builds the gift cards bought in an order, one for every
+// unit of its gift card products, worth the unit price. They are issued
+// together with the order becoming paid, under codes from drawGiftCardCodes.
+func (s *Server) purchasedGiftCards(ctx context.Context, orderID int64) ([]*storer.GiftCard, error) {
+	o, err := s.storer.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	giftCards, err := s.giftCardProducts(ctx, o.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	var cards []*storer.GiftCard
+	for _, oi := range o.Items {
+		if !giftCards[oi.ProductID] {
+			continue
+		}
+		for range oi.Quantity {
+			cards = append(cards, &storer.GiftCard{
+				InitialAmount: oi.Price,
+				PurchaserID:   &o.UserID,
+				OrderID:       &o.ID,
+			})
+		}
+	}
+
+	return cards, nil
+}
+
+// drawGiftCardCodes gives every card of cards a newly generated code.
+func drawGiftCardCodes(cards []*storer.GiftCard) error {
+	for _, g := range cards {
+		var err error
+		if g.Code, err = newGiftCardCode(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// giftCardProducts reports which of the products of items are gift cards.
+func (s *Server) giftCardProducts(ctx context.Context, items []storer.OrderItem) (map[int64]bool, error) {
+	ids := make([]int64, 0, len(items))
+	for _, oi := range items {
+		ids = append(ids, oi.ProductID)
+	}
+	products, err := s.storer.GetProducts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	giftCards := make(map[int64]bool, len(products))
+	for _, p := range products {
+		giftCards[p.ID] = p.GiftCard
+	}
+
+	return giftCards, nil
+}
+
+// issueGiftCard creates g under code, or under a generated code when code is
+// empty.
+func (s *Server) issueGiftCard(ctx context.Context, g *storer.GiftCard, code string) (*storer.GiftCard, error) {
+	if code != "" {
+		g.Code = code
+		created, err := s.storer.CreateGiftCard(ctx, g)
+		if errors.Is(err, storer.ErrGiftCardExists) {
+			return nil, status.Errorf(codes.AlreadyExists, "gift card %s already exists", code)
+		}
+		return created, err
+	}
+
+	for range giftCardCodeAttempts {
+		var err error
+		if g.Code, err = newGiftCardCode(); err != nil {
+			return nil, err
+		}
+		created, err := s.storer.CreateGiftCard(ctx, g)
+		if !errors.Is(err, storer.ErrGiftCardExists) {
+			return created, err
+		}
+	}
+
+	return nil, fmt.Errorf("error issuing gift card: no free code after %d attempts", giftCardCodeAttempts)
+}
+
+func (s *Server) getGiftCard(ctx context.Context, code string) (*storer.GiftCard, error) {
+	code = normalizeGiftCardCode(code)
+	g, err := s.storer.GetGiftCardByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "gift card %s not found", code)
+		}
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// getGiftCardRes maps a gift card as it is now, with its transactions.
+func (s *Server) getGiftCardRes(ctx context.Context, id int64) (*pb.GiftCardRes, error) {
+	g, err := s.storer.GetGiftCard(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	txs, err := s.storer.ListGiftCardTransactions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := toPBGiftCardRes(g)
+	for _, t := range txs {
+		res.Transactions = append(res.Transactions, toPBGiftCardTransaction(t))
+	}
+
+	return res, nil
+}
+
+// balanceAdjustment checks the amount and reason of a manual change to a
+// gift card or store credit balance.
+func balanceAdjustment(m *pb.Money, reason string) (money.Money, string, error) {
+	if err := checkCurrency(m); err != nil {
+		return money.Money{}, "", err
+	}
+	amount := toMoney(m)
+	if amount.IsZero() {
+		return money.Money{}, "", status.Error(codes.InvalidArgument, "adjustment amount is zero")
+	}
+
+	reason, err := stockReason(reason)
+	if err != nil {
+		return money.Money{}, "", err
+	}
+
+	return amount, reason, nil
+}
+
+// normalizeGiftCardCode trims a gift card code and upper-cases it, so codes
+// can be typed in any case.
+func normalizeGiftCardCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func checkGiftCardCode(code string) error {
+	if n := len(code); n < minGiftCardCode || n > maxGiftCardCode {
+		return status.Errorf(codes.InvalidArgument, "gift card code must be %d to %d characters long", minGiftCardCode, maxGiftCardCode)
+	}
+	for _, r := range code {
+		if !('A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '-') {
+			return status.Errorf(codes.InvalidArgument, "gift card code %q may only contain letters, digits and dashes", code)
+		}
+	}
+	return nil
+}
+
+func giftCardNote(note string) (string, error) {
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxGiftCardNote {
+		return "", status.Errorf(codes.InvalidArgument, "note is longer than %d characters", maxGiftCardNote)
+	}
+	return note, nil
+}
+
+// newGiftCardCode returns a random code that is unguessable enough to be the
+// only thing guarding a gift card's balance.
+func newGiftCardCode() (string, error) {
+	b := make([]byte, giftCardCodeGroups*giftCardCodeGroupLen)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating gift card code: %w", err)
+	}
+
+	var code strings.Builder
+	for i, c := range b {
+		if i > 0 && i%giftCardCodeGroupLen == 0 {
+			code.WriteByte('-')
+		}
+		code.WriteByte(giftCardCodeAlphabet[int(c)%len(giftCardCodeAlphabet)])
+	}
+
+	return code.String(), nil
+}
+
+func minMoney(a, b money.Money) money.Money {
+	if a.Cmp(b) < 0 {
+		return a
+	}
+	return b
+}
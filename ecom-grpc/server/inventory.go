@@ -0,0 +1,350 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/storer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Constants
+const (
+	maxWarehouseCode = 32
+	maxWarehouseName = 128
+	maxStockReason   = 255
+)
+
+func (s *Server) CreateWarehouse(ctx context.Context, req *pb.WarehouseReq) (*pb.WarehouseRes, error) {
+	w := &storer.Warehouse{
+		Code: normalizeWarehouseCode(req.GetCode()),
+		Name: strings.TrimSpace(req.GetName()),
+	}
+	if err := checkWarehouse(w); err != nil {
+		return nil, err
+	}
+
+	created, err := s.storer.CreateWarehouse(ctx, w)
+	if err != nil {
+		return nil, warehouseError(err, w.Code)
+	}
+
+	return toPBWarehouseRes(created), nil
+}
+
+func (s *Server) ListWarehouses(ctx context.Context, _ *pb.ListWarehousesReq) (*pb.ListWarehousesRes, error) {
+	ws, err := s.storer.ListWarehouses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.ListWarehousesRes{Warehouses: make([]*pb.WarehouseRes, 0, len(ws))}
+	for _, w := range ws {
+		res.Warehouses = append(res.Warehouses, toPBWarehouseRes(w))
+	}
+
+	return res, nil
+}
+
+// UpdateWarehouse renames a warehouse or changes its code.
+func (s *Server) UpdateWarehouse(ctx context.Context, req *pb.WarehouseReq) (*pb.WarehouseRes, error) {
+	w, err := s.getWarehouse(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetCode() != "" {
+		w.Code = normalizeWarehouseCode(req.GetCode())
+	}
+	if req.GetName() != "" {
+		w.Name = strings.TrimSpace(req.GetName())
+	}
+	if err := checkWarehouse(w); err != nil {
+		return nil, err
+	}
+	w.UpdatedAt = toTimePtr(time.Now())
+
+	if _, err := s.storer.UpdateWarehouse(ctx, w); err != nil {
+		return nil, warehouseError(err, w.Code)
+	}
+
+	return toPBWarehouseRes(w), nil
+}
+
+// GetProductStock returns the stock of a product per warehouse and variant.
+func (s *Server) GetProductStock(ctx context.Context, req *pb.StockReq) (*pb.StockRes, error) {
+	p, err := s.getProduct(ctx, req.GetProductId())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getStockRes(ctx, p.ID)
+}
+
+// AdjustStock records a receipt into a warehouse, or an adjustment that
+// corrects its stock either way. Adjustments cannot take the stock below
+// zero.
+func (s *Server) AdjustStock(ctx context.Context, req *pb.StockReq) (*pb.StockRes, error) {
+	reason, err := stockReason(req.GetReason())
+	if err != nil {
+		return nil, err
+	}
+
+	var kind storer.StockMovementKind
+	switch req.GetKind() {
+	case "", string(storer.StockAdjustment):
+		kind = storer.StockAdjustment
+	case string(storer.StockReceipt):
+		kind = storer.StockReceipt
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid kind %q, stock is adjusted by a receipt or an adjustment", req.GetKind())
+	}
+	switch {
+	case req.GetQuantity() == 0:
+		return nil, status.Error(codes.InvalidArgument, "quantity is 0")
+	case kind == storer.StockReceipt && req.GetQuantity() < 0:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid quantity %d for a receipt", req.GetQuantity())
+	}
+
+	p, variantID, err := s.stockItem(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	w, err := s.getWarehouse(ctx, stockWarehouseID(req.GetWarehouseId()))
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.recordStockMovements(ctx, []*storer.StockMovement{{
+		WarehouseID: w.ID,
+		ProductID:   p.ID,
+		VariantID:   variantID,
+		Kind:        kind,
+		Quantity:    req.GetQuantity(),
+		Reason:      reason,
+		ActorID:     toIDPtr(req.GetActorId()),
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getStockRes(ctx, p.ID)
+}
+
+// TransferStock moves units of a product or variant from one warehouse to
+// another, leaving its total stock as it is.
+func (s *Server) TransferStock(ctx context.Context, req *pb.StockReq) (*pb.StockRes, error) {
+	reason, err := stockReason(req.GetReason())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetQuantity() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid quantity %d", req.GetQuantity())
+	}
+
+	p, variantID, err := s.stockItem(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	from, err := s.getWarehouse(ctx, stockWarehouseID(req.GetWarehouseId()))
+	if err != nil {
+		return nil, err
+	}
+	if req.GetToWarehouseId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "to_warehouse_id is not set")
+	}
+	to, err := s.getWarehouse(ctx, req.GetToWarehouseId())
+	if err != nil {
+		return nil, err
+	}
+	if from.ID == to.ID {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot transfer stock from warehouse %d to itself", from.ID)
+	}
+
+	mv := storer.StockMovement{
+		ProductID: p.ID,
+		VariantID: variantID,
+		Kind:      storer.StockTransfer,
+		Reason:    reason,
+		ActorID:   toIDPtr(req.GetActorId()),
+	}
+	out, in := mv, mv
+	out.WarehouseID, out.Quantity, out.OtherWarehouseID = from.ID, -req.GetQuantity(), &to.ID
+	in.WarehouseID, in.Quantity, in.OtherWarehouseID = to.ID, req.GetQuantity(), &from.ID
+	if err := s.recordStockMovements(ctx, []*storer.StockMovement{&out, &in}); err != nil {
+		return nil, err
+	}
+
+	return s.getStockRes(ctx, p.ID)
+}
+
+// ListStockMovements pages through the inventory ledger of a product.
+func (s *Server) ListStockMovements(ctx context.Context, req *pb.ListStockMovementsReq) (*pb.ListStockMovementsRes, error) {
+	p, err := s.getProduct(ctx, req.GetProductId())
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(req.GetLimit())
+	switch {
+	case limit <= 0:
+		limit = storer.DefaultPageSize
+	case limit > storer.MaxPageSize:
+		limit = storer.MaxPageSize
+	}
+
+	mvs, err := s.storer.ListStockMovements(ctx, &storer.ListStockMovementsParams{
+		ProductID:   p.ID,
+		WarehouseID: req.GetWarehouseId(),
+		BeforeID:    req.GetBeforeId(),
+		Limit:       limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.ListStockMovementsRes{Movements: make([]*pb.StockMovement, 0, len(mvs))}
+	for _, m := range mvs {
+		res.Movements = append(res.Movements, toPBStockMovement(m))
+	}
+	if len(mvs) == limit {
+		res.NextBeforeId = mvs[len(mvs)-1].ID
+	}
+
+	return res, nil
+}
+
+// stockItem resolves the product, and the variant for products that come in
+// variants, whose stock a request moves.
+func (s *Server) stockItem(ctx context.Context, req *pb.StockReq) (*storer.Product, int64, error) {
+	p, err := s.getProduct(ctx, req.GetProductId())
+	if err != nil {
+		return nil, 0, err
+	}
+	variants, err := s.storer.ListProductVariants(ctx, []int64{p.ID})
+	if err != nil {
+		return nil, 0, err
+	}
+	if _, err := resolveVariant(p, variants, req.GetVariantId()); err != nil {
+		return nil, 0, err
+	}
+
+	return p, req.GetVariantId(), nil
+}
+
+func (s *Server) recordStockMovements(ctx context.Context, mvs []*storer.StockMovement) error {
+	err := s.storer.RecordStockMovements(ctx, mvs)
+	var stockErr *storer.InsufficientStockError
+	if errors.As(err, &stockErr) {
+		return status.Error(codes.FailedPrecondition, stockErr.Error())
+	}
+	return err
+}
+
+func (s *Server) getStockRes(ctx context.Context, productID int64) (*pb.StockRes, error) {
+	p, err := s.getProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	levels, err := s.storer.ListStockLevels(ctx, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := s.storer.ListWarehouses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	warehouseCodes := make(map[int64]string, len(ws))
+	for _, w := range ws {
+		warehouseCodes[w.ID] = w.Code
+	}
+	variants, err := s.storer.ListProductVariants(ctx, []int64{p.ID})
+	if err != nil {
+		return nil, err
+	}
+	skus := make(map[int64]string, len(variants))
+	for _, v := range variants {
+		skus[v.ID] = v.SKU
+	}
+
+	res := &pb.StockRes{ProductId: p.ID, CountInStock: p.CountInStock}
+	for _, l := range levels {
+		if l.Quantity == 0 {
+			continue
+		}
+		res.Levels = append(res.Levels, &pb.StockLevel{
+			WarehouseId:   l.WarehouseID,
+			WarehouseCode: warehouseCodes[l.WarehouseID],
+			VariantId:     l.VariantID,
+			Sku:           skus[l.VariantID],
+			Quantity:      l.Quantity,
+		})
+	}
+
+	return res, nil
+}
+
+func (s *Server) getWarehouse(ctx context.Context, id int64) (*storer.Warehouse, error) {
+	w, err := s.storer.GetWarehouse(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "warehouse %d not found", id)
+		}
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// stockWarehouseID defaults the warehouse of a stock request to the main
+// warehouse.
+func stockWarehouseID(id int64) int64 {
+	if id == 0 {
+		return storer.MainWarehouseID
+	}
+	return id
+}
+
+func stockReason(reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	switch {
+	case reason == "":
+		return "", status.Error(codes.InvalidArgument, "reason is empty")
+	case utf8.RuneCountInString(reason) > maxStockReason:
+		return "", status.Errorf(codes.InvalidArgument, "reason is longer than %d characters", maxStockReason)
+	}
+	return reason, nil
+}
+
+// normalizeWarehouseCode trims a warehouse code and upper-cases it, so
+// "main" and "MAIN" name the same warehouse.
+func normalizeWarehouseCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func checkWarehouse(w *storer.Warehouse) error {
+	switch {
+	case w.Code == "":
+		return status.Error(codes.InvalidArgument, "code is empty")
+	case utf8.RuneCountInString(w.Code) > maxWarehouseCode:
+		return status.Errorf(codes.InvalidArgument, "code is longer than %d characters", maxWarehouseCode)
+	case w.Name == "":
+		return status.Error(codes.InvalidArgument, "name is empty")
+	case utf8.RuneCountInString(w.Name) > maxWarehouseName:
+		return status.Errorf(codes.InvalidArgument, "name is longer than %d characters", maxWarehouseName)
+	}
+	return nil
+}
+
+func warehouseError(err error, code string) error {
+	if errors.Is(err, storer.ErrWarehouseExists) {
+		return status.Errorf(codes.AlreadyExists, "warehouse code %q is already taken", code)
+	}
+	return err
+}
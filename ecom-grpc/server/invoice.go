@@ -0,0 +1,171 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/storer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetOrderInvoice returns the invoice of a paid order together with its
+// credit notes. Orders paid before they were invoiced automatically are
+// invoiced on the first request.
+func (s *Server) GetOrderInvoice(ctx context.Context, o *pb.OrderReq) (*pb.InvoiceRes, error) {
+	order, err := s.getOwnOrderStatus(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+
+	inv, err := s.storer.GetOrderInvoice(ctx, order.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		if !isPaid(order.Status) {
+			return nil, status.Errorf(codes.FailedPrecondition, "order %d is %s and has no invoice", order.ID, order.Status)
+		}
+		inv, err = s.issueInvoice(ctx, order.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := s.storer.ListInvoiceCreditNotes(ctx, inv.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBInvoiceRes(inv, notes), nil
+}
+
+// issueInvoice invoices a paid order, snapshotting the seller, the customer
+// and the order as they are now. An order is only ever invoiced once; if it
+// already is, its invoice is returned.
+func (s *Server) issueInvoice(ctx context.Context, orderID int64) (*storer.Invoice, error) {
+	o, err := s.storer.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.storer.GetUserByID(ctx, o.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	inv := &storer.Invoice{
+		OrderID:         o.ID,
+		UserID:          o.UserID,
+		Seller:          s.seller,
+		BuyerName:       customer.Name,
+		BuyerEmail:      customer.Email,
+		BillingAddress:  o.BillingAddress,
+		ShippingAddress: o.ShippingAddress,
+		ItemsPrice:      itemsPrice(o.Items),
+		DiscountPrice:   o.DiscountPrice,
+		ShippingPrice:   o.ShippingPrice,
+		TaxPrice:        o.TaxPrice,
+		TotalPrice:      o.TotalPrice,
+		IssuedAt:        time.Now(),
+	}
+	for _, i := range o.Items {
+		inv.Items = append(inv.Items, storer.InvoiceLine{
+			Description:     i.Name,
+			SKU:             i.SKU,
+			Quantity:        i.Quantity,
+			UnitPrice:       i.Price,
+			TaxRate:         i.TaxRate,
+			TaxJurisdiction: i.TaxJurisdiction,
+			TaxPrice:        i.TaxPrice,
+			TaxInclusive:    i.TaxInclusive,
+			Total:           lineTotal(i),
+		})
+	}
+
+	created, err := s.storer.CreateInvoice(ctx, inv)
+	if errors.Is(err, storer.ErrInvoiceExists) {
+		return s.storer.GetOrderInvoice(ctx, orderID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// creditNote builds the credit note for what is left of the invoice of a
+// refunded order: all of it, or what credit notes for returns did not credit
+// already. It returns nil if the order was never invoiced or nothing is left.
+func (s *Server) creditNote(ctx context.Context, orderID int64, reason string) (*storer.CreditNote, error) {
+	inv, err := s.storer.GetOrderInvoice(ctx, orderID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := s.storer.ListInvoiceCreditNotes(ctx, inv.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	cn := &storer.CreditNote{
+		InvoiceID:     inv.ID,
+		Reason:        reason,
+		Items:         uncreditedLines(inv.Items, notes),
+		ItemsPrice:    inv.ItemsPrice,
+		DiscountPrice: inv.DiscountPrice,
+		ShippingPrice: inv.ShippingPrice,
+		TaxPrice:      inv.TaxPrice,
+		TotalPrice:    inv.TotalPrice,
+		IssuedAt:      time.Now(),
+	}
+	for _, n := range notes {
+		cn.ItemsPrice = cn.ItemsPrice.Sub(n.ItemsPrice)
+		cn.DiscountPrice = cn.DiscountPrice.Sub(n.DiscountPrice)
+		cn.ShippingPrice = cn.ShippingPrice.Sub(n.ShippingPrice)
+		cn.TaxPrice = cn.TaxPrice.Sub(n.TaxPrice)
+		cn.TotalPrice = cn.TotalPrice.Sub(n.TotalPrice)
+	}
+	if cn.TotalPrice.Amount <= 0 {
+		return nil, nil
+	}
+
+	return cn, nil
+}
+
+// uncreditedLines returns invoice lines less the units credit notes credited
+// of them. Credited lines are matched to invoice lines by description and
+// SKU.
+func uncreditedLines(lines storer.InvoiceLines, notes []*storer.CreditNote) storer.InvoiceLines {
+	left := slices.Clone(lines)
+	for _, n := range notes {
+		for _, cl := range n.Items {
+			for i := range left {
+				l := &left[i]
+				if l.Description == cl.Description && l.SKU == cl.SKU && l.Quantity >= cl.Quantity {
+					l.Quantity -= cl.Quantity
+					l.TaxPrice = l.TaxPrice.Sub(cl.TaxPrice)
+					l.Total = l.Total.Sub(cl.Total)
+					break
+				}
+			}
+		}
+	}
+
+	return slices.DeleteFunc(left, func(l storer.InvoiceLine) bool { return l.Quantity == 0 })
+}
+
+// isPaid reports whether an order in status st has been paid for and not
+// given up since.
+func isPaid(st storer.OrderStatus) bool {
+	switch st {
+	case storer.Paid, storer.Processing, storer.Shipped, storer.Delivered:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,338 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/storer"
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"github.com/OrkhanMehbaliyev/ecom-golang/payments"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Constants
+const (
+	// tiers follow what customers spent over this long
+	loyaltyYear = 365 * 24 * time.Hour
+	// a tier multiplier of 100 earns points at the base rate
+	baseMultiplier = 100
+)
+
+// LoyaltyProgram is how customers earn loyalty points on delivered orders
+// and spend them at checkout.
+type LoyaltyProgram struct {
+	// EarnRate is the points earned per whole currency unit spent; zero
+	// turns earning off.
+	EarnRate int64
+	// PointValue is what a point pays at checkout; zero turns redeeming
+	// off.
+	PointValue money.Money
+	// PointsTTL is how long points stay spendable; zero keeps them forever.
+	PointsTTL time.Duration
+	// Tiers are ordered by MinSpend, lowest first. Customers are in the
+	// highest tier whose MinSpend they spent over the last year, and earn
+	// at the base rate below all of them.
+	Tiers []LoyaltyTier
+}
+
+// LoyaltyTier is a level of the loyalty program. Multiplier scales the
+// points earned in it, in percent.
+type LoyaltyTier struct {
+	Name       string
+	MinSpend   money.Money
+	Multiplier int64
+}
+
+// DefaultLoyaltyProgram earns a point per dollar, worth a cent, for a year,
+// with silver and gold tiers earning a quarter and a half more.
+func DefaultLoyaltyProgram() LoyaltyProgram {
+	return LoyaltyProgram{
+		EarnRate:   1,
+		PointValue: money.New(1, money.DefaultCurrency),
+		PointsTTL:  loyaltyYear,
+		Tiers: []LoyaltyTier{
+			{Name: "bronze", MinSpend: money.New(0, money.DefaultCurrency), Multiplier: 100},
+			{Name: "silver", MinSpend: money.New(50000, money.DefaultCurrency), Multiplier: 125},
+			{Name: "gold", MinSpend: money.New(200000, money.DefaultCurrency), Multiplier: 150},
+		},
+	}
+}
+
+// tier returns the tier of a customer who spent spend over the last year,
+// nil below all tiers, and the tier above it, nil at the top.
+func (lp LoyaltyProgram) tier(spend money.Money) (*LoyaltyTier, *LoyaltyTier) {
+	var cur *LoyaltyTier
+	for i := range lp.Tiers {
+		t := &lp.Tiers[i]
+		if spend.Cmp(t.MinSpend) < 0 {
+			return cur, t
+		}
+		cur = t
+	}
+	return cur, nil
+}
+
+// multiplier returns the multiplier of tier t, in percent.
+func multiplier(t *LoyaltyTier) int64 {
+	if t == nil {
+		return baseMultiplier
+	}
+	return t.Multiplier
+}
+
+// points returns the points spend earns in tier t, rounded down.
+func (lp LoyaltyProgram) points(spend money.Money, t *LoyaltyTier) int64 {
+	unit := int64(1)
+	for range money.Digits(spend.Currency) {
+		unit *= 10
+	}
+	return spend.Amount * lp.EarnRate * multiplier(t) / (unit * baseMultiplier)
+}
+
+// expiresAt returns when points credited now expire, nil if they never do.
+func (lp LoyaltyProgram) expiresAt() *time.Time {
+	if lp.PointsTTL == 0 {
+		return nil
+	}
+	return toTimePtr(time.Now().Add(lp.PointsTTL))
+}
+
+// GetLoyalty returns a user's loyalty points, letting lapsed ones expire
+// first, their tier and their transactions, oldest first.
+func (s *Server) GetLoyalty(ctx context.Context, req *pb.LoyaltyReq) (*pb.LoyaltyRes, error) {
+	if err := s.storer.ExpireLoyaltyPoints(ctx, req.GetUserId()); err != nil {
+		return nil, err
+	}
+	points, err := s.storer.GetLoyaltyBalance(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	spend, err := s.storer.GetLoyaltySpend(ctx, req.GetUserId(), time.Now().Add(-loyaltyYear))
+	if err != nil {
+		return nil, err
+	}
+	txs, err := s.storer.ListLoyaltyTransactions(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	tier, next := s.loyalty.tier(spend)
+	res := &pb.LoyaltyRes{
+		UserId:       req.GetUserId(),
+		Points:       points,
+		PointsValue:  toPBMoney(s.pointsValue(max(points, 0))),
+		YearSpend:    toPBMoney(spend),
+		EarnRate:     s.loyalty.EarnRate,
+		Multiplier:   multiplier(tier),
+		Transactions: make([]*pb.LoyaltyTransaction, 0, len(txs)),
+	}
+	if tier != nil {
+		res.Tier = tier.Name
+	}
+	if next != nil {
+		res.NextTier = next.Name
+		res.NextTierSpend = toPBMoney(next.MinSpend.Sub(spend))
+	}
+	for _, t := range txs {
+		res.Transactions = append(res.Transactions, toPBLoyaltyTransaction(t))
+	}
+
+	return res, nil
+}
+
+// loyaltyTender returns the payment with which up to points of a user's
+// loyalty points pay what is left of an order, or nil when nothing is left
+// or it is worth less than a point. Only whole points are redeemed, and no
+// more than what is left is worth, so a remainder worth less than a point
+// is left to the next tender.
+func (s *Server) loyaltyTender(ctx context.Context, userID, points int64, left money.Money) (*storer.Payment, error) {
+	switch {
+	case points < 0:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid loyalty points %d", points)
+	case points == 0 || left.Amount <= 0:
+		return nil, nil
+	case s.loyalty.PointValue.Amount <= 0:
+		return nil, status.Error(codes.FailedPrecondition, "loyalty points cannot be redeemed")
+	}
+
+	if err := s.storer.ExpireLoyaltyPoints(ctx, userID); err != nil {
+		return nil, err
+	}
+	balance, err := s.storer.GetLoyaltyBalance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if balance < points {
+		return nil, status.Errorf(codes.FailedPrecondition, "user %d has only %d loyalty points", userID, max(balance, 0))
+	}
+
+	points = min(points, left.Amount/s.loyalty.PointValue.Amount)
+	if points == 0 {
+		return nil, nil
+	}
+	return &storer.Payment{
+		Provider:  storer.LoyaltyPointsProvider,
+		Reference: strconv.FormatInt(points, 10),
+		Amount:    s.pointsValue(points),
+	}, nil
+}
+
+// loyaltyEarning returns the transaction that credits the customer of an
+// order being delivered with the points it earns in their tier, or nil when
+// it earns none. Points are earned on what the customer paid, less what they
+// paid with points; gift cards bought earn nothing, as spending them does.
+// Orders that earned their points already earn none.
+func (s *Server) loyaltyEarning(ctx context.Context, orderID int64) (*storer.LoyaltyTransaction, error) {
+	if s.loyalty.EarnRate <= 0 {
+		return nil, nil
+	}
+
+	o, err := s.storer.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	earned, _, err := s.orderLoyaltyPoints(ctx, o.UserID, o.ID)
+	if err != nil {
+		return nil, err
+	}
+	if earned.Points != 0 {
+		return nil, nil
+	}
+
+	ps, err := s.storer.ListOrderPayments(ctx, o.ID)
+	if err != nil {
+		return nil, err
+	}
+	giftCards, err := s.giftCardProducts(ctx, o.Items)
+	if err != nil {
+		return nil, err
+	}
+	spend := money.New(0, o.TotalPrice.Currency)
+	for _, p := range ps {
+		if p.Status == payments.StatusCaptured && p.Provider != storer.LoyaltyPointsProvider {
+			spend = spend.Add(p.Amount.Sub(p.RefundedAmount))
+		}
+	}
+	for _, oi := range o.Items {
+		if giftCards[oi.ProductID] {
+			spend = spend.Sub(lineTotal(oi))
+		}
+	}
+	if spend.Amount <= 0 {
+		return nil, nil
+	}
+
+	yearSpend, err := s.storer.GetLoyaltySpend(ctx, o.UserID, time.Now().Add(-loyaltyYear))
+	if err != nil {
+		return nil, err
+	}
+	tier, _ := s.loyalty.tier(yearSpend)
+	points := s.loyalty.points(spend, tier)
+	if points <= 0 {
+		return nil, nil
+	}
+
+	return &storer.LoyaltyTransaction{
+		UserID:    o.UserID,
+		Kind:      storer.BalanceEarn,
+		Points:    points,
+		Spend:     spend,
+		OrderID:   &o.ID,
+		Reason:    fmt.Sprintf("order %d delivered", o.ID),
+		ExpiresAt: s.loyalty.expiresAt(),
+	}, nil
+}
+
+// loyaltyReversal returns the transaction that takes back the points an
+// order earned for amount of what was spent on it being refunded, in
+// proportion, or all that are left of them when amount is nil or covers the
+// rest. It returns nil when there are none to take back. The balance may go
+// below zero when the points were spent already.
+func (s *Server) loyaltyReversal(ctx context.Context, userID, orderID int64, amount *money.Money, reason string) (*storer.LoyaltyTransaction, error) {
+	earned, reversed, err := s.orderLoyaltyPoints(ctx, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	points := earned.Points + reversed.Points
+	spend := earned.Spend.Add(reversed.Spend)
+	if points <= 0 {
+		return nil, nil
+	}
+
+	if amount != nil && amount.Cmp(spend) < 0 {
+		points = min(points, earned.Points*amount.Amount/earned.Spend.Amount)
+		spend = *amount
+	}
+	if points <= 0 {
+		return nil, nil
+	}
+
+	return &storer.LoyaltyTransaction{
+		UserID:  userID,
+		Kind:    storer.BalanceReverse,
+		Points:  -points,
+		Spend:   spend.Mul(-1),
+		OrderID: &orderID,
+		Reason:  reason,
+	}, nil
+}
+
+// loyaltyRefund returns the transaction that gives back the points redeemed
+// by loyalty points payment p for amount of it being refunded, as newly
+// credited points, or nil when that gives back none.
+func (s *Server) loyaltyRefund(p *storer.Payment, userID int64, amount money.Money) *storer.LoyaltyTransaction {
+	// points are given back in proportion, rounding so that refunding all
+	// of the payment gives back all of its points
+	n := p.LoyaltyPoints()
+	before := n * p.RefundedAmount.Amount / p.Amount.Amount
+	after := n * p.RefundedAmount.Add(amount).Amount / p.Amount.Amount
+	if after == before {
+		return nil
+	}
+
+	return &storer.LoyaltyTransaction{
+		UserID:    userID,
+		Kind:      storer.BalanceRefund,
+		Points:    after - before,
+		Spend:     money.New(0, amount.Currency),
+		OrderID:   &p.OrderID,
+		Reason:    fmt.Sprintf("payment %d refunded", p.ID),
+		ExpiresAt: s.loyalty.expiresAt(),
+	}
+}
+
+// orderLoyaltyPoints sums the points, and the spend, an order earned and
+// what was reversed of them.
+func (s *Server) orderLoyaltyPoints(ctx context.Context, userID, orderID int64) (earned, reversed storer.LoyaltyTransaction, err error) {
+	txs, err := s.storer.ListLoyaltyTransactions(ctx, userID)
+	if err != nil {
+		return earned, reversed, err
+	}
+
+	earned.Spend = money.New(0, money.DefaultCurrency)
+	reversed.Spend = money.New(0, money.DefaultCurrency)
+	for _, t := range txs {
+		if fromIDPtr(t.OrderID) != orderID {
+			continue
+		}
+		switch t.Kind {
+		case storer.BalanceEarn:
+			earned.Points += t.Points
+			earned.Spend = earned.Spend.Add(t.Spend)
+		case storer.BalanceReverse:
+			reversed.Points += t.Points
+			reversed.Spend = reversed.Spend.Add(t.Spend)
+		}
+	}
+
+	return earned, reversed, nil
+}
+
+// pointsValue returns what points pay at checkout.
+func (s *Server) pointsValue(points int64) money.Money {
+	return s.loyalty.PointValue.Mul(points)
+}
@@ -1,10 +1,12 @@
 package server
 
 import (
+	"strings"
 	"time"
 
 	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
 	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/storer"
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
 	"github.com/OrkhanMehbaliyev/ecom-golang/util"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -13,24 +15,31 @@ func toStorerProduct(p *pb.ProductReq) *storer.Product {
 	return &storer.Product{
 		Name:         p.Name,
 		Image:        p.Image,
-		Category:     p.Category,
+		CategoryID:   toIDPtr(p.GetCategoryId()),
 		Description:  p.Description,
-		Rating:       p.Rating,
-		NumReviews:   p.NumReviews,
-		Price:        p.Price,
+		Options:      p.Options,
+		TaxClass:     p.TaxClass,
+		Weight:       p.GetWeight(),
+		GiftCard:     p.GetGiftCard(),
+		Price:        toMoney(p.Price),
 		CountInStock: p.CountInStock,
 	}
 }
 
 func toPBProductRes(p *storer.Product) *pb.ProductRes {
 	res := &pb.ProductRes{
+		Id:           p.ID,
 		Name:         p.Name,
 		Image:        p.Image,
-		Category:     p.Category,
+		CategoryId:   fromIDPtr(p.CategoryID),
 		Description:  p.Description,
+		Options:      p.Options,
+		TaxClass:     p.TaxClass,
+		Weight:       p.Weight,
+		GiftCard:     p.GiftCard,
 		Rating:       p.Rating,
 		NumReviews:   p.NumReviews,
-		Price:        p.Price,
+		Price:        toPBMoney(p.Price),
 		CountInStock: p.CountInStock,
 		CreatedAt:    timestamppb.New(p.CreatedAt),
 	}
@@ -41,6 +50,52 @@ func toPBProductRes(p *storer.Product) *pb.ProductRes {
 	return res
 }
 
+// toPBVariantRes maps a variant with the price and image it sells at.
+func toPBVariantRes(p *storer.Product, v *storer.Variant, sps scheduledPrices) *pb.VariantRes {
+	price, _ := sps.price(p, v)
+	res := &pb.VariantRes{
+		Id:             v.ID,
+		ProductId:      v.ProductID,
+		Sku:            v.SKU,
+		Options:        v.Options,
+		Price:          toPBMoney(price),
+		CompareAtPrice: sps.compareAtPrice(p, v),
+		Image:          variantImage(p, v),
+		CountInStock:   v.CountInStock,
+		CreatedAt:      timestamppb.New(v.CreatedAt),
+	}
+	if v.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*v.UpdatedAt)
+	}
+
+	return res
+}
+
+func toStorerListProductsParams(p *pb.ListProductsReq) *storer.ListProductsParams {
+	params := &storer.ListProductsParams{
+		Cursor:    p.GetCursor(),
+		Limit:     int(p.GetLimit()),
+		Desc:      p.GetSortOrder() == pb.SortOrder_DESC,
+		MinPrice:  toMoneyPtr(p.MinPrice),
+		MaxPrice:  toMoneyPtr(p.MaxPrice),
+		MinRating: p.MinRating,
+		InStock:   p.GetInStock(),
+	}
+
+	switch p.GetSortBy() {
+	case pb.ProductSortBy_PRICE:
+		params.SortBy = storer.SortByPrice
+	case pb.ProductSortBy_RATING:
+		params.SortBy = storer.SortByRating
+	case pb.ProductSortBy_NAME:
+		params.SortBy = storer.SortByName
+	default:
+		params.SortBy = storer.SortByCreatedAt
+	}
+
+	return params
+}
+
 func patchProductReq(product *storer.Product, p *pb.ProductReq) {
 	if p.Name != "" {
 		product.Name = p.Name
@@ -48,23 +103,26 @@ func patchProductReq(product *storer.Product, p *pb.ProductReq) {
 	if p.Image != "" {
 		product.Image = p.Image
 	}
-	if p.Category != "" {
-		product.Category = p.Category
+	if p.CategoryId != 0 {
+		product.CategoryID = toIDPtr(p.CategoryId)
 	}
 	if p.Description != "" {
 		product.Description = p.Description
 	}
-	if p.Rating != 0 {
-		product.Rating = p.Rating
+	if len(p.Options) > 0 {
+		product.Options = p.Options
 	}
-	if p.NumReviews != 0 {
-		product.NumReviews = p.NumReviews
+	if p.TaxClass != "" {
+		product.TaxClass = p.TaxClass
 	}
-	if p.Price != 0 {
-		product.Price = p.Price
+	if p.Weight != nil {
+		product.Weight = p.GetWeight()
 	}
-	if p.CountInStock != 0 {
-		product.CountInStock = p.CountInStock
+	if p.GiftCard != nil {
+		product.GiftCard = p.GetGiftCard()
+	}
+	if p.Price != nil {
+		product.Price = toMoney(p.Price)
 	}
 	product.UpdatedAt = toTimePtr(time.Now())
 }
@@ -73,39 +131,100 @@ func toTimePtr(t time.Time) *time.Time {
 	return &t
 }
 
-func toStorerOrder(o *pb.OrderReq) *storer.Order {
+// toIDPtr maps the zero id used on the wire for "none" to a NULL reference.
+func toIDPtr(id int64) *int64 {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
+
+func fromIDPtr(id *int64) int64 {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
+
+// toMoney reads an amount sent over the wire. An amount without a currency
+// is in the default currency.
+func toMoney(m *pb.Money) money.Money {
+	currency := m.GetCurrency()
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+
+	return money.New(m.GetAmount(), currency)
+}
+
+func toMoneyPtr(m *pb.Money) *money.Money {
+	if m == nil {
+		return nil
+	}
+
+	v := toMoney(m)
+	return &v
+}
+
+func toPBMoney(m money.Money) *pb.Money {
+	return &pb.Money{
+		Amount:   m.Amount,
+		Currency: m.Currency,
+	}
+}
+
+func toStorerOrder(o *pb.OrderReq, q *orderQuote) *storer.Order {
 	return &storer.Order{
-		PaymentMethod: o.PaymentMethod,
-		TaxPrice:      o.TaxPrice,
-		ShippingPrice: o.ShippingPrice,
-		TotalPrice:    o.TotalPrice,
-		UserID:        o.UserId,
-		Items:         toStorerOrderItems(o.Items),
+		PaymentMethod:   o.PaymentMethod,
+		ShippingAddress: q.ShippingAddress,
+		BillingAddress:  q.BillingAddress,
+		ShippingMethod:  q.ShippingMethod,
+		TaxPrice:        q.TaxPrice,
+		ShippingPrice:   q.ShippingPrice,
+		DiscountPrice:   q.DiscountPrice,
+		CouponCode:      q.CouponCode,
+		Discounts:       q.Discounts,
+		TotalPrice:      q.TotalPrice,
+		UserID:          o.UserId,
+		Items:           q.Items,
 	}
 }
 
-func toStorerOrderItems(items []*pb.OrderItem) []storer.OrderItem {
-	var res []storer.OrderItem
-	for _, i := range items {
-		res = append(res, storer.OrderItem{
-			Name:      i.Name,
-			Quantity:  i.Quantity,
-			Image:     i.Image,
-			Price:     i.Price,
-			ProductID: i.ProductId,
-		})
+func toStorerListOrdersParams(req *pb.ListUserOrdersReq) *storer.ListOrdersParams {
+	params := &storer.ListOrdersParams{
+		UserID: req.GetUserId(),
+		Cursor: req.GetCursor(),
+		Limit:  int(req.GetLimit()),
 	}
-	return res
+	if req.Status != nil {
+		params.Status = storer.OrderStatus(strings.ToLower(req.GetStatus().String()))
+	}
+	if req.From != nil {
+		params.From = toTimePtr(req.GetFrom().AsTime())
+	}
+	if req.To != nil {
+		params.To = toTimePtr(req.GetTo().AsTime())
+	}
+
+	return params
 }
 
 func toPBOrderStatus(os storer.OrderStatus) pb.OrderStatus {
 	switch os {
 	case storer.Pending:
 		return pb.OrderStatus_PENDING
+	case storer.Paid:
+		return pb.OrderStatus_PAID
+	case storer.Processing:
+		return pb.OrderStatus_PROCESSING
 	case storer.Shipped:
 		return pb.OrderStatus_SHIPPED
 	case storer.Delivered:
 		return pb.OrderStatus_DELIVERED
+	case storer.Cancelled:
+		return pb.OrderStatus_CANCELLED
+	case storer.Refunded:
+		return pb.OrderStatus_REFUNDED
 	default:
 		return 0
 	}
@@ -113,14 +232,32 @@ func toPBOrderStatus(os storer.OrderStatus) pb.OrderStatus {
 
 func toPBOrderRes(o *storer.Order) *pb.OrderRes {
 	res := &pb.OrderRes{
-		Id:            o.ID,
-		Items:         toPBOrderItems(o.Items),
-		PaymentMethod: o.PaymentMethod,
-		TaxPrice:      o.TaxPrice,
-		ShippingPrice: o.ShippingPrice,
-		TotalPrice:    o.TotalPrice,
-		Status:        toPBOrderStatus(o.Status),
-		CreatedAt:     timestamppb.New(o.CreatedAt),
+		Id:             o.ID,
+		Items:          toPBOrderItems(o.Items),
+		PaymentMethod:  o.PaymentMethod,
+		ShippingMethod: o.ShippingMethod,
+		ItemsPrice:     toPBMoney(itemsPrice(o.Items)),
+		TaxPrice:       toPBMoney(o.TaxPrice),
+		ShippingPrice:  toPBMoney(o.ShippingPrice),
+		DiscountPrice:  toPBMoney(o.DiscountPrice),
+		CouponCode:     o.CouponCode,
+		TotalPrice:     toPBMoney(o.TotalPrice),
+		Status:         toPBOrderStatus(o.Status),
+		CreatedAt:      timestamppb.New(o.CreatedAt),
+	}
+	if a := o.ShippingAddress; a != nil {
+		res.ShippingAddress = toPBAddress(a)
+	}
+	if a := o.BillingAddress; a != nil {
+		res.BillingAddress = toPBAddress(a)
+	}
+	for _, d := range o.Discounts {
+		res.Discounts = append(res.Discounts, &pb.OrderDiscount{
+			PromotionId: d.PromotionID,
+			Name:        d.Name,
+			Code:        d.Code,
+			Amount:      toPBMoney(d.Amount),
+		})
 	}
 	if o.UpdatedAt != nil {
 		res.UpdatedAt = timestamppb.New(*o.UpdatedAt)
@@ -133,16 +270,78 @@ func toPBOrderItems(items []storer.OrderItem) []*pb.OrderItem {
 	var res []*pb.OrderItem
 	for _, i := range items {
 		res = append(res, &pb.OrderItem{
-			Name:      i.Name,
-			Quantity:  i.Quantity,
-			Image:     i.Image,
-			Price:     i.Price,
-			ProductId: i.ProductID,
+			Id:              i.ID,
+			Name:            i.Name,
+			Quantity:        i.Quantity,
+			Image:           i.Image,
+			Price:           toPBMoney(i.Price),
+			ProductId:       i.ProductID,
+			VariantId:       fromIDPtr(i.VariantID),
+			Sku:             i.SKU,
+			LineTotal:       toPBMoney(lineTotal(i)),
+			TaxRate:         i.TaxRate,
+			TaxJurisdiction: i.TaxJurisdiction,
+			TaxPrice:        toPBMoney(i.TaxPrice),
+			TaxInclusive:    i.TaxInclusive,
 		})
 	}
 	return res
 }
 
+func toPBAddress(a *storer.Address) *pb.Address {
+	return &pb.Address{
+		Name:       a.Name,
+		Line1:      a.Line1,
+		Line2:      a.Line2,
+		City:       a.City,
+		Region:     a.Region,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+	}
+}
+
+func toPBAddressRes(a *storer.UserAddress) *pb.AddressRes {
+	res := &pb.AddressRes{
+		Id:        a.ID,
+		UserId:    a.UserID,
+		Label:     a.Label,
+		Address:   toPBAddress(a.Address()),
+		IsDefault: a.IsDefault,
+		CreatedAt: timestamppb.New(a.CreatedAt),
+	}
+	if a.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*a.UpdatedAt)
+	}
+
+	return res
+}
+
+func toPBTaxRateRes(r *storer.TaxRate) *pb.TaxRateRes {
+	res := &pb.TaxRateRes{
+		Id:        r.ID,
+		Country:   r.Country,
+		Region:    r.Region,
+		TaxClass:  r.TaxClass,
+		Name:      r.Name,
+		Rate:      r.Rate,
+		Inclusive: r.Inclusive,
+		CreatedAt: timestamppb.New(r.CreatedAt),
+	}
+	if r.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*r.UpdatedAt)
+	}
+
+	return res
+}
+
+func itemsPrice(items []storer.OrderItem) money.Money {
+	total := money.New(0, money.DefaultCurrency)
+	for _, i := range items {
+		total = total.Add(lineTotal(i))
+	}
+	return total
+}
+
 func toStorerUser(u *pb.UserReq) *storer.User {
 	return &storer.User{
 		Name:     u.Name,
@@ -154,11 +353,12 @@ func toStorerUser(u *pb.UserReq) *storer.User {
 
 func toPBUserRes(u *storer.User) *pb.UserRes {
 	return &pb.UserRes{
-		Id:       u.ID,
-		Name:     u.Name,
-		Email:    u.Email,
-		Password: u.Password,
-		IsAdmin:  u.IsAdmin,
+		Id:        u.ID,
+		Name:      u.Name,
+		Email:     u.Email,
+		Password:  u.Password,
+		IsAdmin:   u.IsAdmin,
+		TaxExempt: u.TaxExempt,
 	}
 }
 
@@ -181,3 +381,431 @@ func patchUserReq(user *storer.User, u *pb.UserReq) {
 	}
 	user.UpdatedAt = toTimePtr(time.Now())
 }
+
+func toPBOrderStatusChange(c *storer.OrderStatusChange) *pb.OrderStatusChange {
+	return &pb.OrderStatusChange{
+		Id:         c.ID,
+		OrderId:    c.OrderID,
+		FromStatus: toPBOrderStatus(c.FromStatus),
+		ToStatus:   toPBOrderStatus(c.ToStatus),
+		Reason:     c.Reason,
+		ActorId:    c.ActorID,
+		ActorRole:  string(c.ActorRole),
+		CreatedAt:  timestamppb.New(c.CreatedAt),
+	}
+}
+
+func toPBNotificationEvents(events []*storer.NotificationEvent) []*pb.NotificationEvent {
+	res := make([]*pb.NotificationEvent, 0, len(events))
+	for _, ne := range events {
+		ev := &pb.NotificationEvent{
+			Id:          ne.ID,
+			UserEmail:   ne.UserEmail,
+			OrderStatus: toPBOrderStatus(ne.OrderStatus),
+			OrderId:     ne.OrderID,
+			StateId:     ne.StateID,
+			Attempts:    ne.Attempts,
+		}
+		if ne.ReturnID != nil {
+			ev.ReturnId = *ne.ReturnID
+			ev.ReturnStatus = string(ne.ReturnStatus)
+		}
+		res = append(res, ev)
+	}
+	return res
+}
+
+func toPBReviewRes(r *storer.Review) *pb.ReviewRes {
+	res := &pb.ReviewRes{
+		Id:        r.ID,
+		ProductId: r.ProductID,
+		UserId:    r.UserID,
+		Rating:    r.Rating,
+		Title:     r.Title,
+		Body:      r.Body,
+		Hidden:    r.Hidden,
+		CreatedAt: timestamppb.New(r.CreatedAt),
+	}
+	if r.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*r.UpdatedAt)
+	}
+
+	return res
+}
+
+func toPBCategoryRes(c *storer.Category) *pb.CategoryRes {
+	res := &pb.CategoryRes{
+		Id:        c.ID,
+		ParentId:  fromIDPtr(c.ParentID),
+		Name:      c.Name,
+		Slug:      c.Slug,
+		SortOrder: c.SortOrder,
+		CreatedAt: timestamppb.New(c.CreatedAt),
+	}
+	if c.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*c.UpdatedAt)
+	}
+
+	return res
+}
+
+func toStorerPromotionType(t pb.PromotionType) storer.PromotionType {
+	switch t {
+	case pb.PromotionType_FIXED_AMOUNT:
+		return storer.FixedAmountOff
+	case pb.PromotionType_FREE_SHIPPING:
+		return storer.FreeShipping
+	case pb.PromotionType_BUY_X_GET_Y:
+		return storer.BuyXGetY
+	default:
+		return storer.PercentageOff
+	}
+}
+
+func toPBPromotionType(t storer.PromotionType) pb.PromotionType {
+	switch t {
+	case storer.FixedAmountOff:
+		return pb.PromotionType_FIXED_AMOUNT
+	case storer.FreeShipping:
+		return pb.PromotionType_FREE_SHIPPING
+	case storer.BuyXGetY:
+		return pb.PromotionType_BUY_X_GET_Y
+	default:
+		return pb.PromotionType_PERCENTAGE
+	}
+}
+
+func toPBPromotionRes(p *storer.Promotion) *pb.PromotionRes {
+	res := &pb.PromotionRes{
+		Id:               p.ID,
+		Name:             p.Name,
+		Type:             toPBPromotionType(p.Type),
+		DiscountRate:     p.DiscountRate,
+		DiscountAmount:   toPBMoney(p.DiscountAmount),
+		BuyQuantity:      p.BuyQuantity,
+		GetQuantity:      p.GetQuantity,
+		ProductId:        fromIDPtr(p.ProductID),
+		CategoryId:       fromIDPtr(p.CategoryID),
+		MinSubtotal:      toPBMoney(p.MinSubtotal),
+		UsageLimit:       p.UsageLimit,
+		PerCustomerLimit: p.PerCustomerLimit,
+		Active:           p.Active,
+		TimesUsed:        p.TimesUsed,
+		CreatedAt:        timestamppb.New(p.CreatedAt),
+	}
+	if p.Code != nil {
+		res.Code = *p.Code
+	}
+	if p.StartsAt != nil {
+		res.StartsAt = timestamppb.New(*p.StartsAt)
+	}
+	if p.EndsAt != nil {
+		res.EndsAt = timestamppb.New(*p.EndsAt)
+	}
+	if p.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*p.UpdatedAt)
+	}
+
+	return res
+}
+
+func toPBScheduledPriceRes(sp *storer.ScheduledPrice) *pb.ScheduledPriceRes {
+	res := &pb.ScheduledPriceRes{
+		Id:           sp.ID,
+		ProductId:    sp.ProductID,
+		VariantId:    fromIDPtr(sp.VariantID),
+		Price:        toPBMoney(sp.Price),
+		StartsAt:     timestamppb.New(sp.StartsAt),
+		EndsAt:       timestamppb.New(sp.EndsAt),
+		QuantitySold: sp.QuantitySold,
+		Running:      sp.Running(time.Now()),
+		CreatedAt:    timestamppb.New(sp.CreatedAt),
+	}
+	if sp.QuantityCap != nil {
+		res.QuantityCap = *sp.QuantityCap
+	}
+	if sp.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*sp.UpdatedAt)
+	}
+
+	return res
+}
+
+func toStorerShippingBasis(b pb.ShippingBasis) storer.ShippingBasis {
+	if b == pb.ShippingBasis_WEIGHT {
+		return storer.ByWeight
+	}
+	return storer.BySubtotal
+}
+
+func toPBShippingBasis(b storer.ShippingBasis) pb.ShippingBasis {
+	if b == storer.ByWeight {
+		return pb.ShippingBasis_WEIGHT
+	}
+	return pb.ShippingBasis_SUBTOTAL
+}
+
+func toPBShippingZoneRes(z *storer.ShippingZone, methods []*storer.ShippingMethod) *pb.ShippingZoneRes {
+	res := &pb.ShippingZoneRes{
+		Id:             z.ID,
+		Name:           z.Name,
+		Countries:      z.Countries,
+		PostalPrefixes: z.PostalPrefixes,
+		CreatedAt:      timestamppb.New(z.CreatedAt),
+	}
+	for _, m := range methods {
+		res.Methods = append(res.Methods, toPBShippingMethodRes(m))
+	}
+	if z.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*z.UpdatedAt)
+	}
+
+	return res
+}
+
+func toPBShippingMethodRes(m *storer.ShippingMethod) *pb.ShippingMethodRes {
+	res := &pb.ShippingMethodRes{
+		Id:        m.ID,
+		ZoneId:    m.ZoneID,
+		Name:      m.Name,
+		Basis:     toPBShippingBasis(m.Basis),
+		Active:    m.Active,
+		CreatedAt: timestamppb.New(m.CreatedAt),
+	}
+	for _, t := range m.Tiers {
+		res.Tiers = append(res.Tiers, &pb.ShippingTier{
+			Min:   t.Min,
+			Price: toPBMoney(t.Price),
+		})
+	}
+	if m.FreeAbove != nil {
+		res.FreeAbove = toPBMoney(*m.FreeAbove)
+	}
+	if m.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*m.UpdatedAt)
+	}
+
+	return res
+}
+
+func toPBPaymentRes(p *storer.Payment) *pb.PaymentRes {
+	res := &pb.PaymentRes{
+		Id:        p.ID,
+		OrderId:   p.OrderID,
+		Provider:  p.Provider,
+		Reference: p.Reference,
+		Status:    string(p.Status),
+		Amount:    toPBMoney(p.Amount),
+		Message:   p.Message,
+		CreatedAt: timestamppb.New(p.CreatedAt),
+		// the same currency as the amount, even when nothing was refunded
+		RefundedAmount: toPBMoney(money.New(p.RefundedAmount.Amount, p.Amount.Currency)),
+	}
+	if p.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*p.UpdatedAt)
+	}
+
+	return res
+}
+
+func toPBGiftCardRes(g *storer.GiftCard) *pb.GiftCardRes {
+	res := toPBGiftCardBalance(g)
+	res.Id = g.ID
+	res.InitialAmount = toPBMoney(g.InitialAmount)
+	res.PurchaserId = fromIDPtr(g.PurchaserID)
+	res.OrderId = fromIDPtr(g.OrderID)
+	res.IssuedBy = fromIDPtr(g.IssuedBy)
+	res.Note = g.Note
+	res.CreatedAt = timestamppb.New(g.CreatedAt)
+	if g.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*g.UpdatedAt)
+	}
+
+	return res
+}
+
+// toPBGiftCardBalance maps what anyone holding a gift card's code may see
+// of it.
+func toPBGiftCardBalance(g *storer.GiftCard) *pb.GiftCardRes {
+	res := &pb.GiftCardRes{
+		Code:    g.Code,
+		Balance: toPBMoney(g.Balance),
+		Expired: g.Expired(time.Now()),
+	}
+	if g.ExpiresAt != nil {
+		res.ExpiresAt = timestamppb.New(*g.ExpiresAt)
+	}
+
+	return res
+}
+
+func toPBGiftCardTransaction(t *storer.GiftCardTransaction) *pb.GiftCardTransaction {
+	return &pb.GiftCardTransaction{
+		Id:        t.ID,
+		Kind:      string(t.Kind),
+		Amount:    toPBMoney(t.Amount),
+		OrderId:   fromIDPtr(t.OrderID),
+		ActorId:   fromIDPtr(t.ActorID),
+		Reason:    t.Reason,
+		CreatedAt: timestamppb.New(t.CreatedAt),
+	}
+}
+
+func toPBLoyaltyTransaction(t *storer.LoyaltyTransaction) *pb.LoyaltyTransaction {
+	res := &pb.LoyaltyTransaction{
+		Id:        t.ID,
+		Kind:      string(t.Kind),
+		Points:    t.Points,
+		OrderId:   fromIDPtr(t.OrderID),
+		Reason:    t.Reason,
+		CreatedAt: timestamppb.New(t.CreatedAt),
+	}
+	if t.ExpiresAt != nil {
+		res.ExpiresAt = timestamppb.New(*t.ExpiresAt)
+	}
+
+	return res
+}
+
+func toPBStoreCreditTransaction(t *storer.StoreCreditTransaction) *pb.StoreCreditTransaction {
+	return &pb.StoreCreditTransaction{
+		Id:        t.ID,
+		Kind:      string(t.Kind),
+		Amount:    toPBMoney(t.Amount),
+		OrderId:   fromIDPtr(t.OrderID),
+		ActorId:   fromIDPtr(t.ActorID),
+		Reason:    t.Reason,
+		CreatedAt: timestamppb.New(t.CreatedAt),
+	}
+}
+
+func toPBInvoiceLines(ls storer.InvoiceLines) []*pb.InvoiceLine {
+	res := make([]*pb.InvoiceLine, 0, len(ls))
+	for _, l := range ls {
+		res = append(res, &pb.InvoiceLine{
+			Description:     l.Description,
+			Sku:             l.SKU,
+			Quantity:        l.Quantity,
+			UnitPrice:       toPBMoney(l.UnitPrice),
+			TaxRate:         l.TaxRate,
+			TaxJurisdiction: l.TaxJurisdiction,
+			TaxPrice:        toPBMoney(l.TaxPrice),
+			TaxInclusive:    l.TaxInclusive,
+			Total:           toPBMoney(l.Total),
+		})
+	}
+
+	return res
+}
+
+func toPBInvoiceRes(inv *storer.Invoice, notes []*storer.CreditNote) *pb.InvoiceRes {
+	res := &pb.InvoiceRes{
+		Id:      inv.ID,
+		Number:  inv.Number,
+		OrderId: inv.OrderID,
+		UserId:  inv.UserID,
+		Seller: &pb.Seller{
+			Name:    inv.Seller.Name,
+			Address: inv.Seller.Address,
+			Email:   inv.Seller.Email,
+			TaxId:   inv.Seller.TaxID,
+		},
+		BuyerName:     inv.BuyerName,
+		BuyerEmail:    inv.BuyerEmail,
+		Lines:         toPBInvoiceLines(inv.Items),
+		ItemsPrice:    toPBMoney(inv.ItemsPrice),
+		DiscountPrice: toPBMoney(inv.DiscountPrice),
+		ShippingPrice: toPBMoney(inv.ShippingPrice),
+		TaxPrice:      toPBMoney(inv.TaxPrice),
+		TotalPrice:    toPBMoney(inv.TotalPrice),
+		IssuedAt:      timestamppb.New(inv.IssuedAt),
+	}
+	if a := inv.BillingAddress; a != nil {
+		res.BillingAddress = toPBAddress(a)
+	}
+	if a := inv.ShippingAddress; a != nil {
+		res.ShippingAddress = toPBAddress(a)
+	}
+	for _, n := range notes {
+		res.CreditNotes = append(res.CreditNotes, toPBCreditNote(n))
+	}
+
+	return res
+}
+
+func toPBCreditNote(n *storer.CreditNote) *pb.CreditNote {
+	return &pb.CreditNote{
+		Id:            n.ID,
+		Number:        n.Number,
+		InvoiceId:     n.InvoiceID,
+		Reason:        n.Reason,
+		Lines:         toPBInvoiceLines(n.Items),
+		ItemsPrice:    toPBMoney(n.ItemsPrice),
+		DiscountPrice: toPBMoney(n.DiscountPrice),
+		ShippingPrice: toPBMoney(n.ShippingPrice),
+		TaxPrice:      toPBMoney(n.TaxPrice),
+		TotalPrice:    toPBMoney(n.TotalPrice),
+		IssuedAt:      timestamppb.New(n.IssuedAt),
+	}
+}
+
+func toPBReturnRes(r *storer.Return) *pb.ReturnRes {
+	res := &pb.ReturnRes{
+		Id:           r.ID,
+		OrderId:      r.OrderID,
+		OrderItemId:  r.OrderItemID,
+		UserId:       r.UserID,
+		Quantity:     r.Quantity,
+		Reason:       r.Reason,
+		PhotosUrl:    r.PhotosURL,
+		Status:       string(r.Status),
+		Note:         r.Note,
+		Restocked:    r.Restocked,
+		RefundAmount: toPBMoney(r.RefundAmount),
+		CreatedAt:    timestamppb.New(r.CreatedAt),
+	}
+	if r.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*r.UpdatedAt)
+	}
+
+	return res
+}
+
+func toPBListReturnsRes(returns []*storer.Return) *pb.ListReturnsRes {
+	res := &pb.ListReturnsRes{Returns: make([]*pb.ReturnRes, 0, len(returns))}
+	for _, r := range returns {
+		res.Returns = append(res.Returns, toPBReturnRes(r))
+	}
+
+	return res
+}
+
+func toPBWarehouseRes(w *storer.Warehouse) *pb.WarehouseRes {
+	res := &pb.WarehouseRes{
+		Id:        w.ID,
+		Code:      w.Code,
+		Name:      w.Name,
+		CreatedAt: timestamppb.New(w.CreatedAt),
+	}
+	if w.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*w.UpdatedAt)
+	}
+
+	return res
+}
+
+func toPBStockMovement(m *storer.StockMovement) *pb.StockMovement {
+	return &pb.StockMovement{
+		Id:               m.ID,
+		WarehouseId:      m.WarehouseID,
+		ProductId:        m.ProductID,
+		VariantId:        m.VariantID,
+		Kind:             string(m.Kind),
+		Quantity:         m.Quantity,
+		Reason:           m.Reason,
+		ActorId:          fromIDPtr(m.ActorID),
+		OrderId:          fromIDPtr(m.OrderID),
+		OtherWarehouseId: fromIDPtr(m.OtherWarehouseID),
+		CreatedAt:        timestamppb.New(m.CreatedAt),
+	}
+}
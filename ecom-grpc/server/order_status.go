@@ -0,0 +1,49 @@
+package server
+
+import (
+	"slices"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/storer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// orderTransitions is the order lifecycle: the statuses an order may move to
+// from each status. Refunded is terminal.
+var orderTransitions = map[storer.OrderStatus][]storer.OrderStatus{
+	storer.Pending:    {storer.Paid, storer.Cancelled},
+	storer.Paid:       {storer.Processing, storer.Cancelled, storer.Refunded},
+	storer.Processing: {storer.Shipped, storer.Cancelled, storer.Refunded},
+	storer.Shipped:    {storer.Delivered},
+	storer.Delivered:  {storer.Refunded},
+	storer.Cancelled:  {storer.Refunded},
+}
+
+// checkTransition validates moving an order from one status to another.
+// Customers may only cancel their order, which the lifecycle allows until it
+// ships; every other transition is reserved for admins. Nobody marks an order
+// paid by hand: that happens when its payment is captured. Cancelling or
+// refunding an order again retries giving back its payments.
+func checkTransition(from, to storer.OrderStatus, isAdmin bool) error {
+	retry := from == to && (to == storer.Cancelled || to == storer.Refunded)
+	if !retry && !slices.Contains(orderTransitions[from], to) {
+		return status.Errorf(codes.FailedPrecondition, "cannot change order status from %s to %s", from, to)
+	}
+
+	if !isAdmin && to != storer.Cancelled {
+		return status.Errorf(codes.PermissionDenied, "only admins can change order status to %s", to)
+	}
+
+	if to == storer.Paid {
+		return status.Error(codes.FailedPrecondition, "orders are marked paid once their payment is captured")
+	}
+
+	return nil
+}
+
+func actorRole(isAdmin bool) storer.ActorRole {
+	if isAdmin {
+		return storer.ActorAdmin
+	}
+	return storer.ActorCustomer
+}
@@ -0,0 +1,393 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/storer"
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"github.com/OrkhanMehbaliyev/ecom-golang/payments"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PayOrder charges what gift cards and store credit left to pay of a
+// pending order through the payment provider. Approved payments are
+// captured straight away, which moves the order to paid. A decline is
+// returned as a declined payment rather than an error, so the customer can
+// try again with another source. Payments the provider has not decided on
+// yet are returned pending; their outcome arrives through
+// HandlePaymentEvent.
+func (s *Server) PayOrder(ctx context.Context, req *pb.PaymentReq) (*pb.PaymentRes, error) {
+	order, err := s.getOwnOrderStatus(ctx, &pb.OrderReq{Id: req.GetOrderId(), UserId: req.GetUserId(), IsAdmin: req.GetIsAdmin()})
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != storer.Pending {
+		return nil, status.Errorf(codes.FailedPrecondition, "order %d is %s, not pending", order.ID, order.Status)
+	}
+
+	source := strings.TrimSpace(req.GetSource())
+	if source == "" {
+		return nil, status.Error(codes.InvalidArgument, "payment source is empty")
+	}
+
+	o, err := s.storer.GetOrder(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	ps, err := s.storer.ListOrderPayments(ctx, o.ID)
+	if err != nil {
+		return nil, err
+	}
+	due := amountDue(o.TotalPrice, ps)
+
+	p, err := s.storer.CreatePayment(ctx, &storer.Payment{
+		OrderID:        o.ID,
+		Provider:       s.payments.Name(),
+		Status:         payments.StatusPending,
+		Amount:         due,
+		RefundedAmount: money.New(0, due.Currency),
+	})
+	if errors.Is(err, storer.ErrPaymentInProgress) {
+		return nil, status.Errorf(codes.FailedPrecondition, "order %d already has a payment in progress", o.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.payments.Authorize(ctx, &payments.AuthorizeReq{
+		OrderID: o.ID,
+		Amount:  due,
+		Source:  source,
+	})
+	if err != nil {
+		p.Status = payments.StatusFailed
+		p.Message = err.Error()
+		p.UpdatedAt = toTimePtr(time.Now())
+		if _, err := s.storer.UpdatePayment(ctx, p); err != nil {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Unavailable, "error authorizing payment: %v", err)
+	}
+
+	if err := s.applyPaymentResult(ctx, p, res); err != nil {
+		return nil, err
+	}
+
+	return s.settlePayment(ctx, p)
+}
+
+// ListOrderPayments returns the attempts to pay an order, oldest first.
+func (s *Server) ListOrderPayments(ctx context.Context, req *pb.PaymentReq) (*pb.ListPaymentsRes, error) {
+	order, err := s.getOwnOrderStatus(ctx, &pb.OrderReq{Id: req.GetOrderId(), UserId: req.GetUserId(), IsAdmin: req.GetIsAdmin()})
+	if err != nil {
+		return nil, err
+	}
+
+	ps, err := s.storer.ListOrderPayments(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.ListPaymentsRes{
+		Payments: make([]*pb.PaymentRes, 0, len(ps)),
+	}
+	for _, p := range ps {
+		res.Payments = append(res.Payments, toPBPaymentRes(p))
+	}
+
+	return res, nil
+}
+
+// HandlePaymentEvent applies a provider's decision on a pending payment, as
+// delivered to the API's webhook. Providers may deliver an event more than
+// once, so events for payments that are no longer pending are ignored.
+func (s *Server) HandlePaymentEvent(ctx context.Context, req *pb.PaymentEventReq) (*pb.PaymentRes, error) {
+	p, err := s.storer.GetPaymentByReference(ctx, req.GetProvider(), req.GetReference())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "%s payment %q not found", req.GetProvider(), req.GetReference())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Status != payments.StatusPending {
+		return toPBPaymentRes(p), nil
+	}
+
+	st := payments.Status(req.GetStatus())
+	switch st {
+	case payments.StatusAuthorized, payments.StatusDeclined, payments.StatusFailed:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unexpected payment status %q", req.GetStatus())
+	}
+
+	err = s.applyPaymentResult(ctx, p, &payments.Result{
+		Reference: p.Reference,
+		Status:    st,
+		Message:   req.GetMessage(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.settlePayment(ctx, p)
+}
+
+// settlePayment captures an authorized payment and marks its order paid.
+// When the order is no longer pending, e.g. because it was cancelled while
+// the provider made up its mind, the authorization is voided instead.
+func (s *Server) settlePayment(ctx context.Context, p *storer.Payment) (*pb.PaymentRes, error) {
+	if p.Status != payments.StatusAuthorized {
+		return toPBPaymentRes(p), nil
+	}
+
+	order, err := s.storer.GetOrderStatusByID(ctx, p.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != storer.Pending {
+		res, err := s.payments.Void(ctx, p.Reference)
+		if err != nil {
+			return nil, status.Errorf(codes.Unavailable, "error voiding payment %d: %v", p.ID, err)
+		}
+		if err := s.applyPaymentResult(ctx, p, res); err != nil {
+			return nil, err
+		}
+		return toPBPaymentRes(p), nil
+	}
+
+	res, err := s.payments.Capture(ctx, p.Reference, p.Amount)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "error capturing payment %d: %v", p.ID, err)
+	}
+	if err := s.applyPaymentResult(ctx, p, res); err != nil {
+		return nil, err
+	}
+
+	if err := s.markOrderPaid(ctx, order, p); err != nil {
+		return nil, err
+	}
+
+	return toPBPaymentRes(p), nil
+}
+
+// markOrderPaid moves a pending order to paid once p is captured, issuing the
+// gift cards bought in it along with the transition so they are issued once
+// and in full. Should the order have been cancelled in the meantime, the
+// payment is refunded; otherwise the order is invoiced.
+func (s *Server) markOrderPaid(ctx context.Context, order *storer.Order, p *storer.Payment) error {
+	customer, err := s.storer.GetUserByID(ctx, order.UserID)
+	if err != nil {
+		return err
+	}
+	cards, err := s.purchasedGiftCards(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+
+	order.Status = storer.Paid
+	order.UpdatedAt = toTimePtr(time.Now())
+	// a code drawn twice fails the transition as a whole, so it is simply
+	// tried again with new codes
+	for attempt := 1; ; attempt++ {
+		if err := drawGiftCardCodes(cards); err != nil {
+			return err
+		}
+		_, err = s.storer.UpdateOrderStatus(ctx, order, &storer.OrderStatusChange{
+			FromStatus: storer.Pending,
+			Reason:     fmt.Sprintf("payment %d captured", p.ID),
+			ActorRole:  storer.ActorSystem,
+			GiftCards:  cards,
+		}, &storer.NotificationEvent{
+			UserEmail: customer.Email,
+		})
+		if !errors.Is(err, storer.ErrGiftCardExists) || attempt == giftCardCodeAttempts {
+			break
+		}
+	}
+	if errors.Is(err, storer.ErrOrderStatusChanged) {
+		return s.refundPayment(ctx, p, order.UserID, orderRefundKey(order.ID), false)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = s.issueInvoice(ctx, order.ID)
+	return err
+}
+
+// releaseOrderPayments undoes the payments of an order that was cancelled
+// or refunded: those not captured yet are voided when the order is
+// cancelled, and what is left of the captured ones is refunded, so that
+// cancelling a paid order gives back all the customer paid. toStoreCredit
+// refunds what the payment provider took into the customer's store credit.
+// It runs once the order's status has changed, so that concurrent cancels
+// and refunds do not both release the payments, and refunds are keyed by the
+// order, so that running it again retries what failed without refunding
+// anything twice.
+func (s *Server) releaseOrderPayments(ctx context.Context, order *storer.Order, toStoreCredit bool) error {
+	to := order.Status
+	if to != storer.Cancelled && to != storer.Refunded {
+		return nil
+	}
+	// refunds an earlier attempt recorded are carried out before what is
+	// left is refunded
+	if err := s.applyPendingRefunds(ctx, order.ID, orderRefundKey(order.ID)); err != nil {
+		return err
+	}
+
+	ps, err := s.storer.ListOrderPayments(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range ps {
+		switch {
+		case to == storer.Cancelled && (p.Status == payments.StatusPending || p.Status == payments.StatusAuthorized):
+			res, err := s.payments.Void(ctx, p.Reference)
+			if err != nil {
+				return status.Errorf(codes.Unavailable, "error voiding payment %d: %v", p.ID, err)
+			}
+			if err := s.applyPaymentResult(ctx, p, res); err != nil {
+				return err
+			}
+		case p.Status == payments.StatusCaptured:
+			if err := s.refundPayment(ctx, p, order.UserID, orderRefundKey(order.ID), toStoreCredit); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// orderRefundKey is the key payments are refunded for when their order is
+// cancelled or refunded.
+func orderRefundKey(orderID int64) string {
+	return fmt.Sprintf("order %d", orderID)
+}
+
+// returnRefundKey is the key payments are refunded for when a return is
+// refunded.
+func returnRefundKey(returnID int64) string {
+	return fmt.Sprintf("return %d", returnID)
+}
+
+// refundPayment refunds what is left to refund of captured payment p for
+// key.
+func (s *Server) refundPayment(ctx context.Context, p *storer.Payment, userID int64, key string, toStoreCredit bool) error {
+	left := p.Amount.Sub(p.RefundedAmount)
+	if left.Amount <= 0 {
+		return nil
+	}
+	return s.refundPaymentAmount(ctx, p, userID, key, left, toStoreCredit)
+}
+
+// refundPaymentAmount refunds amount of captured payment p of user userID's
+// order for key, which stays captured until all of it is refunded. A
+// payment is refunded for a key only once; refunding it for the key again
+// does nothing. Gift card, store credit and loyalty points payments go back
+// where they came from together with the refund being recorded. Payments
+// taken by the provider are refunded into the user's store credit when
+// toStoreCredit is set, or else through the provider: the refund is
+// recorded pending first and applied once the provider carried it out, so
+// that a refund the provider failed, or never heard of, is retried by
+// applyPendingRefunds rather than lost.
+func (s *Server) refundPaymentAmount(ctx context.Context, p *storer.Payment, userID int64, key string, amount money.Money, toStoreCredit bool) error {
+	r := &storer.PaymentRefund{Key: key, Amount: amount}
+	reason := fmt.Sprintf("payment %d refunded", p.ID)
+	switch {
+	case p.Provider == storer.GiftCardProvider:
+		g, err := s.getGiftCard(ctx, p.Reference)
+		if err != nil {
+			return err
+		}
+		r.GiftCard = &storer.GiftCardTransaction{
+			GiftCardID: g.ID,
+			Kind:       storer.BalanceRefund,
+			Amount:     amount,
+			OrderID:    &p.OrderID,
+			Reason:     reason,
+		}
+	case p.Provider == storer.LoyaltyPointsProvider:
+		r.Loyalty = s.loyaltyRefund(p, userID, amount)
+	case p.Provider == storer.StoreCreditProvider || toStoreCredit:
+		r.StoreCredit = &storer.StoreCreditTransaction{
+			UserID:  userID,
+			Kind:    storer.BalanceRefund,
+			Amount:  amount,
+			OrderID: &p.OrderID,
+			Reason:  reason,
+		}
+	}
+
+	_, err := s.storer.RefundPayment(ctx, p, r)
+	if errors.Is(err, storer.ErrRefundApplied) {
+		return nil
+	}
+	if errors.Is(err, storer.ErrPaymentChanged) {
+		return status.Errorf(codes.Aborted, "payment %d was updated concurrently, try again", p.ID)
+	}
+	if err != nil {
+		return err
+	}
+	if r.Status != storer.RefundPending {
+		return nil
+	}
+
+	return s.applyPendingRefund(ctx, p, r)
+}
+
+// applyPendingRefunds carries out the refunds of an order's payments for key
+// that were recorded but not carried out by the payment provider, e.g.
+// because it failed or the server stopped in between.
+func (s *Server) applyPendingRefunds(ctx context.Context, orderID int64, key string) error {
+	refunds, err := s.storer.ListOrderPaymentRefunds(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range refunds {
+		if r.Key != key || r.Status != storer.RefundPending {
+			continue
+		}
+		p, err := s.storer.GetPayment(ctx, r.PaymentID)
+		if err != nil {
+			return err
+		}
+		if err := s.applyPendingRefund(ctx, p, r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyPendingRefund has the payment provider carry out pending refund r of
+// p. The provider refunds under r's key only once, so a refund it carried
+// out already is not paid out again.
+func (s *Server) applyPendingRefund(ctx context.Context, p *storer.Payment, r *storer.PaymentRefund) error {
+	if _, err := s.payments.Refund(ctx, p.Reference, r.Key, r.Amount); err != nil {
+		return status.Errorf(codes.Unavailable, "error refunding payment %d: %v", p.ID, err)
+	}
+
+	return s.storer.ApplyPaymentRefund(ctx, r.ID)
+}
+
+// applyPaymentResult records what the provider answered about p.
+func (s *Server) applyPaymentResult(ctx context.Context, p *storer.Payment, res *payments.Result) error {
+	p.Reference = res.Reference
+	p.Status = res.Status
+	p.Message = res.Message
+	p.UpdatedAt = toTimePtr(time.Now())
+
+	_, err := s.storer.UpdatePayment(ctx, p)
+	return err
+}
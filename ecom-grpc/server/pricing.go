@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/storer"
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Constants
+const (
+	// tax rate in basis points, i.e. 10%, charged where no tax rate is
+	// configured
+	taxRateBasisPoints = 1000
+	// most units of a product or variant a cart or order line may hold
+	maxLineQuantity = 1000
+)
+
+var (
+	flatShippingPrice     = money.New(1000, money.DefaultCurrency)
+	freeShippingThreshold = money.New(10000, money.DefaultCurrency)
+)
+
+// orderQuote is the server-side price breakdown of an order, computed from
+// the current product catalog rather than from client input.
+type orderQuote struct {
+	Items           []storer.OrderItem
+	ShippingAddress *storer.Address
+	BillingAddress  *storer.Address
+	// ShippingMethodID is the shipping method the customer chose, or 0 for
+	// the cheapest one.
+	ShippingMethodID int64
+	ShippingMethod   string
+	ItemsPrice       money.Money
+	TaxPrice         money.Money
+	// IncludedTax is the part of TaxPrice contained in tax-inclusive prices.
+	IncludedTax   money.Money
+	ShippingPrice money.Money
+	DiscountPrice money.Money
+	TotalPrice    money.Money
+	CouponCode    string
+	Discounts     storer.OrderDiscounts
+	// Products holds the ordered products by id, for scoping promotions and
+	// taxing lines by their tax class.
+	Products map[int64]*storer.Product
+}
+
+// quoteOrder prices every requested line at the current price of its
+// variant, or of the product when it has no variants, which is the lowest
+// scheduled price running for it if any. Lines sold at a scheduled price
+// refer to it, so that CreateOrder counts them against its cap. It applies
+// promotions and derives tax, shipping and the grand total. All amounts are
+// exact minor units; the only rounding is the tax, which is taken on every
+// discounted line and rounded half away from zero to the cent, and
+// percentage discounts, rounded the same way.
+func (s *Server) quoteOrder(ctx context.Context, o *pb.OrderReq) (*orderQuote, error) {
+	shipping, billing, err := s.orderAddresses(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+
+	items := o.GetItems()
+	ids := make([]int64, 0, len(items))
+	for _, oi := range items {
+		ids = append(ids, oi.GetProductId())
+	}
+
+	products, err := s.storer.GetProducts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*storer.Product, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+	variants, err := s.productVariants(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	sps, err := s.runningPrices(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &orderQuote{
+		ShippingAddress:  shipping,
+		BillingAddress:   billing,
+		ShippingMethodID: o.GetShippingMethodId(),
+		ItemsPrice:       money.New(0, money.DefaultCurrency),
+		Products:         byID,
+	}
+	for _, oi := range items {
+		p, ok := byID[oi.GetProductId()]
+		if !ok {
+			return nil, status.Errorf(codes.NotFound, "product %d not found", oi.GetProductId())
+		}
+		v, err := resolveVariant(p, variants[p.ID], oi.GetVariantId())
+		if err != nil {
+			return nil, err
+		}
+
+		price, sp := sps.price(p, v)
+		if oi.Price != nil && toMoney(oi.Price) != price {
+			return nil, status.Errorf(codes.InvalidArgument, "price %s for product %d does not match current price %s", toMoney(oi.Price), p.ID, price)
+		}
+
+		item := storer.OrderItem{
+			Name:          variantName(p, v),
+			Quantity:      oi.GetQuantity(),
+			Image:         variantImage(p, v),
+			Price:         price,
+			ProductID:     p.ID,
+			DiscountPrice: money.New(0, money.DefaultCurrency),
+		}
+		if v != nil {
+			item.VariantID = &v.ID
+			item.SKU = v.SKU
+		}
+		if sp != nil {
+			item.ScheduledPriceID = &sp.ID
+		}
+		q.Items = append(q.Items, item)
+		q.ItemsPrice = q.ItemsPrice.Add(lineTotal(item))
+	}
+
+	if err := s.applyPromotions(ctx, q, o.GetUserId(), o.GetCouponCode()); err != nil {
+		return nil, err
+	}
+	if err := s.taxOrder(ctx, q, o.GetUserId()); err != nil {
+		return nil, err
+	}
+	q.TotalPrice = q.ItemsPrice.Add(q.TaxPrice).Sub(q.IncludedTax).Add(q.ShippingPrice).Sub(q.DiscountPrice)
+
+	return q, nil
+}
+
+// checkClientTotals rejects orders whose client-sent totals disagree with the
+// quote. Totals the client left out are not checked.
+func checkClientTotals(o *pb.OrderReq, q *orderQuote) error {
+	totals := []struct {
+		name   string
+		client *pb.Money
+		quoted money.Money
+	}{
+		{"tax_price", o.TaxPrice, q.TaxPrice},
+		{"shipping_price", o.ShippingPrice, q.ShippingPrice},
+		{"discount_price", o.DiscountPrice, q.DiscountPrice},
+		{"total_price", o.TotalPrice, q.TotalPrice},
+	}
+
+	for _, t := range totals {
+		if t.client != nil && toMoney(t.client) != t.quoted {
+			return status.Errorf(codes.InvalidArgument, "%s %s does not match computed %s", t.name, toMoney(t.client), t.quoted)
+		}
+	}
+
+	return nil
+}
+
+// checkCurrency rejects amounts in any currency but the one the store prices
+// in. Amounts without a currency are taken to be in the default currency.
+func checkCurrency(amounts ...*pb.Money) error {
+	for _, m := range amounts {
+		if m != nil && m.GetCurrency() != "" && m.GetCurrency() != money.DefaultCurrency {
+			return status.Errorf(codes.InvalidArgument, "unsupported currency %q, prices are in %s", m.GetCurrency(), money.DefaultCurrency)
+		}
+	}
+
+	return nil
+}
+
+func lineTotal(oi storer.OrderItem) money.Money {
+	return oi.Price.Mul(oi.Quantity)
+}
@@ -0,0 +1,468 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/storer"
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Constants
+const (
+	maxPromotionName = 255
+	maxCouponCode    = 64
+	// a percentage discount in basis points, i.e. 100%
+	maxDiscountRate = 10000
+)
+
+var couponCodePattern = regexp.MustCompile(`^[A-Z0-9_-]+$`)
+
+// normalizeCouponCode makes coupon codes case insensitive.
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// CreatePromotion adds a promotion. Promotions without a code apply to every
+// qualifying order automatically.
+func (s *Server) CreatePromotion(ctx context.Context, req *pb.PromotionReq) (*pb.PromotionRes, error) {
+	p, err := s.toStorerPromotion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.Active = req.Active == nil || req.GetActive()
+
+	created, err := s.storer.CreatePromotion(ctx, p)
+	if err != nil {
+		if errors.Is(err, storer.ErrPromotionExists) {
+			return nil, status.Errorf(codes.AlreadyExists, "coupon code %q is already taken", req.GetCode())
+		}
+		return nil, err
+	}
+
+	return s.getPromotionRes(ctx, created.ID)
+}
+
+func (s *Server) GetPromotion(ctx context.Context, req *pb.PromotionReq) (*pb.PromotionRes, error) {
+	return s.getPromotionRes(ctx, req.GetId())
+}
+
+func (s *Server) ListPromotions(ctx context.Context, req *pb.ListPromotionsReq) (*pb.ListPromotionsRes, error) {
+	promotions, err := s.storer.ListPromotions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.ListPromotionsRes{
+		Promotions: make([]*pb.PromotionRes, 0, len(promotions)),
+	}
+	for _, p := range promotions {
+		res.Promotions = append(res.Promotions, toPBPromotionRes(p))
+	}
+
+	return res, nil
+}
+
+// UpdatePromotion replaces a promotion with req. Orders that already redeemed
+// it keep the discount they were given.
+func (s *Server) UpdatePromotion(ctx context.Context, req *pb.PromotionReq) (*pb.PromotionRes, error) {
+	existing, err := s.getPromotion(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.toStorerPromotion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.ID = existing.ID
+	p.Active = req.Active == nil || req.GetActive()
+	p.CreatedAt = existing.CreatedAt
+	p.UpdatedAt = toTimePtr(time.Now())
+	if _, err := s.storer.UpdatePromotion(ctx, p); err != nil {
+		if errors.Is(err, storer.ErrPromotionExists) {
+			return nil, status.Errorf(codes.AlreadyExists, "coupon code %q is already taken", req.GetCode())
+		}
+		return nil, err
+	}
+
+	return s.getPromotionRes(ctx, p.ID)
+}
+
+// DeletePromotion deletes a promotion that was never redeemed.
+func (s *Server) DeletePromotion(ctx context.Context, req *pb.PromotionReq) (*pb.PromotionRes, error) {
+	if _, err := s.getPromotion(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	err := s.storer.DeletePromotion(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, storer.ErrPromotionInUse) {
+			return nil, status.Errorf(codes.FailedPrecondition, "promotion %d was redeemed and cannot be deleted, deactivate it instead", req.GetId())
+		}
+		return nil, err
+	}
+
+	return &pb.PromotionRes{}, nil
+}
+
+func (s *Server) getPromotion(ctx context.Context, id int64) (*storer.Promotion, error) {
+	p, err := s.storer.GetPromotion(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "promotion %d not found", id)
+		}
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (s *Server) getPromotionRes(ctx context.Context, id int64) (*pb.PromotionRes, error) {
+	p, err := s.getPromotion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBPromotionRes(p), nil
+}
+
+// toStorerPromotion validates req and maps it to a promotion, leaving Active
+// to the caller.
+func (s *Server) toStorerPromotion(ctx context.Context, req *pb.PromotionReq) (*storer.Promotion, error) {
+	if err := checkCurrency(req.GetDiscountAmount(), req.GetMinSubtotal()); err != nil {
+		return nil, err
+	}
+
+	p := &storer.Promotion{
+		Name:             strings.TrimSpace(req.GetName()),
+		Type:             toStorerPromotionType(req.GetType()),
+		DiscountAmount:   money.New(0, money.DefaultCurrency),
+		ProductID:        toIDPtr(req.GetProductId()),
+		CategoryID:       toIDPtr(req.GetCategoryId()),
+		MinSubtotal:      money.New(0, money.DefaultCurrency),
+		UsageLimit:       req.GetUsageLimit(),
+		PerCustomerLimit: req.GetPerCustomerLimit(),
+	}
+	if code := normalizeCouponCode(req.GetCode()); code != "" {
+		p.Code = &code
+	}
+	if req.StartsAt != nil {
+		p.StartsAt = toTimePtr(req.GetStartsAt().AsTime())
+	}
+	if req.EndsAt != nil {
+		p.EndsAt = toTimePtr(req.GetEndsAt().AsTime())
+	}
+	if req.MinSubtotal != nil {
+		p.MinSubtotal = toMoney(req.MinSubtotal)
+	}
+	// only the parameters of the promotion's type are kept
+	switch p.Type {
+	case storer.PercentageOff:
+		p.DiscountRate = req.GetDiscountRate()
+	case storer.FixedAmountOff:
+		p.DiscountAmount = toMoney(req.GetDiscountAmount())
+	case storer.BuyXGetY:
+		p.BuyQuantity = req.GetBuyQuantity()
+		p.GetQuantity = req.GetGetQuantity()
+	}
+	if err := checkPromotion(p); err != nil {
+		return nil, err
+	}
+
+	if p.ProductID != nil {
+		if _, err := s.getProduct(ctx, *p.ProductID); err != nil {
+			return nil, err
+		}
+	}
+	if p.CategoryID != nil {
+		if _, err := s.getCategory(ctx, *p.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+func checkPromotion(p *storer.Promotion) error {
+	switch {
+	case p.Name == "":
+		return status.Error(codes.InvalidArgument, "promotion name is empty")
+	case utf8.RuneCountInString(p.Name) > maxPromotionName:
+		return status.Errorf(codes.InvalidArgument, "promotion name is longer than %d characters", maxPromotionName)
+	case p.Code != nil && !couponCodePattern.MatchString(*p.Code):
+		return status.Errorf(codes.InvalidArgument, "invalid coupon code %q, use letters, digits, dashes and underscores", *p.Code)
+	case p.Code != nil && len(*p.Code) > maxCouponCode:
+		return status.Errorf(codes.InvalidArgument, "coupon code is longer than %d characters", maxCouponCode)
+	case p.ProductID != nil && p.CategoryID != nil:
+		return status.Error(codes.InvalidArgument, "a promotion is scoped to a product or a category, not both")
+	case p.Type == storer.FreeShipping && (p.ProductID != nil || p.CategoryID != nil):
+		return status.Error(codes.InvalidArgument, "free shipping promotions apply to the whole order and cannot be scoped")
+	case p.Type == storer.PercentageOff && (p.DiscountRate <= 0 || p.DiscountRate > maxDiscountRate):
+		return status.Errorf(codes.InvalidArgument, "discount_rate must be between 1 and %d basis points", maxDiscountRate)
+	case p.Type == storer.FixedAmountOff && p.DiscountAmount.Amount <= 0:
+		return status.Errorf(codes.InvalidArgument, "invalid discount_amount %s", p.DiscountAmount)
+	case p.Type == storer.BuyXGetY && (p.BuyQuantity <= 0 || p.GetQuantity <= 0):
+		return status.Error(codes.InvalidArgument, "buy_quantity and get_quantity must be positive")
+	case p.MinSubtotal.Amount < 0:
+		return status.Errorf(codes.InvalidArgument, "invalid min_subtotal %s", p.MinSubtotal)
+	case p.UsageLimit < 0 || p.PerCustomerLimit < 0:
+		return status.Error(codes.InvalidArgument, "usage limits cannot be negative")
+	case p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt):
+		return status.Error(codes.InvalidArgument, "ends_at must be after starts_at")
+	}
+
+	return nil
+}
+
+// applyPromotions applies the automatic promotions running now and the
+// coupon, if given, to q and prices its shipping. Promotions stack: every
+// item promotion is taken off what the ones before it left of its lines, in
+// id order with the coupon last, and none can take a line below zero.
+// Shipping is priced on the discounted subtotal.
+// Automatic promotions the order does not qualify for are skipped, while a
+// coupon that does not apply fails the order so the customer learns why.
+func (s *Server) applyPromotions(ctx context.Context, q *orderQuote, userID int64, code string) error {
+	now := time.Now()
+	promotions, err := s.storer.ListAutomaticPromotions(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	var coupon *storer.Promotion
+	if code = normalizeCouponCode(code); code != "" {
+		coupon, err = s.storer.GetPromotionByCode(ctx, code)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return status.Errorf(codes.NotFound, "coupon %q not found", code)
+			}
+			return err
+		}
+		if !coupon.Running(now) {
+			return status.Errorf(codes.FailedPrecondition, "coupon %q is not valid at this time", code)
+		}
+		promotions = append(promotions, coupon)
+		q.CouponCode = code
+	}
+
+	ids := make([]int64, 0, len(promotions))
+	needTree := false
+	for _, p := range promotions {
+		ids = append(ids, p.ID)
+		needTree = needTree || p.CategoryID != nil
+	}
+	used, err := s.storer.CountUserRedemptions(ctx, userID, ids)
+	if err != nil {
+		return err
+	}
+	var tree *categoryTree
+	if needTree {
+		if tree, err = s.categoryTree(ctx); err != nil {
+			return err
+		}
+	}
+
+	itemDiscount := money.New(0, money.DefaultCurrency)
+	var shipping []*storer.Promotion
+	for _, p := range promotions {
+		reason := promotionIneligible(p, q, used[p.ID])
+		amount := money.New(0, money.DefaultCurrency)
+		var lines []int
+		if reason == "" && p.Type != storer.FreeShipping {
+			lines = scopedLines(p, q, tree)
+			amount = promotionDiscount(p, q, lines)
+			if remaining := q.remaining(lines); amount.Cmp(remaining) > 0 {
+				amount = remaining
+			}
+			if amount.IsZero() {
+				reason = "does not apply to any item in the order"
+			}
+		}
+		if reason != "" {
+			if p == coupon {
+				return status.Errorf(codes.FailedPrecondition, "coupon %q %s", code, reason)
+			}
+			continue
+		}
+
+		if p.Type == storer.FreeShipping {
+			shipping = append(shipping, p)
+			continue
+		}
+		q.allocateDiscount(lines, amount)
+		itemDiscount = itemDiscount.Add(amount)
+		q.Discounts = append(q.Discounts, toOrderDiscount(p, amount))
+	}
+
+	if err := s.priceShipping(ctx, q, itemDiscount); err != nil {
+		return err
+	}
+	q.DiscountPrice = itemDiscount
+	waived := false
+	for _, p := range shipping {
+		// shipping is waived once, by the first promotion offering it
+		if waived || q.ShippingPrice.IsZero() {
+			if p == coupon {
+				return status.Errorf(codes.FailedPrecondition, "coupon %q does not apply, shipping is already free", code)
+			}
+			continue
+		}
+		q.Discounts = append(q.Discounts, toOrderDiscount(p, q.ShippingPrice))
+		q.DiscountPrice = q.DiscountPrice.Add(q.ShippingPrice)
+		waived = true
+	}
+
+	return nil
+}
+
+// promotionIneligible explains why an order cannot use p, or returns "" if it
+// can. used is how often the customer already redeemed p.
+func promotionIneligible(p *storer.Promotion, q *orderQuote, used int64) string {
+	switch {
+	case p.UsageLimit > 0 && p.TimesUsed >= p.UsageLimit:
+		return "has reached its usage limit"
+	case p.PerCustomerLimit > 0 && used >= p.PerCustomerLimit:
+		return "was already used the maximum number of times"
+	case q.ItemsPrice.Cmp(p.MinSubtotal) < 0:
+		return "requires a subtotal of at least " + p.MinSubtotal.String()
+	}
+
+	return ""
+}
+
+// scopedLines returns the indices of the order lines p applies to.
+func scopedLines(p *storer.Promotion, q *orderQuote, tree *categoryTree) []int {
+	var categories []int64
+	if p.CategoryID != nil {
+		categories = tree.subtreeIDs(*p.CategoryID)
+	}
+
+	var res []int
+	for i, oi := range q.Items {
+		categoryID := q.Products[oi.ProductID].CategoryID
+		switch {
+		case p.ProductID == nil && p.CategoryID == nil:
+			res = append(res, i)
+		case p.ProductID != nil && oi.ProductID == *p.ProductID:
+			res = append(res, i)
+		case p.CategoryID != nil && categoryID != nil && slices.Contains(categories, *categoryID):
+			res = append(res, i)
+		}
+	}
+
+	return res
+}
+
+// promotionDiscount is what an item promotion takes off the given lines,
+// before capping it to what is left of them.
+func promotionDiscount(p *storer.Promotion, q *orderQuote, lines []int) money.Money {
+	items := make([]storer.OrderItem, 0, len(lines))
+	for _, i := range lines {
+		items = append(items, q.Items[i])
+	}
+
+	subtotal := itemsPrice(items)
+	switch p.Type {
+	case storer.PercentageOff:
+		return subtotal.MulRate(p.DiscountRate)
+	case storer.FixedAmountOff:
+		if p.DiscountAmount.Cmp(subtotal) > 0 {
+			return subtotal
+		}
+		return p.DiscountAmount
+	case storer.BuyXGetY:
+		return freeUnitsPrice(items, p.BuyQuantity, p.GetQuantity)
+	}
+
+	return money.New(0, money.DefaultCurrency)
+}
+
+// remaining is what the given lines still cost after the discounts already
+// taken off them.
+func (q *orderQuote) remaining(lines []int) money.Money {
+	res := money.New(0, money.DefaultCurrency)
+	for _, i := range lines {
+		res = res.Add(lineTotal(q.Items[i]).Sub(q.Items[i].DiscountPrice))
+	}
+	return res
+}
+
+// allocateDiscount spreads an item discount over the lines it was taken off,
+// in proportion to what each of them still costs, so every line is taxed on
+// what the customer pays for it. Cents left over from rounding down go to
+// the lines with the largest remainders, the earlier line on a tie.
+func (q *orderQuote) allocateDiscount(lines []int, amount money.Money) {
+	total := q.remaining(lines).Amount
+	if total == 0 {
+		return
+	}
+
+	rems := make([]int64, len(lines))
+	left := amount.Amount
+	for k, i := range lines {
+		oi := &q.Items[i]
+		weighted := amount.Amount * lineTotal(*oi).Sub(oi.DiscountPrice).Amount
+		share := weighted / total
+		rems[k] = weighted % total
+		oi.DiscountPrice = oi.DiscountPrice.Add(money.New(share, amount.Currency))
+		left -= share
+	}
+
+	order := make([]int, len(lines))
+	for k := range order {
+		order[k] = k
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(rems[b], rems[a])
+	})
+	for _, k := range order[:left] {
+		oi := &q.Items[lines[k]]
+		oi.DiscountPrice = oi.DiscountPrice.Add(money.New(1, amount.Currency))
+	}
+}
+
+// freeUnitsPrice gives get units away for every full group of buy+get units,
+// the cheapest units of the lines, so the customer pays for the expensive
+// ones. Units are counted per price rather than one by one.
+func freeUnitsPrice(items []storer.OrderItem, buy, get int64) money.Money {
+	units := make(map[int64]int64)
+	var total int64
+	for _, oi := range items {
+		units[oi.Price.Amount] += oi.Quantity
+		total += oi.Quantity
+	}
+	prices := slices.Sorted(maps.Keys(units))
+
+	left := total / (buy + get) * get
+	free := money.New(0, money.DefaultCurrency)
+	for _, price := range prices {
+		n := min(units[price], left)
+		free = free.Add(money.New(price, money.DefaultCurrency).Mul(n))
+		left -= n
+	}
+
+	return free
+}
+
+func toOrderDiscount(p *storer.Promotion, amount money.Money) storer.OrderDiscount {
+	d := storer.OrderDiscount{
+		PromotionID: p.ID,
+		Name:        p.Name,
+		Amount:      amount,
+	}
+	if p.Code != nil {
+		d.Code = *p.Code
+	}
+	return d
+}
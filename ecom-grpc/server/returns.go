@@ -0,0 +1,425 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/storer"
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"github.com/OrkhanMehbaliyev/ecom-golang/payments"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Constants
+const (
+	maxReturnReason = 512
+	maxReturnNote   = 512
+	maxPhotosURL    = 1024
+)
+
+// returnTransitions is the return workflow: the statuses a return may move
+// to from each status. Rejected and refunded are terminal.
+var returnTransitions = map[storer.ReturnStatus][]storer.ReturnStatus{
+	storer.ReturnRequested: {storer.ReturnApproved, storer.ReturnRejected},
+	storer.ReturnApproved:  {storer.ReturnReceived, storer.ReturnRejected},
+	storer.ReturnReceived:  {storer.ReturnRefunded},
+}
+
+// CreateReturn requests the return of some units of an item of a delivered
+// order. What the customer gets back for them is worked out now, from what
+// they paid for the item.
+func (s *Server) CreateReturn(ctx context.Context, req *pb.ReturnReq) (*pb.ReturnRes, error) {
+	reason, photosURL, err := checkReturn(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getOwnOrderStatus(ctx, &pb.OrderReq{Id: req.GetOrderId(), UserId: req.GetUserId(), IsAdmin: req.GetIsAdmin()}); err != nil {
+		return nil, err
+	}
+	order, err := s.storer.GetOrder(ctx, req.GetOrderId())
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != storer.Delivered {
+		return nil, status.Errorf(codes.FailedPrecondition, "order %d is %s, only delivered orders can be returned", order.ID, order.Status)
+	}
+
+	item, ok := orderItem(order, req.GetOrderItemId())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "order %d has no item %d", order.ID, req.GetOrderItemId())
+	}
+	if req.GetQuantity() < 1 || req.GetQuantity() > item.Quantity {
+		return nil, status.Errorf(codes.InvalidArgument, "quantity must be between 1 and %d", item.Quantity)
+	}
+
+	customer, err := s.storer.GetUserByID(ctx, order.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := s.storer.CreateReturn(ctx, &storer.Return{
+		OrderID:      order.ID,
+		OrderItemID:  item.ID,
+		UserID:       order.UserID,
+		Quantity:     req.GetQuantity(),
+		Reason:       reason,
+		PhotosURL:    photosURL,
+		Status:       storer.ReturnRequested,
+		RefundAmount: returnCredit(item, req.GetQuantity()).Total,
+	}, &storer.NotificationEvent{
+		UserEmail:   customer.Email,
+		OrderStatus: order.Status,
+	})
+	if errors.Is(err, storer.ErrReturnQuantityExceeded) {
+		return nil, status.Errorf(codes.FailedPrecondition, "fewer than %d units of item %d are left to return", req.GetQuantity(), item.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getReturnRes(ctx, r.ID)
+}
+
+// ListOrderReturns returns the returns of an order, oldest first.
+func (s *Server) ListOrderReturns(ctx context.Context, o *pb.OrderReq) (*pb.ListReturnsRes, error) {
+	order, err := s.getOwnOrderStatus(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+
+	returns, err := s.storer.ListOrderReturns(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBListReturnsRes(returns), nil
+}
+
+// ListReturns returns every return in a status, or every return, oldest
+// first. It is for admins working through returns.
+func (s *Server) ListReturns(ctx context.Context, req *pb.ListReturnsReq) (*pb.ListReturnsRes, error) {
+	st := storer.ReturnStatus(strings.ToLower(req.GetStatus()))
+	if st != "" && !isReturnStatus(st) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown return status %q", req.GetStatus())
+	}
+
+	returns, err := s.storer.ListReturns(ctx, st)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBListReturnsRes(returns), nil
+}
+
+// UpdateReturnStatus moves a return along its workflow, which only admins
+// do. Received units may be put back into stock, and refunding a return
+// refunds its amount from the order's payments, or into the customer's
+// store credit, credits it on the order's invoice and takes back the loyalty
+// points it earned. The customer is notified of every change. Refunding a
+// refunded return again retries giving back what is left of its amount.
+func (s *Server) UpdateReturnStatus(ctx context.Context, req *pb.ReturnReq) (*pb.ReturnRes, error) {
+	if !req.GetIsAdmin() {
+		return nil, status.Error(codes.PermissionDenied, "only admins can change return status")
+	}
+
+	note := strings.TrimSpace(req.GetNote())
+	if utf8.RuneCountInString(note) > maxReturnNote {
+		return nil, status.Errorf(codes.InvalidArgument, "note is longer than %d characters", maxReturnNote)
+	}
+
+	r, err := s.getReturn(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := r.Status, storer.ReturnStatus(strings.ToLower(req.GetStatus()))
+	retry := from == storer.ReturnRefunded && to == storer.ReturnRefunded
+	if !retry && !slices.Contains(returnTransitions[from], to) {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot change return status from %s to %s", from, to)
+	}
+	if req.GetRestock() && to != storer.ReturnReceived {
+		return nil, status.Error(codes.InvalidArgument, "only received returns can be restocked")
+	}
+
+	var captured []*storer.Payment
+	var due money.Money
+	if to == storer.ReturnRefunded {
+		captured, due, err = s.returnRefunds(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the return is claimed before any money moves, so that concurrent
+	// requests do not both refund it, and refunds are keyed by the return,
+	// so that a retry only gives back what failed
+	if !retry {
+		if err := s.changeReturnStatus(ctx, r, to, note, req.GetRestock()); err != nil {
+			return nil, err
+		}
+	}
+
+	if to == storer.ReturnRefunded {
+		if err := s.refundReturn(ctx, r, captured, due, req.GetRefundToStoreCredit()); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.getReturnRes(ctx, r.ID)
+}
+
+// changeReturnStatus moves r to status to, failing with Aborted if its
+// status changed since it was read. A refunded return's credit note and the
+// loyalty points it takes back are recorded with the change.
+func (s *Server) changeReturnStatus(ctx context.Context, r *storer.Return, to storer.ReturnStatus, note string, restock bool) error {
+	order, err := s.storer.GetOrder(ctx, r.OrderID)
+	if err != nil {
+		return err
+	}
+	customer, err := s.storer.GetUserByID(ctx, r.UserID)
+	if err != nil {
+		return err
+	}
+
+	from := r.Status
+	r.Status = to
+	if note != "" {
+		r.Note = note
+	}
+	if to == storer.ReturnReceived {
+		r.Restocked = restock
+	}
+	r.UpdatedAt = toTimePtr(time.Now())
+	if to == storer.ReturnRefunded {
+		r.CreditNote, err = s.returnCreditNote(ctx, order, r)
+		if err != nil {
+			return err
+		}
+		reason := fmt.Sprintf("return %d refunded", r.ID)
+		r.Loyalty, err = s.loyaltyReversal(ctx, r.UserID, order.ID, &r.RefundAmount, reason)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = s.storer.UpdateReturnStatus(ctx, r, from, &storer.NotificationEvent{
+		UserEmail:   customer.Email,
+		OrderStatus: order.Status,
+	})
+	if errors.Is(err, storer.ErrReturnStatusChanged) {
+		return status.Errorf(codes.Aborted, "return %d was updated concurrently, try again", r.ID)
+	}
+
+	return err
+}
+
+// returnRefunds returns what is left to refund of r and the captured
+// payments of its order to refund it from, newest first, so what the payment
+// provider took is refunded before gift cards, store credit and loyalty
+// points are given back. Payments refunded for r already are left out.
+func (s *Server) returnRefunds(ctx context.Context, r *storer.Return) ([]*storer.Payment, money.Money, error) {
+	refunds, err := s.storer.ListOrderPaymentRefunds(ctx, r.OrderID)
+	if err != nil {
+		return nil, money.Money{}, err
+	}
+
+	key := returnRefundKey(r.ID)
+	due := r.RefundAmount
+	refunded := make(map[int64]bool)
+	for _, pr := range refunds {
+		if pr.Key == key {
+			due = due.Sub(pr.Amount)
+			refunded[pr.PaymentID] = true
+		}
+	}
+	if due.Amount <= 0 {
+		return nil, due, nil
+	}
+
+	ps, err := s.storer.ListOrderPayments(ctx, r.OrderID)
+	if err != nil {
+		return nil, money.Money{}, err
+	}
+
+	var captured []*storer.Payment
+	left := money.New(0, r.RefundAmount.Currency)
+	for _, p := range slices.Backward(ps) {
+		if p.Status == payments.StatusCaptured && !refunded[p.ID] {
+			captured = append(captured, p)
+			left = left.Add(p.Amount.Sub(p.RefundedAmount))
+		}
+	}
+	if len(captured) == 0 {
+		return nil, money.Money{}, status.Errorf(codes.FailedPrecondition, "order %d has no captured payment to refund", r.OrderID)
+	}
+	if left.Cmp(due) < 0 {
+		return nil, money.Money{}, status.Errorf(codes.FailedPrecondition, "order %d has less than %s left to refund", r.OrderID, due)
+	}
+
+	return captured, due, nil
+}
+
+// refundReturn refunds due of the amount of r from captured, in order, once
+// the refunds an earlier attempt recorded for r are carried out.
+// toStoreCredit refunds what the provider took into the customer's store
+// credit.
+func (s *Server) refundReturn(ctx context.Context, r *storer.Return, captured []*storer.Payment, due money.Money, toStoreCredit bool) error {
+	if err := s.applyPendingRefunds(ctx, r.OrderID, returnRefundKey(r.ID)); err != nil {
+		return err
+	}
+
+	for _, p := range captured {
+		amount := minMoney(p.Amount.Sub(p.RefundedAmount), due)
+		if amount.Amount <= 0 {
+			continue
+		}
+		if err := s.refundPaymentAmount(ctx, p, r.UserID, returnRefundKey(r.ID), amount, toStoreCredit); err != nil {
+			return err
+		}
+		due = due.Sub(amount)
+	}
+
+	return nil
+}
+
+// returnCreditNote builds the credit note for the returned units of r on the
+// invoice of order, or returns nil if the order was never invoiced.
+func (s *Server) returnCreditNote(ctx context.Context, order *storer.Order, r *storer.Return) (*storer.CreditNote, error) {
+	inv, err := s.storer.GetOrderInvoice(ctx, order.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	item, ok := orderItem(order, r.OrderItemID)
+	if !ok {
+		return nil, fmt.Errorf("error crediting return %d: order %d has no item %d", r.ID, order.ID, r.OrderItemID)
+	}
+	credit := returnCredit(item, r.Quantity)
+
+	return &storer.CreditNote{
+		InvoiceID: inv.ID,
+		Reason:    fmt.Sprintf("return %d: %s", r.ID, r.Reason),
+		Items: storer.InvoiceLines{{
+			Description:     item.Name,
+			SKU:             item.SKU,
+			Quantity:        r.Quantity,
+			UnitPrice:       item.Price,
+			TaxRate:         item.TaxRate,
+			TaxJurisdiction: item.TaxJurisdiction,
+			TaxPrice:        credit.Tax,
+			TaxInclusive:    item.TaxInclusive,
+			Total:           credit.Items,
+		}},
+		ItemsPrice:    credit.Items,
+		DiscountPrice: credit.Discount,
+		ShippingPrice: money.New(0, credit.Total.Currency),
+		TaxPrice:      credit.Tax,
+		TotalPrice:    credit.Total,
+		IssuedAt:      time.Now(),
+	}, nil
+}
+
+// lineCredit is what returning some units of an order line gives back, split
+// like an order's prices.
+type lineCredit struct {
+	Items    money.Money
+	Discount money.Money
+	Tax      money.Money
+	Total    money.Money
+}
+
+// returnCredit works out what returning qty units of item gives back: their
+// price, less their share of the discount taken off the line, plus their
+// share of the line's tax unless it was included in the price. Shipping, and
+// so a waiver of it, is not given back.
+func returnCredit(item storer.OrderItem, qty int64) lineCredit {
+	c := lineCredit{
+		Items:    item.Price.Mul(qty),
+		Discount: item.DiscountPrice.MulFrac(qty, item.Quantity),
+		Tax:      item.TaxPrice.MulFrac(qty, item.Quantity),
+	}
+	c.Total = c.Items.Sub(c.Discount)
+	if !item.TaxInclusive {
+		c.Total = c.Total.Add(c.Tax)
+	}
+
+	return c
+}
+
+func orderItem(o *storer.Order, id int64) (storer.OrderItem, bool) {
+	for _, oi := range o.Items {
+		if oi.ID == id {
+			return oi, true
+		}
+	}
+
+	return storer.OrderItem{}, false
+}
+
+func (s *Server) getReturn(ctx context.Context, id int64) (*storer.Return, error) {
+	r, err := s.storer.GetReturn(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "return %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (s *Server) getReturnRes(ctx context.Context, id int64) (*pb.ReturnRes, error) {
+	r, err := s.getReturn(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBReturnRes(r), nil
+}
+
+// checkReturn validates a return request, returning its trimmed reason and
+// photos URL. Photos are optional; when given, they must be at an http(s)
+// URL.
+func checkReturn(req *pb.ReturnReq) (string, string, error) {
+	reason := strings.TrimSpace(req.GetReason())
+	photosURL := strings.TrimSpace(req.GetPhotosUrl())
+	switch {
+	case reason == "":
+		return "", "", status.Error(codes.InvalidArgument, "return reason is empty")
+	case utf8.RuneCountInString(reason) > maxReturnReason:
+		return "", "", status.Errorf(codes.InvalidArgument, "return reason is longer than %d characters", maxReturnReason)
+	case len(photosURL) > maxPhotosURL:
+		return "", "", status.Errorf(codes.InvalidArgument, "photos URL is longer than %d characters", maxPhotosURL)
+	}
+
+	if photosURL != "" {
+		u, err := url.Parse(photosURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "", "", status.Errorf(codes.InvalidArgument, "invalid photos URL %q", photosURL)
+		}
+	}
+
+	return reason, photosURL, nil
+}
+
+func isReturnStatus(st storer.ReturnStatus) bool {
+	switch st {
+	case storer.ReturnRequested, storer.ReturnApproved, storer.ReturnRejected, storer.ReturnReceived, storer.ReturnRefunded:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/storer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Constants
+const (
+	minReviewRating = 1
+	maxReviewRating = 5
+	maxReviewTitle  = 255
+)
+
+// CreateReview posts the user's review of a product. Only customers with a
+// delivered order containing the product can review it, once.
+func (s *Server) CreateReview(ctx context.Context, req *pb.ReviewReq) (*pb.ReviewRes, error) {
+	if err := checkReview(req); err != nil {
+		return nil, err
+	}
+
+	delivered, err := s.storer.HasDeliveredOrder(ctx, req.GetUserId(), req.GetProductId())
+	if err != nil {
+		return nil, err
+	}
+	if !delivered {
+		return nil, status.Errorf(codes.PermissionDenied, "product %d can only be reviewed after an order of it was delivered", req.GetProductId())
+	}
+
+	r, err := s.storer.CreateReview(ctx, &storer.Review{
+		ProductID: req.GetProductId(),
+		UserID:    req.GetUserId(),
+		Rating:    req.GetRating(),
+		Title:     strings.TrimSpace(req.GetTitle()),
+		Body:      req.GetBody(),
+	})
+	if err != nil {
+		if errors.Is(err, storer.ErrReviewExists) {
+			return nil, status.Errorf(codes.AlreadyExists, "product %d is already reviewed, edit the existing review instead", req.GetProductId())
+		}
+		return nil, err
+	}
+
+	return s.getReviewRes(ctx, r.ID)
+}
+
+func (s *Server) ListProductReviews(ctx context.Context, req *pb.ListReviewsReq) (*pb.ListReviewsRes, error) {
+	page, err := s.storer.ListProductReviews(ctx, &storer.ListReviewsParams{
+		ProductID:     req.GetProductId(),
+		Cursor:        req.GetCursor(),
+		Limit:         int(req.GetLimit()),
+		IncludeHidden: req.GetIncludeHidden(),
+	})
+	if err != nil {
+		if errors.Is(err, storer.ErrInvalidCursor) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, err
+	}
+
+	res := &pb.ListReviewsRes{
+		Reviews:    make([]*pb.ReviewRes, 0, len(page.Reviews)),
+		NextCursor: page.NextCursor,
+	}
+	for _, r := range page.Reviews {
+		res.Reviews = append(res.Reviews, toPBReviewRes(r))
+	}
+
+	return res, nil
+}
+
+// UpdateReview edits the rating, title and body of the user's own review.
+func (s *Server) UpdateReview(ctx context.Context, req *pb.ReviewReq) (*pb.ReviewRes, error) {
+	if err := checkReview(req); err != nil {
+		return nil, err
+	}
+
+	r, err := s.getOwnReview(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Rating = req.GetRating()
+	r.Title = strings.TrimSpace(req.GetTitle())
+	r.Body = req.GetBody()
+	r.UpdatedAt = toTimePtr(time.Now())
+	if _, err := s.storer.UpdateReview(ctx, r); err != nil {
+		return nil, err
+	}
+
+	return s.getReviewRes(ctx, r.ID)
+}
+
+// DeleteReview removes a review. Customers can delete their own reviews and
+// admins any review.
+func (s *Server) DeleteReview(ctx context.Context, req *pb.ReviewReq) (*pb.ReviewRes, error) {
+	r, err := s.getOwnReview(ctx, req, req.GetIsAdmin())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storer.DeleteReview(ctx, r.ID); err != nil {
+		return nil, err
+	}
+
+	return &pb.ReviewRes{}, nil
+}
+
+// ModerateReview hides an abusive review from customers and from the
+// product's rating, or shows it again. Only admins can moderate.
+func (s *Server) ModerateReview(ctx context.Context, req *pb.ReviewReq) (*pb.ReviewRes, error) {
+	if !req.GetIsAdmin() {
+		return nil, status.Error(codes.PermissionDenied, "only admins can moderate reviews")
+	}
+
+	err := s.storer.SetReviewHidden(ctx, req.GetId(), req.GetHidden())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "review %d not found", req.GetId())
+		}
+		return nil, err
+	}
+
+	return s.getReviewRes(ctx, req.GetId())
+}
+
+// getOwnReview loads the review in req, which must belong to the user unless
+// anyUser is set.
+func (s *Server) getOwnReview(ctx context.Context, req *pb.ReviewReq, anyUser bool) (*storer.Review, error) {
+	r, err := s.storer.GetReview(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "review %d not found", req.GetId())
+		}
+		return nil, err
+	}
+	if !anyUser && r.UserID != req.GetUserId() {
+		return nil, status.Errorf(codes.PermissionDenied, "review %d belongs to another user", req.GetId())
+	}
+
+	return r, nil
+}
+
+func (s *Server) getReviewRes(ctx context.Context, id int64) (*pb.ReviewRes, error) {
+	r, err := s.storer.GetReview(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBReviewRes(r), nil
+}
+
+func checkReview(req *pb.ReviewReq) error {
+	switch title := strings.TrimSpace(req.GetTitle()); {
+	case req.GetRating() < minReviewRating || req.GetRating() > maxReviewRating:
+		return status.Errorf(codes.InvalidArgument, "rating must be between %d and %d stars", minReviewRating, maxReviewRating)
+	case title == "":
+		return status.Error(codes.InvalidArgument, "review title is empty")
+	case utf8.RuneCountInString(title) > maxReviewTitle:
+		return status.Errorf(codes.InvalidArgument, "review title is longer than %d characters", maxReviewTitle)
+	}
+
+	return nil
+}
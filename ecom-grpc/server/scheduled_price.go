@@ -0,0 +1,214 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/storer"
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreateScheduledPrice schedules a price for a product, or for one of its
+// variants, to sell at for a while.
+func (s *Server) CreateScheduledPrice(ctx context.Context, req *pb.ScheduledPriceReq) (*pb.ScheduledPriceRes, error) {
+	sp, err := s.toStorerScheduledPrice(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.storer.CreateScheduledPrice(ctx, sp)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getScheduledPriceRes(ctx, created.ProductID, created.ID)
+}
+
+// ListScheduledPrices returns the scheduled prices of a product, past and
+// future ones included, by start.
+func (s *Server) ListScheduledPrices(ctx context.Context, req *pb.ScheduledPriceReq) (*pb.ListScheduledPricesRes, error) {
+	if _, err := s.getProduct(ctx, req.GetProductId()); err != nil {
+		return nil, err
+	}
+
+	prices, err := s.storer.ListScheduledPrices(ctx, req.GetProductId())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.ListScheduledPricesRes{
+		Prices: make([]*pb.ScheduledPriceRes, 0, len(prices)),
+	}
+	for _, sp := range prices {
+		res.Prices = append(res.Prices, toPBScheduledPriceRes(sp))
+	}
+
+	return res, nil
+}
+
+// UpdateScheduledPrice replaces a scheduled price with req. The units sold at
+// it so far still count towards a new cap; lowering the cap below them ends
+// the sale. Order lines sold at it keep their price.
+func (s *Server) UpdateScheduledPrice(ctx context.Context, req *pb.ScheduledPriceReq) (*pb.ScheduledPriceRes, error) {
+	existing, err := s.getScheduledPrice(ctx, req.GetProductId(), req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	sp, err := s.toStorerScheduledPrice(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	sp.ID = existing.ID
+	sp.CreatedAt = existing.CreatedAt
+	sp.UpdatedAt = toTimePtr(time.Now())
+	if _, err := s.storer.UpdateScheduledPrice(ctx, sp); err != nil {
+		return nil, err
+	}
+
+	return s.getScheduledPriceRes(ctx, sp.ProductID, sp.ID)
+}
+
+// DeleteScheduledPrice deletes a scheduled price. Order lines sold at it keep
+// their price.
+func (s *Server) DeleteScheduledPrice(ctx context.Context, req *pb.ScheduledPriceReq) (*pb.ScheduledPriceRes, error) {
+	if _, err := s.getScheduledPrice(ctx, req.GetProductId(), req.GetId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.storer.DeleteScheduledPrice(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	return &pb.ScheduledPriceRes{}, nil
+}
+
+// getScheduledPrice loads a scheduled price, which must belong to productID.
+func (s *Server) getScheduledPrice(ctx context.Context, productID, id int64) (*storer.ScheduledPrice, error) {
+	sp, err := s.storer.GetScheduledPrice(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "scheduled price %d not found", id)
+		}
+		return nil, err
+	}
+	if sp.ProductID != productID {
+		return nil, status.Errorf(codes.NotFound, "scheduled price %d of product %d not found", id, productID)
+	}
+
+	return sp, nil
+}
+
+func (s *Server) getScheduledPriceRes(ctx context.Context, productID, id int64) (*pb.ScheduledPriceRes, error) {
+	sp, err := s.getScheduledPrice(ctx, productID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBScheduledPriceRes(sp), nil
+}
+
+// toStorerScheduledPrice validates req and maps it to a scheduled price of
+// an existing product and, if it names one, a variant of it.
+func (s *Server) toStorerScheduledPrice(ctx context.Context, req *pb.ScheduledPriceReq) (*storer.ScheduledPrice, error) {
+	if err := checkCurrency(req.GetPrice()); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case req.GetPrice() == nil:
+		return nil, status.Error(codes.InvalidArgument, "price is required")
+	case req.GetPrice().GetAmount() < 0:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid price %s", toMoney(req.GetPrice()))
+	case req.GetStartsAt() == nil || req.GetEndsAt() == nil:
+		return nil, status.Error(codes.InvalidArgument, "starts_at and ends_at are required")
+	case !req.GetEndsAt().AsTime().After(req.GetStartsAt().AsTime()):
+		return nil, status.Error(codes.InvalidArgument, "ends_at must be after starts_at")
+	case req.QuantityCap != nil && req.GetQuantityCap() <= 0:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid quantity_cap %d", req.GetQuantityCap())
+	}
+
+	p, err := s.getProduct(ctx, req.GetProductId())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetVariantId() != 0 {
+		if _, err := s.getVariant(ctx, p.ID, req.GetVariantId()); err != nil {
+			return nil, err
+		}
+	}
+
+	sp := &storer.ScheduledPrice{
+		ProductID: p.ID,
+		VariantID: toIDPtr(req.GetVariantId()),
+		Price:     toMoney(req.GetPrice()),
+		StartsAt:  req.GetStartsAt().AsTime(),
+		EndsAt:    req.GetEndsAt().AsTime(),
+	}
+	if req.QuantityCap != nil {
+		limit := req.GetQuantityCap()
+		sp.QuantityCap = &limit
+	}
+
+	return sp, nil
+}
+
+// scheduledPrices are the scheduled prices running for some products, keyed
+// by product id.
+type scheduledPrices map[int64][]*storer.ScheduledPrice
+
+// runningPrices loads the scheduled prices running now for the given
+// products.
+func (s *Server) runningPrices(ctx context.Context, productIDs []int64) (scheduledPrices, error) {
+	prices, err := s.storer.ListRunningScheduledPrices(ctx, productIDs, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	byProduct := make(scheduledPrices, len(productIDs))
+	for _, sp := range prices {
+		byProduct[sp.ProductID] = append(byProduct[sp.ProductID], sp)
+	}
+
+	return byProduct, nil
+}
+
+// price returns what a product, or a variant of it, sells at now and the
+// scheduled price that is, nil when it sells at its own price. The lowest
+// running price applies; prices scheduled for the product also apply to
+// variants that do not override its price.
+func (sps scheduledPrices) price(p *storer.Product, v *storer.Variant) (money.Money, *storer.ScheduledPrice) {
+	var best *storer.ScheduledPrice
+	for _, sp := range sps[p.ID] {
+		if sp.VariantID != nil {
+			if v == nil || *sp.VariantID != v.ID {
+				continue
+			}
+		} else if v != nil && v.Price != nil {
+			continue
+		}
+		if best == nil || sp.Price.Cmp(best.Price) < 0 {
+			best = sp
+		}
+	}
+
+	if best == nil {
+		return variantPrice(p, v), nil
+	}
+	return best.Price, best
+}
+
+// compareAtPrice returns the own price of a product, or a variant of it,
+// while it sells below that, nil otherwise.
+func (sps scheduledPrices) compareAtPrice(p *storer.Product, v *storer.Variant) *pb.Money {
+	price, _ := sps.price(p, v)
+	if own := variantPrice(p, v); price.Cmp(own) < 0 {
+		return toPBMoney(own)
+	}
+	return nil
+}
@@ -2,33 +2,77 @@ package server
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
 	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/storer"
+	"github.com/OrkhanMehbaliyev/ecom-golang/payments"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// Constants
+const (
+	// how long a relay may hold claimed notification events by default
+	defaultNotificationLease = 5 * time.Minute
+)
+
 type Server struct {
-	storer *storer.MySQLStorer
+	storer   storer.Storer
+	payments payments.Provider
+	// seller is printed on every invoice issued
+	seller  storer.Seller
+	loyalty LoyaltyProgram
 	pb.UnimplementedEcomServer
 }
 
-func NewServer(storer *storer.MySQLStorer) *Server {
+func NewServer(storer storer.Storer, provider payments.Provider, seller storer.Seller, loyalty LoyaltyProgram) *Server {
 	return &Server{
-		storer: storer,
+		storer:   storer,
+		payments: provider,
+		seller:   seller,
+		loyalty:  loyalty,
 	}
 }
 
 func (s *Server) CreateProduct(ctx context.Context, req *pb.ProductReq) (*pb.ProductRes, error) {
-	pr, err := s.storer.CreateProduct(ctx, toStorerProduct(req))
+	if err := checkCurrency(req.GetPrice()); err != nil {
+		return nil, err
+	}
+	if req.GetWeight() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid weight %d", req.GetWeight())
+	}
+	if req.GetCountInStock() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid count_in_stock %d", req.GetCountInStock())
+	}
+
+	options, err := checkProductOptions(req.GetOptions())
+	if err != nil {
+		return nil, err
+	}
+	taxClass, err := checkTaxClass(req.GetTaxClass())
+	if err != nil {
+		return nil, err
+	}
+	product := toStorerProduct(req)
+	product.Options = options
+	product.TaxClass = taxClass
+	if err := s.checkProductCategory(ctx, product.CategoryID); err != nil {
+		return nil, err
+	}
+
+	pr, err := s.storer.CreateProduct(ctx, product)
 	if err != nil {
 		return nil, err
 	}
 
-	return toPBProductRes(pr), nil
+	return s.toPBProduct(ctx, pr)
 }
 
 func (s *Server) GetProduct(ctx context.Context, p *pb.ProductReq) (*pb.ProductRes, error) {
@@ -37,38 +81,128 @@ func (s *Server) GetProduct(ctx context.Context, p *pb.ProductReq) (*pb.ProductR
 		return nil, err
 	}
 
-	return toPBProductRes(pr), nil
+	return s.toPBProduct(ctx, pr)
 }
 
-func (s *Server) ListProducts(ctx context.Context, p *pb.ProductReq) (*pb.ListProductRes, error) {
-	lps, err := s.storer.ListProducts(ctx)
+func (s *Server) ListProducts(ctx context.Context, p *pb.ListProductsReq) (*pb.ListProductRes, error) {
+	if err := checkCurrency(p.GetMinPrice(), p.GetMaxPrice()); err != nil {
+		return nil, err
+	}
+
+	params := toStorerListProductsParams(p)
+	if p.GetCategorySlug() != "" {
+		ids, err := s.categoryIDsBySlug(ctx, p.GetCategorySlug())
+		if err != nil {
+			return nil, err
+		}
+		params.CategoryIDs = ids
+	}
+
+	page, err := s.storer.ListProducts(ctx, params)
 	if err != nil {
+		if errors.Is(err, storer.ErrInvalidCursor) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 		return nil, err
 	}
 
-	lpr := make([]*pb.ProductRes, 0, len(lps))
-	for _, lp := range lps {
-		lpr = append(lpr, toPBProductRes(lp))
+	lpr, err := s.toPBProducts(ctx, page.Products)
+	if err != nil {
+		return nil, err
 	}
 
 	return &pb.ListProductRes{
-		Products: lpr,
+		Products:   lpr,
+		NextCursor: page.NextCursor,
 	}, nil
 }
 
+func (s *Server) SearchProducts(ctx context.Context, req *pb.SearchProductsReq) (*pb.SearchProductsRes, error) {
+	if strings.TrimSpace(req.GetQuery()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "search query is empty")
+	}
+
+	hits, err := s.storer.SearchProducts(ctx, &storer.SearchProductsParams{
+		Query: req.GetQuery(),
+		Limit: int(req.GetLimit()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	products := make([]*storer.Product, 0, len(hits))
+	for _, hit := range hits {
+		products = append(products, hit.Product)
+	}
+	prs, err := s.toPBProducts(ctx, products)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*pb.ProductSearchHit, 0, len(hits))
+	for i, hit := range hits {
+		res = append(res, &pb.ProductSearchHit{
+			Product:    prs[i],
+			Score:      hit.Score,
+			Highlights: hit.Highlights,
+		})
+	}
+
+	return &pb.SearchProductsRes{
+		Hits: res,
+	}, nil
+}
+
+// UpdateProduct patches a product. The stock of a product with variants is
+// the sum of its variants' and its options cannot change while it has any.
 func (s *Server) UpdateProduct(ctx context.Context, p *pb.ProductReq) (*pb.ProductRes, error) {
+	if err := checkCurrency(p.GetPrice()); err != nil {
+		return nil, err
+	}
+	if p.GetWeight() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid weight %d", p.GetWeight())
+	}
+	if p.GetCountInStock() != 0 {
+		return nil, status.Error(codes.InvalidArgument, "count_in_stock is kept by the inventory ledger, adjust the stock instead")
+	}
+	options, err := checkProductOptions(p.GetOptions())
+	if err != nil {
+		return nil, err
+	}
+	taxClass, err := checkTaxClass(p.GetTaxClass())
+	if err != nil {
+		return nil, err
+	}
+
 	product, err := s.storer.GetProduct(ctx, p.GetId())
 	if err != nil {
 		return nil, err
 	}
 
+	variants, err := s.storer.ListProductVariants(ctx, []int64{product.ID})
+	if err != nil {
+		return nil, err
+	}
+	if len(variants) > 0 && len(options) > 0 && !slices.Equal(options, product.Options) {
+		return nil, status.Errorf(codes.FailedPrecondition, "options of product %d cannot change while it has variants", product.ID)
+	}
+
 	patchProductReq(product, p)
+	if len(options) > 0 {
+		product.Options = options
+	}
+	if p.GetTaxClass() != "" {
+		product.TaxClass = taxClass
+	}
+	if err := s.checkProductCategory(ctx, product.CategoryID); err != nil {
+		return nil, err
+	}
 	pr, err := s.storer.UpdateProduct(ctx, product)
 	if err != nil {
 		return nil, err
 	}
 
-	return toPBProductRes(pr), nil
+	return s.toPBProduct(ctx, pr)
 }
 
 func (s *Server) DeleteProduct(ctx context.Context, p *pb.ProductReq) (*pb.ProductRes, error) {
@@ -81,31 +215,91 @@ func (s *Server) DeleteProduct(ctx context.Context, p *pb.ProductReq) (*pb.Produ
 }
 
 func (s *Server) CreateOrder(ctx context.Context, o *pb.OrderReq) (*pb.OrderRes, error) {
-	order, err := s.storer.CreateOrder(ctx, toStorerOrder(o))
+	if len(o.GetItems()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "order has no items")
+	}
+	for _, oi := range o.GetItems() {
+		if oi.GetQuantity() <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid quantity %d for product %d", oi.GetQuantity(), oi.GetProductId())
+		}
+		if oi.GetQuantity() > maxLineQuantity {
+			return nil, status.Errorf(codes.InvalidArgument, "quantity of product %d is more than %d", oi.GetProductId(), maxLineQuantity)
+		}
+		if err := checkCurrency(oi.GetPrice()); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkCurrency(o.GetTaxPrice(), o.GetShippingPrice(), o.GetDiscountPrice(), o.GetTotalPrice(), o.GetStoreCredit()); err != nil {
+		return nil, err
+	}
+
+	quote, err := s.quoteOrder(ctx, o)
 	if err != nil {
 		return nil, err
 	}
-	order.Status = storer.Pending
+	if err := checkClientTotals(o, quote); err != nil {
+		return nil, err
+	}
 
-	_, err = s.storer.EnqueueNotificatioEvent(ctx, &storer.NotificationEvent{
-		UserEmail:   o.GetUserEmail(),
-		OrderStatus: order.Status,
-		OrderID:     order.ID,
-		Attempts:    0,
+	tenders, err := s.checkoutTenders(ctx, o.GetUserId(), o.GetGiftCardCodes(), toMoney(o.GetStoreCredit()), o.GetLoyaltyPoints(), quote.TotalPrice)
+	if err != nil {
+		return nil, err
+	}
+	so := toStorerOrder(o, quote)
+	so.Tenders = tenders
+
+	order, err := s.storer.CreateOrder(ctx, so, &storer.NotificationEvent{
+		UserEmail: o.GetUserEmail(),
 	})
 	if err != nil {
+		var stockErr *storer.InsufficientStockError
+		if errors.As(err, &stockErr) {
+			return nil, status.Error(codes.FailedPrecondition, stockErr.Error())
+		}
+		if errors.Is(err, storer.ErrPromotionLimitReached) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		var soldOutErr *storer.ScheduledPriceSoldOutError
+		if errors.As(err, &soldOutErr) {
+			return nil, status.Error(codes.FailedPrecondition, soldOutErr.Error())
+		}
+		if errors.Is(err, storer.ErrInsufficientBalance) {
+			return nil, status.Error(codes.FailedPrecondition, "a gift card, the store credit or loyalty points were spent in the meantime, try again")
+		}
 		return nil, err
 	}
+	order.Status = storer.Pending
 
-	return toPBOrderRes(order), nil
+	// an order gift cards, store credit and points pay in full needs no
+	// payment
+	due := amountDue(order.TotalPrice, tenders)
+	if len(tenders) > 0 && due.IsZero() {
+		if err := s.markOrderPaid(ctx, order, tenders[len(tenders)-1]); err != nil {
+			return nil, err
+		}
+	}
+
+	res := toPBOrderRes(order)
+	res.AmountDue = toPBMoney(due)
+
+	return res, nil
 }
 
+// GetOrder returns an order with its items. Customers can only read their
+// own orders.
 func (s *Server) GetOrder(ctx context.Context, o *pb.OrderReq) (*pb.OrderRes, error) {
-	order, err := s.storer.GetOrder(ctx, o.GetUserId())
+	order, err := s.storer.GetOrder(ctx, o.GetId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "order %d not found", o.GetId())
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	if !o.GetIsAdmin() && o.GetUserId() != order.UserID {
+		return nil, status.Errorf(codes.PermissionDenied, "order %d does not belong to user %d", order.ID, o.GetUserId())
+	}
+
 	return toPBOrderRes(order), nil
 }
 
@@ -125,44 +319,165 @@ func (s *Server) ListOrders(ctx context.Context, o *pb.OrderReq) (*pb.ListOrderR
 	}, nil
 }
 
+// ListUserOrders returns one page of a user's orders, newest first.
+func (s *Server) ListUserOrders(ctx context.Context, req *pb.ListUserOrdersReq) (*pb.ListOrderRes, error) {
+	page, err := s.storer.ListUserOrders(ctx, toStorerListOrdersParams(req))
+	if err != nil {
+		if errors.Is(err, storer.ErrInvalidCursor) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, err
+	}
+
+	lor := make([]*pb.OrderRes, 0, len(page.Orders))
+	for _, order := range page.Orders {
+		lor = append(lor, toPBOrderRes(order))
+	}
+
+	return &pb.ListOrderRes{
+		Orders:     lor,
+		NextCursor: page.NextCursor,
+	}, nil
+}
+
+// UpdateOrderStatus moves an order along its lifecycle. Customers may cancel
+// their own orders before they ship; admins may make any allowed transition
+// except to paid, which only capturing a payment does. Payments are voided or
+// refunded along with the order, into the customer's store credit if asked
+// to, and the invoice of a refunded order is credited. Gift cards bought in
+// a refunded order are emptied. Delivered orders earn loyalty points, which
+// refunding the order takes back. The customer is notified of every change.
 func (s *Server) UpdateOrderStatus(ctx context.Context, o *pb.OrderReq) (*pb.OrderRes, error) {
-	order, err := s.storer.GetOrderStatusByID(ctx, o.GetId())
+	order, err := s.getOwnOrderStatus(ctx, o)
 	if err != nil {
 		return nil, err
 	}
 
-	if o.GetUserId() != order.UserID {
-		return nil, fmt.Errorf("order %d does not belong to user %d", order.ID, o.GetUserId())
+	from := order.Status
+	to := storer.OrderStatus(strings.ToLower(o.GetStatus().String()))
+	if err := checkTransition(from, to, o.GetIsAdmin()); err != nil {
+		return nil, err
+	}
+
+	if from != to {
+		if err := s.changeOrderStatus(ctx, order, to, o); err != nil {
+			return nil, err
+		}
 	}
 
-	strOrderStatus := storer.OrderStatus(strings.ToLower(o.GetStatus().String()))
-	if strOrderStatus == order.Status {
-		return nil, fmt.Errorf("order status is already %s", order.Status)
+	// payments are given back once the order is cancelled or refunded.
+	// Refunds are keyed by the order, so releasing them again, from a
+	// concurrent request or a retry of a failed one, only gives back what
+	// was not given back yet
+	if err := s.releaseOrderPayments(ctx, order, o.GetRefundToStoreCredit()); err != nil {
+		return nil, err
 	}
 
-	order.Status = strOrderStatus
+	return toPBOrderRes(order), nil
+}
+
+// changeOrderStatus moves order to status to as requested by o, failing
+// with Aborted if its status changed since it was read.
+func (s *Server) changeOrderStatus(ctx context.Context, order *storer.Order, to storer.OrderStatus, o *pb.OrderReq) error {
+	from := order.Status
+	order.Status = to
 	order.UpdatedAt = toTimePtr(time.Now())
 
-	uo, err := s.storer.UpdateOrderStatus(ctx, order)
+	customer, err := s.storer.GetUserByID(ctx, order.UserID)
+	if err != nil {
+		return err
+	}
+
+	change := &storer.OrderStatusChange{
+		FromStatus: from,
+		Reason:     o.GetReason(),
+		ActorID:    o.GetUserId(),
+		ActorRole:  actorRole(o.GetIsAdmin()),
+	}
+	switch to {
+	case storer.Delivered:
+		change.Loyalty, err = s.loyaltyEarning(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+	case storer.Cancelled, storer.Refunded:
+		change.CreditNote, err = s.creditNote(ctx, order.ID, o.GetReason())
+		if err != nil {
+			return err
+		}
+		change.CancelGiftCards = true
+		reason := fmt.Sprintf("order %d %s", order.ID, to)
+		change.Loyalty, err = s.loyaltyReversal(ctx, order.UserID, order.ID, nil, reason)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = s.storer.UpdateOrderStatus(ctx, order, change, &storer.NotificationEvent{
+		UserEmail: customer.Email,
+	})
+	if errors.Is(err, storer.ErrOrderStatusChanged) {
+		return status.Errorf(codes.Aborted, "order %d was updated concurrently, try again", order.ID)
+	}
+
+	return err
+}
+
+// ListOrderStatusChanges returns the status history of an order, oldest first.
+func (s *Server) ListOrderStatusChanges(ctx context.Context, o *pb.OrderReq) (*pb.ListOrderStatusChangesRes, error) {
+	order, err := s.getOwnOrderStatus(ctx, o)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = s.storer.EnqueueNotificatioEvent(ctx, &storer.NotificationEvent{
-		UserEmail:   o.GetUserEmail(),
-		OrderStatus: order.Status,
-		OrderID:     order.ID,
-		Attempts:    0,
-	})
+	changes, err := s.storer.ListOrderStatusChanges(ctx, order.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	return toPBOrderRes(uo), nil
+	res := make([]*pb.OrderStatusChange, 0, len(changes))
+	for _, c := range changes {
+		res = append(res, toPBOrderStatusChange(c))
+	}
+
+	return &pb.ListOrderStatusChangesRes{
+		Changes: res,
+	}, nil
 }
 
+// getOwnOrderStatus loads the order o refers to, making sure it belongs to the
+// requesting user unless that user is an admin.
+func (s *Server) getOwnOrderStatus(ctx context.Context, o *pb.OrderReq) (*storer.Order, error) {
+	order, err := s.storer.GetOrderStatusByID(ctx, o.GetId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "order %d not found", o.GetId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !o.GetIsAdmin() && o.GetUserId() != order.UserID {
+		return nil, status.Errorf(codes.PermissionDenied, "order %d does not belong to user %d", order.ID, o.GetUserId())
+	}
+
+	return order, nil
+}
+
+// DeleteOrder deletes an order that was never paid, invoiced or returned.
+// Customers may only delete their own orders while they are pending.
 func (s *Server) DeleteOrder(ctx context.Context, o *pb.OrderReq) (*pb.OrderRes, error) {
-	err := s.storer.DeleteOrder(ctx, o.GetId())
+	order, err := s.getOwnOrderStatus(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	if !o.GetIsAdmin() && order.Status != storer.Pending {
+		return nil, status.Errorf(codes.PermissionDenied, "only admins can delete %s orders", order.Status)
+	}
+
+	err = s.storer.DeleteOrder(ctx, order.ID)
+	if errors.Is(err, storer.ErrOrderHasRecords) {
+		return nil, status.Errorf(codes.FailedPrecondition, "order %d has payments, an invoice or returns to keep, cancel it instead", order.ID)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -288,20 +603,26 @@ func (s *Server) ListNotificationEvents(ctx context.Context, lnr *pb.ListNotific
 		return nil, err
 	}
 
-	lners := make([]*pb.NotificationEvent, 0, len(notificationEvents))
-	for _, ne := range notificationEvents {
-		lners = append(lners, &pb.NotificationEvent{
-			Id:          ne.ID,
-			UserEmail:   ne.UserEmail,
-			OrderStatus: toPBOrderStatus(ne.OrderStatus),
-			OrderId:     ne.OrderID,
-			StateId:     ne.StateID,
-			Attempts:    ne.Attempts,
-		})
+	return &pb.ListNotificationEventsRes{
+		Events: toPBNotificationEvents(notificationEvents),
+	}, nil
+}
+
+// ClaimNotificationEvents leases due events to a notification relay. Events
+// not reported back before the lease ends are handed out again.
+func (s *Server) ClaimNotificationEvents(ctx context.Context, req *pb.ClaimNotificationEventsReq) (*pb.ListNotificationEventsRes, error) {
+	lease := time.Duration(req.GetLeaseSeconds()) * time.Second
+	if lease <= 0 {
+		lease = defaultNotificationLease
+	}
+
+	notificationEvents, err := s.storer.ClaimNotificationEvents(ctx, int(req.GetLimit()), lease)
+	if err != nil {
+		return nil, err
 	}
 
 	return &pb.ListNotificationEventsRes{
-		Events: lners,
+		Events: toPBNotificationEvents(notificationEvents),
 	}, nil
 }
 
@@ -0,0 +1,2549 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/storer"
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"github.com/OrkhanMehbaliyev/ecom-golang/payments"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// usd returns an amount of cents in the default currency.
+func usd(cents int64) *pb.Money {
+	return &pb.Money{Amount: cents, Currency: money.DefaultCurrency}
+}
+
+// newTestServer returns a server backed by an in-memory storer holding one
+// customer and two products, taking payments through the fake provider.
+func newTestServer(t *testing.T) (*Server, *storer.MemoryStorer) {
+	ctx := context.Background()
+	st := storer.NewMemoryStorer()
+
+	_, err := st.CreateUser(ctx, &storer.User{Name: "test", Email: "test@example.com"})
+	require.NoError(t, err)
+
+	for _, p := range []*storer.Product{
+		{Name: "test product", Image: "test.jpg", Price: money.New(1999, money.DefaultCurrency), CountInStock: 10},
+		{Name: "test product 2", Image: "test2.jpg", Price: money.New(4550, money.DefaultCurrency), CountInStock: 1},
+	} {
+		_, err := st.CreateProduct(ctx, p)
+		require.NoError(t, err)
+	}
+
+	seller := storer.Seller{Name: "test shop", Address: storer.StringList{"1 Market St", "Springfield"}, TaxID: "US123"}
+	return NewServer(st, payments.NewFakeProvider(0, nil), seller, DefaultLoyaltyProgram()), st
+}
+
+func TestCreateOrderPricing(t *testing.T) {
+	tcs := []struct {
+		name string
+		req  *pb.OrderReq
+		code codes.Code
+		test func(*testing.T, *pb.OrderRes)
+	}{
+		{
+			name: "mismatching item price",
+			req: &pb.OrderReq{
+				UserId: 1,
+				Items: []*pb.OrderItem{
+					{ProductId: 1, Quantity: 2, Price: usd(1), Name: "ignored"},
+				},
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "prices from catalog",
+			req: &pb.OrderReq{
+				UserId: 1,
+				Items: []*pb.OrderItem{
+					{ProductId: 1, Quantity: 2, Name: "ignored"},
+					{ProductId: 2, Quantity: 1},
+				},
+			},
+			test: func(t *testing.T, res *pb.OrderRes) {
+				require.Equal(t, "test product", res.Items[0].Name)
+				require.Equal(t, int64(1999), res.Items[0].Price.GetAmount())
+				require.Equal(t, money.DefaultCurrency, res.Items[0].Price.GetCurrency())
+				require.Equal(t, int64(3998), res.Items[0].LineTotal.GetAmount())
+				require.Equal(t, int64(4550), res.Items[1].LineTotal.GetAmount())
+				require.Equal(t, int64(8548), res.ItemsPrice.GetAmount())
+				// 10% of 85.48 is 8.548, rounded half up to the cent
+				require.Equal(t, int64(855), res.TaxPrice.GetAmount())
+				require.Equal(t, flatShippingPrice.Amount, res.ShippingPrice.GetAmount())
+				require.Equal(t, int64(10403), res.TotalPrice.GetAmount())
+			},
+		},
+		{
+			name: "matching client totals",
+			req: &pb.OrderReq{
+				UserId:        1,
+				Items:         []*pb.OrderItem{{ProductId: 1, Quantity: 1, Price: usd(1999)}},
+				TaxPrice:      usd(200),
+				ShippingPrice: usd(1000),
+				TotalPrice:    usd(3199),
+			},
+		},
+		{
+			name: "unsupported currency",
+			req: &pb.OrderReq{
+				UserId:     1,
+				Items:      []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+				TotalPrice: &pb.Money{Amount: 3199, Currency: "EUR"},
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "mismatching client total",
+			req: &pb.OrderReq{
+				UserId:     1,
+				Items:      []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+				TotalPrice: usd(1),
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "unknown product",
+			req: &pb.OrderReq{
+				UserId: 1,
+				Items:  []*pb.OrderItem{{ProductId: 42, Quantity: 1}},
+			},
+			code: codes.NotFound,
+		},
+		{
+			name: "insufficient stock",
+			req: &pb.OrderReq{
+				UserId: 1,
+				Items:  []*pb.OrderItem{{ProductId: 2, Quantity: 2}},
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			name: "invalid quantity",
+			req: &pb.OrderReq{
+				UserId: 1,
+				Items:  []*pb.OrderItem{{ProductId: 1, Quantity: 0}},
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "quantity over the line limit",
+			req: &pb.OrderReq{
+				UserId: 1,
+				Items:  []*pb.OrderItem{{ProductId: 1, Quantity: maxLineQuantity + 1}},
+			},
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, _ := newTestServer(t)
+
+			res, err := srv.CreateOrder(context.Background(), tc.req)
+			require.Equal(t, tc.code, status.Code(err), "unexpected error: %v", err)
+			if tc.test != nil {
+				tc.test(t, res)
+			}
+		})
+	}
+}
+
+func TestUpdateOrderStatus(t *testing.T) {
+	// a step either pays the order with a payment source or changes its
+	// status; retries succeed without changing it
+	type step struct {
+		pay   string
+		req   *pb.OrderReq
+		code  codes.Code
+		retry bool
+	}
+
+	tcs := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "admin moves order forward",
+			steps: []step{
+				{pay: payments.FakeSourceApprove},
+				{req: &pb.OrderReq{UserId: 2, IsAdmin: true, Status: pb.OrderStatus_PROCESSING}},
+				{req: &pb.OrderReq{UserId: 2, IsAdmin: true, Status: pb.OrderStatus_SHIPPED}},
+				{req: &pb.OrderReq{UserId: 2, IsAdmin: true, Status: pb.OrderStatus_DELIVERED}},
+				{req: &pb.OrderReq{UserId: 2, IsAdmin: true, Status: pb.OrderStatus_REFUNDED, Reason: "damaged"}},
+			},
+		},
+		{
+			name: "admin cannot mark order paid",
+			steps: []step{
+				{req: &pb.OrderReq{UserId: 2, IsAdmin: true, Status: pb.OrderStatus_PAID}, code: codes.FailedPrecondition},
+			},
+		},
+		{
+			name: "customer cannot move order forward",
+			steps: []step{
+				{req: &pb.OrderReq{UserId: 1, Status: pb.OrderStatus_PAID}, code: codes.PermissionDenied},
+			},
+		},
+		{
+			name: "customer cancels before shipping",
+			steps: []step{
+				{pay: payments.FakeSourceApprove},
+				{req: &pb.OrderReq{UserId: 1, Status: pb.OrderStatus_CANCELLED, Reason: "changed my mind"}},
+				{req: &pb.OrderReq{UserId: 1, Status: pb.OrderStatus_CANCELLED}, retry: true},
+				{req: &pb.OrderReq{UserId: 1, Status: pb.OrderStatus_REFUNDED}, code: codes.PermissionDenied},
+			},
+		},
+		{
+			name: "customer cannot cancel shipped order",
+			steps: []step{
+				{pay: payments.FakeSourceApprove},
+				{req: &pb.OrderReq{UserId: 2, IsAdmin: true, Status: pb.OrderStatus_PROCESSING}},
+				{req: &pb.OrderReq{UserId: 2, IsAdmin: true, Status: pb.OrderStatus_SHIPPED}},
+				{req: &pb.OrderReq{UserId: 1, Status: pb.OrderStatus_CANCELLED}, code: codes.FailedPrecondition},
+			},
+		},
+		{
+			name: "skipping a step",
+			steps: []step{
+				{req: &pb.OrderReq{UserId: 2, IsAdmin: true, Status: pb.OrderStatus_SHIPPED}, code: codes.FailedPrecondition},
+			},
+		},
+		{
+			name: "other customer's order",
+			steps: []step{
+				{req: &pb.OrderReq{UserId: 2, Status: pb.OrderStatus_CANCELLED}, code: codes.PermissionDenied},
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			srv, st := newTestServer(t)
+
+			order, err := srv.CreateOrder(ctx, &pb.OrderReq{
+				UserId:    1,
+				UserEmail: "test@example.com",
+				Items:     []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+			})
+			require.NoError(t, err)
+
+			var applied int
+			for _, s := range tc.steps {
+				if s.pay != "" {
+					res, err := srv.PayOrder(ctx, &pb.PaymentReq{OrderId: order.Id, UserId: 1, Source: s.pay})
+					require.NoError(t, err)
+					require.Equal(t, string(payments.StatusCaptured), res.Status)
+					applied++
+					continue
+				}
+
+				s.req.Id = order.Id
+				res, err := srv.UpdateOrderStatus(ctx, s.req)
+				require.Equal(t, s.code, status.Code(err), "unexpected error: %v", err)
+				if s.code == codes.OK {
+					require.Equal(t, s.req.Status, res.Status)
+					if !s.retry {
+						applied++
+					}
+				}
+			}
+
+			history, err := srv.ListOrderStatusChanges(ctx, &pb.OrderReq{Id: order.Id, UserId: 1})
+			require.NoError(t, err)
+			require.Len(t, history.Changes, applied)
+
+			// one notification for the new order and one per applied change,
+			// all addressed to the customer
+			events, err := st.ListNotificationEvents(ctx)
+			require.NoError(t, err)
+			require.Len(t, events, applied+1)
+			for _, ev := range events {
+				require.Equal(t, "test@example.com", ev.UserEmail)
+			}
+		})
+	}
+}
+
+func TestConcurrentOrderRefunds(t *testing.T) {
+	ctx := context.Background()
+	srv, _ := newTestServer(t)
+
+	_, err := srv.AdjustStoreCredit(ctx, &pb.StoreCreditReq{UserId: 1, Amount: usd(500), Reason: "goodwill"})
+	require.NoError(t, err)
+	order, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{{ProductId: 1, Quantity: 1}}, StoreCredit: usd(500)})
+	require.NoError(t, err)
+	_, err = srv.PayOrder(ctx, &pb.PaymentReq{OrderId: order.Id, UserId: 1, Source: payments.FakeSourceApprove})
+	require.NoError(t, err)
+
+	// concurrent refunds either lose the race or find the order refunded
+	// already, and the payments are refunded once
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := srv.UpdateOrderStatus(ctx, &pb.OrderReq{Id: order.Id, UserId: 2, IsAdmin: true, Status: pb.OrderStatus_REFUNDED})
+			if status.Code(err) != codes.Aborted {
+				require.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	sc, err := srv.GetStoreCredit(ctx, &pb.StoreCreditReq{UserId: 1})
+	require.NoError(t, err)
+	require.Equal(t, int64(500), sc.Balance.GetAmount())
+	ps, err := srv.ListOrderPayments(ctx, &pb.PaymentReq{OrderId: order.Id, UserId: 1})
+	require.NoError(t, err)
+	require.Len(t, ps.Payments, 2)
+	for _, p := range ps.Payments {
+		require.Equal(t, string(payments.StatusRefunded), p.Status)
+		require.True(t, proto.Equal(p.Amount, p.RefundedAmount))
+	}
+	history, err := srv.ListOrderStatusChanges(ctx, &pb.OrderReq{Id: order.Id, UserId: 1})
+	require.NoError(t, err)
+	require.Len(t, history.Changes, 2, "paid and refunded once")
+}
+
+func TestCancelPaidOrder(t *testing.T) {
+	ctx := context.Background()
+	srv, _ := newTestServer(t)
+
+	order, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{{ProductId: 1, Quantity: 1}}})
+	require.NoError(t, err)
+	_, err = srv.PayOrder(ctx, &pb.PaymentReq{OrderId: order.Id, UserId: 1, Source: payments.FakeSourceApprove})
+	require.NoError(t, err)
+
+	// cancelling gives back what the provider took and credits the invoice
+	_, err = srv.UpdateOrderStatus(ctx, &pb.OrderReq{Id: order.Id, UserId: 1, Status: pb.OrderStatus_CANCELLED, Reason: "changed my mind"})
+	require.NoError(t, err)
+	ps, err := srv.ListOrderPayments(ctx, &pb.PaymentReq{OrderId: order.Id, UserId: 1})
+	require.NoError(t, err)
+	require.Len(t, ps.Payments, 1)
+	require.Equal(t, string(payments.StatusRefunded), ps.Payments[0].Status)
+	inv, err := srv.GetOrderInvoice(ctx, &pb.OrderReq{Id: order.Id, UserId: 1})
+	require.NoError(t, err)
+	require.Len(t, inv.CreditNotes, 1)
+	require.True(t, proto.Equal(inv.TotalPrice, inv.CreditNotes[0].TotalPrice))
+
+	// refunding it afterwards has nothing left to give back
+	_, err = srv.UpdateOrderStatus(ctx, &pb.OrderReq{Id: order.Id, UserId: 2, IsAdmin: true, Status: pb.OrderStatus_REFUNDED})
+	require.NoError(t, err)
+	inv, err = srv.GetOrderInvoice(ctx, &pb.OrderReq{Id: order.Id, UserId: 1})
+	require.NoError(t, err)
+	require.Len(t, inv.CreditNotes, 1)
+}
+
+// flakyRefunds is a payment provider whose refunds fail while fail is set.
+type flakyRefunds struct {
+	payments.Provider
+	fail    bool
+	refunds int
+}
+
+func (p *flakyRefunds) Refund(ctx context.Context, reference, key string, amount money.Money) (*payments.Result, error) {
+	if p.fail {
+		return nil, errors.New("provider unavailable")
+	}
+	p.refunds++
+	return p.Provider.Refund(ctx, reference, key, amount)
+}
+
+func TestCancelRetriesPendingRefund(t *testing.T) {
+	ctx := context.Background()
+	srv, st := newTestServer(t)
+	provider := &flakyRefunds{Provider: srv.payments, fail: true}
+	srv.payments = provider
+
+	order, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{{ProductId: 1, Quantity: 1}}})
+	require.NoError(t, err)
+	_, err = srv.PayOrder(ctx, &pb.PaymentReq{OrderId: order.Id, UserId: 1, Source: payments.FakeSourceApprove})
+	require.NoError(t, err)
+
+	// the refund the provider failed stays recorded, pending
+	cancel := &pb.OrderReq{Id: order.Id, UserId: 1, Status: pb.OrderStatus_CANCELLED}
+	_, err = srv.UpdateOrderStatus(ctx, cancel)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	refunds, err := st.ListOrderPaymentRefunds(ctx, order.Id)
+	require.NoError(t, err)
+	require.Len(t, refunds, 1)
+	require.Equal(t, storer.RefundPending, refunds[0].Status)
+
+	// cancelling again carries it out, once
+	provider.fail = false
+	for range 2 {
+		_, err = srv.UpdateOrderStatus(ctx, cancel)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, provider.refunds)
+	refunds, err = st.ListOrderPaymentRefunds(ctx, order.Id)
+	require.NoError(t, err)
+	require.Len(t, refunds, 1)
+	require.Equal(t, storer.RefundApplied, refunds[0].Status)
+	require.Equal(t, toMoney(order.TotalPrice), refunds[0].Amount)
+}
+
+func TestGetOrder(t *testing.T) {
+	ctx := context.Background()
+	srv, _ := newTestServer(t)
+
+	order, err := srv.CreateOrder(ctx, &pb.OrderReq{
+		UserId: 1,
+		Items:  []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+	})
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name string
+		req  *pb.OrderReq
+		code codes.Code
+	}{
+		{name: "owner", req: &pb.OrderReq{Id: order.Id, UserId: 1}},
+		{name: "admin", req: &pb.OrderReq{Id: order.Id, UserId: 2, IsAdmin: true}},
+		{name: "other customer", req: &pb.OrderReq{Id: order.Id, UserId: 2}, code: codes.PermissionDenied},
+		{name: "missing order", req: &pb.OrderReq{Id: 42, UserId: 1}, code: codes.NotFound},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := srv.GetOrder(ctx, tc.req)
+			require.Equal(t, tc.code, status.Code(err), "unexpected error: %v", err)
+			if tc.code == codes.OK {
+				require.Equal(t, order.Id, res.Id)
+				require.Len(t, res.Items, 1)
+			}
+		})
+	}
+}
+
+func TestDeleteOrder(t *testing.T) {
+	ctx := context.Background()
+	srv, _ := newTestServer(t)
+
+	newOrder := func() int64 {
+		order, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{{ProductId: 1, Quantity: 1}}})
+		require.NoError(t, err)
+		return order.Id
+	}
+	paid := newOrder()
+	_, err := srv.PayOrder(ctx, &pb.PaymentReq{OrderId: paid, UserId: 1, Source: payments.FakeSourceApprove})
+	require.NoError(t, err)
+	cancelled := newOrder()
+	_, err = srv.UpdateOrderStatus(ctx, &pb.OrderReq{Id: cancelled, UserId: 2, IsAdmin: true, Status: pb.OrderStatus_CANCELLED})
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name string
+		req  *pb.OrderReq
+		code codes.Code
+	}{
+		{name: "other customer", req: &pb.OrderReq{Id: newOrder(), UserId: 2}, code: codes.PermissionDenied},
+		{name: "owner of a cancelled order", req: &pb.OrderReq{Id: cancelled, UserId: 1}, code: codes.PermissionDenied},
+		{name: "paid order", req: &pb.OrderReq{Id: paid, UserId: 2, IsAdmin: true}, code: codes.FailedPrecondition},
+		{name: "owner of a pending order", req: &pb.OrderReq{Id: newOrder(), UserId: 1}},
+		{name: "admin", req: &pb.OrderReq{Id: cancelled, UserId: 2, IsAdmin: true}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := srv.DeleteOrder(ctx, tc.req)
+			require.Equal(t, tc.code, status.Code(err), "unexpected error: %v", err)
+			_, err = srv.GetOrder(ctx, &pb.OrderReq{Id: tc.req.Id, UserId: 2, IsAdmin: true})
+			if tc.code == codes.OK {
+				require.Equal(t, codes.NotFound, status.Code(err))
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPayOrder(t *testing.T) {
+	pay := func(srv *Server, orderID int64, source string) (*pb.PaymentRes, error) {
+		return srv.PayOrder(context.Background(), &pb.PaymentReq{OrderId: orderID, UserId: 1, Source: source})
+	}
+	orderStatus := func(t *testing.T, srv *Server, orderID int64) pb.OrderStatus {
+		o, err := srv.GetOrder(context.Background(), &pb.OrderReq{Id: orderID, UserId: 1})
+		require.NoError(t, err)
+		return o.Status
+	}
+	paymentStatuses := func(t *testing.T, srv *Server, orderID int64) []string {
+		res, err := srv.ListOrderPayments(context.Background(), &pb.PaymentReq{OrderId: orderID, UserId: 1})
+		require.NoError(t, err)
+		var statuses []string
+		for _, p := range res.Payments {
+			statuses = append(statuses, p.Status)
+		}
+		return statuses
+	}
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *Server, int64)
+	}{
+		{
+			name: "approved and refunded",
+			test: func(t *testing.T, srv *Server, orderID int64) {
+				res, err := pay(srv, orderID, payments.FakeSourceApprove)
+				require.NoError(t, err)
+				require.Equal(t, string(payments.StatusCaptured), res.Status)
+				require.Equal(t, pb.OrderStatus_PAID, orderStatus(t, srv, orderID))
+				o, err := srv.GetOrder(context.Background(), &pb.OrderReq{Id: orderID, UserId: 1})
+				require.NoError(t, err)
+				require.True(t, proto.Equal(o.TotalPrice, res.Amount))
+
+				history, err := srv.ListOrderStatusChanges(context.Background(), &pb.OrderReq{Id: orderID, UserId: 1})
+				require.NoError(t, err)
+				require.Len(t, history.Changes, 1)
+				require.Equal(t, string(storer.ActorSystem), history.Changes[0].ActorRole)
+
+				_, err = pay(srv, orderID, payments.FakeSourceApprove)
+				require.Equal(t, codes.FailedPrecondition, status.Code(err), "paying a paid order")
+
+				_, err = srv.UpdateOrderStatus(context.Background(), &pb.OrderReq{Id: orderID, UserId: 2, IsAdmin: true, Status: pb.OrderStatus_REFUNDED})
+				require.NoError(t, err)
+				require.Equal(t, []string{"refunded"}, paymentStatuses(t, srv, orderID))
+			},
+		},
+		{
+			name: "declined, then approved",
+			test: func(t *testing.T, srv *Server, orderID int64) {
+				res, err := pay(srv, orderID, payments.FakeSourceDecline)
+				require.NoError(t, err)
+				require.Equal(t, string(payments.StatusDeclined), res.Status)
+				require.NotEmpty(t, res.Message)
+				require.Equal(t, pb.OrderStatus_PENDING, orderStatus(t, srv, orderID))
+
+				_, err = pay(srv, orderID, payments.FakeSourceApprove)
+				require.NoError(t, err)
+				require.Equal(t, pb.OrderStatus_PAID, orderStatus(t, srv, orderID))
+				require.Equal(t, []string{"declined", "captured"}, paymentStatuses(t, srv, orderID))
+			},
+		},
+		{
+			name: "provider unavailable",
+			test: func(t *testing.T, srv *Server, orderID int64) {
+				_, err := pay(srv, orderID, payments.FakeSourceError)
+				require.Equal(t, codes.Unavailable, status.Code(err))
+				require.Equal(t, []string{"failed"}, paymentStatuses(t, srv, orderID))
+			},
+		},
+		{
+			name: "pending payment settled by event",
+			test: func(t *testing.T, srv *Server, orderID int64) {
+				events := make(chan *payments.Event, 1)
+				srv.payments = payments.NewFakeProvider(time.Millisecond, payments.NotifierFunc(func(ctx context.Context, e *payments.Event) error {
+					events <- e
+					return nil
+				}))
+
+				res, err := pay(srv, orderID, payments.FakeSourcePending)
+				require.NoError(t, err)
+				require.Equal(t, string(payments.StatusPending), res.Status)
+
+				_, err = pay(srv, orderID, payments.FakeSourceApprove)
+				require.Equal(t, codes.FailedPrecondition, status.Code(err), "paying twice")
+
+				e := <-events
+				req := &pb.PaymentEventReq{Provider: e.Provider, Reference: e.Reference, Status: string(e.Status)}
+				res, err = srv.HandlePaymentEvent(context.Background(), req)
+				require.NoError(t, err)
+				require.Equal(t, string(payments.StatusCaptured), res.Status)
+				require.Equal(t, pb.OrderStatus_PAID, orderStatus(t, srv, orderID))
+
+				// redelivered events change nothing
+				res, err = srv.HandlePaymentEvent(context.Background(), req)
+				require.NoError(t, err)
+				require.Equal(t, string(payments.StatusCaptured), res.Status)
+
+				_, err = srv.HandlePaymentEvent(context.Background(), &pb.PaymentEventReq{Provider: "fake", Reference: "fake_404", Status: "authorized"})
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "pending payment voided by cancelling",
+			test: func(t *testing.T, srv *Server, orderID int64) {
+				srv.payments = payments.NewFakeProvider(time.Hour, nil)
+
+				res, err := pay(srv, orderID, payments.FakeSourcePending)
+				require.NoError(t, err)
+
+				_, err = srv.UpdateOrderStatus(context.Background(), &pb.OrderReq{Id: orderID, UserId: 1, Status: pb.OrderStatus_CANCELLED})
+				require.NoError(t, err)
+				require.Equal(t, []string{"voided"}, paymentStatuses(t, srv, orderID))
+
+				res, err = srv.HandlePaymentEvent(context.Background(), &pb.PaymentEventReq{Provider: res.Provider, Reference: res.Reference, Status: "authorized"})
+				require.NoError(t, err)
+				require.Equal(t, string(payments.StatusVoided), res.Status)
+				require.Equal(t, pb.OrderStatus_CANCELLED, orderStatus(t, srv, orderID))
+			},
+		},
+		{
+			name: "other customer's order",
+			test: func(t *testing.T, srv *Server, orderID int64) {
+				_, err := srv.PayOrder(context.Background(), &pb.PaymentReq{OrderId: orderID, UserId: 2, Source: payments.FakeSourceApprove})
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+				_, err = pay(srv, orderID, " ")
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, _ := newTestServer(t)
+			order, err := srv.CreateOrder(context.Background(), &pb.OrderReq{
+				UserId: 1,
+				Items:  []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+			})
+			require.NoError(t, err)
+
+			tc.test(t, srv, order.Id)
+		})
+	}
+}
+
+func TestCart(t *testing.T) {
+	ctx := context.Background()
+	srv, _ := newTestServer(t)
+
+	_, err := srv.AddCartItem(ctx, &pb.CartReq{UserId: 1, ProductId: 1, Quantity: 2})
+	require.NoError(t, err)
+
+	_, err = srv.AddCartItem(ctx, &pb.CartReq{UserId: 1, ProductId: 1, Quantity: 9})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = srv.AddCartItem(ctx, &pb.CartReq{UserId: 1, ProductId: 1, Quantity: maxLineQuantity - 1})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = srv.AddCartItem(ctx, &pb.CartReq{UserId: 1, ProductId: 42, Quantity: 1})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = srv.UpdateCartItem(ctx, &pb.CartReq{UserId: 1, ProductId: 2, Quantity: 1})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = srv.AddCartItem(ctx, &pb.CartReq{UserId: 1, ProductId: 2, Quantity: 1})
+	require.NoError(t, err)
+
+	cart, err := srv.UpdateCartItem(ctx, &pb.CartReq{UserId: 1, ProductId: 1, Quantity: 3})
+	require.NoError(t, err)
+	require.Len(t, cart.Items, 2)
+	require.Equal(t, int64(3), cart.Items[0].Quantity)
+	require.True(t, cart.Items[0].Available)
+	require.Equal(t, int64(5997), cart.Items[0].LineTotal.GetAmount())
+	require.Equal(t, int64(10547), cart.ItemsPrice.GetAmount())
+
+	// stock sold elsewhere shows up as unavailable
+	_, err = srv.AdjustStock(ctx, &pb.StockReq{ProductId: 2, Quantity: -1, Reason: "sold in store"})
+	require.NoError(t, err)
+	cart, err = srv.GetCart(ctx, &pb.CartReq{UserId: 1})
+	require.NoError(t, err)
+	require.False(t, cart.Items[1].Available)
+
+	_, err = srv.CheckoutCart(ctx, &pb.CheckoutCartReq{UserId: 1})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = srv.RemoveCartItem(ctx, &pb.CartReq{UserId: 1, ProductId: 2})
+	require.NoError(t, err)
+
+	order, err := srv.CheckoutCart(ctx, &pb.CheckoutCartReq{UserId: 1, UserEmail: "test@example.com", TotalPrice: usd(7597)})
+	require.NoError(t, err)
+	require.Len(t, order.Items, 1)
+	require.Equal(t, int64(3), order.Items[0].Quantity)
+
+	cart, err = srv.GetCart(ctx, &pb.CartReq{UserId: 1})
+	require.NoError(t, err)
+	require.Empty(t, cart.Items)
+
+	_, err = srv.CheckoutCart(ctx, &pb.CheckoutCartReq{UserId: 1})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestReviews(t *testing.T) {
+	ctx := context.Background()
+	srv, st := newTestServer(t)
+
+	rating := func() (int64, int64) {
+		p, err := st.GetProduct(ctx, 1)
+		require.NoError(t, err)
+		return p.Rating, p.NumReviews
+	}
+
+	review := &pb.ReviewReq{UserId: 1, ProductId: 1, Rating: 4, Title: "solid", Body: "does the job"}
+	_, err := srv.CreateReview(ctx, review)
+	require.Equal(t, codes.PermissionDenied, status.Code(err), "reviewing before delivery")
+
+	order, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{{ProductId: 1, Quantity: 1}}})
+	require.NoError(t, err)
+	_, err = srv.PayOrder(ctx, &pb.PaymentReq{OrderId: order.Id, UserId: 1, Source: payments.FakeSourceApprove})
+	require.NoError(t, err)
+	for _, s := range []pb.OrderStatus{pb.OrderStatus_PROCESSING, pb.OrderStatus_SHIPPED, pb.OrderStatus_DELIVERED} {
+		_, err := srv.UpdateOrderStatus(ctx, &pb.OrderReq{Id: order.Id, UserId: 2, IsAdmin: true, Status: s})
+		require.NoError(t, err)
+	}
+
+	_, err = srv.CreateReview(ctx, &pb.ReviewReq{UserId: 1, ProductId: 1, Rating: 6, Title: "too good"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	created, err := srv.CreateReview(ctx, review)
+	require.NoError(t, err)
+	r, n := rating()
+	require.Equal(t, int64(4), r)
+	require.Equal(t, int64(1), n)
+
+	_, err = srv.CreateReview(ctx, review)
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = srv.UpdateReview(ctx, &pb.ReviewReq{Id: created.Id, UserId: 2, Rating: 1, Title: "not mine"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = srv.UpdateReview(ctx, &pb.ReviewReq{Id: created.Id, UserId: 1, Rating: 2, Title: "broke after a week"})
+	require.NoError(t, err)
+	r, _ = rating()
+	require.Equal(t, int64(2), r)
+
+	_, err = srv.ModerateReview(ctx, &pb.ReviewReq{Id: created.Id, UserId: 1, Hidden: true})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	moderated, err := srv.ModerateReview(ctx, &pb.ReviewReq{Id: created.Id, UserId: 2, IsAdmin: true, Hidden: true})
+	require.NoError(t, err)
+	require.True(t, moderated.Hidden)
+	r, n = rating()
+	require.Zero(t, r)
+	require.Zero(t, n)
+
+	list, err := srv.ListProductReviews(ctx, &pb.ListReviewsReq{ProductId: 1})
+	require.NoError(t, err)
+	require.Empty(t, list.Reviews)
+	list, err = srv.ListProductReviews(ctx, &pb.ListReviewsReq{ProductId: 1, IncludeHidden: true})
+	require.NoError(t, err)
+	require.Len(t, list.Reviews, 1)
+
+	_, err = srv.DeleteReview(ctx, &pb.ReviewReq{Id: created.Id, UserId: 1})
+	require.NoError(t, err)
+	_, err = srv.DeleteReview(ctx, &pb.ReviewReq{Id: created.Id, UserId: 1})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCategories(t *testing.T) {
+	ctx := context.Background()
+	srv, _ := newTestServer(t)
+
+	id := func(v int64) *int64 { return &v }
+
+	shoes, err := srv.CreateCategory(ctx, &pb.CategoryReq{Name: "Shoes & Boots"})
+	require.NoError(t, err)
+	require.Equal(t, "shoes-boots", shoes.Slug)
+
+	running, err := srv.CreateCategory(ctx, &pb.CategoryReq{Name: "Running", ParentId: id(shoes.Id)})
+	require.NoError(t, err)
+	trail, err := srv.CreateCategory(ctx, &pb.CategoryReq{Name: "Trail", ParentId: id(running.Id)})
+	require.NoError(t, err)
+	_, err = srv.CreateCategory(ctx, &pb.CategoryReq{Name: "Accessories", SortOrder: id(-1)})
+	require.NoError(t, err)
+
+	_, err = srv.CreateCategory(ctx, &pb.CategoryReq{Name: "Running", Slug: "running"})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+	_, err = srv.CreateCategory(ctx, &pb.CategoryReq{Name: "Bad", Slug: "Not A Slug"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = srv.CreateCategory(ctx, &pb.CategoryReq{Name: "Orphan", ParentId: id(99)})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	tree, err := srv.ListCategories(ctx, &pb.ListCategoriesReq{})
+	require.NoError(t, err)
+	require.Len(t, tree.Categories, 2)
+	require.Equal(t, "accessories", tree.Categories[0].Slug)
+	require.Equal(t, "running", tree.Categories[1].Children[0].Slug)
+	require.Equal(t, "trail", tree.Categories[1].Children[0].Children[0].Slug)
+
+	_, err = srv.UpdateCategory(ctx, &pb.CategoryReq{Id: shoes.Id, ParentId: id(trail.Id)})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "moving a category under its descendant")
+
+	_, err = srv.UpdateProduct(ctx, &pb.ProductReq{Id: 1, CategoryId: trail.Id})
+	require.NoError(t, err)
+	_, err = srv.UpdateProduct(ctx, &pb.ProductReq{Id: 2, CategoryId: shoes.Id})
+	require.NoError(t, err)
+	_, err = srv.UpdateProduct(ctx, &pb.ProductReq{Id: 2, CategoryId: 99})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	products, err := srv.ListProducts(ctx, &pb.ListProductsReq{CategorySlug: "shoes-boots"})
+	require.NoError(t, err)
+	require.Len(t, products.Products, 2, "products of descendant categories are included")
+	products, err = srv.ListProducts(ctx, &pb.ListProductsReq{CategorySlug: "running"})
+	require.NoError(t, err)
+	require.Len(t, products.Products, 1)
+	require.Equal(t, trail.Id, products.Products[0].CategoryId)
+	_, err = srv.ListProducts(ctx, &pb.ListProductsReq{CategorySlug: "missing"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = srv.DeleteCategory(ctx, &pb.CategoryReq{Id: running.Id})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	moved, err := srv.UpdateCategory(ctx, &pb.CategoryReq{Id: trail.Id, ParentId: id(0), Slug: "trail-shoes"})
+	require.NoError(t, err)
+	require.Zero(t, moved.ParentId)
+	got, err := srv.GetCategory(ctx, &pb.CategoryReq{Slug: "running"})
+	require.NoError(t, err)
+	require.Empty(t, got.Children)
+
+	_, err = srv.DeleteCategory(ctx, &pb.CategoryReq{Id: running.Id})
+	require.NoError(t, err)
+	_, err = srv.GetCategory(ctx, &pb.CategoryReq{Id: running.Id})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestVariants(t *testing.T) {
+	ctx := context.Background()
+	srv, st := newTestServer(t)
+
+	shirt, err := srv.CreateProduct(ctx, &pb.ProductReq{Name: "T-shirt", Image: "shirt.jpg", Price: usd(1500), Options: []string{"size", " color "}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"size", "color"}, shirt.Options)
+
+	mRed, err := srv.CreateVariant(ctx, &pb.VariantReq{ProductId: shirt.Id, Sku: "TS-M-RED", Options: map[string]string{"size": "M", "color": "Red"}, CountInStock: proto.Int64(2)})
+	require.NoError(t, err)
+	require.Equal(t, int64(1500), mRed.Price.GetAmount(), "price falls back to the product's")
+	require.Equal(t, "shirt.jpg", mRed.Image)
+	lRed, err := srv.CreateVariant(ctx, &pb.VariantReq{ProductId: shirt.Id, Sku: "TS-L-RED", Options: map[string]string{"size": "L", "color": "Red"}, Price: usd(1700), Image: proto.String("red.jpg"), CountInStock: proto.Int64(5)})
+	require.NoError(t, err)
+	require.Equal(t, int64(1700), lRed.Price.GetAmount())
+
+	for name, req := range map[string]*pb.VariantReq{
+		"missing axis":     {ProductId: shirt.Id, Sku: "TS-S", Options: map[string]string{"size": "S"}},
+		"unknown axis":     {ProductId: shirt.Id, Sku: "TS-S", Options: map[string]string{"size": "S", "fit": "Slim"}},
+		"empty value":      {ProductId: shirt.Id, Sku: "TS-S", Options: map[string]string{"size": "S", "color": ""}},
+		"empty sku":        {ProductId: shirt.Id, Options: map[string]string{"size": "S", "color": "Red"}},
+		"negative stock":   {ProductId: shirt.Id, Sku: "TS-S", Options: map[string]string{"size": "S", "color": "Red"}, CountInStock: proto.Int64(-1)},
+		"foreign currency": {ProductId: shirt.Id, Sku: "TS-S", Options: map[string]string{"size": "S", "color": "Red"}, Price: &pb.Money{Amount: 1, Currency: "EUR"}},
+	} {
+		_, err := srv.CreateVariant(ctx, req)
+		require.Equal(t, codes.InvalidArgument, status.Code(err), name)
+	}
+	_, err = srv.CreateVariant(ctx, &pb.VariantReq{ProductId: shirt.Id, Sku: "TS-M-RED-2", Options: map[string]string{"size": "M", "color": "Red"}})
+	require.Equal(t, codes.AlreadyExists, status.Code(err), "duplicate option combination")
+	_, err = srv.CreateVariant(ctx, &pb.VariantReq{ProductId: shirt.Id, Sku: "TS-M-RED", Options: map[string]string{"size": "M", "color": "Blue"}})
+	require.Equal(t, codes.AlreadyExists, status.Code(err), "duplicate sku")
+	_, err = srv.CreateVariant(ctx, &pb.VariantReq{ProductId: 1, Sku: "TP-1", Options: map[string]string{"size": "M"}})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "product without options")
+
+	got, err := srv.GetProduct(ctx, &pb.ProductReq{Id: shirt.Id})
+	require.NoError(t, err)
+	require.Len(t, got.Variants, 2)
+	require.Equal(t, int64(7), got.CountInStock, "product stock is the sum of its variants'")
+
+	_, err = srv.UpdateProduct(ctx, &pb.ProductReq{Id: shirt.Id, Options: []string{"size"}})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = srv.UpdateProduct(ctx, &pb.ProductReq{Id: shirt.Id, CountInStock: 3})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{{ProductId: shirt.Id, Quantity: 1}}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "a variant must be chosen")
+	_, err = srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{{ProductId: 1, VariantId: mRed.Id, Quantity: 1}}})
+	require.Equal(t, codes.NotFound, status.Code(err), "variant of another product")
+	_, err = srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{{ProductId: shirt.Id, VariantId: mRed.Id, Quantity: 3}}})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "variant stock is checked")
+
+	order, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{
+		{ProductId: shirt.Id, VariantId: mRed.Id, Quantity: 2, Price: usd(1500)},
+		{ProductId: shirt.Id, VariantId: lRed.Id, Quantity: 1, Price: usd(1700)},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, "T-shirt - M / Red", order.Items[0].Name)
+	require.Equal(t, "TS-M-RED", order.Items[0].Sku)
+	require.Equal(t, "red.jpg", order.Items[1].Image)
+	require.Equal(t, int64(4700), order.ItemsPrice.GetAmount())
+
+	p, err := st.GetProduct(ctx, shirt.Id)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), p.CountInStock)
+
+	// cart lines are per variant
+	_, err = srv.AddCartItem(ctx, &pb.CartReq{UserId: 1, ProductId: shirt.Id, Quantity: 1})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = srv.AddCartItem(ctx, &pb.CartReq{UserId: 1, ProductId: shirt.Id, VariantId: mRed.Id, Quantity: 1})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "sold out")
+	cart, err := srv.AddCartItem(ctx, &pb.CartReq{UserId: 1, ProductId: shirt.Id, VariantId: lRed.Id, Quantity: 2})
+	require.NoError(t, err)
+	require.Equal(t, "TS-L-RED", cart.Items[0].Sku)
+	require.Equal(t, int64(3400), cart.ItemsPrice.GetAmount())
+
+	_, err = srv.DeleteVariant(ctx, &pb.VariantReq{ProductId: shirt.Id, Id: mRed.Id})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "ordered variants are kept")
+
+	_, err = srv.UpdateVariant(ctx, &pb.VariantReq{ProductId: shirt.Id, Id: lRed.Id, CountInStock: proto.Int64(10)})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "stock is adjusted through the ledger")
+	updated, err := srv.UpdateVariant(ctx, &pb.VariantReq{ProductId: shirt.Id, Id: lRed.Id, InheritPrice: true})
+	require.NoError(t, err)
+	require.Equal(t, int64(1500), updated.Price.GetAmount())
+	_, err = srv.AdjustStock(ctx, &pb.StockReq{ProductId: shirt.Id, VariantId: lRed.Id, Kind: "receipt", Quantity: 7, Reason: "delivery"})
+	require.NoError(t, err)
+	p, err = st.GetProduct(ctx, shirt.Id)
+	require.NoError(t, err)
+	require.Equal(t, int64(11), p.CountInStock)
+
+	sRed, err := srv.CreateVariant(ctx, &pb.VariantReq{ProductId: shirt.Id, Sku: "TS-S-RED", Options: map[string]string{"size": "S", "color": "Red"}, CountInStock: proto.Int64(1)})
+	require.NoError(t, err)
+	_, err = srv.AddCartItem(ctx, &pb.CartReq{UserId: 1, ProductId: shirt.Id, VariantId: sRed.Id, Quantity: 1})
+	require.NoError(t, err)
+	_, err = srv.DeleteVariant(ctx, &pb.VariantReq{ProductId: 1, Id: sRed.Id})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = srv.DeleteVariant(ctx, &pb.VariantReq{ProductId: shirt.Id, Id: sRed.Id})
+	require.NoError(t, err)
+	cart, err = srv.GetCart(ctx, &pb.CartReq{UserId: 1})
+	require.NoError(t, err)
+	require.Len(t, cart.Items, 1, "deleted variants drop out of carts")
+}
+
+func TestPromotionPricing(t *testing.T) {
+	active := false
+	hourAgo := timestamppb.New(time.Now().Add(-time.Hour))
+
+	tcs := []struct {
+		name       string
+		promotions []*pb.PromotionReq
+		setup      func(*testing.T, *Server)
+		req        *pb.OrderReq
+		code       codes.Code
+		test       func(*testing.T, *pb.OrderRes)
+	}{
+		{
+			name:       "percentage coupon",
+			promotions: []*pb.PromotionReq{{Name: "save 10%", Code: "save10", Type: pb.PromotionType_PERCENTAGE, DiscountRate: 1000}},
+			req: &pb.OrderReq{
+				UserId:     1,
+				CouponCode: " Save10 ",
+				Items:      []*pb.OrderItem{{ProductId: 1, Quantity: 2}},
+			},
+			test: func(t *testing.T, res *pb.OrderRes) {
+				require.Equal(t, int64(3998), res.ItemsPrice.GetAmount())
+				// 10% of 39.98 is 3.998, rounded to 4.00
+				require.Equal(t, int64(400), res.DiscountPrice.GetAmount())
+				// tax is taken on the discounted 35.98
+				require.Equal(t, int64(360), res.TaxPrice.GetAmount())
+				require.Equal(t, flatShippingPrice.Amount, res.ShippingPrice.GetAmount())
+				require.Equal(t, int64(4958), res.TotalPrice.GetAmount())
+				require.Equal(t, "SAVE10", res.CouponCode)
+				require.Len(t, res.Discounts, 1)
+				require.Equal(t, "SAVE10", res.Discounts[0].Code)
+				require.Equal(t, int64(400), res.Discounts[0].Amount.GetAmount())
+			},
+		},
+		{
+			name: "unknown coupon",
+			req: &pb.OrderReq{
+				UserId:     1,
+				CouponCode: "NOPE",
+				Items:      []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+			},
+			code: codes.NotFound,
+		},
+		{
+			name:       "coupon below minimum subtotal",
+			promotions: []*pb.PromotionReq{{Name: "big spender", Code: "BIG", Type: pb.PromotionType_FIXED_AMOUNT, DiscountAmount: usd(500), MinSubtotal: usd(5000)}},
+			req: &pb.OrderReq{
+				UserId:     1,
+				CouponCode: "BIG",
+				Items:      []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			name:       "expired coupon",
+			promotions: []*pb.PromotionReq{{Name: "old", Code: "OLD", Type: pb.PromotionType_PERCENTAGE, DiscountRate: 1000, EndsAt: hourAgo}},
+			req: &pb.OrderReq{
+				UserId:     1,
+				CouponCode: "OLD",
+				Items:      []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			name:       "coupon for a product not ordered",
+			promotions: []*pb.PromotionReq{{Name: "5 off", Code: "FIVE", Type: pb.PromotionType_FIXED_AMOUNT, DiscountAmount: usd(500), ProductId: 2}},
+			req: &pb.OrderReq{
+				UserId:     1,
+				CouponCode: "FIVE",
+				Items:      []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			name:       "mismatching client discount",
+			promotions: []*pb.PromotionReq{{Name: "save 10%", Code: "SAVE10", Type: pb.PromotionType_PERCENTAGE, DiscountRate: 1000}},
+			req: &pb.OrderReq{
+				UserId:        1,
+				CouponCode:    "SAVE10",
+				Items:         []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+				DiscountPrice: usd(1),
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name:       "buy two get one free",
+			promotions: []*pb.PromotionReq{{Name: "3 for 2", Type: pb.PromotionType_BUY_X_GET_Y, BuyQuantity: 2, GetQuantity: 1, ProductId: 1}},
+			req: &pb.OrderReq{
+				UserId: 1,
+				Items:  []*pb.OrderItem{{ProductId: 1, Quantity: 3}, {ProductId: 2, Quantity: 1}},
+			},
+			test: func(t *testing.T, res *pb.OrderRes) {
+				require.Equal(t, int64(1999), res.DiscountPrice.GetAmount())
+				require.Empty(t, res.CouponCode)
+				require.Equal(t, "3 for 2", res.Discounts[0].Name)
+				// 105.47 minus the free unit is 85.48
+				require.Equal(t, int64(855), res.TaxPrice.GetAmount())
+				require.Equal(t, int64(10547+855+1000-1999), res.TotalPrice.GetAmount())
+			},
+		},
+		{
+			name:       "fixed amount capped at the scoped items",
+			promotions: []*pb.PromotionReq{{Name: "50 off", Type: pb.PromotionType_FIXED_AMOUNT, DiscountAmount: usd(5000), ProductId: 1}},
+			req: &pb.OrderReq{
+				UserId: 1,
+				Items:  []*pb.OrderItem{{ProductId: 1, Quantity: 1}, {ProductId: 2, Quantity: 1}},
+			},
+			test: func(t *testing.T, res *pb.OrderRes) {
+				require.Equal(t, int64(1999), res.DiscountPrice.GetAmount())
+				require.Equal(t, int64(455), res.TaxPrice.GetAmount())
+				require.Equal(t, int64(6549+455+1000-1999), res.TotalPrice.GetAmount())
+			},
+		},
+		{
+			name: "category scope includes subcategories",
+			promotions: []*pb.PromotionReq{
+				{Name: "half off apparel", Type: pb.PromotionType_PERCENTAGE, DiscountRate: 5000, CategoryId: 1},
+				{Name: "inactive", Type: pb.PromotionType_PERCENTAGE, DiscountRate: 5000, Active: &active},
+			},
+			setup: func(t *testing.T, srv *Server) {
+				ctx := context.Background()
+				apparel, err := srv.CreateCategory(ctx, &pb.CategoryReq{Name: "Apparel"})
+				require.NoError(t, err)
+				shirts, err := srv.CreateCategory(ctx, &pb.CategoryReq{Name: "Shirts", ParentId: &apparel.Id})
+				require.NoError(t, err)
+				_, err = srv.UpdateProduct(ctx, &pb.ProductReq{Id: 1, CategoryId: shirts.Id})
+				require.NoError(t, err)
+			},
+			req: &pb.OrderReq{
+				UserId: 1,
+				Items:  []*pb.OrderItem{{ProductId: 1, Quantity: 1}, {ProductId: 2, Quantity: 1}},
+			},
+			test: func(t *testing.T, res *pb.OrderRes) {
+				// half of 19.99 rounded half away from zero
+				require.Equal(t, int64(1000), res.DiscountPrice.GetAmount())
+				require.Len(t, res.Discounts, 1)
+			},
+		},
+		{
+			name:       "free shipping above minimum",
+			promotions: []*pb.PromotionReq{{Name: "free shipping", Type: pb.PromotionType_FREE_SHIPPING, MinSubtotal: usd(3000)}},
+			req: &pb.OrderReq{
+				UserId: 1,
+				Items:  []*pb.OrderItem{{ProductId: 1, Quantity: 2}},
+			},
+			test: func(t *testing.T, res *pb.OrderRes) {
+				require.Equal(t, flatShippingPrice.Amount, res.ShippingPrice.GetAmount())
+				require.Equal(t, flatShippingPrice.Amount, res.DiscountPrice.GetAmount())
+				require.Equal(t, int64(3998+400), res.TotalPrice.GetAmount())
+			},
+		},
+		{
+			name:       "free shipping coupon when shipping is free",
+			promotions: []*pb.PromotionReq{{Name: "free shipping", Code: "SHIP", Type: pb.PromotionType_FREE_SHIPPING}},
+			req: &pb.OrderReq{
+				UserId:     1,
+				CouponCode: "SHIP",
+				Items:      []*pb.OrderItem{{ProductId: 1, Quantity: 6}},
+			},
+			code: codes.FailedPrecondition,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			srv, _ := newTestServer(t)
+			if tc.setup != nil {
+				tc.setup(t, srv)
+			}
+			for _, p := range tc.promotions {
+				_, err := srv.CreatePromotion(ctx, p)
+				require.NoError(t, err)
+			}
+
+			res, err := srv.CreateOrder(ctx, tc.req)
+			require.Equal(t, tc.code, status.Code(err), "unexpected error: %v", err)
+			if tc.test != nil {
+				tc.test(t, res)
+			}
+		})
+	}
+}
+
+func TestFreeUnitsPrice(t *testing.T) {
+	line := func(price, qty int64) storer.OrderItem {
+		return storer.OrderItem{Price: money.New(price, money.DefaultCurrency), Quantity: qty}
+	}
+
+	tcs := []struct {
+		name  string
+		items []storer.OrderItem
+		buy   int64
+		get   int64
+		want  int64
+	}{
+		{name: "no full group", items: []storer.OrderItem{line(1000, 2)}, buy: 2, get: 1},
+		{name: "one group", items: []storer.OrderItem{line(1000, 3)}, buy: 2, get: 1, want: 1000},
+		{name: "cheapest units go free", items: []storer.OrderItem{line(1000, 1), line(900, 1), line(800, 1), line(700, 1)}, buy: 1, get: 1, want: 1500},
+		{name: "free units span prices", items: []storer.OrderItem{line(500, 1), line(300, 2), line(100, 1)}, buy: 1, get: 2, want: 400},
+		{name: "huge quantity", items: []storer.OrderItem{line(1999, 1_000_000_000_000)}, buy: 2, get: 1, want: 1999 * 333_333_333_333},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, freeUnitsPrice(tc.items, tc.buy, tc.get).Amount)
+		})
+	}
+}
+
+func TestPromotions(t *testing.T) {
+	ctx := context.Background()
+	srv, st := newTestServer(t)
+
+	_, err := srv.CreatePromotion(ctx, &pb.PromotionReq{Name: "nothing off", Type: pb.PromotionType_PERCENTAGE})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = srv.CreatePromotion(ctx, &pb.PromotionReq{Name: "free shipping", Type: pb.PromotionType_FREE_SHIPPING, ProductId: 1})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "scoped free shipping")
+	_, err = srv.CreatePromotion(ctx, &pb.PromotionReq{Name: "5 off", Type: pb.PromotionType_FIXED_AMOUNT, DiscountAmount: usd(500), ProductId: 99})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	welcome, err := srv.CreatePromotion(ctx, &pb.PromotionReq{Name: "welcome", Code: "welcome", Type: pb.PromotionType_FIXED_AMOUNT, DiscountAmount: usd(500), PerCustomerLimit: 1})
+	require.NoError(t, err)
+	require.Equal(t, "WELCOME", welcome.Code)
+	require.True(t, welcome.Active)
+	_, err = srv.CreatePromotion(ctx, &pb.PromotionReq{Name: "again", Code: "Welcome", Type: pb.PromotionType_PERCENTAGE, DiscountRate: 100})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	order, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, CouponCode: "welcome", Items: []*pb.OrderItem{{ProductId: 1, Quantity: 1}}})
+	require.NoError(t, err)
+	require.Equal(t, int64(500), order.DiscountPrice.GetAmount())
+	got, err := srv.GetOrder(ctx, &pb.OrderReq{Id: order.Id, UserId: 1})
+	require.NoError(t, err)
+	require.Equal(t, "WELCOME", got.CouponCode)
+	require.Len(t, got.Discounts, 1)
+	require.Equal(t, welcome.Id, got.Discounts[0].PromotionId)
+
+	gp, err := srv.GetPromotion(ctx, &pb.PromotionReq{Id: welcome.Id})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), gp.TimesUsed)
+
+	_, err = srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, CouponCode: "WELCOME", Items: []*pb.OrderItem{{ProductId: 1, Quantity: 1}}})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "per-customer limit")
+
+	// another customer can still use it at checkout
+	u, err := st.CreateUser(ctx, &storer.User{Name: "other", Email: "other@example.com"})
+	require.NoError(t, err)
+	_, err = srv.AddCartItem(ctx, &pb.CartReq{UserId: u.ID, ProductId: 1, Quantity: 1})
+	require.NoError(t, err)
+	checkedOut, err := srv.CheckoutCart(ctx, &pb.CheckoutCartReq{UserId: u.ID, CouponCode: "welcome"})
+	require.NoError(t, err)
+	require.Equal(t, int64(500), checkedOut.DiscountPrice.GetAmount())
+
+	_, err = srv.DeletePromotion(ctx, &pb.PromotionReq{Id: welcome.Id})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	active := false
+	updated, err := srv.UpdatePromotion(ctx, &pb.PromotionReq{Id: welcome.Id, Name: "welcome", Code: "WELCOME", Type: pb.PromotionType_PERCENTAGE, DiscountRate: 1500, Active: &active})
+	require.NoError(t, err)
+	require.False(t, updated.Active)
+	require.Equal(t, pb.PromotionType_PERCENTAGE, updated.Type)
+	require.Zero(t, updated.DiscountAmount.GetAmount())
+	require.Equal(t, int64(2), updated.TimesUsed)
+
+	list, err := srv.ListPromotions(ctx, &pb.ListPromotionsReq{})
+	require.NoError(t, err)
+	require.Len(t, list.Promotions, 1)
+
+	_, err = srv.UpdatePromotion(ctx, &pb.PromotionReq{Id: 99, Name: "missing", Type: pb.PromotionType_PERCENTAGE, DiscountRate: 100})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestTaxes(t *testing.T) {
+	us := &pb.Address{Name: "test", Line1: "1 Main St", City: "Springfield", PostalCode: "12345", Country: "us"}
+	withRegion := func(a *pb.Address, region string) *pb.Address {
+		c := proto.Clone(a).(*pb.Address)
+		c.Region = region
+		return c
+	}
+	de := &pb.Address{Name: "test", Line1: "Hauptstr. 1", City: "Berlin", PostalCode: "10115", Country: "DE"}
+	usRates := []*pb.TaxRateReq{
+		{Country: "US", Name: "US", Rate: 500},
+		{Country: "US", Region: "CA", Name: "California", Rate: 725},
+		{Country: "US", TaxClass: "food", Name: "US food", Rate: 0},
+	}
+	vat := []*pb.TaxRateReq{{Country: "DE", Name: "VAT", Rate: 1900, Inclusive: true}}
+	foodProduct := func(t *testing.T, srv *Server) {
+		_, err := srv.UpdateProduct(context.Background(), &pb.ProductReq{Id: 2, TaxClass: "Food"})
+		require.NoError(t, err)
+	}
+
+	tcs := []struct {
+		name  string
+		rates []*pb.TaxRateReq
+		setup func(*testing.T, *Server)
+		req   *pb.OrderReq
+		code  codes.Code
+		test  func(*testing.T, *pb.OrderRes)
+	}{
+		{
+			name:  "region rate",
+			rates: usRates,
+			req: &pb.OrderReq{
+				UserId:          1,
+				ShippingAddress: withRegion(us, "ca"),
+				Items:           []*pb.OrderItem{{ProductId: 1, Quantity: 2}},
+			},
+			test: func(t *testing.T, res *pb.OrderRes) {
+				// 7.25% of 39.98 is 2.89855
+				require.Equal(t, int64(290), res.TaxPrice.GetAmount())
+				require.Equal(t, int64(5288), res.TotalPrice.GetAmount())
+				require.Equal(t, int64(725), res.Items[0].TaxRate)
+				require.Equal(t, "US-CA", res.Items[0].TaxJurisdiction)
+				require.Equal(t, "US", res.ShippingAddress.GetCountry())
+				require.Equal(t, "CA", res.ShippingAddress.GetRegion())
+			},
+		},
+		{
+			name:  "country rate where the region has none",
+			rates: usRates,
+			req: &pb.OrderReq{
+				UserId:          1,
+				ShippingAddress: withRegion(us, "NY"),
+				Items:           []*pb.OrderItem{{ProductId: 1, Quantity: 2}},
+			},
+			test: func(t *testing.T, res *pb.OrderRes) {
+				require.Equal(t, int64(200), res.TaxPrice.GetAmount())
+				require.Equal(t, "US", res.Items[0].TaxJurisdiction)
+			},
+		},
+		{
+			name:  "tax class rate before the regional standard rate",
+			rates: usRates,
+			setup: foodProduct,
+			req: &pb.OrderReq{
+				UserId:          1,
+				ShippingAddress: withRegion(us, "CA"),
+				Items:           []*pb.OrderItem{{ProductId: 1, Quantity: 1}, {ProductId: 2, Quantity: 1}},
+			},
+			test: func(t *testing.T, res *pb.OrderRes) {
+				require.Equal(t, "US-CA", res.Items[0].TaxJurisdiction)
+				require.Equal(t, int64(145), res.Items[0].TaxPrice.GetAmount())
+				require.Equal(t, "US", res.Items[1].TaxJurisdiction)
+				require.Zero(t, res.Items[1].TaxPrice.GetAmount())
+				require.Equal(t, int64(145), res.TaxPrice.GetAmount())
+				require.Equal(t, int64(7694), res.TotalPrice.GetAmount())
+			},
+		},
+		{
+			name:  "discount taxed on the lines it was taken off",
+			rates: usRates,
+			setup: func(t *testing.T, srv *Server) {
+				foodProduct(t, srv)
+				_, err := srv.CreatePromotion(context.Background(), &pb.PromotionReq{Name: "10 off", Type: pb.PromotionType_FIXED_AMOUNT, DiscountAmount: usd(1000)})
+				require.NoError(t, err)
+			},
+			req: &pb.OrderReq{
+				UserId:          1,
+				ShippingAddress: us,
+				Items:           []*pb.OrderItem{{ProductId: 1, Quantity: 1}, {ProductId: 2, Quantity: 1}},
+			},
+			test: func(t *testing.T, res *pb.OrderRes) {
+				// 3.05 of the 10.00 falls on the 19.99 line, whose 5% of
+				// 16.94 is 0.847
+				require.Equal(t, int64(85), res.TaxPrice.GetAmount())
+				require.Equal(t, int64(6634), res.TotalPrice.GetAmount())
+			},
+		},
+		{
+			name:  "tax-inclusive price",
+			rates: vat,
+			req: &pb.OrderReq{
+				UserId:          1,
+				ShippingAddress: de,
+				Items:           []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+			},
+			test: func(t *testing.T, res *pb.OrderRes) {
+				// 19.99 contains 19.99 * 19/119 = 3.1916 of VAT
+				require.Equal(t, int64(319), res.TaxPrice.GetAmount())
+				require.True(t, res.Items[0].TaxInclusive)
+				require.Equal(t, int64(2999), res.TotalPrice.GetAmount())
+			},
+		},
+		{
+			name:  "tax exempt customer",
+			rates: vat,
+			setup: func(t *testing.T, srv *Server) {
+				_, err := srv.SetUserTaxExempt(context.Background(), &pb.UserReq{Id: 1, TaxExempt: true})
+				require.NoError(t, err)
+			},
+			req: &pb.OrderReq{
+				UserId:          1,
+				ShippingAddress: de,
+				Items:           []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+			},
+			test: func(t *testing.T, res *pb.OrderRes) {
+				require.Zero(t, res.TaxPrice.GetAmount())
+				require.Equal(t, "exempt", res.Items[0].TaxJurisdiction)
+				require.Equal(t, int64(2680), res.TotalPrice.GetAmount())
+			},
+		},
+		{
+			name: "no rate configured",
+			req: &pb.OrderReq{
+				UserId: 1,
+				Items:  []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+			},
+			test: func(t *testing.T, res *pb.OrderRes) {
+				require.Equal(t, int64(taxRateBasisPoints), res.Items[0].TaxRate)
+				require.Equal(t, "default", res.Items[0].TaxJurisdiction)
+				require.Equal(t, int64(200), res.TaxPrice.GetAmount())
+			},
+		},
+		{
+			name: "invalid country",
+			req: &pb.OrderReq{
+				UserId:          1,
+				ShippingAddress: &pb.Address{Country: "USA"},
+				Items:           []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+			},
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			srv, _ := newTestServer(t)
+			for _, r := range tc.rates {
+				_, err := srv.CreateTaxRate(ctx, r)
+				require.NoError(t, err)
+			}
+			if tc.setup != nil {
+				tc.setup(t, srv)
+			}
+
+			res, err := srv.CreateOrder(ctx, tc.req)
+			require.Equal(t, tc.code, status.Code(err), "unexpected error: %v", err)
+			if tc.test != nil {
+				tc.test(t, res)
+			}
+		})
+	}
+}
+
+func TestTaxRates(t *testing.T) {
+	ctx := context.Background()
+	srv, _ := newTestServer(t)
+
+	_, err := srv.CreateTaxRate(ctx, &pb.TaxRateReq{Country: "US", Name: "US", Rate: 10001})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "rate above 100%")
+	_, err = srv.CreateTaxRate(ctx, &pb.TaxRateReq{Region: "CA", Name: "California", Rate: 725})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "region without country")
+	_, err = srv.CreateTaxRate(ctx, &pb.TaxRateReq{Country: "US", TaxClass: "food & drink", Name: "US", Rate: 500})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "invalid tax class")
+
+	ca, err := srv.CreateTaxRate(ctx, &pb.TaxRateReq{Country: "us", Region: "ca", Name: "California", Rate: 725})
+	require.NoError(t, err)
+	require.Equal(t, "US", ca.Country)
+	require.Equal(t, "CA", ca.Region)
+	require.Equal(t, storer.DefaultTaxClass, ca.TaxClass)
+	_, err = srv.CreateTaxRate(ctx, &pb.TaxRateReq{Country: "US", Region: "CA", TaxClass: "standard", Name: "again", Rate: 800})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	order, err := srv.CreateOrder(ctx, &pb.OrderReq{
+		UserId:          1,
+		ShippingAddress: &pb.Address{Country: "US", Region: "CA"},
+		Items:           []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+	})
+	require.NoError(t, err)
+
+	// orders keep the rate they were taxed at
+	updated, err := srv.UpdateTaxRate(ctx, &pb.TaxRateReq{Id: ca.Id, Country: "US", Region: "CA", Name: "California", Rate: 800})
+	require.NoError(t, err)
+	require.Equal(t, int64(800), updated.Rate)
+	got, err := srv.GetOrder(ctx, &pb.OrderReq{Id: order.Id, UserId: 1})
+	require.NoError(t, err)
+	require.Equal(t, int64(725), got.Items[0].TaxRate)
+	require.Equal(t, "US-CA", got.Items[0].TaxJurisdiction)
+	require.Equal(t, int64(145), got.Items[0].TaxPrice.GetAmount())
+
+	list, err := srv.ListTaxRates(ctx, &pb.ListTaxRatesReq{})
+	require.NoError(t, err)
+	require.Len(t, list.TaxRates, 1)
+
+	_, err = srv.DeleteTaxRate(ctx, &pb.TaxRateReq{Id: ca.Id})
+	require.NoError(t, err)
+	_, err = srv.DeleteTaxRate(ctx, &pb.TaxRateReq{Id: ca.Id})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = srv.SetUserTaxExempt(ctx, &pb.UserReq{Id: 42, TaxExempt: true})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestShipping(t *testing.T) {
+	us := &pb.Address{Name: "test", Line1: "1 Main St", City: "San Francisco", PostalCode: "94105", Country: "US"}
+	nyc := &pb.Address{Name: "test", Line1: "1 Broadway", City: "New York", PostalCode: "10001", Country: "US"}
+	de := &pb.Address{Name: "test", Line1: "Hauptstr. 1", City: "Berlin", PostalCode: "10115", Country: "DE"}
+
+	// zones and methods are created in order, so Standard is method 1,
+	// Express 2, Local 3 and International 4
+	setupRates := func(t *testing.T, srv *Server) {
+		ctx := context.Background()
+		zones := []struct {
+			zone    *pb.ShippingZoneReq
+			methods []*pb.ShippingMethodReq
+		}{
+			{
+				zone: &pb.ShippingZoneReq{Name: "US", Countries: []string{"US"}},
+				methods: []*pb.ShippingMethodReq{
+					{Name: "Standard", Tiers: []*pb.ShippingTier{{Min: 0, Price: usd(800)}, {Min: 5000, Price: usd(500)}}, FreeAbove: usd(10000)},
+					{Name: "Express", Basis: pb.ShippingBasis_WEIGHT, Tiers: []*pb.ShippingTier{{Min: 0, Price: usd(1500)}, {Min: 2000, Price: usd(2500)}}},
+				},
+			},
+			{
+				zone:    &pb.ShippingZoneReq{Name: "New York City", Countries: []string{"US"}, PostalPrefixes: []string{"100"}},
+				methods: []*pb.ShippingMethodReq{{Name: "Local", Tiers: []*pb.ShippingTier{{Min: 0, Price: usd(300)}}}},
+			},
+			{
+				zone:    &pb.ShippingZoneReq{Name: "Rest of world"},
+				methods: []*pb.ShippingMethodReq{{Name: "International", Tiers: []*pb.ShippingTier{{Min: 0, Price: usd(3000)}}}},
+			},
+		}
+		for _, z := range zones {
+			zone, err := srv.CreateShippingZone(ctx, z.zone)
+			require.NoError(t, err)
+			for _, m := range z.methods {
+				m.ZoneId = zone.Id
+				_, err := srv.CreateShippingMethod(ctx, m)
+				require.NoError(t, err)
+			}
+		}
+		for _, p := range []*pb.ProductReq{{Id: 1, Weight: proto.Int64(500)}, {Id: 2, Weight: proto.Int64(1500)}} {
+			_, err := srv.UpdateProduct(ctx, p)
+			require.NoError(t, err)
+		}
+	}
+	saveAddress := func(userID int64, a *pb.Address) func(*testing.T, *Server) {
+		return func(t *testing.T, srv *Server) {
+			_, err := srv.CreateAddress(context.Background(), &pb.AddressReq{UserId: userID, Address: a})
+			require.NoError(t, err)
+		}
+	}
+
+	tcs := []struct {
+		name  string
+		setup []func(*testing.T, *Server)
+		req   *pb.OrderReq
+		code  codes.Code
+		test  func(*testing.T, *pb.OrderRes)
+	}{
+		{
+			name:  "cheapest method of the country zone",
+			setup: []func(*testing.T, *Server){setupRates},
+			req: &pb.OrderReq{
+				UserId:          1,
+				ShippingAddress: us,
+				Items:           []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+			},
+			test: func(t *testing.T, res *pb.OrderRes) {
+				require.Equal(t, "Standard", res.ShippingMethod)
+				require.Equal(t, int64(800), res.ShippingPrice.GetAmount())
+				require.Equal(t, int64(2999), res.TotalPrice.GetAmount())
+				require.Equal(t, "94105", res.BillingAddress.GetPostalCode())
+			},
+		},
+		{
+			name:  "postal prefix zone before the country zone",
+			setup: []func(*testing.T, *Server){setupRates},
+			req: &pb.OrderReq{
+				UserId:          1,
+				ShippingAddress: nyc,
+				Items:           []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+			},
+			test: func(t *testing.T, res *pb.OrderRes) {
+				require.Equal(t, "Local", res.ShippingMethod)
+				require.Equal(t, int64(300), res.ShippingPrice.GetAmount())
+			},
+		},
+		{
+			name:  "zone covering the rest of the world",
+			setup: []func(*testing.T, *Server){setupRates},
+			req: &pb.OrderReq{
+				UserId:          1,
+				ShippingAddress: de,
+				Items:           []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+			},
+			test: func(t *testing.T, res *pb.OrderRes) {
+				require.Equal(t, "International", res.ShippingMethod)
+				require.Equal(t, int64(3000), res.ShippingPrice.GetAmount())
+			},
+		},
+		{
+			name:  "subtotal tier",
+			setup: []func(*testing.T, *Server){setupRates},
+			req: &pb.OrderReq{
+				UserId:          1,
+				ShippingAddress: us,
+				Items:           []*pb.OrderItem{{ProductId: 1, Quantity: 3}},
+			},
+			test: func(t *testing.T, res *pb.OrderRes) {
+				require.Equal(t, int64(500), res.ShippingPrice.GetAmount())
+			},
+		},
+		{
+			name:  "free above the method's threshold",
+			setup: []func(*testing.T, *Server){setupRates},
+			req: &pb.OrderReq{
+				UserId:          1,
+				ShippingAddress: us,
+				Items:           []*pb.OrderItem{{ProductId: 1, Quantity: 6}},
+			},
+			test: func(t *testing.T, res *pb.OrderRes) {
+				require.Equal(t, "Standard", res.ShippingMethod)
+				require.Zero(t, res.ShippingPrice.GetAmount())
+			},
+		},
+		{
+			name:  "chosen method priced by weight",
+			setup: []func(*testing.T, *Server){setupRates},
+			req: &pb.OrderReq{
+				UserId:           1,
+				ShippingAddress:  us,
+				ShippingMethodId: 2,
+				Items:            []*pb.OrderItem{{ProductId: 1, Quantity: 1}, {ProductId: 2, Quantity: 1}},
+			},
+			test: func(t *testing.T, res *pb.OrderRes) {
+				require.Equal(t, "Express", res.ShippingMethod)
+				require.Equal(t, int64(2500), res.ShippingPrice.GetAmount())
+			},
+		},
+		{
+			name:  "method not shipping to the address",
+			setup: []func(*testing.T, *Server){setupRates},
+			req: &pb.OrderReq{
+				UserId:           1,
+				ShippingAddress:  de,
+				ShippingMethodId: 1,
+				Items:            []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			name:  "no address to ship to",
+			setup: []func(*testing.T, *Server){setupRates},
+			req: &pb.OrderReq{
+				UserId: 1,
+				Items:  []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			name:  "default address",
+			setup: []func(*testing.T, *Server){setupRates, saveAddress(1, nyc)},
+			req: &pb.OrderReq{
+				UserId: 1,
+				Items:  []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+			},
+			test: func(t *testing.T, res *pb.OrderRes) {
+				require.Equal(t, "10001", res.ShippingAddress.GetPostalCode())
+				require.Equal(t, "10001", res.BillingAddress.GetPostalCode())
+				require.Equal(t, "Local", res.ShippingMethod)
+			},
+		},
+		{
+			name:  "saved addresses by id",
+			setup: []func(*testing.T, *Server){saveAddress(1, us), saveAddress(1, de)},
+			req: &pb.OrderReq{
+				UserId:            1,
+				ShippingAddressId: 2,
+				BillingAddressId:  1,
+				Items:             []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+			},
+			test: func(t *testing.T, res *pb.OrderRes) {
+				require.Equal(t, "DE", res.ShippingAddress.GetCountry())
+				require.Equal(t, "US", res.BillingAddress.GetCountry())
+				// no zones are configured, so the flat rate applies
+				require.Equal(t, flatShippingName, res.ShippingMethod)
+				require.Equal(t, flatShippingPrice.Amount, res.ShippingPrice.GetAmount())
+			},
+		},
+		{
+			name:  "another user's address",
+			setup: []func(*testing.T, *Server){saveAddress(2, us)},
+			req: &pb.OrderReq{
+				UserId:            1,
+				ShippingAddressId: 1,
+				Items:             []*pb.OrderItem{{ProductId: 1, Quantity: 1}},
+			},
+			code: codes.PermissionDenied,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			srv, _ := newTestServer(t)
+			for _, setup := range tc.setup {
+				setup(t, srv)
+			}
+
+			res, err := srv.CreateOrder(ctx, tc.req)
+			require.Equal(t, tc.code, status.Code(err), "unexpected error: %v", err)
+			if tc.test != nil {
+				tc.test(t, res)
+			}
+		})
+	}
+}
+
+func TestAddresses(t *testing.T) {
+	ctx := context.Background()
+	srv, _ := newTestServer(t)
+	home := &pb.Address{Name: "test", Line1: "1 Main St", City: "Springfield", PostalCode: "12345", Country: "us"}
+
+	_, err := srv.CreateAddress(ctx, &pb.AddressReq{UserId: 1, Address: &pb.Address{Name: "test", City: "Springfield", Country: "US"}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "missing street")
+	_, err = srv.CreateAddress(ctx, &pb.AddressReq{UserId: 1})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "missing address")
+
+	first, err := srv.CreateAddress(ctx, &pb.AddressReq{UserId: 1, Label: "home", Address: home})
+	require.NoError(t, err)
+	require.True(t, first.IsDefault, "first address is the default")
+	require.Equal(t, "US", first.Address.GetCountry())
+	second, err := srv.CreateAddress(ctx, &pb.AddressReq{UserId: 1, Label: "work", Address: home})
+	require.NoError(t, err)
+	require.False(t, second.IsDefault)
+	third, err := srv.CreateAddress(ctx, &pb.AddressReq{UserId: 1, Label: "cabin", Address: home, IsDefault: true})
+	require.NoError(t, err)
+
+	list, err := srv.ListAddresses(ctx, &pb.AddressReq{UserId: 1})
+	require.NoError(t, err)
+	require.Len(t, list.Addresses, 3)
+	require.Equal(t, third.Id, list.Addresses[0].Id)
+	require.False(t, list.Addresses[1].IsDefault)
+
+	_, err = srv.UpdateAddress(ctx, &pb.AddressReq{Id: first.Id, UserId: 2, Address: home})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = srv.DeleteAddress(ctx, &pb.AddressReq{Id: 42, UserId: 1})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// orders keep the address they were placed with
+	order, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{{ProductId: 1, Quantity: 1}}})
+	require.NoError(t, err)
+	moved := proto.Clone(home).(*pb.Address)
+	moved.City = "Shelbyville"
+	updated, err := srv.UpdateAddress(ctx, &pb.AddressReq{Id: third.Id, UserId: 1, Label: "cabin", Address: moved})
+	require.NoError(t, err)
+	require.True(t, updated.IsDefault, "stays the default")
+	got, err := srv.GetOrder(ctx, &pb.OrderReq{Id: order.Id, UserId: 1})
+	require.NoError(t, err)
+	require.Equal(t, "Springfield", got.ShippingAddress.GetCity())
+
+	// deleting the default makes the oldest address left the default
+	_, err = srv.DeleteAddress(ctx, &pb.AddressReq{Id: third.Id, UserId: 1})
+	require.NoError(t, err)
+	list, err = srv.ListAddresses(ctx, &pb.AddressReq{UserId: 1})
+	require.NoError(t, err)
+	require.Len(t, list.Addresses, 2)
+	require.Equal(t, first.Id, list.Addresses[0].Id)
+	require.True(t, list.Addresses[0].IsDefault)
+}
+
+func TestShippingZones(t *testing.T) {
+	ctx := context.Background()
+	srv, _ := newTestServer(t)
+
+	_, err := srv.CreateShippingZone(ctx, &pb.ShippingZoneReq{Name: "US", Countries: []string{"USA"}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "invalid country")
+	_, err = srv.CreateShippingZone(ctx, &pb.ShippingZoneReq{Name: "London", PostalPrefixes: []string{"EC"}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "postal prefixes without country")
+
+	zone, err := srv.CreateShippingZone(ctx, &pb.ShippingZoneReq{Name: "UK", Countries: []string{"gb"}, PostalPrefixes: []string{"ec 1", "EC1"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"GB"}, zone.Countries)
+	require.Equal(t, []string{"EC1"}, zone.PostalPrefixes)
+
+	_, err = srv.CreateShippingMethod(ctx, &pb.ShippingMethodReq{ZoneId: 42, Name: "Standard", Tiers: []*pb.ShippingTier{{Price: usd(500)}}})
+	require.Equal(t, codes.NotFound, status.Code(err), "unknown zone")
+	_, err = srv.CreateShippingMethod(ctx, &pb.ShippingMethodReq{ZoneId: zone.Id, Name: "Standard"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "no tiers")
+	_, err = srv.CreateShippingMethod(ctx, &pb.ShippingMethodReq{ZoneId: zone.Id, Name: "Standard", Tiers: []*pb.ShippingTier{{Min: 1000, Price: usd(500)}, {Min: 1000, Price: usd(300)}}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "tiers not rising")
+	_, err = srv.CreateShippingMethod(ctx, &pb.ShippingMethodReq{ZoneId: zone.Id, Name: "Standard", Tiers: []*pb.ShippingTier{{Price: &pb.Money{Amount: 500, Currency: "EUR"}}}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "unsupported currency")
+
+	standard, err := srv.CreateShippingMethod(ctx, &pb.ShippingMethodReq{ZoneId: zone.Id, Name: "Standard", Tiers: []*pb.ShippingTier{{Price: usd(500)}}})
+	require.NoError(t, err)
+	require.True(t, standard.Active, "active by default")
+	express, err := srv.CreateShippingMethod(ctx, &pb.ShippingMethodReq{ZoneId: zone.Id, Name: "Express", Tiers: []*pb.ShippingTier{{Price: usd(1200)}}})
+	require.NoError(t, err)
+
+	// quotes price the cart, cheapest first
+	_, err = srv.AddCartItem(ctx, &pb.CartReq{UserId: 1, ProductId: 1, Quantity: 1})
+	require.NoError(t, err)
+	london := &pb.Address{Name: "test", Line1: "1 Fleet St", City: "London", PostalCode: "EC1A 1BB", Country: "GB"}
+	quote, err := srv.QuoteShipping(ctx, &pb.ShippingQuoteReq{UserId: 1, Address: london})
+	require.NoError(t, err)
+	require.Len(t, quote.Options, 2)
+	require.Equal(t, standard.Id, quote.Options[0].MethodId)
+	require.Equal(t, "UK", quote.Options[0].Zone)
+	require.Equal(t, express.Id, quote.Options[1].MethodId)
+	_, err = srv.QuoteShipping(ctx, &pb.ShippingQuoteReq{UserId: 1, Address: &pb.Address{Country: "GB", PostalCode: "SW1A 1AA"}})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "outside every zone")
+
+	// inactive methods are not offered
+	updated, err := srv.UpdateShippingMethod(ctx, &pb.ShippingMethodReq{Id: standard.Id, Name: "Standard", Tiers: []*pb.ShippingTier{{Price: usd(500)}}, Active: proto.Bool(false)})
+	require.NoError(t, err)
+	require.False(t, updated.Active)
+	require.Equal(t, zone.Id, updated.ZoneId)
+	quote, err = srv.QuoteShipping(ctx, &pb.ShippingQuoteReq{UserId: 1, Address: london})
+	require.NoError(t, err)
+	require.Len(t, quote.Options, 1)
+	require.Equal(t, express.Id, quote.Options[0].MethodId)
+
+	list, err := srv.ListShippingZones(ctx, &pb.ListShippingZonesReq{})
+	require.NoError(t, err)
+	require.Len(t, list.Zones, 1)
+	require.Len(t, list.Zones[0].Methods, 2)
+
+	_, err = srv.DeleteShippingZone(ctx, &pb.ShippingZoneReq{Id: zone.Id})
+	require.NoError(t, err)
+	_, err = srv.DeleteShippingMethod(ctx, &pb.ShippingMethodReq{Id: express.Id})
+	require.Equal(t, codes.NotFound, status.Code(err), "deleted with its zone")
+
+	// without zones the cart ships at the flat rate
+	quote, err = srv.QuoteShipping(ctx, &pb.ShippingQuoteReq{UserId: 1})
+	require.NoError(t, err)
+	require.Len(t, quote.Options, 1)
+	require.Zero(t, quote.Options[0].MethodId)
+	require.Equal(t, flatShippingPrice.Amount, quote.Options[0].Price.GetAmount())
+}
+
+func TestInvoices(t *testing.T) {
+	ctx := context.Background()
+	srv, _ := newTestServer(t)
+	newOrder := func(t *testing.T) int64 {
+		order, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{{ProductId: 1, Quantity: 2}}})
+		require.NoError(t, err)
+		return order.Id
+	}
+
+	first := newOrder(t)
+	_, err := srv.GetOrderInvoice(ctx, &pb.OrderReq{Id: first, UserId: 1})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "pending order")
+
+	_, err = srv.PayOrder(ctx, &pb.PaymentReq{OrderId: first, UserId: 1, Source: payments.FakeSourceApprove})
+	require.NoError(t, err)
+	inv, err := srv.GetOrderInvoice(ctx, &pb.OrderReq{Id: first, UserId: 1})
+	require.NoError(t, err)
+	require.Equal(t, "INV-000001", inv.Number)
+	require.Equal(t, "test shop", inv.Seller.GetName())
+	require.Equal(t, "test@example.com", inv.BuyerEmail)
+	require.Len(t, inv.Lines, 1)
+	require.Equal(t, "test product", inv.Lines[0].Description)
+	require.Equal(t, int64(3998), inv.Lines[0].Total.GetAmount())
+	order, err := srv.GetOrder(ctx, &pb.OrderReq{Id: first, UserId: 1})
+	require.NoError(t, err)
+	require.True(t, proto.Equal(order.TotalPrice, inv.TotalPrice))
+	require.Empty(t, inv.CreditNotes)
+
+	_, err = srv.GetOrderInvoice(ctx, &pb.OrderReq{Id: first, UserId: 2})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	again, err := srv.GetOrderInvoice(ctx, &pb.OrderReq{Id: first, UserId: 2, IsAdmin: true})
+	require.NoError(t, err)
+	require.Equal(t, inv.Id, again.Id, "invoiced once")
+
+	second := newOrder(t)
+	_, err = srv.PayOrder(ctx, &pb.PaymentReq{OrderId: second, UserId: 1, Source: payments.FakeSourceApprove})
+	require.NoError(t, err)
+	inv2, err := srv.GetOrderInvoice(ctx, &pb.OrderReq{Id: second, UserId: 1})
+	require.NoError(t, err)
+	require.Equal(t, "INV-000002", inv2.Number)
+
+	// refunding credits the invoice in full, once
+	_, err = srv.UpdateOrderStatus(ctx, &pb.OrderReq{Id: first, UserId: 2, IsAdmin: true, Status: pb.OrderStatus_REFUNDED, Reason: "damaged"})
+	require.NoError(t, err)
+	inv, err = srv.GetOrderInvoice(ctx, &pb.OrderReq{Id: first, UserId: 1})
+	require.NoError(t, err)
+	require.Len(t, inv.CreditNotes, 1)
+	note := inv.CreditNotes[0]
+	require.Equal(t, "CN-000001", note.Number)
+	require.Equal(t, "damaged", note.Reason)
+	require.True(t, proto.Equal(inv.TotalPrice, note.TotalPrice))
+	require.Len(t, note.Lines, 1)
+}
+
+func TestReturns(t *testing.T) {
+	ctx := context.Background()
+	srv, st := newTestServer(t)
+	admin := func(req *pb.ReturnReq) *pb.ReturnReq {
+		req.UserId, req.IsAdmin = 2, true
+		return req
+	}
+
+	// 2 x 19.99 plus 10% tax and flat shipping
+	order, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{{ProductId: 1, Quantity: 2}}})
+	require.NoError(t, err)
+	itemID := order.Items[0].Id
+	_, err = srv.PayOrder(ctx, &pb.PaymentReq{OrderId: order.Id, UserId: 1, Source: payments.FakeSourceApprove})
+	require.NoError(t, err)
+
+	req := &pb.ReturnReq{OrderId: order.Id, OrderItemId: itemID, UserId: 1, Quantity: 1, Reason: "too small", PhotosUrl: "https://example.com/photos"}
+	_, err = srv.CreateReturn(ctx, req)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "order not delivered")
+
+	for _, to := range []pb.OrderStatus{pb.OrderStatus_PROCESSING, pb.OrderStatus_SHIPPED, pb.OrderStatus_DELIVERED} {
+		_, err = srv.UpdateOrderStatus(ctx, &pb.OrderReq{Id: order.Id, UserId: 2, IsAdmin: true, Status: to})
+		require.NoError(t, err)
+	}
+
+	for _, bad := range []*pb.ReturnReq{
+		{OrderId: order.Id, OrderItemId: itemID, UserId: 1, Quantity: 1},
+		{OrderId: order.Id, OrderItemId: itemID, UserId: 1, Quantity: 3, Reason: "too small"},
+		{OrderId: order.Id, OrderItemId: itemID, UserId: 1, Quantity: 1, Reason: "too small", PhotosUrl: "ftp://example.com"},
+	} {
+		_, err = srv.CreateReturn(ctx, bad)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+	_, err = srv.CreateReturn(ctx, &pb.ReturnReq{OrderId: order.Id, OrderItemId: itemID, UserId: 2, Quantity: 1, Reason: "too small"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = srv.CreateReturn(ctx, &pb.ReturnReq{OrderId: order.Id, OrderItemId: 42, UserId: 1, Quantity: 1, Reason: "too small"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	r, err := srv.CreateReturn(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, "requested", r.Status)
+	// 19.99 and its 2.00 of tax
+	require.Equal(t, int64(2199), r.RefundAmount.GetAmount())
+	_, err = srv.CreateReturn(ctx, &pb.ReturnReq{OrderId: order.Id, OrderItemId: itemID, UserId: 1, Quantity: 2, Reason: "too small"})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "only one unit left to return")
+
+	_, err = srv.UpdateReturnStatus(ctx, &pb.ReturnReq{Id: r.Id, UserId: 1, Status: "approved"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = srv.UpdateReturnStatus(ctx, admin(&pb.ReturnReq{Id: r.Id, Status: "refunded"}))
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "refunding before receiving")
+	_, err = srv.UpdateReturnStatus(ctx, admin(&pb.ReturnReq{Id: r.Id, Status: "approved", Restock: true}))
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = srv.UpdateReturnStatus(ctx, admin(&pb.ReturnReq{Id: r.Id, Status: "approved"}))
+	require.NoError(t, err)
+	r, err = srv.UpdateReturnStatus(ctx, admin(&pb.ReturnReq{Id: r.Id, Status: "received", Restock: true, Note: "as new"}))
+	require.NoError(t, err)
+	require.True(t, r.Restocked)
+	require.Equal(t, "as new", r.Note)
+	p, err := st.GetProduct(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(9), p.CountInStock)
+
+	// refunding keeps what was recorded when the units were received
+	r, err = srv.UpdateReturnStatus(ctx, admin(&pb.ReturnReq{Id: r.Id, Status: "refunded"}))
+	require.NoError(t, err)
+	require.Equal(t, "refunded", r.Status)
+	require.True(t, r.Restocked)
+	require.Equal(t, "as new", r.Note)
+	ps, err := srv.ListOrderPayments(ctx, &pb.PaymentReq{OrderId: order.Id, UserId: 1})
+	require.NoError(t, err)
+	require.Equal(t, "captured", ps.Payments[0].Status)
+	require.Equal(t, int64(2199), ps.Payments[0].RefundedAmount.GetAmount())
+
+	inv, err := srv.GetOrderInvoice(ctx, &pb.OrderReq{Id: order.Id, UserId: 1})
+	require.NoError(t, err)
+	require.Len(t, inv.CreditNotes, 1)
+	require.Equal(t, int64(2199), inv.CreditNotes[0].TotalPrice.GetAmount())
+	require.Equal(t, int64(1), inv.CreditNotes[0].Lines[0].Quantity)
+
+	// refunding the return again has nothing left to give back
+	r, err = srv.UpdateReturnStatus(ctx, admin(&pb.ReturnReq{Id: r.Id, Status: "refunded"}))
+	require.NoError(t, err)
+	require.Equal(t, "refunded", r.Status)
+	ps, err = srv.ListOrderPayments(ctx, &pb.PaymentReq{OrderId: order.Id, UserId: 1})
+	require.NoError(t, err)
+	require.Equal(t, int64(2199), ps.Payments[0].RefundedAmount.GetAmount())
+	inv, err = srv.GetOrderInvoice(ctx, &pb.OrderReq{Id: order.Id, UserId: 1})
+	require.NoError(t, err)
+	require.Len(t, inv.CreditNotes, 1)
+
+	// refunding the order refunds and credits what the return did not
+	_, err = srv.UpdateOrderStatus(ctx, &pb.OrderReq{Id: order.Id, UserId: 2, IsAdmin: true, Status: pb.OrderStatus_REFUNDED})
+	require.NoError(t, err)
+	ps, err = srv.ListOrderPayments(ctx, &pb.PaymentReq{OrderId: order.Id, UserId: 1})
+	require.NoError(t, err)
+	require.Equal(t, "refunded", ps.Payments[0].Status)
+	inv, err = srv.GetOrderInvoice(ctx, &pb.OrderReq{Id: order.Id, UserId: 1})
+	require.NoError(t, err)
+	require.Len(t, inv.CreditNotes, 2)
+	require.Equal(t, inv.TotalPrice.GetAmount()-2199, inv.CreditNotes[1].TotalPrice.GetAmount())
+	require.Len(t, inv.CreditNotes[1].Lines, 1)
+	require.Equal(t, int64(1), inv.CreditNotes[1].Lines[0].Quantity)
+
+	listed, err := srv.ListReturns(ctx, &pb.ListReturnsReq{Status: "refunded"})
+	require.NoError(t, err)
+	require.Len(t, listed.Returns, 1)
+	_, err = srv.ListReturns(ctx, &pb.ListReturnsReq{Status: "lost"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// the customer heard of every change to the return
+	events, err := srv.ListNotificationEvents(ctx, &pb.ListNotificationEventsReq{})
+	require.NoError(t, err)
+	var changes []string
+	for _, e := range events.Events {
+		if e.ReturnId == r.Id {
+			changes = append(changes, e.ReturnStatus)
+		}
+	}
+	require.Equal(t, []string{"requested", "approved", "received", "refunded"}, changes)
+}
+
+func TestReturnCredit(t *testing.T) {
+	usd := func(cents int64) money.Money { return money.New(cents, money.DefaultCurrency) }
+	item := storer.OrderItem{ID: 1, Quantity: 3, Price: usd(1000), DiscountPrice: usd(300), TaxPrice: usd(270)}
+
+	// every unit gives back a third of the discount taken off the line
+	c := returnCredit(item, 1)
+	require.Equal(t, usd(1000), c.Items)
+	require.Equal(t, usd(100), c.Discount)
+	require.Equal(t, usd(90), c.Tax)
+	require.Equal(t, usd(990), c.Total)
+
+	item.TaxInclusive = true
+	c = returnCredit(item, 2)
+	require.Equal(t, usd(180), c.Tax)
+	require.Equal(t, usd(1800), c.Total)
+}
+
+func TestReturnDiscountedOrder(t *testing.T) {
+	ctx := context.Background()
+	srv, _ := newTestServer(t)
+
+	for _, p := range []*pb.PromotionReq{
+		{Name: "save 10%", Type: pb.PromotionType_PERCENTAGE, DiscountRate: 1000},
+		{Name: "free shipping", Type: pb.PromotionType_FREE_SHIPPING},
+	} {
+		_, err := srv.CreatePromotion(ctx, p)
+		require.NoError(t, err)
+	}
+
+	// 2 x 19.99 less 10% plus 10% tax, shipped for free
+	order, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{{ProductId: 1, Quantity: 2}}})
+	require.NoError(t, err)
+	require.Greater(t, order.ShippingPrice.GetAmount(), int64(0))
+	_, err = srv.PayOrder(ctx, &pb.PaymentReq{OrderId: order.Id, UserId: 1, Source: payments.FakeSourceApprove})
+	require.NoError(t, err)
+	for _, to := range []pb.OrderStatus{pb.OrderStatus_PROCESSING, pb.OrderStatus_SHIPPED, pb.OrderStatus_DELIVERED} {
+		_, err = srv.UpdateOrderStatus(ctx, &pb.OrderReq{Id: order.Id, UserId: 2, IsAdmin: true, Status: to})
+		require.NoError(t, err)
+	}
+
+	// the unit takes half the 4.00 off the line and none of the waived
+	// shipping: 19.99 - 2.00 and its 1.80 of tax
+	r, err := srv.CreateReturn(ctx, &pb.ReturnReq{OrderId: order.Id, OrderItemId: order.Items[0].Id, UserId: 1, Quantity: 1, Reason: "too small"})
+	require.NoError(t, err)
+	require.Equal(t, int64(1979), r.RefundAmount.GetAmount())
+}
+
+func TestWishlists(t *testing.T) {
+	ctx := context.Background()
+	srv, st := newTestServer(t)
+	_, err := st.CreateUser(ctx, &storer.User{Name: "other", Email: "other@example.com"})
+	require.NoError(t, err)
+
+	_, err = srv.CreateWishlist(ctx, &pb.WishlistReq{UserId: 1, Name: "  "})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	w, err := srv.CreateWishlist(ctx, &pb.WishlistReq{UserId: 1, Name: " birthday "})
+	require.NoError(t, err)
+	require.Equal(t, "birthday", w.Name)
+	require.Empty(t, w.ShareToken)
+	_, err = srv.CreateWishlist(ctx, &pb.WishlistReq{UserId: 1, Name: "birthday"})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+	// names are only unique per user
+	_, err = srv.CreateWishlist(ctx, &pb.WishlistReq{UserId: 2, Name: "birthday"})
+	require.NoError(t, err)
+
+	_, err = srv.AddWishlistItem(ctx, &pb.WishlistItemReq{WishlistId: w.Id, UserId: 2, ProductId: 1})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = srv.AddWishlistItem(ctx, &pb.WishlistItemReq{WishlistId: w.Id, UserId: 1, ProductId: 42})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = srv.AddWishlistItem(ctx, &pb.WishlistItemReq{WishlistId: w.Id, UserId: 1, ProductId: 1})
+	require.NoError(t, err)
+	w, err = srv.AddWishlistItem(ctx, &pb.WishlistItemReq{WishlistId: w.Id, UserId: 1, ProductId: 2})
+	require.NoError(t, err)
+	w, err = srv.AddWishlistItem(ctx, &pb.WishlistItemReq{WishlistId: w.Id, UserId: 1, ProductId: 2})
+	require.NoError(t, err)
+	require.Len(t, w.Items, 2)
+	require.Equal(t, "test product 2", w.Items[1].Name)
+	require.Equal(t, int64(4550), w.Items[1].Price.GetAmount())
+	require.Equal(t, int64(1), w.Items[1].CountInStock)
+	require.True(t, w.Items[1].Available)
+
+	// entries show the current price and stock
+	p, err := st.GetProduct(ctx, 2)
+	require.NoError(t, err)
+	p.Price = money.New(3999, money.DefaultCurrency)
+	_, err = st.UpdateProduct(ctx, p)
+	require.NoError(t, err)
+	_, err = srv.AdjustStock(ctx, &pb.StockReq{ProductId: 2, Quantity: -1, Reason: "sold in store"})
+	require.NoError(t, err)
+	w, err = srv.GetWishlist(ctx, &pb.WishlistReq{Id: w.Id, UserId: 1})
+	require.NoError(t, err)
+	require.Equal(t, int64(3999), w.Items[1].Price.GetAmount())
+	require.False(t, w.Items[1].Available)
+
+	_, err = srv.MoveWishlistItemToCart(ctx, &pb.WishlistItemReq{WishlistId: w.Id, UserId: 1, ProductId: 2})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "out of stock")
+	cart, err := srv.MoveWishlistItemToCart(ctx, &pb.WishlistItemReq{WishlistId: w.Id, UserId: 1, ProductId: 1, Quantity: 2})
+	require.NoError(t, err)
+	require.Len(t, cart.Items, 1)
+	require.Equal(t, int64(2), cart.Items[0].Quantity)
+	w, err = srv.GetWishlist(ctx, &pb.WishlistReq{Id: w.Id, UserId: 1})
+	require.NoError(t, err)
+	require.Len(t, w.Items, 1)
+	_, err = srv.MoveWishlistItemToCart(ctx, &pb.WishlistItemReq{WishlistId: w.Id, UserId: 1, ProductId: 1})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// sharing hands out a token anyone can view the list with
+	w, err = srv.UpdateWishlist(ctx, &pb.WishlistReq{Id: w.Id, UserId: 1, Shared: proto.Bool(true)})
+	require.NoError(t, err)
+	require.Equal(t, "birthday", w.Name)
+	require.Len(t, w.ShareToken, 2*shareTokenBytes)
+	token := w.ShareToken
+	shared, err := srv.GetSharedWishlist(ctx, &pb.SharedWishlistReq{ShareToken: token})
+	require.NoError(t, err)
+	require.Equal(t, w.Id, shared.Id)
+	require.Zero(t, shared.UserId)
+	require.Empty(t, shared.ShareToken)
+	require.Len(t, shared.Items, 1)
+
+	// renaming keeps the link, unsharing revokes it
+	w, err = srv.UpdateWishlist(ctx, &pb.WishlistReq{Id: w.Id, UserId: 1, Name: "later"})
+	require.NoError(t, err)
+	require.Equal(t, token, w.ShareToken)
+	_, err = srv.UpdateWishlist(ctx, &pb.WishlistReq{Id: w.Id, UserId: 1, Shared: proto.Bool(false)})
+	require.NoError(t, err)
+	_, err = srv.GetSharedWishlist(ctx, &pb.SharedWishlistReq{ShareToken: token})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	w, err = srv.UpdateWishlist(ctx, &pb.WishlistReq{Id: w.Id, UserId: 1, Shared: proto.Bool(true)})
+	require.NoError(t, err)
+	require.NotEqual(t, token, w.ShareToken)
+
+	_, err = srv.RemoveWishlistItem(ctx, &pb.WishlistItemReq{WishlistId: w.Id, UserId: 1, ProductId: 1})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	w, err = srv.RemoveWishlistItem(ctx, &pb.WishlistItemReq{WishlistId: w.Id, UserId: 1, ProductId: 2})
+	require.NoError(t, err)
+	require.Empty(t, w.Items)
+
+	list, err := srv.ListWishlists(ctx, &pb.WishlistReq{UserId: 1})
+	require.NoError(t, err)
+	require.Len(t, list.Wishlists, 1)
+
+	_, err = srv.DeleteWishlist(ctx, &pb.WishlistReq{Id: w.Id, UserId: 2})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = srv.DeleteWishlist(ctx, &pb.WishlistReq{Id: w.Id, UserId: 1})
+	require.NoError(t, err)
+	_, err = srv.GetWishlist(ctx, &pb.WishlistReq{Id: w.Id, UserId: 1})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestInventory(t *testing.T) {
+	ctx := context.Background()
+	srv, _ := newTestServer(t)
+
+	east, err := srv.CreateWarehouse(ctx, &pb.WarehouseReq{Code: " east ", Name: "East coast"})
+	require.NoError(t, err)
+	require.Equal(t, "EAST", east.Code)
+	_, err = srv.CreateWarehouse(ctx, &pb.WarehouseReq{Code: "East", Name: "East again"})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+	_, err = srv.CreateWarehouse(ctx, &pb.WarehouseReq{Code: "WEST"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "name is required")
+	ws, err := srv.ListWarehouses(ctx, &pb.ListWarehousesReq{})
+	require.NoError(t, err)
+	require.Len(t, ws.Warehouses, 2)
+
+	_, err = srv.UpdateProduct(ctx, &pb.ProductReq{Id: 1, CountInStock: 20})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "stock is kept by the ledger")
+
+	stock, err := srv.TransferStock(ctx, &pb.StockReq{ProductId: 1, ToWarehouseId: east.Id, Quantity: 4, Reason: "rebalance", ActorId: 1})
+	require.NoError(t, err)
+	require.Equal(t, int64(10), stock.CountInStock)
+	require.Len(t, stock.Levels, 2)
+	require.Equal(t, "MAIN", stock.Levels[0].WarehouseCode)
+	require.Equal(t, int64(6), stock.Levels[0].Quantity)
+	require.Equal(t, "EAST", stock.Levels[1].WarehouseCode)
+	require.Equal(t, int64(4), stock.Levels[1].Quantity)
+
+	for name, tc := range map[string]struct {
+		req  *pb.StockReq
+		code codes.Code
+	}{
+		"transfer too many":  {&pb.StockReq{ProductId: 1, WarehouseId: east.Id, ToWarehouseId: storer.MainWarehouseID, Quantity: 5, Reason: "rebalance"}, codes.FailedPrecondition},
+		"transfer to itself": {&pb.StockReq{ProductId: 1, ToWarehouseId: storer.MainWarehouseID, Quantity: 1, Reason: "rebalance"}, codes.InvalidArgument},
+		"transfer nowhere":   {&pb.StockReq{ProductId: 1, Quantity: 1, Reason: "rebalance"}, codes.InvalidArgument},
+	} {
+		_, err := srv.TransferStock(ctx, tc.req)
+		require.Equal(t, tc.code, status.Code(err), name)
+	}
+	for name, tc := range map[string]struct {
+		req  *pb.StockReq
+		code codes.Code
+	}{
+		"no reason":          {&pb.StockReq{ProductId: 1, Quantity: 1}, codes.InvalidArgument},
+		"no quantity":        {&pb.StockReq{ProductId: 1, Reason: "count"}, codes.InvalidArgument},
+		"negative receipt":   {&pb.StockReq{ProductId: 1, Kind: "receipt", Quantity: -1, Reason: "count"}, codes.InvalidArgument},
+		"sale":               {&pb.StockReq{ProductId: 1, Kind: "sale", Quantity: -1, Reason: "count"}, codes.InvalidArgument},
+		"unknown warehouse":  {&pb.StockReq{ProductId: 1, WarehouseId: 99, Quantity: 1, Reason: "count"}, codes.NotFound},
+		"unknown product":    {&pb.StockReq{ProductId: 99, Quantity: 1, Reason: "count"}, codes.NotFound},
+		"below zero":         {&pb.StockReq{ProductId: 1, WarehouseId: east.Id, Quantity: -5, Reason: "count"}, codes.FailedPrecondition},
+		"variant of a plain": {&pb.StockReq{ProductId: 1, VariantId: 1, Quantity: 1, Reason: "count"}, codes.NotFound},
+	} {
+		_, err := srv.AdjustStock(ctx, tc.req)
+		require.Equal(t, tc.code, status.Code(err), name)
+	}
+
+	stock, err = srv.AdjustStock(ctx, &pb.StockReq{ProductId: 1, WarehouseId: east.Id, Quantity: -2, Reason: "damaged", ActorId: 1})
+	require.NoError(t, err)
+	require.Equal(t, int64(8), stock.CountInStock)
+
+	// orders take stock from the warehouses in turn
+	order, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{{ProductId: 1, Quantity: 7}}})
+	require.NoError(t, err)
+	stock, err = srv.GetProductStock(ctx, &pb.StockReq{ProductId: 1})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), stock.CountInStock)
+	require.Len(t, stock.Levels, 1)
+	require.Equal(t, east.Id, stock.Levels[0].WarehouseId)
+
+	page, err := srv.ListStockMovements(ctx, &pb.ListStockMovementsReq{ProductId: 1, Limit: 4})
+	require.NoError(t, err)
+	require.Len(t, page.Movements, 4)
+	require.Equal(t, "sale", page.Movements[0].Kind)
+	require.Equal(t, order.Id, page.Movements[0].OrderId)
+	require.Equal(t, "adjustment", page.Movements[2].Kind)
+	require.Equal(t, "damaged", page.Movements[2].Reason)
+	require.Equal(t, int64(1), page.Movements[2].ActorId)
+	require.Equal(t, "transfer", page.Movements[3].Kind)
+	require.NotZero(t, page.NextBeforeId)
+
+	page, err = srv.ListStockMovements(ctx, &pb.ListStockMovementsReq{ProductId: 1, BeforeId: page.NextBeforeId, Limit: 4})
+	require.NoError(t, err)
+	require.Len(t, page.Movements, 2)
+	require.Equal(t, "receipt", page.Movements[1].Kind)
+	require.Zero(t, page.NextBeforeId)
+
+	page, err = srv.ListStockMovements(ctx, &pb.ListStockMovementsReq{ProductId: 1, WarehouseId: east.Id})
+	require.NoError(t, err)
+	require.Len(t, page.Movements, 3)
+	require.Equal(t, storer.MainWarehouseID, page.Movements[2].OtherWarehouseId)
+
+	// stock of the product itself has to go before it gains variants
+	bag, err := srv.CreateProduct(ctx, &pb.ProductReq{Name: "bag", Image: "bag.jpg", Price: usd(1000), Options: []string{"color"}, CountInStock: 3})
+	require.NoError(t, err)
+	_, err = srv.CreateVariant(ctx, &pb.VariantReq{ProductId: bag.Id, Sku: "BAG-RED", Options: map[string]string{"color": "Red"}})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = srv.AdjustStock(ctx, &pb.StockReq{ProductId: bag.Id, Quantity: -3, Reason: "moved to variants"})
+	require.NoError(t, err)
+	_, err = srv.CreateVariant(ctx, &pb.VariantReq{ProductId: bag.Id, Sku: "BAG-RED", Options: map[string]string{"color": "Red"}, CountInStock: proto.Int64(3)})
+	require.NoError(t, err)
+}
+
+func TestGiftCards(t *testing.T) {
+	ctx := context.Background()
+	srv, _ := newTestServer(t)
+	admin := func(req *pb.GiftCardReq) *pb.GiftCardReq {
+		req.UserId, req.IsAdmin = 2, true
+		return req
+	}
+	balance := func(t *testing.T, code string) int64 {
+		g, err := srv.GetGiftCard(ctx, &pb.GiftCardReq{Code: code, UserId: 1})
+		require.NoError(t, err)
+		return g.Balance.GetAmount()
+	}
+	credit := func(t *testing.T) int64 {
+		sc, err := srv.GetStoreCredit(ctx, &pb.StoreCreditReq{UserId: 1})
+		require.NoError(t, err)
+		return sc.Balance.GetAmount()
+	}
+	order := func(req *pb.OrderReq) (*pb.OrderRes, error) {
+		req.UserId = 1
+		req.Items = []*pb.OrderItem{{ProductId: 1, Quantity: 1}}
+		return srv.CreateOrder(ctx, req)
+	}
+
+	_, err := srv.CreateGiftCard(ctx, admin(&pb.GiftCardReq{Amount: usd(0)}))
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = srv.CreateGiftCard(ctx, admin(&pb.GiftCardReq{Code: "bad code!", Amount: usd(1000)}))
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	big, err := srv.CreateGiftCard(ctx, admin(&pb.GiftCardReq{Code: " gift-one ", Amount: usd(5000), Note: "goodwill"}))
+	require.NoError(t, err)
+	require.Equal(t, "GIFT-ONE", big.Code)
+	require.Equal(t, int64(2), big.IssuedBy)
+	require.Len(t, big.Transactions, 1)
+	_, err = srv.CreateGiftCard(ctx, admin(&pb.GiftCardReq{Code: "GIFT-ONE", Amount: usd(1000)}))
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	small, err := srv.CreateGiftCard(ctx, admin(&pb.GiftCardReq{Amount: usd(1000)}))
+	require.NoError(t, err)
+	require.Regexp(t, `^[A-Z2-9]{4}(-[A-Z2-9]{4}){3}$`, small.Code)
+
+	// customers only see the balance of cards they did not buy
+	got, err := srv.GetGiftCard(ctx, &pb.GiftCardReq{Code: strings.ToLower(small.Code), UserId: 1})
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), got.Balance.GetAmount())
+	require.Zero(t, got.Id)
+	require.Empty(t, got.Transactions)
+
+	_, err = srv.AdjustStoreCredit(ctx, &pb.StoreCreditReq{UserId: 1, Amount: usd(500)})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "no reason")
+	_, err = srv.AdjustStoreCredit(ctx, &pb.StoreCreditReq{UserId: 42, Amount: usd(500), Reason: "goodwill"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = srv.AdjustStoreCredit(ctx, &pb.StoreCreditReq{UserId: 1, Amount: usd(-500), Reason: "goodwill"})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	sc, err := srv.AdjustStoreCredit(ctx, &pb.StoreCreditReq{UserId: 1, Amount: usd(500), Reason: "goodwill", ActorId: 2})
+	require.NoError(t, err)
+	require.Equal(t, int64(500), sc.Balance.GetAmount())
+
+	_, err = order(&pb.OrderReq{GiftCardCodes: []string{"NOPE-NOPE"}})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = order(&pb.OrderReq{GiftCardCodes: []string{small.Code, small.Code}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = order(&pb.OrderReq{StoreCredit: usd(600)})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "more store credit than the user has")
+
+	// the card and store credit pay part, the provider the rest
+	o, err := order(&pb.OrderReq{GiftCardCodes: []string{small.Code}, StoreCredit: usd(500)})
+	require.NoError(t, err)
+	due := o.TotalPrice.GetAmount() - 1500
+	require.Equal(t, due, o.AmountDue.GetAmount())
+	require.Equal(t, pb.OrderStatus_PENDING, o.Status)
+	require.Zero(t, balance(t, small.Code))
+	require.Zero(t, credit(t))
+
+	p, err := srv.PayOrder(ctx, &pb.PaymentReq{OrderId: o.Id, UserId: 1, Source: payments.FakeSourceApprove})
+	require.NoError(t, err)
+	require.Equal(t, due, p.Amount.GetAmount())
+	ps, err := srv.ListOrderPayments(ctx, &pb.PaymentReq{OrderId: o.Id, UserId: 1})
+	require.NoError(t, err)
+	require.Len(t, ps.Payments, 3)
+	require.Equal(t, storer.GiftCardProvider, ps.Payments[0].Provider)
+	require.Equal(t, storer.StoreCreditProvider, ps.Payments[1].Provider)
+
+	// refunding into store credit gives the card back its balance and
+	// credits what the provider took
+	_, err = srv.UpdateOrderStatus(ctx, &pb.OrderReq{Id: o.Id, UserId: 2, IsAdmin: true, Status: pb.OrderStatus_REFUNDED, RefundToStoreCredit: true})
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), balance(t, small.Code))
+	require.Equal(t, 500+due, credit(t))
+	ps, err = srv.ListOrderPayments(ctx, &pb.PaymentReq{OrderId: o.Id, UserId: 1})
+	require.NoError(t, err)
+	for _, p := range ps.Payments {
+		require.Equal(t, string(payments.StatusRefunded), p.Status)
+	}
+
+	// a card that covers the total pays the order on the spot
+	o, err = order(&pb.OrderReq{GiftCardCodes: []string{"gift-one"}})
+	require.NoError(t, err)
+	require.Equal(t, pb.OrderStatus_PAID, o.Status)
+	require.Zero(t, o.AmountDue.GetAmount())
+	require.Equal(t, 5000-o.TotalPrice.GetAmount(), balance(t, big.Code))
+	_, err = srv.GetOrderInvoice(ctx, &pb.OrderReq{Id: o.Id, UserId: 1})
+	require.NoError(t, err)
+
+	// cancelling gives it back
+	_, err = srv.UpdateOrderStatus(ctx, &pb.OrderReq{Id: o.Id, UserId: 1, Status: pb.OrderStatus_CANCELLED})
+	require.NoError(t, err)
+	require.Equal(t, int64(5000), balance(t, big.Code))
+
+	_, err = srv.AdjustGiftCard(ctx, admin(&pb.GiftCardReq{Code: big.Code, Amount: usd(-6000), Reason: "correction"}))
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	adjusted, err := srv.AdjustGiftCard(ctx, admin(&pb.GiftCardReq{Code: big.Code, Amount: usd(-1000), Reason: "correction"}))
+	require.NoError(t, err)
+	require.Equal(t, int64(4000), adjusted.Balance.GetAmount())
+	require.Equal(t, "adjustment", adjusted.Transactions[len(adjusted.Transactions)-1].Kind)
+
+	expired, err := srv.UpdateGiftCard(ctx, admin(&pb.GiftCardReq{Code: big.Code, ExpiresAt: timestamppb.New(time.Now().Add(-time.Hour))}))
+	require.NoError(t, err)
+	require.True(t, expired.Expired)
+	require.Empty(t, expired.Note, "the note is replaced")
+	_, err = order(&pb.OrderReq{GiftCardCodes: []string{big.Code}})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// buying gift cards issues one per unit once the order is paid
+	gc, err := srv.CreateProduct(ctx, &pb.ProductReq{Name: "gift card", Image: "gift.jpg", Price: usd(2500), CountInStock: 10, GiftCard: proto.Bool(true)})
+	require.NoError(t, err)
+	require.True(t, gc.GiftCard)
+	o, err = srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{{ProductId: gc.Id, Quantity: 2}}})
+	require.NoError(t, err)
+	bought, err := srv.ListGiftCards(ctx, &pb.GiftCardReq{UserId: 1})
+	require.NoError(t, err)
+	require.Empty(t, bought.GiftCards)
+	_, err = srv.PayOrder(ctx, &pb.PaymentReq{OrderId: o.Id, UserId: 1, Source: payments.FakeSourceApprove})
+	require.NoError(t, err)
+	bought, err = srv.ListGiftCards(ctx, &pb.GiftCardReq{UserId: 1})
+	require.NoError(t, err)
+	require.Len(t, bought.GiftCards, 2)
+	require.Equal(t, int64(2500), bought.GiftCards[0].Balance.GetAmount())
+	require.Equal(t, o.Id, bought.GiftCards[0].OrderId)
+
+	// the purchaser sees their card in full
+	got, err = srv.GetGiftCard(ctx, &pb.GiftCardReq{Code: bought.GiftCards[0].Code, UserId: 1})
+	require.NoError(t, err)
+	require.Len(t, got.Transactions, 1)
+
+	// and refunding the order empties its cards
+	_, err = srv.UpdateOrderStatus(ctx, &pb.OrderReq{Id: o.Id, UserId: 2, IsAdmin: true, Status: pb.OrderStatus_REFUNDED})
+	require.NoError(t, err)
+	require.Zero(t, balance(t, bought.GiftCards[0].Code))
+	require.Zero(t, balance(t, bought.GiftCards[1].Code))
+}
+
+func TestLoyalty(t *testing.T) {
+	ctx := context.Background()
+	srv, _ := newTestServer(t)
+	admin := func(req *pb.ReturnReq) *pb.ReturnReq {
+		req.UserId, req.IsAdmin = 2, true
+		return req
+	}
+	loyalty := func(t *testing.T) *pb.LoyaltyRes {
+		l, err := srv.GetLoyalty(ctx, &pb.LoyaltyReq{UserId: 1})
+		require.NoError(t, err)
+		return l
+	}
+
+	o, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{{ProductId: 1, Quantity: 2}}})
+	require.NoError(t, err)
+	_, err = srv.PayOrder(ctx, &pb.PaymentReq{OrderId: o.Id, UserId: 1, Source: payments.FakeSourceApprove})
+	require.NoError(t, err)
+
+	// points are only earned once the order is delivered
+	for _, to := range []pb.OrderStatus{pb.OrderStatus_PROCESSING, pb.OrderStatus_SHIPPED} {
+		_, err = srv.UpdateOrderStatus(ctx, &pb.OrderReq{Id: o.Id, UserId: 2, IsAdmin: true, Status: to})
+		require.NoError(t, err)
+	}
+	require.Zero(t, loyalty(t).Points)
+	_, err = srv.UpdateOrderStatus(ctx, &pb.OrderReq{Id: o.Id, UserId: 2, IsAdmin: true, Status: pb.OrderStatus_DELIVERED})
+	require.NoError(t, err)
+
+	earned := o.TotalPrice.GetAmount() / 100
+	l := loyalty(t)
+	require.Equal(t, earned, l.Points)
+	require.Equal(t, earned, l.PointsValue.GetAmount())
+	require.Equal(t, "bronze", l.Tier)
+	require.Equal(t, "silver", l.NextTier)
+	require.Equal(t, 50000-o.TotalPrice.GetAmount(), l.NextTierSpend.GetAmount())
+	require.Len(t, l.Transactions, 1)
+	require.Equal(t, "earn", l.Transactions[0].Kind)
+	require.NotNil(t, l.Transactions[0].ExpiresAt)
+
+	// refunding a return takes back its share of the points
+	itemID := o.Items[0].Id
+	r, err := srv.CreateReturn(ctx, &pb.ReturnReq{OrderId: o.Id, OrderItemId: itemID, UserId: 1, Quantity: 1, Reason: "too small"})
+	require.NoError(t, err)
+	for _, st := range []string{"approved", "received", "refunded"} {
+		_, err = srv.UpdateReturnStatus(ctx, admin(&pb.ReturnReq{Id: r.Id, Status: st}))
+		require.NoError(t, err)
+	}
+	reversed := earned * r.RefundAmount.GetAmount() / o.TotalPrice.GetAmount()
+	require.Equal(t, earned-reversed, loyalty(t).Points)
+
+	// points pay at checkout, and cancelling gives them back
+	_, err = srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{{ProductId: 1, Quantity: 1}}, LoyaltyPoints: earned})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "more points than the user has")
+	_, err = srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{{ProductId: 1, Quantity: 1}}, LoyaltyPoints: -1})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	paid, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{{ProductId: 1, Quantity: 1}}, LoyaltyPoints: 10})
+	require.NoError(t, err)
+	require.Equal(t, paid.TotalPrice.GetAmount()-10, paid.AmountDue.GetAmount())
+	require.Equal(t, earned-reversed-10, loyalty(t).Points)
+	_, err = srv.UpdateOrderStatus(ctx, &pb.OrderReq{Id: paid.Id, UserId: 1, Status: pb.OrderStatus_CANCELLED})
+	require.NoError(t, err)
+	require.Equal(t, earned-reversed, loyalty(t).Points)
+
+	// refunding the order takes back the rest
+	_, err = srv.UpdateOrderStatus(ctx, &pb.OrderReq{Id: o.Id, UserId: 2, IsAdmin: true, Status: pb.OrderStatus_REFUNDED})
+	require.NoError(t, err)
+	l = loyalty(t)
+	require.Zero(t, l.Points)
+	require.Zero(t, l.YearSpend.GetAmount())
+	require.Equal(t, "reverse", l.Transactions[len(l.Transactions)-1].Kind)
+
+	// tiers follow the yearly spend and scale what is earned
+	lp := DefaultLoyaltyProgram()
+	tier, next := lp.tier(money.New(60000, money.DefaultCurrency))
+	require.Equal(t, "silver", tier.Name)
+	require.Equal(t, "gold", next.Name)
+	require.Equal(t, int64(12), lp.points(money.New(1000, money.DefaultCurrency), tier))
+	tier, next = lp.tier(money.New(200000, money.DefaultCurrency))
+	require.Equal(t, "gold", tier.Name)
+	require.Nil(t, next)
+}
+
+func TestLoyaltyTender(t *testing.T) {
+	ctx := context.Background()
+	srv, st := newTestServer(t)
+	srv.loyalty.PointValue = money.New(3, money.DefaultCurrency)
+	require.NoError(t, st.RecordLoyaltyTransaction(ctx, &storer.LoyaltyTransaction{UserID: 1, Kind: storer.BalanceAdjustment, Points: 100, Spend: money.New(0, money.DefaultCurrency), Reason: "welcome"}))
+
+	tcs := []struct {
+		name   string
+		points int64
+		left   int64
+		want   int64
+	}{
+		{name: "points cover less than is left", points: 2, left: 10, want: 2},
+		{name: "remainder is left to the next tender", points: 100, left: 10, want: 3},
+		{name: "less than a point is left", points: 100, left: 2},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := srv.loyaltyTender(ctx, 1, tc.points, money.New(tc.left, money.DefaultCurrency))
+			require.NoError(t, err)
+			if tc.want == 0 {
+				require.Nil(t, p)
+				return
+			}
+			require.Equal(t, tc.want, p.LoyaltyPoints())
+			require.Equal(t, money.New(3*tc.want, money.DefaultCurrency), p.Amount)
+		})
+	}
+}
+
+func TestScheduledPrices(t *testing.T) {
+	ctx := context.Background()
+	srv, _ := newTestServer(t)
+	now := time.Now()
+	productPrice := func(t *testing.T) (int64, int64) {
+		p, err := srv.GetProduct(ctx, &pb.ProductReq{Id: 1})
+		require.NoError(t, err)
+		return p.GetPrice().GetAmount(), p.GetCompareAtPrice().GetAmount()
+	}
+
+	for _, req := range []*pb.ScheduledPriceReq{
+		{ProductId: 1, Price: usd(999), StartsAt: timestamppb.New(now), EndsAt: timestamppb.New(now.Add(-time.Hour))},
+		{ProductId: 1, Price: usd(999), StartsAt: timestamppb.New(now)},
+		{ProductId: 1, Price: usd(-1), StartsAt: timestamppb.New(now), EndsAt: timestamppb.New(now.Add(time.Hour))},
+		{ProductId: 1, Price: usd(999), StartsAt: timestamppb.New(now), EndsAt: timestamppb.New(now.Add(time.Hour)), QuantityCap: proto.Int64(0)},
+	} {
+		_, err := srv.CreateScheduledPrice(ctx, req)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+	_, err := srv.CreateScheduledPrice(ctx, &pb.ScheduledPriceReq{ProductId: 99, Price: usd(999), StartsAt: timestamppb.New(now), EndsAt: timestamppb.New(now.Add(time.Hour))})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// a flash sale of 3 units, and a higher price scheduled for later
+	sale, err := srv.CreateScheduledPrice(ctx, &pb.ScheduledPriceReq{ProductId: 1, Price: usd(999), StartsAt: timestamppb.New(now.Add(-time.Minute)), EndsAt: timestamppb.New(now.Add(time.Hour)), QuantityCap: proto.Int64(3)})
+	require.NoError(t, err)
+	require.True(t, sale.Running)
+	_, err = srv.CreateScheduledPrice(ctx, &pb.ScheduledPriceReq{ProductId: 1, Price: usd(2499), StartsAt: timestamppb.New(now.Add(time.Hour)), EndsAt: timestamppb.New(now.Add(2 * time.Hour))})
+	require.NoError(t, err)
+
+	price, compareAt := productPrice(t)
+	require.Equal(t, int64(999), price)
+	require.Equal(t, int64(1999), compareAt)
+	cart, err := srv.AddCartItem(ctx, &pb.CartReq{UserId: 1, ProductId: 1, Quantity: 2})
+	require.NoError(t, err)
+	require.Equal(t, int64(999), cart.Items[0].Price.GetAmount())
+	require.Equal(t, int64(1999), cart.Items[0].CompareAtPrice.GetAmount())
+	require.Equal(t, int64(1998), cart.ItemsPrice.GetAmount())
+
+	// orders are priced at the sale price until its units are sold
+	_, err = srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{{ProductId: 1, Quantity: 1, Price: usd(1999)}}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	o, err := srv.CheckoutCart(ctx, &pb.CheckoutCartReq{UserId: 1})
+	require.NoError(t, err)
+	require.Equal(t, int64(999), o.Items[0].Price.GetAmount())
+	_, err = srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{{ProductId: 1, Quantity: 1}}})
+	require.NoError(t, err)
+	price, compareAt = productPrice(t)
+	require.Equal(t, int64(1999), price)
+	require.Zero(t, compareAt)
+
+	// cancelling gives the units back to the sale
+	_, err = srv.UpdateOrderStatus(ctx, &pb.OrderReq{Id: o.Id, UserId: 1, Status: pb.OrderStatus_CANCELLED})
+	require.NoError(t, err)
+	price, _ = productPrice(t)
+	require.Equal(t, int64(999), price)
+	_, err = srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{{ProductId: 1, Quantity: 3}}})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "only 2 units are left at the sale price")
+
+	list, err := srv.ListScheduledPrices(ctx, &pb.ScheduledPriceReq{ProductId: 1})
+	require.NoError(t, err)
+	require.Len(t, list.Prices, 2)
+	require.Equal(t, sale.Id, list.Prices[0].Id)
+	require.Equal(t, int64(1), list.Prices[0].QuantitySold)
+
+	_, err = srv.UpdateScheduledPrice(ctx, &pb.ScheduledPriceReq{Id: sale.Id, ProductId: 2, Price: usd(999), StartsAt: sale.StartsAt, EndsAt: sale.EndsAt})
+	require.Equal(t, codes.NotFound, status.Code(err), "the sale is not of product 2")
+	updated, err := srv.UpdateScheduledPrice(ctx, &pb.ScheduledPriceReq{Id: sale.Id, ProductId: 1, Price: usd(1499), StartsAt: sale.StartsAt, EndsAt: sale.EndsAt})
+	require.NoError(t, err)
+	require.Zero(t, updated.QuantityCap)
+	require.Equal(t, int64(1), updated.QuantitySold)
+	price, _ = productPrice(t)
+	require.Equal(t, int64(1499), price)
+
+	_, err = srv.DeleteScheduledPrice(ctx, &pb.ScheduledPriceReq{Id: sale.Id, ProductId: 1})
+	require.NoError(t, err)
+	price, compareAt = productPrice(t)
+	require.Equal(t, int64(1999), price)
+	require.Zero(t, compareAt)
+}
+
+func TestScheduledVariantPrices(t *testing.T) {
+	ctx := context.Background()
+	srv, _ := newTestServer(t)
+	now := time.Now()
+
+	shirt, err := srv.CreateProduct(ctx, &pb.ProductReq{Name: "T-shirt", Price: usd(1500), Options: []string{"size"}})
+	require.NoError(t, err)
+	m, err := srv.CreateVariant(ctx, &pb.VariantReq{ProductId: shirt.Id, Sku: "TS-M", Options: map[string]string{"size": "M"}, CountInStock: proto.Int64(5)})
+	require.NoError(t, err)
+	xl, err := srv.CreateVariant(ctx, &pb.VariantReq{ProductId: shirt.Id, Sku: "TS-XL", Options: map[string]string{"size": "XL"}, Price: usd(1800), CountInStock: proto.Int64(5)})
+	require.NoError(t, err)
+
+	_, err = srv.CreateScheduledPrice(ctx, &pb.ScheduledPriceReq{ProductId: 1, VariantId: m.Id, Price: usd(999), StartsAt: timestamppb.New(now), EndsAt: timestamppb.New(now.Add(time.Hour))})
+	require.Equal(t, codes.NotFound, status.Code(err), "variant of another product")
+
+	// a product-wide price leaves variants with their own price alone, and
+	// a variant price undercuts it
+	_, err = srv.CreateScheduledPrice(ctx, &pb.ScheduledPriceReq{ProductId: shirt.Id, Price: usd(1200), StartsAt: timestamppb.New(now.Add(-time.Minute)), EndsAt: timestamppb.New(now.Add(time.Hour))})
+	require.NoError(t, err)
+	_, err = srv.CreateScheduledPrice(ctx, &pb.ScheduledPriceReq{ProductId: shirt.Id, VariantId: m.Id, Price: usd(1000), StartsAt: timestamppb.New(now.Add(-time.Minute)), EndsAt: timestamppb.New(now.Add(time.Hour))})
+	require.NoError(t, err)
+
+	p, err := srv.GetProduct(ctx, &pb.ProductReq{Id: shirt.Id})
+	require.NoError(t, err)
+	require.Equal(t, int64(1200), p.Price.GetAmount())
+	require.Equal(t, int64(1500), p.CompareAtPrice.GetAmount())
+	prices := map[int64][2]int64{}
+	for _, v := range p.Variants {
+		prices[v.Id] = [2]int64{v.Price.GetAmount(), v.CompareAtPrice.GetAmount()}
+	}
+	require.Equal(t, map[int64][2]int64{m.Id: {1000, 1500}, xl.Id: {1800, 0}}, prices)
+
+	o, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: 1, Items: []*pb.OrderItem{{ProductId: shirt.Id, VariantId: m.Id, Quantity: 1}, {ProductId: shirt.Id, VariantId: xl.Id, Quantity: 1}}})
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), o.Items[0].Price.GetAmount())
+	require.Equal(t, int64(1800), o.Items[1].Price.GetAmount())
+}
@@ -0,0 +1,535 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/storer"
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Constants
+const (
+	maxShippingName  = 255
+	maxPostalPrefix  = 32
+	flatShippingName = "Standard"
+	flatShippingZone = "default"
+)
+
+// shippingOption is a way an order can ship and what it costs. MethodID is 0
+// for the flat rate charged while no shipping zones are configured.
+type shippingOption struct {
+	MethodID int64
+	Name     string
+	Zone     string
+	Price    money.Money
+}
+
+// CreateShippingZone adds a zone to the shipping rate table. Zones ship
+// nowhere until methods are added to them.
+func (s *Server) CreateShippingZone(ctx context.Context, req *pb.ShippingZoneReq) (*pb.ShippingZoneRes, error) {
+	z, err := toStorerShippingZone(req)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.storer.CreateShippingZone(ctx, z)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getShippingZoneRes(ctx, created.ID)
+}
+
+// ListShippingZones returns the shipping rate table, every zone with its
+// methods.
+func (s *Server) ListShippingZones(ctx context.Context, req *pb.ListShippingZonesReq) (*pb.ListShippingZonesRes, error) {
+	zones, err := s.storer.ListShippingZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(zones))
+	for _, z := range zones {
+		ids = append(ids, z.ID)
+	}
+	methods, err := s.storer.ListShippingMethods(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byZone := make(map[int64][]*storer.ShippingMethod, len(zones))
+	for _, m := range methods {
+		byZone[m.ZoneID] = append(byZone[m.ZoneID], m)
+	}
+
+	res := &pb.ListShippingZonesRes{
+		Zones: make([]*pb.ShippingZoneRes, 0, len(zones)),
+	}
+	for _, z := range zones {
+		res.Zones = append(res.Zones, toPBShippingZoneRes(z, byZone[z.ID]))
+	}
+
+	return res, nil
+}
+
+// UpdateShippingZone replaces the name and destinations of a zone, keeping
+// its methods.
+func (s *Server) UpdateShippingZone(ctx context.Context, req *pb.ShippingZoneReq) (*pb.ShippingZoneRes, error) {
+	existing, err := s.getShippingZone(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	z, err := toStorerShippingZone(req)
+	if err != nil {
+		return nil, err
+	}
+	z.ID = existing.ID
+	z.CreatedAt = existing.CreatedAt
+	z.UpdatedAt = toTimePtr(time.Now())
+	if _, err := s.storer.UpdateShippingZone(ctx, z); err != nil {
+		return nil, err
+	}
+
+	return s.getShippingZoneRes(ctx, z.ID)
+}
+
+// DeleteShippingZone removes a zone and its methods.
+func (s *Server) DeleteShippingZone(ctx context.Context, req *pb.ShippingZoneReq) (*pb.ShippingZoneRes, error) {
+	if _, err := s.getShippingZone(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.storer.DeleteShippingZone(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	return &pb.ShippingZoneRes{}, nil
+}
+
+func (s *Server) CreateShippingMethod(ctx context.Context, req *pb.ShippingMethodReq) (*pb.ShippingMethodRes, error) {
+	if _, err := s.getShippingZone(ctx, req.GetZoneId()); err != nil {
+		return nil, err
+	}
+
+	m, err := toStorerShippingMethod(req)
+	if err != nil {
+		return nil, err
+	}
+	m.Active = req.Active == nil || req.GetActive()
+
+	created, err := s.storer.CreateShippingMethod(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getShippingMethodRes(ctx, created.ID)
+}
+
+// UpdateShippingMethod replaces a method's name and rates. It stays in its
+// zone, and stays active unless active is sent. Orders keep the shipping
+// they were charged.
+func (s *Server) UpdateShippingMethod(ctx context.Context, req *pb.ShippingMethodReq) (*pb.ShippingMethodRes, error) {
+	existing, err := s.getShippingMethod(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := toStorerShippingMethod(req)
+	if err != nil {
+		return nil, err
+	}
+	m.ID = existing.ID
+	m.ZoneID = existing.ZoneID
+	m.Active = existing.Active
+	if req.Active != nil {
+		m.Active = req.GetActive()
+	}
+	m.CreatedAt = existing.CreatedAt
+	m.UpdatedAt = toTimePtr(time.Now())
+	if _, err := s.storer.UpdateShippingMethod(ctx, m); err != nil {
+		return nil, err
+	}
+
+	return s.getShippingMethodRes(ctx, m.ID)
+}
+
+func (s *Server) DeleteShippingMethod(ctx context.Context, req *pb.ShippingMethodReq) (*pb.ShippingMethodRes, error) {
+	if _, err := s.getShippingMethod(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.storer.DeleteShippingMethod(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	return &pb.ShippingMethodRes{}, nil
+}
+
+// QuoteShipping prices the ways the user's cart can ship to an address,
+// cheapest first. The address is a saved one, the one sent, or else the
+// user's default address. Promotions are not applied, so an order may ship
+// for less than quoted.
+func (s *Server) QuoteShipping(ctx context.Context, req *pb.ShippingQuoteReq) (*pb.ShippingQuoteRes, error) {
+	var addr *storer.Address
+	var err error
+	switch {
+	case req.GetAddressId() != 0:
+		a, err := s.getOwnAddress(ctx, req.GetUserId(), req.GetAddressId())
+		if err != nil {
+			return nil, err
+		}
+		addr = a.Address()
+	case req.GetAddress() != nil:
+		if addr, err = toStorerAddress(req.GetAddress()); err != nil {
+			return nil, err
+		}
+	default:
+		if addr, err = s.defaultAddress(ctx, req.GetUserId()); err != nil {
+			return nil, err
+		}
+	}
+
+	cart, err := s.getCartRes(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.GetItems()) == 0 {
+		return nil, status.Error(codes.FailedPrecondition, "cart is empty")
+	}
+	weight, err := s.cartWeight(ctx, cart)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := s.shippingOptions(ctx, addr, toMoney(cart.GetItemsPrice()), weight)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.ShippingQuoteRes{
+		Options: make([]*pb.ShippingOption, 0, len(options)),
+	}
+	if addr != nil {
+		res.Address = toPBAddress(addr)
+	}
+	for _, o := range options {
+		res.Options = append(res.Options, &pb.ShippingOption{
+			MethodId: o.MethodID,
+			Name:     o.Name,
+			Zone:     o.Zone,
+			Price:    toPBMoney(o.Price),
+		})
+	}
+
+	return res, nil
+}
+
+// cartWeight is the weight in grams of the available lines of a cart.
+func (s *Server) cartWeight(ctx context.Context, cart *pb.CartRes) (int64, error) {
+	ids := make([]int64, 0, len(cart.GetItems()))
+	for _, ci := range cart.GetItems() {
+		ids = append(ids, ci.GetProductId())
+	}
+	products, err := s.storer.GetProducts(ctx, ids)
+	if err != nil {
+		return 0, err
+	}
+	byID := make(map[int64]*storer.Product, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+
+	var weight int64
+	for _, ci := range cart.GetItems() {
+		if p, ok := byID[ci.GetProductId()]; ok && ci.GetPrice() != nil {
+			weight += p.Weight * ci.GetQuantity()
+		}
+	}
+
+	return weight, nil
+}
+
+// priceShipping charges q the method the customer chose, or the cheapest one
+// that ships to the address, on the items subtotal left after item
+// discounts.
+func (s *Server) priceShipping(ctx context.Context, q *orderQuote, itemDiscount money.Money) error {
+	var weight int64
+	for _, oi := range q.Items {
+		weight += q.Products[oi.ProductID].Weight * oi.Quantity
+	}
+
+	options, err := s.shippingOptions(ctx, q.ShippingAddress, q.ItemsPrice.Sub(itemDiscount), weight)
+	if err != nil {
+		return err
+	}
+
+	i := 0
+	if q.ShippingMethodID != 0 {
+		i = slices.IndexFunc(options, func(o shippingOption) bool { return o.MethodID == q.ShippingMethodID })
+		if i < 0 {
+			return status.Errorf(codes.FailedPrecondition, "shipping method %d is not available for this order", q.ShippingMethodID)
+		}
+	}
+	q.ShippingPrice = options[i].Price
+	q.ShippingMethod = options[i].Name
+
+	return nil
+}
+
+// shippingOptions prices the active methods of the zone addr is in for an
+// order of subtotal and weight, cheapest first. While no zones are
+// configured every order ships at a flat rate, free from
+// freeShippingThreshold.
+func (s *Server) shippingOptions(ctx context.Context, addr *storer.Address, subtotal money.Money, weight int64) ([]shippingOption, error) {
+	zones, err := s.storer.ListShippingZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(zones) == 0 {
+		price := flatShippingPrice
+		if subtotal.Cmp(freeShippingThreshold) >= 0 {
+			price = money.New(0, money.DefaultCurrency)
+		}
+		return []shippingOption{{Name: flatShippingName, Zone: flatShippingZone, Price: price}}, nil
+	}
+
+	if addr == nil {
+		return nil, status.Error(codes.FailedPrecondition, "a shipping address is needed to price shipping")
+	}
+	zone := matchShippingZone(zones, addr)
+	if zone == nil {
+		return nil, noShipping(addr)
+	}
+
+	methods, err := s.storer.ListShippingMethods(ctx, []int64{zone.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	var options []shippingOption
+	for _, m := range methods {
+		if !m.Active {
+			continue
+		}
+		price, ok := methodPrice(m, subtotal, weight)
+		if !ok {
+			continue
+		}
+		options = append(options, shippingOption{
+			MethodID: m.ID,
+			Name:     m.Name,
+			Zone:     zone.Name,
+			Price:    price,
+		})
+	}
+	if len(options) == 0 {
+		return nil, noShipping(addr)
+	}
+	slices.SortStableFunc(options, func(a, b shippingOption) int {
+		return cmp.Or(a.Price.Cmp(b.Price), cmp.Compare(a.MethodID, b.MethodID))
+	})
+
+	return options, nil
+}
+
+func noShipping(addr *storer.Address) error {
+	return status.Errorf(codes.FailedPrecondition, "no shipping method ships this order to %s", addr.Country)
+}
+
+// matchShippingZone picks the zone of an address: the zone whose postal
+// prefix matches most of its postal code, then a zone for its whole country,
+// then the zone covering every other destination.
+func matchShippingZone(zones []*storer.ShippingZone, addr *storer.Address) *storer.ShippingZone {
+	var match, country, rest *storer.ShippingZone
+	longest := 0
+	postalCode := strings.ToUpper(strings.ReplaceAll(addr.PostalCode, " ", ""))
+	for _, z := range zones {
+		if len(z.Countries) == 0 {
+			if rest == nil {
+				rest = z
+			}
+			continue
+		}
+		if !slices.Contains(z.Countries, addr.Country) {
+			continue
+		}
+		if len(z.PostalPrefixes) == 0 {
+			if country == nil {
+				country = z
+			}
+			continue
+		}
+		for _, prefix := range z.PostalPrefixes {
+			if len(prefix) > longest && strings.HasPrefix(postalCode, prefix) {
+				match, longest = z, len(prefix)
+			}
+		}
+	}
+
+	return cmp.Or(match, country, rest)
+}
+
+// methodPrice prices an order of subtotal and weight by m at its highest tier
+// the order reaches. Orders below the first tier cannot ship by m.
+func methodPrice(m *storer.ShippingMethod, subtotal money.Money, weight int64) (money.Money, bool) {
+	if m.FreeAbove != nil && subtotal.Cmp(*m.FreeAbove) >= 0 {
+		return money.New(0, money.DefaultCurrency), true
+	}
+
+	value := subtotal.Amount
+	if m.Basis == storer.ByWeight {
+		value = weight
+	}
+
+	var price money.Money
+	ok := false
+	for _, t := range m.Tiers {
+		if value < t.Min {
+			break
+		}
+		price, ok = t.Price, true
+	}
+
+	return price, ok
+}
+
+func (s *Server) getShippingZone(ctx context.Context, id int64) (*storer.ShippingZone, error) {
+	z, err := s.storer.GetShippingZone(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "shipping zone %d not found", id)
+		}
+		return nil, err
+	}
+
+	return z, nil
+}
+
+func (s *Server) getShippingZoneRes(ctx context.Context, id int64) (*pb.ShippingZoneRes, error) {
+	z, err := s.getShippingZone(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	methods, err := s.storer.ListShippingMethods(ctx, []int64{z.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBShippingZoneRes(z, methods), nil
+}
+
+func (s *Server) getShippingMethod(ctx context.Context, id int64) (*storer.ShippingMethod, error) {
+	m, err := s.storer.GetShippingMethod(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "shipping method %d not found", id)
+		}
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (s *Server) getShippingMethodRes(ctx context.Context, id int64) (*pb.ShippingMethodRes, error) {
+	m, err := s.getShippingMethod(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBShippingMethodRes(m), nil
+}
+
+// toStorerShippingZone validates req and maps it to a zone, normalizing
+// countries and postal prefixes to upper case.
+func toStorerShippingZone(req *pb.ShippingZoneReq) (*storer.ShippingZone, error) {
+	z := &storer.ShippingZone{
+		Name:           strings.TrimSpace(req.GetName()),
+		Countries:      storer.StringList{},
+		PostalPrefixes: storer.StringList{},
+	}
+	switch {
+	case z.Name == "":
+		return nil, status.Error(codes.InvalidArgument, "shipping zone name is empty")
+	case utf8.RuneCountInString(z.Name) > maxShippingName:
+		return nil, status.Errorf(codes.InvalidArgument, "shipping zone name is longer than %d characters", maxShippingName)
+	}
+
+	for _, c := range req.GetCountries() {
+		c = strings.ToUpper(strings.TrimSpace(c))
+		if !countryPattern.MatchString(c) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid country %q, use an ISO 3166-1 alpha-2 code", c)
+		}
+		if !slices.Contains(z.Countries, c) {
+			z.Countries = append(z.Countries, c)
+		}
+	}
+	for _, p := range req.GetPostalPrefixes() {
+		p = strings.ToUpper(strings.ReplaceAll(p, " ", ""))
+		switch {
+		case p == "":
+			return nil, status.Error(codes.InvalidArgument, "postal prefix is empty")
+		case len(p) > maxPostalPrefix:
+			return nil, status.Errorf(codes.InvalidArgument, "postal prefix is longer than %d characters", maxPostalPrefix)
+		}
+		if !slices.Contains(z.PostalPrefixes, p) {
+			z.PostalPrefixes = append(z.PostalPrefixes, p)
+		}
+	}
+	if len(z.PostalPrefixes) > 0 && len(z.Countries) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "postal prefixes need a country")
+	}
+
+	return z, nil
+}
+
+// toStorerShippingMethod validates req and maps it to a method. Tiers must
+// start at 0 or above and rise strictly.
+func toStorerShippingMethod(req *pb.ShippingMethodReq) (*storer.ShippingMethod, error) {
+	m := &storer.ShippingMethod{
+		ZoneID:    req.GetZoneId(),
+		Name:      strings.TrimSpace(req.GetName()),
+		Basis:     toStorerShippingBasis(req.GetBasis()),
+		FreeAbove: toMoneyPtr(req.GetFreeAbove()),
+	}
+	switch {
+	case m.Name == "":
+		return nil, status.Error(codes.InvalidArgument, "shipping method name is empty")
+	case utf8.RuneCountInString(m.Name) > maxShippingName:
+		return nil, status.Errorf(codes.InvalidArgument, "shipping method name is longer than %d characters", maxShippingName)
+	case len(req.GetTiers()) == 0:
+		return nil, status.Error(codes.InvalidArgument, "shipping method has no tiers")
+	case m.FreeAbove != nil && m.FreeAbove.Amount < 0:
+		return nil, status.Error(codes.InvalidArgument, "free_above is negative")
+	}
+	if err := checkCurrency(req.GetFreeAbove()); err != nil {
+		return nil, err
+	}
+
+	for i, t := range req.GetTiers() {
+		if err := checkCurrency(t.GetPrice()); err != nil {
+			return nil, err
+		}
+		tier := storer.ShippingTier{Min: t.GetMin(), Price: toMoney(t.GetPrice())}
+		switch {
+		case tier.Min < 0:
+			return nil, status.Errorf(codes.InvalidArgument, "invalid tier minimum %d", tier.Min)
+		case i > 0 && tier.Min <= m.Tiers[i-1].Min:
+			return nil, status.Error(codes.InvalidArgument, "tier minimums must rise strictly")
+		case tier.Price.Amount < 0:
+			return nil, status.Errorf(codes.InvalidArgument, "tier price %s is negative", tier.Price)
+		}
+		m.Tiers = append(m.Tiers, tier)
+	}
+
+	return m, nil
+}
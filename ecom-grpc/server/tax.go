@@ -0,0 +1,321 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/storer"
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Constants
+const (
+	maxTaxRateName  = 255
+	maxTaxClass     = 32
+	maxRegion       = 64
+	maxAddressField = 255
+	// a tax rate in basis points, i.e. 100%
+	maxTaxRate = 10000
+
+	// taxExemptJurisdiction is recorded on the lines of tax exempt customers.
+	taxExemptJurisdiction = "exempt"
+	// defaultJurisdiction is recorded on lines taxed at a rate that applies
+	// to every destination.
+	defaultJurisdiction = "default"
+)
+
+var (
+	countryPattern  = regexp.MustCompile(`^[A-Z]{2}$`)
+	taxClassPattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+)
+
+// CreateTaxRate adds the rate of a tax class in a jurisdiction.
+func (s *Server) CreateTaxRate(ctx context.Context, req *pb.TaxRateReq) (*pb.TaxRateRes, error) {
+	r, err := toStorerTaxRate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.storer.CreateTaxRate(ctx, r)
+	if err != nil {
+		if errors.Is(err, storer.ErrTaxRateExists) {
+			return nil, taxRateExists(r)
+		}
+		return nil, err
+	}
+
+	return s.getTaxRateRes(ctx, created.ID)
+}
+
+func (s *Server) ListTaxRates(ctx context.Context, req *pb.ListTaxRatesReq) (*pb.ListTaxRatesRes, error) {
+	rates, err := s.storer.ListTaxRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.ListTaxRatesRes{
+		TaxRates: make([]*pb.TaxRateRes, 0, len(rates)),
+	}
+	for _, r := range rates {
+		res.TaxRates = append(res.TaxRates, toPBTaxRateRes(r))
+	}
+
+	return res, nil
+}
+
+// UpdateTaxRate replaces a tax rate with req. Orders keep the rates they were
+// taxed at.
+func (s *Server) UpdateTaxRate(ctx context.Context, req *pb.TaxRateReq) (*pb.TaxRateRes, error) {
+	existing, err := s.getTaxRate(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := toStorerTaxRate(req)
+	if err != nil {
+		return nil, err
+	}
+	r.ID = existing.ID
+	r.CreatedAt = existing.CreatedAt
+	r.UpdatedAt = toTimePtr(time.Now())
+	if _, err := s.storer.UpdateTaxRate(ctx, r); err != nil {
+		if errors.Is(err, storer.ErrTaxRateExists) {
+			return nil, taxRateExists(r)
+		}
+		return nil, err
+	}
+
+	return s.getTaxRateRes(ctx, r.ID)
+}
+
+func (s *Server) DeleteTaxRate(ctx context.Context, req *pb.TaxRateReq) (*pb.TaxRateRes, error) {
+	if _, err := s.getTaxRate(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.storer.DeleteTaxRate(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	return &pb.TaxRateRes{}, nil
+}
+
+// SetUserTaxExempt flags a customer as exempt from tax, or clears the flag.
+// Only orders placed afterwards are affected.
+func (s *Server) SetUserTaxExempt(ctx context.Context, req *pb.UserReq) (*pb.UserRes, error) {
+	err := s.storer.SetUserTaxExempt(ctx, req.GetId(), req.GetTaxExempt())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "user %d not found", req.GetId())
+		}
+		return nil, err
+	}
+
+	u, err := s.storer.GetUserByID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBUserRes(u), nil
+}
+
+func (s *Server) getTaxRate(ctx context.Context, id int64) (*storer.TaxRate, error) {
+	r, err := s.storer.GetTaxRate(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "tax rate %d not found", id)
+		}
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (s *Server) getTaxRateRes(ctx context.Context, id int64) (*pb.TaxRateRes, error) {
+	r, err := s.getTaxRate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBTaxRateRes(r), nil
+}
+
+func taxRateExists(r *storer.TaxRate) error {
+	return status.Errorf(codes.AlreadyExists, "%s already has a rate for tax class %q", jurisdiction(r), r.TaxClass)
+}
+
+// toStorerTaxRate validates req and maps it to a tax rate, normalizing the
+// jurisdiction to upper case.
+func toStorerTaxRate(req *pb.TaxRateReq) (*storer.TaxRate, error) {
+	taxClass, err := checkTaxClass(req.GetTaxClass())
+	if err != nil {
+		return nil, err
+	}
+
+	r := &storer.TaxRate{
+		Country:   strings.ToUpper(strings.TrimSpace(req.GetCountry())),
+		Region:    strings.ToUpper(strings.TrimSpace(req.GetRegion())),
+		TaxClass:  taxClass,
+		Name:      strings.TrimSpace(req.GetName()),
+		Rate:      req.GetRate(),
+		Inclusive: req.GetInclusive(),
+	}
+	switch {
+	case r.Country != "" && !countryPattern.MatchString(r.Country):
+		return nil, status.Errorf(codes.InvalidArgument, "invalid country %q, use an ISO 3166-1 alpha-2 code", r.Country)
+	case r.Country == "" && r.Region != "":
+		return nil, status.Error(codes.InvalidArgument, "a region needs a country")
+	case utf8.RuneCountInString(r.Region) > maxRegion:
+		return nil, status.Errorf(codes.InvalidArgument, "region is longer than %d characters", maxRegion)
+	case r.Name == "":
+		return nil, status.Error(codes.InvalidArgument, "tax rate name is empty")
+	case utf8.RuneCountInString(r.Name) > maxTaxRateName:
+		return nil, status.Errorf(codes.InvalidArgument, "tax rate name is longer than %d characters", maxTaxRateName)
+	case r.Rate < 0 || r.Rate > maxTaxRate:
+		return nil, status.Errorf(codes.InvalidArgument, "rate must be between 0 and %d basis points", maxTaxRate)
+	}
+
+	return r, nil
+}
+
+// checkTaxClass normalizes a product's or rate's tax class, which defaults
+// to the standard class.
+func checkTaxClass(class string) (string, error) {
+	class = strings.ToLower(strings.TrimSpace(class))
+	switch {
+	case class == "":
+		return storer.DefaultTaxClass, nil
+	case len(class) > maxTaxClass:
+		return "", status.Errorf(codes.InvalidArgument, "tax class is longer than %d characters", maxTaxClass)
+	case !taxClassPattern.MatchString(class):
+		return "", status.Errorf(codes.InvalidArgument, "invalid tax class %q, use lower case letters, digits, dashes and underscores", class)
+	}
+
+	return class, nil
+}
+
+// toStorerAddress validates an address, which is optional. Only the country
+// is required, as it is all tax needs.
+func toStorerAddress(a *pb.Address) (*storer.Address, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	addr := &storer.Address{
+		Name:       strings.TrimSpace(a.GetName()),
+		Line1:      strings.TrimSpace(a.GetLine1()),
+		Line2:      strings.TrimSpace(a.GetLine2()),
+		City:       strings.TrimSpace(a.GetCity()),
+		Region:     strings.ToUpper(strings.TrimSpace(a.GetRegion())),
+		PostalCode: strings.TrimSpace(a.GetPostalCode()),
+		Country:    strings.ToUpper(strings.TrimSpace(a.GetCountry())),
+	}
+	if !countryPattern.MatchString(addr.Country) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid country %q, use an ISO 3166-1 alpha-2 code", addr.Country)
+	}
+	if utf8.RuneCountInString(addr.Region) > maxRegion {
+		return nil, status.Errorf(codes.InvalidArgument, "region is longer than %d characters", maxRegion)
+	}
+	for _, f := range []string{addr.Name, addr.Line1, addr.Line2, addr.City, addr.PostalCode} {
+		if utf8.RuneCountInString(f) > maxAddressField {
+			return nil, status.Errorf(codes.InvalidArgument, "address fields are limited to %d characters", maxAddressField)
+		}
+	}
+
+	return addr, nil
+}
+
+// taxOrder computes the tax of every line of q from the shipping address.
+// A line is taxed at the rate of its product's tax class in the most
+// specific jurisdiction that has one: the country and region, the whole
+// country, then every destination. Lines whose class has no rate there are
+// taxed at the standard class's, and without any matching rate at
+// taxRateBasisPoints. Tax is taken on
+// the line after its share of the item discounts, rounded per line. The
+// lines of tax exempt customers are not taxed, and their tax-inclusive
+// prices are reduced by the tax they contain.
+func (s *Server) taxOrder(ctx context.Context, q *orderQuote, userID int64) error {
+	u, err := s.storer.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return status.Errorf(codes.NotFound, "user %d not found", userID)
+		}
+		return err
+	}
+
+	var country, region string
+	if q.ShippingAddress != nil {
+		country, region = q.ShippingAddress.Country, q.ShippingAddress.Region
+	}
+	rates, err := s.storer.ListApplicableTaxRates(ctx, country, region)
+	if err != nil {
+		return err
+	}
+
+	q.TaxPrice = money.New(0, money.DefaultCurrency)
+	q.IncludedTax = money.New(0, money.DefaultCurrency)
+	for i := range q.Items {
+		oi := &q.Items[i]
+		taxClass := q.Products[oi.ProductID].TaxClass
+		if taxClass == "" {
+			taxClass = storer.DefaultTaxClass
+		}
+
+		oi.TaxRate, oi.TaxJurisdiction, oi.TaxInclusive = taxRateBasisPoints, defaultJurisdiction, false
+		if r := matchTaxRate(rates, country, region, taxClass); r != nil {
+			oi.TaxRate, oi.TaxJurisdiction, oi.TaxInclusive = r.Rate, jurisdiction(r), r.Inclusive
+		}
+
+		taxable := lineTotal(*oi).Sub(oi.DiscountPrice)
+		if oi.TaxInclusive {
+			oi.TaxPrice = taxable.IncludedRate(oi.TaxRate)
+			q.IncludedTax = q.IncludedTax.Add(oi.TaxPrice)
+		} else {
+			oi.TaxPrice = taxable.MulRate(oi.TaxRate)
+		}
+		if u.TaxExempt {
+			oi.TaxRate, oi.TaxJurisdiction = 0, taxExemptJurisdiction
+			oi.TaxPrice = money.New(0, money.DefaultCurrency)
+		}
+		q.TaxPrice = q.TaxPrice.Add(oi.TaxPrice)
+	}
+
+	return nil
+}
+
+// matchTaxRate picks the rate of a line from the rates that apply to the
+// destination.
+func matchTaxRate(rates []*storer.TaxRate, country, region, taxClass string) *storer.TaxRate {
+	levels := [][2]string{{country, region}, {country, ""}, {"", ""}}
+	for _, class := range []string{taxClass, storer.DefaultTaxClass} {
+		for _, l := range levels {
+			for _, r := range rates {
+				if r.Country == l[0] && r.Region == l[1] && r.TaxClass == class {
+					return r
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// jurisdiction names where a rate applies, e.g. "US-CA", "DE" or "default".
+func jurisdiction(r *storer.TaxRate) string {
+	switch {
+	case r.Country == "":
+		return defaultJurisdiction
+	case r.Region == "":
+		return r.Country
+	default:
+		return r.Country + "-" + r.Region
+	}
+}
@@ -0,0 +1,366 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/storer"
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Constants
+const (
+	maxSKU         = 64
+	maxOptionName  = 64
+	maxOptionValue = 64
+)
+
+// CreateVariant adds a variant to a product. Its options must name a value
+// for every option axis of the product, in a combination no other variant of
+// the product has.
+func (s *Server) CreateVariant(ctx context.Context, req *pb.VariantReq) (*pb.VariantRes, error) {
+	if err := checkCurrency(req.GetPrice()); err != nil {
+		return nil, err
+	}
+
+	p, err := s.getProduct(ctx, req.GetProductId())
+	if err != nil {
+		return nil, err
+	}
+	variants, err := s.storer.ListProductVariants(ctx, []int64{p.ID})
+	if err != nil {
+		return nil, err
+	}
+	// the stock of a product with variants is the stock of its variants,
+	// so units kept of the product itself would drop out of it
+	if len(variants) == 0 && p.CountInStock > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "product %d has %d units in stock that are not of any variant, adjust them to 0 first", p.ID, p.CountInStock)
+	}
+
+	v := &storer.Variant{
+		ProductID:    p.ID,
+		SKU:          strings.TrimSpace(req.GetSku()),
+		Options:      trimVariantOptions(req.GetOptions()),
+		Price:        toMoneyPtr(req.GetPrice()),
+		Image:        req.GetImage(),
+		CountInStock: req.GetCountInStock(),
+	}
+	if err := s.checkVariant(ctx, p, v); err != nil {
+		return nil, err
+	}
+
+	created, err := s.storer.CreateVariant(ctx, v)
+	if err != nil {
+		if errors.Is(err, storer.ErrSKUExists) {
+			return nil, status.Errorf(codes.AlreadyExists, "sku %q is already taken", v.SKU)
+		}
+		return nil, err
+	}
+
+	return s.toPBVariant(ctx, p, created)
+}
+
+// UpdateVariant changes the SKU, options, price or image of a variant. Its
+// stock is adjusted through AdjustStock.
+func (s *Server) UpdateVariant(ctx context.Context, req *pb.VariantReq) (*pb.VariantRes, error) {
+	if err := checkCurrency(req.GetPrice()); err != nil {
+		return nil, err
+	}
+	if req.CountInStock != nil {
+		return nil, status.Error(codes.InvalidArgument, "count_in_stock is kept by the inventory ledger, adjust the stock instead")
+	}
+
+	v, err := s.getVariant(ctx, req.GetProductId(), req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.getProduct(ctx, v.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetSku() != "" {
+		v.SKU = strings.TrimSpace(req.GetSku())
+	}
+	if len(req.GetOptions()) > 0 {
+		v.Options = trimVariantOptions(req.GetOptions())
+	}
+	switch {
+	case req.GetInheritPrice():
+		v.Price = nil
+	case req.Price != nil:
+		v.Price = toMoneyPtr(req.Price)
+	}
+	if req.Image != nil {
+		v.Image = req.GetImage()
+	}
+	if err := s.checkVariant(ctx, p, v); err != nil {
+		return nil, err
+	}
+
+	v.UpdatedAt = toTimePtr(time.Now())
+	if _, err := s.storer.UpdateVariant(ctx, v); err != nil {
+		if errors.Is(err, storer.ErrSKUExists) {
+			return nil, status.Errorf(codes.AlreadyExists, "sku %q is already taken", v.SKU)
+		}
+		return nil, err
+	}
+
+	return s.toPBVariant(ctx, p, v)
+}
+
+// DeleteVariant deletes a variant and drops it from carts. Variants that
+// were ordered cannot be deleted.
+func (s *Server) DeleteVariant(ctx context.Context, req *pb.VariantReq) (*pb.VariantRes, error) {
+	v, err := s.getVariant(ctx, req.GetProductId(), req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.storer.DeleteVariant(ctx, v.ID)
+	if err != nil {
+		if errors.Is(err, storer.ErrVariantInUse) {
+			return nil, status.Errorf(codes.FailedPrecondition, "variant %d was ordered and cannot be deleted, adjust its stock to 0 instead", v.ID)
+		}
+		return nil, err
+	}
+
+	return &pb.VariantRes{}, nil
+}
+
+func (s *Server) getProduct(ctx context.Context, id int64) (*storer.Product, error) {
+	p, err := s.storer.GetProduct(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "product %d not found", id)
+		}
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// getVariant loads a variant, which must belong to productID unless that is
+// 0.
+func (s *Server) getVariant(ctx context.Context, productID, id int64) (*storer.Variant, error) {
+	v, err := s.storer.GetVariant(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "variant %d not found", id)
+		}
+		return nil, err
+	}
+	if productID != 0 && v.ProductID != productID {
+		return nil, status.Errorf(codes.NotFound, "variant %d of product %d not found", id, productID)
+	}
+
+	return v, nil
+}
+
+// productVariants loads the variants of the given products keyed by product
+// id.
+func (s *Server) productVariants(ctx context.Context, productIDs []int64) (map[int64][]*storer.Variant, error) {
+	variants, err := s.storer.ListProductVariants(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byProduct := make(map[int64][]*storer.Variant, len(productIDs))
+	for _, v := range variants {
+		byProduct[v.ProductID] = append(byProduct[v.ProductID], v)
+	}
+
+	return byProduct, nil
+}
+
+// toPBProducts maps products together with their variant matrices, priced
+// at what they sell at now.
+func (s *Server) toPBProducts(ctx context.Context, products []*storer.Product) ([]*pb.ProductRes, error) {
+	ids := make([]int64, 0, len(products))
+	for _, p := range products {
+		ids = append(ids, p.ID)
+	}
+	variants, err := s.productVariants(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	sps, err := s.runningPrices(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*pb.ProductRes, 0, len(products))
+	for _, p := range products {
+		pr := toPBProductRes(p)
+		price, _ := sps.price(p, nil)
+		pr.Price = toPBMoney(price)
+		pr.CompareAtPrice = sps.compareAtPrice(p, nil)
+		for _, v := range variants[p.ID] {
+			pr.Variants = append(pr.Variants, toPBVariantRes(p, v, sps))
+		}
+		res = append(res, pr)
+	}
+
+	return res, nil
+}
+
+func (s *Server) toPBProduct(ctx context.Context, p *storer.Product) (*pb.ProductRes, error) {
+	res, err := s.toPBProducts(ctx, []*storer.Product{p})
+	if err != nil {
+		return nil, err
+	}
+
+	return res[0], nil
+}
+
+func (s *Server) toPBVariant(ctx context.Context, p *storer.Product, v *storer.Variant) (*pb.VariantRes, error) {
+	sps, err := s.runningPrices(ctx, []int64{p.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBVariantRes(p, v, sps), nil
+}
+
+// checkVariant validates a variant against its product and the product's
+// other variants.
+func (s *Server) checkVariant(ctx context.Context, p *storer.Product, v *storer.Variant) error {
+	switch {
+	case v.SKU == "":
+		return status.Error(codes.InvalidArgument, "sku is empty")
+	case utf8.RuneCountInString(v.SKU) > maxSKU:
+		return status.Errorf(codes.InvalidArgument, "sku is longer than %d characters", maxSKU)
+	case v.CountInStock < 0:
+		return status.Errorf(codes.InvalidArgument, "invalid count_in_stock %d", v.CountInStock)
+	case v.Price != nil && v.Price.Amount < 0:
+		return status.Errorf(codes.InvalidArgument, "invalid price %s", v.Price)
+	case len(p.Options) == 0:
+		return status.Errorf(codes.FailedPrecondition, "product %d has no options to vary in", p.ID)
+	}
+
+	if len(v.Options) != len(p.Options) {
+		return status.Errorf(codes.InvalidArgument, "variant options must set exactly %s", strings.Join(p.Options, ", "))
+	}
+	for _, name := range p.Options {
+		value, ok := v.Options[name]
+		switch {
+		case !ok:
+			return status.Errorf(codes.InvalidArgument, "variant options must set exactly %s", strings.Join(p.Options, ", "))
+		case value == "":
+			return status.Errorf(codes.InvalidArgument, "option %q is empty", name)
+		case utf8.RuneCountInString(value) > maxOptionValue:
+			return status.Errorf(codes.InvalidArgument, "option %q is longer than %d characters", name, maxOptionValue)
+		}
+	}
+
+	siblings, err := s.storer.ListProductVariants(ctx, []int64{p.ID})
+	if err != nil {
+		return err
+	}
+	for _, other := range siblings {
+		if other.ID != v.ID && maps.Equal(other.Options, v.Options) {
+			return status.Errorf(codes.AlreadyExists, "variant %d already has options %s", other.ID, variantLabel(p, other))
+		}
+	}
+
+	return nil
+}
+
+// checkProductOptions trims the option axes of a product and makes sure they
+// are set and distinct.
+func checkProductOptions(options []string) ([]string, error) {
+	var res []string
+	for _, o := range options {
+		o = strings.TrimSpace(o)
+		switch {
+		case o == "":
+			return nil, status.Error(codes.InvalidArgument, "option name is empty")
+		case utf8.RuneCountInString(o) > maxOptionName:
+			return nil, status.Errorf(codes.InvalidArgument, "option name is longer than %d characters", maxOptionName)
+		case slices.Contains(res, o):
+			return nil, status.Errorf(codes.InvalidArgument, "duplicate option %q", o)
+		}
+		res = append(res, o)
+	}
+
+	return res, nil
+}
+
+func trimVariantOptions(options map[string]string) storer.VariantOptions {
+	res := make(storer.VariantOptions, len(options))
+	for k, v := range options {
+		res[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return res
+}
+
+// resolveVariant finds the variant an order or cart line refers to. Products
+// with variants can only be bought as one of them, and products without
+// only as themselves.
+func resolveVariant(p *storer.Product, variants []*storer.Variant, variantID int64) (*storer.Variant, error) {
+	if variantID == 0 {
+		if len(variants) > 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "product %d comes in variants, choose one", p.ID)
+		}
+		return nil, nil
+	}
+
+	for _, v := range variants {
+		if v.ID == variantID {
+			return v, nil
+		}
+	}
+
+	return nil, status.Errorf(codes.NotFound, "variant %d of product %d not found", variantID, p.ID)
+}
+
+// variantPrice is the price of a variant, or of the product when it has no
+// variant or the variant does not override it.
+func variantPrice(p *storer.Product, v *storer.Variant) money.Money {
+	if v != nil && v.Price != nil {
+		return *v.Price
+	}
+	return p.Price
+}
+
+func variantImage(p *storer.Product, v *storer.Variant) string {
+	if v != nil && v.Image != "" {
+		return v.Image
+	}
+	return p.Image
+}
+
+func variantStock(p *storer.Product, v *storer.Variant) int64 {
+	if v != nil {
+		return v.CountInStock
+	}
+	return p.CountInStock
+}
+
+// variantLabel lists the option values of a variant in the order of the
+// product's option axes, e.g. "M / Red".
+func variantLabel(p *storer.Product, v *storer.Variant) string {
+	values := make([]string, 0, len(p.Options))
+	for _, name := range p.Options {
+		values = append(values, v.Options[name])
+	}
+	return strings.Join(values, " / ")
+}
+
+// variantName is the name an order or cart line shows, e.g.
+// "T-shirt - M / Red".
+func variantName(p *storer.Product, v *storer.Variant) string {
+	if v == nil {
+		return p.Name
+	}
+	return p.Name + " - " + variantLabel(p, v)
+}
@@ -0,0 +1,377 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/pb"
+	"github.com/OrkhanMehbaliyev/ecom-golang/ecom-grpc/storer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Constants
+const (
+	maxWishlistName = 128
+	// random bytes in a share token, hex encoded into twice as many
+	// characters
+	shareTokenBytes = 16
+)
+
+func (s *Server) CreateWishlist(ctx context.Context, req *pb.WishlistReq) (*pb.WishlistRes, error) {
+	name, err := wishlistName(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	w := &storer.Wishlist{UserID: req.GetUserId(), Name: name}
+	if req.GetShared() {
+		if w.ShareToken, err = newShareToken(); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := s.storer.CreateWishlist(ctx, w)
+	if err != nil {
+		return nil, wishlistError(err, name)
+	}
+
+	return s.getWishlistRes(ctx, created.ID)
+}
+
+func (s *Server) GetWishlist(ctx context.Context, req *pb.WishlistReq) (*pb.WishlistRes, error) {
+	w, err := s.getOwnWishlist(ctx, req.GetUserId(), req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.toPBWishlists(ctx, []*storer.Wishlist{w})
+	if err != nil {
+		return nil, err
+	}
+
+	return res[0], nil
+}
+
+// ListWishlists returns the user's wishlists in the order they were created.
+func (s *Server) ListWishlists(ctx context.Context, req *pb.WishlistReq) (*pb.ListWishlistsRes, error) {
+	ws, err := s.storer.ListUserWishlists(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.toPBWishlists(ctx, ws)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListWishlistsRes{Wishlists: res}, nil
+}
+
+// UpdateWishlist renames a wishlist and turns sharing on or off. A name
+// left empty is kept. Sharing a wishlist again gives it a new share token,
+// so links handed out before it stopped being shared stay dead.
+func (s *Server) UpdateWishlist(ctx context.Context, req *pb.WishlistReq) (*pb.WishlistRes, error) {
+	w, err := s.getOwnWishlist(ctx, req.GetUserId(), req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetName() != "" {
+		if w.Name, err = wishlistName(req.GetName()); err != nil {
+			return nil, err
+		}
+	}
+	if req.Shared != nil {
+		switch {
+		case !req.GetShared():
+			w.ShareToken = nil
+		case w.ShareToken == nil:
+			if w.ShareToken, err = newShareToken(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	w.UpdatedAt = toTimePtr(time.Now())
+
+	if _, err := s.storer.UpdateWishlist(ctx, w); err != nil {
+		return nil, wishlistError(err, w.Name)
+	}
+
+	return s.getWishlistRes(ctx, w.ID)
+}
+
+func (s *Server) DeleteWishlist(ctx context.Context, req *pb.WishlistReq) (*pb.WishlistRes, error) {
+	w, err := s.getOwnWishlist(ctx, req.GetUserId(), req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storer.DeleteWishlist(ctx, w.ID); err != nil {
+		return nil, err
+	}
+
+	return &pb.WishlistRes{}, nil
+}
+
+// AddWishlistItem saves a product, or one of its variants, to a wishlist.
+// Products that come in variants are saved as a particular variant, as in
+// carts. Saving an item twice keeps it once.
+func (s *Server) AddWishlistItem(ctx context.Context, req *pb.WishlistItemReq) (*pb.WishlistRes, error) {
+	w, err := s.getOwnWishlist(ctx, req.GetUserId(), req.GetWishlistId())
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.getProduct(ctx, req.GetProductId())
+	if err != nil {
+		return nil, err
+	}
+	variants, err := s.storer.ListProductVariants(ctx, []int64{p.ID})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := resolveVariant(p, variants, req.GetVariantId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.storer.AddWishlistItem(ctx, w.ID, p.ID, req.GetVariantId()); err != nil {
+		return nil, err
+	}
+
+	return s.getWishlistRes(ctx, w.ID)
+}
+
+func (s *Server) RemoveWishlistItem(ctx context.Context, req *pb.WishlistItemReq) (*pb.WishlistRes, error) {
+	w, err := s.getOwnWishlist(ctx, req.GetUserId(), req.GetWishlistId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.removeWishlistItem(ctx, w.ID, req); err != nil {
+		return nil, err
+	}
+
+	return s.getWishlistRes(ctx, w.ID)
+}
+
+// MoveWishlistItemToCart adds units of a wishlist item to the cart through
+// AddCartItem, so its stock is checked as for any other cart item, and then
+// takes it off the wishlist. It returns the cart.
+func (s *Server) MoveWishlistItemToCart(ctx context.Context, req *pb.WishlistItemReq) (*pb.CartRes, error) {
+	w, err := s.getOwnWishlist(ctx, req.GetUserId(), req.GetWishlistId())
+	if err != nil {
+		return nil, err
+	}
+	if findWishlistItem(w, req.GetProductId(), req.GetVariantId()) == nil {
+		return nil, notInWishlist(req)
+	}
+
+	quantity := req.GetQuantity()
+	if quantity == 0 {
+		quantity = 1
+	}
+	cart, err := s.AddCartItem(ctx, &pb.CartReq{
+		UserId:    req.GetUserId(),
+		ProductId: req.GetProductId(),
+		VariantId: req.GetVariantId(),
+		Quantity:  quantity,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the item is in the cart at this point, so failing the call would
+	// invite a retry that adds it twice
+	if err := s.removeWishlistItem(ctx, w.ID, req); err != nil {
+		log.Printf("error removing product %d from wishlist %d after moving it to the cart: %v", req.GetProductId(), w.ID, err)
+	}
+
+	return cart, nil
+}
+
+// GetSharedWishlist returns the wishlist a share token was handed out for,
+// to anyone who has it. The owner is left out.
+func (s *Server) GetSharedWishlist(ctx context.Context, req *pb.SharedWishlistReq) (*pb.WishlistRes, error) {
+	w, err := s.storer.GetWishlistByShareToken(ctx, req.GetShareToken())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "shared wishlist not found")
+		}
+		return nil, err
+	}
+
+	res, err := s.toPBWishlists(ctx, []*storer.Wishlist{w})
+	if err != nil {
+		return nil, err
+	}
+	res[0].UserId = 0
+	res[0].ShareToken = ""
+
+	return res[0], nil
+}
+
+func (s *Server) removeWishlistItem(ctx context.Context, wishlistID int64, req *pb.WishlistItemReq) error {
+	err := s.storer.RemoveWishlistItem(ctx, wishlistID, req.GetProductId(), req.GetVariantId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return notInWishlist(req)
+	}
+	return err
+}
+
+// getOwnWishlist loads a wishlist, which must belong to userID.
+func (s *Server) getOwnWishlist(ctx context.Context, userID, id int64) (*storer.Wishlist, error) {
+	w, err := s.storer.GetWishlist(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "wishlist %d not found", id)
+		}
+		return nil, err
+	}
+
+	if w.UserID != userID {
+		return nil, status.Errorf(codes.PermissionDenied, "wishlist %d belongs to another user", id)
+	}
+
+	return w, nil
+}
+
+func (s *Server) getWishlistRes(ctx context.Context, id int64) (*pb.WishlistRes, error) {
+	w, err := s.storer.GetWishlist(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.toPBWishlists(ctx, []*storer.Wishlist{w})
+	if err != nil {
+		return nil, err
+	}
+
+	return res[0], nil
+}
+
+// toPBWishlists maps wishlists, pricing their items from the catalog.
+func (s *Server) toPBWishlists(ctx context.Context, ws []*storer.Wishlist) ([]*pb.WishlistRes, error) {
+	var ids []int64
+	for _, w := range ws {
+		for _, wi := range w.Items {
+			ids = append(ids, wi.ProductID)
+		}
+	}
+	products, err := s.storer.GetProducts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*storer.Product, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+	variants, err := s.productVariants(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	sps, err := s.runningPrices(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*pb.WishlistRes, 0, len(ws))
+	for _, w := range ws {
+		wr := &pb.WishlistRes{
+			Id:        w.ID,
+			UserId:    w.UserID,
+			Name:      w.Name,
+			Items:     make([]*pb.WishlistItem, 0, len(w.Items)),
+			CreatedAt: timestamppb.New(w.CreatedAt),
+		}
+		if w.ShareToken != nil {
+			wr.ShareToken = *w.ShareToken
+		}
+		if w.UpdatedAt != nil {
+			wr.UpdatedAt = timestamppb.New(*w.UpdatedAt)
+		}
+		for _, wi := range w.Items {
+			item := &pb.WishlistItem{
+				ProductId: wi.ProductID,
+				VariantId: wi.VariantID,
+				AddedAt:   timestamppb.New(wi.CreatedAt),
+			}
+			// an item whose variant was dropped, or that misses one the
+			// product has gained, stays on the list unavailable
+			if p, ok := byID[wi.ProductID]; ok {
+				if v, err := resolveVariant(p, variants[p.ID], wi.VariantID); err == nil {
+					stock := variantStock(p, v)
+					item.Name = variantName(p, v)
+					item.Image = variantImage(p, v)
+					price, _ := sps.price(p, v)
+					item.Price = toPBMoney(price)
+					item.CompareAtPrice = sps.compareAtPrice(p, v)
+					item.CountInStock = stock
+					item.Available = stock > 0
+					if v != nil {
+						item.Sku = v.SKU
+					}
+				}
+			}
+			wr.Items = append(wr.Items, item)
+		}
+		res = append(res, wr)
+	}
+
+	return res, nil
+}
+
+func findWishlistItem(w *storer.Wishlist, productID, variantID int64) *storer.WishlistItem {
+	for i := range w.Items {
+		if w.Items[i].ProductID == productID && w.Items[i].VariantID == variantID {
+			return &w.Items[i]
+		}
+	}
+	return nil
+}
+
+func notInWishlist(req *pb.WishlistItemReq) error {
+	if req.GetVariantId() != 0 {
+		return status.Errorf(codes.NotFound, "variant %d of product %d is not in wishlist %d", req.GetVariantId(), req.GetProductId(), req.GetWishlistId())
+	}
+	return status.Errorf(codes.NotFound, "product %d is not in wishlist %d", req.GetProductId(), req.GetWishlistId())
+}
+
+func wishlistName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	switch {
+	case name == "":
+		return "", status.Error(codes.InvalidArgument, "wishlist name is empty")
+	case utf8.RuneCountInString(name) > maxWishlistName:
+		return "", status.Errorf(codes.InvalidArgument, "wishlist name is longer than %d characters", maxWishlistName)
+	}
+	return name, nil
+}
+
+func wishlistError(err error, name string) error {
+	if errors.Is(err, storer.ErrWishlistExists) {
+		return status.Errorf(codes.AlreadyExists, "wishlist %q already exists", name)
+	}
+	return err
+}
+
+// newShareToken returns a random token that is unguessable enough to be
+// the only thing guarding a shared wishlist.
+func newShareToken() (*string, error) {
+	b := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("error generating share token: %w", err)
+	}
+
+	token := hex.EncodeToString(b)
+	return &token, nil
+}
@@ -0,0 +1,358 @@
+package storer
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+)
+
+// Constants
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type ProductSort string
+
+const (
+	SortByCreatedAt ProductSort = "created_at"
+	SortByPrice     ProductSort = "price"
+	SortByRating    ProductSort = "rating"
+	SortByName      ProductSort = "name"
+)
+
+// ListProductsParams describes one page of a product listing. Cursor is the
+// opaque NextCursor of the previous page and must be used with the same sort.
+// CategoryIDs, when set, keeps products in any of those categories. Prices
+// are filtered and sorted by the ListPrice products sell at now.
+type ListProductsParams struct {
+	Cursor      string
+	Limit       int
+	SortBy      ProductSort
+	Desc        bool
+	CategoryIDs []int64
+	MinPrice    *money.Money
+	MaxPrice    *money.Money
+	MinRating   *int64
+	InStock     bool
+}
+
+type ProductPage struct {
+	Products   []*Product
+	NextCursor string
+}
+
+// pageCursor is the keyset position of the last row of a page: the value of
+// the sort column plus the id used as a tie breaker.
+type pageCursor struct {
+	SortBy string `json:"s"`
+	Desc   bool   `json:"d"`
+	Value  string `json:"v"`
+	ID     int64  `json:"id"`
+}
+
+func (c *pageCursor) encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (*pageCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	var c pageCursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	return &c, nil
+}
+
+func pageSize(limit int) int {
+	switch {
+	case limit <= 0:
+		return DefaultPageSize
+	case limit > MaxPageSize:
+		return MaxPageSize
+	default:
+		return limit
+	}
+}
+
+func (p *ListProductsParams) normalize() error {
+	if p.SortBy == "" {
+		p.SortBy = SortByCreatedAt
+	}
+	if _, ok := productSortColumns[p.SortBy]; !ok {
+		return fmt.Errorf("unknown sort field %q", p.SortBy)
+	}
+	p.Limit = pageSize(p.Limit)
+
+	return nil
+}
+
+// cursor decodes p.Cursor and checks it was issued for the same ordering.
+func (p *ListProductsParams) cursor() (*pageCursor, error) {
+	if p.Cursor == "" {
+		return nil, nil
+	}
+
+	c, err := decodeCursor(p.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if c.SortBy != string(p.SortBy) || c.Desc != p.Desc {
+		return nil, fmt.Errorf("%w: cursor was issued for a different sort order", ErrInvalidCursor)
+	}
+
+	return c, nil
+}
+
+var productSortColumns = map[ProductSort]string{
+	SortByCreatedAt: "created_at",
+	SortByPrice:     "COALESCE(scheduled.price, products.price)",
+	SortByRating:    "rating",
+	SortByName:      "name",
+}
+
+func productSortValue(p *Product, sortBy ProductSort) string {
+	switch sortBy {
+	case SortByPrice:
+		return p.ListPrice.String()
+	case SortByRating:
+		return fmt.Sprintf("%d", p.Rating)
+	case SortByName:
+		return p.Name
+	default:
+		return p.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+func productCursor(p *Product, params *ListProductsParams) string {
+	c := &pageCursor{
+		SortBy: string(params.SortBy),
+		Desc:   params.Desc,
+		Value:  productSortValue(p, params.SortBy),
+		ID:     p.ID,
+	}
+	return c.encode()
+}
+
+// compareProducts orders a and b by sortBy ascending, falling back to id.
+func compareProducts(a, b *Product, sortBy ProductSort) int {
+	var c int
+	switch sortBy {
+	case SortByPrice:
+		c = cmp.Compare(a.ListPrice.Amount, b.ListPrice.Amount)
+	case SortByRating:
+		c = cmp.Compare(a.Rating, b.Rating)
+	case SortByName:
+		c = strings.Compare(a.Name, b.Name)
+	default:
+		c = a.CreatedAt.Compare(b.CreatedAt)
+	}
+	if c != 0 {
+		return c
+	}
+
+	return cmp.Compare(a.ID, b.ID)
+}
+
+// cursorProduct turns the cursor position back into a product carrying only
+// the sort column and id, so it can be compared with compareProducts.
+func cursorProduct(c *pageCursor) (*Product, error) {
+	p := &Product{ID: c.ID}
+
+	var err error
+	switch ProductSort(c.SortBy) {
+	case SortByPrice:
+		p.ListPrice, err = money.Parse(c.Value, money.DefaultCurrency)
+	case SortByRating:
+		p.Rating, err = strconv.ParseInt(c.Value, 10, 64)
+	case SortByName:
+		p.Name = c.Value
+	default:
+		p.CreatedAt, err = time.Parse(time.RFC3339Nano, c.Value)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	return p, nil
+}
+
+// productSortArg is the SQL argument for the sort column of p. Prices are
+// passed as decimal strings by money.Money, so equality against DECIMAL
+// columns is exact.
+func productSortArg(p *Product, sortBy ProductSort) interface{} {
+	switch sortBy {
+	case SortByPrice:
+		return p.ListPrice
+	case SortByRating:
+		return p.Rating
+	case SortByName:
+		return p.Name
+	default:
+		return p.CreatedAt
+	}
+}
+
+// newProductPage trims the look-ahead row fetched past params.Limit and
+// derives the next cursor from the last product kept.
+func newProductPage(products []*Product, params *ListProductsParams) *ProductPage {
+	page := &ProductPage{Products: products}
+	if len(products) > params.Limit {
+		page.Products = products[:params.Limit]
+		page.NextCursor = productCursor(page.Products[params.Limit-1], params)
+	}
+
+	return page
+}
+
+// orderCursorSort tags cursors issued for order listings so product cursors
+// are rejected and vice versa.
+const orderCursorSort = "orders"
+
+// ListOrdersParams describes one page of a user's orders, newest first.
+// Status, From and To are optional; From is inclusive and To exclusive.
+type ListOrdersParams struct {
+	UserID int64
+	Cursor string
+	Limit  int
+	Status OrderStatus
+	From   *time.Time
+	To     *time.Time
+}
+
+type OrderPage struct {
+	Orders     []*Order
+	NextCursor string
+}
+
+// cursor decodes p.Cursor into the created_at and id of the last order of
+// the previous page.
+func (p *ListOrdersParams) cursor() (*Order, error) {
+	if p.Cursor == "" {
+		return nil, nil
+	}
+
+	createdAt, id, err := decodeNewestFirstCursor(p.Cursor, orderCursorSort)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Order{ID: id, CreatedAt: createdAt}, nil
+}
+
+func orderCursor(o *Order) string {
+	return newestFirstCursor(orderCursorSort, o.CreatedAt, o.ID)
+}
+
+// newestFirstCursor encodes the position of the last row of a page of a
+// listing ordered by created_at and id descending. sort names the listing
+// so cursors cannot be replayed against another one.
+func newestFirstCursor(sort string, createdAt time.Time, id int64) string {
+	c := &pageCursor{
+		SortBy: sort,
+		Desc:   true,
+		Value:  createdAt.UTC().Format(time.RFC3339Nano),
+		ID:     id,
+	}
+	return c.encode()
+}
+
+func decodeNewestFirstCursor(s, sort string) (time.Time, int64, error) {
+	c, err := decodeCursor(s)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	if c.SortBy != sort {
+		return time.Time{}, 0, fmt.Errorf("%w: cursor was not issued for %s", ErrInvalidCursor, sort)
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, c.Value)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	return createdAt, c.ID, nil
+}
+
+// compareOrdersNewestFirst orders a before b if it was created later, falling
+// back to the higher id.
+func compareOrdersNewestFirst(a, b *Order) int {
+	if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+		return c
+	}
+	return cmp.Compare(b.ID, a.ID)
+}
+
+// newOrderPage trims the look-ahead row fetched past limit and derives the
+// next cursor from the last order kept.
+func newOrderPage(orders []*Order, limit int) *OrderPage {
+	page := &OrderPage{Orders: orders}
+	if len(orders) > limit {
+		page.Orders = orders[:limit]
+		page.NextCursor = orderCursor(page.Orders[limit-1])
+	}
+
+	return page
+}
+
+const reviewCursorSort = "reviews"
+
+// ListReviewsParams describes one page of a product's reviews, newest first.
+// Hidden reviews are only included for moderators.
+type ListReviewsParams struct {
+	ProductID     int64
+	Cursor        string
+	Limit         int
+	IncludeHidden bool
+}
+
+type ReviewPage struct {
+	Reviews    []*Review
+	NextCursor string
+}
+
+func (p *ListReviewsParams) cursor() (*Review, error) {
+	if p.Cursor == "" {
+		return nil, nil
+	}
+
+	createdAt, id, err := decodeNewestFirstCursor(p.Cursor, reviewCursorSort)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Review{ID: id, CreatedAt: createdAt}, nil
+}
+
+func compareReviewsNewestFirst(a, b *Review) int {
+	if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+		return c
+	}
+	return cmp.Compare(b.ID, a.ID)
+}
+
+func newReviewPage(reviews []*Review, limit int) *ReviewPage {
+	page := &ReviewPage{Reviews: reviews}
+	if len(reviews) > limit {
+		page.Reviews = reviews[:limit]
+		last := page.Reviews[limit-1]
+		page.NextCursor = newestFirstCursor(reviewCursorSort, last.CreatedAt, last.ID)
+	}
+
+	return page
+}
@@ -0,0 +1,243 @@
+package storer
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Constants
+const (
+	// queries with at most this many terms are matched with typo tolerance
+	maxFuzzyQueryTerms = 3
+	// upper bound of rows pulled from MySQL for the fuzzy pass
+	fuzzyCandidateLimit = 500
+	snippetRadius       = 60
+)
+
+// field weights used when ranking in process
+var searchFieldWeights = map[string]float64{
+	"name":        3,
+	"description": 1,
+}
+
+type SearchProductsParams struct {
+	Query string
+	Limit int
+}
+
+// ProductSearchHit is a product matching a search together with its
+// relevance score and per-field snippets with matches wrapped in <em>.
+type ProductSearchHit struct {
+	Product    *Product
+	Score      float64
+	Highlights map[string]string
+}
+
+// searchTerms splits q into lower-cased words, dropping punctuation and
+// MySQL boolean-mode operators.
+func searchTerms(q string) []string {
+	return strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func isShortQuery(terms []string) bool {
+	return len(terms) <= maxFuzzyQueryTerms
+}
+
+// typoTolerance is the edit distance accepted for term; very short words must
+// match exactly or they would match almost anything.
+func typoTolerance(term string) int {
+	switch n := utf8.RuneCountInString(term); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// matchWord scores how well word matches term: 1 for an exact match, 0.8 for
+// a prefix match and 0.5 for a match within the typo tolerance.
+func matchWord(word, term string, fuzzy bool) float64 {
+	switch {
+	case word == term:
+		return 1
+	case strings.HasPrefix(word, term):
+		return 0.8
+	case fuzzy && typoTolerance(term) > 0 && levenshtein(word, term) <= typoTolerance(term):
+		return 0.5
+	default:
+		return 0
+	}
+}
+
+func searchFields(p *Product) map[string]string {
+	return map[string]string{
+		"name":        p.Name,
+		"description": p.Description,
+	}
+}
+
+// scoreProduct ranks p against terms. Every term has to match somewhere,
+// otherwise the product is not a hit and the score is zero.
+func scoreProduct(p *Product, terms []string, fuzzy bool) float64 {
+	fields := searchFields(p)
+	words := make(map[string][]string, len(fields))
+	for name, text := range fields {
+		words[name] = searchTerms(text)
+	}
+
+	var score float64
+	for _, term := range terms {
+		var best float64
+		for name, ws := range words {
+			for _, w := range ws {
+				best = max(best, matchWord(w, term, fuzzy)*searchFieldWeights[name])
+			}
+		}
+		if best == 0 {
+			return 0
+		}
+		score += best
+	}
+
+	return score
+}
+
+// highlightProduct returns a snippet for every field of p containing a term.
+func highlightProduct(p *Product, terms []string, fuzzy bool) map[string]string {
+	highlights := make(map[string]string)
+	for name, text := range searchFields(p) {
+		if snippet, ok := highlight(text, terms, fuzzy); ok {
+			highlights[name] = snippet
+		}
+	}
+
+	return highlights
+}
+
+// highlight HTML-escapes text, wraps matching words in <em> and trims it to a
+// window around the first match.
+func highlight(text string, terms []string, fuzzy bool) (string, bool) {
+	type span struct{ start, end int }
+	var spans []span
+
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		word := strings.ToLower(text[start:end])
+		for _, term := range terms {
+			if matchWord(word, term, fuzzy) > 0 {
+				spans = append(spans, span{start, end})
+				break
+			}
+		}
+		start = -1
+	}
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		flush(i)
+	}
+	flush(len(text))
+
+	if len(spans) == 0 {
+		return "", false
+	}
+
+	// cut on spaces so the snippet never starts or ends mid-word
+	from, to := 0, len(text)
+	if first := spans[0]; first.start > snippetRadius {
+		from = first.start
+		if j := strings.IndexByte(text[first.start-snippetRadius:first.start], ' '); j >= 0 {
+			from = first.start - snippetRadius + j + 1
+		}
+	}
+	if end := spans[0].end + 2*snippetRadius; end < len(text) {
+		to = len(text)
+		if j := strings.IndexByte(text[end:], ' '); j >= 0 {
+			to = end + j
+		}
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, s := range spans {
+		if s.start < from || s.end > to {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:s.start]))
+		b.WriteString("<em>")
+		b.WriteString(html.EscapeString(text[s.start:s.end]))
+		b.WriteString("</em>")
+		pos = s.end
+	}
+	b.WriteString(html.EscapeString(text[pos:to]))
+	if to < len(text) {
+		b.WriteString("…")
+	}
+
+	return b.String(), true
+}
+
+// rankProducts scores candidates in process and returns the hits ordered by
+// score, best first.
+func rankProducts(candidates []*Product, terms []string, fuzzy bool) []*ProductSearchHit {
+	var hits []*ProductSearchHit
+	for _, p := range candidates {
+		score := scoreProduct(p, terms, fuzzy)
+		if score == 0 {
+			continue
+		}
+		hits = append(hits, &ProductSearchHit{
+			Product:    p,
+			Score:      score,
+			Highlights: highlightProduct(p, terms, fuzzy),
+		})
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Product.ID < hits[j].Product.ID
+	})
+
+	return hits
+}
@@ -0,0 +1,109 @@
+package storer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHighlight(t *testing.T) {
+	tcs := []struct {
+		name    string
+		text    string
+		terms   []string
+		fuzzy   bool
+		snippet string
+		ok      bool
+	}{
+		{
+			name:    "exact",
+			text:    "Red running shoes",
+			terms:   []string{"running"},
+			snippet: "Red <em>running</em> shoes",
+			ok:      true,
+		},
+		{
+			name:    "prefix and escaping",
+			text:    "Shoes & socks",
+			terms:   []string{"sho"},
+			snippet: "<em>Shoes</em> &amp; socks",
+			ok:      true,
+		},
+		{
+			name:    "typo",
+			text:    "Leather wallet",
+			terms:   []string{"lether"},
+			fuzzy:   true,
+			snippet: "<em>Leather</em> wallet",
+			ok:      true,
+		},
+		{
+			name:  "typo without tolerance",
+			text:  "Leather wallet",
+			terms: []string{"lether"},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			snippet, ok := highlight(tc.text, tc.terms, tc.fuzzy)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.snippet, snippet)
+		})
+	}
+}
+
+func TestMemorySearchProducts(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	for _, p := range []*Product{
+		{Name: "Trail running shoes", Description: "Lightweight shoes for trails"},
+		{Name: "Leather wallet", Description: "Fits running errands"},
+		{Name: "Wool socks", Description: "Warm socks to wear with shoes"},
+	} {
+		_, err := st.CreateProduct(ctx, p)
+		require.NoError(t, err)
+	}
+
+	tcs := []struct {
+		name  string
+		query string
+		ids   []int64
+	}{
+		{
+			name:  "name outranks description",
+			query: "running",
+			ids:   []int64{1, 2},
+		},
+		{
+			name:  "all terms must match",
+			query: "running wallet",
+			ids:   []int64{2},
+		},
+		{
+			name:  "typo tolerance",
+			query: "shoees",
+			ids:   []int64{1, 3},
+		},
+		{
+			name:  "no match",
+			query: "umbrella",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			hits, err := st.SearchProducts(ctx, &SearchProductsParams{Query: tc.query})
+			require.NoError(t, err)
+
+			var ids []int64
+			for _, hit := range hits {
+				ids = append(ids, hit.Product.ID)
+				require.NotEmpty(t, hit.Highlights)
+			}
+			require.Equal(t, tc.ids, ids)
+		})
+	}
+}
@@ -0,0 +1,578 @@
+package storer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"github.com/OrkhanMehbaliyev/ecom-golang/payments"
+)
+
+// Storer is the persistence API used by the gRPC server. MySQLStorer is the
+// production implementation; MemoryStorer keeps everything in process so the
+// stack can run locally and in tests without a database.
+type Storer interface {
+	ProductStorer
+	VariantStorer
+	ScheduledPriceStorer
+	InventoryStorer
+	CategoryStorer
+	PromotionStorer
+	TaxRateStorer
+	AddressStorer
+	ShippingStorer
+	OrderStorer
+	PaymentStorer
+	GiftCardStorer
+	StoreCreditStorer
+	LoyaltyStorer
+	InvoiceStorer
+	ReturnStorer
+	CartStorer
+	WishlistStorer
+	ReviewStorer
+	UserStorer
+	SessionStorer
+	NotificationStorer
+}
+
+type ProductStorer interface {
+	CreateProduct(ctx context.Context, p *Product) (*Product, error)
+	GetProduct(ctx context.Context, id int64) (*Product, error)
+	GetProducts(ctx context.Context, ids []int64) ([]*Product, error)
+	ListProducts(ctx context.Context, params *ListProductsParams) (*ProductPage, error)
+	SearchProducts(ctx context.Context, params *SearchProductsParams) ([]*ProductSearchHit, error)
+	UpdateProduct(ctx context.Context, p *Product) (*Product, error)
+	DeleteProduct(ctx context.Context, id int64) error
+}
+
+// VariantStorer keeps product variants. Every write recomputes the product's
+// count_in_stock as the sum of its variants' stock in the same transaction.
+type VariantStorer interface {
+	CreateVariant(ctx context.Context, v *Variant) (*Variant, error)
+	GetVariant(ctx context.Context, id int64) (*Variant, error)
+	ListProductVariants(ctx context.Context, productIDs []int64) ([]*Variant, error)
+	UpdateVariant(ctx context.Context, v *Variant) (*Variant, error)
+	DeleteVariant(ctx context.Context, id int64) error
+}
+
+// ScheduledPriceStorer keeps the prices products sell at for a while. Orders
+// count the units they sell at a scheduled price in the same transaction,
+// failing with a ScheduledPriceSoldOutError past its cap, and cancelling
+// them gives the units back.
+type ScheduledPriceStorer interface {
+	CreateScheduledPrice(ctx context.Context, sp *ScheduledPrice) (*ScheduledPrice, error)
+	GetScheduledPrice(ctx context.Context, id int64) (*ScheduledPrice, error)
+	// ListScheduledPrices returns the scheduled prices of a product, by
+	// start.
+	ListScheduledPrices(ctx context.Context, productID int64) ([]*ScheduledPrice, error)
+	// ListRunningScheduledPrices returns the scheduled prices of the given
+	// products that are running at t.
+	ListRunningScheduledPrices(ctx context.Context, productIDs []int64, t time.Time) ([]*ScheduledPrice, error)
+	UpdateScheduledPrice(ctx context.Context, sp *ScheduledPrice) (*ScheduledPrice, error)
+	DeleteScheduledPrice(ctx context.Context, id int64) error
+}
+
+// InventoryStorer keeps stock per warehouse and the ledger of movements it
+// is the sum of. The count_in_stock of products and variants is kept at the
+// sum of their stock levels and only changes through movements: orders
+// record sales, releasing their stock reverses them, restocked returns and
+// the opening stock of new products and variants go to the main warehouse.
+type InventoryStorer interface {
+	// CreateWarehouse fails with ErrWarehouseExists if the code is taken.
+	CreateWarehouse(ctx context.Context, w *Warehouse) (*Warehouse, error)
+	GetWarehouse(ctx context.Context, id int64) (*Warehouse, error)
+	ListWarehouses(ctx context.Context) ([]*Warehouse, error)
+	UpdateWarehouse(ctx context.Context, w *Warehouse) (*Warehouse, error)
+	// ListStockLevels returns the stock of a product per warehouse and
+	// variant.
+	ListStockLevels(ctx context.Context, productID int64) ([]*StockLevel, error)
+	// RecordStockMovements appends movements to the ledger and applies them
+	// to stock levels, all or none. It fails with an InsufficientStockError
+	// if they would take a stock level below zero.
+	RecordStockMovements(ctx context.Context, movements []*StockMovement) error
+	ListStockMovements(ctx context.Context, params *ListStockMovementsParams) ([]*StockMovement, error)
+}
+
+// CategoryStorer keeps the category tree. The tree is small, so callers load
+// all of it with ListCategories and walk it in memory.
+type CategoryStorer interface {
+	CreateCategory(ctx context.Context, c *Category) (*Category, error)
+	GetCategory(ctx context.Context, id int64) (*Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*Category, error)
+	ListCategories(ctx context.Context) ([]*Category, error)
+	UpdateCategory(ctx context.Context, c *Category) (*Category, error)
+	DeleteCategory(ctx context.Context, id int64) error
+}
+
+type OrderStorer interface {
+	// CreateOrder reserves the stock of an order, counts the units it sells
+	// at scheduled prices, redeems its promotions and takes its tenders off
+	// their gift cards and store credit, all or none. It fails with
+	// ErrInsufficientBalance if a balance no longer covers its tender.
+	CreateOrder(ctx context.Context, o *Order, ne *NotificationEvent) (*Order, error)
+	GetOrder(ctx context.Context, id int64) (*Order, error)
+	GetOrderStatusByID(ctx context.Context, id int64) (*Order, error)
+	ListOrders(ctx context.Context) ([]*Order, error)
+	ListUserOrders(ctx context.Context, params *ListOrdersParams) (*OrderPage, error)
+	// UpdateOrderStatus moves the order from change.FromStatus to o.Status
+	// and records the change, its credit note, gift card cancellations and
+	// loyalty points, all or none. It fails with ErrOrderStatusChanged if
+	// the order is no longer in change.FromStatus.
+	UpdateOrderStatus(ctx context.Context, o *Order, change *OrderStatusChange, ne *NotificationEvent) (*Order, error)
+	ListOrderStatusChanges(ctx context.Context, orderID int64) ([]*OrderStatusChange, error)
+	// DeleteOrder fails with ErrOrderHasRecords if the order has payments
+	// that are in progress or took money, an invoice or returns.
+	DeleteOrder(ctx context.Context, id int64) error
+}
+
+// PaymentStorer keeps the attempts to pay orders. An order has at most one
+// active payment at a time.
+type PaymentStorer interface {
+	// CreatePayment records a new attempt to pay an order. It fails with
+	// ErrPaymentInProgress if the order has an active payment.
+	CreatePayment(ctx context.Context, p *Payment) (*Payment, error)
+	GetPayment(ctx context.Context, id int64) (*Payment, error)
+	GetPaymentByReference(ctx context.Context, provider, reference string) (*Payment, error)
+	// ListOrderPayments returns an order's payments, oldest first.
+	ListOrderPayments(ctx context.Context, orderID int64) ([]*Payment, error)
+	// UpdatePayment records what the payment provider answered about p. What
+	// was refunded of it only changes through RefundPayment.
+	UpdatePayment(ctx context.Context, p *Payment) (*Payment, error)
+	// RefundPayment records refund r of captured payment p, with the ledger
+	// transaction it carries, and marks p refunded once all of it is, all
+	// or none. Refunds without a ledger transaction are recorded pending,
+	// for the payment provider to carry out. It fails with ErrRefundApplied
+	// if p was refunded for r.Key already, and with ErrPaymentChanged if p
+	// was refunded since it was read.
+	RefundPayment(ctx context.Context, p *Payment, r *PaymentRefund) (*Payment, error)
+	// ApplyPaymentRefund marks pending refund id applied once the payment
+	// provider carried it out.
+	ApplyPaymentRefund(ctx context.Context, id int64) error
+	// ListOrderPaymentRefunds returns the refunds of an order's payments,
+	// oldest first.
+	ListOrderPaymentRefunds(ctx context.Context, orderID int64) ([]*PaymentRefund, error)
+}
+
+// GiftCardStorer keeps gift cards and their ledgers.
+type GiftCardStorer interface {
+	// CreateGiftCard inserts a card with a balance of its initial amount
+	// and records that as its issue transaction. It fails with
+	// ErrGiftCardExists if the code is taken.
+	CreateGiftCard(ctx context.Context, g *GiftCard) (*GiftCard, error)
+	GetGiftCard(ctx context.Context, id int64) (*GiftCard, error)
+	GetGiftCardByCode(ctx context.Context, code string) (*GiftCard, error)
+	// ListUserGiftCards returns the cards a user bought, newest first.
+	ListUserGiftCards(ctx context.Context, purchaserID int64) ([]*GiftCard, error)
+	// ListOrderGiftCards returns the cards bought in an order.
+	ListOrderGiftCards(ctx context.Context, orderID int64) ([]*GiftCard, error)
+	// UpdateGiftCard changes the expiry and note of a card.
+	UpdateGiftCard(ctx context.Context, g *GiftCard) (*GiftCard, error)
+	// RecordGiftCardTransaction appends a transaction to the ledger of its
+	// card and applies it to the balance. It fails with
+	// ErrInsufficientBalance if the balance would go below zero.
+	RecordGiftCardTransaction(ctx context.Context, t *GiftCardTransaction) error
+	// ListGiftCardTransactions returns the ledger of a card, oldest first.
+	ListGiftCardTransactions(ctx context.Context, giftCardID int64) ([]*GiftCardTransaction, error)
+}
+
+// StoreCreditStorer keeps the store credit of users, a balance every user
+// has that only changes through transactions.
+type StoreCreditStorer interface {
+	// GetStoreCredit returns the balance of a user's store credit, which is
+	// zero until the first transaction.
+	GetStoreCredit(ctx context.Context, userID int64) (money.Money, error)
+	// RecordStoreCreditTransaction appends a transaction to the ledger of
+	// its user and applies it to the balance. It fails with
+	// ErrInsufficientBalance if the balance would go below zero.
+	RecordStoreCreditTransaction(ctx context.Context, t *StoreCreditTransaction) error
+	// ListStoreCreditTransactions returns the ledger of a user's store
+	// credit, oldest first.
+	ListStoreCreditTransactions(ctx context.Context, userID int64) ([]*StoreCreditTransaction, error)
+}
+
+// LoyaltyStorer keeps the loyalty points of users, a balance every user has
+// that only changes through transactions. Points lapse once they expire
+// unspent, which is recorded as a transaction the next time the balance
+// changes or ExpireLoyaltyPoints runs.
+type LoyaltyStorer interface {
+	// GetLoyaltyBalance returns a user's points, which are zero until the
+	// first transaction.
+	GetLoyaltyBalance(ctx context.Context, userID int64) (int64, error)
+	// GetLoyaltySpend returns what a user spent to earn the points of
+	// transactions made since then.
+	GetLoyaltySpend(ctx context.Context, userID int64, since time.Time) (money.Money, error)
+	// RecordLoyaltyTransaction lets lapsed points of its user expire, then
+	// appends the transaction to the ledger and applies it to the balance.
+	// It fails with ErrInsufficientBalance if a redemption would take the
+	// balance below zero; reversals may.
+	RecordLoyaltyTransaction(ctx context.Context, t *LoyaltyTransaction) error
+	// ExpireLoyaltyPoints lets lapsed points of a user expire.
+	ExpireLoyaltyPoints(ctx context.Context, userID int64) error
+	// ListLoyaltyTransactions returns the ledger of a user's points, oldest
+	// first.
+	ListLoyaltyTransactions(ctx context.Context, userID int64) ([]*LoyaltyTransaction, error)
+}
+
+// InvoiceStorer keeps invoices and the credit notes issued against them.
+// Both are numbered sequentially, without gaps, as they are created.
+type InvoiceStorer interface {
+	// CreateInvoice numbers and inserts an invoice. It fails with
+	// ErrInvoiceExists if the order is already invoiced.
+	CreateInvoice(ctx context.Context, inv *Invoice) (*Invoice, error)
+	GetOrderInvoice(ctx context.Context, orderID int64) (*Invoice, error)
+	// CreateCreditNote numbers and inserts a credit note.
+	CreateCreditNote(ctx context.Context, cn *CreditNote) (*CreditNote, error)
+	// ListInvoiceCreditNotes returns the credit notes of an invoice, oldest
+	// first.
+	ListInvoiceCreditNotes(ctx context.Context, invoiceID int64) ([]*CreditNote, error)
+}
+
+// ReturnStorer keeps return requests. Like order status changes, every
+// change enqueues its notification event in the same transaction.
+type ReturnStorer interface {
+	// CreateReturn inserts a requested return. It fails with
+	// ErrReturnQuantityExceeded if the order item's returns that were not
+	// rejected leave fewer than r.Quantity units to return.
+	CreateReturn(ctx context.Context, r *Return, ne *NotificationEvent) (*Return, error)
+	GetReturn(ctx context.Context, id int64) (*Return, error)
+	// ListOrderReturns returns an order's returns, oldest first.
+	ListOrderReturns(ctx context.Context, orderID int64) ([]*Return, error)
+	// ListReturns returns the returns in status, or every return if status
+	// is empty, oldest first.
+	ListReturns(ctx context.Context, status ReturnStatus) ([]*Return, error)
+	// UpdateReturnStatus moves r to r.Status, with its credit note and
+	// loyalty points if any, all or none. It fails with
+	// ErrReturnStatusChanged if the return is no longer in status from. The
+	// units of a return received with r.Restocked go back into stock.
+	UpdateReturnStatus(ctx context.Context, r *Return, from ReturnStatus, ne *NotificationEvent) (*Return, error)
+}
+
+// PromotionStorer keeps promotions. Redemptions are recorded by CreateOrder
+// for the promotions in an order's discounts.
+type PromotionStorer interface {
+	CreatePromotion(ctx context.Context, p *Promotion) (*Promotion, error)
+	GetPromotion(ctx context.Context, id int64) (*Promotion, error)
+	GetPromotionByCode(ctx context.Context, code string) (*Promotion, error)
+	ListPromotions(ctx context.Context) ([]*Promotion, error)
+	// ListAutomaticPromotions returns the promotions without a code that are
+	// running at t.
+	ListAutomaticPromotions(ctx context.Context, t time.Time) ([]*Promotion, error)
+	// CountUserRedemptions returns how often the user redeemed each of the
+	// given promotions.
+	CountUserRedemptions(ctx context.Context, userID int64, promotionIDs []int64) (map[int64]int64, error)
+	UpdatePromotion(ctx context.Context, p *Promotion) (*Promotion, error)
+	DeletePromotion(ctx context.Context, id int64) error
+}
+
+// TaxRateStorer keeps the tax rates admins configure per jurisdiction and
+// tax class.
+type TaxRateStorer interface {
+	CreateTaxRate(ctx context.Context, r *TaxRate) (*TaxRate, error)
+	GetTaxRate(ctx context.Context, id int64) (*TaxRate, error)
+	ListTaxRates(ctx context.Context) ([]*TaxRate, error)
+	// ListApplicableTaxRates returns the rates that can apply to a destination:
+	// those of the country and region, of the whole country and of every
+	// destination.
+	ListApplicableTaxRates(ctx context.Context, country, region string) ([]*TaxRate, error)
+	UpdateTaxRate(ctx context.Context, r *TaxRate) (*TaxRate, error)
+	DeleteTaxRate(ctx context.Context, id int64) error
+}
+
+// AddressStorer keeps users' address books. Saving an address as the
+// default clears the flag on the user's other addresses.
+type AddressStorer interface {
+	CreateAddress(ctx context.Context, a *UserAddress) (*UserAddress, error)
+	GetAddress(ctx context.Context, id int64) (*UserAddress, error)
+	// ListUserAddresses returns the user's addresses, the default first.
+	ListUserAddresses(ctx context.Context, userID int64) ([]*UserAddress, error)
+	UpdateAddress(ctx context.Context, a *UserAddress) (*UserAddress, error)
+	DeleteAddress(ctx context.Context, id int64) error
+}
+
+// ShippingStorer keeps the shipping rate table: zones and the methods
+// shipping to them. Deleting a zone deletes its methods.
+type ShippingStorer interface {
+	CreateShippingZone(ctx context.Context, z *ShippingZone) (*ShippingZone, error)
+	GetShippingZone(ctx context.Context, id int64) (*ShippingZone, error)
+	ListShippingZones(ctx context.Context) ([]*ShippingZone, error)
+	UpdateShippingZone(ctx context.Context, z *ShippingZone) (*ShippingZone, error)
+	DeleteShippingZone(ctx context.Context, id int64) error
+	CreateShippingMethod(ctx context.Context, m *ShippingMethod) (*ShippingMethod, error)
+	GetShippingMethod(ctx context.Context, id int64) (*ShippingMethod, error)
+	// ListShippingMethods returns the methods of the given zones.
+	ListShippingMethods(ctx context.Context, zoneIDs []int64) ([]*ShippingMethod, error)
+	UpdateShippingMethod(ctx context.Context, m *ShippingMethod) (*ShippingMethod, error)
+	DeleteShippingMethod(ctx context.Context, id int64) error
+}
+
+// WishlistStorer keeps users' named wishlists, which are returned with
+// their items in the order they were added. Names are unique per user;
+// reusing one fails with ErrWishlistExists.
+type WishlistStorer interface {
+	CreateWishlist(ctx context.Context, w *Wishlist) (*Wishlist, error)
+	GetWishlist(ctx context.Context, id int64) (*Wishlist, error)
+	GetWishlistByShareToken(ctx context.Context, token string) (*Wishlist, error)
+	ListUserWishlists(ctx context.Context, userID int64) ([]*Wishlist, error)
+	// UpdateWishlist saves the name and share token of a wishlist.
+	UpdateWishlist(ctx context.Context, w *Wishlist) (*Wishlist, error)
+	DeleteWishlist(ctx context.Context, id int64) error
+	// AddWishlistItem adds a product or variant to a wishlist; adding one
+	// that is already on it does nothing.
+	AddWishlistItem(ctx context.Context, wishlistID, productID, variantID int64) error
+	RemoveWishlistItem(ctx context.Context, wishlistID, productID, variantID int64) error
+}
+
+// CartStorer keeps one cart per user. A user without a cart is treated as
+// having an empty one. Items are keyed by product and variant, with a
+// variantID of 0 for products without variants.
+type CartStorer interface {
+	GetCart(ctx context.Context, userID int64) (*Cart, error)
+	SetCartItem(ctx context.Context, userID, productID, variantID, quantity int64) error
+	RemoveCartItem(ctx context.Context, userID, productID, variantID int64) error
+	ClearCart(ctx context.Context, userID int64) error
+}
+
+// ReviewStorer keeps product reviews. Every write recomputes the reviewed
+// product's rating and num_reviews in the same transaction.
+type ReviewStorer interface {
+	CreateReview(ctx context.Context, r *Review) (*Review, error)
+	GetReview(ctx context.Context, id int64) (*Review, error)
+	ListProductReviews(ctx context.Context, params *ListReviewsParams) (*ReviewPage, error)
+	UpdateReview(ctx context.Context, r *Review) (*Review, error)
+	SetReviewHidden(ctx context.Context, id int64, hidden bool) error
+	DeleteReview(ctx context.Context, id int64) error
+	HasDeliveredOrder(ctx context.Context, userID, productID int64) (bool, error)
+}
+
+type UserStorer interface {
+	CreateUser(ctx context.Context, u *User) (*User, error)
+	GetUser(ctx context.Context, email string) (*User, error)
+	GetUserByID(ctx context.Context, id int64) (*User, error)
+	ListUsers(ctx context.Context) ([]*User, error)
+	// UpdateUser updates a user's profile. Tax exemption is only changed
+	// through SetUserTaxExempt.
+	UpdateUser(ctx context.Context, u *User) (*User, error)
+	SetUserTaxExempt(ctx context.Context, id int64, exempt bool) error
+	DeleteUser(ctx context.Context, id int64) error
+}
+
+type SessionStorer interface {
+	CreateSession(ctx context.Context, s *Session) (*Session, error)
+	GetSession(ctx context.Context, id string) (*Session, error)
+	RevokeSession(ctx context.Context, id string) error
+	DeleteSession(ctx context.Context, id string) error
+}
+
+type NotificationStorer interface {
+	EnqueueNotificatioEvent(ctx context.Context, ne *NotificationEvent) (*NotificationEvent, error)
+	ListNotificationEvents(ctx context.Context) ([]*NotificationEvent, error)
+	ClaimNotificationEvents(ctx context.Context, limit int, lease time.Duration) ([]*NotificationEvent, error)
+	UpdateNotificationEvent(ctx context.Context, ev *NotificationEvent, es *NotificationState, responseType NotificationResponseType) (bool, error)
+}
+
+var (
+	_ Storer = (*MySQLStorer)(nil)
+	_ Storer = (*MemoryStorer)(nil)
+)
+
+func sortedKeys[V any](m map[int64]V) []int64 {
+	keys := make([]int64, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// stockKey names the stock of a product, or of one of its variants, in a
+// warehouse. WarehouseID is left 0 where the stock of all warehouses is
+// meant.
+type stockKey struct {
+	WarehouseID int64
+	ProductID   int64
+	VariantID   int64
+}
+
+func (m *StockMovement) key() stockKey {
+	return stockKey{WarehouseID: m.WarehouseID, ProductID: m.ProductID, VariantID: m.VariantID}
+}
+
+// sortedStockKeys orders keys by product, variant and warehouse, the order
+// stock levels are locked in.
+func sortedStockKeys[V any](m map[stockKey]V) []stockKey {
+	keys := make([]stockKey, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.ProductID != b.ProductID {
+			return a.ProductID < b.ProductID
+		}
+		if a.VariantID != b.VariantID {
+			return a.VariantID < b.VariantID
+		}
+		return a.WarehouseID < b.WarehouseID
+	})
+	return keys
+}
+
+// orderedStock sums the quantity of order items per product, or per variant
+// for the items that name one.
+func orderedStock(items []OrderItem) map[stockKey]int64 {
+	qty := make(map[stockKey]int64, len(items))
+	for _, oi := range items {
+		k := stockKey{ProductID: oi.ProductID}
+		if oi.VariantID != nil {
+			k.VariantID = *oi.VariantID
+		}
+		qty[k] += oi.Quantity
+	}
+	return qty
+}
+
+// applyRefund applies refund r to current, the locked payment p was read
+// as, making sure it is still captured, was not refunded since and has the
+// amount of r left to refund.
+func applyRefund(current, p *Payment, r *PaymentRefund) error {
+	if current.Status != payments.StatusCaptured || current.RefundedAmount != p.RefundedAmount {
+		return ErrPaymentChanged
+	}
+	if left := current.Amount.Sub(current.RefundedAmount); r.Amount.Amount <= 0 || r.Amount.Cmp(left) > 0 {
+		return fmt.Errorf("cannot refund %s of payment %d, %s is left", r.Amount, p.ID, left)
+	}
+
+	current.RefundedAmount = current.RefundedAmount.Add(r.Amount)
+	if current.RefundedAmount == current.Amount {
+		current.Status = payments.StatusRefunded
+	}
+	now := time.Now()
+	current.UpdatedAt = &now
+
+	return nil
+}
+
+// refundStatus is what refund r is recorded as: applied when it carries the
+// ledger transaction giving the money back, pending when the payment
+// provider has yet to.
+func refundStatus(r *PaymentRefund) RefundStatus {
+	if r.GiftCard != nil || r.StoreCredit != nil || r.Loyalty != nil {
+		return RefundApplied
+	}
+	return RefundPending
+}
+
+// scheduledPriceLines groups the lines of an order sold at scheduled prices
+// by price.
+func scheduledPriceLines(items []OrderItem) map[int64][]OrderItem {
+	lines := make(map[int64][]OrderItem)
+	for _, oi := range items {
+		if oi.ScheduledPriceID != nil {
+			lines[*oi.ScheduledPriceID] = append(lines[*oi.ScheduledPriceID], oi)
+		}
+	}
+	return lines
+}
+
+// checkScheduledPrice makes sure order lines can be sold at sp at t: it must
+// be running, apply to their product or variant, still be the price they
+// were quoted at and leave enough units under its cap for them.
+func checkScheduledPrice(sp *ScheduledPrice, lines []OrderItem, t time.Time) error {
+	var qty int64
+	changed := t.Before(sp.StartsAt) || !t.Before(sp.EndsAt)
+	for _, oi := range lines {
+		qty += oi.Quantity
+		if oi.ProductID != sp.ProductID || oi.Price != sp.Price {
+			changed = true
+		}
+		if sp.VariantID != nil && (oi.VariantID == nil || *oi.VariantID != *sp.VariantID) {
+			changed = true
+		}
+	}
+
+	err := &ScheduledPriceSoldOutError{
+		ScheduledPriceID: sp.ID,
+		ProductID:        sp.ProductID,
+		Requested:        qty,
+	}
+	switch {
+	case changed:
+		err.Changed = true
+		return err
+	case sp.QuantityCap != nil && sp.QuantitySold+qty > *sp.QuantityCap:
+		err.Available = max(*sp.QuantityCap-sp.QuantitySold, 0)
+		return err
+	}
+
+	return nil
+}
+
+// restockMovements returns the movements putting the quantities of the items
+// of an order back into the main warehouse.
+func restockMovements(items []OrderItem, kind StockMovementKind, reason string, actorID *int64) []*StockMovement {
+	qty := orderedStock(items)
+	mvs := make([]*StockMovement, 0, len(qty))
+	for _, k := range sortedStockKeys(qty) {
+		orderID := items[0].OrderID
+		mvs = append(mvs, &StockMovement{
+			WarehouseID: MainWarehouseID,
+			ProductID:   k.ProductID,
+			VariantID:   k.VariantID,
+			Kind:        kind,
+			Quantity:    qty[k],
+			Reason:      reason,
+			ActorID:     actorID,
+			OrderID:     &orderID,
+		})
+	}
+	return mvs
+}
+
+// netStockChanges sums the quantities of movements per stock level.
+func netStockChanges(mvs []*StockMovement) map[stockKey]int64 {
+	net := make(map[stockKey]int64, len(mvs))
+	for _, m := range mvs {
+		net[m.key()] += m.Quantity
+	}
+	return net
+}
+
+// issueTransaction is the transaction giving a new gift card its initial
+// amount.
+func issueTransaction(g *GiftCard) *GiftCardTransaction {
+	reason := "issued"
+	if g.OrderID != nil {
+		reason = fmt.Sprintf("bought in order %d", *g.OrderID)
+	}
+
+	return &GiftCardTransaction{
+		GiftCardID: g.ID,
+		Kind:       BalanceIssue,
+		Amount:     g.InitialAmount,
+		OrderID:    g.OrderID,
+		ActorID:    g.IssuedBy,
+		Reason:     reason,
+	}
+}
+
+// expireTransaction lets the points of a user that lapsed unspent expire,
+// given the points credited that are past their expiry and all the points
+// taken off so far, expirations included. Points are spent oldest first, so
+// what was taken off used up the lapsed points before anything else. It
+// returns nil when no points are left to expire.
+func expireTransaction(userID, lapsed, used int64) *LoyaltyTransaction {
+	if lapsed <= used {
+		return nil
+	}
+
+	return &LoyaltyTransaction{
+		UserID: userID,
+		Kind:   BalanceExpire,
+		Points: used - lapsed,
+		Spend:  money.New(0, money.DefaultCurrency),
+		Reason: "points expired",
+	}
+}
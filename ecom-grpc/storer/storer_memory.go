@@ -0,0 +1,3521 @@
+package storer
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"github.com/OrkhanMehbaliyev/ecom-golang/payments"
+)
+
+// MemoryStorer is a thread-safe in-memory Storer. It mirrors the behaviour of
+// MySQLStorer, including auto-increment IDs, default timestamps and
+// sql.ErrNoRows for missing rows, so it can stand in for the database.
+type MemoryStorer struct {
+	mu  sync.RWMutex
+	seq map[string]int64
+
+	products           map[int64]*Product
+	variants           map[int64]*Variant
+	scheduledPrices    map[int64]*ScheduledPrice
+	warehouses         map[int64]*Warehouse
+	stockLevels        map[stockKey]int64
+	stockMovements     map[int64]*StockMovement
+	categories         map[int64]*Category
+	promotions         map[int64]*Promotion
+	redemptions        map[int64]*redemption
+	taxRates           map[int64]*TaxRate
+	addresses          map[int64]*UserAddress
+	shippingZones      map[int64]*ShippingZone
+	shippingMethods    map[int64]*ShippingMethod
+	orders             map[int64]*Order
+	statusChanges      map[int64]*OrderStatusChange
+	payments           map[int64]*Payment
+	paymentRefunds     map[int64]*PaymentRefund
+	giftCards          map[int64]*GiftCard
+	giftCardTxs        map[int64]*GiftCardTransaction
+	storeCredits       map[int64]money.Money
+	storeCreditTxs     map[int64]*StoreCreditTransaction
+	loyaltyPoints      map[int64]int64
+	loyaltyTxs         map[int64]*LoyaltyTransaction
+	invoices           map[int64]*Invoice
+	creditNotes        map[int64]*CreditNote
+	returns            map[int64]*Return
+	carts              map[int64]*Cart
+	wishlists          map[int64]*Wishlist
+	reviews            map[int64]*Review
+	users              map[int64]*User
+	sessions           map[string]*Session
+	notificationStates map[int64]*NotificationState
+	notificationEvents map[int64]*NotificationEvent
+}
+
+func NewMemoryStorer() *MemoryStorer {
+	ms := &MemoryStorer{
+		seq:                make(map[string]int64),
+		products:           make(map[int64]*Product),
+		variants:           make(map[int64]*Variant),
+		scheduledPrices:    make(map[int64]*ScheduledPrice),
+		warehouses:         make(map[int64]*Warehouse),
+		stockLevels:        make(map[stockKey]int64),
+		stockMovements:     make(map[int64]*StockMovement),
+		categories:         make(map[int64]*Category),
+		promotions:         make(map[int64]*Promotion),
+		redemptions:        make(map[int64]*redemption),
+		taxRates:           make(map[int64]*TaxRate),
+		addresses:          make(map[int64]*UserAddress),
+		shippingZones:      make(map[int64]*ShippingZone),
+		shippingMethods:    make(map[int64]*ShippingMethod),
+		orders:             make(map[int64]*Order),
+		statusChanges:      make(map[int64]*OrderStatusChange),
+		payments:           make(map[int64]*Payment),
+		paymentRefunds:     make(map[int64]*PaymentRefund),
+		giftCards:          make(map[int64]*GiftCard),
+		giftCardTxs:        make(map[int64]*GiftCardTransaction),
+		storeCredits:       make(map[int64]money.Money),
+		storeCreditTxs:     make(map[int64]*StoreCreditTransaction),
+		loyaltyPoints:      make(map[int64]int64),
+		loyaltyTxs:         make(map[int64]*LoyaltyTransaction),
+		invoices:           make(map[int64]*Invoice),
+		creditNotes:        make(map[int64]*CreditNote),
+		returns:            make(map[int64]*Return),
+		carts:              make(map[int64]*Cart),
+		wishlists:          make(map[int64]*Wishlist),
+		reviews:            make(map[int64]*Review),
+		users:              make(map[int64]*User),
+		sessions:           make(map[string]*Session),
+		notificationStates: make(map[int64]*NotificationState),
+		notificationEvents: make(map[int64]*NotificationEvent),
+	}
+
+	// the main warehouse comes with the migration adding warehouses
+	ms.seq["warehouses"] = MainWarehouseID
+	ms.warehouses[MainWarehouseID] = &Warehouse{ID: MainWarehouseID, Code: "MAIN", Name: "Main warehouse", CreatedAt: time.Now()}
+
+	return ms
+}
+
+// redemption is a row of promotion_redemptions.
+type redemption struct {
+	PromotionID int64
+	OrderID     int64
+	UserID      int64
+	Amount      money.Money
+}
+
+// nextID emulates an AUTO_INCREMENT column; callers must hold the write lock.
+func (ms *MemoryStorer) nextID(table string) int64 {
+	ms.seq[table]++
+	return ms.seq[table]
+}
+
+func copyInvoice(inv *Invoice) *Invoice {
+	c := *inv
+	c.Seller.Address = append(StringList(nil), inv.Seller.Address...)
+	c.Items = append(InvoiceLines(nil), inv.Items...)
+	if inv.BillingAddress != nil {
+		a := *inv.BillingAddress
+		c.BillingAddress = &a
+	}
+	if inv.ShippingAddress != nil {
+		a := *inv.ShippingAddress
+		c.ShippingAddress = &a
+	}
+	return &c
+}
+
+func copyOrder(o *Order) *Order {
+	c := *o
+	c.Items = append([]OrderItem(nil), o.Items...)
+	c.Discounts = append(OrderDiscounts(nil), o.Discounts...)
+	if o.ShippingAddress != nil {
+		a := *o.ShippingAddress
+		c.ShippingAddress = &a
+	}
+	if o.BillingAddress != nil {
+		a := *o.BillingAddress
+		c.BillingAddress = &a
+	}
+	return &c
+}
+
+func copyShippingZone(z *ShippingZone) *ShippingZone {
+	c := *z
+	c.Countries = slices.Clone(z.Countries)
+	c.PostalPrefixes = slices.Clone(z.PostalPrefixes)
+	return &c
+}
+
+func copyShippingMethod(m *ShippingMethod) *ShippingMethod {
+	c := *m
+	c.Tiers = slices.Clone(m.Tiers)
+	if m.FreeAbove != nil {
+		freeAbove := *m.FreeAbove
+		c.FreeAbove = &freeAbove
+	}
+	return &c
+}
+
+func copyVariant(v *Variant) *Variant {
+	c := *v
+	c.Options = maps.Clone(v.Options)
+	if v.Price != nil {
+		price := *v.Price
+		c.Price = &price
+	}
+	return &c
+}
+
+func copyScheduledPrice(sp *ScheduledPrice) *ScheduledPrice {
+	c := *sp
+	if sp.VariantID != nil {
+		id := *sp.VariantID
+		c.VariantID = &id
+	}
+	if sp.QuantityCap != nil {
+		limit := *sp.QuantityCap
+		c.QuantityCap = &limit
+	}
+	return &c
+}
+
+func copyPromotion(p *Promotion) *Promotion {
+	c := *p
+	if p.Code != nil {
+		code := *p.Code
+		c.Code = &code
+	}
+	return &c
+}
+
+func copyCart(c *Cart) *Cart {
+	cc := *c
+	cc.Items = append([]CartItem(nil), c.Items...)
+	return &cc
+}
+
+func copyStockMovement(m *StockMovement) *StockMovement {
+	c := *m
+	for _, ref := range []**int64{&c.ActorID, &c.OrderID, &c.OtherWarehouseID} {
+		if *ref != nil {
+			id := **ref
+			*ref = &id
+		}
+	}
+	return &c
+}
+
+func copyGiftCard(g *GiftCard) *GiftCard {
+	c := *g
+	for _, ref := range []**int64{&c.PurchaserID, &c.OrderID, &c.IssuedBy} {
+		if *ref != nil {
+			id := **ref
+			*ref = &id
+		}
+	}
+	if g.ExpiresAt != nil {
+		t := *g.ExpiresAt
+		c.ExpiresAt = &t
+	}
+	return &c
+}
+
+func copyGiftCardTransaction(t *GiftCardTransaction) *GiftCardTransaction {
+	c := *t
+	for _, ref := range []**int64{&c.OrderID, &c.ActorID} {
+		if *ref != nil {
+			id := **ref
+			*ref = &id
+		}
+	}
+	return &c
+}
+
+func copyStoreCreditTransaction(t *StoreCreditTransaction) *StoreCreditTransaction {
+	c := *t
+	for _, ref := range []**int64{&c.OrderID, &c.ActorID} {
+		if *ref != nil {
+			id := **ref
+			*ref = &id
+		}
+	}
+	return &c
+}
+
+func copyLoyaltyTransaction(t *LoyaltyTransaction) *LoyaltyTransaction {
+	c := *t
+	if t.OrderID != nil {
+		id := *t.OrderID
+		c.OrderID = &id
+	}
+	if t.ExpiresAt != nil {
+		e := *t.ExpiresAt
+		c.ExpiresAt = &e
+	}
+	return &c
+}
+
+func copyWishlist(w *Wishlist) *Wishlist {
+	c := *w
+	c.Items = append([]WishlistItem(nil), w.Items...)
+	return &c
+}
+
+func (ms *MemoryStorer) CreateProduct(ctx context.Context, p *Product) (*Product, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if err := ms.checkCategoryRef(p.CategoryID); err != nil {
+		return nil, fmt.Errorf("error inserting product: %w", err)
+	}
+
+	p.ID = ms.nextID("products")
+	c := *p
+	c.CreatedAt = time.Now()
+	ms.products[p.ID] = &c
+	ms.receiveOpeningStock(p.ID, 0, p.CountInStock)
+
+	return p, nil
+}
+
+func (ms *MemoryStorer) GetProduct(ctx context.Context, id int64) (*Product, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	p, ok := ms.products[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting product: %w", sql.ErrNoRows)
+	}
+
+	c := *p
+	return &c, nil
+}
+
+func (ms *MemoryStorer) GetProducts(ctx context.Context, ids []int64) ([]*Product, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var products []*Product
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		p, ok := ms.products[id]
+		if !ok || seen[id] {
+			continue
+		}
+		seen[id] = true
+		c := *p
+		products = append(products, &c)
+	}
+	sort.Slice(products, func(i, j int) bool { return products[i].ID < products[j].ID })
+
+	return products, nil
+}
+
+func (ms *MemoryStorer) ListProducts(ctx context.Context, params *ListProductsParams) (*ProductPage, error) {
+	if err := params.normalize(); err != nil {
+		return nil, fmt.Errorf("error listing products: %w", err)
+	}
+	cursor, err := params.cursor()
+	if err != nil {
+		return nil, fmt.Errorf("error listing products: %w", err)
+	}
+	var after *Product
+	if cursor != nil {
+		after, err = cursorProduct(cursor)
+		if err != nil {
+			return nil, fmt.Errorf("error listing products: %w", err)
+		}
+	}
+
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	// direction flips comparisons so that descending pages reuse the same
+	// "strictly after the cursor" check as ascending ones
+	direction := 1
+	if params.Desc {
+		direction = -1
+	}
+
+	now := time.Now()
+	var products []*Product
+	for _, p := range ms.products {
+		c := *p
+		c.ListPrice = ms.listPrice(p, now)
+		if !matchesProductFilters(&c, params) {
+			continue
+		}
+		if after != nil && direction*compareProducts(&c, after, params.SortBy) <= 0 {
+			continue
+		}
+		products = append(products, &c)
+	}
+	sort.Slice(products, func(i, j int) bool {
+		return direction*compareProducts(products[i], products[j], params.SortBy) < 0
+	})
+	if len(products) > params.Limit+1 {
+		products = products[:params.Limit+1]
+	}
+
+	return newProductPage(products, params), nil
+}
+
+func matchesProductFilters(p *Product, params *ListProductsParams) bool {
+	switch {
+	case len(params.CategoryIDs) > 0 && (p.CategoryID == nil || !slices.Contains(params.CategoryIDs, *p.CategoryID)):
+		return false
+	case params.MinPrice != nil && p.ListPrice.Cmp(*params.MinPrice) < 0:
+		return false
+	case params.MaxPrice != nil && p.ListPrice.Cmp(*params.MaxPrice) > 0:
+		return false
+	case params.MinRating != nil && p.Rating < *params.MinRating:
+		return false
+	case params.InStock && p.CountInStock <= 0:
+		return false
+	default:
+		return true
+	}
+}
+
+// listPrice is the lowest price scheduled for the whole of p running at t, or
+// else its own price.
+func (ms *MemoryStorer) listPrice(p *Product, t time.Time) money.Money {
+	price := p.Price
+	scheduled := false
+	for _, sp := range ms.scheduledPrices {
+		if sp.ProductID != p.ID || sp.VariantID != nil || !sp.Running(t) {
+			continue
+		}
+		if !scheduled || sp.Price.Cmp(price) < 0 {
+			price, scheduled = sp.Price, true
+		}
+	}
+
+	return price
+}
+
+// SearchProducts ranks every product in process with the same scoring that
+// MySQLStorer uses for its typo tolerant pass.
+func (ms *MemoryStorer) SearchProducts(ctx context.Context, params *SearchProductsParams) ([]*ProductSearchHit, error) {
+	terms := searchTerms(params.Query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	ms.mu.RLock()
+	candidates := make([]*Product, 0, len(ms.products))
+	for _, p := range ms.products {
+		c := *p
+		candidates = append(candidates, &c)
+	}
+	ms.mu.RUnlock()
+
+	hits := rankProducts(candidates, terms, isShortQuery(terms))
+	if limit := pageSize(params.Limit); len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	return hits, nil
+}
+
+func (ms *MemoryStorer) UpdateProduct(ctx context.Context, p *Product) (*Product, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if err := ms.checkCategoryRef(p.CategoryID); err != nil {
+		return nil, fmt.Errorf("error updating product: %w", err)
+	}
+
+	if existing, ok := ms.products[p.ID]; ok {
+		c := *p
+		c.CreatedAt = existing.CreatedAt
+		// derived from reviews and stock movements, like the MySQL update
+		// leaves them alone
+		c.Rating = existing.Rating
+		c.NumReviews = existing.NumReviews
+		c.CountInStock = existing.CountInStock
+		ms.products[p.ID] = &c
+	}
+
+	return p, nil
+}
+
+func (ms *MemoryStorer) DeleteProduct(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	for _, o := range ms.orders {
+		for _, oi := range o.Items {
+			if oi.ProductID == id {
+				return fmt.Errorf("error deleting product: product %d is referenced by order %d", id, o.ID)
+			}
+		}
+	}
+	for _, p := range ms.promotions {
+		if p.ProductID != nil && *p.ProductID == id {
+			return fmt.Errorf("error deleting product: product %d is referenced by promotion %d", id, p.ID)
+		}
+	}
+
+	// variants, scheduled prices, stock, cart items and reviews cascade
+	// like their foreign keys
+	for variantID, v := range ms.variants {
+		if v.ProductID == id {
+			delete(ms.variants, variantID)
+		}
+	}
+	for spID, sp := range ms.scheduledPrices {
+		if sp.ProductID == id {
+			delete(ms.scheduledPrices, spID)
+		}
+	}
+	for k := range ms.stockLevels {
+		if k.ProductID == id {
+			delete(ms.stockLevels, k)
+		}
+	}
+	for mID, m := range ms.stockMovements {
+		if m.ProductID == id {
+			delete(ms.stockMovements, mID)
+		}
+	}
+	for reviewID, r := range ms.reviews {
+		if r.ProductID == id {
+			delete(ms.reviews, reviewID)
+		}
+	}
+	for _, c := range ms.carts {
+		c.Items = slices.DeleteFunc(c.Items, func(ci CartItem) bool {
+			return ci.ProductID == id
+		})
+	}
+	for _, w := range ms.wishlists {
+		w.Items = slices.DeleteFunc(w.Items, func(wi WishlistItem) bool {
+			return wi.ProductID == id
+		})
+	}
+
+	delete(ms.products, id)
+	return nil
+}
+
+// checkVariantSKU emulates the unique key on product_variants.sku; callers
+// must hold the lock.
+func (ms *MemoryStorer) checkVariantSKU(v *Variant) error {
+	for _, other := range ms.variants {
+		if other.ID != v.ID && other.SKU == v.SKU {
+			return ErrSKUExists
+		}
+	}
+	return nil
+}
+
+// updateProductStock sets a product's count_in_stock to the sum of its
+// variants' stock; callers must hold the write lock.
+func (ms *MemoryStorer) updateProductStock(productID int64) {
+	p, ok := ms.products[productID]
+	if !ok {
+		return
+	}
+
+	p.CountInStock = 0
+	for _, v := range ms.variants {
+		if v.ProductID == productID {
+			p.CountInStock += v.CountInStock
+		}
+	}
+}
+
+func (ms *MemoryStorer) CreateVariant(ctx context.Context, v *Variant) (*Variant, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.products[v.ProductID]; !ok {
+		return nil, fmt.Errorf("error inserting variant: product %d does not exist", v.ProductID)
+	}
+	if err := ms.checkVariantSKU(v); err != nil {
+		return nil, fmt.Errorf("error creating variant: %w", err)
+	}
+
+	v.ID = ms.nextID("product_variants")
+	c := copyVariant(v)
+	c.CreatedAt = time.Now()
+	ms.variants[v.ID] = c
+	ms.receiveOpeningStock(v.ProductID, v.ID, v.CountInStock)
+	ms.updateProductStock(v.ProductID)
+
+	return v, nil
+}
+
+func (ms *MemoryStorer) GetVariant(ctx context.Context, id int64) (*Variant, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	v, ok := ms.variants[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting variant: %w", sql.ErrNoRows)
+	}
+
+	return copyVariant(v), nil
+}
+
+func (ms *MemoryStorer) ListProductVariants(ctx context.Context, productIDs []int64) ([]*Variant, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var variants []*Variant
+	for _, id := range sortedKeys(ms.variants) {
+		if v := ms.variants[id]; slices.Contains(productIDs, v.ProductID) {
+			variants = append(variants, copyVariant(v))
+		}
+	}
+	sort.SliceStable(variants, func(i, j int) bool { return variants[i].ProductID < variants[j].ProductID })
+
+	return variants, nil
+}
+
+func (ms *MemoryStorer) UpdateVariant(ctx context.Context, v *Variant) (*Variant, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	existing, ok := ms.variants[v.ID]
+	if !ok {
+		return nil, fmt.Errorf("error getting variant: %w", sql.ErrNoRows)
+	}
+	if err := ms.checkVariantSKU(v); err != nil {
+		return nil, fmt.Errorf("error updating variant: %w", err)
+	}
+
+	c := copyVariant(v)
+	c.ProductID = existing.ProductID
+	c.CountInStock = existing.CountInStock
+	c.CreatedAt = existing.CreatedAt
+	ms.variants[v.ID] = c
+
+	return v, nil
+}
+
+func (ms *MemoryStorer) DeleteVariant(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	v, ok := ms.variants[id]
+	if !ok {
+		return fmt.Errorf("error getting variant: %w", sql.ErrNoRows)
+	}
+	for _, o := range ms.orders {
+		for _, oi := range o.Items {
+			if oi.VariantID != nil && *oi.VariantID == id {
+				return fmt.Errorf("error deleting variant: %w", ErrVariantInUse)
+			}
+		}
+	}
+
+	for _, c := range ms.carts {
+		c.Items = slices.DeleteFunc(c.Items, func(ci CartItem) bool {
+			return ci.VariantID == id
+		})
+	}
+	for spID, sp := range ms.scheduledPrices {
+		if sp.VariantID != nil && *sp.VariantID == id {
+			delete(ms.scheduledPrices, spID)
+		}
+	}
+	for _, w := range ms.wishlists {
+		w.Items = slices.DeleteFunc(w.Items, func(wi WishlistItem) bool {
+			return wi.VariantID == id
+		})
+	}
+
+	for _, k := range sortedStockKeys(ms.stockLevels) {
+		if k.ProductID != v.ProductID || k.VariantID != id {
+			continue
+		}
+		if n := ms.stockLevels[k]; n != 0 {
+			ms.insertStockMovement(&StockMovement{
+				WarehouseID: k.WarehouseID,
+				ProductID:   k.ProductID,
+				VariantID:   k.VariantID,
+				Kind:        StockAdjustment,
+				Quantity:    -n,
+				Reason:      fmt.Sprintf("variant %d deleted", id),
+			})
+		}
+		delete(ms.stockLevels, k)
+	}
+
+	delete(ms.variants, id)
+	ms.updateProductStock(v.ProductID)
+	return nil
+}
+
+func (ms *MemoryStorer) CreateScheduledPrice(ctx context.Context, sp *ScheduledPrice) (*ScheduledPrice, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.products[sp.ProductID]; !ok {
+		return nil, fmt.Errorf("error inserting scheduled price: product %d does not exist", sp.ProductID)
+	}
+	if sp.VariantID != nil {
+		if _, ok := ms.variants[*sp.VariantID]; !ok {
+			return nil, fmt.Errorf("error inserting scheduled price: variant %d does not exist", *sp.VariantID)
+		}
+	}
+
+	sp.ID = ms.nextID("product_prices")
+	c := copyScheduledPrice(sp)
+	c.QuantitySold = 0
+	c.CreatedAt = time.Now()
+	ms.scheduledPrices[sp.ID] = c
+
+	return sp, nil
+}
+
+func (ms *MemoryStorer) GetScheduledPrice(ctx context.Context, id int64) (*ScheduledPrice, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	sp, ok := ms.scheduledPrices[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting scheduled price: %w", sql.ErrNoRows)
+	}
+
+	return copyScheduledPrice(sp), nil
+}
+
+func (ms *MemoryStorer) ListScheduledPrices(ctx context.Context, productID int64) ([]*ScheduledPrice, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var prices []*ScheduledPrice
+	for _, id := range sortedKeys(ms.scheduledPrices) {
+		if sp := ms.scheduledPrices[id]; sp.ProductID == productID {
+			prices = append(prices, copyScheduledPrice(sp))
+		}
+	}
+	slices.SortStableFunc(prices, func(a, b *ScheduledPrice) int {
+		return a.StartsAt.Compare(b.StartsAt)
+	})
+
+	return prices, nil
+}
+
+func (ms *MemoryStorer) ListRunningScheduledPrices(ctx context.Context, productIDs []int64, t time.Time) ([]*ScheduledPrice, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var prices []*ScheduledPrice
+	for _, id := range sortedKeys(ms.scheduledPrices) {
+		if sp := ms.scheduledPrices[id]; slices.Contains(productIDs, sp.ProductID) && sp.Running(t) {
+			prices = append(prices, copyScheduledPrice(sp))
+		}
+	}
+	slices.SortStableFunc(prices, func(a, b *ScheduledPrice) int {
+		return cmp.Compare(a.ProductID, b.ProductID)
+	})
+
+	return prices, nil
+}
+
+func (ms *MemoryStorer) UpdateScheduledPrice(ctx context.Context, sp *ScheduledPrice) (*ScheduledPrice, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if existing, ok := ms.scheduledPrices[sp.ID]; ok {
+		c := copyScheduledPrice(sp)
+		c.ProductID = existing.ProductID
+		c.QuantitySold = existing.QuantitySold
+		c.CreatedAt = existing.CreatedAt
+		ms.scheduledPrices[sp.ID] = c
+	}
+
+	return sp, nil
+}
+
+func (ms *MemoryStorer) DeleteScheduledPrice(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	// order lines keep their price, like the foreign key setting their
+	// scheduled_price_id to null
+	for _, o := range ms.orders {
+		for i := range o.Items {
+			if oi := &o.Items[i]; oi.ScheduledPriceID != nil && *oi.ScheduledPriceID == id {
+				oi.ScheduledPriceID = nil
+			}
+		}
+	}
+
+	delete(ms.scheduledPrices, id)
+	return nil
+}
+
+// checkScheduledPrices emulates the checks of sellScheduledPrices; callers
+// must hold the lock.
+func (ms *MemoryStorer) checkScheduledPrices(items []OrderItem) error {
+	lines := scheduledPriceLines(items)
+	now := time.Now()
+	for _, id := range sortedKeys(lines) {
+		sp, ok := ms.scheduledPrices[id]
+		if !ok {
+			return &ScheduledPriceSoldOutError{ScheduledPriceID: id, ProductID: lines[id][0].ProductID, Changed: true}
+		}
+		if err := checkScheduledPrice(sp, lines[id], now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addScheduledPriceSales adds the units sold on items to the scheduled prices
+// they were sold at, or takes them off again for a negative sign; callers
+// must hold the write lock.
+func (ms *MemoryStorer) addScheduledPriceSales(items []OrderItem, sign int64) {
+	for _, oi := range items {
+		if oi.ScheduledPriceID == nil {
+			continue
+		}
+		if sp, ok := ms.scheduledPrices[*oi.ScheduledPriceID]; ok {
+			sp.QuantitySold += sign * oi.Quantity
+		}
+	}
+}
+
+// checkWarehouseCode emulates the unique key on warehouses.code; callers
+// must hold the lock.
+func (ms *MemoryStorer) checkWarehouseCode(w *Warehouse) error {
+	for _, other := range ms.warehouses {
+		if other.ID != w.ID && other.Code == w.Code {
+			return ErrWarehouseExists
+		}
+	}
+	return nil
+}
+
+func (ms *MemoryStorer) CreateWarehouse(ctx context.Context, w *Warehouse) (*Warehouse, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if err := ms.checkWarehouseCode(w); err != nil {
+		return nil, fmt.Errorf("error inserting warehouse: %w", err)
+	}
+
+	w.ID = ms.nextID("warehouses")
+	c := *w
+	c.CreatedAt = time.Now()
+	ms.warehouses[w.ID] = &c
+
+	return w, nil
+}
+
+func (ms *MemoryStorer) GetWarehouse(ctx context.Context, id int64) (*Warehouse, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	w, ok := ms.warehouses[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting warehouse: %w", sql.ErrNoRows)
+	}
+
+	c := *w
+	return &c, nil
+}
+
+func (ms *MemoryStorer) ListWarehouses(ctx context.Context) ([]*Warehouse, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	warehouses := make([]*Warehouse, 0, len(ms.warehouses))
+	for _, id := range sortedKeys(ms.warehouses) {
+		c := *ms.warehouses[id]
+		warehouses = append(warehouses, &c)
+	}
+
+	return warehouses, nil
+}
+
+func (ms *MemoryStorer) UpdateWarehouse(ctx context.Context, w *Warehouse) (*Warehouse, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	existing, ok := ms.warehouses[w.ID]
+	if !ok {
+		return w, nil
+	}
+	if err := ms.checkWarehouseCode(w); err != nil {
+		return nil, fmt.Errorf("error updating warehouse: %w", err)
+	}
+
+	c := *w
+	c.CreatedAt = existing.CreatedAt
+	ms.warehouses[w.ID] = &c
+
+	return w, nil
+}
+
+func (ms *MemoryStorer) ListStockLevels(ctx context.Context, productID int64) ([]*StockLevel, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var levels []*StockLevel
+	for _, k := range sortedStockKeys(ms.stockLevels) {
+		if k.ProductID == productID {
+			levels = append(levels, &StockLevel{
+				WarehouseID: k.WarehouseID,
+				ProductID:   k.ProductID,
+				VariantID:   k.VariantID,
+				Quantity:    ms.stockLevels[k],
+			})
+		}
+	}
+
+	return levels, nil
+}
+
+func (ms *MemoryStorer) RecordStockMovements(ctx context.Context, movements []*StockMovement) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	for _, m := range movements {
+		if _, ok := ms.products[m.ProductID]; !ok {
+			return fmt.Errorf("error recording stock movements: %w", sql.ErrNoRows)
+		}
+		if v, ok := ms.variants[m.VariantID]; m.VariantID != 0 && (!ok || v.ProductID != m.ProductID) {
+			return fmt.Errorf("error recording stock movements: %w", sql.ErrNoRows)
+		}
+		if _, ok := ms.warehouses[m.WarehouseID]; !ok {
+			return fmt.Errorf("error recording stock movements: warehouse %d does not exist", m.WarehouseID)
+		}
+	}
+
+	net := netStockChanges(movements)
+	for _, k := range sortedStockKeys(net) {
+		if available := ms.stockLevels[k]; available+net[k] < 0 {
+			err := &InsufficientStockError{
+				ProductID: k.ProductID,
+				Name:      ms.products[k.ProductID].Name,
+				Requested: -net[k],
+				Available: available,
+			}
+			if v, ok := ms.variants[k.VariantID]; ok {
+				err.SKU = v.SKU
+			}
+			return fmt.Errorf("error recording stock movements: %w", err)
+		}
+	}
+
+	ms.applyStockMovements(movements)
+	return nil
+}
+
+func (ms *MemoryStorer) ListStockMovements(ctx context.Context, params *ListStockMovementsParams) ([]*StockMovement, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	limit := pageSize(params.Limit)
+	ids := sortedKeys(ms.stockMovements)
+	var movements []*StockMovement
+	for i := len(ids) - 1; i >= 0 && len(movements) < limit; i-- {
+		m := ms.stockMovements[ids[i]]
+		if m.ProductID != params.ProductID ||
+			params.WarehouseID != 0 && m.WarehouseID != params.WarehouseID ||
+			params.BeforeID != 0 && m.ID >= params.BeforeID {
+			continue
+		}
+		movements = append(movements, copyStockMovement(m))
+	}
+
+	return movements, nil
+}
+
+// checkCategoryRef emulates the foreign key from a product or category to its
+// category; callers must hold the lock.
+func (ms *MemoryStorer) checkCategoryRef(id *int64) error {
+	if id == nil {
+		return nil
+	}
+	if _, ok := ms.categories[*id]; !ok {
+		return fmt.Errorf("category %d does not exist", *id)
+	}
+	return nil
+}
+
+// checkCategorySlug emulates the unique key on categories.slug; callers must
+// hold the lock.
+func (ms *MemoryStorer) checkCategorySlug(c *Category) error {
+	for _, other := range ms.categories {
+		if other.ID != c.ID && other.Slug == c.Slug {
+			return ErrCategoryExists
+		}
+	}
+	return nil
+}
+
+func (ms *MemoryStorer) CreateCategory(ctx context.Context, c *Category) (*Category, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if err := ms.checkCategoryRef(c.ParentID); err != nil {
+		return nil, fmt.Errorf("error inserting category: %w", err)
+	}
+	if err := ms.checkCategorySlug(c); err != nil {
+		return nil, fmt.Errorf("error inserting category: %w", err)
+	}
+
+	c.ID = ms.nextID("categories")
+	cc := *c
+	cc.CreatedAt = time.Now()
+	ms.categories[c.ID] = &cc
+
+	return c, nil
+}
+
+func (ms *MemoryStorer) GetCategory(ctx context.Context, id int64) (*Category, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	c, ok := ms.categories[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting category: %w", sql.ErrNoRows)
+	}
+
+	cc := *c
+	return &cc, nil
+}
+
+func (ms *MemoryStorer) GetCategoryBySlug(ctx context.Context, slug string) (*Category, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	for _, id := range sortedKeys(ms.categories) {
+		if c := ms.categories[id]; c.Slug == slug {
+			cc := *c
+			return &cc, nil
+		}
+	}
+
+	return nil, fmt.Errorf("error getting category: %w", sql.ErrNoRows)
+}
+
+func (ms *MemoryStorer) ListCategories(ctx context.Context) ([]*Category, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	categories := make([]*Category, 0, len(ms.categories))
+	for _, id := range sortedKeys(ms.categories) {
+		c := *ms.categories[id]
+		categories = append(categories, &c)
+	}
+	sort.SliceStable(categories, func(i, j int) bool {
+		if categories[i].SortOrder != categories[j].SortOrder {
+			return categories[i].SortOrder < categories[j].SortOrder
+		}
+		return categories[i].Name < categories[j].Name
+	})
+
+	return categories, nil
+}
+
+func (ms *MemoryStorer) UpdateCategory(ctx context.Context, c *Category) (*Category, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if err := ms.checkCategoryRef(c.ParentID); err != nil {
+		return nil, fmt.Errorf("error updating category: %w", err)
+	}
+	if err := ms.checkCategorySlug(c); err != nil {
+		return nil, fmt.Errorf("error updating category: %w", err)
+	}
+
+	if existing, ok := ms.categories[c.ID]; ok {
+		cc := *c
+		cc.CreatedAt = existing.CreatedAt
+		ms.categories[c.ID] = &cc
+	}
+
+	return c, nil
+}
+
+func (ms *MemoryStorer) DeleteCategory(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	for _, c := range ms.categories {
+		if c.ParentID != nil && *c.ParentID == id {
+			return fmt.Errorf("error deleting category: %w", ErrCategoryInUse)
+		}
+	}
+	for _, p := range ms.products {
+		if p.CategoryID != nil && *p.CategoryID == id {
+			return fmt.Errorf("error deleting category: %w", ErrCategoryInUse)
+		}
+	}
+	for _, p := range ms.promotions {
+		if p.CategoryID != nil && *p.CategoryID == id {
+			return fmt.Errorf("error deleting category: %w", ErrCategoryInUse)
+		}
+	}
+
+	delete(ms.categories, id)
+	return nil
+}
+
+// checkPromotionLimits emulates the usage limit checks of redeemPromotions;
+// callers must hold the lock.
+func (ms *MemoryStorer) checkPromotionLimits(o *Order) error {
+	for _, d := range o.Discounts {
+		p, ok := ms.promotions[d.PromotionID]
+		if !ok {
+			return fmt.Errorf("error locking promotion: %w", sql.ErrNoRows)
+		}
+
+		var used, usedByUser int64
+		for _, r := range ms.redemptions {
+			if r.PromotionID == p.ID {
+				used++
+				if r.UserID == o.UserID {
+					usedByUser++
+				}
+			}
+		}
+		if p.UsageLimit > 0 && used >= p.UsageLimit {
+			return &PromotionLimitError{PromotionID: p.ID, Name: p.Name}
+		}
+		if p.PerCustomerLimit > 0 && usedByUser >= p.PerCustomerLimit {
+			return &PromotionLimitError{PromotionID: p.ID, Name: p.Name, PerCustomer: true}
+		}
+	}
+
+	return nil
+}
+
+// withTimesUsed copies p and counts its redemptions; callers must hold the
+// lock.
+func (ms *MemoryStorer) withTimesUsed(p *Promotion) *Promotion {
+	c := copyPromotion(p)
+	c.TimesUsed = 0
+	for _, r := range ms.redemptions {
+		if r.PromotionID == p.ID {
+			c.TimesUsed++
+		}
+	}
+	return c
+}
+
+// checkPromotionRefs emulates the unique key on promotions.code and the
+// foreign keys to products and categories; callers must hold the lock.
+func (ms *MemoryStorer) checkPromotionRefs(p *Promotion) error {
+	if p.Code != nil {
+		for _, other := range ms.promotions {
+			if other.ID != p.ID && other.Code != nil && *other.Code == *p.Code {
+				return ErrPromotionExists
+			}
+		}
+	}
+	if p.ProductID != nil {
+		if _, ok := ms.products[*p.ProductID]; !ok {
+			return fmt.Errorf("product %d does not exist", *p.ProductID)
+		}
+	}
+	return ms.checkCategoryRef(p.CategoryID)
+}
+
+func (ms *MemoryStorer) CreatePromotion(ctx context.Context, p *Promotion) (*Promotion, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if err := ms.checkPromotionRefs(p); err != nil {
+		return nil, fmt.Errorf("error inserting promotion: %w", err)
+	}
+
+	p.ID = ms.nextID("promotions")
+	c := copyPromotion(p)
+	c.CreatedAt = time.Now()
+	ms.promotions[p.ID] = c
+
+	return p, nil
+}
+
+func (ms *MemoryStorer) GetPromotion(ctx context.Context, id int64) (*Promotion, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	p, ok := ms.promotions[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting promotion: %w", sql.ErrNoRows)
+	}
+
+	return ms.withTimesUsed(p), nil
+}
+
+func (ms *MemoryStorer) GetPromotionByCode(ctx context.Context, code string) (*Promotion, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	for _, id := range sortedKeys(ms.promotions) {
+		if p := ms.promotions[id]; p.Code != nil && *p.Code == code {
+			return ms.withTimesUsed(p), nil
+		}
+	}
+
+	return nil, fmt.Errorf("error getting promotion: %w", sql.ErrNoRows)
+}
+
+func (ms *MemoryStorer) ListPromotions(ctx context.Context) ([]*Promotion, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	promotions := make([]*Promotion, 0, len(ms.promotions))
+	for _, id := range sortedKeys(ms.promotions) {
+		promotions = append(promotions, ms.withTimesUsed(ms.promotions[id]))
+	}
+
+	return promotions, nil
+}
+
+func (ms *MemoryStorer) ListAutomaticPromotions(ctx context.Context, t time.Time) ([]*Promotion, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var promotions []*Promotion
+	for _, id := range sortedKeys(ms.promotions) {
+		if p := ms.promotions[id]; p.Code == nil && p.Running(t) {
+			promotions = append(promotions, ms.withTimesUsed(p))
+		}
+	}
+
+	return promotions, nil
+}
+
+func (ms *MemoryStorer) CountUserRedemptions(ctx context.Context, userID int64, promotionIDs []int64) (map[int64]int64, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	counts := make(map[int64]int64, len(promotionIDs))
+	for _, r := range ms.redemptions {
+		if r.UserID == userID && slices.Contains(promotionIDs, r.PromotionID) {
+			counts[r.PromotionID]++
+		}
+	}
+
+	return counts, nil
+}
+
+func (ms *MemoryStorer) UpdatePromotion(ctx context.Context, p *Promotion) (*Promotion, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if err := ms.checkPromotionRefs(p); err != nil {
+		return nil, fmt.Errorf("error updating promotion: %w", err)
+	}
+
+	if existing, ok := ms.promotions[p.ID]; ok {
+		c := copyPromotion(p)
+		c.CreatedAt = existing.CreatedAt
+		ms.promotions[p.ID] = c
+	}
+
+	return p, nil
+}
+
+func (ms *MemoryStorer) DeletePromotion(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	for _, r := range ms.redemptions {
+		if r.PromotionID == id {
+			return fmt.Errorf("error deleting promotion: %w", ErrPromotionInUse)
+		}
+	}
+
+	delete(ms.promotions, id)
+	return nil
+}
+
+// checkTaxRateKey emulates the unique key on the jurisdiction and tax class
+// of tax_rates; callers must hold the lock.
+func (ms *MemoryStorer) checkTaxRateKey(r *TaxRate) error {
+	for _, other := range ms.taxRates {
+		if other.ID != r.ID && other.Country == r.Country && other.Region == r.Region && other.TaxClass == r.TaxClass {
+			return ErrTaxRateExists
+		}
+	}
+	return nil
+}
+
+func (ms *MemoryStorer) CreateTaxRate(ctx context.Context, r *TaxRate) (*TaxRate, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if err := ms.checkTaxRateKey(r); err != nil {
+		return nil, fmt.Errorf("error inserting tax rate: %w", err)
+	}
+
+	r.ID = ms.nextID("tax_rates")
+	c := *r
+	c.CreatedAt = time.Now()
+	ms.taxRates[r.ID] = &c
+
+	return r, nil
+}
+
+func (ms *MemoryStorer) GetTaxRate(ctx context.Context, id int64) (*TaxRate, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	r, ok := ms.taxRates[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting tax rate: %w", sql.ErrNoRows)
+	}
+
+	c := *r
+	return &c, nil
+}
+
+func (ms *MemoryStorer) ListTaxRates(ctx context.Context) ([]*TaxRate, error) {
+	return ms.listTaxRates(func(*TaxRate) bool { return true }), nil
+}
+
+func (ms *MemoryStorer) ListApplicableTaxRates(ctx context.Context, country, region string) ([]*TaxRate, error) {
+	return ms.listTaxRates(func(r *TaxRate) bool {
+		return r.Country == country && (r.Region == region || r.Region == "") || r.Country == "" && r.Region == ""
+	}), nil
+}
+
+// listTaxRates returns the rates matching keep in the order of the MySQL
+// queries.
+func (ms *MemoryStorer) listTaxRates(keep func(*TaxRate) bool) []*TaxRate {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var rates []*TaxRate
+	for _, r := range ms.taxRates {
+		if keep(r) {
+			c := *r
+			rates = append(rates, &c)
+		}
+	}
+	slices.SortFunc(rates, func(a, b *TaxRate) int {
+		return cmp.Or(cmp.Compare(a.Country, b.Country), cmp.Compare(a.Region, b.Region), cmp.Compare(a.TaxClass, b.TaxClass))
+	})
+
+	return rates
+}
+
+func (ms *MemoryStorer) UpdateTaxRate(ctx context.Context, r *TaxRate) (*TaxRate, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if err := ms.checkTaxRateKey(r); err != nil {
+		return nil, fmt.Errorf("error updating tax rate: %w", err)
+	}
+
+	if existing, ok := ms.taxRates[r.ID]; ok {
+		c := *r
+		c.CreatedAt = existing.CreatedAt
+		ms.taxRates[r.ID] = &c
+	}
+
+	return r, nil
+}
+
+func (ms *MemoryStorer) DeleteTaxRate(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	delete(ms.taxRates, id)
+	return nil
+}
+
+func (ms *MemoryStorer) CreateAddress(ctx context.Context, a *UserAddress) (*UserAddress, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if a.IsDefault {
+		ms.clearDefaultAddress(a.UserID)
+	}
+
+	a.ID = ms.nextID("addresses")
+	c := *a
+	c.CreatedAt = time.Now()
+	ms.addresses[a.ID] = &c
+
+	return a, nil
+}
+
+// clearDefaultAddress unsets the default flag on all addresses of a user;
+// callers must hold the write lock.
+func (ms *MemoryStorer) clearDefaultAddress(userID int64) {
+	for _, a := range ms.addresses {
+		if a.UserID == userID {
+			a.IsDefault = false
+		}
+	}
+}
+
+func (ms *MemoryStorer) GetAddress(ctx context.Context, id int64) (*UserAddress, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	a, ok := ms.addresses[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting address: %w", sql.ErrNoRows)
+	}
+
+	c := *a
+	return &c, nil
+}
+
+func (ms *MemoryStorer) ListUserAddresses(ctx context.Context, userID int64) ([]*UserAddress, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var addresses []*UserAddress
+	for _, id := range sortedKeys(ms.addresses) {
+		if a := ms.addresses[id]; a.UserID == userID {
+			c := *a
+			addresses = append(addresses, &c)
+		}
+	}
+	slices.SortStableFunc(addresses, func(a, b *UserAddress) int {
+		switch {
+		case a.IsDefault == b.IsDefault:
+			return 0
+		case a.IsDefault:
+			return -1
+		default:
+			return 1
+		}
+	})
+
+	return addresses, nil
+}
+
+func (ms *MemoryStorer) UpdateAddress(ctx context.Context, a *UserAddress) (*UserAddress, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if existing, ok := ms.addresses[a.ID]; ok {
+		if a.IsDefault {
+			ms.clearDefaultAddress(existing.UserID)
+		}
+		c := *a
+		c.UserID = existing.UserID
+		c.CreatedAt = existing.CreatedAt
+		ms.addresses[a.ID] = &c
+	}
+
+	return a, nil
+}
+
+func (ms *MemoryStorer) DeleteAddress(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	delete(ms.addresses, id)
+	return nil
+}
+
+func (ms *MemoryStorer) CreateShippingZone(ctx context.Context, z *ShippingZone) (*ShippingZone, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	z.ID = ms.nextID("shipping_zones")
+	c := copyShippingZone(z)
+	c.CreatedAt = time.Now()
+	ms.shippingZones[z.ID] = c
+
+	return z, nil
+}
+
+func (ms *MemoryStorer) GetShippingZone(ctx context.Context, id int64) (*ShippingZone, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	z, ok := ms.shippingZones[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting shipping zone: %w", sql.ErrNoRows)
+	}
+
+	return copyShippingZone(z), nil
+}
+
+func (ms *MemoryStorer) ListShippingZones(ctx context.Context) ([]*ShippingZone, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	zones := make([]*ShippingZone, 0, len(ms.shippingZones))
+	for _, id := range sortedKeys(ms.shippingZones) {
+		zones = append(zones, copyShippingZone(ms.shippingZones[id]))
+	}
+
+	return zones, nil
+}
+
+func (ms *MemoryStorer) UpdateShippingZone(ctx context.Context, z *ShippingZone) (*ShippingZone, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if existing, ok := ms.shippingZones[z.ID]; ok {
+		c := copyShippingZone(z)
+		c.CreatedAt = existing.CreatedAt
+		ms.shippingZones[z.ID] = c
+	}
+
+	return z, nil
+}
+
+func (ms *MemoryStorer) DeleteShippingZone(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	for methodID, m := range ms.shippingMethods {
+		if m.ZoneID == id {
+			delete(ms.shippingMethods, methodID)
+		}
+	}
+	delete(ms.shippingZones, id)
+	return nil
+}
+
+func (ms *MemoryStorer) CreateShippingMethod(ctx context.Context, m *ShippingMethod) (*ShippingMethod, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.shippingZones[m.ZoneID]; !ok {
+		return nil, fmt.Errorf("error inserting shipping method: zone %d does not exist", m.ZoneID)
+	}
+
+	m.ID = ms.nextID("shipping_methods")
+	c := copyShippingMethod(m)
+	c.CreatedAt = time.Now()
+	ms.shippingMethods[m.ID] = c
+
+	return m, nil
+}
+
+func (ms *MemoryStorer) GetShippingMethod(ctx context.Context, id int64) (*ShippingMethod, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	m, ok := ms.shippingMethods[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting shipping method: %w", sql.ErrNoRows)
+	}
+
+	return copyShippingMethod(m), nil
+}
+
+func (ms *MemoryStorer) ListShippingMethods(ctx context.Context, zoneIDs []int64) ([]*ShippingMethod, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var methods []*ShippingMethod
+	for _, id := range sortedKeys(ms.shippingMethods) {
+		if m := ms.shippingMethods[id]; slices.Contains(zoneIDs, m.ZoneID) {
+			methods = append(methods, copyShippingMethod(m))
+		}
+	}
+	slices.SortStableFunc(methods, func(a, b *ShippingMethod) int {
+		return cmp.Compare(a.ZoneID, b.ZoneID)
+	})
+
+	return methods, nil
+}
+
+func (ms *MemoryStorer) UpdateShippingMethod(ctx context.Context, m *ShippingMethod) (*ShippingMethod, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if existing, ok := ms.shippingMethods[m.ID]; ok {
+		c := copyShippingMethod(m)
+		c.ZoneID = existing.ZoneID
+		c.CreatedAt = existing.CreatedAt
+		ms.shippingMethods[m.ID] = c
+	}
+
+	return m, nil
+}
+
+func (ms *MemoryStorer) DeleteShippingMethod(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	delete(ms.shippingMethods, id)
+	return nil
+}
+
+func (ms *MemoryStorer) CreateOrder(ctx context.Context, o *Order, ne *NotificationEvent) (*Order, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.users[o.UserID]; !ok {
+		return nil, fmt.Errorf("error inserting order: user %d does not exist", o.UserID)
+	}
+
+	qty := orderedQuantities(o.Items)
+	plainQty := make(map[int64]int64, len(qty))
+	for _, oi := range o.Items {
+		if oi.VariantID == nil {
+			plainQty[oi.ProductID] += oi.Quantity
+		}
+	}
+	for _, id := range sortedKeys(qty) {
+		p, ok := ms.products[id]
+		if !ok {
+			return nil, fmt.Errorf("error reserving stock: %w", sql.ErrNoRows)
+		}
+		if n, ok := plainQty[id]; ok && p.CountInStock < n {
+			return nil, fmt.Errorf("error creating order: %w", &InsufficientStockError{
+				ProductID: p.ID,
+				Name:      p.Name,
+				Requested: n,
+				Available: p.CountInStock,
+			})
+		}
+	}
+	variantQty := orderedVariantQuantities(o.Items)
+	for _, id := range sortedKeys(variantQty) {
+		v, ok := ms.variants[id]
+		if !ok {
+			return nil, fmt.Errorf("error reserving stock: %w", sql.ErrNoRows)
+		}
+		if v.CountInStock < variantQty[id] {
+			return nil, fmt.Errorf("error creating order: %w", &InsufficientStockError{
+				ProductID: v.ProductID,
+				Name:      ms.products[v.ProductID].Name,
+				SKU:       v.SKU,
+				Requested: variantQty[id],
+				Available: v.CountInStock,
+			})
+		}
+	}
+	if err := ms.checkScheduledPrices(o.Items); err != nil {
+		return nil, fmt.Errorf("error creating order: %w", err)
+	}
+	if err := ms.checkPromotionLimits(o); err != nil {
+		return nil, fmt.Errorf("error creating order: %w", err)
+	}
+	if err := ms.checkTenders(o); err != nil {
+		return nil, fmt.Errorf("error creating order: %w", err)
+	}
+	sales, err := ms.allocateStock(o.Items)
+	if err != nil {
+		return nil, fmt.Errorf("error creating order: %w", err)
+	}
+
+	o.ID = ms.nextID("orders")
+	for i := range o.Items {
+		o.Items[i].OrderID = o.ID
+		o.Items[i].ID = ms.nextID("order_items")
+	}
+	for _, m := range sales {
+		m.Reason = fmt.Sprintf("order %d", o.ID)
+		m.ActorID = &o.UserID
+		m.OrderID = &o.ID
+	}
+	ms.applyStockMovements(sales)
+	ms.addScheduledPriceSales(o.Items, 1)
+
+	for _, d := range o.Discounts {
+		ms.redemptions[ms.nextID("promotion_redemptions")] = &redemption{
+			PromotionID: d.PromotionID,
+			OrderID:     o.ID,
+			UserID:      o.UserID,
+			Amount:      d.Amount,
+		}
+	}
+	ms.redeemTenders(o)
+
+	c := copyOrder(o)
+	c.Status = Pending
+	c.CreatedAt = time.Now()
+	// tenders are kept as payments
+	c.Tenders = nil
+	ms.orders[o.ID] = c
+
+	if ne != nil {
+		ne.OrderID = o.ID
+		ne.OrderStatus = Pending
+		ms.enqueueNotificationEvent(ne)
+	}
+
+	return o, nil
+}
+
+// allocateStock takes the quantities of items from the warehouses holding
+// them, lowest warehouse id first, and returns the sales to record; callers
+// must hold the lock and have checked the stock.
+func (ms *MemoryStorer) allocateStock(items []OrderItem) ([]*StockMovement, error) {
+	var sales []*StockMovement
+	wanted := orderedStock(items)
+	for _, k := range sortedStockKeys(wanted) {
+		n := wanted[k]
+		for _, wID := range sortedKeys(ms.warehouses) {
+			if n == 0 {
+				break
+			}
+			l := stockKey{WarehouseID: wID, ProductID: k.ProductID, VariantID: k.VariantID}
+			if ms.stockLevels[l] <= 0 {
+				continue
+			}
+			take := min(n, ms.stockLevels[l])
+			sales = append(sales, &StockMovement{
+				WarehouseID: wID,
+				ProductID:   k.ProductID,
+				VariantID:   k.VariantID,
+				Kind:        StockSale,
+				Quantity:    -take,
+			})
+			n -= take
+		}
+		if n > 0 {
+			return nil, fmt.Errorf("error reserving stock: warehouses hold %d units of product %d less than its count_in_stock", n, k.ProductID)
+		}
+	}
+
+	return sales, nil
+}
+
+// restockOrder releases the stock reserved by o by reversing its sales, or
+// into the main warehouse if it has none recorded; callers must hold the
+// write lock.
+func (ms *MemoryStorer) restockOrder(o *Order, reason string, actorID *int64) {
+	sold := make(map[stockKey]int64)
+	for _, m := range ms.stockMovements {
+		if m.OrderID != nil && *m.OrderID == o.ID && m.Kind == StockSale {
+			sold[m.key()] += m.Quantity
+		}
+	}
+
+	if len(sold) == 0 {
+		ms.applyStockMovements(restockMovements(o.Items, StockRelease, reason, actorID))
+		return
+	}
+
+	var mvs []*StockMovement
+	for _, k := range sortedStockKeys(sold) {
+		if sold[k] < 0 {
+			orderID := o.ID
+			mvs = append(mvs, &StockMovement{
+				WarehouseID: k.WarehouseID,
+				ProductID:   k.ProductID,
+				VariantID:   k.VariantID,
+				Kind:        StockRelease,
+				Quantity:    -sold[k],
+				Reason:      reason,
+				ActorID:     actorID,
+				OrderID:     &orderID,
+			})
+		}
+	}
+	ms.applyStockMovements(mvs)
+}
+
+// applyStockMovements appends movements to the ledger and adds them to stock
+// levels and counts; callers must hold the write lock.
+func (ms *MemoryStorer) applyStockMovements(mvs []*StockMovement) {
+	for _, m := range mvs {
+		if p, ok := ms.products[m.ProductID]; ok {
+			p.CountInStock += m.Quantity
+		}
+		if v, ok := ms.variants[m.VariantID]; ok {
+			v.CountInStock += m.Quantity
+		}
+		ms.stockLevels[m.key()] += m.Quantity
+		ms.insertStockMovement(m)
+	}
+}
+
+// receiveOpeningStock records the stock a product or variant is created with
+// as received into the main warehouse; callers must hold the write lock.
+func (ms *MemoryStorer) receiveOpeningStock(productID, variantID, quantity int64) {
+	if quantity == 0 {
+		return
+	}
+
+	m := &StockMovement{
+		WarehouseID: MainWarehouseID,
+		ProductID:   productID,
+		VariantID:   variantID,
+		Kind:        StockReceipt,
+		Quantity:    quantity,
+		Reason:      "opening stock",
+	}
+	ms.stockLevels[m.key()] += quantity
+	ms.insertStockMovement(m)
+}
+
+// insertStockMovement appends m to the ledger; callers must hold the write
+// lock.
+func (ms *MemoryStorer) insertStockMovement(m *StockMovement) {
+	m.ID = ms.nextID("stock_movements")
+	c := copyStockMovement(m)
+	c.CreatedAt = time.Now()
+	ms.stockMovements[m.ID] = c
+}
+
+func (ms *MemoryStorer) GetOrder(ctx context.Context, id int64) (*Order, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	o, ok := ms.orders[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting order: %w", sql.ErrNoRows)
+	}
+
+	return copyOrder(o), nil
+}
+
+func (ms *MemoryStorer) GetOrderStatusByID(ctx context.Context, id int64) (*Order, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	o, ok := ms.orders[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting order status: %w", sql.ErrNoRows)
+	}
+
+	return &Order{
+		ID:     o.ID,
+		UserID: o.UserID,
+		Status: o.Status,
+	}, nil
+}
+
+func (ms *MemoryStorer) ListOrders(ctx context.Context) ([]*Order, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	orders := make([]*Order, 0, len(ms.orders))
+	for _, id := range sortedKeys(ms.orders) {
+		orders = append(orders, copyOrder(ms.orders[id]))
+	}
+
+	return orders, nil
+}
+
+func (ms *MemoryStorer) ListUserOrders(ctx context.Context, params *ListOrdersParams) (*OrderPage, error) {
+	limit := pageSize(params.Limit)
+	after, err := params.cursor()
+	if err != nil {
+		return nil, fmt.Errorf("error listing orders: %w", err)
+	}
+
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var orders []*Order
+	for _, o := range ms.orders {
+		switch {
+		case o.UserID != params.UserID,
+			params.Status != "" && o.Status != params.Status,
+			params.From != nil && o.CreatedAt.Before(*params.From),
+			params.To != nil && !o.CreatedAt.Before(*params.To),
+			after != nil && compareOrdersNewestFirst(o, after) <= 0:
+			continue
+		}
+		orders = append(orders, copyOrder(o))
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return compareOrdersNewestFirst(orders[i], orders[j]) < 0
+	})
+	if len(orders) > limit+1 {
+		orders = orders[:limit+1]
+	}
+
+	return newOrderPage(orders, limit), nil
+}
+
+func (ms *MemoryStorer) UpdateOrderStatus(ctx context.Context, o *Order, change *OrderStatusChange, ne *NotificationEvent) (*Order, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	existing, ok := ms.orders[o.ID]
+	if !ok {
+		return nil, fmt.Errorf("error updating order status: %w", sql.ErrNoRows)
+	}
+
+	if existing.Status != change.FromStatus {
+		return nil, fmt.Errorf("error updating order status: order %d is %s, not %s: %w", o.ID, existing.Status, change.FromStatus, ErrOrderStatusChanged)
+	}
+	if cn := change.CreditNote; cn != nil {
+		if _, ok := ms.invoices[cn.InvoiceID]; !ok {
+			return nil, fmt.Errorf("error updating order status: invoice %d does not exist", cn.InvoiceID)
+		}
+	}
+	if lt := change.Loyalty; lt != nil {
+		if _, ok := ms.users[lt.UserID]; !ok {
+			return nil, fmt.Errorf("error updating order status: user %d does not exist", lt.UserID)
+		}
+	}
+	seen := make(map[string]bool, len(change.GiftCards))
+	for _, g := range change.GiftCards {
+		if seen[g.Code] || ms.giftCardCodeTaken(g.Code) {
+			return nil, fmt.Errorf("error updating order status: %w", ErrGiftCardExists)
+		}
+		seen[g.Code] = true
+	}
+
+	if releasesStock(existing.Status, o.Status) {
+		var actorID *int64
+		if change.ActorID != 0 {
+			actorID = &change.ActorID
+		}
+		ms.restockOrder(existing, fmt.Sprintf("order %d %s", o.ID, o.Status), actorID)
+		ms.addScheduledPriceSales(existing.Items, -1)
+	}
+	existing.Status = o.Status
+	existing.UpdatedAt = o.UpdatedAt
+
+	change.ID = ms.nextID("order_status_history")
+	change.OrderID = o.ID
+	change.ToStatus = o.Status
+	c := *change
+	c.CreatedAt = time.Now()
+	c.CreditNote, c.GiftCards, c.CancelGiftCards, c.Loyalty = nil, nil, false, nil
+	ms.statusChanges[c.ID] = &c
+
+	if change.CreditNote != nil {
+		ms.insertCreditNote(change.CreditNote)
+	}
+
+	for _, g := range change.GiftCards {
+		ms.insertGiftCard(g)
+	}
+
+	if change.CancelGiftCards {
+		ms.cancelOrderGiftCards(o.ID, fmt.Sprintf("order %d %s", o.ID, o.Status))
+	}
+
+	if change.Loyalty != nil {
+		ms.expireLoyaltyPoints(change.Loyalty.UserID)
+		ms.applyLoyaltyTransaction(change.Loyalty)
+	}
+
+	if ne != nil {
+		ne.OrderID = o.ID
+		ne.OrderStatus = o.Status
+		ms.enqueueNotificationEvent(ne)
+	}
+
+	return o, nil
+}
+
+func (ms *MemoryStorer) ListOrderStatusChanges(ctx context.Context, orderID int64) ([]*OrderStatusChange, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var changes []*OrderStatusChange
+	for _, id := range sortedKeys(ms.statusChanges) {
+		if sc := ms.statusChanges[id]; sc.OrderID == orderID {
+			c := *sc
+			changes = append(changes, &c)
+		}
+	}
+
+	return changes, nil
+}
+
+func (ms *MemoryStorer) DeleteOrder(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	o, ok := ms.orders[id]
+	if !ok {
+		return fmt.Errorf("error deleting order: %w", sql.ErrNoRows)
+	}
+	if ms.orderHasRecords(id) {
+		return fmt.Errorf("error deleting order: %w", ErrOrderHasRecords)
+	}
+
+	if o.Status.ReservesStock() {
+		ms.restockOrder(o, fmt.Sprintf("order %d deleted", id), nil)
+		ms.addScheduledPriceSales(o.Items, -1)
+	}
+	for scID, sc := range ms.statusChanges {
+		if sc.OrderID == id {
+			delete(ms.statusChanges, scID)
+		}
+	}
+	for evID, ev := range ms.notificationEvents {
+		if ev.OrderID == id {
+			delete(ms.notificationEvents, evID)
+		}
+	}
+	for stID, st := range ms.notificationStates {
+		if st.OrderID == id {
+			delete(ms.notificationStates, stID)
+		}
+	}
+	for rID, r := range ms.redemptions {
+		if r.OrderID == id {
+			delete(ms.redemptions, rID)
+		}
+	}
+	for pID, p := range ms.payments {
+		if p.OrderID == id {
+			delete(ms.payments, pID)
+		}
+	}
+	for rID, r := range ms.paymentRefunds {
+		if _, ok := ms.payments[r.PaymentID]; !ok {
+			delete(ms.paymentRefunds, rID)
+		}
+	}
+	for _, g := range ms.giftCards {
+		if g.OrderID != nil && *g.OrderID == id {
+			g.OrderID = nil
+		}
+	}
+	for _, t := range ms.giftCardTxs {
+		if t.OrderID != nil && *t.OrderID == id {
+			t.OrderID = nil
+		}
+	}
+	for _, t := range ms.storeCreditTxs {
+		if t.OrderID != nil && *t.OrderID == id {
+			t.OrderID = nil
+		}
+	}
+	for _, t := range ms.loyaltyTxs {
+		if t.OrderID != nil && *t.OrderID == id {
+			t.OrderID = nil
+		}
+	}
+	for _, m := range ms.stockMovements {
+		if m.OrderID != nil && *m.OrderID == id {
+			m.OrderID = nil
+		}
+	}
+	delete(ms.orders, id)
+
+	return nil
+}
+
+// orderHasRecords reports whether an order has payments that are in
+// progress or took money, an invoice or returns; callers must hold the
+// lock.
+func (ms *MemoryStorer) orderHasRecords(id int64) bool {
+	for _, p := range ms.payments {
+		if p.OrderID == id && (p.Status.IsActive() || p.Status == payments.StatusRefunded) {
+			return true
+		}
+	}
+	for _, inv := range ms.invoices {
+		if inv.OrderID == id {
+			return true
+		}
+	}
+	for _, r := range ms.returns {
+		if r.OrderID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ms *MemoryStorer) CreatePayment(ctx context.Context, p *Payment) (*Payment, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.orders[p.OrderID]; !ok {
+		return nil, fmt.Errorf("error inserting payment: order %d does not exist", p.OrderID)
+	}
+	for _, existing := range ms.payments {
+		if existing.OrderID == p.OrderID && existing.Status.IsActive() && !existing.IsTender() {
+			return nil, fmt.Errorf("error inserting payment: %w", ErrPaymentInProgress)
+		}
+	}
+
+	p.ID = ms.nextID("payments")
+	c := *p
+	c.CreatedAt = time.Now()
+	ms.payments[p.ID] = &c
+
+	return p, nil
+}
+
+func (ms *MemoryStorer) GetPayment(ctx context.Context, id int64) (*Payment, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	p, ok := ms.payments[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting payment: %w", sql.ErrNoRows)
+	}
+
+	c := *p
+	return &c, nil
+}
+
+func (ms *MemoryStorer) GetPaymentByReference(ctx context.Context, provider, reference string) (*Payment, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	for _, id := range sortedKeys(ms.payments) {
+		if p := ms.payments[id]; p.Provider == provider && p.Reference == reference {
+			c := *p
+			return &c, nil
+		}
+	}
+
+	return nil, fmt.Errorf("error getting payment: %w", sql.ErrNoRows)
+}
+
+func (ms *MemoryStorer) ListOrderPayments(ctx context.Context, orderID int64) ([]*Payment, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var payments []*Payment
+	for _, id := range sortedKeys(ms.payments) {
+		if p := ms.payments[id]; p.OrderID == orderID {
+			c := *p
+			payments = append(payments, &c)
+		}
+	}
+
+	return payments, nil
+}
+
+func (ms *MemoryStorer) UpdatePayment(ctx context.Context, p *Payment) (*Payment, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if existing, ok := ms.payments[p.ID]; ok {
+		c := *p
+		c.OrderID = existing.OrderID
+		c.Provider = existing.Provider
+		c.Amount = existing.Amount
+		c.RefundedAmount = existing.RefundedAmount
+		c.CreatedAt = existing.CreatedAt
+		ms.payments[p.ID] = &c
+	}
+
+	return p, nil
+}
+
+func (ms *MemoryStorer) RefundPayment(ctx context.Context, p *Payment, r *PaymentRefund) (*Payment, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	existing, ok := ms.payments[p.ID]
+	if !ok {
+		return nil, fmt.Errorf("error refunding payment: %w", sql.ErrNoRows)
+	}
+	for _, pr := range ms.paymentRefunds {
+		if pr.PaymentID == p.ID && pr.Key == r.Key {
+			return nil, fmt.Errorf("error refunding payment: %w", ErrRefundApplied)
+		}
+	}
+	if r.GiftCard != nil {
+		if _, ok := ms.giftCards[r.GiftCard.GiftCardID]; !ok {
+			return nil, fmt.Errorf("error refunding payment: %w", sql.ErrNoRows)
+		}
+	}
+
+	refunded := *existing
+	if err := applyRefund(&refunded, p, r); err != nil {
+		return nil, fmt.Errorf("error refunding payment: %w", err)
+	}
+	switch {
+	case r.GiftCard != nil:
+		ms.applyGiftCardTransaction(r.GiftCard)
+	case r.StoreCredit != nil:
+		ms.applyStoreCreditTransaction(r.StoreCredit)
+	case r.Loyalty != nil:
+		ms.applyLoyaltyTransaction(r.Loyalty)
+	}
+	ms.payments[p.ID] = &refunded
+
+	r.ID = ms.nextID("payment_refunds")
+	r.PaymentID = p.ID
+	r.Status = refundStatus(r)
+	c := PaymentRefund{ID: r.ID, PaymentID: r.PaymentID, Key: r.Key, Amount: r.Amount, Status: r.Status, CreatedAt: time.Now()}
+	ms.paymentRefunds[r.ID] = &c
+
+	*p = refunded
+	return p, nil
+}
+
+func (ms *MemoryStorer) ListOrderPaymentRefunds(ctx context.Context, orderID int64) ([]*PaymentRefund, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var refunds []*PaymentRefund
+	for _, id := range sortedKeys(ms.paymentRefunds) {
+		if r := ms.paymentRefunds[id]; ms.payments[r.PaymentID].OrderID == orderID {
+			c := *r
+			refunds = append(refunds, &c)
+		}
+	}
+
+	return refunds, nil
+}
+
+func (ms *MemoryStorer) ApplyPaymentRefund(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	r, ok := ms.paymentRefunds[id]
+	if !ok {
+		return fmt.Errorf("error applying payment refund: %w", sql.ErrNoRows)
+	}
+	r.Status = RefundApplied
+
+	return nil
+}
+
+func (ms *MemoryStorer) CreateGiftCard(ctx context.Context, g *GiftCard) (*GiftCard, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if ms.giftCardCodeTaken(g.Code) {
+		return nil, fmt.Errorf("error creating gift card: %w", ErrGiftCardExists)
+	}
+	ms.insertGiftCard(g)
+
+	return g, nil
+}
+
+// giftCardCodeTaken reports whether a gift card has code; callers must hold
+// the lock.
+func (ms *MemoryStorer) giftCardCodeTaken(code string) bool {
+	for _, existing := range ms.giftCards {
+		if existing.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// insertGiftCard stores g and credits it with its initial amount; callers
+// must hold the write lock and have checked its code is free.
+func (ms *MemoryStorer) insertGiftCard(g *GiftCard) {
+	g.ID = ms.nextID("gift_cards")
+	g.Balance = money.New(0, g.InitialAmount.Currency)
+	c := copyGiftCard(g)
+	c.CreatedAt = time.Now()
+	ms.giftCards[g.ID] = c
+	ms.applyGiftCardTransaction(issueTransaction(g))
+	g.Balance = g.InitialAmount
+}
+
+func (ms *MemoryStorer) GetGiftCard(ctx context.Context, id int64) (*GiftCard, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	g, ok := ms.giftCards[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting gift card: %w", sql.ErrNoRows)
+	}
+
+	return copyGiftCard(g), nil
+}
+
+func (ms *MemoryStorer) GetGiftCardByCode(ctx context.Context, code string) (*GiftCard, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	g, ok := ms.giftCardByCode(code)
+	if !ok {
+		return nil, fmt.Errorf("error getting gift card: %w", sql.ErrNoRows)
+	}
+
+	return copyGiftCard(g), nil
+}
+
+func (ms *MemoryStorer) ListUserGiftCards(ctx context.Context, purchaserID int64) ([]*GiftCard, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var cards []*GiftCard
+	for _, id := range slices.Backward(sortedKeys(ms.giftCards)) {
+		if g := ms.giftCards[id]; g.PurchaserID != nil && *g.PurchaserID == purchaserID {
+			cards = append(cards, copyGiftCard(g))
+		}
+	}
+
+	return cards, nil
+}
+
+func (ms *MemoryStorer) ListOrderGiftCards(ctx context.Context, orderID int64) ([]*GiftCard, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var cards []*GiftCard
+	for _, id := range sortedKeys(ms.giftCards) {
+		if g := ms.giftCards[id]; g.OrderID != nil && *g.OrderID == orderID {
+			cards = append(cards, copyGiftCard(g))
+		}
+	}
+
+	return cards, nil
+}
+
+func (ms *MemoryStorer) UpdateGiftCard(ctx context.Context, g *GiftCard) (*GiftCard, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if existing, ok := ms.giftCards[g.ID]; ok {
+		c := copyGiftCard(existing)
+		c.ExpiresAt = copyGiftCard(g).ExpiresAt
+		c.Note = g.Note
+		c.UpdatedAt = g.UpdatedAt
+		ms.giftCards[g.ID] = c
+	}
+
+	return g, nil
+}
+
+func (ms *MemoryStorer) RecordGiftCardTransaction(ctx context.Context, t *GiftCardTransaction) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	g, ok := ms.giftCards[t.GiftCardID]
+	if !ok {
+		return fmt.Errorf("error recording gift card transaction: %w", sql.ErrNoRows)
+	}
+	if g.Balance.Add(t.Amount).Amount < 0 {
+		return fmt.Errorf("error recording gift card transaction: %w", ErrInsufficientBalance)
+	}
+	ms.applyGiftCardTransaction(t)
+
+	return nil
+}
+
+func (ms *MemoryStorer) ListGiftCardTransactions(ctx context.Context, giftCardID int64) ([]*GiftCardTransaction, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var txs []*GiftCardTransaction
+	for _, id := range sortedKeys(ms.giftCardTxs) {
+		if t := ms.giftCardTxs[id]; t.GiftCardID == giftCardID {
+			txs = append(txs, copyGiftCardTransaction(t))
+		}
+	}
+
+	return txs, nil
+}
+
+func (ms *MemoryStorer) GetStoreCredit(ctx context.Context, userID int64) (money.Money, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	return ms.storeCredit(userID), nil
+}
+
+func (ms *MemoryStorer) RecordStoreCreditTransaction(ctx context.Context, t *StoreCreditTransaction) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.users[t.UserID]; !ok {
+		return fmt.Errorf("error recording store credit transaction: user %d does not exist", t.UserID)
+	}
+	if ms.storeCredit(t.UserID).Add(t.Amount).Amount < 0 {
+		return fmt.Errorf("error recording store credit transaction: %w", ErrInsufficientBalance)
+	}
+	ms.applyStoreCreditTransaction(t)
+
+	return nil
+}
+
+func (ms *MemoryStorer) ListStoreCreditTransactions(ctx context.Context, userID int64) ([]*StoreCreditTransaction, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var txs []*StoreCreditTransaction
+	for _, id := range sortedKeys(ms.storeCreditTxs) {
+		if t := ms.storeCreditTxs[id]; t.UserID == userID {
+			txs = append(txs, copyStoreCreditTransaction(t))
+		}
+	}
+
+	return txs, nil
+}
+
+func (ms *MemoryStorer) GetLoyaltyBalance(ctx context.Context, userID int64) (int64, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	return ms.loyaltyPoints[userID], nil
+}
+
+func (ms *MemoryStorer) GetLoyaltySpend(ctx context.Context, userID int64, since time.Time) (money.Money, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	spend := money.New(0, money.DefaultCurrency)
+	for _, t := range ms.loyaltyTxs {
+		if t.UserID == userID && !t.CreatedAt.Before(since) {
+			spend = spend.Add(t.Spend)
+		}
+	}
+
+	return spend, nil
+}
+
+func (ms *MemoryStorer) RecordLoyaltyTransaction(ctx context.Context, t *LoyaltyTransaction) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.users[t.UserID]; !ok {
+		return fmt.Errorf("error recording loyalty transaction: user %d does not exist", t.UserID)
+	}
+	ms.expireLoyaltyPoints(t.UserID)
+	if t.Kind == BalanceRedeem && ms.loyaltyPoints[t.UserID]+t.Points < 0 {
+		return fmt.Errorf("error recording loyalty transaction: %w", ErrInsufficientBalance)
+	}
+	ms.applyLoyaltyTransaction(t)
+
+	return nil
+}
+
+func (ms *MemoryStorer) ExpireLoyaltyPoints(ctx context.Context, userID int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.expireLoyaltyPoints(userID)
+
+	return nil
+}
+
+func (ms *MemoryStorer) ListLoyaltyTransactions(ctx context.Context, userID int64) ([]*LoyaltyTransaction, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var txs []*LoyaltyTransaction
+	for _, id := range sortedKeys(ms.loyaltyTxs) {
+		if t := ms.loyaltyTxs[id]; t.UserID == userID {
+			txs = append(txs, copyLoyaltyTransaction(t))
+		}
+	}
+
+	return txs, nil
+}
+
+// giftCardByCode finds a card by its code; callers must hold the lock.
+func (ms *MemoryStorer) giftCardByCode(code string) (*GiftCard, bool) {
+	for _, g := range ms.giftCards {
+		if g.Code == code {
+			return g, true
+		}
+	}
+	return nil, false
+}
+
+// storeCredit returns the balance of a user's store credit; callers must
+// hold the lock.
+func (ms *MemoryStorer) storeCredit(userID int64) money.Money {
+	if balance, ok := ms.storeCredits[userID]; ok {
+		return balance
+	}
+	return money.New(0, money.DefaultCurrency)
+}
+
+// applyGiftCardTransaction records t and applies it to its card; callers
+// must hold the write lock and have checked the balance.
+func (ms *MemoryStorer) applyGiftCardTransaction(t *GiftCardTransaction) {
+	g := ms.giftCards[t.GiftCardID]
+	g.Balance = g.Balance.Add(t.Amount)
+
+	t.ID = ms.nextID("gift_card_transactions")
+	c := copyGiftCardTransaction(t)
+	c.CreatedAt = time.Now()
+	ms.giftCardTxs[t.ID] = c
+}
+
+// cancelOrderGiftCards takes what is left off the gift cards bought in an
+// order; callers must hold the write lock.
+func (ms *MemoryStorer) cancelOrderGiftCards(orderID int64, reason string) {
+	for _, id := range sortedKeys(ms.giftCards) {
+		g := ms.giftCards[id]
+		if g.OrderID == nil || *g.OrderID != orderID || g.Balance.Amount <= 0 {
+			continue
+		}
+		ms.applyGiftCardTransaction(&GiftCardTransaction{
+			GiftCardID: g.ID,
+			Kind:       BalanceAdjustment,
+			Amount:     g.Balance.Mul(-1),
+			OrderID:    &orderID,
+			Reason:     reason,
+		})
+	}
+}
+
+// applyStoreCreditTransaction records t and applies it to the user's store
+// credit; callers must hold the write lock and have checked the balance.
+func (ms *MemoryStorer) applyStoreCreditTransaction(t *StoreCreditTransaction) {
+	ms.storeCredits[t.UserID] = ms.storeCredit(t.UserID).Add(t.Amount)
+
+	t.ID = ms.nextID("store_credit_transactions")
+	c := copyStoreCreditTransaction(t)
+	c.CreatedAt = time.Now()
+	ms.storeCreditTxs[t.ID] = c
+}
+
+// applyLoyaltyTransaction records t and applies it to the user's points;
+// callers must hold the write lock and have checked the balance.
+func (ms *MemoryStorer) applyLoyaltyTransaction(t *LoyaltyTransaction) {
+	ms.loyaltyPoints[t.UserID] += t.Points
+
+	t.ID = ms.nextID("loyalty_transactions")
+	c := copyLoyaltyTransaction(t)
+	c.CreatedAt = time.Now()
+	ms.loyaltyTxs[t.ID] = c
+}
+
+// loyaltyExpiry returns the transaction that lets the lapsed points of a
+// user expire, or nil; callers must hold the lock.
+func (ms *MemoryStorer) loyaltyExpiry(userID int64) *LoyaltyTransaction {
+	var lapsed, used int64
+	now := time.Now()
+	for _, t := range ms.loyaltyTxs {
+		if t.UserID != userID {
+			continue
+		}
+		switch {
+		case t.Points < 0:
+			used -= t.Points
+		case t.ExpiresAt != nil && !now.Before(*t.ExpiresAt):
+			lapsed += t.Points
+		}
+	}
+
+	return expireTransaction(userID, lapsed, used)
+}
+
+// expireLoyaltyPoints records the expiry of the lapsed points of a user;
+// callers must hold the write lock.
+func (ms *MemoryStorer) expireLoyaltyPoints(userID int64) {
+	if t := ms.loyaltyExpiry(userID); t != nil {
+		ms.applyLoyaltyTransaction(t)
+	}
+}
+
+// checkTenders makes sure the gift cards, store credit and loyalty points of
+// an order cover its tenders; callers must hold the lock.
+func (ms *MemoryStorer) checkTenders(o *Order) error {
+	spent := make(map[int64]money.Money)
+	credit := ms.storeCredit(o.UserID)
+	points := ms.loyaltyPoints[o.UserID]
+	if t := ms.loyaltyExpiry(o.UserID); t != nil {
+		points += t.Points
+	}
+	for _, p := range o.Tenders {
+		switch p.Provider {
+		case GiftCardProvider:
+			g, ok := ms.giftCardByCode(p.Reference)
+			if !ok {
+				return fmt.Errorf("error getting gift card: %w", sql.ErrNoRows)
+			}
+			spent[g.ID] = spent[g.ID].Add(p.Amount)
+			if g.Balance.Cmp(spent[g.ID]) < 0 {
+				return ErrInsufficientBalance
+			}
+		case StoreCreditProvider:
+			credit = credit.Sub(p.Amount)
+			if credit.Amount < 0 {
+				return ErrInsufficientBalance
+			}
+		case LoyaltyPointsProvider:
+			points -= p.LoyaltyPoints()
+			if points < 0 {
+				return ErrInsufficientBalance
+			}
+		default:
+			return fmt.Errorf("error redeeming tender: unknown provider %q", p.Provider)
+		}
+	}
+
+	return nil
+}
+
+// redeemTenders takes the tenders of an order off their gift cards, the
+// customer's store credit and loyalty points and records them as captured
+// payments; callers must hold the write lock and have checked the tenders.
+func (ms *MemoryStorer) redeemTenders(o *Order) {
+	for _, p := range o.Tenders {
+		p.OrderID = o.ID
+		p.Status = payments.StatusCaptured
+		p.RefundedAmount = money.New(0, p.Amount.Currency)
+		reason := fmt.Sprintf("order %d", o.ID)
+		amount := money.New(-p.Amount.Amount, p.Amount.Currency)
+
+		switch p.Provider {
+		case GiftCardProvider:
+			g, _ := ms.giftCardByCode(p.Reference)
+			ms.applyGiftCardTransaction(&GiftCardTransaction{
+				GiftCardID: g.ID,
+				Kind:       BalanceRedeem,
+				Amount:     amount,
+				OrderID:    &o.ID,
+				ActorID:    &o.UserID,
+				Reason:     reason,
+			})
+		case StoreCreditProvider:
+			ms.applyStoreCreditTransaction(&StoreCreditTransaction{
+				UserID:  o.UserID,
+				Kind:    BalanceRedeem,
+				Amount:  amount,
+				OrderID: &o.ID,
+				ActorID: &o.UserID,
+				Reason:  reason,
+			})
+		case LoyaltyPointsProvider:
+			ms.expireLoyaltyPoints(o.UserID)
+			ms.applyLoyaltyTransaction(&LoyaltyTransaction{
+				UserID:  o.UserID,
+				Kind:    BalanceRedeem,
+				Points:  -p.LoyaltyPoints(),
+				Spend:   money.New(0, p.Amount.Currency),
+				OrderID: &o.ID,
+				Reason:  reason,
+			})
+		}
+
+		p.ID = ms.nextID("payments")
+		c := *p
+		c.CreatedAt = time.Now()
+		ms.payments[p.ID] = &c
+	}
+}
+
+func (ms *MemoryStorer) CreateInvoice(ctx context.Context, inv *Invoice) (*Invoice, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	for _, existing := range ms.invoices {
+		if existing.OrderID == inv.OrderID {
+			return nil, fmt.Errorf("error creating invoice: %w", ErrInvoiceExists)
+		}
+	}
+
+	inv.ID = ms.nextID("invoices")
+	inv.Number = documentNumber(invoicePrefix, ms.nextID(invoiceSequence))
+	ms.invoices[inv.ID] = copyInvoice(inv)
+
+	return inv, nil
+}
+
+func (ms *MemoryStorer) GetOrderInvoice(ctx context.Context, orderID int64) (*Invoice, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	for _, inv := range ms.invoices {
+		if inv.OrderID == orderID {
+			return copyInvoice(inv), nil
+		}
+	}
+
+	return nil, fmt.Errorf("error getting invoice: %w", sql.ErrNoRows)
+}
+
+func (ms *MemoryStorer) CreateCreditNote(ctx context.Context, cn *CreditNote) (*CreditNote, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.invoices[cn.InvoiceID]; !ok {
+		return nil, fmt.Errorf("error creating credit note: invoice %d does not exist", cn.InvoiceID)
+	}
+
+	ms.insertCreditNote(cn)
+
+	return cn, nil
+}
+
+// insertCreditNote numbers and stores a credit note whose invoice exists;
+// callers must hold the write lock.
+func (ms *MemoryStorer) insertCreditNote(cn *CreditNote) {
+	cn.ID = ms.nextID("credit_notes")
+	cn.Number = documentNumber(creditNotePrefix, ms.nextID(creditNoteSequence))
+	c := *cn
+	c.Items = append(InvoiceLines(nil), cn.Items...)
+	ms.creditNotes[cn.ID] = &c
+}
+
+func (ms *MemoryStorer) ListInvoiceCreditNotes(ctx context.Context, invoiceID int64) ([]*CreditNote, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var notes []*CreditNote
+	for _, id := range sortedKeys(ms.creditNotes) {
+		if cn := ms.creditNotes[id]; cn.InvoiceID == invoiceID {
+			c := *cn
+			c.Items = append(InvoiceLines(nil), cn.Items...)
+			notes = append(notes, &c)
+		}
+	}
+
+	return notes, nil
+}
+
+func (ms *MemoryStorer) CreateReturn(ctx context.Context, r *Return, ne *NotificationEvent) (*Return, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	o, ok := ms.orders[r.OrderID]
+	if !ok {
+		return nil, fmt.Errorf("error creating return: %w", sql.ErrNoRows)
+	}
+	var ordered int64 = -1
+	for _, oi := range o.Items {
+		if oi.ID == r.OrderItemID {
+			ordered = oi.Quantity
+		}
+	}
+	if ordered < 0 {
+		return nil, fmt.Errorf("error creating return: %w", sql.ErrNoRows)
+	}
+
+	var returned int64
+	for _, existing := range ms.returns {
+		if existing.OrderItemID == r.OrderItemID && existing.Status != ReturnRejected {
+			returned += existing.Quantity
+		}
+	}
+	if returned+r.Quantity > ordered {
+		return nil, fmt.Errorf("error creating return: %d of %d units already returned: %w", returned, ordered, ErrReturnQuantityExceeded)
+	}
+
+	r.ID = ms.nextID("returns")
+	c := *r
+	c.CreatedAt = time.Now()
+	ms.returns[r.ID] = &c
+
+	if ne != nil {
+		id := r.ID
+		ne.OrderID = r.OrderID
+		ne.ReturnID = &id
+		ne.ReturnStatus = r.Status
+		ms.enqueueNotificationEvent(ne)
+	}
+
+	return r, nil
+}
+
+func (ms *MemoryStorer) GetReturn(ctx context.Context, id int64) (*Return, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	r, ok := ms.returns[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting return: %w", sql.ErrNoRows)
+	}
+
+	c := *r
+	return &c, nil
+}
+
+func (ms *MemoryStorer) ListOrderReturns(ctx context.Context, orderID int64) ([]*Return, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var returns []*Return
+	for _, id := range sortedKeys(ms.returns) {
+		if r := ms.returns[id]; r.OrderID == orderID {
+			c := *r
+			returns = append(returns, &c)
+		}
+	}
+
+	return returns, nil
+}
+
+func (ms *MemoryStorer) ListReturns(ctx context.Context, status ReturnStatus) ([]*Return, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var returns []*Return
+	for _, id := range sortedKeys(ms.returns) {
+		if r := ms.returns[id]; status == "" || r.Status == status {
+			c := *r
+			returns = append(returns, &c)
+		}
+	}
+
+	return returns, nil
+}
+
+func (ms *MemoryStorer) UpdateReturnStatus(ctx context.Context, r *Return, from ReturnStatus, ne *NotificationEvent) (*Return, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	existing, ok := ms.returns[r.ID]
+	if !ok {
+		return nil, fmt.Errorf("error updating return status: %w", sql.ErrNoRows)
+	}
+
+	if existing.Status != from {
+		return nil, fmt.Errorf("error updating return status: return %d is %s, not %s: %w", r.ID, existing.Status, from, ErrReturnStatusChanged)
+	}
+	if cn := r.CreditNote; cn != nil {
+		if _, ok := ms.invoices[cn.InvoiceID]; !ok {
+			return nil, fmt.Errorf("error updating return status: invoice %d does not exist", cn.InvoiceID)
+		}
+	}
+	if lt := r.Loyalty; lt != nil {
+		if _, ok := ms.users[lt.UserID]; !ok {
+			return nil, fmt.Errorf("error updating return status: user %d does not exist", lt.UserID)
+		}
+	}
+
+	if r.Status == ReturnReceived && r.Restocked {
+		for _, oi := range ms.orders[existing.OrderID].Items {
+			if oi.ID == existing.OrderItemID {
+				oi.Quantity = existing.Quantity
+				ms.applyStockMovements(restockMovements([]OrderItem{oi}, StockReturn, fmt.Sprintf("return %d received", r.ID), nil))
+			}
+		}
+	}
+	existing.Status = r.Status
+	existing.Note = r.Note
+	existing.Restocked = r.Restocked
+	existing.UpdatedAt = r.UpdatedAt
+
+	if r.CreditNote != nil {
+		ms.insertCreditNote(r.CreditNote)
+	}
+
+	if r.Loyalty != nil {
+		ms.expireLoyaltyPoints(r.Loyalty.UserID)
+		ms.applyLoyaltyTransaction(r.Loyalty)
+	}
+
+	if ne != nil {
+		id := r.ID
+		ne.OrderID = existing.OrderID
+		ne.ReturnID = &id
+		ne.ReturnStatus = r.Status
+		ms.enqueueNotificationEvent(ne)
+	}
+
+	return r, nil
+}
+
+func (ms *MemoryStorer) GetCart(ctx context.Context, userID int64) (*Cart, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	c, ok := ms.carts[userID]
+	if !ok {
+		return &Cart{UserID: userID}, nil
+	}
+
+	return copyCart(c), nil
+}
+
+func (ms *MemoryStorer) SetCartItem(ctx context.Context, userID, productID, variantID, quantity int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.users[userID]; !ok {
+		return fmt.Errorf("error creating cart: user %d does not exist", userID)
+	}
+	if _, ok := ms.products[productID]; !ok {
+		return fmt.Errorf("error setting cart item: product %d does not exist", productID)
+	}
+
+	now := time.Now()
+	c, ok := ms.carts[userID]
+	if !ok {
+		c = &Cart{ID: ms.nextID("carts"), UserID: userID, CreatedAt: now}
+		ms.carts[userID] = c
+	} else {
+		c.UpdatedAt = &now
+	}
+
+	for i := range c.Items {
+		if c.Items[i].ProductID == productID && c.Items[i].VariantID == variantID {
+			c.Items[i].Quantity = quantity
+			c.Items[i].UpdatedAt = &now
+			return nil
+		}
+	}
+	c.Items = append(c.Items, CartItem{
+		ID:        ms.nextID("cart_items"),
+		CartID:    c.ID,
+		ProductID: productID,
+		VariantID: variantID,
+		Quantity:  quantity,
+		CreatedAt: now,
+	})
+
+	return nil
+}
+
+func (ms *MemoryStorer) RemoveCartItem(ctx context.Context, userID, productID, variantID int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	c, ok := ms.carts[userID]
+	if !ok {
+		return fmt.Errorf("error removing cart item: %w", sql.ErrNoRows)
+	}
+	for i, ci := range c.Items {
+		if ci.ProductID == productID && ci.VariantID == variantID {
+			c.Items = append(c.Items[:i], c.Items[i+1:]...)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("error removing cart item: %w", sql.ErrNoRows)
+}
+
+func (ms *MemoryStorer) ClearCart(ctx context.Context, userID int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if c, ok := ms.carts[userID]; ok {
+		c.Items = nil
+	}
+
+	return nil
+}
+
+func (ms *MemoryStorer) CreateWishlist(ctx context.Context, w *Wishlist) (*Wishlist, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.users[w.UserID]; !ok {
+		return nil, fmt.Errorf("error inserting wishlist: user %d does not exist", w.UserID)
+	}
+	if err := ms.checkWishlistUnique(w); err != nil {
+		return nil, err
+	}
+
+	w.ID = ms.nextID("wishlists")
+	c := copyWishlist(w)
+	c.CreatedAt = time.Now()
+	ms.wishlists[w.ID] = c
+
+	return w, nil
+}
+
+// checkWishlistUnique emulates the unique keys on the name and share token
+// of wishlists; callers must hold the lock.
+func (ms *MemoryStorer) checkWishlistUnique(w *Wishlist) error {
+	for _, o := range ms.wishlists {
+		if o.ID == w.ID {
+			continue
+		}
+		if o.UserID == w.UserID && o.Name == w.Name {
+			return ErrWishlistExists
+		}
+		if o.ShareToken != nil && w.ShareToken != nil && *o.ShareToken == *w.ShareToken {
+			return fmt.Errorf("error saving wishlist: share token already in use")
+		}
+	}
+	return nil
+}
+
+func (ms *MemoryStorer) GetWishlist(ctx context.Context, id int64) (*Wishlist, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	w, ok := ms.wishlists[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting wishlist: %w", sql.ErrNoRows)
+	}
+
+	return copyWishlist(w), nil
+}
+
+func (ms *MemoryStorer) GetWishlistByShareToken(ctx context.Context, token string) (*Wishlist, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	for _, w := range ms.wishlists {
+		if w.ShareToken != nil && *w.ShareToken == token {
+			return copyWishlist(w), nil
+		}
+	}
+
+	return nil, fmt.Errorf("error getting wishlist: %w", sql.ErrNoRows)
+}
+
+func (ms *MemoryStorer) ListUserWishlists(ctx context.Context, userID int64) ([]*Wishlist, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var wishlists []*Wishlist
+	for _, id := range sortedKeys(ms.wishlists) {
+		if w := ms.wishlists[id]; w.UserID == userID {
+			wishlists = append(wishlists, copyWishlist(w))
+		}
+	}
+
+	return wishlists, nil
+}
+
+func (ms *MemoryStorer) UpdateWishlist(ctx context.Context, w *Wishlist) (*Wishlist, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	existing, ok := ms.wishlists[w.ID]
+	if !ok {
+		return w, nil
+	}
+	c := *w
+	c.UserID = existing.UserID
+	if err := ms.checkWishlistUnique(&c); err != nil {
+		return nil, err
+	}
+
+	existing.Name = w.Name
+	existing.ShareToken = w.ShareToken
+	existing.UpdatedAt = w.UpdatedAt
+
+	return w, nil
+}
+
+func (ms *MemoryStorer) DeleteWishlist(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	delete(ms.wishlists, id)
+	return nil
+}
+
+func (ms *MemoryStorer) AddWishlistItem(ctx context.Context, wishlistID, productID, variantID int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	w, ok := ms.wishlists[wishlistID]
+	if !ok {
+		return fmt.Errorf("error adding wishlist item: wishlist %d does not exist", wishlistID)
+	}
+	if _, ok := ms.products[productID]; !ok {
+		return fmt.Errorf("error adding wishlist item: product %d does not exist", productID)
+	}
+
+	for _, wi := range w.Items {
+		if wi.ProductID == productID && wi.VariantID == variantID {
+			return nil
+		}
+	}
+	w.Items = append(w.Items, WishlistItem{
+		ID:         ms.nextID("wishlist_items"),
+		WishlistID: wishlistID,
+		ProductID:  productID,
+		VariantID:  variantID,
+		CreatedAt:  time.Now(),
+	})
+
+	return nil
+}
+
+func (ms *MemoryStorer) RemoveWishlistItem(ctx context.Context, wishlistID, productID, variantID int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	w, ok := ms.wishlists[wishlistID]
+	if !ok {
+		return fmt.Errorf("error removing wishlist item: %w", sql.ErrNoRows)
+	}
+	for i, wi := range w.Items {
+		if wi.ProductID == productID && wi.VariantID == variantID {
+			w.Items = append(w.Items[:i], w.Items[i+1:]...)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("error removing wishlist item: %w", sql.ErrNoRows)
+}
+
+func (ms *MemoryStorer) CreateReview(ctx context.Context, r *Review) (*Review, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.products[r.ProductID]; !ok {
+		return nil, fmt.Errorf("error inserting review: product %d does not exist", r.ProductID)
+	}
+	for _, existing := range ms.reviews {
+		if existing.ProductID == r.ProductID && existing.UserID == r.UserID {
+			return nil, fmt.Errorf("error creating review: %w", ErrReviewExists)
+		}
+	}
+
+	r.ID = ms.nextID("reviews")
+	c := *r
+	c.Hidden = false
+	c.CreatedAt = time.Now()
+	ms.reviews[r.ID] = &c
+	ms.updateProductRating(r.ProductID)
+
+	return r, nil
+}
+
+func (ms *MemoryStorer) GetReview(ctx context.Context, id int64) (*Review, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	r, ok := ms.reviews[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting review: %w", sql.ErrNoRows)
+	}
+
+	c := *r
+	return &c, nil
+}
+
+func (ms *MemoryStorer) ListProductReviews(ctx context.Context, params *ListReviewsParams) (*ReviewPage, error) {
+	limit := pageSize(params.Limit)
+	after, err := params.cursor()
+	if err != nil {
+		return nil, fmt.Errorf("error listing reviews: %w", err)
+	}
+
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var reviews []*Review
+	for _, r := range ms.reviews {
+		switch {
+		case r.ProductID != params.ProductID,
+			r.Hidden && !params.IncludeHidden,
+			after != nil && compareReviewsNewestFirst(r, after) <= 0:
+			continue
+		}
+		c := *r
+		reviews = append(reviews, &c)
+	}
+	sort.Slice(reviews, func(i, j int) bool {
+		return compareReviewsNewestFirst(reviews[i], reviews[j]) < 0
+	})
+	if len(reviews) > limit+1 {
+		reviews = reviews[:limit+1]
+	}
+
+	return newReviewPage(reviews, limit), nil
+}
+
+func (ms *MemoryStorer) UpdateReview(ctx context.Context, r *Review) (*Review, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	existing, ok := ms.reviews[r.ID]
+	if !ok {
+		return nil, fmt.Errorf("error updating review: %w", sql.ErrNoRows)
+	}
+	existing.Rating = r.Rating
+	existing.Title = r.Title
+	existing.Body = r.Body
+	existing.UpdatedAt = r.UpdatedAt
+	ms.updateProductRating(existing.ProductID)
+
+	return r, nil
+}
+
+func (ms *MemoryStorer) SetReviewHidden(ctx context.Context, id int64, hidden bool) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	r, ok := ms.reviews[id]
+	if !ok {
+		return fmt.Errorf("error moderating review: %w", sql.ErrNoRows)
+	}
+	now := time.Now()
+	r.Hidden = hidden
+	r.UpdatedAt = &now
+	ms.updateProductRating(r.ProductID)
+
+	return nil
+}
+
+func (ms *MemoryStorer) DeleteReview(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	r, ok := ms.reviews[id]
+	if !ok {
+		return fmt.Errorf("error deleting review: %w", sql.ErrNoRows)
+	}
+	delete(ms.reviews, id)
+	ms.updateProductRating(r.ProductID)
+
+	return nil
+}
+
+func (ms *MemoryStorer) HasDeliveredOrder(ctx context.Context, userID, productID int64) (bool, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	for _, o := range ms.orders {
+		if o.UserID != userID || o.Status != Delivered {
+			continue
+		}
+		for _, oi := range o.Items {
+			if oi.ProductID == productID {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// updateProductRating recomputes a product's rating, the average of its
+// visible reviews rounded half up, and its review count; callers must hold
+// the write lock.
+func (ms *MemoryStorer) updateProductRating(productID int64) {
+	p, ok := ms.products[productID]
+	if !ok {
+		return
+	}
+
+	var sum, n int64
+	for _, r := range ms.reviews {
+		if r.ProductID == productID && !r.Hidden {
+			sum += r.Rating
+			n++
+		}
+	}
+
+	p.NumReviews = n
+	p.Rating = 0
+	if n > 0 {
+		p.Rating = (2*sum + n) / (2 * n)
+	}
+}
+
+func (ms *MemoryStorer) CreateUser(ctx context.Context, u *User) (*User, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	for _, existing := range ms.users {
+		if existing.Email == u.Email {
+			return nil, fmt.Errorf("error inserting user: duplicate email %q", u.Email)
+		}
+	}
+
+	u.ID = ms.nextID("users")
+	c := *u
+	c.CreatedAt = time.Now()
+	ms.users[u.ID] = &c
+
+	return u, nil
+}
+
+func (ms *MemoryStorer) GetUser(ctx context.Context, email string) (*User, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	for _, u := range ms.users {
+		if u.Email == email {
+			c := *u
+			return &c, nil
+		}
+	}
+
+	return nil, fmt.Errorf("error getting user: %w", sql.ErrNoRows)
+}
+
+func (ms *MemoryStorer) GetUserByID(ctx context.Context, id int64) (*User, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	u, ok := ms.users[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting user: %w", sql.ErrNoRows)
+	}
+
+	c := *u
+	return &c, nil
+}
+
+func (ms *MemoryStorer) ListUsers(ctx context.Context) ([]*User, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	users := make([]*User, 0, len(ms.users))
+	for _, id := range sortedKeys(ms.users) {
+		c := *ms.users[id]
+		users = append(users, &c)
+	}
+
+	return users, nil
+}
+
+func (ms *MemoryStorer) UpdateUser(ctx context.Context, u *User) (*User, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if existing, ok := ms.users[u.ID]; ok {
+		c := *u
+		c.TaxExempt = existing.TaxExempt
+		c.CreatedAt = existing.CreatedAt
+		ms.users[u.ID] = &c
+	}
+
+	return u, nil
+}
+
+func (ms *MemoryStorer) SetUserTaxExempt(ctx context.Context, id int64, exempt bool) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	u, ok := ms.users[id]
+	if !ok {
+		return fmt.Errorf("error updating user tax exemption: %w", sql.ErrNoRows)
+	}
+	now := time.Now()
+	u.TaxExempt = exempt
+	u.UpdatedAt = &now
+
+	return nil
+}
+
+func (ms *MemoryStorer) DeleteUser(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	for _, o := range ms.orders {
+		if o.UserID == id {
+			return fmt.Errorf("error deleting user: user %d is referenced by order %d", id, o.ID)
+		}
+	}
+
+	for reviewID, r := range ms.reviews {
+		if r.UserID == id {
+			delete(ms.reviews, reviewID)
+		}
+	}
+	for addressID, a := range ms.addresses {
+		if a.UserID == id {
+			delete(ms.addresses, addressID)
+		}
+	}
+	for wishlistID, w := range ms.wishlists {
+		if w.UserID == id {
+			delete(ms.wishlists, wishlistID)
+		}
+	}
+	for _, m := range ms.stockMovements {
+		if m.ActorID != nil && *m.ActorID == id {
+			m.ActorID = nil
+		}
+	}
+	for _, g := range ms.giftCards {
+		if g.PurchaserID != nil && *g.PurchaserID == id {
+			g.PurchaserID = nil
+		}
+		if g.IssuedBy != nil && *g.IssuedBy == id {
+			g.IssuedBy = nil
+		}
+	}
+	for _, t := range ms.giftCardTxs {
+		if t.ActorID != nil && *t.ActorID == id {
+			t.ActorID = nil
+		}
+	}
+	for txID, t := range ms.storeCreditTxs {
+		switch {
+		case t.UserID == id:
+			delete(ms.storeCreditTxs, txID)
+		case t.ActorID != nil && *t.ActorID == id:
+			t.ActorID = nil
+		}
+	}
+	delete(ms.storeCredits, id)
+	for txID, t := range ms.loyaltyTxs {
+		if t.UserID == id {
+			delete(ms.loyaltyTxs, txID)
+		}
+	}
+	delete(ms.loyaltyPoints, id)
+	delete(ms.carts, id)
+	delete(ms.users, id)
+	return nil
+}
+
+func (ms *MemoryStorer) CreateSession(ctx context.Context, s *Session) (*Session, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.sessions[s.ID]; ok {
+		return nil, fmt.Errorf("error inserting session: duplicate id %q", s.ID)
+	}
+
+	c := *s
+	c.CreatedAt = time.Now()
+	ms.sessions[s.ID] = &c
+
+	return s, nil
+}
+
+func (ms *MemoryStorer) GetSession(ctx context.Context, id string) (*Session, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	s, ok := ms.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting session: %w", sql.ErrNoRows)
+	}
+
+	c := *s
+	return &c, nil
+}
+
+func (ms *MemoryStorer) RevokeSession(ctx context.Context, id string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if s, ok := ms.sessions[id]; ok {
+		s.IsRevoked = true
+	}
+
+	return nil
+}
+
+func (ms *MemoryStorer) DeleteSession(ctx context.Context, id string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	delete(ms.sessions, id)
+	return nil
+}
+
+func (ms *MemoryStorer) EnqueueNotificatioEvent(ctx context.Context, ne *NotificationEvent) (*NotificationEvent, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	return ms.enqueueNotificationEvent(ne), nil
+}
+
+// enqueueNotificationEvent inserts the state and queue rows for ne; callers
+// must hold the write lock.
+func (ms *MemoryStorer) enqueueNotificationEvent(ne *NotificationEvent) *NotificationEvent {
+	now := time.Now()
+
+	ns := &NotificationState{
+		ID:          ms.nextID("notification_states"),
+		OrderID:     ne.OrderID,
+		State:       NotSent,
+		RequestedAt: now,
+	}
+	ms.notificationStates[ns.ID] = ns
+
+	ne.StateID = ns.ID
+	ne.ID = ms.nextID("notification_events_queue")
+	c := *ne
+	c.CreatedAt = now
+	ms.notificationEvents[ne.ID] = &c
+
+	return ne
+}
+
+func (ms *MemoryStorer) ListNotificationEvents(ctx context.Context) ([]*NotificationEvent, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var events []*NotificationEvent
+	for _, id := range sortedKeys(ms.notificationEvents) {
+		ev := ms.notificationEvents[id]
+		if ev.Attempts < maxAttempts {
+			c := *ev
+			events = append(events, &c)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].CreatedAt.Before(events[j].CreatedAt)
+	})
+
+	return events, nil
+}
+
+func (ms *MemoryStorer) ClaimNotificationEvents(ctx context.Context, limit int, lease time.Duration) ([]*NotificationEvent, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	now := time.Now()
+	var due []*NotificationEvent
+	for _, ev := range ms.notificationEvents {
+		if ev.Attempts < maxAttempts && (ev.LockedUntil == nil || !ev.LockedUntil.After(now)) {
+			due = append(due, ev)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].CreatedAt.Equal(due[j].CreatedAt) {
+			return due[i].CreatedAt.Before(due[j].CreatedAt)
+		}
+		return due[i].ID < due[j].ID
+	})
+	if n := pageSize(limit); len(due) > n {
+		due = due[:n]
+	}
+
+	lockedUntil := now.Add(lease)
+	events := make([]*NotificationEvent, 0, len(due))
+	for _, ev := range due {
+		ev.LockedUntil = &lockedUntil
+		c := *ev
+		events = append(events, &c)
+	}
+
+	return events, nil
+}
+
+func (ms *MemoryStorer) updateNotificationState(es *NotificationState) {
+	ns, ok := ms.notificationStates[es.ID]
+	if !ok {
+		return
+	}
+
+	ns.State = es.State
+	ns.Message = es.Message
+	if es.State == Sent {
+		t := time.Now()
+		ns.CompletedAt = &t
+	}
+}
+
+func (ms *MemoryStorer) UpdateNotificationEvent(ctx context.Context, ev *NotificationEvent, es *NotificationState, responseType NotificationResponseType) (bool, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	switch responseType {
+	case NotificationSuccess:
+		ms.updateNotificationState(&NotificationState{
+			ID:      ev.StateID,
+			State:   Sent,
+			Message: es.Message,
+		})
+		delete(ms.notificationEvents, ev.ID)
+		return true, nil
+
+	case NotificationFailure:
+		u, ok := ms.notificationEvents[ev.ID]
+		if !ok {
+			return false, fmt.Errorf("error getting notification event: %w", sql.ErrNoRows)
+		}
+
+		if u.Attempts+1 < maxAttempts {
+			t := time.Now()
+			u.UpdatedAt = &t
+			u.Attempts += 1
+			retryAt := t.Add(retryBackoff(u.Attempts))
+			u.LockedUntil = &retryAt
+		} else {
+			ms.updateNotificationState(&NotificationState{
+				ID:      ev.StateID,
+				State:   Failed,
+				Message: es.Message,
+			})
+			delete(ms.notificationEvents, u.ID)
+		}
+		return false, nil
+
+	default:
+		return false, fmt.Errorf("invalid notification response type: %v", responseType)
+	}
+}
@@ -0,0 +1,1481 @@
+package storer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"github.com/OrkhanMehbaliyev/ecom-golang/payments"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProduct() *Product {
+	return &Product{
+		Name:         "test product",
+		Image:        "test.jpg",
+		Description:  "test description",
+		Rating:       5,
+		NumReviews:   10,
+		Price:        money.New(10000, money.DefaultCurrency),
+		CountInStock: 100,
+	}
+}
+
+func TestMemoryProducts(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	cp, err := st.CreateProduct(ctx, newTestProduct())
+	require.NoError(t, err)
+	require.Equal(t, int64(1), cp.ID)
+
+	gp, err := st.GetProduct(ctx, cp.ID)
+	require.NoError(t, err)
+	require.Equal(t, cp.Name, gp.Name)
+	require.False(t, gp.CreatedAt.IsZero())
+
+	gp.Name = "new test product"
+	_, err = st.UpdateProduct(ctx, gp)
+	require.NoError(t, err)
+
+	page, err := st.ListProducts(ctx, &ListProductsParams{})
+	require.NoError(t, err)
+	require.Len(t, page.Products, 1)
+	require.Equal(t, "new test product", page.Products[0].Name)
+
+	require.NoError(t, st.DeleteProduct(ctx, cp.ID))
+	_, err = st.GetProduct(ctx, cp.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestMemoryListProductsPagination(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	prices := []int64{3000, 1000, 2000, 1000, 5000}
+	for i, price := range prices {
+		p := newTestProduct()
+		p.Price = money.New(price, money.DefaultCurrency)
+		p.CountInStock = int64(i)
+		_, err := st.CreateProduct(ctx, p)
+		require.NoError(t, err)
+	}
+
+	minPrice := money.New(1500, money.DefaultCurrency)
+	tcs := []struct {
+		name   string
+		params ListProductsParams
+		ids    []int64
+	}{
+		{
+			name:   "price ascending",
+			params: ListProductsParams{SortBy: SortByPrice, Limit: 2},
+			ids:    []int64{2, 4, 3, 1, 5},
+		},
+		{
+			name:   "price descending",
+			params: ListProductsParams{SortBy: SortByPrice, Desc: true, Limit: 2},
+			ids:    []int64{5, 1, 3, 4, 2},
+		},
+		{
+			name:   "filtered",
+			params: ListProductsParams{MinPrice: &minPrice, InStock: true, Limit: 1},
+			ids:    []int64{3, 5},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			params := tc.params
+			var ids []int64
+			for {
+				page, err := st.ListProducts(ctx, &params)
+				require.NoError(t, err)
+				require.LessOrEqual(t, len(page.Products), params.Limit)
+				for _, p := range page.Products {
+					ids = append(ids, p.ID)
+				}
+				if page.NextCursor == "" {
+					break
+				}
+				params.Cursor = page.NextCursor
+			}
+			require.Equal(t, tc.ids, ids)
+		})
+	}
+}
+
+func TestMemoryListProductsScheduledPrice(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	var ids []int64
+	for _, price := range []int64{3000, 2000, 1000} {
+		p := newTestProduct()
+		p.Price = money.New(price, money.DefaultCurrency)
+		p, err := st.CreateProduct(ctx, p)
+		require.NoError(t, err)
+		ids = append(ids, p.ID)
+	}
+
+	// the first product is on sale now, the second only later
+	now := time.Now()
+	_, err := st.CreateScheduledPrice(ctx, &ScheduledPrice{ProductID: ids[0], Price: money.New(500, money.DefaultCurrency), StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)})
+	require.NoError(t, err)
+	_, err = st.CreateScheduledPrice(ctx, &ScheduledPrice{ProductID: ids[1], Price: money.New(100, money.DefaultCurrency), StartsAt: now.Add(time.Hour), EndsAt: now.Add(2 * time.Hour)})
+	require.NoError(t, err)
+
+	maxPrice := money.New(1500, money.DefaultCurrency)
+	tcs := []struct {
+		name   string
+		params ListProductsParams
+		ids    []int64
+	}{
+		{
+			name:   "price ascending",
+			params: ListProductsParams{SortBy: SortByPrice, Limit: 1},
+			ids:    []int64{ids[0], ids[2], ids[1]},
+		},
+		{
+			name:   "filtered",
+			params: ListProductsParams{MaxPrice: &maxPrice},
+			ids:    []int64{ids[0], ids[2]},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			params := tc.params
+			var got []int64
+			for {
+				page, err := st.ListProducts(ctx, &params)
+				require.NoError(t, err)
+				for _, p := range page.Products {
+					got = append(got, p.ID)
+				}
+				if page.NextCursor == "" {
+					break
+				}
+				params.Cursor = page.NextCursor
+			}
+			require.Equal(t, tc.ids, got)
+		})
+	}
+}
+
+func TestMemoryOrders(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	u, err := st.CreateUser(ctx, &User{Name: "test", Email: "test@example.com"})
+	require.NoError(t, err)
+	p, err := st.CreateProduct(ctx, newTestProduct())
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name  string
+		order *Order
+		err   bool
+	}{
+		{
+			name: "success",
+			order: &Order{
+				PaymentMethod: "test payment method",
+				UserID:        u.ID,
+				Items:         []OrderItem{{Name: p.Name, Quantity: 1, ProductID: p.ID}},
+			},
+		},
+		{
+			name:  "unknown user",
+			order: &Order{UserID: 42},
+			err:   true,
+		},
+		{
+			name: "unknown product",
+			order: &Order{
+				UserID: u.ID,
+				Items:  []OrderItem{{Name: "missing", Quantity: 1, ProductID: 42}},
+			},
+			err: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			o, err := st.CreateOrder(ctx, tc.order, nil)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotZero(t, o.ID)
+			require.Equal(t, o.ID, o.Items[0].OrderID)
+
+			so, err := st.GetOrderStatusByID(ctx, o.ID)
+			require.NoError(t, err)
+			require.Equal(t, Pending, so.Status)
+		})
+	}
+
+	orders, err := st.ListOrders(ctx)
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	require.Len(t, orders[0].Items, 1)
+
+	require.Error(t, st.DeleteProduct(ctx, p.ID))
+	require.NoError(t, st.DeleteOrder(ctx, orders[0].ID))
+	require.NoError(t, st.DeleteProduct(ctx, p.ID))
+}
+
+func TestMemoryOrderStock(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	u, err := st.CreateUser(ctx, &User{Name: "test", Email: "test@example.com"})
+	require.NoError(t, err)
+	p := newTestProduct()
+	p.CountInStock = 3
+	p, err = st.CreateProduct(ctx, p)
+	require.NoError(t, err)
+
+	stock := func() int64 {
+		gp, err := st.GetProduct(ctx, p.ID)
+		require.NoError(t, err)
+		return gp.CountInStock
+	}
+
+	o, err := st.CreateOrder(ctx, &Order{UserID: u.ID, Items: []OrderItem{{ProductID: p.ID, Quantity: 1}, {ProductID: p.ID, Quantity: 1}}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), stock())
+
+	_, err = st.CreateOrder(ctx, &Order{UserID: u.ID, Items: []OrderItem{{ProductID: p.ID, Quantity: 2}}}, nil)
+	require.ErrorIs(t, err, ErrInsufficientStock)
+	require.Equal(t, int64(1), stock())
+
+	_, err = st.UpdateOrderStatus(ctx, &Order{ID: o.ID, Status: Cancelled}, &OrderStatusChange{FromStatus: Pending}, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), stock())
+
+	_, err = st.UpdateOrderStatus(ctx, &Order{ID: o.ID, Status: Refunded}, &OrderStatusChange{FromStatus: Cancelled}, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), stock())
+
+	require.NoError(t, st.DeleteOrder(ctx, o.ID))
+	require.Equal(t, int64(3), stock())
+}
+
+func TestMemoryListUserOrders(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	u, err := st.CreateUser(ctx, &User{Name: "test", Email: "test@example.com"})
+	require.NoError(t, err)
+	other, err := st.CreateUser(ctx, &User{Name: "other", Email: "other@example.com"})
+	require.NoError(t, err)
+	p, err := st.CreateProduct(ctx, newTestProduct())
+	require.NoError(t, err)
+
+	for _, userID := range []int64{u.ID, other.ID, u.ID, u.ID, u.ID} {
+		_, err := st.CreateOrder(ctx, &Order{UserID: userID, Items: []OrderItem{{ProductID: p.ID, Quantity: 1}}}, nil)
+		require.NoError(t, err)
+	}
+	_, err = st.UpdateOrderStatus(ctx, &Order{ID: 4, Status: Cancelled}, &OrderStatusChange{FromStatus: Pending}, nil)
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name   string
+		params ListOrdersParams
+		ids    []int64
+	}{
+		{
+			name:   "newest first",
+			params: ListOrdersParams{UserID: u.ID, Limit: 2},
+			ids:    []int64{5, 4, 3, 1},
+		},
+		{
+			name:   "by status",
+			params: ListOrdersParams{UserID: u.ID, Status: Pending, Limit: 2},
+			ids:    []int64{5, 3, 1},
+		},
+		{
+			name:   "other user",
+			params: ListOrdersParams{UserID: other.ID},
+			ids:    []int64{2},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			params := tc.params
+			var ids []int64
+			for {
+				page, err := st.ListUserOrders(ctx, &params)
+				require.NoError(t, err)
+				for _, o := range page.Orders {
+					require.Len(t, o.Items, 1)
+					ids = append(ids, o.ID)
+				}
+				if page.NextCursor == "" {
+					break
+				}
+				params.Cursor = page.NextCursor
+			}
+			require.Equal(t, tc.ids, ids)
+		})
+	}
+}
+
+func TestMemoryOrderStatusHistory(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	u, err := st.CreateUser(ctx, &User{Name: "test", Email: "test@example.com"})
+	require.NoError(t, err)
+	p, err := st.CreateProduct(ctx, newTestProduct())
+	require.NoError(t, err)
+	o, err := st.CreateOrder(ctx, &Order{UserID: u.ID, Items: []OrderItem{{ProductID: p.ID, Quantity: 1}}}, nil)
+	require.NoError(t, err)
+
+	_, err = st.UpdateOrderStatus(ctx, &Order{ID: o.ID, Status: Paid}, &OrderStatusChange{FromStatus: Pending, ActorID: 2, ActorRole: ActorAdmin}, nil)
+	require.NoError(t, err)
+
+	_, err = st.UpdateOrderStatus(ctx, &Order{ID: o.ID, Status: Cancelled}, &OrderStatusChange{FromStatus: Pending, ActorID: u.ID, ActorRole: ActorCustomer}, nil)
+	require.ErrorIs(t, err, ErrOrderStatusChanged)
+
+	_, err = st.UpdateOrderStatus(ctx, &Order{ID: o.ID, Status: Cancelled}, &OrderStatusChange{FromStatus: Paid, Reason: "changed my mind", ActorID: u.ID, ActorRole: ActorCustomer}, nil)
+	require.NoError(t, err)
+
+	changes, err := st.ListOrderStatusChanges(ctx, o.ID)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, Pending, changes[0].FromStatus)
+	require.Equal(t, Paid, changes[0].ToStatus)
+	require.Equal(t, ActorAdmin, changes[0].ActorRole)
+	require.Equal(t, Cancelled, changes[1].ToStatus)
+	require.Equal(t, "changed my mind", changes[1].Reason)
+
+	require.NoError(t, st.DeleteOrder(ctx, o.ID))
+	changes, err = st.ListOrderStatusChanges(ctx, o.ID)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func TestMemoryCart(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	u, err := st.CreateUser(ctx, &User{Name: "test", Email: "test@example.com"})
+	require.NoError(t, err)
+	p1, err := st.CreateProduct(ctx, newTestProduct())
+	require.NoError(t, err)
+	p2, err := st.CreateProduct(ctx, newTestProduct())
+	require.NoError(t, err)
+
+	c, err := st.GetCart(ctx, u.ID)
+	require.NoError(t, err)
+	require.Zero(t, c.ID)
+	require.Empty(t, c.Items)
+
+	require.NoError(t, st.SetCartItem(ctx, u.ID, p1.ID, 0, 2))
+	require.NoError(t, st.SetCartItem(ctx, u.ID, p2.ID, 0, 1))
+	require.NoError(t, st.SetCartItem(ctx, u.ID, p1.ID, 0, 3))
+	require.Error(t, st.SetCartItem(ctx, u.ID, 42, 0, 1))
+
+	c, err = st.GetCart(ctx, u.ID)
+	require.NoError(t, err)
+	require.NotZero(t, c.ID)
+	require.Len(t, c.Items, 2)
+	require.Equal(t, p1.ID, c.Items[0].ProductID)
+	require.Equal(t, int64(3), c.Items[0].Quantity)
+
+	require.NoError(t, st.RemoveCartItem(ctx, u.ID, p1.ID, 0))
+	require.ErrorIs(t, st.RemoveCartItem(ctx, u.ID, p1.ID, 0), sql.ErrNoRows)
+
+	// deleting a product drops it from carts
+	require.NoError(t, st.DeleteProduct(ctx, p2.ID))
+	c, err = st.GetCart(ctx, u.ID)
+	require.NoError(t, err)
+	require.Empty(t, c.Items)
+
+	require.NoError(t, st.SetCartItem(ctx, u.ID, p1.ID, 0, 1))
+	require.NoError(t, st.ClearCart(ctx, u.ID))
+	c, err = st.GetCart(ctx, u.ID)
+	require.NoError(t, err)
+	require.Empty(t, c.Items)
+}
+
+func TestMemoryVariants(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	u, err := st.CreateUser(ctx, &User{Name: "test", Email: "test@example.com"})
+	require.NoError(t, err)
+	tp := newTestProduct()
+	tp.Options = ProductOptions{"size"}
+	p, err := st.CreateProduct(ctx, tp)
+	require.NoError(t, err)
+
+	stock := func() int64 {
+		gp, err := st.GetProduct(ctx, p.ID)
+		require.NoError(t, err)
+		return gp.CountInStock
+	}
+
+	m, err := st.CreateVariant(ctx, &Variant{ProductID: p.ID, SKU: "TS-M", Options: VariantOptions{"size": "M"}, CountInStock: 2})
+	require.NoError(t, err)
+	l, err := st.CreateVariant(ctx, &Variant{ProductID: p.ID, SKU: "TS-L", Options: VariantOptions{"size": "L"}, CountInStock: 3})
+	require.NoError(t, err)
+	_, err = st.CreateVariant(ctx, &Variant{ProductID: p.ID, SKU: "TS-M", Options: VariantOptions{"size": "S"}})
+	require.ErrorIs(t, err, ErrSKUExists)
+	require.Equal(t, int64(5), stock())
+
+	err = st.RecordStockMovements(ctx, []*StockMovement{{WarehouseID: MainWarehouseID, ProductID: p.ID, VariantID: l.ID, Kind: StockAdjustment, Quantity: -2, Reason: "damaged"}})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), stock())
+
+	variants, err := st.ListProductVariants(ctx, []int64{p.ID})
+	require.NoError(t, err)
+	require.Len(t, variants, 2)
+	require.Equal(t, "TS-M", variants[0].SKU)
+
+	// variant lines are checked against the variant's stock
+	_, err = st.CreateOrder(ctx, &Order{UserID: u.ID, Items: []OrderItem{{ProductID: p.ID, VariantID: &l.ID, Quantity: 2}}}, nil)
+	var stockErr *InsufficientStockError
+	require.ErrorAs(t, err, &stockErr)
+	require.Equal(t, "TS-L", stockErr.SKU)
+
+	o, err := st.CreateOrder(ctx, &Order{UserID: u.ID, Items: []OrderItem{{ProductID: p.ID, VariantID: &m.ID, Quantity: 2}}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), stock())
+	gv, err := st.GetVariant(ctx, m.ID)
+	require.NoError(t, err)
+	require.Zero(t, gv.CountInStock)
+
+	_, err = st.UpdateOrderStatus(ctx, &Order{ID: o.ID, Status: Cancelled}, &OrderStatusChange{FromStatus: Pending}, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), stock())
+	gv, err = st.GetVariant(ctx, m.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), gv.CountInStock)
+
+	// cart items are keyed by variant
+	require.NoError(t, st.SetCartItem(ctx, u.ID, p.ID, m.ID, 1))
+	require.NoError(t, st.SetCartItem(ctx, u.ID, p.ID, l.ID, 1))
+	c, err := st.GetCart(ctx, u.ID)
+	require.NoError(t, err)
+	require.Len(t, c.Items, 2)
+
+	require.ErrorIs(t, st.DeleteVariant(ctx, m.ID), ErrVariantInUse)
+	require.NoError(t, st.DeleteVariant(ctx, l.ID))
+	require.Equal(t, int64(2), stock())
+	c, err = st.GetCart(ctx, u.ID)
+	require.NoError(t, err)
+	require.Len(t, c.Items, 1)
+	_, err = st.GetVariant(ctx, l.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestMemoryInventory(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	warehouses, err := st.ListWarehouses(ctx)
+	require.NoError(t, err)
+	require.Len(t, warehouses, 1)
+	require.Equal(t, "MAIN", warehouses[0].Code)
+
+	east, err := st.CreateWarehouse(ctx, &Warehouse{Code: "EAST", Name: "East"})
+	require.NoError(t, err)
+	_, err = st.CreateWarehouse(ctx, &Warehouse{Code: "EAST", Name: "East 2"})
+	require.ErrorIs(t, err, ErrWarehouseExists)
+
+	u, err := st.CreateUser(ctx, &User{Name: "test", Email: "test@example.com"})
+	require.NoError(t, err)
+	p := newTestProduct()
+	p.CountInStock = 3
+	p, err = st.CreateProduct(ctx, p)
+	require.NoError(t, err)
+
+	stock := func() int64 {
+		gp, err := st.GetProduct(ctx, p.ID)
+		require.NoError(t, err)
+		return gp.CountInStock
+	}
+
+	// a transfer moves stock between warehouses and leaves the count alone
+	eastID := east.ID
+	mainID := MainWarehouseID
+	err = st.RecordStockMovements(ctx, []*StockMovement{
+		{WarehouseID: MainWarehouseID, ProductID: p.ID, Kind: StockTransfer, Quantity: -2, OtherWarehouseID: &eastID},
+		{WarehouseID: east.ID, ProductID: p.ID, Kind: StockTransfer, Quantity: 2, OtherWarehouseID: &mainID},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), stock())
+
+	err = st.RecordStockMovements(ctx, []*StockMovement{{WarehouseID: MainWarehouseID, ProductID: p.ID, Kind: StockAdjustment, Quantity: -2}})
+	require.ErrorIs(t, err, ErrInsufficientStock)
+	err = st.RecordStockMovements(ctx, []*StockMovement{{WarehouseID: MainWarehouseID, ProductID: p.ID, VariantID: 99, Kind: StockReceipt, Quantity: 1}})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// orders take stock from the lowest warehouse id first and cancelling
+	// puts it back where it came from
+	o, err := st.CreateOrder(ctx, &Order{UserID: u.ID, Items: []OrderItem{{ProductID: p.ID, Quantity: 2}}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), stock())
+	levels, err := st.ListStockLevels(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, []*StockLevel{
+		{WarehouseID: MainWarehouseID, ProductID: p.ID},
+		{WarehouseID: east.ID, ProductID: p.ID, Quantity: 1},
+	}, levels)
+
+	_, err = st.UpdateOrderStatus(ctx, &Order{ID: o.ID, Status: Cancelled}, &OrderStatusChange{FromStatus: Pending, ActorID: u.ID}, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), stock())
+	levels, err = st.ListStockLevels(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), levels[0].Quantity)
+	require.Equal(t, int64(2), levels[1].Quantity)
+
+	movements, err := st.ListStockMovements(ctx, &ListStockMovementsParams{ProductID: p.ID, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, movements, 2)
+	require.Equal(t, StockRelease, movements[0].Kind)
+	require.Equal(t, int64(1), movements[0].Quantity)
+	require.Equal(t, "order 1 cancelled", movements[0].Reason)
+	require.Equal(t, u.ID, *movements[0].ActorID)
+
+	movements, err = st.ListStockMovements(ctx, &ListStockMovementsParams{ProductID: p.ID, BeforeID: movements[1].ID})
+	require.NoError(t, err)
+	require.Len(t, movements, 5)
+	require.Equal(t, StockReceipt, movements[4].Kind, "opening stock comes first")
+
+	movements, err = st.ListStockMovements(ctx, &ListStockMovementsParams{ProductID: p.ID, WarehouseID: east.ID})
+	require.NoError(t, err)
+	require.Len(t, movements, 3)
+}
+
+func TestMemoryReviews(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	p, err := st.CreateProduct(ctx, newTestProduct())
+	require.NoError(t, err)
+
+	rating := func() (int64, int64) {
+		gp, err := st.GetProduct(ctx, p.ID)
+		require.NoError(t, err)
+		return gp.Rating, gp.NumReviews
+	}
+
+	var ids []int64
+	for user, stars := range []int64{5, 4, 4} {
+		r, err := st.CreateReview(ctx, &Review{ProductID: p.ID, UserID: int64(user + 1), Rating: stars})
+		require.NoError(t, err)
+		ids = append(ids, r.ID)
+	}
+	_, err = st.CreateReview(ctx, &Review{ProductID: p.ID, UserID: 1, Rating: 1})
+	require.ErrorIs(t, err, ErrReviewExists)
+
+	// 13/3 rounds to 4
+	r, n := rating()
+	require.Equal(t, int64(4), r)
+	require.Equal(t, int64(3), n)
+
+	// 9/2 rounds half up to 5
+	require.NoError(t, st.SetReviewHidden(ctx, ids[2], true))
+	r, n = rating()
+	require.Equal(t, int64(5), r)
+	require.Equal(t, int64(2), n)
+
+	page, err := st.ListProductReviews(ctx, &ListReviewsParams{ProductID: p.ID, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, page.Reviews, 1)
+	require.NotEmpty(t, page.NextCursor)
+	page, err = st.ListProductReviews(ctx, &ListReviewsParams{ProductID: p.ID, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, page.Reviews, 1)
+	require.Empty(t, page.NextCursor)
+
+	page, err = st.ListProductReviews(ctx, &ListReviewsParams{ProductID: p.ID, IncludeHidden: true})
+	require.NoError(t, err)
+	require.Len(t, page.Reviews, 3)
+
+	_, err = st.UpdateReview(ctx, &Review{ID: ids[0], Rating: 1})
+	require.NoError(t, err)
+	require.NoError(t, st.DeleteReview(ctx, ids[1]))
+	r, n = rating()
+	require.Equal(t, int64(1), r)
+	require.Equal(t, int64(1), n)
+
+	// admin edits do not overwrite the derived rating
+	gp, err := st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	gp.Rating, gp.NumReviews = 0, 0
+	_, err = st.UpdateProduct(ctx, gp)
+	require.NoError(t, err)
+	r, n = rating()
+	require.Equal(t, int64(1), r)
+	require.Equal(t, int64(1), n)
+}
+
+func TestMemoryCategories(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	shoes, err := st.CreateCategory(ctx, &Category{Name: "Shoes", Slug: "shoes"})
+	require.NoError(t, err)
+	running, err := st.CreateCategory(ctx, &Category{ParentID: &shoes.ID, Name: "Running", Slug: "running"})
+	require.NoError(t, err)
+	_, err = st.CreateCategory(ctx, &Category{Name: "Accessories", Slug: "accessories", SortOrder: -1})
+	require.NoError(t, err)
+
+	_, err = st.CreateCategory(ctx, &Category{Name: "Shoes again", Slug: "shoes"})
+	require.ErrorIs(t, err, ErrCategoryExists)
+	missing := int64(99)
+	_, err = st.CreateCategory(ctx, &Category{ParentID: &missing, Name: "Orphan", Slug: "orphan"})
+	require.Error(t, err)
+
+	categories, err := st.ListCategories(ctx)
+	require.NoError(t, err)
+	require.Len(t, categories, 3)
+	require.Equal(t, "accessories", categories[0].Slug)
+
+	c, err := st.GetCategoryBySlug(ctx, "running")
+	require.NoError(t, err)
+	require.Equal(t, running.ID, c.ID)
+	_, err = st.GetCategoryBySlug(ctx, "missing")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	tp := newTestProduct()
+	tp.CategoryID = &running.ID
+	p, err := st.CreateProduct(ctx, tp)
+	require.NoError(t, err)
+	tp = newTestProduct()
+	tp.CategoryID = &missing
+	_, err = st.CreateProduct(ctx, tp)
+	require.Error(t, err)
+
+	page, err := st.ListProducts(ctx, &ListProductsParams{CategoryIDs: []int64{shoes.ID, running.ID}})
+	require.NoError(t, err)
+	require.Len(t, page.Products, 1)
+	page, err = st.ListProducts(ctx, &ListProductsParams{CategoryIDs: []int64{shoes.ID}})
+	require.NoError(t, err)
+	require.Empty(t, page.Products)
+
+	require.ErrorIs(t, st.DeleteCategory(ctx, shoes.ID), ErrCategoryInUse)
+	require.ErrorIs(t, st.DeleteCategory(ctx, running.ID), ErrCategoryInUse)
+	require.NoError(t, st.DeleteProduct(ctx, p.ID))
+	require.NoError(t, st.DeleteCategory(ctx, running.ID))
+	require.NoError(t, st.DeleteCategory(ctx, shoes.ID))
+}
+
+func TestMemoryUsersAndSessions(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	_, err := st.CreateUser(ctx, &User{Name: "test", Email: "test@example.com"})
+	require.NoError(t, err)
+	_, err = st.CreateUser(ctx, &User{Name: "dup", Email: "test@example.com"})
+	require.Error(t, err)
+
+	_, err = st.CreateSession(ctx, &Session{ID: "sess", UserEmail: "test@example.com"})
+	require.NoError(t, err)
+	require.NoError(t, st.RevokeSession(ctx, "sess"))
+
+	s, err := st.GetSession(ctx, "sess")
+	require.NoError(t, err)
+	require.True(t, s.IsRevoked)
+
+	require.NoError(t, st.DeleteSession(ctx, "sess"))
+	_, err = st.GetSession(ctx, "sess")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestMemoryNotificationEvents(t *testing.T) {
+	ctx := context.Background()
+
+	tcs := []struct {
+		name      string
+		responses []NotificationResponseType
+		state     NotificationEventState
+		queued    int
+	}{
+		{
+			name:      "success",
+			responses: []NotificationResponseType{NotificationSuccess},
+			state:     Sent,
+		},
+		{
+			name:      "retried after failure",
+			responses: []NotificationResponseType{NotificationFailure},
+			state:     NotSent,
+			queued:    1,
+		},
+		{
+			name:      "failed after max attempts",
+			responses: []NotificationResponseType{NotificationFailure, NotificationFailure, NotificationFailure},
+			state:     Failed,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			st := NewMemoryStorer()
+			ev, err := st.EnqueueNotificatioEvent(ctx, &NotificationEvent{UserEmail: "test@example.com", OrderStatus: Pending, OrderID: 1})
+			require.NoError(t, err)
+
+			for _, rt := range tc.responses {
+				_, err := st.UpdateNotificationEvent(ctx, ev, &NotificationState{Message: "test"}, rt)
+				require.NoError(t, err)
+			}
+
+			events, err := st.ListNotificationEvents(ctx)
+			require.NoError(t, err)
+			require.Len(t, events, tc.queued)
+			require.Equal(t, tc.state, st.notificationStates[ev.StateID].State)
+		})
+	}
+}
+
+func TestMemoryNotificationOutbox(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	u, err := st.CreateUser(ctx, &User{Name: "test", Email: "test@example.com"})
+	require.NoError(t, err)
+	p := newTestProduct()
+	p.CountInStock = 1
+	p, err = st.CreateProduct(ctx, p)
+	require.NoError(t, err)
+
+	o, err := st.CreateOrder(ctx, &Order{UserID: u.ID, Items: []OrderItem{{ProductID: p.ID, Quantity: 1}}}, &NotificationEvent{UserEmail: u.Email})
+	require.NoError(t, err)
+
+	// a failed order must not leave a notification behind
+	_, err = st.CreateOrder(ctx, &Order{UserID: u.ID, Items: []OrderItem{{ProductID: p.ID, Quantity: 1}}}, &NotificationEvent{UserEmail: u.Email})
+	require.ErrorIs(t, err, ErrInsufficientStock)
+
+	_, err = st.UpdateOrderStatus(ctx, &Order{ID: o.ID, Status: Paid}, &OrderStatusChange{FromStatus: Pending}, &NotificationEvent{UserEmail: u.Email})
+	require.NoError(t, err)
+
+	claimed, err := st.ClaimNotificationEvents(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	require.Equal(t, Pending, claimed[0].OrderStatus)
+	require.Equal(t, Paid, claimed[1].OrderStatus)
+	require.Equal(t, o.ID, claimed[1].OrderID)
+
+	// leased events are not handed out twice
+	again, err := st.ClaimNotificationEvents(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Empty(t, again)
+
+	_, err = st.UpdateNotificationEvent(ctx, claimed[0], &NotificationState{Message: "sent"}, NotificationSuccess)
+	require.NoError(t, err)
+
+	// an expired lease makes the unacknowledged event due again
+	st.notificationEvents[claimed[1].ID].LockedUntil = nil
+	again, err = st.ClaimNotificationEvents(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, again, 1)
+	require.Equal(t, claimed[1].ID, again[0].ID)
+}
+
+func TestMemoryConcurrentCreates(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := st.CreateProduct(ctx, newTestProduct())
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	page, err := st.ListProducts(ctx, &ListProductsParams{Limit: MaxPageSize})
+	require.NoError(t, err)
+	require.Len(t, page.Products, 50)
+	for i, p := range page.Products {
+		require.Equal(t, int64(i+1), p.ID)
+	}
+}
+
+func TestMemoryPromotions(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	u, err := st.CreateUser(ctx, &User{Name: "test", Email: "test@example.com"})
+	require.NoError(t, err)
+	p, err := st.CreateProduct(ctx, newTestProduct())
+	require.NoError(t, err)
+
+	code := "SAVE10"
+	coupon, err := st.CreatePromotion(ctx, &Promotion{Name: "save 10%", Code: &code, Type: PercentageOff, DiscountRate: 1000, PerCustomerLimit: 1, Active: true})
+	require.NoError(t, err)
+	_, err = st.CreatePromotion(ctx, &Promotion{Name: "duplicate", Code: &code, Type: PercentageOff, DiscountRate: 500})
+	require.ErrorIs(t, err, ErrPromotionExists)
+
+	past := time.Now().Add(-time.Hour)
+	auto, err := st.CreatePromotion(ctx, &Promotion{Name: "free shipping", Type: FreeShipping, Active: true})
+	require.NoError(t, err)
+	_, err = st.CreatePromotion(ctx, &Promotion{Name: "expired", Type: FreeShipping, Active: true, EndsAt: &past})
+	require.NoError(t, err)
+	_, err = st.CreatePromotion(ctx, &Promotion{Name: "inactive", Type: FreeShipping})
+	require.NoError(t, err)
+
+	automatic, err := st.ListAutomaticPromotions(ctx, time.Now())
+	require.NoError(t, err)
+	require.Len(t, automatic, 1)
+	require.Equal(t, auto.ID, automatic[0].ID)
+
+	gp, err := st.GetPromotionByCode(ctx, code)
+	require.NoError(t, err)
+	require.Equal(t, coupon.ID, gp.ID)
+
+	order := func() *Order {
+		return &Order{
+			UserID:    u.ID,
+			Items:     []OrderItem{{ProductID: p.ID, Quantity: 1, Price: p.Price}},
+			Discounts: OrderDiscounts{{PromotionID: coupon.ID, Name: coupon.Name, Code: code, Amount: p.Price.MulRate(1000)}},
+		}
+	}
+	o, err := st.CreateOrder(ctx, order(), nil)
+	require.NoError(t, err)
+
+	used, err := st.CountUserRedemptions(ctx, u.ID, []int64{coupon.ID, auto.ID})
+	require.NoError(t, err)
+	require.Equal(t, map[int64]int64{coupon.ID: 1}, used)
+	gp, err = st.GetPromotion(ctx, coupon.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), gp.TimesUsed)
+
+	// the per-customer limit is enforced when the order is stored, without
+	// touching stock
+	_, err = st.CreateOrder(ctx, order(), nil)
+	var limitErr *PromotionLimitError
+	require.ErrorAs(t, err, &limitErr)
+	require.True(t, limitErr.PerCustomer)
+	require.ErrorIs(t, err, ErrPromotionLimitReached)
+	gprod, err := st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, p.CountInStock-1, gprod.CountInStock)
+
+	got, err := st.GetOrder(ctx, o.ID)
+	require.NoError(t, err)
+	require.Equal(t, OrderDiscounts{{PromotionID: coupon.ID, Name: coupon.Name, Code: code, Amount: p.Price.MulRate(1000)}}, got.Discounts)
+
+	require.ErrorIs(t, st.DeletePromotion(ctx, coupon.ID), ErrPromotionInUse)
+	require.NoError(t, st.DeleteOrder(ctx, o.ID))
+	require.NoError(t, st.DeletePromotion(ctx, coupon.ID))
+	_, err = st.GetPromotion(ctx, coupon.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestMemoryScheduledPrices(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	u, err := st.CreateUser(ctx, &User{Name: "test", Email: "test@example.com"})
+	require.NoError(t, err)
+	p, err := st.CreateProduct(ctx, newTestProduct())
+	require.NoError(t, err)
+
+	now := time.Now()
+	limit := int64(3)
+	sale, err := st.CreateScheduledPrice(ctx, &ScheduledPrice{ProductID: p.ID, Price: money.New(5000, money.DefaultCurrency), StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), QuantityCap: &limit})
+	require.NoError(t, err)
+	_, err = st.CreateScheduledPrice(ctx, &ScheduledPrice{ProductID: p.ID, Price: money.New(8000, money.DefaultCurrency), StartsAt: now.Add(time.Hour), EndsAt: now.Add(2 * time.Hour)})
+	require.NoError(t, err)
+
+	running, err := st.ListRunningScheduledPrices(ctx, []int64{p.ID}, now)
+	require.NoError(t, err)
+	require.Len(t, running, 1)
+	require.Equal(t, sale.ID, running[0].ID)
+
+	order := func(quantity int64) *Order {
+		return &Order{
+			UserID: u.ID,
+			Items:  []OrderItem{{ProductID: p.ID, Quantity: quantity, Price: sale.Price, ScheduledPriceID: &sale.ID}},
+		}
+	}
+	o, err := st.CreateOrder(ctx, order(2), nil)
+	require.NoError(t, err)
+
+	// of concurrent orders for the last unit, exactly one gets it
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var placed, soldOut int
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := st.CreateOrder(ctx, order(1), nil)
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				placed++
+			} else if errors.Is(err, ErrScheduledPriceSoldOut) {
+				soldOut++
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 1, placed)
+	require.Equal(t, 4, soldOut)
+
+	running, err = st.ListRunningScheduledPrices(ctx, []int64{p.ID}, now)
+	require.NoError(t, err)
+	require.Empty(t, running)
+
+	// cancelling gives the units back to the sale
+	_, err = st.UpdateOrderStatus(ctx, &Order{ID: o.ID, Status: Cancelled}, &OrderStatusChange{FromStatus: Pending}, nil)
+	require.NoError(t, err)
+	gs, err := st.GetScheduledPrice(ctx, sale.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), gs.QuantitySold)
+
+	// orders quoted before the price changed are not sold at it
+	quoted := order(1)
+	gs.Price = money.New(6000, money.DefaultCurrency)
+	_, err = st.UpdateScheduledPrice(ctx, gs)
+	require.NoError(t, err)
+	_, err = st.CreateOrder(ctx, quoted, nil)
+	var soldOutErr *ScheduledPriceSoldOutError
+	require.ErrorAs(t, err, &soldOutErr)
+	require.True(t, soldOutErr.Changed)
+
+	prices, err := st.ListScheduledPrices(ctx, p.ID)
+	require.NoError(t, err)
+	require.Len(t, prices, 2)
+	require.Equal(t, sale.ID, prices[0].ID)
+
+	require.NoError(t, st.DeleteScheduledPrice(ctx, sale.ID))
+	got, err := st.GetOrder(ctx, o.ID)
+	require.NoError(t, err)
+	require.Nil(t, got.Items[0].ScheduledPriceID)
+}
+
+func TestMemoryTaxRates(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	rates := []*TaxRate{
+		{Country: "US", Region: "CA", TaxClass: DefaultTaxClass, Name: "California", Rate: 725},
+		{Country: "US", TaxClass: DefaultTaxClass, Name: "US", Rate: 500},
+		{Country: "US", Region: "NY", TaxClass: DefaultTaxClass, Name: "New York", Rate: 400},
+		{Country: "DE", TaxClass: DefaultTaxClass, Name: "VAT", Rate: 1900, Inclusive: true},
+		{TaxClass: "food", Name: "food", Rate: 0},
+	}
+	for _, r := range rates {
+		_, err := st.CreateTaxRate(ctx, r)
+		require.NoError(t, err)
+	}
+	_, err := st.CreateTaxRate(ctx, &TaxRate{Country: "US", Region: "CA", TaxClass: DefaultTaxClass, Name: "duplicate", Rate: 800})
+	require.ErrorIs(t, err, ErrTaxRateExists)
+
+	applicable, err := st.ListApplicableTaxRates(ctx, "US", "CA")
+	require.NoError(t, err)
+	var names []string
+	for _, r := range applicable {
+		names = append(names, r.Name)
+	}
+	require.Equal(t, []string{"food", "US", "California"}, names)
+
+	ny := rates[2]
+	ny.Region = "CA"
+	_, err = st.UpdateTaxRate(ctx, ny)
+	require.ErrorIs(t, err, ErrTaxRateExists)
+
+	require.NoError(t, st.DeleteTaxRate(ctx, rates[0].ID))
+	_, err = st.GetTaxRate(ctx, rates[0].ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	u, err := st.CreateUser(ctx, &User{Name: "test", Email: "test@example.com"})
+	require.NoError(t, err)
+	require.NoError(t, st.SetUserTaxExempt(ctx, u.ID, true))
+	_, err = st.UpdateUser(ctx, &User{ID: u.ID, Name: "renamed", Email: u.Email})
+	require.NoError(t, err)
+	gu, err := st.GetUserByID(ctx, u.ID)
+	require.NoError(t, err)
+	require.True(t, gu.TaxExempt)
+	require.ErrorIs(t, st.SetUserTaxExempt(ctx, u.ID+1, true), sql.ErrNoRows)
+}
+
+func TestMemoryAddresses(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	u, err := st.CreateUser(ctx, &User{Name: "test", Email: "test@example.com"})
+	require.NoError(t, err)
+
+	home, err := st.CreateAddress(ctx, &UserAddress{UserID: u.ID, Label: "home", Country: "US", IsDefault: true})
+	require.NoError(t, err)
+	work, err := st.CreateAddress(ctx, &UserAddress{UserID: u.ID, Label: "work", Country: "US"})
+	require.NoError(t, err)
+	_, err = st.CreateAddress(ctx, &UserAddress{UserID: u.ID + 1, Label: "other", Country: "US", IsDefault: true})
+	require.NoError(t, err)
+
+	addresses, err := st.ListUserAddresses(ctx, u.ID)
+	require.NoError(t, err)
+	require.Len(t, addresses, 2)
+	require.Equal(t, home.ID, addresses[0].ID)
+
+	// making another address the default clears the old one
+	work.IsDefault = true
+	_, err = st.UpdateAddress(ctx, work)
+	require.NoError(t, err)
+	addresses, err = st.ListUserAddresses(ctx, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, work.ID, addresses[0].ID)
+	require.False(t, addresses[1].IsDefault)
+
+	require.NoError(t, st.DeleteUser(ctx, u.ID))
+	_, err = st.GetAddress(ctx, home.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestMemoryShippingRates(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	us, err := st.CreateShippingZone(ctx, &ShippingZone{Name: "US", Countries: StringList{"US"}})
+	require.NoError(t, err)
+	rest, err := st.CreateShippingZone(ctx, &ShippingZone{Name: "rest of world"})
+	require.NoError(t, err)
+
+	freeAbove := money.New(10000, money.DefaultCurrency)
+	methods := []*ShippingMethod{
+		{ZoneID: rest.ID, Name: "International", Basis: BySubtotal, Tiers: ShippingTiers{{Price: money.New(3000, money.DefaultCurrency)}}, Active: true},
+		{ZoneID: us.ID, Name: "Standard", Basis: BySubtotal, Tiers: ShippingTiers{{Price: money.New(800, money.DefaultCurrency)}}, FreeAbove: &freeAbove, Active: true},
+	}
+	for _, m := range methods {
+		_, err := st.CreateShippingMethod(ctx, m)
+		require.NoError(t, err)
+	}
+	_, err = st.CreateShippingMethod(ctx, &ShippingMethod{ZoneID: 42, Name: "nowhere"})
+	require.Error(t, err)
+
+	listed, err := st.ListShippingMethods(ctx, []int64{us.ID, rest.ID})
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+	require.Equal(t, "Standard", listed[0].Name)
+	require.Equal(t, freeAbove, *listed[0].FreeAbove)
+
+	// methods returned are copies
+	listed[0].Tiers[0].Price = money.New(0, money.DefaultCurrency)
+	got, err := st.GetShippingMethod(ctx, methods[1].ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(800), got.Tiers[0].Price.Amount)
+
+	require.NoError(t, st.DeleteShippingZone(ctx, us.ID))
+	_, err = st.GetShippingMethod(ctx, methods[1].ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	zones, err := st.ListShippingZones(ctx)
+	require.NoError(t, err)
+	require.Len(t, zones, 1)
+}
+
+func TestMemoryPayments(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	u, err := st.CreateUser(ctx, &User{Name: "test", Email: "test@example.com"})
+	require.NoError(t, err)
+	pr, err := st.CreateProduct(ctx, newTestProduct())
+	require.NoError(t, err)
+	o, err := st.CreateOrder(ctx, &Order{UserID: u.ID, Items: []OrderItem{{ProductID: pr.ID, Quantity: 1}}}, nil)
+	require.NoError(t, err)
+
+	_, err = st.CreatePayment(ctx, &Payment{OrderID: 42, Provider: "fake", Status: payments.StatusPending})
+	require.Error(t, err)
+
+	amount := money.New(2500, money.DefaultCurrency)
+	p, err := st.CreatePayment(ctx, &Payment{OrderID: o.ID, Provider: "fake", Status: payments.StatusPending, Amount: amount})
+	require.NoError(t, err)
+
+	// only one active payment per order
+	_, err = st.CreatePayment(ctx, &Payment{OrderID: o.ID, Provider: "fake", Status: payments.StatusPending, Amount: amount})
+	require.ErrorIs(t, err, ErrPaymentInProgress)
+
+	p.Reference = "fake_1"
+	p.Status = payments.StatusDeclined
+	_, err = st.UpdatePayment(ctx, p)
+	require.NoError(t, err)
+
+	got, err := st.GetPaymentByReference(ctx, "fake", "fake_1")
+	require.NoError(t, err)
+	require.Equal(t, payments.StatusDeclined, got.Status)
+	require.Equal(t, amount, got.Amount)
+	_, err = st.GetPaymentByReference(ctx, "other", "fake_1")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = st.CreatePayment(ctx, &Payment{OrderID: o.ID, Provider: "fake", Status: payments.StatusPending, Amount: amount, RefundedAmount: money.New(0, money.DefaultCurrency)})
+	require.NoError(t, err)
+	listed, err := st.ListOrderPayments(ctx, o.ID)
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+	require.Equal(t, p.ID, listed[0].ID)
+
+	// a payment is refunded for a key once, and not from a stale read
+	captured := listed[1]
+	captured.Status = payments.StatusCaptured
+	_, err = st.UpdatePayment(ctx, captured)
+	require.NoError(t, err)
+	stale := *captured
+	half := money.New(1250, money.DefaultCurrency)
+	_, err = st.RefundPayment(ctx, captured, &PaymentRefund{Key: "return 1", Amount: half, StoreCredit: &StoreCreditTransaction{UserID: u.ID, Kind: BalanceRefund, Amount: half}})
+	require.NoError(t, err)
+	require.Equal(t, half, captured.RefundedAmount)
+	credit, err := st.GetStoreCredit(ctx, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, half, credit)
+	_, err = st.RefundPayment(ctx, captured, &PaymentRefund{Key: "return 1", Amount: half})
+	require.ErrorIs(t, err, ErrRefundApplied)
+	_, err = st.RefundPayment(ctx, &stale, &PaymentRefund{Key: "order 1", Amount: half})
+	require.ErrorIs(t, err, ErrPaymentChanged)
+
+	// refunds the provider carries out stay pending until it did
+	r := &PaymentRefund{Key: "order 1", Amount: half}
+	_, err = st.RefundPayment(ctx, captured, r)
+	require.NoError(t, err)
+	require.Equal(t, payments.StatusRefunded, captured.Status)
+	refunds, err := st.ListOrderPaymentRefunds(ctx, o.ID)
+	require.NoError(t, err)
+	require.Len(t, refunds, 2)
+	require.Equal(t, "return 1", refunds[0].Key)
+	require.Equal(t, RefundApplied, refunds[0].Status)
+	require.Equal(t, captured.ID, refunds[1].PaymentID)
+	require.Equal(t, RefundPending, refunds[1].Status)
+	require.NoError(t, st.ApplyPaymentRefund(ctx, r.ID))
+	refunds, err = st.ListOrderPaymentRefunds(ctx, o.ID)
+	require.NoError(t, err)
+	require.Equal(t, RefundApplied, refunds[1].Status)
+	require.ErrorIs(t, st.ApplyPaymentRefund(ctx, 42), sql.ErrNoRows)
+
+	// orders that took money keep their payments
+	require.ErrorIs(t, st.DeleteOrder(ctx, o.ID), ErrOrderHasRecords)
+	_, err = st.GetPayment(ctx, captured.ID)
+	require.NoError(t, err)
+}
+
+func TestMemoryInvoices(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	first, err := st.CreateInvoice(ctx, &Invoice{OrderID: 1, Items: InvoiceLines{{Description: "test product", Quantity: 1}}})
+	require.NoError(t, err)
+	require.Equal(t, "INV-000001", first.Number)
+
+	_, err = st.CreateInvoice(ctx, &Invoice{OrderID: 1})
+	require.ErrorIs(t, err, ErrInvoiceExists)
+
+	second, err := st.CreateInvoice(ctx, &Invoice{OrderID: 2})
+	require.NoError(t, err)
+	require.Equal(t, "INV-000002", second.Number, "numbers have no gaps")
+
+	// invoices returned are copies
+	got, err := st.GetOrderInvoice(ctx, 1)
+	require.NoError(t, err)
+	got.Items[0].Quantity = 5
+	got, err = st.GetOrderInvoice(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), got.Items[0].Quantity)
+
+	_, err = st.GetOrderInvoice(ctx, 3)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = st.CreateCreditNote(ctx, &CreditNote{InvoiceID: 42})
+	require.Error(t, err)
+	cn, err := st.CreateCreditNote(ctx, &CreditNote{InvoiceID: first.ID, Reason: "damaged"})
+	require.NoError(t, err)
+	require.Equal(t, "CN-000001", cn.Number)
+
+	notes, err := st.ListInvoiceCreditNotes(ctx, first.ID)
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+	require.Equal(t, "damaged", notes[0].Reason)
+}
+
+func TestMemoryReturns(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	u, err := st.CreateUser(ctx, &User{Name: "test", Email: "test@example.com"})
+	require.NoError(t, err)
+	pr, err := st.CreateProduct(ctx, newTestProduct())
+	require.NoError(t, err)
+	o, err := st.CreateOrder(ctx, &Order{UserID: u.ID, Items: []OrderItem{{ProductID: pr.ID, Quantity: 3}}}, nil)
+	require.NoError(t, err)
+	itemID := o.Items[0].ID
+
+	_, err = st.CreateReturn(ctx, &Return{OrderID: o.ID, OrderItemID: 42, Quantity: 1}, nil)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	ne := &NotificationEvent{UserEmail: u.Email, OrderStatus: Delivered}
+	r, err := st.CreateReturn(ctx, &Return{OrderID: o.ID, OrderItemID: itemID, UserID: u.ID, Quantity: 2, Status: ReturnRequested}, ne)
+	require.NoError(t, err)
+	require.Equal(t, r.ID, *ne.ReturnID)
+	require.Equal(t, ReturnRequested, ne.ReturnStatus)
+
+	_, err = st.CreateReturn(ctx, &Return{OrderID: o.ID, OrderItemID: itemID, Quantity: 2, Status: ReturnRequested}, nil)
+	require.ErrorIs(t, err, ErrReturnQuantityExceeded)
+
+	// rejected returns give their units back
+	r.Status = ReturnRejected
+	_, err = st.UpdateReturnStatus(ctx, r, ReturnRequested, nil)
+	require.NoError(t, err)
+	_, err = st.UpdateReturnStatus(ctx, r, ReturnRequested, nil)
+	require.ErrorIs(t, err, ErrReturnStatusChanged)
+	second, err := st.CreateReturn(ctx, &Return{OrderID: o.ID, OrderItemID: itemID, Quantity: 3, Status: ReturnRequested}, nil)
+	require.NoError(t, err)
+
+	second.Status = ReturnReceived
+	second.Restocked = true
+	_, err = st.UpdateReturnStatus(ctx, second, ReturnRequested, nil)
+	require.NoError(t, err)
+	p, err := st.GetProduct(ctx, pr.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), p.CountInStock)
+
+	rejected, err := st.ListReturns(ctx, ReturnRejected)
+	require.NoError(t, err)
+	require.Len(t, rejected, 1)
+	require.Equal(t, r.ID, rejected[0].ID)
+	listed, err := st.ListOrderReturns(ctx, o.ID)
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+
+	require.ErrorIs(t, st.DeleteOrder(ctx, o.ID), ErrOrderHasRecords)
+	_, err = st.GetReturn(ctx, r.ID)
+	require.NoError(t, err)
+}
+
+func TestMemoryWishlists(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	u, err := st.CreateUser(ctx, &User{Name: "test", Email: "test@example.com"})
+	require.NoError(t, err)
+	p1, err := st.CreateProduct(ctx, newTestProduct())
+	require.NoError(t, err)
+	p2, err := st.CreateProduct(ctx, newTestProduct())
+	require.NoError(t, err)
+
+	w, err := st.CreateWishlist(ctx, &Wishlist{UserID: u.ID, Name: "birthday"})
+	require.NoError(t, err)
+	_, err = st.CreateWishlist(ctx, &Wishlist{UserID: u.ID, Name: "birthday"})
+	require.ErrorIs(t, err, ErrWishlistExists)
+	later, err := st.CreateWishlist(ctx, &Wishlist{UserID: u.ID, Name: "later"})
+	require.NoError(t, err)
+
+	require.NoError(t, st.AddWishlistItem(ctx, w.ID, p1.ID, 0))
+	require.NoError(t, st.AddWishlistItem(ctx, w.ID, p2.ID, 0))
+	require.NoError(t, st.AddWishlistItem(ctx, w.ID, p1.ID, 0))
+	require.Error(t, st.AddWishlistItem(ctx, w.ID, 42, 0))
+
+	token := "0123456789abcdef0123456789abcdef"
+	w.ShareToken = &token
+	_, err = st.UpdateWishlist(ctx, w)
+	require.NoError(t, err)
+	later.Name = "birthday"
+	_, err = st.UpdateWishlist(ctx, later)
+	require.ErrorIs(t, err, ErrWishlistExists)
+
+	shared, err := st.GetWishlistByShareToken(ctx, token)
+	require.NoError(t, err)
+	require.Equal(t, w.ID, shared.ID)
+	require.Len(t, shared.Items, 2)
+	require.Equal(t, p1.ID, shared.Items[0].ProductID)
+
+	require.NoError(t, st.RemoveWishlistItem(ctx, w.ID, p1.ID, 0))
+	require.ErrorIs(t, st.RemoveWishlistItem(ctx, w.ID, p1.ID, 0), sql.ErrNoRows)
+
+	// deleting a product drops it from wishlists
+	require.NoError(t, st.DeleteProduct(ctx, p2.ID))
+	ws, err := st.ListUserWishlists(ctx, u.ID)
+	require.NoError(t, err)
+	require.Len(t, ws, 2)
+	require.Equal(t, "birthday", ws[0].Name)
+	require.Empty(t, ws[0].Items)
+
+	require.NoError(t, st.DeleteWishlist(ctx, w.ID))
+	_, err = st.GetWishlistByShareToken(ctx, token)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestMemoryGiftCards(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+	usd := func(cents int64) money.Money { return money.New(cents, money.DefaultCurrency) }
+
+	u, err := st.CreateUser(ctx, &User{Name: "test", Email: "test@example.com"})
+	require.NoError(t, err)
+	pr, err := st.CreateProduct(ctx, newTestProduct())
+	require.NoError(t, err)
+
+	g, err := st.CreateGiftCard(ctx, &GiftCard{Code: "ABCD-EFGH", InitialAmount: usd(5000)})
+	require.NoError(t, err)
+	require.Equal(t, usd(5000), g.Balance)
+	_, err = st.CreateGiftCard(ctx, &GiftCard{Code: "ABCD-EFGH", InitialAmount: usd(1000)})
+	require.ErrorIs(t, err, ErrGiftCardExists)
+
+	require.NoError(t, st.RecordStoreCreditTransaction(ctx, &StoreCreditTransaction{UserID: u.ID, Kind: BalanceAdjustment, Amount: usd(3000), Reason: "goodwill"}))
+
+	// tenders are redeemed all or none
+	tenders := func(card, credit int64) []*Payment {
+		return []*Payment{
+			{Provider: GiftCardProvider, Reference: "ABCD-EFGH", Amount: usd(card)},
+			{Provider: StoreCreditProvider, Amount: usd(credit)},
+		}
+	}
+	order := func(ts []*Payment) *Order {
+		return &Order{UserID: u.ID, Items: []OrderItem{{ProductID: pr.ID, Quantity: 1}}, TotalPrice: usd(10000), Tenders: ts}
+	}
+	_, err = st.CreateOrder(ctx, order(tenders(4000, 3500)), nil)
+	require.ErrorIs(t, err, ErrInsufficientBalance)
+	got, err := st.GetGiftCardByCode(ctx, "ABCD-EFGH")
+	require.NoError(t, err)
+	require.Equal(t, usd(5000), got.Balance)
+
+	o, err := st.CreateOrder(ctx, order(tenders(4000, 2000)), nil)
+	require.NoError(t, err)
+	got, err = st.GetGiftCard(ctx, g.ID)
+	require.NoError(t, err)
+	require.Equal(t, usd(1000), got.Balance)
+	credit, err := st.GetStoreCredit(ctx, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, usd(1000), credit)
+
+	ps, err := st.ListOrderPayments(ctx, o.ID)
+	require.NoError(t, err)
+	require.Len(t, ps, 2)
+	require.Equal(t, payments.StatusCaptured, ps[0].Status)
+
+	// tenders do not keep the order from being paid for
+	_, err = st.CreatePayment(ctx, &Payment{OrderID: o.ID, Provider: "fake", Status: payments.StatusPending, Amount: usd(4000)})
+	require.NoError(t, err)
+
+	err = st.RecordGiftCardTransaction(ctx, &GiftCardTransaction{GiftCardID: g.ID, Kind: BalanceAdjustment, Amount: usd(-1500), Reason: "correction"})
+	require.ErrorIs(t, err, ErrInsufficientBalance)
+
+	txs, err := st.ListGiftCardTransactions(ctx, g.ID)
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+	require.Equal(t, BalanceIssue, txs[0].Kind)
+	require.Equal(t, BalanceRedeem, txs[1].Kind)
+	require.Equal(t, o.ID, *txs[1].OrderID)
+
+	require.ErrorIs(t, st.DeleteOrder(ctx, o.ID), ErrOrderHasRecords)
+
+	// the cards bought are issued along with the payment, or not at all when
+	// one of their codes is taken
+	bought := func(code string) *GiftCard {
+		return &GiftCard{Code: code, InitialAmount: usd(2500), PurchaserID: &u.ID, OrderID: &o.ID}
+	}
+	paid := func(cards ...*GiftCard) error {
+		_, err := st.UpdateOrderStatus(ctx, &Order{ID: o.ID, Status: Paid}, &OrderStatusChange{FromStatus: Pending, ActorRole: ActorSystem, GiftCards: cards}, nil)
+		return err
+	}
+	require.ErrorIs(t, paid(bought("WXYZ-0001"), bought("ABCD-EFGH")), ErrGiftCardExists)
+	require.ErrorIs(t, paid(bought("WXYZ-0001"), bought("WXYZ-0001")), ErrGiftCardExists)
+	current, err := st.GetOrder(ctx, o.ID)
+	require.NoError(t, err)
+	require.Equal(t, Pending, current.Status)
+	cards, err := st.ListOrderGiftCards(ctx, o.ID)
+	require.NoError(t, err)
+	require.Empty(t, cards)
+
+	require.NoError(t, paid(bought("WXYZ-0001"), bought("WXYZ-0002")))
+	cards, err = st.ListOrderGiftCards(ctx, o.ID)
+	require.NoError(t, err)
+	require.Len(t, cards, 2)
+	require.Equal(t, usd(2500), cards[1].Balance)
+
+	// deleting a user deletes their store credit
+	other, err := st.CreateUser(ctx, &User{Name: "other", Email: "other@example.com"})
+	require.NoError(t, err)
+	require.NoError(t, st.RecordStoreCreditTransaction(ctx, &StoreCreditTransaction{UserID: other.ID, Kind: BalanceAdjustment, Amount: usd(3000), Reason: "goodwill"}))
+	require.NoError(t, st.DeleteUser(ctx, other.ID))
+	credit, err = st.GetStoreCredit(ctx, other.ID)
+	require.NoError(t, err)
+	require.True(t, credit.IsZero())
+	scTxs, err := st.ListStoreCreditTransactions(ctx, other.ID)
+	require.NoError(t, err)
+	require.Empty(t, scTxs)
+}
+
+func TestMemoryLoyaltyPoints(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+	usd := func(cents int64) money.Money { return money.New(cents, money.DefaultCurrency) }
+	year := 365 * 24 * time.Hour
+	earn := func(userID, points int64, expiresAt time.Time) *LoyaltyTransaction {
+		return &LoyaltyTransaction{UserID: userID, Kind: BalanceEarn, Points: points, Spend: usd(points * 100), ExpiresAt: &expiresAt}
+	}
+
+	u, err := st.CreateUser(ctx, &User{Name: "test", Email: "test@example.com"})
+	require.NoError(t, err)
+	pr, err := st.CreateProduct(ctx, newTestProduct())
+	require.NoError(t, err)
+
+	// points are spent oldest first, so the 30 spent come off the points
+	// that lapse, and only the 70 left of them expire
+	require.NoError(t, st.RecordLoyaltyTransaction(ctx, earn(u.ID, 100, time.Now().Add(time.Hour))))
+	require.NoError(t, st.RecordLoyaltyTransaction(ctx, earn(u.ID, 50, time.Now().Add(2*time.Hour))))
+	require.NoError(t, st.RecordLoyaltyTransaction(ctx, &LoyaltyTransaction{UserID: u.ID, Kind: BalanceRedeem, Points: -30, Spend: usd(0)}))
+	lapsed := time.Now().Add(-time.Hour)
+	st.loyaltyTxs[1].ExpiresAt = &lapsed
+	st.loyaltyTxs[1].CreatedAt = time.Now().Add(-2 * year)
+	require.NoError(t, st.ExpireLoyaltyPoints(ctx, u.ID))
+
+	points, err := st.GetLoyaltyBalance(ctx, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(50), points)
+	txs, err := st.ListLoyaltyTransactions(ctx, u.ID)
+	require.NoError(t, err)
+	require.Len(t, txs, 4)
+	require.Equal(t, BalanceExpire, txs[3].Kind)
+	require.Equal(t, int64(-70), txs[3].Points)
+
+	spend, err := st.GetLoyaltySpend(ctx, u.ID, time.Now().Add(-year))
+	require.NoError(t, err)
+	require.Equal(t, usd(5000), spend)
+
+	// an order redeems points as a tender, all or none
+	order := func(points int64) *Order {
+		return &Order{UserID: u.ID, Items: []OrderItem{{ProductID: pr.ID, Quantity: 1}}, TotalPrice: usd(10000), Tenders: []*Payment{
+			{Provider: LoyaltyPointsProvider, Reference: strconv.FormatInt(points, 10), Amount: usd(points)},
+		}}
+	}
+	_, err = st.CreateOrder(ctx, order(60), nil)
+	require.ErrorIs(t, err, ErrInsufficientBalance)
+	o, err := st.CreateOrder(ctx, order(40), nil)
+	require.NoError(t, err)
+
+	points, err = st.GetLoyaltyBalance(ctx, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), points)
+	ps, err := st.ListOrderPayments(ctx, o.ID)
+	require.NoError(t, err)
+	require.Len(t, ps, 1)
+	require.Equal(t, int64(40), ps[0].LoyaltyPoints())
+	require.True(t, ps[0].IsTender())
+
+	// reversals may take the balance below zero
+	require.NoError(t, st.RecordLoyaltyTransaction(ctx, &LoyaltyTransaction{UserID: u.ID, Kind: BalanceReverse, Points: -50, Spend: usd(-5000), OrderID: &o.ID}))
+	points, err = st.GetLoyaltyBalance(ctx, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(-40), points)
+	err = st.RecordLoyaltyTransaction(ctx, &LoyaltyTransaction{UserID: u.ID, Kind: BalanceRedeem, Points: -1, Spend: usd(0)})
+	require.ErrorIs(t, err, ErrInsufficientBalance)
+}
@@ -2,9 +2,15 @@ package storer
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"github.com/OrkhanMehbaliyev/ecom-golang/payments"
+	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -21,210 +27,3111 @@ func NewMySQLStorer(db *sqlx.DB) *MySQLStorer {
 // Constants
 const (
 	maxAttempts = 3
+	// MySQL error numbers of a unique key violation and of deleting a row
+	// that a foreign key still references
+	mysqlErrDupEntry        = 1062
+	mysqlErrRowIsReferenced = 1451
 )
 
+func isMySQLError(err error, number uint16) bool {
+	var me *mysql.MySQLError
+	return errors.As(err, &me) && me.Number == number
+}
+
+// retryBackoff is how long a failed notification waits before it is handed
+// out again; it doubles with every attempt.
+func retryBackoff(attempts int64) time.Duration {
+	return time.Duration(1<<attempts) * time.Minute
+}
+
+// CreateProduct inserts a product and receives its stock into the main
+// warehouse.
 func (ms *MySQLStorer) CreateProduct(ctx context.Context, p *Product) (*Product, error) {
-	res, err := ms.db.NamedExecContext(ctx, "INSERT INTO products (name, image, category, description, rating, num_reviews, price, count_in_stock) VALUES (:name, :image, :category, :description, :rating, :num_reviews, :price, :count_in_stock)", p)
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		res, err := tx.NamedExecContext(ctx, "INSERT INTO products (name, image, category_id, description, options, tax_class, weight, gift_card, rating, num_reviews, price, count_in_stock) VALUES (:name, :image, :category_id, :description, :options, :tax_class, :weight, :gift_card, :rating, :num_reviews, :price, :count_in_stock)", p)
+		if err != nil {
+			return fmt.Errorf("error inserting product: %w", err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("error getting last inserted ID: %w", err)
+		}
+		p.ID = id
+
+		return receiveOpeningStock(ctx, tx, p.ID, 0, p.CountInStock)
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("error creating product: %w", err)
+	}
+
+	return p, nil
+}
+
+func (ms *MySQLStorer) GetProduct(ctx context.Context, id int64) (*Product, error) {
+	var p Product
+	err := ms.db.GetContext(ctx, &p, "SELECT * FROM products WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting product: %w", err)
+	}
+	return &p, nil
+}
+
+// GetProducts loads the products with the given ids. Unknown ids are
+// skipped, so callers should compare the result with what they asked for.
+func (ms *MySQLStorer) GetProducts(ctx context.Context, ids []int64) ([]*Product, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	q, args, err := sqlx.In("SELECT * FROM products WHERE id IN (?) ORDER BY id", ids)
+	if err != nil {
+		return nil, fmt.Errorf("error building products query: %w", err)
+	}
+
+	var products []*Product
+	err = ms.db.SelectContext(ctx, &products, ms.db.Rebind(q), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting products: %w", err)
+	}
+
+	return products, nil
+}
+
+func (ms *MySQLStorer) ListProducts(ctx context.Context, params *ListProductsParams) (*ProductPage, error) {
+	if err := params.normalize(); err != nil {
+		return nil, fmt.Errorf("error listing products: %w", err)
+	}
+	cursor, err := params.cursor()
+	if err != nil {
+		return nil, fmt.Errorf("error listing products: %w", err)
+	}
+
+	// prices scheduled for a variant only apply to that variant, so only the
+	// ones scheduled for the whole product change what it is listed at
+	now := time.Now()
+	args := []interface{}{now, now}
+	var where []string
+	if len(params.CategoryIDs) > 0 {
+		where = append(where, "category_id IN ("+strings.TrimSuffix(strings.Repeat("?,", len(params.CategoryIDs)), ",")+")")
+		for _, id := range params.CategoryIDs {
+			args = append(args, id)
+		}
+	}
+	if params.MinPrice != nil {
+		where = append(where, productSortColumns[SortByPrice]+">=?")
+		args = append(args, *params.MinPrice)
+	}
+	if params.MaxPrice != nil {
+		where = append(where, productSortColumns[SortByPrice]+"<=?")
+		args = append(args, *params.MaxPrice)
+	}
+	if params.MinRating != nil {
+		where = append(where, "rating>=?")
+		args = append(args, *params.MinRating)
+	}
+	if params.InStock {
+		where = append(where, "count_in_stock>0")
+	}
+
+	col := productSortColumns[params.SortBy]
+	op, dir := ">", "ASC"
+	if params.Desc {
+		op, dir = "<", "DESC"
+	}
+	if cursor != nil {
+		cp, err := cursorProduct(cursor)
+		if err != nil {
+			return nil, fmt.Errorf("error listing products: %w", err)
+		}
+		v := productSortArg(cp, params.SortBy)
+		where = append(where, fmt.Sprintf("(%s%s? OR (%s=? AND id%s?))", col, op, col, op))
+		args = append(args, v, v, cp.ID)
+	}
+
+	q := "SELECT products.*, " + productSortColumns[SortByPrice] + " AS list_price FROM products LEFT JOIN (SELECT product_id, MIN(price) AS price FROM product_prices WHERE variant_id IS NULL AND starts_at<=? AND ends_at>? AND (quantity_cap IS NULL OR quantity_sold<quantity_cap) GROUP BY product_id) scheduled ON scheduled.product_id=products.id"
+	if len(where) > 0 {
+		q += " WHERE " + strings.Join(where, " AND ")
+	}
+	q += fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT ?", col, dir, dir)
+	// one extra row tells us whether there is a next page
+	args = append(args, params.Limit+1)
+
+	var products []*Product
+	err = ms.db.SelectContext(ctx, &products, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing products: %w", err)
+	}
+
+	return newProductPage(products, params), nil
+}
+
+// SearchProducts ranks products with the FULLTEXT index over name and
+// description. Short queries additionally get a typo tolerant
+// pass over products sharing the first letter of a term; those hits are
+// ranked after the full-text matches.
+func (ms *MySQLStorer) SearchProducts(ctx context.Context, params *SearchProductsParams) ([]*ProductSearchHit, error) {
+	terms := searchTerms(params.Query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	limit := pageSize(params.Limit)
+
+	// prefix-match every term in boolean mode so partial words still match
+	boolean := make([]string, 0, len(terms))
+	for _, t := range terms {
+		boolean = append(boolean, t+"*")
+	}
+
+	var rows []struct {
+		Product
+		Relevance float64 `db:"relevance"`
+	}
+	err := ms.db.SelectContext(ctx, &rows, "SELECT *, MATCH(name, description) AGAINST(? IN NATURAL LANGUAGE MODE) AS relevance FROM products WHERE MATCH(name, description) AGAINST(? IN BOOLEAN MODE) ORDER BY relevance DESC, id ASC LIMIT ?", params.Query, strings.Join(boolean, " "), limit)
+	if err != nil {
+		return nil, fmt.Errorf("error searching products: %w", err)
+	}
+
+	hits := make([]*ProductSearchHit, 0, len(rows))
+	seen := make(map[int64]bool, len(rows))
+	for i := range rows {
+		p := &rows[i].Product
+		seen[p.ID] = true
+		hits = append(hits, &ProductSearchHit{
+			Product:    p,
+			Score:      rows[i].Relevance,
+			Highlights: highlightProduct(p, terms, false),
+		})
+	}
+
+	if len(hits) >= limit || !isShortQuery(terms) {
+		return hits, nil
+	}
+
+	var where []string
+	var args []interface{}
+	for _, t := range terms {
+		if typoTolerance(t) == 0 {
+			continue
+		}
+		prefix := string([]rune(t)[:1]) + "%"
+		where = append(where, "name LIKE ?")
+		args = append(args, prefix)
+	}
+	if len(where) == 0 {
+		return hits, nil
+	}
+	args = append(args, fuzzyCandidateLimit)
+
+	var candidates []*Product
+	err = ms.db.SelectContext(ctx, &candidates, "SELECT * FROM products WHERE "+strings.Join(where, " OR ")+" LIMIT ?", args...)
+	if err != nil {
+		return nil, fmt.Errorf("error searching products: %w", err)
+	}
+
+	for _, hit := range rankProducts(candidates, terms, true) {
+		if len(hits) == limit {
+			break
+		}
+		if !seen[hit.Product.ID] {
+			hits = append(hits, hit)
+		}
+	}
+
+	return hits, nil
+}
+
+// UpdateProduct writes the editable product fields. Rating and num_reviews
+// are derived from reviews and count_in_stock from stock movements, so they
+// are left alone.
+func (ms *MySQLStorer) UpdateProduct(ctx context.Context, p *Product) (*Product, error) {
+	_, err := ms.db.NamedExecContext(ctx, "UPDATE products SET name=:name, image=:image, category_id=:category_id, description=:description, options=:options, tax_class=:tax_class, weight=:weight, gift_card=:gift_card, price=:price, updated_at=:updated_at WHERE id=:id", p)
+	if err != nil {
+		return nil, fmt.Errorf("error updating product: %w", err)
+	}
+
+	return p, nil
+}
+
+func (ms *MySQLStorer) DeleteProduct(ctx context.Context, id int64) error {
+	_, err := ms.db.ExecContext(ctx, "DELETE FROM products WHERE id=?", id)
+	if err != nil {
+		return fmt.Errorf("error deleting product: %w", err)
+	}
+
+	return nil
+}
+
+// CreateVariant inserts a variant, receives its stock into the main warehouse
+// and adds it to the product's. A SKU that is already taken fails with
+// ErrSKUExists.
+func (ms *MySQLStorer) CreateVariant(ctx context.Context, v *Variant) (*Variant, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		res, err := tx.NamedExecContext(ctx, "INSERT INTO product_variants (product_id, sku, options, price, image, count_in_stock) VALUES (:product_id, :sku, :options, :price, :image, :count_in_stock)", v)
+		if err != nil {
+			if isMySQLError(err, mysqlErrDupEntry) {
+				return ErrSKUExists
+			}
+			return fmt.Errorf("error inserting variant: %w", err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("error getting last inserted ID: %w", err)
+		}
+		v.ID = id
+
+		err = receiveOpeningStock(ctx, tx, v.ProductID, v.ID, v.CountInStock)
+		if err != nil {
+			return err
+		}
+
+		return updateProductStock(ctx, tx, v.ProductID)
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("error creating variant: %w", err)
+	}
+
+	return v, nil
+}
+
+func (ms *MySQLStorer) GetVariant(ctx context.Context, id int64) (*Variant, error) {
+	var v Variant
+	err := ms.db.GetContext(ctx, &v, "SELECT * FROM product_variants WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting variant: %w", err)
+	}
+
+	return &v, nil
+}
+
+// ListProductVariants returns the variants of the given products ordered by
+// product and id.
+func (ms *MySQLStorer) ListProductVariants(ctx context.Context, productIDs []int64) ([]*Variant, error) {
+	if len(productIDs) == 0 {
+		return nil, nil
+	}
+
+	q, args, err := sqlx.In("SELECT * FROM product_variants WHERE product_id IN (?) ORDER BY product_id, id", productIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error building variants query: %w", err)
+	}
+
+	var variants []*Variant
+	err = ms.db.SelectContext(ctx, &variants, ms.db.Rebind(q), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing variants: %w", err)
+	}
+
+	return variants, nil
+}
+
+// UpdateVariant writes the editable variant fields; count_in_stock is derived
+// from stock movements and left alone.
+func (ms *MySQLStorer) UpdateVariant(ctx context.Context, v *Variant) (*Variant, error) {
+	_, err := ms.db.NamedExecContext(ctx, "UPDATE product_variants SET sku=:sku, options=:options, price=:price, image=:image, updated_at=:updated_at WHERE id=:id", v)
+	if err != nil {
+		if isMySQLError(err, mysqlErrDupEntry) {
+			return nil, fmt.Errorf("error updating variant: %w", ErrSKUExists)
+		}
+		return nil, fmt.Errorf("error updating variant: %w", err)
+	}
+
+	return v, nil
+}
+
+// DeleteVariant deletes a variant together with the cart items holding it.
+// Its stock is written off in the ledger. Variants that were ordered fail
+// with ErrVariantInUse.
+func (ms *MySQLStorer) DeleteVariant(ctx context.Context, id int64) error {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		productID, err := lockVariant(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM cart_items WHERE variant_id=?", id)
+		if err != nil {
+			return fmt.Errorf("error deleting cart items: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM wishlist_items WHERE variant_id=?", id)
+		if err != nil {
+			return fmt.Errorf("error deleting wishlist items: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM product_variants WHERE id=?", id)
+		if err != nil {
+			if isMySQLError(err, mysqlErrRowIsReferenced) {
+				return ErrVariantInUse
+			}
+			return fmt.Errorf("error deleting variant: %w", err)
+		}
+
+		err = writeOffVariantStock(ctx, tx, productID, id)
+		if err != nil {
+			return err
+		}
+
+		return updateProductStock(ctx, tx, productID)
+	})
+
+	if err != nil {
+		return fmt.Errorf("error deleting variant: %w", err)
+	}
+
+	return nil
+}
+
+// lockVariant locks a variant row and returns its product id.
+func lockVariant(ctx context.Context, tx *sqlx.Tx, id int64) (int64, error) {
+	var productID int64
+	err := tx.GetContext(ctx, &productID, "SELECT product_id FROM product_variants WHERE id=? FOR UPDATE", id)
+	if err != nil {
+		return 0, fmt.Errorf("error getting variant: %w", err)
+	}
+
+	return productID, nil
+}
+
+// writeOffVariantStock records the stock a deleted variant leaves in every
+// warehouse as adjusted away and drops its stock levels.
+func writeOffVariantStock(ctx context.Context, tx *sqlx.Tx, productID, id int64) error {
+	var levels []StockLevel
+	err := tx.SelectContext(ctx, &levels, "SELECT * FROM stock_levels WHERE product_id=? AND variant_id=? AND quantity<>0 ORDER BY warehouse_id FOR UPDATE", productID, id)
+	if err != nil {
+		return fmt.Errorf("error getting stock levels: %w", err)
+	}
+
+	for _, l := range levels {
+		err = insertStockMovement(ctx, tx, &StockMovement{
+			WarehouseID: l.WarehouseID,
+			ProductID:   l.ProductID,
+			VariantID:   l.VariantID,
+			Kind:        StockAdjustment,
+			Quantity:    -l.Quantity,
+			Reason:      fmt.Sprintf("variant %d deleted", id),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM stock_levels WHERE product_id=? AND variant_id=?", productID, id)
+	if err != nil {
+		return fmt.Errorf("error deleting stock levels: %w", err)
+	}
+
+	return nil
+}
+
+// updateProductStock sets a product's count_in_stock to the sum of its
+// variants' stock.
+func updateProductStock(ctx context.Context, tx *sqlx.Tx, productID int64) error {
+	_, err := tx.ExecContext(ctx, "UPDATE products SET count_in_stock=(SELECT COALESCE(SUM(count_in_stock), 0) FROM product_variants WHERE product_id=?) WHERE id=?", productID, productID)
+	if err != nil {
+		return fmt.Errorf("error updating product stock: %w", err)
+	}
+
+	return nil
+}
+
+func (ms *MySQLStorer) CreateScheduledPrice(ctx context.Context, sp *ScheduledPrice) (*ScheduledPrice, error) {
+	res, err := ms.db.NamedExecContext(ctx, "INSERT INTO product_prices (product_id, variant_id, price, starts_at, ends_at, quantity_cap) VALUES (:product_id, :variant_id, :price, :starts_at, :ends_at, :quantity_cap)", sp)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting scheduled price: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("error getting last insert ID: %w", err)
+	}
+	sp.ID = id
+
+	return sp, nil
+}
+
+func (ms *MySQLStorer) GetScheduledPrice(ctx context.Context, id int64) (*ScheduledPrice, error) {
+	var sp ScheduledPrice
+	err := ms.db.GetContext(ctx, &sp, "SELECT * FROM product_prices WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting scheduled price: %w", err)
+	}
+
+	return &sp, nil
+}
+
+func (ms *MySQLStorer) ListScheduledPrices(ctx context.Context, productID int64) ([]*ScheduledPrice, error) {
+	var prices []*ScheduledPrice
+	err := ms.db.SelectContext(ctx, &prices, "SELECT * FROM product_prices WHERE product_id=? ORDER BY starts_at, id", productID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing scheduled prices: %w", err)
+	}
+
+	return prices, nil
+}
+
+func (ms *MySQLStorer) ListRunningScheduledPrices(ctx context.Context, productIDs []int64, t time.Time) ([]*ScheduledPrice, error) {
+	if len(productIDs) == 0 {
+		return nil, nil
+	}
+
+	q, args, err := sqlx.In("SELECT * FROM product_prices WHERE product_id IN (?) AND starts_at<=? AND ends_at>? AND (quantity_cap IS NULL OR quantity_sold<quantity_cap) ORDER BY product_id, id", productIDs, t, t)
+	if err != nil {
+		return nil, fmt.Errorf("error building scheduled prices query: %w", err)
+	}
+
+	var prices []*ScheduledPrice
+	err = ms.db.SelectContext(ctx, &prices, ms.db.Rebind(q), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing scheduled prices: %w", err)
+	}
+
+	return prices, nil
+}
+
+// UpdateScheduledPrice writes the editable fields; quantity_sold is kept by
+// orders and left alone.
+func (ms *MySQLStorer) UpdateScheduledPrice(ctx context.Context, sp *ScheduledPrice) (*ScheduledPrice, error) {
+	_, err := ms.db.NamedExecContext(ctx, "UPDATE product_prices SET variant_id=:variant_id, price=:price, starts_at=:starts_at, ends_at=:ends_at, quantity_cap=:quantity_cap, updated_at=:updated_at WHERE id=:id", sp)
+	if err != nil {
+		return nil, fmt.Errorf("error updating scheduled price: %w", err)
+	}
+
+	return sp, nil
+}
+
+// DeleteScheduledPrice deletes a scheduled price. Order lines sold at it
+// keep their price.
+func (ms *MySQLStorer) DeleteScheduledPrice(ctx context.Context, id int64) error {
+	_, err := ms.db.ExecContext(ctx, "DELETE FROM product_prices WHERE id=?", id)
+	if err != nil {
+		return fmt.Errorf("error deleting scheduled price: %w", err)
+	}
+
+	return nil
+}
+
+// CreateWarehouse inserts a warehouse. A code that is already taken fails
+// with ErrWarehouseExists.
+func (ms *MySQLStorer) CreateWarehouse(ctx context.Context, w *Warehouse) (*Warehouse, error) {
+	res, err := ms.db.NamedExecContext(ctx, "INSERT INTO warehouses (code, name) VALUES (:code, :name)", w)
+	if err != nil {
+		if isMySQLError(err, mysqlErrDupEntry) {
+			return nil, fmt.Errorf("error inserting warehouse: %w", ErrWarehouseExists)
+		}
+		return nil, fmt.Errorf("error inserting warehouse: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("error getting last inserted ID: %w", err)
+	}
+	w.ID = id
+
+	return w, nil
+}
+
+func (ms *MySQLStorer) GetWarehouse(ctx context.Context, id int64) (*Warehouse, error) {
+	var w Warehouse
+	err := ms.db.GetContext(ctx, &w, "SELECT * FROM warehouses WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting warehouse: %w", err)
+	}
+
+	return &w, nil
+}
+
+func (ms *MySQLStorer) ListWarehouses(ctx context.Context) ([]*Warehouse, error) {
+	var warehouses []*Warehouse
+	err := ms.db.SelectContext(ctx, &warehouses, "SELECT * FROM warehouses ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("error listing warehouses: %w", err)
+	}
+
+	return warehouses, nil
+}
+
+func (ms *MySQLStorer) UpdateWarehouse(ctx context.Context, w *Warehouse) (*Warehouse, error) {
+	_, err := ms.db.NamedExecContext(ctx, "UPDATE warehouses SET code=:code, name=:name, updated_at=:updated_at WHERE id=:id", w)
+	if err != nil {
+		if isMySQLError(err, mysqlErrDupEntry) {
+			return nil, fmt.Errorf("error updating warehouse: %w", ErrWarehouseExists)
+		}
+		return nil, fmt.Errorf("error updating warehouse: %w", err)
+	}
+
+	return w, nil
+}
+
+// ListStockLevels returns a product's stock levels ordered by variant and
+// warehouse.
+func (ms *MySQLStorer) ListStockLevels(ctx context.Context, productID int64) ([]*StockLevel, error) {
+	var levels []*StockLevel
+	err := ms.db.SelectContext(ctx, &levels, "SELECT * FROM stock_levels WHERE product_id=? ORDER BY variant_id, warehouse_id", productID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing stock levels: %w", err)
+	}
+
+	return levels, nil
+}
+
+// RecordStockMovements locks the products and variants moved, then the stock
+// levels taken from, in the same order as orders do.
+func (ms *MySQLStorer) RecordStockMovements(ctx context.Context, movements []*StockMovement) error {
+	if len(movements) == 0 {
+		return nil
+	}
+
+	productIDs := make(map[int64]bool)
+	variantIDs := make(map[int64]int64)
+	for _, m := range movements {
+		productIDs[m.ProductID] = true
+		if m.VariantID != 0 {
+			variantIDs[m.VariantID] = m.ProductID
+		}
+	}
+
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		products, err := lockProducts(ctx, tx, sortedKeys(productIDs))
+		if err != nil {
+			return err
+		}
+		variants, err := lockVariants(ctx, tx, sortedKeys(variantIDs))
+		if err != nil {
+			return err
+		}
+
+		names := make(map[int64]string, len(products))
+		for _, p := range products {
+			names[p.ID] = p.Name
+		}
+		skus := make(map[int64]string, len(variants))
+		for _, v := range variants {
+			if v.ProductID != variantIDs[v.ID] {
+				return fmt.Errorf("error locking variants: variant %d of product %d: %w", v.ID, variantIDs[v.ID], sql.ErrNoRows)
+			}
+			skus[v.ID] = v.SKU
+		}
+
+		net := netStockChanges(movements)
+		for _, k := range sortedStockKeys(net) {
+			if net[k] >= 0 {
+				continue
+			}
+
+			var available int64
+			err := tx.GetContext(ctx, &available, "SELECT quantity FROM stock_levels WHERE warehouse_id=? AND product_id=? AND variant_id=? FOR UPDATE", k.WarehouseID, k.ProductID, k.VariantID)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("error locking stock level: %w", err)
+			}
+			if available+net[k] < 0 {
+				return &InsufficientStockError{
+					ProductID: k.ProductID,
+					Name:      names[k.ProductID],
+					SKU:       skus[k.VariantID],
+					Requested: -net[k],
+					Available: available,
+				}
+			}
+		}
+
+		return applyStockMovements(ctx, tx, movements)
+	})
+
+	if err != nil {
+		return fmt.Errorf("error recording stock movements: %w", err)
+	}
+
+	return nil
+}
+
+func (ms *MySQLStorer) ListStockMovements(ctx context.Context, params *ListStockMovementsParams) ([]*StockMovement, error) {
+	where := []string{"product_id=?"}
+	args := []interface{}{params.ProductID}
+	if params.WarehouseID != 0 {
+		where = append(where, "warehouse_id=?")
+		args = append(args, params.WarehouseID)
+	}
+	if params.BeforeID != 0 {
+		where = append(where, "id<?")
+		args = append(args, params.BeforeID)
+	}
+
+	q := "SELECT * FROM stock_movements WHERE " + strings.Join(where, " AND ") + " ORDER BY id DESC LIMIT ?"
+	args = append(args, pageSize(params.Limit))
+
+	var movements []*StockMovement
+	err := ms.db.SelectContext(ctx, &movements, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing stock movements: %w", err)
+	}
+
+	return movements, nil
+}
+
+// CreateCategory inserts a category. A slug that is already taken fails with
+// ErrCategoryExists.
+func (ms *MySQLStorer) CreateCategory(ctx context.Context, c *Category) (*Category, error) {
+	res, err := ms.db.NamedExecContext(ctx, "INSERT INTO categories (parent_id, name, slug, sort_order) VALUES (:parent_id, :name, :slug, :sort_order)", c)
+	if err != nil {
+		if isMySQLError(err, mysqlErrDupEntry) {
+			return nil, fmt.Errorf("error inserting category: %w", ErrCategoryExists)
+		}
+		return nil, fmt.Errorf("error inserting category: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("error getting last inserted ID: %w", err)
+	}
+	c.ID = id
+
+	return c, nil
+}
+
+func (ms *MySQLStorer) GetCategory(ctx context.Context, id int64) (*Category, error) {
+	var c Category
+	err := ms.db.GetContext(ctx, &c, "SELECT * FROM categories WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting category: %w", err)
+	}
+
+	return &c, nil
+}
+
+func (ms *MySQLStorer) GetCategoryBySlug(ctx context.Context, slug string) (*Category, error) {
+	var c Category
+	err := ms.db.GetContext(ctx, &c, "SELECT * FROM categories WHERE slug=?", slug)
+	if err != nil {
+		return nil, fmt.Errorf("error getting category: %w", err)
+	}
+
+	return &c, nil
+}
+
+// ListCategories returns every category in display order.
+func (ms *MySQLStorer) ListCategories(ctx context.Context) ([]*Category, error) {
+	var categories []*Category
+	err := ms.db.SelectContext(ctx, &categories, "SELECT * FROM categories ORDER BY sort_order, name, id")
+	if err != nil {
+		return nil, fmt.Errorf("error listing categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+func (ms *MySQLStorer) UpdateCategory(ctx context.Context, c *Category) (*Category, error) {
+	_, err := ms.db.NamedExecContext(ctx, "UPDATE categories SET parent_id=:parent_id, name=:name, slug=:slug, sort_order=:sort_order, updated_at=:updated_at WHERE id=:id", c)
+	if err != nil {
+		if isMySQLError(err, mysqlErrDupEntry) {
+			return nil, fmt.Errorf("error updating category: %w", ErrCategoryExists)
+		}
+		return nil, fmt.Errorf("error updating category: %w", err)
+	}
+
+	return c, nil
+}
+
+// DeleteCategory deletes an empty category. Categories with subcategories or
+// products fail with ErrCategoryInUse.
+func (ms *MySQLStorer) DeleteCategory(ctx context.Context, id int64) error {
+	_, err := ms.db.ExecContext(ctx, "DELETE FROM categories WHERE id=?", id)
+	if err != nil {
+		if isMySQLError(err, mysqlErrRowIsReferenced) {
+			return fmt.Errorf("error deleting category: %w", ErrCategoryInUse)
+		}
+		return fmt.Errorf("error deleting category: %w", err)
+	}
+
+	return nil
+}
+
+// promotionColumns selects a promotion with the number of times it was
+// redeemed.
+const promotionColumns = "promotions.*, (SELECT COUNT(*) FROM promotion_redemptions WHERE promotion_redemptions.promotion_id=promotions.id) AS times_used"
+
+// CreatePromotion inserts a promotion. A code that is already taken fails
+// with ErrPromotionExists.
+func (ms *MySQLStorer) CreatePromotion(ctx context.Context, p *Promotion) (*Promotion, error) {
+	res, err := ms.db.NamedExecContext(ctx, "INSERT INTO promotions (name, code, type, discount_rate, discount_amount, buy_quantity, get_quantity, product_id, category_id, min_subtotal, starts_at, ends_at, usage_limit, per_customer_limit, active) VALUES (:name, :code, :type, :discount_rate, :discount_amount, :buy_quantity, :get_quantity, :product_id, :category_id, :min_subtotal, :starts_at, :ends_at, :usage_limit, :per_customer_limit, :active)", p)
+	if err != nil {
+		if isMySQLError(err, mysqlErrDupEntry) {
+			return nil, fmt.Errorf("error inserting promotion: %w", ErrPromotionExists)
+		}
+		return nil, fmt.Errorf("error inserting promotion: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("error getting last insert ID: %w", err)
+	}
+	p.ID = id
+
+	return p, nil
+}
+
+func (ms *MySQLStorer) GetPromotion(ctx context.Context, id int64) (*Promotion, error) {
+	var p Promotion
+	err := ms.db.GetContext(ctx, &p, "SELECT "+promotionColumns+" FROM promotions WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting promotion: %w", err)
+	}
+
+	return &p, nil
+}
+
+func (ms *MySQLStorer) GetPromotionByCode(ctx context.Context, code string) (*Promotion, error) {
+	var p Promotion
+	err := ms.db.GetContext(ctx, &p, "SELECT "+promotionColumns+" FROM promotions WHERE code=?", code)
+	if err != nil {
+		return nil, fmt.Errorf("error getting promotion: %w", err)
+	}
+
+	return &p, nil
+}
+
+func (ms *MySQLStorer) ListPromotions(ctx context.Context) ([]*Promotion, error) {
+	var promotions []*Promotion
+	err := ms.db.SelectContext(ctx, &promotions, "SELECT "+promotionColumns+" FROM promotions ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("error listing promotions: %w", err)
+	}
+
+	return promotions, nil
+}
+
+func (ms *MySQLStorer) ListAutomaticPromotions(ctx context.Context, t time.Time) ([]*Promotion, error) {
+	var promotions []*Promotion
+	err := ms.db.SelectContext(ctx, &promotions, "SELECT "+promotionColumns+" FROM promotions WHERE code IS NULL AND active AND (starts_at IS NULL OR starts_at<=?) AND (ends_at IS NULL OR ends_at>?) ORDER BY id", t, t)
+	if err != nil {
+		return nil, fmt.Errorf("error listing promotions: %w", err)
+	}
+
+	return promotions, nil
+}
+
+func (ms *MySQLStorer) CountUserRedemptions(ctx context.Context, userID int64, promotionIDs []int64) (map[int64]int64, error) {
+	counts := make(map[int64]int64, len(promotionIDs))
+	if len(promotionIDs) == 0 {
+		return counts, nil
+	}
+
+	q, args, err := sqlx.In("SELECT promotion_id, COUNT(*) AS n FROM promotion_redemptions WHERE user_id=? AND promotion_id IN (?) GROUP BY promotion_id", userID, promotionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error building redemptions query: %w", err)
+	}
+
+	var rows []struct {
+		PromotionID int64 `db:"promotion_id"`
+		N           int64 `db:"n"`
+	}
+	err = ms.db.SelectContext(ctx, &rows, ms.db.Rebind(q), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error counting redemptions: %w", err)
+	}
+	for _, r := range rows {
+		counts[r.PromotionID] = r.N
+	}
+
+	return counts, nil
+}
+
+func (ms *MySQLStorer) UpdatePromotion(ctx context.Context, p *Promotion) (*Promotion, error) {
+	_, err := ms.db.NamedExecContext(ctx, "UPDATE promotions SET name=:name, code=:code, type=:type, discount_rate=:discount_rate, discount_amount=:discount_amount, buy_quantity=:buy_quantity, get_quantity=:get_quantity, product_id=:product_id, category_id=:category_id, min_subtotal=:min_subtotal, starts_at=:starts_at, ends_at=:ends_at, usage_limit=:usage_limit, per_customer_limit=:per_customer_limit, active=:active, updated_at=:updated_at WHERE id=:id", p)
+	if err != nil {
+		if isMySQLError(err, mysqlErrDupEntry) {
+			return nil, fmt.Errorf("error updating promotion: %w", ErrPromotionExists)
+		}
+		return nil, fmt.Errorf("error updating promotion: %w", err)
+	}
+
+	return p, nil
+}
+
+// DeletePromotion deletes a promotion that was never redeemed.
+func (ms *MySQLStorer) DeletePromotion(ctx context.Context, id int64) error {
+	_, err := ms.db.ExecContext(ctx, "DELETE FROM promotions WHERE id=?", id)
+	if err != nil {
+		if isMySQLError(err, mysqlErrRowIsReferenced) {
+			return fmt.Errorf("error deleting promotion: %w", ErrPromotionInUse)
+		}
+		return fmt.Errorf("error deleting promotion: %w", err)
+	}
+
+	return nil
+}
+
+// CreateTaxRate inserts a tax rate. A jurisdiction and tax class that
+// already have a rate fail with ErrTaxRateExists.
+func (ms *MySQLStorer) CreateTaxRate(ctx context.Context, r *TaxRate) (*TaxRate, error) {
+	res, err := ms.db.NamedExecContext(ctx, "INSERT INTO tax_rates (country, region, tax_class, name, rate, inclusive) VALUES (:country, :region, :tax_class, :name, :rate, :inclusive)", r)
+	if err != nil {
+		if isMySQLError(err, mysqlErrDupEntry) {
+			return nil, fmt.Errorf("error inserting tax rate: %w", ErrTaxRateExists)
+		}
+		return nil, fmt.Errorf("error inserting tax rate: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("error getting last insert ID: %w", err)
+	}
+	r.ID = id
+
+	return r, nil
+}
+
+func (ms *MySQLStorer) GetTaxRate(ctx context.Context, id int64) (*TaxRate, error) {
+	var r TaxRate
+	err := ms.db.GetContext(ctx, &r, "SELECT * FROM tax_rates WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting tax rate: %w", err)
+	}
+
+	return &r, nil
+}
+
+func (ms *MySQLStorer) ListTaxRates(ctx context.Context) ([]*TaxRate, error) {
+	var rates []*TaxRate
+	err := ms.db.SelectContext(ctx, &rates, "SELECT * FROM tax_rates ORDER BY country, region, tax_class")
+	if err != nil {
+		return nil, fmt.Errorf("error listing tax rates: %w", err)
+	}
+
+	return rates, nil
+}
+
+func (ms *MySQLStorer) ListApplicableTaxRates(ctx context.Context, country, region string) ([]*TaxRate, error) {
+	var rates []*TaxRate
+	err := ms.db.SelectContext(ctx, &rates, "SELECT * FROM tax_rates WHERE (country=? AND region IN (?, '')) OR (country='' AND region='') ORDER BY country, region, tax_class", country, region)
+	if err != nil {
+		return nil, fmt.Errorf("error listing tax rates: %w", err)
+	}
+
+	return rates, nil
+}
+
+func (ms *MySQLStorer) UpdateTaxRate(ctx context.Context, r *TaxRate) (*TaxRate, error) {
+	_, err := ms.db.NamedExecContext(ctx, "UPDATE tax_rates SET country=:country, region=:region, tax_class=:tax_class, name=:name, rate=:rate, inclusive=:inclusive, updated_at=:updated_at WHERE id=:id", r)
+	if err != nil {
+		if isMySQLError(err, mysqlErrDupEntry) {
+			return nil, fmt.Errorf("error updating tax rate: %w", ErrTaxRateExists)
+		}
+		return nil, fmt.Errorf("error updating tax rate: %w", err)
+	}
+
+	return r, nil
+}
+
+// DeleteTaxRate deletes a tax rate. Orders keep the rates they were taxed
+// at, so deleting one never affects them.
+func (ms *MySQLStorer) DeleteTaxRate(ctx context.Context, id int64) error {
+	_, err := ms.db.ExecContext(ctx, "DELETE FROM tax_rates WHERE id=?", id)
+	if err != nil {
+		return fmt.Errorf("error deleting tax rate: %w", err)
+	}
+
+	return nil
+}
+
+// CreateAddress adds an address to a user's address book, clearing the
+// default flag of the user's other addresses if it is the default.
+func (ms *MySQLStorer) CreateAddress(ctx context.Context, a *UserAddress) (*UserAddress, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		if a.IsDefault {
+			if _, err := tx.ExecContext(ctx, "UPDATE addresses SET is_default=false WHERE user_id=?", a.UserID); err != nil {
+				return fmt.Errorf("error clearing default address: %w", err)
+			}
+		}
+
+		res, err := tx.NamedExecContext(ctx, "INSERT INTO addresses (user_id, label, name, line1, line2, city, region, postal_code, country, is_default) VALUES (:user_id, :label, :name, :line1, :line2, :city, :region, :postal_code, :country, :is_default)", a)
+		if err != nil {
+			return fmt.Errorf("error inserting address: %w", err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("error getting last insert ID: %w", err)
+		}
+		a.ID = id
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating address: %w", err)
+	}
+
+	return a, nil
+}
+
+func (ms *MySQLStorer) GetAddress(ctx context.Context, id int64) (*UserAddress, error) {
+	var a UserAddress
+	err := ms.db.GetContext(ctx, &a, "SELECT * FROM addresses WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting address: %w", err)
+	}
+
+	return &a, nil
+}
+
+func (ms *MySQLStorer) ListUserAddresses(ctx context.Context, userID int64) ([]*UserAddress, error) {
+	var addresses []*UserAddress
+	err := ms.db.SelectContext(ctx, &addresses, "SELECT * FROM addresses WHERE user_id=? ORDER BY is_default DESC, id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing addresses: %w", err)
+	}
+
+	return addresses, nil
+}
+
+func (ms *MySQLStorer) UpdateAddress(ctx context.Context, a *UserAddress) (*UserAddress, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		if a.IsDefault {
+			if _, err := tx.ExecContext(ctx, "UPDATE addresses SET is_default=false WHERE user_id=? AND id<>?", a.UserID, a.ID); err != nil {
+				return fmt.Errorf("error clearing default address: %w", err)
+			}
+		}
+
+		_, err := tx.NamedExecContext(ctx, "UPDATE addresses SET label=:label, name=:name, line1=:line1, line2=:line2, city=:city, region=:region, postal_code=:postal_code, country=:country, is_default=:is_default, updated_at=:updated_at WHERE id=:id", a)
+		if err != nil {
+			return fmt.Errorf("error updating address: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error updating address: %w", err)
+	}
+
+	return a, nil
+}
+
+// DeleteAddress deletes an address. Orders keep the snapshots they took of
+// it.
+func (ms *MySQLStorer) DeleteAddress(ctx context.Context, id int64) error {
+	_, err := ms.db.ExecContext(ctx, "DELETE FROM addresses WHERE id=?", id)
+	if err != nil {
+		return fmt.Errorf("error deleting address: %w", err)
+	}
+
+	return nil
+}
+
+func (ms *MySQLStorer) CreateShippingZone(ctx context.Context, z *ShippingZone) (*ShippingZone, error) {
+	res, err := ms.db.NamedExecContext(ctx, "INSERT INTO shipping_zones (name, countries, postal_prefixes) VALUES (:name, :countries, :postal_prefixes)", z)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting shipping zone: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("error getting last insert ID: %w", err)
+	}
+	z.ID = id
+
+	return z, nil
+}
+
+func (ms *MySQLStorer) GetShippingZone(ctx context.Context, id int64) (*ShippingZone, error) {
+	var z ShippingZone
+	err := ms.db.GetContext(ctx, &z, "SELECT * FROM shipping_zones WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting shipping zone: %w", err)
+	}
+
+	return &z, nil
+}
+
+func (ms *MySQLStorer) ListShippingZones(ctx context.Context) ([]*ShippingZone, error) {
+	var zones []*ShippingZone
+	err := ms.db.SelectContext(ctx, &zones, "SELECT * FROM shipping_zones ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("error listing shipping zones: %w", err)
+	}
+
+	return zones, nil
+}
+
+func (ms *MySQLStorer) UpdateShippingZone(ctx context.Context, z *ShippingZone) (*ShippingZone, error) {
+	_, err := ms.db.NamedExecContext(ctx, "UPDATE shipping_zones SET name=:name, countries=:countries, postal_prefixes=:postal_prefixes, updated_at=:updated_at WHERE id=:id", z)
+	if err != nil {
+		return nil, fmt.Errorf("error updating shipping zone: %w", err)
+	}
+
+	return z, nil
+}
+
+// DeleteShippingZone deletes a zone, and its methods with it.
+func (ms *MySQLStorer) DeleteShippingZone(ctx context.Context, id int64) error {
+	_, err := ms.db.ExecContext(ctx, "DELETE FROM shipping_zones WHERE id=?", id)
+	if err != nil {
+		return fmt.Errorf("error deleting shipping zone: %w", err)
+	}
+
+	return nil
+}
+
+func (ms *MySQLStorer) CreateShippingMethod(ctx context.Context, m *ShippingMethod) (*ShippingMethod, error) {
+	res, err := ms.db.NamedExecContext(ctx, "INSERT INTO shipping_methods (zone_id, name, basis, tiers, free_above, active) VALUES (:zone_id, :name, :basis, :tiers, :free_above, :active)", m)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting shipping method: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("error getting last insert ID: %w", err)
+	}
+	m.ID = id
+
+	return m, nil
+}
+
+func (ms *MySQLStorer) GetShippingMethod(ctx context.Context, id int64) (*ShippingMethod, error) {
+	var m ShippingMethod
+	err := ms.db.GetContext(ctx, &m, "SELECT * FROM shipping_methods WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting shipping method: %w", err)
+	}
+
+	return &m, nil
+}
+
+// ListShippingMethods returns the methods of the given zones ordered by zone
+// and id.
+func (ms *MySQLStorer) ListShippingMethods(ctx context.Context, zoneIDs []int64) ([]*ShippingMethod, error) {
+	if len(zoneIDs) == 0 {
+		return nil, nil
+	}
+
+	q, args, err := sqlx.In("SELECT * FROM shipping_methods WHERE zone_id IN (?) ORDER BY zone_id, id", zoneIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error building shipping methods query: %w", err)
+	}
+
+	var methods []*ShippingMethod
+	err = ms.db.SelectContext(ctx, &methods, ms.db.Rebind(q), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing shipping methods: %w", err)
+	}
+
+	return methods, nil
+}
+
+func (ms *MySQLStorer) UpdateShippingMethod(ctx context.Context, m *ShippingMethod) (*ShippingMethod, error) {
+	_, err := ms.db.NamedExecContext(ctx, "UPDATE shipping_methods SET name=:name, basis=:basis, tiers=:tiers, free_above=:free_above, active=:active, updated_at=:updated_at WHERE id=:id", m)
+	if err != nil {
+		return nil, fmt.Errorf("error updating shipping method: %w", err)
+	}
+
+	return m, nil
+}
+
+// DeleteShippingMethod deletes a shipping method. Orders keep the name of
+// the method they shipped with.
+func (ms *MySQLStorer) DeleteShippingMethod(ctx context.Context, id int64) error {
+	_, err := ms.db.ExecContext(ctx, "DELETE FROM shipping_methods WHERE id=?", id)
+	if err != nil {
+		return fmt.Errorf("error deleting shipping method: %w", err)
+	}
+
+	return nil
+}
+
+// redeemPromotions records a redemption for every promotion an order was
+// discounted by. Each promotion is locked, in id order, while its usage
+// limits are checked against the redemptions so far.
+func redeemPromotions(ctx context.Context, tx *sqlx.Tx, o *Order) error {
+	amounts := make(map[int64]money.Money, len(o.Discounts))
+	for _, d := range o.Discounts {
+		amounts[d.PromotionID] = amounts[d.PromotionID].Add(d.Amount)
+	}
+
+	for _, id := range sortedKeys(amounts) {
+		var p Promotion
+		err := tx.GetContext(ctx, &p, "SELECT id, name, usage_limit, per_customer_limit FROM promotions WHERE id=? FOR UPDATE", id)
+		if err != nil {
+			return fmt.Errorf("error locking promotion: %w", err)
+		}
+
+		if p.UsageLimit > 0 {
+			var used int64
+			err := tx.GetContext(ctx, &used, "SELECT COUNT(*) FROM promotion_redemptions WHERE promotion_id=?", id)
+			if err != nil {
+				return fmt.Errorf("error counting redemptions: %w", err)
+			}
+			if used >= p.UsageLimit {
+				return &PromotionLimitError{PromotionID: p.ID, Name: p.Name}
+			}
+		}
+		if p.PerCustomerLimit > 0 {
+			var used int64
+			err := tx.GetContext(ctx, &used, "SELECT COUNT(*) FROM promotion_redemptions WHERE promotion_id=? AND user_id=?", id, o.UserID)
+			if err != nil {
+				return fmt.Errorf("error counting redemptions: %w", err)
+			}
+			if used >= p.PerCustomerLimit {
+				return &PromotionLimitError{PromotionID: p.ID, Name: p.Name, PerCustomer: true}
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, "INSERT INTO promotion_redemptions (promotion_id, order_id, user_id, amount) VALUES (?, ?, ?, ?)", id, o.ID, o.UserID, amounts[id])
+		if err != nil {
+			return fmt.Errorf("error inserting redemption: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// CreateOrder reserves stock, inserts the order with its items, counts the
+// units sold at scheduled prices and redeems the promotions it was
+// discounted by. The notification event ne, if not nil, is enqueued in the
+// same transaction so the customer is notified if and only if the order is
+// committed.
+func (ms *MySQLStorer) CreateOrder(ctx context.Context, o *Order, ne *NotificationEvent) (*Order, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		sales, err := reserveStock(ctx, tx, o.Items)
+		if err != nil {
+			return err
+		}
+
+		order, err := createOrder(ctx, tx, o)
+		if err != nil {
+			return fmt.Errorf("error creating order: %w", err)
+		}
+
+		for i := range order.Items {
+			oi := &order.Items[i]
+			oi.OrderID = order.ID
+			id, err := createOrderItem(ctx, tx, *oi)
+			if err != nil {
+				return fmt.Errorf("error creating order item: %w", err)
+			}
+			oi.ID = *id
+
+		}
+
+		for _, m := range sales {
+			m.Reason = fmt.Sprintf("order %d", order.ID)
+			m.ActorID = &order.UserID
+			m.OrderID = &order.ID
+		}
+		err = applyStockMovements(ctx, tx, sales)
+		if err != nil {
+			return err
+		}
+
+		err = sellScheduledPrices(ctx, tx, order.Items)
+		if err != nil {
+			return err
+		}
+
+		err = redeemPromotions(ctx, tx, order)
+		if err != nil {
+			return err
+		}
+
+		err = redeemTenders(ctx, tx, order)
+		if err != nil {
+			return err
+		}
+
+		if ne != nil {
+			ne.OrderID = order.ID
+			ne.OrderStatus = Pending
+			err = enqueueNotificationEvent(ctx, tx, ne)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("error creating order: %w", err)
+	}
+
+	return o, nil
+}
+
+// sellScheduledPrices counts the units an order sells at each scheduled
+// price. The prices are locked, in id order after the rows reserveStock
+// locked, while they are checked to still run at the price the order was
+// quoted at and the units against what their caps leave.
+func sellScheduledPrices(ctx context.Context, tx *sqlx.Tx, items []OrderItem) error {
+	lines := scheduledPriceLines(items)
+	now := time.Now()
+	for _, id := range sortedKeys(lines) {
+		var sp ScheduledPrice
+		err := tx.GetContext(ctx, &sp, "SELECT * FROM product_prices WHERE id=? FOR UPDATE", id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return &ScheduledPriceSoldOutError{ScheduledPriceID: id, ProductID: lines[id][0].ProductID, Changed: true}
+		}
+		if err != nil {
+			return fmt.Errorf("error locking scheduled price: %w", err)
+		}
+		if err := checkScheduledPrice(&sp, lines[id], now); err != nil {
+			return err
+		}
+
+		var qty int64
+		for _, oi := range lines[id] {
+			qty += oi.Quantity
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE product_prices SET quantity_sold=quantity_sold+? WHERE id=?", qty, id)
+		if err != nil {
+			return fmt.Errorf("error updating scheduled price: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// releaseScheduledPrices gives the units an order sold at scheduled prices
+// back to them.
+func releaseScheduledPrices(ctx context.Context, tx *sqlx.Tx, orderID int64) error {
+	_, err := tx.ExecContext(ctx, "UPDATE product_prices JOIN (SELECT scheduled_price_id, SUM(quantity) AS quantity FROM order_items WHERE order_id=? AND scheduled_price_id IS NOT NULL GROUP BY scheduled_price_id) sold ON sold.scheduled_price_id=product_prices.id SET product_prices.quantity_sold=product_prices.quantity_sold-sold.quantity", orderID)
+	if err != nil {
+		return fmt.Errorf("error releasing scheduled prices: %w", err)
+	}
+
+	return nil
+}
+
+// orderedQuantities sums the quantity per product, as the same product may
+// appear on several order lines.
+func orderedQuantities(items []OrderItem) map[int64]int64 {
+	qty := make(map[int64]int64, len(items))
+	for _, oi := range items {
+		qty[oi.ProductID] += oi.Quantity
+	}
+	return qty
+}
+
+// orderedVariantQuantities sums the quantity per variant over the lines that
+// order a variant.
+func orderedVariantQuantities(items []OrderItem) map[int64]int64 {
+	qty := make(map[int64]int64)
+	for _, oi := range items {
+		if oi.VariantID != nil {
+			qty[*oi.VariantID] += oi.Quantity
+		}
+	}
+	return qty
+}
+
+// reserveStock locks the ordered products and variants, checks that every
+// quantity is in stock and takes it from the warehouses holding it, lowest
+// warehouse id first. Variants are checked on their own stock, other lines
+// on the product's. The sales it returns are recorded once the order has an
+// id. Rows are locked products first, then variants, then stock levels, each
+// in id order to avoid deadlocks between concurrent orders.
+func reserveStock(ctx context.Context, tx *sqlx.Tx, items []OrderItem) ([]*StockMovement, error) {
+	qty := orderedQuantities(items)
+	if len(qty) == 0 {
+		return nil, nil
+	}
+
+	products, err := lockProducts(ctx, tx, sortedKeys(qty))
+	if err != nil {
+		return nil, err
+	}
+
+	variantQty := orderedVariantQuantities(items)
+	variants, err := lockVariants(ctx, tx, sortedKeys(variantQty))
+	if err != nil {
+		return nil, err
+	}
+
+	// quantities of product lines that do not name a variant
+	plainQty := make(map[int64]int64, len(qty))
+	for _, oi := range items {
+		if oi.VariantID == nil {
+			plainQty[oi.ProductID] += oi.Quantity
+		}
+	}
+
+	names := make(map[int64]string, len(products))
+	for _, p := range products {
+		names[p.ID] = p.Name
+		if n, ok := plainQty[p.ID]; ok && p.CountInStock < n {
+			return nil, &InsufficientStockError{
+				ProductID: p.ID,
+				Name:      p.Name,
+				Requested: n,
+				Available: p.CountInStock,
+			}
+		}
+	}
+	for _, v := range variants {
+		if v.CountInStock < variantQty[v.ID] {
+			return nil, &InsufficientStockError{
+				ProductID: v.ProductID,
+				Name:      names[v.ProductID],
+				SKU:       v.SKU,
+				Requested: variantQty[v.ID],
+				Available: v.CountInStock,
+			}
+		}
+	}
+
+	var sales []*StockMovement
+	wanted := orderedStock(items)
+	for _, k := range sortedStockKeys(wanted) {
+		var levels []StockLevel
+		err := tx.SelectContext(ctx, &levels, "SELECT * FROM stock_levels WHERE product_id=? AND variant_id=? AND quantity>0 ORDER BY warehouse_id FOR UPDATE", k.ProductID, k.VariantID)
+		if err != nil {
+			return nil, fmt.Errorf("error locking stock levels: %w", err)
+		}
+
+		n := wanted[k]
+		for _, l := range levels {
+			if n == 0 {
+				break
+			}
+			take := min(n, l.Quantity)
+			sales = append(sales, &StockMovement{
+				WarehouseID: l.WarehouseID,
+				ProductID:   k.ProductID,
+				VariantID:   k.VariantID,
+				Kind:        StockSale,
+				Quantity:    -take,
+			})
+			n -= take
+		}
+		if n > 0 {
+			return nil, fmt.Errorf("error reserving stock: warehouses hold %d units of product %d less than its count_in_stock", n, k.ProductID)
+		}
+	}
+
+	return sales, nil
+}
+
+// lockProducts locks products in id order. A missing product fails with
+// sql.ErrNoRows.
+func lockProducts(ctx context.Context, tx *sqlx.Tx, ids []int64) ([]Product, error) {
+	q, args, err := sqlx.In("SELECT id, name, count_in_stock FROM products WHERE id IN (?) ORDER BY id FOR UPDATE", ids)
+	if err != nil {
+		return nil, fmt.Errorf("error building stock query: %w", err)
+	}
+
+	var products []Product
+	err = tx.SelectContext(ctx, &products, tx.Rebind(q), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error locking products: %w", err)
+	}
+	if len(products) != len(ids) {
+		return nil, fmt.Errorf("error locking products: %w", sql.ErrNoRows)
+	}
+
+	return products, nil
+}
+
+// lockVariants locks variants in id order, like lockProducts.
+func lockVariants(ctx context.Context, tx *sqlx.Tx, ids []int64) ([]Variant, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	q, args, err := sqlx.In("SELECT id, product_id, sku, count_in_stock FROM product_variants WHERE id IN (?) ORDER BY id FOR UPDATE", ids)
+	if err != nil {
+		return nil, fmt.Errorf("error building stock query: %w", err)
+	}
+
+	var variants []Variant
+	err = tx.SelectContext(ctx, &variants, tx.Rebind(q), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error locking variants: %w", err)
+	}
+	if len(variants) != len(ids) {
+		return nil, fmt.Errorf("error locking variants: %w", sql.ErrNoRows)
+	}
+
+	return variants, nil
+}
+
+// restockOrder releases the stock an order took by reversing its sales in
+// the warehouses they were taken from. Orders placed before stock was kept
+// per warehouse have no sales recorded and are restocked into the main
+// warehouse.
+func restockOrder(ctx context.Context, tx *sqlx.Tx, orderID int64, reason string, actorID *int64) error {
+	var sold []StockLevel
+	err := tx.SelectContext(ctx, &sold, "SELECT warehouse_id, product_id, variant_id, SUM(quantity) AS quantity FROM stock_movements WHERE order_id=? AND kind='sale' GROUP BY warehouse_id, product_id, variant_id ORDER BY product_id, variant_id, warehouse_id", orderID)
+	if err != nil {
+		return fmt.Errorf("error getting order sales: %w", err)
+	}
+
+	if len(sold) == 0 {
+		var items []OrderItem
+		err := tx.SelectContext(ctx, &items, "SELECT * FROM order_items WHERE order_id=?", orderID)
+		if err != nil {
+			return fmt.Errorf("error getting order items: %w", err)
+		}
+
+		return applyStockMovements(ctx, tx, restockMovements(items, StockRelease, reason, actorID))
+	}
+
+	var mvs []*StockMovement
+	for _, l := range sold {
+		if l.Quantity < 0 {
+			mvs = append(mvs, &StockMovement{
+				WarehouseID: l.WarehouseID,
+				ProductID:   l.ProductID,
+				VariantID:   l.VariantID,
+				Kind:        StockRelease,
+				Quantity:    -l.Quantity,
+				Reason:      reason,
+				ActorID:     actorID,
+				OrderID:     &orderID,
+			})
+		}
+	}
+
+	return applyStockMovements(ctx, tx, mvs)
+}
+
+// applyStockMovements appends movements to the ledger and adds their
+// quantities to stock levels and to the count_in_stock of products and
+// variants. Callers lock the rows and check that no stock goes below zero.
+func applyStockMovements(ctx context.Context, tx *sqlx.Tx, mvs []*StockMovement) error {
+	for _, m := range mvs {
+		_, err := tx.ExecContext(ctx, "UPDATE products SET count_in_stock=count_in_stock+? WHERE id=?", m.Quantity, m.ProductID)
+		if err != nil {
+			return fmt.Errorf("error updating product stock: %w", err)
+		}
+
+		if m.VariantID != 0 {
+			_, err := tx.ExecContext(ctx, "UPDATE product_variants SET count_in_stock=count_in_stock+? WHERE id=?", m.Quantity, m.VariantID)
+			if err != nil {
+				return fmt.Errorf("error updating variant stock: %w", err)
+			}
+		}
+
+		err = addStockLevel(ctx, tx, m)
+		if err != nil {
+			return err
+		}
+
+		err = insertStockMovement(ctx, tx, m)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// receiveOpeningStock records the stock a product or variant is created with
+// as received into the main warehouse. Its count_in_stock is inserted with
+// it, so only the stock level and the ledger are written.
+func receiveOpeningStock(ctx context.Context, tx *sqlx.Tx, productID, variantID, quantity int64) error {
+	if quantity == 0 {
+		return nil
+	}
+
+	m := &StockMovement{
+		WarehouseID: MainWarehouseID,
+		ProductID:   productID,
+		VariantID:   variantID,
+		Kind:        StockReceipt,
+		Quantity:    quantity,
+		Reason:      "opening stock",
+	}
+	err := addStockLevel(ctx, tx, m)
+	if err != nil {
+		return err
+	}
+
+	return insertStockMovement(ctx, tx, m)
+}
+
+func addStockLevel(ctx context.Context, tx *sqlx.Tx, m *StockMovement) error {
+	_, err := tx.ExecContext(ctx, "INSERT INTO stock_levels (warehouse_id, product_id, variant_id, quantity) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE quantity=quantity+VALUES(quantity)", m.WarehouseID, m.ProductID, m.VariantID, m.Quantity)
+	if err != nil {
+		return fmt.Errorf("error updating stock level: %w", err)
+	}
+
+	return nil
+}
+
+func insertStockMovement(ctx context.Context, tx *sqlx.Tx, m *StockMovement) error {
+	res, err := tx.NamedExecContext(ctx, "INSERT INTO stock_movements (warehouse_id, product_id, variant_id, kind, quantity, reason, actor_id, order_id, other_warehouse_id) VALUES (:warehouse_id, :product_id, :variant_id, :kind, :quantity, :reason, :actor_id, :order_id, :other_warehouse_id)", m)
+	if err != nil {
+		return fmt.Errorf("error inserting stock movement: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last inserted ID: %w", err)
+	}
+	m.ID = id
+
+	return nil
+}
+
+func createOrder(ctx context.Context, tx *sqlx.Tx, o *Order) (*Order, error) {
+	res, err := tx.NamedExecContext(ctx, "INSERT INTO orders (payment_method, shipping_address, billing_address, shipping_method, tax_price, shipping_price, discount_price, coupon_code, discounts, total_price, user_id) VALUES (:payment_method, :shipping_address, :billing_address, :shipping_method, :tax_price, :shipping_price, :discount_price, :coupon_code, :discounts, :total_price, :user_id)", o)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting order: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("error getting last insert id: %w", err)
+	}
+	o.ID = id
+
+	return o, nil
+}
+
+func createOrderItem(ctx context.Context, tx *sqlx.Tx, oi OrderItem) (*int64, error) {
+	res, err := tx.NamedExecContext(ctx, "INSERT INTO order_items (name, quantity, image, price, product_id, variant_id, sku, scheduled_price_id, discount_price, tax_rate, tax_jurisdiction, tax_price, tax_inclusive, order_id) VALUES (:name, :quantity, :image, :price, :product_id, :variant_id, :sku, :scheduled_price_id, :discount_price, :tax_rate, :tax_jurisdiction, :tax_price, :tax_inclusive, :order_id)", oi)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting order item: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("error getting last insert id: %w", err)
+	}
+
+	return &id, nil
+}
+
+func (ms *MySQLStorer) GetOrder(ctx context.Context, id int64) (*Order, error) {
+	var o Order
+	err := ms.db.GetContext(ctx, &o, "SELECT * FROM orders WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting order: %w", err)
+	}
+
+	var items []OrderItem
+	err = ms.db.SelectContext(ctx, &items, "SELECT * FROM order_items WHERE order_id=?", o.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting order items: %w", err)
+	}
+	o.Items = items
+
+	return &o, nil
+}
+
+func (ms *MySQLStorer) GetOrderStatusByID(ctx context.Context, id int64) (*Order, error) {
+	var o Order
+	err := ms.db.GetContext(ctx, &o, "SELECT id, user_id, status FROM orders WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting order status: %w", err)
+	}
+
+	return &o, nil
+}
+
+func (ms *MySQLStorer) ListOrders(ctx context.Context) ([]*Order, error) {
+	var orders []*Order
+	err := ms.db.SelectContext(ctx, &orders, "SELECT * FROM orders")
+	if err != nil {
+		return nil, fmt.Errorf("error listing orders: %w", err)
+	}
+
+	err = ms.loadOrderItems(ctx, orders)
+	if err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// ListUserOrders returns one page of the user's orders, newest first, with
+// their items.
+func (ms *MySQLStorer) ListUserOrders(ctx context.Context, params *ListOrdersParams) (*OrderPage, error) {
+	limit := pageSize(params.Limit)
+	cursor, err := params.cursor()
+	if err != nil {
+		return nil, fmt.Errorf("error listing orders: %w", err)
+	}
+
+	where := []string{"user_id=?"}
+	args := []interface{}{params.UserID}
+	if params.Status != "" {
+		where = append(where, "status=?")
+		args = append(args, params.Status)
+	}
+	if params.From != nil {
+		where = append(where, "created_at>=?")
+		args = append(args, *params.From)
+	}
+	if params.To != nil {
+		where = append(where, "created_at<?")
+		args = append(args, *params.To)
+	}
+	if cursor != nil {
+		where = append(where, "(created_at<? OR (created_at=? AND id<?))")
+		args = append(args, cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+	}
+
+	q := "SELECT * FROM orders WHERE " + strings.Join(where, " AND ") + " ORDER BY created_at DESC, id DESC LIMIT ?"
+	// one extra row tells us whether there is a next page
+	args = append(args, limit+1)
+
+	var orders []*Order
+	err = ms.db.SelectContext(ctx, &orders, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing orders: %w", err)
+	}
+
+	page := newOrderPage(orders, limit)
+	err = ms.loadOrderItems(ctx, page.Orders)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// loadOrderItems fetches the items of all orders with a single query.
+func (ms *MySQLStorer) loadOrderItems(ctx context.Context, orders []*Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*Order, len(orders))
+	ids := make([]int64, 0, len(orders))
+	for _, o := range orders {
+		byID[o.ID] = o
+		ids = append(ids, o.ID)
+	}
+
+	q, args, err := sqlx.In("SELECT * FROM order_items WHERE order_id IN (?) ORDER BY id", ids)
+	if err != nil {
+		return fmt.Errorf("error building order items query: %w", err)
+	}
+
+	var items []OrderItem
+	err = ms.db.SelectContext(ctx, &items, ms.db.Rebind(q), args...)
+	if err != nil {
+		return fmt.Errorf("error getting order items: %w", err)
+	}
+
+	for _, oi := range items {
+		o := byID[oi.OrderID]
+		o.Items = append(o.Items, oi)
+	}
+
+	return nil
+}
+
+// UpdateOrderStatus moves the order to o.Status and records change in the
+// order's status history. It fails with ErrOrderStatusChanged if the order is
+// no longer in change.FromStatus. Stock is released when an order that still
+// reserves it is cancelled or refunded, and so are the units it sold at
+// scheduled prices. Like in CreateOrder, ne is enqueued in the same
+// transaction.
+func (ms *MySQLStorer) UpdateOrderStatus(ctx context.Context, o *Order, change *OrderStatusChange, ne *NotificationEvent) (*Order, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		var current OrderStatus
+		err := tx.GetContext(ctx, &current, "SELECT status FROM orders WHERE id=? FOR UPDATE", o.ID)
+		if err != nil {
+			return fmt.Errorf("error locking order: %w", err)
+		}
+
+		if current != change.FromStatus {
+			return fmt.Errorf("order %d is %s, not %s: %w", o.ID, current, change.FromStatus, ErrOrderStatusChanged)
+		}
+
+		if releasesStock(current, o.Status) {
+			var actorID *int64
+			if change.ActorID != 0 {
+				actorID = &change.ActorID
+			}
+			err = restockOrder(ctx, tx, o.ID, fmt.Sprintf("order %d %s", o.ID, o.Status), actorID)
+			if err != nil {
+				return err
+			}
+			err = releaseScheduledPrices(ctx, tx, o.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.NamedExecContext(ctx, "UPDATE orders SET status=:status, updated_at=:updated_at WHERE id=:id", o)
+		if err != nil {
+			return fmt.Errorf("error updating order: %w", err)
+		}
+
+		change.OrderID = o.ID
+		change.ToStatus = o.Status
+		_, err = tx.NamedExecContext(ctx, "INSERT INTO order_status_history (order_id, from_status, to_status, reason, actor_id, actor_role) VALUES (:order_id, :from_status, :to_status, :reason, :actor_id, :actor_role)", change)
+		if err != nil {
+			return fmt.Errorf("error inserting order status history: %w", err)
+		}
+
+		if change.CreditNote != nil {
+			err = insertCreditNote(ctx, tx, change.CreditNote)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, g := range change.GiftCards {
+			err = insertGiftCard(ctx, tx, g)
+			if err != nil {
+				return err
+			}
+		}
+
+		if change.CancelGiftCards {
+			err = cancelOrderGiftCards(ctx, tx, o.ID, fmt.Sprintf("order %d %s", o.ID, o.Status))
+			if err != nil {
+				return err
+			}
+		}
+
+		if change.Loyalty != nil {
+			err = applyLoyaltyTransaction(ctx, tx, change.Loyalty)
+			if err != nil {
+				return err
+			}
+		}
+
+		if ne != nil {
+			ne.OrderID = o.ID
+			ne.OrderStatus = o.Status
+			err = enqueueNotificationEvent(ctx, tx, ne)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error updating order status: %w", err)
+	}
+
+	return o, nil
+}
+
+func (ms *MySQLStorer) ListOrderStatusChanges(ctx context.Context, orderID int64) ([]*OrderStatusChange, error) {
+	var changes []*OrderStatusChange
+	err := ms.db.SelectContext(ctx, &changes, "SELECT * FROM order_status_history WHERE order_id=? ORDER BY id", orderID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing order status history: %w", err)
+	}
+
+	return changes, nil
+}
+
+// DeleteOrder removes the order, its items, failed payments, status and
+// notification history. Stock, and the units sold at scheduled prices, are
+// released if the order still reserves it.
+func (ms *MySQLStorer) DeleteOrder(ctx context.Context, id int64) error {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		var current OrderStatus
+		err := tx.GetContext(ctx, &current, "SELECT status FROM orders WHERE id=? FOR UPDATE", id)
+		if err != nil {
+			return fmt.Errorf("error locking order: %w", err)
+		}
+
+		var records int64
+		err = tx.GetContext(ctx, &records, "SELECT (SELECT COUNT(*) FROM payments WHERE order_id=? AND status IN ('pending', 'authorized', 'captured', 'refunded')) + (SELECT COUNT(*) FROM invoices WHERE order_id=?) + (SELECT COUNT(*) FROM returns WHERE order_id=?)", id, id, id)
+		if err != nil {
+			return fmt.Errorf("error counting order records: %w", err)
+		}
+		if records > 0 {
+			return ErrOrderHasRecords
+		}
+
+		if current.ReservesStock() {
+			err = restockOrder(ctx, tx, id, fmt.Sprintf("order %d deleted", id), nil)
+			if err != nil {
+				return err
+			}
+			err = releaseScheduledPrices(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM order_status_history WHERE order_id=?", id)
+		if err != nil {
+			return fmt.Errorf("error deleting order status history: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM notification_events_queue WHERE order_id=?", id)
+		if err != nil {
+			return fmt.Errorf("error deleting notification events: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM notification_states WHERE order_id=?", id)
+		if err != nil {
+			return fmt.Errorf("error deleting notification states: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM promotion_redemptions WHERE order_id=?", id)
+		if err != nil {
+			return fmt.Errorf("error deleting promotion redemptions: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM payments WHERE order_id=?", id)
+		if err != nil {
+			return fmt.Errorf("error deleting payments: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM order_items WHERE order_id=?", id)
+		if err != nil {
+			return fmt.Errorf("error deleting order items: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM orders WHERE id=?", id)
+		if err != nil {
+			return fmt.Errorf("error deleting order: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("error deleting order: %w", err)
+	}
+
+	return nil
+}
+
+// CreatePayment locks the order while checking that none of its payments is
+// still active, so concurrent attempts to pay it cannot both go through.
+func (ms *MySQLStorer) CreatePayment(ctx context.Context, p *Payment) (*Payment, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, "SELECT id FROM orders WHERE id=? FOR UPDATE", p.OrderID)
+		if err != nil {
+			return fmt.Errorf("error locking order: %w", err)
+		}
+
+		var active int64
+		err = tx.GetContext(ctx, &active, "SELECT COUNT(*) FROM payments WHERE order_id=? AND status IN ('pending', 'authorized', 'captured') AND provider NOT IN (?, ?, ?)", p.OrderID, GiftCardProvider, StoreCreditProvider, LoyaltyPointsProvider)
+		if err != nil {
+			return fmt.Errorf("error counting active payments: %w", err)
+		}
+		if active > 0 {
+			return ErrPaymentInProgress
+		}
+
+		return insertPayment(ctx, tx, p)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating payment: %w", err)
+	}
+
+	return p, nil
+}
+
+func insertPayment(ctx context.Context, tx *sqlx.Tx, p *Payment) error {
+	res, err := tx.NamedExecContext(ctx, "INSERT INTO payments (order_id, provider, reference, status, amount, message) VALUES (:order_id, :provider, :reference, :status, :amount, :message)", p)
+	if err != nil {
+		return fmt.Errorf("error inserting payment: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert ID: %w", err)
+	}
+	p.ID = id
+
+	return nil
+}
+
+func (ms *MySQLStorer) GetPayment(ctx context.Context, id int64) (*Payment, error) {
+	var p Payment
+	err := ms.db.GetContext(ctx, &p, "SELECT * FROM payments WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting payment: %w", err)
+	}
+
+	return &p, nil
+}
+
+func (ms *MySQLStorer) GetPaymentByReference(ctx context.Context, provider, reference string) (*Payment, error) {
+	var p Payment
+	err := ms.db.GetContext(ctx, &p, "SELECT * FROM payments WHERE provider=? AND reference=?", provider, reference)
+	if err != nil {
+		return nil, fmt.Errorf("error getting payment: %w", err)
+	}
+
+	return &p, nil
+}
+
+func (ms *MySQLStorer) ListOrderPayments(ctx context.Context, orderID int64) ([]*Payment, error) {
+	var payments []*Payment
+	err := ms.db.SelectContext(ctx, &payments, "SELECT * FROM payments WHERE order_id=? ORDER BY id", orderID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing payments: %w", err)
+	}
+
+	return payments, nil
+}
+
+func (ms *MySQLStorer) UpdatePayment(ctx context.Context, p *Payment) (*Payment, error) {
+	_, err := ms.db.NamedExecContext(ctx, "UPDATE payments SET reference=:reference, status=:status, message=:message, updated_at=:updated_at WHERE id=:id", p)
+	if err != nil {
+		return nil, fmt.Errorf("error updating payment: %w", err)
+	}
+
+	return p, nil
+}
+
+// RefundPayment locks the payment while checking it was not refunded for
+// the key, or at all since it was read, so concurrent refunds of it cannot
+// both go through.
+func (ms *MySQLStorer) RefundPayment(ctx context.Context, p *Payment, r *PaymentRefund) (*Payment, error) {
+	var refunded Payment
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		err := tx.GetContext(ctx, &refunded, "SELECT * FROM payments WHERE id=? FOR UPDATE", p.ID)
+		if err != nil {
+			return fmt.Errorf("error locking payment: %w", err)
+		}
+
+		var applied int64
+		err = tx.GetContext(ctx, &applied, "SELECT COUNT(*) FROM payment_refunds WHERE payment_id=? AND refund_key=?", p.ID, r.Key)
+		if err != nil {
+			return fmt.Errorf("error counting payment refunds: %w", err)
+		}
+		if applied > 0 {
+			return ErrRefundApplied
+		}
+		if err := applyRefund(&refunded, p, r); err != nil {
+			return err
+		}
+
+		_, err = tx.NamedExecContext(ctx, "UPDATE payments SET status=:status, refunded_amount=:refunded_amount, updated_at=:updated_at WHERE id=:id", &refunded)
+		if err != nil {
+			return fmt.Errorf("error updating payment: %w", err)
+		}
+
+		switch {
+		case r.GiftCard != nil:
+			err = applyGiftCardTransaction(ctx, tx, r.GiftCard)
+		case r.StoreCredit != nil:
+			err = applyStoreCreditTransaction(ctx, tx, r.StoreCredit)
+		case r.Loyalty != nil:
+			err = applyLoyaltyTransaction(ctx, tx, r.Loyalty)
+		}
+		if err != nil {
+			return err
+		}
+
+		r.PaymentID = p.ID
+		r.Status = refundStatus(r)
+		res, err := tx.NamedExecContext(ctx, "INSERT INTO payment_refunds (payment_id, refund_key, amount, status) VALUES (:payment_id, :refund_key, :amount, :status)", r)
+		if err != nil {
+			return fmt.Errorf("error inserting payment refund: %w", err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("error getting last insert ID: %w", err)
+		}
+		r.ID = id
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error refunding payment: %w", err)
+	}
+	*p = refunded
+
+	return p, nil
+}
+
+func (ms *MySQLStorer) ApplyPaymentRefund(ctx context.Context, id int64) error {
+	_, err := ms.db.ExecContext(ctx, "UPDATE payment_refunds SET status=? WHERE id=?", RefundApplied, id)
+	if err != nil {
+		return fmt.Errorf("error applying payment refund: %w", err)
+	}
+
+	return nil
+}
+
+func (ms *MySQLStorer) ListOrderPaymentRefunds(ctx context.Context, orderID int64) ([]*PaymentRefund, error) {
+	var refunds []*PaymentRefund
+	err := ms.db.SelectContext(ctx, &refunds, "SELECT payment_refunds.* FROM payment_refunds JOIN payments ON payments.id=payment_refunds.payment_id WHERE payments.order_id=? ORDER BY payment_refunds.id", orderID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing payment refunds: %w", err)
+	}
+
+	return refunds, nil
+}
+
+func (ms *MySQLStorer) CreateGiftCard(ctx context.Context, g *GiftCard) (*GiftCard, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		return insertGiftCard(ctx, tx, g)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating gift card: %w", err)
+	}
+
+	return g, nil
+}
+
+// insertGiftCard inserts g and credits it with its initial amount.
+func insertGiftCard(ctx context.Context, tx *sqlx.Tx, g *GiftCard) error {
+	g.Balance = money.New(0, g.InitialAmount.Currency)
+	res, err := tx.NamedExecContext(ctx, "INSERT INTO gift_cards (code, initial_amount, balance, expires_at, purchaser_id, order_id, issued_by, note) VALUES (:code, :initial_amount, :balance, :expires_at, :purchaser_id, :order_id, :issued_by, :note)", g)
+	if err != nil {
+		if isMySQLError(err, mysqlErrDupEntry) {
+			return ErrGiftCardExists
+		}
+		return fmt.Errorf("error inserting gift card: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert ID: %w", err)
+	}
+	g.ID = id
+
+	err = applyGiftCardTransaction(ctx, tx, issueTransaction(g))
+	if err != nil {
+		return err
+	}
+	g.Balance = g.InitialAmount
+
+	return nil
+}
+
+func (ms *MySQLStorer) GetGiftCard(ctx context.Context, id int64) (*GiftCard, error) {
+	var g GiftCard
+	err := ms.db.GetContext(ctx, &g, "SELECT * FROM gift_cards WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting gift card: %w", err)
+	}
+
+	return &g, nil
+}
+
+func (ms *MySQLStorer) GetGiftCardByCode(ctx context.Context, code string) (*GiftCard, error) {
+	var g GiftCard
+	err := ms.db.GetContext(ctx, &g, "SELECT * FROM gift_cards WHERE code=?", code)
+	if err != nil {
+		return nil, fmt.Errorf("error getting gift card: %w", err)
+	}
+
+	return &g, nil
+}
+
+func (ms *MySQLStorer) ListUserGiftCards(ctx context.Context, purchaserID int64) ([]*GiftCard, error) {
+	var cards []*GiftCard
+	err := ms.db.SelectContext(ctx, &cards, "SELECT * FROM gift_cards WHERE purchaser_id=? ORDER BY id DESC", purchaserID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing gift cards: %w", err)
+	}
+
+	return cards, nil
+}
+
+func (ms *MySQLStorer) ListOrderGiftCards(ctx context.Context, orderID int64) ([]*GiftCard, error) {
+	var cards []*GiftCard
+	err := ms.db.SelectContext(ctx, &cards, "SELECT * FROM gift_cards WHERE order_id=? ORDER BY id", orderID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing gift cards: %w", err)
+	}
+
+	return cards, nil
+}
+
+func (ms *MySQLStorer) UpdateGiftCard(ctx context.Context, g *GiftCard) (*GiftCard, error) {
+	_, err := ms.db.NamedExecContext(ctx, "UPDATE gift_cards SET expires_at=:expires_at, note=:note, updated_at=:updated_at WHERE id=:id", g)
+	if err != nil {
+		return nil, fmt.Errorf("error updating gift card: %w", err)
+	}
+
+	return g, nil
+}
+
+func (ms *MySQLStorer) RecordGiftCardTransaction(ctx context.Context, t *GiftCardTransaction) error {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		return applyGiftCardTransaction(ctx, tx, t)
+	})
+	if err != nil {
+		return fmt.Errorf("error recording gift card transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (ms *MySQLStorer) ListGiftCardTransactions(ctx context.Context, giftCardID int64) ([]*GiftCardTransaction, error) {
+	var txs []*GiftCardTransaction
+	err := ms.db.SelectContext(ctx, &txs, "SELECT * FROM gift_card_transactions WHERE gift_card_id=? ORDER BY id", giftCardID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing gift card transactions: %w", err)
+	}
+
+	return txs, nil
+}
+
+// applyGiftCardTransaction locks the card, checks the transaction leaves it
+// a balance and applies it.
+func applyGiftCardTransaction(ctx context.Context, tx *sqlx.Tx, t *GiftCardTransaction) error {
+	var balance money.Money
+	err := tx.GetContext(ctx, &balance, "SELECT balance FROM gift_cards WHERE id=? FOR UPDATE", t.GiftCardID)
+	if err != nil {
+		return fmt.Errorf("error locking gift card: %w", err)
+	}
+	if balance.Add(t.Amount).Amount < 0 {
+		return ErrInsufficientBalance
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE gift_cards SET balance=balance+? WHERE id=?", t.Amount, t.GiftCardID)
+	if err != nil {
+		return fmt.Errorf("error updating gift card balance: %w", err)
+	}
+
+	res, err := tx.NamedExecContext(ctx, "INSERT INTO gift_card_transactions (gift_card_id, kind, amount, order_id, actor_id, reason) VALUES (:gift_card_id, :kind, :amount, :order_id, :actor_id, :reason)", t)
+	if err != nil {
+		return fmt.Errorf("error inserting gift card transaction: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert ID: %w", err)
+	}
+	t.ID = id
+
+	return nil
+}
+
+// cancelOrderGiftCards takes what is left off the gift cards bought in an
+// order, so they cannot be spent after their money is given back.
+func cancelOrderGiftCards(ctx context.Context, tx *sqlx.Tx, orderID int64, reason string) error {
+	var cards []*GiftCard
+	err := tx.SelectContext(ctx, &cards, "SELECT * FROM gift_cards WHERE order_id=? ORDER BY id FOR UPDATE", orderID)
+	if err != nil {
+		return fmt.Errorf("error locking gift cards: %w", err)
+	}
+
+	for _, g := range cards {
+		if g.Balance.Amount <= 0 {
+			continue
+		}
+		err = applyGiftCardTransaction(ctx, tx, &GiftCardTransaction{
+			GiftCardID: g.ID,
+			Kind:       BalanceAdjustment,
+			Amount:     g.Balance.Mul(-1),
+			OrderID:    &orderID,
+			Reason:     reason,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ms *MySQLStorer) GetStoreCredit(ctx context.Context, userID int64) (money.Money, error) {
+	var balance money.Money
+	err := ms.db.GetContext(ctx, &balance, "SELECT balance FROM store_credits WHERE user_id=?", userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return money.New(0, money.DefaultCurrency), nil
+	}
+	if err != nil {
+		return money.Money{}, fmt.Errorf("error getting store credit: %w", err)
+	}
+
+	return balance, nil
+}
+
+func (ms *MySQLStorer) RecordStoreCreditTransaction(ctx context.Context, t *StoreCreditTransaction) error {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		return applyStoreCreditTransaction(ctx, tx, t)
+	})
+	if err != nil {
+		return fmt.Errorf("error recording store credit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (ms *MySQLStorer) ListStoreCreditTransactions(ctx context.Context, userID int64) ([]*StoreCreditTransaction, error) {
+	var txs []*StoreCreditTransaction
+	err := ms.db.SelectContext(ctx, &txs, "SELECT * FROM store_credit_transactions WHERE user_id=? ORDER BY id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing store credit transactions: %w", err)
+	}
+
+	return txs, nil
+}
+
+// applyStoreCreditTransaction locks the user's store credit, checks the
+// transaction leaves it a balance and applies it. The first transaction of
+// a user opens their store credit.
+func applyStoreCreditTransaction(ctx context.Context, tx *sqlx.Tx, t *StoreCreditTransaction) error {
+	balance := money.New(0, money.DefaultCurrency)
+	err := tx.GetContext(ctx, &balance, "SELECT balance FROM store_credits WHERE user_id=? FOR UPDATE", t.UserID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("error locking store credit: %w", err)
+	}
+	if balance.Add(t.Amount).Amount < 0 {
+		return ErrInsufficientBalance
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO store_credits (user_id, balance) VALUES (?, ?) ON DUPLICATE KEY UPDATE balance=balance+VALUES(balance), updated_at=CURRENT_TIMESTAMP", t.UserID, t.Amount)
+	if err != nil {
+		return fmt.Errorf("error updating store credit: %w", err)
+	}
+
+	res, err := tx.NamedExecContext(ctx, "INSERT INTO store_credit_transactions (user_id, kind, amount, order_id, actor_id, reason) VALUES (:user_id, :kind, :amount, :order_id, :actor_id, :reason)", t)
+	if err != nil {
+		return fmt.Errorf("error inserting store credit transaction: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert ID: %w", err)
+	}
+	t.ID = id
+
+	return nil
+}
+
+func (ms *MySQLStorer) GetLoyaltyBalance(ctx context.Context, userID int64) (int64, error) {
+	var balance int64
+	err := ms.db.GetContext(ctx, &balance, "SELECT balance FROM loyalty_accounts WHERE user_id=?", userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("error getting loyalty balance: %w", err)
+	}
+
+	return balance, nil
+}
+
+func (ms *MySQLStorer) GetLoyaltySpend(ctx context.Context, userID int64, since time.Time) (money.Money, error) {
+	var spend money.Money
+	err := ms.db.GetContext(ctx, &spend, "SELECT COALESCE(SUM(spend), 0) FROM loyalty_transactions WHERE user_id=? AND created_at>=?", userID, since)
+	if err != nil {
+		return money.Money{}, fmt.Errorf("error getting loyalty spend: %w", err)
+	}
+
+	return spend, nil
+}
+
+func (ms *MySQLStorer) RecordLoyaltyTransaction(ctx context.Context, t *LoyaltyTransaction) error {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		return applyLoyaltyTransaction(ctx, tx, t)
+	})
+	if err != nil {
+		return fmt.Errorf("error recording loyalty transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (ms *MySQLStorer) ExpireLoyaltyPoints(ctx context.Context, userID int64) error {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := lockLoyaltyAccount(ctx, tx, userID); err != nil {
+			return err
+		}
+		_, err := expireLoyaltyPoints(ctx, tx, userID)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error expiring loyalty points: %w", err)
+	}
+
+	return nil
+}
+
+func (ms *MySQLStorer) ListLoyaltyTransactions(ctx context.Context, userID int64) ([]*LoyaltyTransaction, error) {
+	var txs []*LoyaltyTransaction
+	err := ms.db.SelectContext(ctx, &txs, "SELECT * FROM loyalty_transactions WHERE user_id=? ORDER BY id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing loyalty transactions: %w", err)
+	}
+
+	return txs, nil
+}
+
+// applyLoyaltyTransaction locks the user's points, lets lapsed ones expire,
+// checks a redemption leaves them a balance and applies the transaction.
+func applyLoyaltyTransaction(ctx context.Context, tx *sqlx.Tx, t *LoyaltyTransaction) error {
+	balance, err := lockLoyaltyAccount(ctx, tx, t.UserID)
+	if err != nil {
+		return err
+	}
+	expired, err := expireLoyaltyPoints(ctx, tx, t.UserID)
+	if err != nil {
+		return err
+	}
+	if t.Kind == BalanceRedeem && balance-expired+t.Points < 0 {
+		return ErrInsufficientBalance
+	}
+
+	return insertLoyaltyTransaction(ctx, tx, t)
+}
+
+// lockLoyaltyAccount locks the points of a user, who has none until their
+// first transaction, and returns them.
+func lockLoyaltyAccount(ctx context.Context, tx *sqlx.Tx, userID int64) (int64, error) {
+	var balance int64
+	err := tx.GetContext(ctx, &balance, "SELECT balance FROM loyalty_accounts WHERE user_id=? FOR UPDATE", userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("error locking loyalty account: %w", err)
+	}
+
+	return balance, nil
+}
+
+// expireLoyaltyPoints records the expiry of the points of a locked user that
+// lapsed unspent, and returns how many did.
+func expireLoyaltyPoints(ctx context.Context, tx *sqlx.Tx, userID int64) (int64, error) {
+	var sums struct {
+		Lapsed int64 `db:"lapsed"`
+		Used   int64 `db:"used"`
+	}
+	err := tx.GetContext(ctx, &sums, "SELECT COALESCE(SUM(CASE WHEN points>0 AND expires_at<=? THEN points ELSE 0 END), 0) AS lapsed, COALESCE(SUM(CASE WHEN points<0 THEN -points ELSE 0 END), 0) AS used FROM loyalty_transactions WHERE user_id=?", time.Now(), userID)
+	if err != nil {
+		return 0, fmt.Errorf("error summing loyalty transactions: %w", err)
+	}
+
+	t := expireTransaction(userID, sums.Lapsed, sums.Used)
+	if t == nil {
+		return 0, nil
+	}
+	if err := insertLoyaltyTransaction(ctx, tx, t); err != nil {
+		return 0, err
+	}
+
+	return -t.Points, nil
+}
+
+// insertLoyaltyTransaction appends t to the ledger and applies it to the
+// balance, opening the user's account with their first transaction.
+func insertLoyaltyTransaction(ctx context.Context, tx *sqlx.Tx, t *LoyaltyTransaction) error {
+	_, err := tx.ExecContext(ctx, "INSERT INTO loyalty_accounts (user_id, balance) VALUES (?, ?) ON DUPLICATE KEY UPDATE balance=balance+VALUES(balance), updated_at=CURRENT_TIMESTAMP", t.UserID, t.Points)
+	if err != nil {
+		return fmt.Errorf("error updating loyalty account: %w", err)
+	}
+
+	res, err := tx.NamedExecContext(ctx, "INSERT INTO loyalty_transactions (user_id, kind, points, spend, order_id, reason, expires_at) VALUES (:user_id, :kind, :points, :spend, :order_id, :reason, :expires_at)", t)
+	if err != nil {
+		return fmt.Errorf("error inserting loyalty transaction: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert ID: %w", err)
+	}
+	t.ID = id
+
+	return nil
+}
+
+// redeemTenders takes the tenders of an order off their gift cards, the
+// customer's store credit and loyalty points and records them as captured
+// payments.
+func redeemTenders(ctx context.Context, tx *sqlx.Tx, o *Order) error {
+	for _, p := range o.Tenders {
+		p.OrderID = o.ID
+		p.Status = payments.StatusCaptured
+		p.RefundedAmount = money.New(0, p.Amount.Currency)
+		reason := fmt.Sprintf("order %d", o.ID)
+
+		switch p.Provider {
+		case GiftCardProvider:
+			var id int64
+			err := tx.GetContext(ctx, &id, "SELECT id FROM gift_cards WHERE code=?", p.Reference)
+			if err != nil {
+				return fmt.Errorf("error getting gift card: %w", err)
+			}
+			err = applyGiftCardTransaction(ctx, tx, &GiftCardTransaction{
+				GiftCardID: id,
+				Kind:       BalanceRedeem,
+				Amount:     money.New(-p.Amount.Amount, p.Amount.Currency),
+				OrderID:    &o.ID,
+				ActorID:    &o.UserID,
+				Reason:     reason,
+			})
+			if err != nil {
+				return err
+			}
+		case StoreCreditProvider:
+			err := applyStoreCreditTransaction(ctx, tx, &StoreCreditTransaction{
+				UserID:  o.UserID,
+				Kind:    BalanceRedeem,
+				Amount:  money.New(-p.Amount.Amount, p.Amount.Currency),
+				OrderID: &o.ID,
+				ActorID: &o.UserID,
+				Reason:  reason,
+			})
+			if err != nil {
+				return err
+			}
+		case LoyaltyPointsProvider:
+			err := applyLoyaltyTransaction(ctx, tx, &LoyaltyTransaction{
+				UserID:  o.UserID,
+				Kind:    BalanceRedeem,
+				Points:  -p.LoyaltyPoints(),
+				Spend:   money.New(0, p.Amount.Currency),
+				OrderID: &o.ID,
+				Reason:  reason,
+			})
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("error redeeming tender: unknown provider %q", p.Provider)
+		}
+
+		if err := insertPayment(ctx, tx, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateInvoice numbers the invoice in the same transaction as it is
+// inserted, so an order that turns out to be invoiced already gives its
+// number back.
+func (ms *MySQLStorer) CreateInvoice(ctx context.Context, inv *Invoice) (*Invoice, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		n, err := nextDocumentNumber(ctx, tx, invoiceSequence)
+		if err != nil {
+			return err
+		}
+		inv.Number = documentNumber(invoicePrefix, n)
+
+		res, err := tx.NamedExecContext(ctx, "INSERT INTO invoices (number, order_id, user_id, seller, buyer_name, buyer_email, billing_address, shipping_address, items, items_price, discount_price, shipping_price, tax_price, total_price, issued_at) VALUES (:number, :order_id, :user_id, :seller, :buyer_name, :buyer_email, :billing_address, :shipping_address, :items, :items_price, :discount_price, :shipping_price, :tax_price, :total_price, :issued_at)", inv)
+		if err != nil {
+			if isMySQLError(err, mysqlErrDupEntry) {
+				return ErrInvoiceExists
+			}
+			return fmt.Errorf("error inserting invoice: %w", err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("error getting last insert ID: %w", err)
+		}
+		inv.ID = id
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating invoice: %w", err)
+	}
+
+	return inv, nil
+}
+
+func (ms *MySQLStorer) GetOrderInvoice(ctx context.Context, orderID int64) (*Invoice, error) {
+	var inv Invoice
+	err := ms.db.GetContext(ctx, &inv, "SELECT * FROM invoices WHERE order_id=?", orderID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting invoice: %w", err)
+	}
+
+	return &inv, nil
+}
+
+func (ms *MySQLStorer) CreateCreditNote(ctx context.Context, cn *CreditNote) (*CreditNote, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		return insertCreditNote(ctx, tx, cn)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating credit note: %w", err)
+	}
+
+	return cn, nil
+}
+
+// insertCreditNote numbers and inserts a credit note within tx.
+func insertCreditNote(ctx context.Context, tx *sqlx.Tx, cn *CreditNote) error {
+	n, err := nextDocumentNumber(ctx, tx, creditNoteSequence)
+	if err != nil {
+		return err
+	}
+	cn.Number = documentNumber(creditNotePrefix, n)
+
+	res, err := tx.NamedExecContext(ctx, "INSERT INTO credit_notes (number, invoice_id, reason, items, items_price, discount_price, shipping_price, tax_price, total_price, issued_at) VALUES (:number, :invoice_id, :reason, :items, :items_price, :discount_price, :shipping_price, :tax_price, :total_price, :issued_at)", cn)
+	if err != nil {
+		return fmt.Errorf("error inserting credit note: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert ID: %w", err)
+	}
+	cn.ID = id
+
+	return nil
+}
+
+func (ms *MySQLStorer) ListInvoiceCreditNotes(ctx context.Context, invoiceID int64) ([]*CreditNote, error) {
+	var notes []*CreditNote
+	err := ms.db.SelectContext(ctx, &notes, "SELECT * FROM credit_notes WHERE invoice_id=? ORDER BY id", invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing credit notes: %w", err)
+	}
+
+	return notes, nil
+}
+
+// nextDocumentNumber takes the next number of a document sequence. The
+// sequence stays locked until tx ends, so numbers are used in the order they
+// are taken and a rolled back transaction leaves no gap.
+func nextDocumentNumber(ctx context.Context, tx *sqlx.Tx, sequence string) (int64, error) {
+	res, err := tx.ExecContext(ctx, "UPDATE document_sequences SET last_number=LAST_INSERT_ID(last_number+1) WHERE name=?", sequence)
+	if err != nil {
+		return 0, fmt.Errorf("error taking %s number: %w", sequence, err)
+	}
+
+	n, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("error taking %s number: %w", sequence, err)
+	}
+
+	return n, nil
+}
+
+// CreateReturn locks the order item while counting the units already being
+// returned, so concurrent returns cannot return more units than were ordered.
+func (ms *MySQLStorer) CreateReturn(ctx context.Context, r *Return, ne *NotificationEvent) (*Return, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		var ordered int64
+		err := tx.GetContext(ctx, &ordered, "SELECT quantity FROM order_items WHERE id=? AND order_id=? FOR UPDATE", r.OrderItemID, r.OrderID)
+		if err != nil {
+			return fmt.Errorf("error locking order item: %w", err)
+		}
+
+		var returned int64
+		err = tx.GetContext(ctx, &returned, "SELECT COALESCE(SUM(quantity), 0) FROM returns WHERE order_item_id=? AND status<>'rejected'", r.OrderItemID)
+		if err != nil {
+			return fmt.Errorf("error counting returned units: %w", err)
+		}
+		if returned+r.Quantity > ordered {
+			return fmt.Errorf("%d of %d units already returned: %w", returned, ordered, ErrReturnQuantityExceeded)
+		}
+
+		res, err := tx.NamedExecContext(ctx, "INSERT INTO returns (order_id, order_item_id, user_id, quantity, reason, photos_url, status, refund_amount) VALUES (:order_id, :order_item_id, :user_id, :quantity, :reason, :photos_url, :status, :refund_amount)", r)
+		if err != nil {
+			return fmt.Errorf("error inserting return: %w", err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("error getting last insert ID: %w", err)
+		}
+		r.ID = id
+
+		if ne != nil {
+			ne.OrderID = r.OrderID
+			ne.ReturnID = &r.ID
+			ne.ReturnStatus = r.Status
+			err = enqueueNotificationEvent(ctx, tx, ne)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating return: %w", err)
+	}
+
+	return r, nil
+}
+
+func (ms *MySQLStorer) GetReturn(ctx context.Context, id int64) (*Return, error) {
+	var r Return
+	err := ms.db.GetContext(ctx, &r, "SELECT * FROM returns WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting return: %w", err)
+	}
+
+	return &r, nil
+}
+
+func (ms *MySQLStorer) ListOrderReturns(ctx context.Context, orderID int64) ([]*Return, error) {
+	var returns []*Return
+	err := ms.db.SelectContext(ctx, &returns, "SELECT * FROM returns WHERE order_id=? ORDER BY id", orderID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing returns: %w", err)
+	}
+
+	return returns, nil
+}
+
+func (ms *MySQLStorer) ListReturns(ctx context.Context, status ReturnStatus) ([]*Return, error) {
+	q, args := "SELECT * FROM returns ORDER BY id", []any{}
+	if status != "" {
+		q, args = "SELECT * FROM returns WHERE status=? ORDER BY id", []any{status}
+	}
+
+	var returns []*Return
+	err := ms.db.SelectContext(ctx, &returns, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing returns: %w", err)
+	}
+
+	return returns, nil
+}
+
+// UpdateReturnStatus enqueues ne in the same transaction, like
+// UpdateOrderStatus.
+func (ms *MySQLStorer) UpdateReturnStatus(ctx context.Context, r *Return, from ReturnStatus, ne *NotificationEvent) (*Return, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		var current ReturnStatus
+		err := tx.GetContext(ctx, &current, "SELECT status FROM returns WHERE id=? FOR UPDATE", r.ID)
+		if err != nil {
+			return fmt.Errorf("error locking return: %w", err)
+		}
+
+		if current != from {
+			return fmt.Errorf("return %d is %s, not %s: %w", r.ID, current, from, ErrReturnStatusChanged)
+		}
+
+		if r.Status == ReturnReceived && r.Restocked {
+			var item OrderItem
+			err = tx.GetContext(ctx, &item, "SELECT * FROM order_items WHERE id=?", r.OrderItemID)
+			if err != nil {
+				return fmt.Errorf("error getting order item: %w", err)
+			}
+			item.Quantity = r.Quantity
+
+			err = applyStockMovements(ctx, tx, restockMovements([]OrderItem{item}, StockReturn, fmt.Sprintf("return %d received", r.ID), nil))
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.NamedExecContext(ctx, "UPDATE returns SET status=:status, note=:note, restocked=:restocked, updated_at=:updated_at WHERE id=:id", r)
+		if err != nil {
+			return fmt.Errorf("error updating return: %w", err)
+		}
+
+		if r.CreditNote != nil {
+			err = insertCreditNote(ctx, tx, r.CreditNote)
+			if err != nil {
+				return err
+			}
+		}
+
+		if r.Loyalty != nil {
+			err = applyLoyaltyTransaction(ctx, tx, r.Loyalty)
+			if err != nil {
+				return err
+			}
+		}
+
+		if ne != nil {
+			ne.OrderID = r.OrderID
+			ne.ReturnID = &r.ID
+			ne.ReturnStatus = r.Status
+			err = enqueueNotificationEvent(ctx, tx, ne)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error updating return status: %w", err)
+	}
+
+	return r, nil
+}
+
+func (ms *MySQLStorer) execTx(ctx context.Context, fn func(*sqlx.Tx) error) error {
+	tx, err := ms.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error inserting product: %w", err)
+		return fmt.Errorf("error starting transaction: %w", err)
 	}
 
-	id, err := res.LastInsertId()
+	err = fn(tx)
 	if err != nil {
-		return nil, fmt.Errorf("error getting last inserted ID: %w", err)
+		rbErr := tx.Rollback()
+		if rbErr != nil {
+			return fmt.Errorf("transaction error: %v; rollback error: %w", err, rbErr)
+		}
+		return fmt.Errorf("error in transaction: %w", err)
 	}
-	p.ID = id
 
-	return p, nil
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
 }
 
-func (ms *MySQLStorer) GetProduct(ctx context.Context, id int64) (*Product, error) {
-	var p Product
-	err := ms.db.GetContext(ctx, &p, "SELECT * FROM products WHERE id=?", id)
+// GetCart returns the user's cart with its items in the order they were
+// added. A user who never added anything gets an empty cart with ID 0.
+func (ms *MySQLStorer) GetCart(ctx context.Context, userID int64) (*Cart, error) {
+	var c Cart
+	err := ms.db.GetContext(ctx, &c, "SELECT * FROM carts WHERE user_id=?", userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &Cart{UserID: userID}, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("error getting product: %w", err)
+		return nil, fmt.Errorf("error getting cart: %w", err)
 	}
-	return &p, nil
+
+	err = ms.db.SelectContext(ctx, &c.Items, "SELECT * FROM cart_items WHERE cart_id=? ORDER BY id", c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting cart items: %w", err)
+	}
+
+	return &c, nil
 }
 
-func (ms *MySQLStorer) ListProducts(ctx context.Context) ([]*Product, error) {
-	var products []*Product
-	err := ms.db.SelectContext(ctx, &products, "SELECT * FROM products")
+// SetCartItem sets the quantity of a product or variant in the user's cart,
+// creating the cart and the item as needed.
+func (ms *MySQLStorer) SetCartItem(ctx context.Context, userID, productID, variantID, quantity int64) error {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		now := time.Now()
+		// LAST_INSERT_ID(id) makes the id of an existing cart available as
+		// the insert id
+		res, err := tx.ExecContext(ctx, "INSERT INTO carts (user_id) VALUES (?) ON DUPLICATE KEY UPDATE id=LAST_INSERT_ID(id), updated_at=?", userID, now)
+		if err != nil {
+			return fmt.Errorf("error creating cart: %w", err)
+		}
+		cartID, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("error getting cart id: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, "INSERT INTO cart_items (cart_id, product_id, variant_id, quantity) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE quantity=VALUES(quantity), updated_at=?", cartID, productID, variantID, quantity, now)
+		if err != nil {
+			return fmt.Errorf("error setting cart item: %w", err)
+		}
+
+		return nil
+	})
+
 	if err != nil {
-		return nil, fmt.Errorf("error listing products: %w", err)
+		return fmt.Errorf("error setting cart item: %w", err)
 	}
 
-	return products, nil
+	return nil
 }
 
-func (ms *MySQLStorer) UpdateProduct(ctx context.Context, p *Product) (*Product, error) {
-	_, err := ms.db.NamedExecContext(ctx, "UPDATE products SET name=:name, image=:image, category=:category, description=:description, rating=:rating, num_reviews=:num_reviews, price=:price, count_in_stock=:count_in_stock, updated_at=:updated_at WHERE id=:id", p)
+func (ms *MySQLStorer) RemoveCartItem(ctx context.Context, userID, productID, variantID int64) error {
+	res, err := ms.db.ExecContext(ctx, "DELETE cart_items FROM cart_items JOIN carts ON carts.id=cart_items.cart_id WHERE carts.user_id=? AND cart_items.product_id=? AND cart_items.variant_id=?", userID, productID, variantID)
 	if err != nil {
-		return nil, fmt.Errorf("error updating product: %w", err)
+		return fmt.Errorf("error removing cart item: %w", err)
 	}
 
-	return p, nil
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("error removing cart item: %w", sql.ErrNoRows)
+	}
+
+	return nil
 }
 
-func (ms *MySQLStorer) DeleteProduct(ctx context.Context, id int64) error {
-	_, err := ms.db.ExecContext(ctx, "DELETE FROM products WHERE id=?", id)
+func (ms *MySQLStorer) ClearCart(ctx context.Context, userID int64) error {
+	_, err := ms.db.ExecContext(ctx, "DELETE cart_items FROM cart_items JOIN carts ON carts.id=cart_items.cart_id WHERE carts.user_id=?", userID)
 	if err != nil {
-		return fmt.Errorf("error deleting product: %w", err)
+		return fmt.Errorf("error clearing cart: %w", err)
 	}
 
 	return nil
 }
 
-func (ms *MySQLStorer) CreateOrder(ctx context.Context, o *Order) (*Order, error) {
-	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
-		order, err := createOrder(ctx, tx, o)
-		if err != nil {
-			return fmt.Errorf("error creating order: %w", err)
+func (ms *MySQLStorer) CreateWishlist(ctx context.Context, w *Wishlist) (*Wishlist, error) {
+	res, err := ms.db.NamedExecContext(ctx, "INSERT INTO wishlists (user_id, name, share_token) VALUES (:user_id, :name, :share_token)", w)
+	if err != nil {
+		if isMySQLError(err, mysqlErrDupEntry) {
+			return nil, ErrWishlistExists
 		}
+		return nil, fmt.Errorf("error inserting wishlist: %w", err)
+	}
 
-		for _, oi := range order.Items {
-			oi.OrderID = order.ID
-			id, err := createOrderItem(ctx, tx, oi)
-			if err != nil {
-				return fmt.Errorf("error creating order item: %w", err)
-			}
-			oi.ID = *id
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("error getting last insert ID: %w", err)
+	}
+	w.ID = id
 
-		}
+	return w, nil
+}
 
-		return nil
-	})
+func (ms *MySQLStorer) GetWishlist(ctx context.Context, id int64) (*Wishlist, error) {
+	var w Wishlist
+	err := ms.db.GetContext(ctx, &w, "SELECT * FROM wishlists WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting wishlist: %w", err)
+	}
+
+	return ms.withWishlistItems(ctx, &w)
+}
 
+func (ms *MySQLStorer) GetWishlistByShareToken(ctx context.Context, token string) (*Wishlist, error) {
+	var w Wishlist
+	err := ms.db.GetContext(ctx, &w, "SELECT * FROM wishlists WHERE share_token=?", token)
 	if err != nil {
-		return nil, fmt.Errorf("error creating order: %w", err)
+		return nil, fmt.Errorf("error getting wishlist: %w", err)
 	}
 
-	return o, nil
+	return ms.withWishlistItems(ctx, &w)
 }
 
-func createOrder(ctx context.Context, tx *sqlx.Tx, o *Order) (*Order, error) {
-	res, err := tx.NamedExecContext(ctx, "INSERT INTO orders (payment_method, tax_price, shipping_price, total_price, user_id) VALUES (:payment_method, :tax_price, :shipping_price, :total_price, :user_id)", o)
+func (ms *MySQLStorer) withWishlistItems(ctx context.Context, w *Wishlist) (*Wishlist, error) {
+	err := ms.db.SelectContext(ctx, &w.Items, "SELECT * FROM wishlist_items WHERE wishlist_id=? ORDER BY id", w.ID)
 	if err != nil {
-		return nil, fmt.Errorf("error inserting order: %w", err)
+		return nil, fmt.Errorf("error getting wishlist items: %w", err)
 	}
 
-	id, err := res.LastInsertId()
+	return w, nil
+}
+
+// ListUserWishlists returns the user's wishlists in the order they were
+// created.
+func (ms *MySQLStorer) ListUserWishlists(ctx context.Context, userID int64) ([]*Wishlist, error) {
+	var wishlists []*Wishlist
+	err := ms.db.SelectContext(ctx, &wishlists, "SELECT * FROM wishlists WHERE user_id=? ORDER BY id", userID)
 	if err != nil {
-		return nil, fmt.Errorf("error getting last insert id: %w", err)
+		return nil, fmt.Errorf("error listing wishlists: %w", err)
 	}
-	o.ID = id
 
-	return o, nil
+	var items []WishlistItem
+	err = ms.db.SelectContext(ctx, &items, "SELECT wishlist_items.* FROM wishlist_items JOIN wishlists ON wishlists.id=wishlist_items.wishlist_id WHERE wishlists.user_id=? ORDER BY wishlist_items.id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing wishlist items: %w", err)
+	}
+
+	byID := make(map[int64]*Wishlist, len(wishlists))
+	for _, w := range wishlists {
+		byID[w.ID] = w
+	}
+	for _, i := range items {
+		if w, ok := byID[i.WishlistID]; ok {
+			w.Items = append(w.Items, i)
+		}
+	}
+
+	return wishlists, nil
 }
 
-func createOrderItem(ctx context.Context, tx *sqlx.Tx, oi OrderItem) (*int64, error) {
-	res, err := tx.NamedExecContext(ctx, "INSERT INTO order_items (name, quantity, image, price, product_id, order_id) VALUES (:name, :quantity, :image, :price, :product_id, :order_id)", oi)
+func (ms *MySQLStorer) UpdateWishlist(ctx context.Context, w *Wishlist) (*Wishlist, error) {
+	_, err := ms.db.NamedExecContext(ctx, "UPDATE wishlists SET name=:name, share_token=:share_token, updated_at=:updated_at WHERE id=:id", w)
 	if err != nil {
-		return nil, fmt.Errorf("error inserting order item: %w", err)
+		if isMySQLError(err, mysqlErrDupEntry) {
+			return nil, ErrWishlistExists
+		}
+		return nil, fmt.Errorf("error updating wishlist: %w", err)
 	}
 
-	id, err := res.LastInsertId()
+	return w, nil
+}
+
+// DeleteWishlist deletes a wishlist; its items go with it.
+func (ms *MySQLStorer) DeleteWishlist(ctx context.Context, id int64) error {
+	_, err := ms.db.ExecContext(ctx, "DELETE FROM wishlists WHERE id=?", id)
 	if err != nil {
-		return nil, fmt.Errorf("error getting last insert id: %w", err)
+		return fmt.Errorf("error deleting wishlist: %w", err)
 	}
 
-	return &id, nil
+	return nil
 }
 
-func (ms *MySQLStorer) GetOrder(ctx context.Context, userID int64) (*Order, error) {
-	var o Order
-	err := ms.db.GetContext(ctx, &o, "SELECT * FROM orders WHERE user_id=?", userID)
+func (ms *MySQLStorer) AddWishlistItem(ctx context.Context, wishlistID, productID, variantID int64) error {
+	_, err := ms.db.ExecContext(ctx, "INSERT INTO wishlist_items (wishlist_id, product_id, variant_id) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE id=id", wishlistID, productID, variantID)
 	if err != nil {
-		return nil, fmt.Errorf("error getting order: %w", err)
+		return fmt.Errorf("error adding wishlist item: %w", err)
 	}
 
-	var items []OrderItem
-	err = ms.db.SelectContext(ctx, &items, "SELECT * FROM order_items WHERE order_id=?", o.ID)
+	return nil
+}
+
+func (ms *MySQLStorer) RemoveWishlistItem(ctx context.Context, wishlistID, productID, variantID int64) error {
+	res, err := ms.db.ExecContext(ctx, "DELETE FROM wishlist_items WHERE wishlist_id=? AND product_id=? AND variant_id=?", wishlistID, productID, variantID)
 	if err != nil {
-		return nil, fmt.Errorf("error getting order items: %w", err)
+		return fmt.Errorf("error removing wishlist item: %w", err)
 	}
-	o.Items = items
 
-	return &o, nil
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("error removing wishlist item: %w", sql.ErrNoRows)
+	}
+
+	return nil
 }
 
-func (ms *MySQLStorer) GetOrderStatusByID(ctx context.Context, id int64) (*Order, error) {
-	var o Order
-	err := ms.db.GetContext(ctx, &o, "SELECT id, user_id, status FROM orders WHERE id=?", id)
+// CreateReview inserts a review and recomputes the product's rating in the
+// same transaction. A second review of the same product by the same user
+// fails with ErrReviewExists.
+func (ms *MySQLStorer) CreateReview(ctx context.Context, r *Review) (*Review, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		res, err := tx.NamedExecContext(ctx, "INSERT INTO reviews (product_id, user_id, rating, title, body) VALUES (:product_id, :user_id, :rating, :title, :body)", r)
+		if err != nil {
+			if isMySQLError(err, mysqlErrDupEntry) {
+				return ErrReviewExists
+			}
+			return fmt.Errorf("error inserting review: %w", err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("error getting last insert ID: %w", err)
+		}
+		r.ID = id
+
+		return updateProductRating(ctx, tx, r.ProductID)
+	})
+
 	if err != nil {
-		return nil, fmt.Errorf("error getting order status: %w", err)
+		return nil, fmt.Errorf("error creating review: %w", err)
 	}
 
-	return &o, nil
+	return r, nil
 }
 
-func (ms *MySQLStorer) ListOrders(ctx context.Context) ([]*Order, error) {
-	var orders []*Order
-	err := ms.db.SelectContext(ctx, &orders, "SELECT * FROM orders")
+func (ms *MySQLStorer) GetReview(ctx context.Context, id int64) (*Review, error) {
+	var r Review
+	err := ms.db.GetContext(ctx, &r, "SELECT * FROM reviews WHERE id=?", id)
 	if err != nil {
-		return nil, fmt.Errorf("error listing orders: %w", err)
+		return nil, fmt.Errorf("error getting review: %w", err)
 	}
 
-	for i := range orders {
-		var items []OrderItem
-		err := ms.db.SelectContext(ctx, &items, "SELECT * FROM order_items WHERE order_id=?", orders[i].ID)
+	return &r, nil
+}
+
+// ListProductReviews returns a page of a product's reviews, newest first.
+func (ms *MySQLStorer) ListProductReviews(ctx context.Context, params *ListReviewsParams) (*ReviewPage, error) {
+	limit := pageSize(params.Limit)
+	cursor, err := params.cursor()
+	if err != nil {
+		return nil, fmt.Errorf("error listing reviews: %w", err)
+	}
+
+	where := []string{"product_id=?"}
+	args := []interface{}{params.ProductID}
+	if !params.IncludeHidden {
+		where = append(where, "hidden=false")
+	}
+	if cursor != nil {
+		where = append(where, "(created_at<? OR (created_at=? AND id<?))")
+		args = append(args, cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+	}
+
+	q := "SELECT * FROM reviews WHERE " + strings.Join(where, " AND ") + " ORDER BY created_at DESC, id DESC LIMIT ?"
+	args = append(args, limit+1)
+
+	var reviews []*Review
+	err = ms.db.SelectContext(ctx, &reviews, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing reviews: %w", err)
+	}
+
+	return newReviewPage(reviews, limit), nil
+}
+
+func (ms *MySQLStorer) UpdateReview(ctx context.Context, r *Review) (*Review, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		productID, err := lockReview(ctx, tx, r.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NamedExecContext(ctx, "UPDATE reviews SET rating=:rating, title=:title, body=:body, updated_at=:updated_at WHERE id=:id", r)
 		if err != nil {
-			return nil, fmt.Errorf("error getting order items: %w", err)
+			return fmt.Errorf("error updating review: %w", err)
 		}
-		orders[i].Items = items
+
+		return updateProductRating(ctx, tx, productID)
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("error updating review: %w", err)
 	}
 
-	return orders, nil
+	return r, nil
 }
 
-func (ms *MySQLStorer) UpdateOrderStatus(ctx context.Context, o *Order) (*Order, error) {
-	_, err := ms.db.NamedExecContext(ctx, "UPDATE orders SET status=:status, updated_at=:updated_at WHERE id=:id", o)
+// SetReviewHidden hides a review from listings and the product's rating, or
+// shows it again.
+func (ms *MySQLStorer) SetReviewHidden(ctx context.Context, id int64, hidden bool) error {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		productID, err := lockReview(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "UPDATE reviews SET hidden=?, updated_at=? WHERE id=?", hidden, time.Now(), id)
+		if err != nil {
+			return fmt.Errorf("error updating review visibility: %w", err)
+		}
+
+		return updateProductRating(ctx, tx, productID)
+	})
+
 	if err != nil {
-		return nil, fmt.Errorf("error updating order status: %w", err)
+		return fmt.Errorf("error moderating review: %w", err)
 	}
 
-	return o, nil
+	return nil
 }
 
-func (ms *MySQLStorer) DeleteOrder(ctx context.Context, id int64) error {
+func (ms *MySQLStorer) DeleteReview(ctx context.Context, id int64) error {
 	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
-		_, err := tx.ExecContext(ctx, "DELETE FROM order_items WHERE order_id=?", id)
+		productID, err := lockReview(ctx, tx, id)
 		if err != nil {
-			return fmt.Errorf("error deleting order items: %w", err)
+			return err
 		}
 
-		_, err = tx.ExecContext(ctx, "DELETE FROM orders WHERE id=?", id)
+		_, err = tx.ExecContext(ctx, "DELETE FROM reviews WHERE id=?", id)
 		if err != nil {
-			return fmt.Errorf("error deleting order: %w", err)
+			return fmt.Errorf("error deleting review: %w", err)
 		}
 
-		return nil
+		return updateProductRating(ctx, tx, productID)
 	})
 
 	if err != nil {
-		return fmt.Errorf("error deleting order: %w", err)
+		return fmt.Errorf("error deleting review: %w", err)
 	}
 
 	return nil
 }
 
-func (ms *MySQLStorer) execTx(ctx context.Context, fn func(*sqlx.Tx) error) error {
-	tx, err := ms.db.BeginTxx(ctx, nil)
+// HasDeliveredOrder reports whether the user has a delivered order that
+// contains the product.
+func (ms *MySQLStorer) HasDeliveredOrder(ctx context.Context, userID, productID int64) (bool, error) {
+	var ok bool
+	err := ms.db.GetContext(ctx, &ok, "SELECT EXISTS (SELECT 1 FROM orders JOIN order_items ON order_items.order_id=orders.id WHERE orders.user_id=? AND orders.status=? AND order_items.product_id=?)", userID, Delivered, productID)
 	if err != nil {
-		return fmt.Errorf("error starting transaction: %w", err)
+		return false, fmt.Errorf("error checking delivered orders: %w", err)
 	}
 
-	err = fn(tx)
+	return ok, nil
+}
+
+// lockReview locks a review row and returns its product id.
+func lockReview(ctx context.Context, tx *sqlx.Tx, id int64) (int64, error) {
+	var productID int64
+	err := tx.GetContext(ctx, &productID, "SELECT product_id FROM reviews WHERE id=? FOR UPDATE", id)
 	if err != nil {
-		rbErr := tx.Rollback()
-		if rbErr != nil {
-			return fmt.Errorf("transaction error: %v; rollback error: %w", err, rbErr)
-		}
-		return fmt.Errorf("error in transaction: %w", err)
+		return 0, fmt.Errorf("error getting review: %w", err)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("error committing transaction: %w", err)
+	return productID, nil
+}
+
+// updateProductRating recomputes a product's rating, the average of its
+// visible reviews rounded half up to whole stars, and its review count.
+func updateProductRating(ctx context.Context, tx *sqlx.Tx, productID int64) error {
+	_, err := tx.ExecContext(ctx, "UPDATE products SET rating=(SELECT COALESCE(ROUND(AVG(rating)), 0) FROM reviews WHERE product_id=? AND hidden=false), num_reviews=(SELECT COUNT(*) FROM reviews WHERE product_id=? AND hidden=false) WHERE id=?", productID, productID, productID)
+	if err != nil {
+		return fmt.Errorf("error updating product rating: %w", err)
 	}
 
 	return nil
@@ -255,6 +3162,16 @@ func (ms *MySQLStorer) GetUser(ctx context.Context, email string) (*User, error)
 	return &u, nil
 }
 
+func (ms *MySQLStorer) GetUserByID(ctx context.Context, id int64) (*User, error) {
+	var u User
+	err := ms.db.GetContext(ctx, &u, "SELECT * FROM users WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting user: %w", err)
+	}
+
+	return &u, nil
+}
+
 func (ms *MySQLStorer) ListUsers(ctx context.Context) ([]*User, error) {
 	var users []*User
 	err := ms.db.SelectContext(ctx, &users, "SELECT * FROM users")
@@ -274,6 +3191,23 @@ func (ms *MySQLStorer) UpdateUser(ctx context.Context, u *User) (*User, error) {
 	return u, nil
 }
 
+func (ms *MySQLStorer) SetUserTaxExempt(ctx context.Context, id int64, exempt bool) error {
+	res, err := ms.db.ExecContext(ctx, "UPDATE users SET tax_exempt=?, updated_at=? WHERE id=?", exempt, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("error updating user tax exemption: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("error updating user tax exemption: %w", sql.ErrNoRows)
+	}
+
+	return nil
+}
+
 func (ms *MySQLStorer) DeleteUser(ctx context.Context, id int64) error {
 	_, err := ms.db.ExecContext(ctx, "DELETE FROM users WHERE id=?", id)
 	if err != nil {
@@ -335,7 +3269,7 @@ func insertNotificationState(ctx context.Context, tx *sqlx.Tx, es *NotificationS
 }
 
 func insertNotificationEvent(ctx context.Context, tx *sqlx.Tx, u *NotificationEvent) (*NotificationEvent, error) {
-	res, err := tx.NamedExecContext(ctx, "INSERT INTO notification_events_queue (user_email, order_status, order_id, state_id, attempts) VALUES (:user_email, :order_status, :order_id, :state_id, :attempts)", u)
+	res, err := tx.NamedExecContext(ctx, "INSERT INTO notification_events_queue (user_email, order_status, order_id, return_id, return_status, state_id, attempts) VALUES (:user_email, :order_status, :order_id, :return_id, :return_status, :state_id, :attempts)", u)
 	if err != nil {
 		return nil, fmt.Errorf("error inserting notification event: %w", err)
 	}
@@ -349,33 +3283,37 @@ func insertNotificationEvent(ctx context.Context, tx *sqlx.Tx, u *NotificationEv
 	return u, nil
 }
 
-func (ms *MySQLStorer) EnqueueNotificatioEvent(ctx context.Context, ne *NotificationEvent) (*NotificationEvent, error) {
-	var ev *NotificationEvent
+// enqueueNotificationEvent inserts the state row tracking ne and the event
+// itself into the outbox.
+func enqueueNotificationEvent(ctx context.Context, tx *sqlx.Tx, ne *NotificationEvent) error {
+	ns, err := insertNotificationState(ctx, tx, &NotificationState{
+		OrderID: ne.OrderID,
+		State:   NotSent,
+		Message: "",
+	})
+	if err != nil {
+		return fmt.Errorf("error inserting notification: %w", err)
+	}
 
-	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
-		ns, err := insertNotificationState(ctx, tx, &NotificationState{
-			OrderID: ne.OrderID,
-			State:   NotSent,
-			Message: "",
-		})
-		if err != nil {
-			return fmt.Errorf("error inserting notification: %w", err)
-		}
+	ne.StateID = ns.ID
 
-		ne.StateID = ns.ID
+	_, err = insertNotificationEvent(ctx, tx, ne)
+	if err != nil {
+		return fmt.Errorf("error inserting notification event: %w", err)
+	}
 
-		ev, err = insertNotificationEvent(ctx, tx, ne)
-		if err != nil {
-			return fmt.Errorf("error inserting notification event: %w", err)
-		}
+	return nil
+}
 
-		return nil
+func (ms *MySQLStorer) EnqueueNotificatioEvent(ctx context.Context, ne *NotificationEvent) (*NotificationEvent, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		return enqueueNotificationEvent(ctx, tx, ne)
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error enqueueing notification event: %w", err)
 	}
 
-	return ev, nil
+	return ne, nil
 }
 
 func (ms *MySQLStorer) ListNotificationEvents(ctx context.Context) ([]*NotificationEvent, error) {
@@ -390,6 +3328,48 @@ func (ms *MySQLStorer) ListNotificationEvents(ctx context.Context) ([]*Notificat
 	return events, nil
 }
 
+// ClaimNotificationEvents leases up to limit due events to the caller until
+// lease has passed. Events stay in the queue until UpdateNotificationEvent
+// reports them sent, so an event whose relay dies mid-delivery is handed out
+// again once its lease expires. Rows leased by a concurrent relay are skipped.
+func (ms *MySQLStorer) ClaimNotificationEvents(ctx context.Context, limit int, lease time.Duration) ([]*NotificationEvent, error) {
+	var events []*NotificationEvent
+	now := time.Now()
+
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		err := tx.SelectContext(ctx, &events, "SELECT * FROM notification_events_queue WHERE attempts<? AND (locked_until IS NULL OR locked_until<=?) ORDER BY created_at, id LIMIT ? FOR UPDATE SKIP LOCKED", maxAttempts, now, pageSize(limit))
+		if err != nil {
+			return fmt.Errorf("error selecting notification events: %w", err)
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		lockedUntil := now.Add(lease)
+		ids := make([]int64, 0, len(events))
+		for _, ev := range events {
+			ev.LockedUntil = &lockedUntil
+			ids = append(ids, ev.ID)
+		}
+
+		q, args, err := sqlx.In("UPDATE notification_events_queue SET locked_until=? WHERE id IN (?)", lockedUntil, ids)
+		if err != nil {
+			return fmt.Errorf("error building lease query: %w", err)
+		}
+		_, err = tx.ExecContext(ctx, tx.Rebind(q), args...)
+		if err != nil {
+			return fmt.Errorf("error leasing notification events: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error claiming notification events: %w", err)
+	}
+
+	return events, nil
+}
+
 func getNotificationEventAttempts(ctx context.Context, tx *sqlx.Tx, id int64) (*NotificationEvent, error) {
 	var u NotificationEvent
 	err := tx.GetContext(ctx, &u, "SELECT id, attempts FROM notification_events_queue WHERE id=?", id)
@@ -401,7 +3381,7 @@ func getNotificationEventAttempts(ctx context.Context, tx *sqlx.Tx, id int64) (*
 }
 
 func updateNotificationEventAttempts(ctx context.Context, tx *sqlx.Tx, u *NotificationEvent) (*NotificationEvent, error) {
-	_, err := tx.NamedExecContext(ctx, "UPDATE notification_events_queue SET attempts=:attempts, updated_at=:updated_at WHERE id=:id", u)
+	_, err := tx.NamedExecContext(ctx, "UPDATE notification_events_queue SET attempts=:attempts, updated_at=:updated_at, locked_until=:locked_until WHERE id=:id", u)
 	if err != nil {
 		return nil, fmt.Errorf("error updating notification event: %w", err)
 	}
@@ -464,6 +3444,8 @@ func (ms *MySQLStorer) UpdateNotificationEvent(ctx context.Context, ev *Notifica
 				t := time.Now()
 				u.UpdatedAt = &t
 				u.Attempts += 1
+				retryAt := t.Add(retryBackoff(u.Attempts))
+				u.LockedUntil = &retryAt
 
 				_, err = updateNotificationEventAttempts(ctx, tx, u)
 				if err != nil {
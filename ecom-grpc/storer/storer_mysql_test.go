@@ -2,10 +2,15 @@ package storer
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"github.com/OrkhanMehbaliyev/ecom-golang/payments"
+	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/require"
 )
@@ -22,15 +27,31 @@ func withTestDB(t *testing.T, fn func(db *sqlx.DB, mock sqlmock.Sqlmock)) {
 	fn(db, mock)
 }
 
+// expectStockMovement expects m to be applied to the stock of its product,
+// its variant and its stock level and appended to the ledger.
+func expectStockMovement(mock sqlmock.Sqlmock, m StockMovement) {
+	mock.ExpectExec("UPDATE products SET count_in_stock=count_in_stock+? WHERE id=?").WithArgs(m.Quantity, m.ProductID).WillReturnResult(sqlmock.NewResult(0, 1))
+	if m.VariantID != 0 {
+		mock.ExpectExec("UPDATE product_variants SET count_in_stock=count_in_stock+? WHERE id=?").WithArgs(m.Quantity, m.VariantID).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	expectStockLevel(mock, m)
+}
+
+// expectStockLevel expects m to be added to its stock level and appended to
+// the ledger, as opening stock is.
+func expectStockLevel(mock sqlmock.Sqlmock, m StockMovement) {
+	mock.ExpectExec("INSERT INTO stock_levels (warehouse_id, product_id, variant_id, quantity) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE quantity=quantity+VALUES(quantity)").WithArgs(m.WarehouseID, m.ProductID, m.VariantID, m.Quantity).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO stock_movements (warehouse_id, product_id, variant_id, kind, quantity, reason, actor_id, order_id, other_warehouse_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)").WithArgs(m.WarehouseID, m.ProductID, m.VariantID, m.Kind, m.Quantity, m.Reason, m.ActorID, m.OrderID, m.OtherWarehouseID).WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
 func TestCreateProduct(t *testing.T) {
 	p := &Product{
 		Name:         "test product",
 		Image:        "test.jpg",
-		Category:     "test category",
 		Description:  "test description",
 		Rating:       5,
 		NumReviews:   10,
-		Price:        100.0,
+		Price:        money.New(10000, money.DefaultCurrency),
 		CountInStock: 100,
 	}
 
@@ -41,7 +62,10 @@ func TestCreateProduct(t *testing.T) {
 		{
 			name: "success",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				mock.ExpectExec("INSERT INTO products (name, image, category, description, rating, num_reviews, price, count_in_stock) VALUES (?, ?, ?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO products (name, image, category_id, description, options, tax_class, weight, gift_card, rating, num_reviews, price, count_in_stock) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(1, 1))
+				expectStockLevel(mock, StockMovement{WarehouseID: MainWarehouseID, ProductID: 1, Kind: StockReceipt, Quantity: p.CountInStock, Reason: "opening stock"})
+				mock.ExpectCommit()
 
 				cp, err := st.CreateProduct(context.Background(), p)
 				require.NoError(t, err)
@@ -53,7 +77,9 @@ func TestCreateProduct(t *testing.T) {
 		{
 			name: "failed inserting product",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				mock.ExpectExec("INSERT INTO products (name, image, category, description, rating, num_reviews, price, count_in_stock) VALUES (?, ?, ?, ?, ?, ?, ?, ?)").WillReturnError(fmt.Errorf("error inserting product"))
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO products (name, image, category_id, description, options, tax_class, weight, gift_card, rating, num_reviews, price, count_in_stock) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").WillReturnError(fmt.Errorf("error inserting product"))
+				mock.ExpectRollback()
 
 				_, err := st.CreateProduct(context.Background(), p)
 				require.Error(t, err)
@@ -64,7 +90,9 @@ func TestCreateProduct(t *testing.T) {
 		{
 			name: "failed getting last insert ID",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				mock.ExpectExec("INSERT INTO products (name, image, category, description, rating, num_reviews, price, count_in_stock) VALUES (?, ?, ?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewErrorResult(fmt.Errorf("error getting last insert ID")))
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO products (name, image, category_id, description, options, tax_class, weight, gift_card, rating, num_reviews, price, count_in_stock) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewErrorResult(fmt.Errorf("error getting last insert ID")))
+				mock.ExpectRollback()
 
 				_, err := st.CreateProduct(context.Background(), p)
 				require.Error(t, err)
@@ -88,11 +116,10 @@ func TestGetProduct(t *testing.T) {
 	p := &Product{
 		Name:         "test product",
 		Image:        "test.jpg",
-		Category:     "test category",
 		Description:  "test description",
 		Rating:       5,
 		NumReviews:   10,
-		Price:        100.0,
+		Price:        money.New(10000, money.DefaultCurrency),
 		CountInStock: 100,
 	}
 
@@ -103,7 +130,7 @@ func TestGetProduct(t *testing.T) {
 		{
 			name: "success",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "name", "image", "category", "description", "rating", "num_reviews", "price", "count_in_stock", "created_at", "updated_at"}).AddRow(1, p.Name, p.Image, p.Category, p.Description, p.Rating, p.NumReviews, p.Price, p.CountInStock, p.CreatedAt, p.UpdatedAt)
+				rows := sqlmock.NewRows([]string{"id", "name", "image", "category_id", "description", "rating", "num_reviews", "price", "count_in_stock", "created_at", "updated_at"}).AddRow(1, p.Name, p.Image, p.CategoryID, p.Description, p.Rating, p.NumReviews, p.Price, p.CountInStock, p.CreatedAt, p.UpdatedAt)
 
 				mock.ExpectQuery("SELECT * FROM products WHERE id=?").WithArgs(1).WillReturnRows(rows)
 
@@ -144,11 +171,10 @@ func TestListProduct(t *testing.T) {
 	p := &Product{
 		Name:         "test product",
 		Image:        "test.jpg",
-		Category:     "test category",
 		Description:  "test description",
 		Rating:       5,
 		NumReviews:   10,
-		Price:        100.0,
+		Price:        money.New(10000, money.DefaultCurrency),
 		CountInStock: 100,
 	}
 
@@ -159,14 +185,66 @@ func TestListProduct(t *testing.T) {
 		{
 			name: "success",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "name", "image", "category", "description", "rating", "num_reviews", "price", "count_in_stock", "created_at", "updated_at"}).
-					AddRow(1, p.Name, p.Image, p.Category, p.Description, p.Rating, p.NumReviews, p.Price, p.CountInStock, p.CreatedAt, p.UpdatedAt)
+				rows := sqlmock.NewRows([]string{"id", "name", "image", "category_id", "description", "rating", "num_reviews", "price", "count_in_stock", "created_at", "updated_at", "list_price"}).
+					AddRow(1, p.Name, p.Image, p.CategoryID, p.Description, p.Rating, p.NumReviews, p.Price, p.CountInStock, p.CreatedAt, p.UpdatedAt, 80.0)
+
+				mock.ExpectQuery("SELECT products.*, COALESCE(scheduled.price, products.price) AS list_price FROM products LEFT JOIN (SELECT product_id, MIN(price) AS price FROM product_prices WHERE variant_id IS NULL AND starts_at<=? AND ends_at>? AND (quantity_cap IS NULL OR quantity_sold<quantity_cap) GROUP BY product_id) scheduled ON scheduled.product_id=products.id ORDER BY created_at ASC, id ASC LIMIT ?").WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), DefaultPageSize+1).WillReturnRows(rows)
 
-				mock.ExpectQuery("SELECT * FROM products").WillReturnRows(rows)
+				page, err := st.ListProducts(context.Background(), &ListProductsParams{})
+				require.NoError(t, err)
+				require.Len(t, page.Products, 1)
+				require.Equal(t, p.Price, page.Products[0].Price)
+				require.Equal(t, money.New(8000, money.DefaultCurrency), page.Products[0].ListPrice)
+				require.Empty(t, page.NextCursor)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "filtered page with cursor",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "name", "image", "category_id", "description", "rating", "num_reviews", "price", "count_in_stock", "created_at", "updated_at", "list_price"}).
+					AddRow(3, p.Name, p.Image, p.CategoryID, p.Description, p.Rating, p.NumReviews, 50.0, p.CountInStock, p.CreatedAt, p.UpdatedAt, 50.0).
+					AddRow(2, p.Name, p.Image, p.CategoryID, p.Description, p.Rating, p.NumReviews, 70.0, p.CountInStock, p.CreatedAt, p.UpdatedAt, 40.0)
+
+				cursor := productCursor(&Product{ID: 4, ListPrice: money.New(6000, money.DefaultCurrency)}, &ListProductsParams{SortBy: SortByPrice, Desc: true})
+				minRating := int64(4)
+				maxPrice := money.New(9000, money.DefaultCurrency)
+
+				mock.ExpectQuery("SELECT products.*, COALESCE(scheduled.price, products.price) AS list_price FROM products LEFT JOIN (SELECT product_id, MIN(price) AS price FROM product_prices WHERE variant_id IS NULL AND starts_at<=? AND ends_at>? AND (quantity_cap IS NULL OR quantity_sold<quantity_cap) GROUP BY product_id) scheduled ON scheduled.product_id=products.id WHERE category_id IN (?,?) AND COALESCE(scheduled.price, products.price)<=? AND rating>=? AND count_in_stock>0 AND (COALESCE(scheduled.price, products.price)<? OR (COALESCE(scheduled.price, products.price)=? AND id<?)) ORDER BY COALESCE(scheduled.price, products.price) DESC, id DESC LIMIT ?").
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 1, 2, "90.00", minRating, "60.00", "60.00", 4, 2).
+					WillReturnRows(rows)
+
+				page, err := st.ListProducts(context.Background(), &ListProductsParams{
+					Cursor:      cursor,
+					Limit:       1,
+					SortBy:      SortByPrice,
+					Desc:        true,
+					CategoryIDs: []int64{1, 2},
+					MaxPrice:    &maxPrice,
+					MinRating:   &minRating,
+					InStock:     true,
+				})
+				require.NoError(t, err)
+				require.Len(t, page.Products, 1)
+				require.Equal(t, int64(3), page.Products[0].ID)
+				require.NotEmpty(t, page.NextCursor)
 
-				products, err := st.ListProducts(context.Background())
+				err = mock.ExpectationsWereMet()
 				require.NoError(t, err)
-				require.Len(t, products, 1)
+			},
+		},
+		{
+			name: "invalid cursor",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				cursor := productCursor(&Product{ID: 4, ListPrice: money.New(6000, money.DefaultCurrency)}, &ListProductsParams{SortBy: SortByPrice})
+
+				_, err := st.ListProducts(context.Background(), &ListProductsParams{Cursor: cursor, SortBy: SortByName})
+				require.ErrorIs(t, err, ErrInvalidCursor)
+
+				_, err = st.ListProducts(context.Background(), &ListProductsParams{Cursor: "not a cursor"})
+				require.ErrorIs(t, err, ErrInvalidCursor)
 
 				err = mock.ExpectationsWereMet()
 				require.NoError(t, err)
@@ -175,9 +253,66 @@ func TestListProduct(t *testing.T) {
 		{
 			name: "failed querying products",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				mock.ExpectQuery("SELECT * FROM products").WillReturnError(fmt.Errorf("error querying products"))
+				mock.ExpectQuery("SELECT products.*, COALESCE(scheduled.price, products.price) AS list_price FROM products LEFT JOIN (SELECT product_id, MIN(price) AS price FROM product_prices WHERE variant_id IS NULL AND starts_at<=? AND ends_at>? AND (quantity_cap IS NULL OR quantity_sold<quantity_cap) GROUP BY product_id) scheduled ON scheduled.product_id=products.id ORDER BY created_at ASC, id ASC LIMIT ?").WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), DefaultPageSize+1).WillReturnError(fmt.Errorf("error querying products"))
+
+				_, err := st.ListProducts(context.Background(), &ListProductsParams{})
+				require.Error(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestSearchProducts(t *testing.T) {
+	columns := []string{"id", "name", "image", "category_id", "description", "rating", "num_reviews", "price", "count_in_stock", "created_at", "updated_at"}
+	fulltextQuery := "SELECT *, MATCH(name, description) AGAINST(? IN NATURAL LANGUAGE MODE) AS relevance FROM products WHERE MATCH(name, description) AGAINST(? IN BOOLEAN MODE) ORDER BY relevance DESC, id ASC LIMIT ?"
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "full-text and typo tolerant hits",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(append(columns, "relevance")).
+					AddRow(1, "Trail shoes", "shoes.jpg", 1, "For trails", 5, 10, 99.99, 10, time.Time{}, nil, 1.5)
+				mock.ExpectQuery(fulltextQuery).WithArgs("shoes", "shoes*", DefaultPageSize).WillReturnRows(rows)
+
+				candidates := sqlmock.NewRows(columns).
+					AddRow(1, "Trail shoes", "shoes.jpg", 1, "For trails", 5, 10, 99.99, 10, time.Time{}, nil).
+					AddRow(2, "Shoos", "shoos.jpg", 1, "Misspelled", 5, 10, 99.99, 10, time.Time{}, nil).
+					AddRow(3, "Socks", "socks.jpg", 1, "Warm", 5, 10, 99.99, 10, time.Time{}, nil)
+				mock.ExpectQuery("SELECT * FROM products WHERE name LIKE ? LIMIT ?").WithArgs("s%", fuzzyCandidateLimit).WillReturnRows(candidates)
+
+				hits, err := st.SearchProducts(context.Background(), &SearchProductsParams{Query: "shoes"})
+				require.NoError(t, err)
+				require.Len(t, hits, 2)
+				require.Equal(t, int64(1), hits[0].Product.ID)
+				require.Equal(t, 1.5, hits[0].Score)
+				require.Equal(t, "Trail <em>shoes</em>", hits[0].Highlights["name"])
+				require.Equal(t, int64(2), hits[1].Product.ID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "failed searching products",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(fulltextQuery).WithArgs("shoes", "shoes*", DefaultPageSize).WillReturnError(fmt.Errorf("error searching products"))
 
-				_, err := st.ListProducts(context.Background())
+				_, err := st.SearchProducts(context.Background(), &SearchProductsParams{Query: "shoes"})
 				require.Error(t, err)
 
 				err = mock.ExpectationsWereMet()
@@ -201,11 +336,10 @@ func TestUpdateProduct(t *testing.T) {
 		ID:           1,
 		Name:         "test product",
 		Image:        "test.jpg",
-		Category:     "test category",
 		Description:  "test description",
 		Rating:       5,
 		NumReviews:   100,
-		Price:        99.99,
+		Price:        money.New(9999, money.DefaultCurrency),
 		CountInStock: 10,
 	}
 
@@ -213,11 +347,10 @@ func TestUpdateProduct(t *testing.T) {
 		ID:           1,
 		Name:         "new test product",
 		Image:        "test.jpg",
-		Category:     "test category",
 		Description:  "test description",
 		Rating:       5,
 		NumReviews:   100,
-		Price:        99.99,
+		Price:        money.New(9999, money.DefaultCurrency),
 		CountInStock: 10,
 	}
 
@@ -228,13 +361,16 @@ func TestUpdateProduct(t *testing.T) {
 		{
 			name: "success",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				mock.ExpectExec("INSERT INTO products (name, image, category, description, rating, num_reviews, price, count_in_stock) VALUES (?, ?, ?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO products (name, image, category_id, description, options, tax_class, weight, gift_card, rating, num_reviews, price, count_in_stock) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(1, 1))
+				expectStockLevel(mock, StockMovement{WarehouseID: MainWarehouseID, ProductID: 1, Kind: StockReceipt, Quantity: p.CountInStock, Reason: "opening stock"})
+				mock.ExpectCommit()
 
 				cp, err := st.CreateProduct(context.Background(), p)
 				require.NoError(t, err)
 				require.Equal(t, int64(1), cp.ID)
 
-				mock.ExpectExec("UPDATE products SET name=?, image=?, category=?, description=?, rating=?, num_reviews=?, price=?, count_in_stock=?, updated_at=? WHERE id=?").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE products SET name=?, image=?, category_id=?, description=?, options=?, tax_class=?, weight=?, gift_card=?, price=?, updated_at=? WHERE id=?").WillReturnResult(sqlmock.NewResult(1, 1))
 
 				up, err := st.UpdateProduct(context.Background(), np)
 				require.NoError(t, err)
@@ -248,7 +384,7 @@ func TestUpdateProduct(t *testing.T) {
 		{
 			name: "failed updating product",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				mock.ExpectExec("UPDATE products SET name=?, image=?, category=?, description=?, rating=?, num_reviews=?, price=?, count_in_stock=?, updated_at=? WHERE id=?").WillReturnError(fmt.Errorf("error updating product"))
+				mock.ExpectExec("UPDATE products SET name=?, image=?, category_id=?, description=?, options=?, tax_class=?, weight=?, gift_card=?, price=?, updated_at=? WHERE id=?").WillReturnError(fmt.Errorf("error updating product"))
 
 				_, err := st.UpdateProduct(context.Background(), p)
 				require.Error(t, err)
@@ -316,26 +452,45 @@ func TestCreateOrder(t *testing.T) {
 			Name:      "test product",
 			Quantity:  1,
 			Image:     "test.jpg",
-			Price:     99.99,
+			Price:     money.New(9999, money.DefaultCurrency),
 			ProductID: 1,
 		},
 		{
 			Name:      "test product 2",
 			Quantity:  2,
 			Image:     "test2.jpg",
-			Price:     99.99,
+			Price:     money.New(9999, money.DefaultCurrency),
 			ProductID: 2,
 		},
 	}
 
 	order := &Order{
 		PaymentMethod: "test payment method",
-		TaxPrice:      10.0,
-		ShippingPrice: 20.0,
-		TotalPrice:    129.99,
+		TaxPrice:      money.New(1000, money.DefaultCurrency),
+		ShippingPrice: money.New(2000, money.DefaultCurrency),
+		TotalPrice:    money.New(12999, money.DefaultCurrency),
 		Items:         orderItems,
 	}
 
+	stockRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "name", "count_in_stock"}).
+			AddRow(1, "test product", 10).
+			AddRow(2, "test product 2", 10)
+	}
+	// both products are taken from the main warehouse
+	expectLevels := func(mock sqlmock.Sqlmock) {
+		for _, id := range []int64{1, 2} {
+			mock.ExpectQuery("SELECT * FROM stock_levels WHERE product_id=? AND variant_id=? AND quantity>0 ORDER BY warehouse_id FOR UPDATE").WithArgs(id, 0).
+				WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "product_id", "variant_id", "quantity"}).AddRow(MainWarehouseID, id, 0, 10))
+		}
+	}
+	expectSales := func(mock sqlmock.Sqlmock) {
+		orderID := int64(1)
+		for _, oi := range orderItems {
+			expectStockMovement(mock, StockMovement{WarehouseID: MainWarehouseID, ProductID: oi.ProductID, Kind: StockSale, Quantity: -oi.Quantity, Reason: "order 1", ActorID: &order.UserID, OrderID: &orderID})
+		}
+	}
+
 	tcs := []struct {
 		name string
 		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
@@ -344,12 +499,15 @@ func TestCreateOrder(t *testing.T) {
 			name: "success",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec("INSERT INTO orders (payment_method, tax_price, shipping_price, total_price) VALUES (?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec("INSERT INTO order_items (name, quantity, image, price, product_id, order_id) VALUES (?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec("INSERT INTO order_items (name, quantity, image, price, product_id, order_id) VALUES (?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectQuery("SELECT id, name, count_in_stock FROM products WHERE id IN (?, ?) ORDER BY id FOR UPDATE").WithArgs(1, 2).WillReturnRows(stockRows())
+				expectLevels(mock)
+				mock.ExpectExec("INSERT INTO orders (payment_method, shipping_address, billing_address, shipping_method, tax_price, shipping_price, discount_price, coupon_code, discounts, total_price, user_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO order_items (name, quantity, image, price, product_id, variant_id, sku, scheduled_price_id, discount_price, tax_rate, tax_jurisdiction, tax_price, tax_inclusive, order_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO order_items (name, quantity, image, price, product_id, variant_id, sku, scheduled_price_id, discount_price, tax_rate, tax_jurisdiction, tax_price, tax_inclusive, order_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(2, 1))
+				expectSales(mock)
 				mock.ExpectCommit()
 
-				co, err := st.CreateOrder(context.Background(), order)
+				co, err := st.CreateOrder(context.Background(), order, nil)
 				require.NoError(t, err)
 				require.Equal(t, int64(1), co.ID)
 
@@ -357,30 +515,100 @@ func TestCreateOrder(t *testing.T) {
 				require.NoError(t, err)
 			},
 		},
+		{
+			name: "notification enqueued in the same transaction",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id, name, count_in_stock FROM products WHERE id IN (?, ?) ORDER BY id FOR UPDATE").WithArgs(1, 2).WillReturnRows(stockRows())
+				expectLevels(mock)
+				mock.ExpectExec("INSERT INTO orders (payment_method, shipping_address, billing_address, shipping_method, tax_price, shipping_price, discount_price, coupon_code, discounts, total_price, user_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO order_items (name, quantity, image, price, product_id, variant_id, sku, scheduled_price_id, discount_price, tax_rate, tax_jurisdiction, tax_price, tax_inclusive, order_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO order_items (name, quantity, image, price, product_id, variant_id, sku, scheduled_price_id, discount_price, tax_rate, tax_jurisdiction, tax_price, tax_inclusive, order_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(2, 1))
+				expectSales(mock)
+				mock.ExpectExec("INSERT INTO notification_states (order_id, state, message) VALUES (?, ?, ?)").WithArgs(1, NotSent, "").WillReturnResult(sqlmock.NewResult(5, 1))
+				mock.ExpectExec("INSERT INTO notification_events_queue (user_email, order_status, order_id, return_id, return_status, state_id, attempts) VALUES (?, ?, ?, ?, ?, ?, ?)").WithArgs("test@example.com", Pending, 1, nil, "", 5, 0).WillReturnResult(sqlmock.NewResult(7, 1))
+				mock.ExpectCommit()
+
+				ne := &NotificationEvent{UserEmail: "test@example.com"}
+				_, err := st.CreateOrder(context.Background(), order, ne)
+				require.NoError(t, err)
+				require.Equal(t, int64(7), ne.ID)
+				require.Equal(t, int64(5), ne.StateID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "failed enqueueing notification",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id, name, count_in_stock FROM products WHERE id IN (?, ?) ORDER BY id FOR UPDATE").WithArgs(1, 2).WillReturnRows(stockRows())
+				expectLevels(mock)
+				mock.ExpectExec("INSERT INTO orders (payment_method, shipping_address, billing_address, shipping_method, tax_price, shipping_price, discount_price, coupon_code, discounts, total_price, user_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO order_items (name, quantity, image, price, product_id, variant_id, sku, scheduled_price_id, discount_price, tax_rate, tax_jurisdiction, tax_price, tax_inclusive, order_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO order_items (name, quantity, image, price, product_id, variant_id, sku, scheduled_price_id, discount_price, tax_rate, tax_jurisdiction, tax_price, tax_inclusive, order_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(2, 1))
+				expectSales(mock)
+				mock.ExpectExec("INSERT INTO notification_states (order_id, state, message) VALUES (?, ?, ?)").WillReturnError(fmt.Errorf("error inserting notification state"))
+				mock.ExpectRollback()
+
+				_, err := st.CreateOrder(context.Background(), order, &NotificationEvent{UserEmail: "test@example.com"})
+				require.Error(t, err)
 
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
 		{
 			name: "failed creating order",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec("INSERT INTO orders (payment_method, tax_price, shipping_price, total_price) VALUES (?, ?, ?, ?)").WillReturnError(fmt.Errorf("error creating order"))
+				mock.ExpectQuery("SELECT id, name, count_in_stock FROM products WHERE id IN (?, ?) ORDER BY id FOR UPDATE").WithArgs(1, 2).WillReturnRows(stockRows())
+				expectLevels(mock)
+				mock.ExpectExec("INSERT INTO orders (payment_method, shipping_address, billing_address, shipping_method, tax_price, shipping_price, discount_price, coupon_code, discounts, total_price, user_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").WillReturnError(fmt.Errorf("error creating order"))
 				mock.ExpectRollback()
 
-				_, err := st.CreateOrder(context.Background(), order)
+				_, err := st.CreateOrder(context.Background(), order, nil)
 				require.Error(t, err)
 
 				err = mock.ExpectationsWereMet()
 				require.NoError(t, err)
 			},
 		},
+		{
+			name: "insufficient stock",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "name", "count_in_stock"}).
+					AddRow(1, "test product", 10).
+					AddRow(2, "test product 2", 1)
+
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id, name, count_in_stock FROM products WHERE id IN (?, ?) ORDER BY id FOR UPDATE").WithArgs(1, 2).WillReturnRows(rows)
+				mock.ExpectRollback()
+
+				_, err := st.CreateOrder(context.Background(), order, nil)
+				require.ErrorIs(t, err, ErrInsufficientStock)
+
+				var stockErr *InsufficientStockError
+				require.ErrorAs(t, err, &stockErr)
+				require.Equal(t, int64(2), stockErr.ProductID)
+				require.Equal(t, "test product 2", stockErr.Name)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
 		{
 			name: "failed creating order item",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec("INSERT INTO orders (payment_method, tax_price, shipping_price, total_price) VALUES (?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec("INSERT INTO order_items (name, quantity, image, price, product_id, order_id) VALUES (?, ?, ?, ?, ?, ?)").WillReturnError(fmt.Errorf("error creating order item"))
+				mock.ExpectQuery("SELECT id, name, count_in_stock FROM products WHERE id IN (?, ?) ORDER BY id FOR UPDATE").WithArgs(1, 2).WillReturnRows(stockRows())
+				expectLevels(mock)
+				mock.ExpectExec("INSERT INTO orders (payment_method, shipping_address, billing_address, shipping_method, tax_price, shipping_price, discount_price, coupon_code, discounts, total_price, user_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO order_items (name, quantity, image, price, product_id, variant_id, sku, scheduled_price_id, discount_price, tax_rate, tax_jurisdiction, tax_price, tax_inclusive, order_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").WillReturnError(fmt.Errorf("error creating order item"))
 				mock.ExpectRollback()
 
-				_, err := st.CreateOrder(context.Background(), order)
+				_, err := st.CreateOrder(context.Background(), order, nil)
 				require.Error(t, err)
 
 				err = mock.ExpectationsWereMet()
@@ -391,12 +619,15 @@ func TestCreateOrder(t *testing.T) {
 			name: "failed committing transaction",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec("INSERT INTO orders (payment_method, tax_price, shipping_price, total_price) VALUES (?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec("INSERT INTO order_items (name, quantity, image, price, product_id, order_id) VALUES (?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec("INSERT INTO order_items (name, quantity, image, price, product_id, order_id) VALUES (?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectQuery("SELECT id, name, count_in_stock FROM products WHERE id IN (?, ?) ORDER BY id FOR UPDATE").WithArgs(1, 2).WillReturnRows(stockRows())
+				expectLevels(mock)
+				mock.ExpectExec("INSERT INTO orders (payment_method, shipping_address, billing_address, shipping_method, tax_price, shipping_price, discount_price, coupon_code, discounts, total_price, user_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO order_items (name, quantity, image, price, product_id, variant_id, sku, scheduled_price_id, discount_price, tax_rate, tax_jurisdiction, tax_price, tax_inclusive, order_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO order_items (name, quantity, image, price, product_id, variant_id, sku, scheduled_price_id, discount_price, tax_rate, tax_jurisdiction, tax_price, tax_inclusive, order_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").WillReturnResult(sqlmock.NewResult(2, 1))
+				expectSales(mock)
 				mock.ExpectCommit().WillReturnError(fmt.Errorf("error committing transaction"))
 
-				_, err := st.CreateOrder(context.Background(), order)
+				_, err := st.CreateOrder(context.Background(), order, nil)
 				require.Error(t, err)
 
 				err = mock.ExpectationsWereMet()
@@ -421,23 +652,23 @@ func TestGetOrder(t *testing.T) {
 			Name:      "test product",
 			Quantity:  1,
 			Image:     "test.jpg",
-			Price:     99.99,
+			Price:     money.New(9999, money.DefaultCurrency),
 			ProductID: 1,
 		},
 		{
 			Name:      "test product 2",
 			Quantity:  2,
 			Image:     "test2.jpg",
-			Price:     99.99,
+			Price:     money.New(9999, money.DefaultCurrency),
 			ProductID: 2,
 		},
 	}
 
 	order := &Order{
 		PaymentMethod: "test payment method",
-		TaxPrice:      10.0,
-		ShippingPrice: 20.0,
-		TotalPrice:    129.99,
+		TaxPrice:      money.New(1000, money.DefaultCurrency),
+		ShippingPrice: money.New(2000, money.DefaultCurrency),
+		TotalPrice:    money.New(12999, money.DefaultCurrency),
 		Items:         orderItems,
 	}
 
@@ -520,23 +751,23 @@ func TestListOrders(t *testing.T) {
 			Name:      "test product",
 			Quantity:  1,
 			Image:     "test.jpg",
-			Price:     99.99,
+			Price:     money.New(9999, money.DefaultCurrency),
 			ProductID: 1,
 		},
 		{
 			Name:      "test product 2",
 			Quantity:  2,
 			Image:     "test2.jpg",
-			Price:     99.99,
+			Price:     money.New(9999, money.DefaultCurrency),
 			ProductID: 2,
 		},
 	}
 
 	order := &Order{
 		PaymentMethod: "test payment method",
-		TaxPrice:      10.0,
-		ShippingPrice: 20.0,
-		TotalPrice:    129.99,
+		TaxPrice:      money.New(1000, money.DefaultCurrency),
+		ShippingPrice: money.New(2000, money.DefaultCurrency),
+		TotalPrice:    money.New(12999, money.DefaultCurrency),
 		Items:         orderItems,
 	}
 
@@ -555,7 +786,7 @@ func TestListOrders(t *testing.T) {
 					AddRow(1, orderItems[0].Name, orderItems[0].Quantity, orderItems[0].Image, orderItems[0].Price, orderItems[0].ProductID, 1).
 					AddRow(2, orderItems[1].Name, orderItems[1].Quantity, orderItems[1].Image, orderItems[1].Price, orderItems[1].ProductID, 1)
 
-				mock.ExpectQuery("SELECT * FROM order_items WHERE order_id=?").WithArgs(1).WillReturnRows(orderItemRows)
+				mock.ExpectQuery("SELECT * FROM order_items WHERE order_id IN (?) ORDER BY id").WithArgs(1).WillReturnRows(orderItemRows)
 
 				mo, err := st.ListOrders(context.Background())
 				require.NoError(t, err)
@@ -584,7 +815,7 @@ func TestListOrders(t *testing.T) {
 
 				mock.ExpectQuery("SELECT * FROM orders").WillReturnRows(orderRows)
 
-				mock.ExpectQuery("SELECT * FROM order_items WHERE order_id=?").WithArgs(1).WillReturnError(fmt.Errorf("error querying order items"))
+				mock.ExpectQuery("SELECT * FROM order_items WHERE order_id IN (?) ORDER BY id").WithArgs(1).WillReturnError(fmt.Errorf("error querying order items"))
 
 				_, err := st.ListOrders(context.Background())
 				require.Error(t, err)
@@ -605,7 +836,83 @@ func TestListOrders(t *testing.T) {
 	}
 }
 
-func TestDeleteOrder(t *testing.T) {
+func TestListUserOrders(t *testing.T) {
+	createdAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	orderRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "payment_method", "user_id", "status", "created_at"}).
+			AddRow(3, "card", 1, "pending", createdAt).
+			AddRow(2, "card", 1, "pending", createdAt)
+	}
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "filtered with next page",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				from := createdAt.Add(-24 * time.Hour)
+				mock.ExpectQuery("SELECT * FROM orders WHERE user_id=? AND status=? AND created_at>=? ORDER BY created_at DESC, id DESC LIMIT ?").
+					WithArgs(1, Pending, from, 2).
+					WillReturnRows(orderRows())
+				mock.ExpectQuery("SELECT * FROM order_items WHERE order_id IN (?) ORDER BY id").
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "quantity", "product_id", "order_id"}).
+						AddRow(1, "test product", 1, 1, 3).
+						AddRow(2, "test product 2", 2, 2, 3))
+
+				page, err := st.ListUserOrders(context.Background(), &ListOrdersParams{UserID: 1, Limit: 1, Status: Pending, From: &from})
+				require.NoError(t, err)
+				require.Len(t, page.Orders, 1)
+				require.Len(t, page.Orders[0].Items, 2)
+				require.NotEmpty(t, page.NextCursor)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "cursor",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				cursor := orderCursor(&Order{ID: 3, CreatedAt: createdAt})
+				mock.ExpectQuery("SELECT * FROM orders WHERE user_id=? AND (created_at<? OR (created_at=? AND id<?)) ORDER BY created_at DESC, id DESC LIMIT ?").
+					WithArgs(1, createdAt, createdAt, 3, DefaultPageSize+1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "created_at"}))
+
+				page, err := st.ListUserOrders(context.Background(), &ListOrdersParams{UserID: 1, Cursor: cursor})
+				require.NoError(t, err)
+				require.Empty(t, page.Orders)
+				require.Empty(t, page.NextCursor)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "product cursor",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				cursor := productCursor(&Product{ID: 1}, &ListProductsParams{SortBy: SortByCreatedAt})
+
+				_, err := st.ListUserOrders(context.Background(), &ListOrdersParams{UserID: 1, Cursor: cursor})
+				require.ErrorIs(t, err, ErrInvalidCursor)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestUpdateOrderStatus(t *testing.T) {
 	tcs := []struct {
 		name string
 		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
@@ -614,11 +921,12 @@ func TestDeleteOrder(t *testing.T) {
 			name: "success",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec("DELETE FROM order_items WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec("DELETE FROM orders WHERE id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("SELECT status FROM orders WHERE id=? FOR UPDATE").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("pending"))
+				mock.ExpectExec("UPDATE orders SET status=?, updated_at=? WHERE id=?").WithArgs(Paid, nil, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO order_status_history (order_id, from_status, to_status, reason, actor_id, actor_role) VALUES (?, ?, ?, ?, ?, ?)").WithArgs(1, Pending, Paid, "", 2, ActorAdmin).WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectCommit()
 
-				err := st.DeleteOrder(context.Background(), 1)
+				_, err := st.UpdateOrderStatus(context.Background(), &Order{ID: 1, Status: Paid}, &OrderStatusChange{FromStatus: Pending, ActorID: 2, ActorRole: ActorAdmin}, nil)
 				require.NoError(t, err)
 
 				err = mock.ExpectationsWereMet()
@@ -626,29 +934,2143 @@ func TestDeleteOrder(t *testing.T) {
 			},
 		},
 		{
-			name: "failed deleting order item",
+			name: "cancel restocks",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				actorID, orderID := int64(3), int64(1)
 				mock.ExpectBegin()
-				mock.ExpectExec("DELETE FROM order_items WHERE order_id=?").WithArgs(1).WillReturnError(fmt.Errorf("error deleting order items"))
+				mock.ExpectQuery("SELECT status FROM orders WHERE id=? FOR UPDATE").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("paid"))
+				mock.ExpectQuery("SELECT warehouse_id, product_id, variant_id, SUM(quantity) AS quantity FROM stock_movements WHERE order_id=? AND kind='sale' GROUP BY warehouse_id, product_id, variant_id ORDER BY product_id, variant_id, warehouse_id").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "product_id", "variant_id", "quantity"}).AddRow(2, 1, 0, -2))
+				expectStockMovement(mock, StockMovement{WarehouseID: 2, ProductID: 1, Kind: StockRelease, Quantity: 2, Reason: "order 1 cancelled", ActorID: &actorID, OrderID: &orderID})
+				mock.ExpectExec("UPDATE product_prices JOIN (SELECT scheduled_price_id, SUM(quantity) AS quantity FROM order_items WHERE order_id=? AND scheduled_price_id IS NOT NULL GROUP BY scheduled_price_id) sold ON sold.scheduled_price_id=product_prices.id SET product_prices.quantity_sold=product_prices.quantity_sold-sold.quantity").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE orders SET status=?, updated_at=? WHERE id=?").WithArgs(Cancelled, nil, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO order_status_history (order_id, from_status, to_status, reason, actor_id, actor_role) VALUES (?, ?, ?, ?, ?, ?)").WithArgs(1, Paid, Cancelled, "changed my mind", 3, ActorCustomer).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+
+				_, err := st.UpdateOrderStatus(context.Background(), &Order{ID: 1, Status: Cancelled}, &OrderStatusChange{FromStatus: Paid, Reason: "changed my mind", ActorID: 3, ActorRole: ActorCustomer}, nil)
+				require.NoError(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "refund issues its credit note",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				issuedAt := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM orders WHERE id=? FOR UPDATE").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("delivered"))
+				mock.ExpectExec("UPDATE orders SET status=?, updated_at=? WHERE id=?").WithArgs(Refunded, nil, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO order_status_history (order_id, from_status, to_status, reason, actor_id, actor_role) VALUES (?, ?, ?, ?, ?, ?)").WithArgs(1, Delivered, Refunded, "damaged", 2, ActorAdmin).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE document_sequences SET last_number=LAST_INSERT_ID(last_number+1) WHERE name=?").WithArgs("credit_note").WillReturnResult(sqlmock.NewResult(4, 1))
+				mock.ExpectExec("INSERT INTO credit_notes (number, invoice_id, reason, items, items_price, discount_price, shipping_price, tax_price, total_price, issued_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
+					WithArgs("CN-000004", 3, "damaged", "[]", "19.99", "0.00", "0.00", "0.00", "19.99", issuedAt).WillReturnResult(sqlmock.NewResult(5, 1))
+				mock.ExpectCommit()
+
+				zero, price := money.New(0, money.DefaultCurrency), money.New(1999, money.DefaultCurrency)
+				cn := &CreditNote{InvoiceID: 3, Reason: "damaged", ItemsPrice: price, DiscountPrice: zero, ShippingPrice: zero, TaxPrice: zero, TotalPrice: price, IssuedAt: issuedAt}
+				_, err := st.UpdateOrderStatus(context.Background(), &Order{ID: 1, Status: Refunded}, &OrderStatusChange{FromStatus: Delivered, Reason: "damaged", ActorID: 2, ActorRole: ActorAdmin, CreditNote: cn}, nil)
+				require.NoError(t, err)
+				require.Equal(t, int64(5), cn.ID)
+				require.Equal(t, "CN-000004", cn.Number)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "refund cancels the gift cards bought",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				orderID := int64(1)
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM orders WHERE id=? FOR UPDATE").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("delivered"))
+				mock.ExpectExec("UPDATE orders SET status=?, updated_at=? WHERE id=?").WithArgs(Refunded, nil, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO order_status_history (order_id, from_status, to_status, reason, actor_id, actor_role) VALUES (?, ?, ?, ?, ?, ?)").WithArgs(1, Delivered, Refunded, "", 2, ActorAdmin).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("SELECT * FROM gift_cards WHERE order_id=? ORDER BY id FOR UPDATE").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).AddRow(4, "25.00").AddRow(5, "0.00"))
+				mock.ExpectQuery("SELECT balance FROM gift_cards WHERE id=? FOR UPDATE").WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("25.00"))
+				mock.ExpectExec("UPDATE gift_cards SET balance=balance+? WHERE id=?").WithArgs("-25.00", 4).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO gift_card_transactions (gift_card_id, kind, amount, order_id, actor_id, reason) VALUES (?, ?, ?, ?, ?, ?)").
+					WithArgs(4, BalanceAdjustment, "-25.00", &orderID, nil, "order 1 refunded").WillReturnResult(sqlmock.NewResult(9, 1))
+				mock.ExpectCommit()
+
+				_, err := st.UpdateOrderStatus(context.Background(), &Order{ID: 1, Status: Refunded}, &OrderStatusChange{FromStatus: Delivered, ActorID: 2, ActorRole: ActorAdmin, CancelGiftCards: true}, nil)
+				require.NoError(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "payment issues the gift cards bought",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				orderID, userID := int64(1), int64(3)
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM orders WHERE id=? FOR UPDATE").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("pending"))
+				mock.ExpectExec("UPDATE orders SET status=?, updated_at=? WHERE id=?").WithArgs(Paid, nil, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO order_status_history (order_id, from_status, to_status, reason, actor_id, actor_role) VALUES (?, ?, ?, ?, ?, ?)").WithArgs(1, Pending, Paid, "payment 2 captured", 0, ActorSystem).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO gift_cards (code, initial_amount, balance, expires_at, purchaser_id, order_id, issued_by, note) VALUES (?, ?, ?, ?, ?, ?, ?, ?)").
+					WithArgs("ABCD-EFGH", "25.00", "0.00", nil, &userID, &orderID, nil, "").WillReturnResult(sqlmock.NewResult(4, 1))
+				mock.ExpectQuery("SELECT balance FROM gift_cards WHERE id=? FOR UPDATE").WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("0.00"))
+				mock.ExpectExec("UPDATE gift_cards SET balance=balance+? WHERE id=?").WithArgs("25.00", 4).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO gift_card_transactions (gift_card_id, kind, amount, order_id, actor_id, reason) VALUES (?, ?, ?, ?, ?, ?)").
+					WithArgs(4, BalanceIssue, "25.00", &orderID, nil, "bought in order 1").WillReturnResult(sqlmock.NewResult(9, 1))
+				mock.ExpectCommit()
+
+				card := &GiftCard{Code: "ABCD-EFGH", InitialAmount: money.New(2500, money.DefaultCurrency), PurchaserID: &userID, OrderID: &orderID}
+				_, err := st.UpdateOrderStatus(context.Background(), &Order{ID: 1, Status: Paid}, &OrderStatusChange{FromStatus: Pending, Reason: "payment 2 captured", ActorRole: ActorSystem, GiftCards: []*GiftCard{card}}, nil)
+				require.NoError(t, err)
+				require.Equal(t, int64(4), card.ID)
+				require.Equal(t, money.New(2500, money.DefaultCurrency), card.Balance)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "gift card code taken rolls the payment back",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				orderID := int64(1)
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM orders WHERE id=? FOR UPDATE").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("pending"))
+				mock.ExpectExec("UPDATE orders SET status=?, updated_at=? WHERE id=?").WithArgs(Paid, nil, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO order_status_history (order_id, from_status, to_status, reason, actor_id, actor_role) VALUES (?, ?, ?, ?, ?, ?)").WithArgs(1, Pending, Paid, "", 0, ActorSystem).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO gift_cards (code, initial_amount, balance, expires_at, purchaser_id, order_id, issued_by, note) VALUES (?, ?, ?, ?, ?, ?, ?, ?)").
+					WithArgs("ABCD-EFGH", "25.00", "0.00", nil, nil, &orderID, nil, "").
+					WillReturnError(&mysql.MySQLError{Number: mysqlErrDupEntry, Message: "Duplicate entry"})
 				mock.ExpectRollback()
 
-				err := st.DeleteOrder(context.Background(), 1)
-				require.Error(t, err)
+				card := &GiftCard{Code: "ABCD-EFGH", InitialAmount: money.New(2500, money.DefaultCurrency), OrderID: &orderID}
+				_, err := st.UpdateOrderStatus(context.Background(), &Order{ID: 1, Status: Paid}, &OrderStatusChange{FromStatus: Pending, ActorRole: ActorSystem, GiftCards: []*GiftCard{card}}, nil)
+				require.ErrorIs(t, err, ErrGiftCardExists)
 
 				err = mock.ExpectationsWereMet()
 				require.NoError(t, err)
 			},
 		},
 		{
-			name: "failed deleting order",
+			name: "delivery awards loyalty points",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				orderID := int64(1)
+				expiresAt := time.Date(2026, 6, 22, 10, 0, 0, 0, time.UTC)
 				mock.ExpectBegin()
-				mock.ExpectExec("DELETE FROM order_items WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec("DELETE FROM orders WHERE id=?").WithArgs(1).WillReturnError(fmt.Errorf("error deleting order"))
+				mock.ExpectQuery("SELECT status FROM orders WHERE id=? FOR UPDATE").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("shipped"))
+				mock.ExpectExec("UPDATE orders SET status=?, updated_at=? WHERE id=?").WithArgs(Delivered, nil, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO order_status_history (order_id, from_status, to_status, reason, actor_id, actor_role) VALUES (?, ?, ?, ?, ?, ?)").WithArgs(1, Shipped, Delivered, "", 2, ActorAdmin).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("SELECT balance FROM loyalty_accounts WHERE user_id=? FOR UPDATE").WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(10))
+				mock.ExpectQuery("SELECT COALESCE(SUM(CASE WHEN points>0 AND expires_at<=? THEN points ELSE 0 END), 0) AS lapsed, COALESCE(SUM(CASE WHEN points<0 THEN -points ELSE 0 END), 0) AS used FROM loyalty_transactions WHERE user_id=?").
+					WithArgs(sqlmock.AnyArg(), 3).WillReturnRows(sqlmock.NewRows([]string{"lapsed", "used"}).AddRow(0, 0))
+				mock.ExpectExec("INSERT INTO loyalty_accounts (user_id, balance) VALUES (?, ?) ON DUPLICATE KEY UPDATE balance=balance+VALUES(balance), updated_at=CURRENT_TIMESTAMP").WithArgs(3, 25).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO loyalty_transactions (user_id, kind, points, spend, order_id, reason, expires_at) VALUES (?, ?, ?, ?, ?, ?, ?)").
+					WithArgs(3, BalanceEarn, 25, "25.00", orderID, "order 1 delivered", expiresAt).WillReturnResult(sqlmock.NewResult(6, 1))
+				mock.ExpectCommit()
+
+				lt := &LoyaltyTransaction{UserID: 3, Kind: BalanceEarn, Points: 25, Spend: money.New(2500, money.DefaultCurrency), OrderID: &orderID, Reason: "order 1 delivered", ExpiresAt: &expiresAt}
+				_, err := st.UpdateOrderStatus(context.Background(), &Order{ID: 1, Status: Delivered}, &OrderStatusChange{FromStatus: Shipped, ActorID: 2, ActorRole: ActorAdmin, Loyalty: lt}, nil)
+				require.NoError(t, err)
+				require.Equal(t, int64(6), lt.ID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "failed credit note rolls the refund back",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM orders WHERE id=? FOR UPDATE").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("delivered"))
+				mock.ExpectExec("UPDATE orders SET status=?, updated_at=? WHERE id=?").WithArgs(Refunded, nil, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO order_status_history (order_id, from_status, to_status, reason, actor_id, actor_role) VALUES (?, ?, ?, ?, ?, ?)").WithArgs(1, Delivered, Refunded, "", 2, ActorAdmin).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE document_sequences SET last_number=LAST_INSERT_ID(last_number+1) WHERE name=?").WithArgs("credit_note").WillReturnError(sql.ErrConnDone)
 				mock.ExpectRollback()
 
-				err := st.DeleteOrder(context.Background(), 1)
-				require.Error(t, err)
+				_, err := st.UpdateOrderStatus(context.Background(), &Order{ID: 1, Status: Refunded}, &OrderStatusChange{FromStatus: Delivered, ActorID: 2, ActorRole: ActorAdmin, CreditNote: &CreditNote{InvoiceID: 3}}, nil)
+				require.ErrorIs(t, err, sql.ErrConnDone)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "status changed concurrently",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM orders WHERE id=? FOR UPDATE").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("shipped"))
+				mock.ExpectRollback()
+
+				_, err := st.UpdateOrderStatus(context.Background(), &Order{ID: 1, Status: Cancelled}, &OrderStatusChange{FromStatus: Paid}, nil)
+				require.ErrorIs(t, err, ErrOrderStatusChanged)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestClaimNotificationEvents(t *testing.T) {
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT * FROM notification_events_queue WHERE attempts<? AND (locked_until IS NULL OR locked_until<=?) ORDER BY created_at, id LIMIT ? FOR UPDATE SKIP LOCKED").
+					WithArgs(maxAttempts, sqlmock.AnyArg(), 10).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_email", "order_status", "order_id", "state_id", "attempts"}).
+						AddRow(1, "test@example.com", "pending", 1, 1, 0).
+						AddRow(2, "test@example.com", "shipped", 1, 2, 1))
+				mock.ExpectExec("UPDATE notification_events_queue SET locked_until=? WHERE id IN (?, ?)").
+					WithArgs(sqlmock.AnyArg(), 1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+
+				events, err := st.ClaimNotificationEvents(context.Background(), 10, time.Minute)
+				require.NoError(t, err)
+				require.Len(t, events, 2)
+				require.NotNil(t, events[0].LockedUntil)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "nothing due",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT * FROM notification_events_queue WHERE attempts<? AND (locked_until IS NULL OR locked_until<=?) ORDER BY created_at, id LIMIT ? FOR UPDATE SKIP LOCKED").
+					WithArgs(maxAttempts, sqlmock.AnyArg(), DefaultPageSize).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectCommit()
+
+				events, err := st.ClaimNotificationEvents(context.Background(), 0, time.Minute)
+				require.NoError(t, err)
+				require.Empty(t, events)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestDeleteOrder(t *testing.T) {
+	// placed before stock was kept per warehouse, so it is restocked into
+	// the main warehouse
+	orderID := int64(1)
+	itemRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "name", "quantity", "image", "price", "product_id", "order_id"}).
+			AddRow(1, "test product", 1, "test.jpg", 99.99, 1, 1).
+			AddRow(2, "test product", 2, "test.jpg", 99.99, 1, 1)
+	}
+	expectRecords := func(mock sqlmock.Sqlmock, records int) {
+		mock.ExpectQuery("SELECT (SELECT COUNT(*) FROM payments WHERE order_id=? AND status IN ('pending', 'authorized', 'captured', 'refunded')) + (SELECT COUNT(*) FROM invoices WHERE order_id=?) + (SELECT COUNT(*) FROM returns WHERE order_id=?)").WithArgs(1, 1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"records"}).AddRow(records))
+	}
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM orders WHERE id=? FOR UPDATE").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("pending"))
+				expectRecords(mock, 0)
+				mock.ExpectQuery("SELECT warehouse_id, product_id, variant_id, SUM(quantity) AS quantity FROM stock_movements WHERE order_id=? AND kind='sale' GROUP BY warehouse_id, product_id, variant_id ORDER BY product_id, variant_id, warehouse_id").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "product_id", "variant_id", "quantity"}))
+				mock.ExpectQuery("SELECT * FROM order_items WHERE order_id=?").WithArgs(1).WillReturnRows(itemRows())
+				expectStockMovement(mock, StockMovement{WarehouseID: MainWarehouseID, ProductID: 1, Kind: StockRelease, Quantity: 3, Reason: "order 1 deleted", OrderID: &orderID})
+				mock.ExpectExec("UPDATE product_prices JOIN (SELECT scheduled_price_id, SUM(quantity) AS quantity FROM order_items WHERE order_id=? AND scheduled_price_id IS NOT NULL GROUP BY scheduled_price_id) sold ON sold.scheduled_price_id=product_prices.id SET product_prices.quantity_sold=product_prices.quantity_sold-sold.quantity").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM order_status_history WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM notification_events_queue WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM notification_states WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM promotion_redemptions WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM payments WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM order_items WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("DELETE FROM orders WHERE id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+
+				err := st.DeleteOrder(context.Background(), 1)
+				require.NoError(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "cancelled order is not restocked twice",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM orders WHERE id=? FOR UPDATE").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("cancelled"))
+				expectRecords(mock, 0)
+				mock.ExpectExec("DELETE FROM order_status_history WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM notification_events_queue WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM notification_states WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM promotion_redemptions WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM payments WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM order_items WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("DELETE FROM orders WHERE id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+
+				err := st.DeleteOrder(context.Background(), 1)
+				require.NoError(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "paid order is kept",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM orders WHERE id=? FOR UPDATE").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("cancelled"))
+				expectRecords(mock, 2)
+				mock.ExpectRollback()
+
+				err := st.DeleteOrder(context.Background(), 1)
+				require.ErrorIs(t, err, ErrOrderHasRecords)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "failed deleting order item",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM orders WHERE id=? FOR UPDATE").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("pending"))
+				expectRecords(mock, 0)
+				mock.ExpectQuery("SELECT warehouse_id, product_id, variant_id, SUM(quantity) AS quantity FROM stock_movements WHERE order_id=? AND kind='sale' GROUP BY warehouse_id, product_id, variant_id ORDER BY product_id, variant_id, warehouse_id").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "product_id", "variant_id", "quantity"}))
+				mock.ExpectQuery("SELECT * FROM order_items WHERE order_id=?").WithArgs(1).WillReturnRows(itemRows())
+				expectStockMovement(mock, StockMovement{WarehouseID: MainWarehouseID, ProductID: 1, Kind: StockRelease, Quantity: 3, Reason: "order 1 deleted", OrderID: &orderID})
+				mock.ExpectExec("UPDATE product_prices JOIN (SELECT scheduled_price_id, SUM(quantity) AS quantity FROM order_items WHERE order_id=? AND scheduled_price_id IS NOT NULL GROUP BY scheduled_price_id) sold ON sold.scheduled_price_id=product_prices.id SET product_prices.quantity_sold=product_prices.quantity_sold-sold.quantity").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM order_status_history WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM notification_events_queue WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM notification_states WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM promotion_redemptions WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM payments WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM order_items WHERE order_id=?").WithArgs(1).WillReturnError(fmt.Errorf("error deleting order items"))
+				mock.ExpectRollback()
+
+				err := st.DeleteOrder(context.Background(), 1)
+				require.Error(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "failed deleting order",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM orders WHERE id=? FOR UPDATE").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("pending"))
+				expectRecords(mock, 0)
+				mock.ExpectQuery("SELECT warehouse_id, product_id, variant_id, SUM(quantity) AS quantity FROM stock_movements WHERE order_id=? AND kind='sale' GROUP BY warehouse_id, product_id, variant_id ORDER BY product_id, variant_id, warehouse_id").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "product_id", "variant_id", "quantity"}))
+				mock.ExpectQuery("SELECT * FROM order_items WHERE order_id=?").WithArgs(1).WillReturnRows(itemRows())
+				expectStockMovement(mock, StockMovement{WarehouseID: MainWarehouseID, ProductID: 1, Kind: StockRelease, Quantity: 3, Reason: "order 1 deleted", OrderID: &orderID})
+				mock.ExpectExec("UPDATE product_prices JOIN (SELECT scheduled_price_id, SUM(quantity) AS quantity FROM order_items WHERE order_id=? AND scheduled_price_id IS NOT NULL GROUP BY scheduled_price_id) sold ON sold.scheduled_price_id=product_prices.id SET product_prices.quantity_sold=product_prices.quantity_sold-sold.quantity").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM order_status_history WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM notification_events_queue WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM notification_states WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM promotion_redemptions WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM payments WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM order_items WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("DELETE FROM orders WHERE id=?").WithArgs(1).WillReturnError(fmt.Errorf("error deleting order"))
+				mock.ExpectRollback()
+
+				err := st.DeleteOrder(context.Background(), 1)
+				require.Error(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestGetCart(t *testing.T) {
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT * FROM carts WHERE user_id=?").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(3, 1))
+				mock.ExpectQuery("SELECT * FROM cart_items WHERE cart_id=? ORDER BY id").WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "cart_id", "product_id", "quantity"}).
+						AddRow(1, 3, 1, 2).
+						AddRow(2, 3, 2, 1))
+
+				c, err := st.GetCart(context.Background(), 1)
+				require.NoError(t, err)
+				require.Equal(t, int64(3), c.ID)
+				require.Len(t, c.Items, 2)
+				require.Equal(t, int64(2), c.Items[0].Quantity)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "no cart yet",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT * FROM carts WHERE user_id=?").WithArgs(1).
+					WillReturnError(sql.ErrNoRows)
+
+				c, err := st.GetCart(context.Background(), 1)
+				require.NoError(t, err)
+				require.Equal(t, int64(1), c.UserID)
+				require.Empty(t, c.Items)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestSetCartItem(t *testing.T) {
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO carts (user_id) VALUES (?) ON DUPLICATE KEY UPDATE id=LAST_INSERT_ID(id), updated_at=?").
+					WithArgs(1, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectExec("INSERT INTO cart_items (cart_id, product_id, variant_id, quantity) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE quantity=VALUES(quantity), updated_at=?").
+					WithArgs(3, 2, 0, 5, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+
+				err := st.SetCartItem(context.Background(), 1, 2, 0, 5)
+				require.NoError(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "failed setting item",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO carts (user_id) VALUES (?) ON DUPLICATE KEY UPDATE id=LAST_INSERT_ID(id), updated_at=?").
+					WithArgs(1, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectExec("INSERT INTO cart_items (cart_id, product_id, variant_id, quantity) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE quantity=VALUES(quantity), updated_at=?").
+					WillReturnError(fmt.Errorf("error setting cart item"))
+				mock.ExpectRollback()
+
+				err := st.SetCartItem(context.Background(), 1, 2, 0, 5)
+				require.Error(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestRemoveCartItem(t *testing.T) {
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE cart_items FROM cart_items JOIN carts ON carts.id=cart_items.cart_id WHERE carts.user_id=? AND cart_items.product_id=? AND cart_items.variant_id=?").
+					WithArgs(1, 2, 0).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				err := st.RemoveCartItem(context.Background(), 1, 2, 0)
+				require.NoError(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "not in cart",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE cart_items FROM cart_items JOIN carts ON carts.id=cart_items.cart_id WHERE carts.user_id=? AND cart_items.product_id=? AND cart_items.variant_id=?").
+					WithArgs(1, 2, 0).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				err := st.RemoveCartItem(context.Background(), 1, 2, 0)
+				require.ErrorIs(t, err, sql.ErrNoRows)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestCreateReview(t *testing.T) {
+	const updateRating = "UPDATE products SET rating=(SELECT COALESCE(ROUND(AVG(rating)), 0) FROM reviews WHERE product_id=? AND hidden=false), num_reviews=(SELECT COUNT(*) FROM reviews WHERE product_id=? AND hidden=false) WHERE id=?"
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO reviews (product_id, user_id, rating, title, body) VALUES (?, ?, ?, ?, ?)").
+					WithArgs(2, 1, 4, "good", "works").
+					WillReturnResult(sqlmock.NewResult(7, 1))
+				mock.ExpectExec(updateRating).WithArgs(2, 2, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				r, err := st.CreateReview(context.Background(), &Review{ProductID: 2, UserID: 1, Rating: 4, Title: "good", Body: "works"})
+				require.NoError(t, err)
+				require.Equal(t, int64(7), r.ID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "already reviewed",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO reviews (product_id, user_id, rating, title, body) VALUES (?, ?, ?, ?, ?)").
+					WillReturnError(&mysql.MySQLError{Number: mysqlErrDupEntry, Message: "Duplicate entry"})
+				mock.ExpectRollback()
+
+				_, err := st.CreateReview(context.Background(), &Review{ProductID: 2, UserID: 1, Rating: 4})
+				require.ErrorIs(t, err, ErrReviewExists)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "hidden and deleted reviews update the rating",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT product_id FROM reviews WHERE id=? FOR UPDATE").WithArgs(7).
+					WillReturnRows(sqlmock.NewRows([]string{"product_id"}).AddRow(2))
+				mock.ExpectExec("UPDATE reviews SET hidden=?, updated_at=? WHERE id=?").
+					WithArgs(true, sqlmock.AnyArg(), 7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(updateRating).WithArgs(2, 2, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT product_id FROM reviews WHERE id=? FOR UPDATE").WithArgs(7).
+					WillReturnRows(sqlmock.NewRows([]string{"product_id"}).AddRow(2))
+				mock.ExpectExec("DELETE FROM reviews WHERE id=?").WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(updateRating).WithArgs(2, 2, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				require.NoError(t, st.SetReviewHidden(context.Background(), 7, true))
+				require.NoError(t, st.DeleteReview(context.Background(), 7))
+
+				err := mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestCategories(t *testing.T) {
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "create",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				parentID := int64(1)
+				mock.ExpectExec("INSERT INTO categories (parent_id, name, slug, sort_order) VALUES (?, ?, ?, ?)").
+					WithArgs(parentID, "Running", "running", 0).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+
+				c, err := st.CreateCategory(context.Background(), &Category{ParentID: &parentID, Name: "Running", Slug: "running"})
+				require.NoError(t, err)
+				require.Equal(t, int64(2), c.ID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "slug taken",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO categories (parent_id, name, slug, sort_order) VALUES (?, ?, ?, ?)").
+					WillReturnError(&mysql.MySQLError{Number: mysqlErrDupEntry, Message: "Duplicate entry"})
+
+				_, err := st.CreateCategory(context.Background(), &Category{Name: "Running", Slug: "running"})
+				require.ErrorIs(t, err, ErrCategoryExists)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "list in display order",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "parent_id", "name", "slug", "sort_order", "created_at", "updated_at"}).
+					AddRow(1, nil, "Shoes", "shoes", 0, time.Time{}, nil).
+					AddRow(2, 1, "Running", "running", 0, time.Time{}, nil)
+				mock.ExpectQuery("SELECT * FROM categories ORDER BY sort_order, name, id").WillReturnRows(rows)
+
+				categories, err := st.ListCategories(context.Background())
+				require.NoError(t, err)
+				require.Len(t, categories, 2)
+				require.Nil(t, categories[0].ParentID)
+				require.Equal(t, int64(1), *categories[1].ParentID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "delete category in use",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM categories WHERE id=?").WithArgs(1).
+					WillReturnError(&mysql.MySQLError{Number: mysqlErrRowIsReferenced, Message: "Cannot delete or update a parent row"})
+
+				err := st.DeleteCategory(context.Background(), 1)
+				require.ErrorIs(t, err, ErrCategoryInUse)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestVariants(t *testing.T) {
+	stockUpdate := "UPDATE products SET count_in_stock=(SELECT COALESCE(SUM(count_in_stock), 0) FROM product_variants WHERE product_id=?) WHERE id=?"
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "create updates product stock",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO product_variants (product_id, sku, options, price, image, count_in_stock) VALUES (?, ?, ?, ?, ?, ?)").
+					WithArgs(1, "TS-M-RED", `{"color":"Red","size":"M"}`, nil, "", 5).
+					WillReturnResult(sqlmock.NewResult(3, 1))
+				expectStockLevel(mock, StockMovement{WarehouseID: MainWarehouseID, ProductID: 1, VariantID: 3, Kind: StockReceipt, Quantity: 5, Reason: "opening stock"})
+				mock.ExpectExec(stockUpdate).WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				v, err := st.CreateVariant(context.Background(), &Variant{
+					ProductID:    1,
+					SKU:          "TS-M-RED",
+					Options:      VariantOptions{"size": "M", "color": "Red"},
+					CountInStock: 5,
+				})
+				require.NoError(t, err)
+				require.Equal(t, int64(3), v.ID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "sku taken",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO product_variants (product_id, sku, options, price, image, count_in_stock) VALUES (?, ?, ?, ?, ?, ?)").
+					WillReturnError(&mysql.MySQLError{Number: mysqlErrDupEntry, Message: "Duplicate entry"})
+				mock.ExpectRollback()
+
+				_, err := st.CreateVariant(context.Background(), &Variant{ProductID: 1, SKU: "TS-M-RED"})
+				require.ErrorIs(t, err, ErrSKUExists)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "list scans options and price override",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "product_id", "sku", "options", "price", "image", "count_in_stock", "created_at", "updated_at"}).
+					AddRow(1, 1, "TS-M-RED", []byte(`{"size":"M","color":"Red"}`), nil, "", 5, time.Time{}, nil).
+					AddRow(2, 1, "TS-L-RED", []byte(`{"size":"L","color":"Red"}`), "24.99", "red.jpg", 0, time.Time{}, nil)
+				mock.ExpectQuery("SELECT * FROM product_variants WHERE product_id IN (?) ORDER BY product_id, id").WithArgs(1).WillReturnRows(rows)
+
+				variants, err := st.ListProductVariants(context.Background(), []int64{1})
+				require.NoError(t, err)
+				require.Len(t, variants, 2)
+				require.Equal(t, VariantOptions{"size": "M", "color": "Red"}, variants[0].Options)
+				require.Nil(t, variants[0].Price)
+				require.Equal(t, money.New(2499, money.DefaultCurrency), *variants[1].Price)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "delete removes cart items and writes off stock",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT product_id FROM product_variants WHERE id=? FOR UPDATE").WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"product_id"}).AddRow(1))
+				mock.ExpectExec("DELETE FROM cart_items WHERE variant_id=?").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM wishlist_items WHERE variant_id=?").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM product_variants WHERE id=?").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT * FROM stock_levels WHERE product_id=? AND variant_id=? AND quantity<>0 ORDER BY warehouse_id FOR UPDATE").WithArgs(1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "product_id", "variant_id", "quantity"}).AddRow(2, 1, 3, 4))
+				mock.ExpectExec("INSERT INTO stock_movements (warehouse_id, product_id, variant_id, kind, quantity, reason, actor_id, order_id, other_warehouse_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)").
+					WithArgs(2, 1, 3, StockAdjustment, -4, "variant 3 deleted", nil, nil, nil).WillReturnResult(sqlmock.NewResult(9, 1))
+				mock.ExpectExec("DELETE FROM stock_levels WHERE product_id=? AND variant_id=?").WithArgs(1, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(stockUpdate).WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				err := st.DeleteVariant(context.Background(), 3)
+				require.NoError(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "delete ordered variant",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT product_id FROM product_variants WHERE id=? FOR UPDATE").WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"product_id"}).AddRow(1))
+				mock.ExpectExec("DELETE FROM cart_items WHERE variant_id=?").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM wishlist_items WHERE variant_id=?").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM product_variants WHERE id=?").WithArgs(3).
+					WillReturnError(&mysql.MySQLError{Number: mysqlErrRowIsReferenced, Message: "Cannot delete or update a parent row"})
+				mock.ExpectRollback()
+
+				err := st.DeleteVariant(context.Background(), 3)
+				require.ErrorIs(t, err, ErrVariantInUse)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "order checks variant stock",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				variantID := int64(3)
+				order := &Order{
+					Items: []OrderItem{
+						{Name: "T-shirt - M / Red", Quantity: 2, ProductID: 1, VariantID: &variantID, SKU: "TS-M-RED"},
+					},
+				}
+
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id, name, count_in_stock FROM products WHERE id IN (?) ORDER BY id FOR UPDATE").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "count_in_stock"}).AddRow(1, "T-shirt", 10))
+				mock.ExpectQuery("SELECT id, product_id, sku, count_in_stock FROM product_variants WHERE id IN (?) ORDER BY id FOR UPDATE").WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "sku", "count_in_stock"}).AddRow(3, 1, "TS-M-RED", 1))
+				mock.ExpectRollback()
+
+				_, err := st.CreateOrder(context.Background(), order, nil)
+				require.ErrorIs(t, err, ErrInsufficientStock)
+
+				var stockErr *InsufficientStockError
+				require.ErrorAs(t, err, &stockErr)
+				require.Equal(t, "TS-M-RED", stockErr.SKU)
+				require.Equal(t, int64(1), stockErr.Available)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestInventory(t *testing.T) {
+	lockProduct := "SELECT id, name, count_in_stock FROM products WHERE id IN (?) ORDER BY id FOR UPDATE"
+	lockLevel := "SELECT quantity FROM stock_levels WHERE warehouse_id=? AND product_id=? AND variant_id=? FOR UPDATE"
+	transfer := func() []*StockMovement {
+		from, to := int64(1), int64(2)
+		return []*StockMovement{
+			{WarehouseID: from, ProductID: 1, Kind: StockTransfer, Quantity: -3, Reason: "rebalance", OtherWarehouseID: &to},
+			{WarehouseID: to, ProductID: 1, Kind: StockTransfer, Quantity: 3, Reason: "rebalance", OtherWarehouseID: &from},
+		}
+	}
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "warehouse code taken",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO warehouses (code, name) VALUES (?, ?)").WithArgs("EAST", "East").
+					WillReturnError(&mysql.MySQLError{Number: mysqlErrDupEntry, Message: "Duplicate entry"})
+
+				_, err := st.CreateWarehouse(context.Background(), &Warehouse{Code: "EAST", Name: "East"})
+				require.ErrorIs(t, err, ErrWarehouseExists)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "transfer",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mvs := transfer()
+
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockProduct).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "count_in_stock"}).AddRow(1, "test product", 5))
+				mock.ExpectQuery(lockLevel).WithArgs(1, 1, 0).WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(3))
+				for _, m := range mvs {
+					expectStockMovement(mock, *m)
+				}
+				mock.ExpectCommit()
+
+				err := st.RecordStockMovements(context.Background(), mvs)
+				require.NoError(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "insufficient stock in warehouse",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockProduct).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "count_in_stock"}).AddRow(1, "test product", 5))
+				mock.ExpectQuery(lockLevel).WithArgs(1, 1, 0).WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(2))
+				mock.ExpectRollback()
+
+				err := st.RecordStockMovements(context.Background(), transfer())
+				var stockErr *InsufficientStockError
+				require.ErrorAs(t, err, &stockErr)
+				require.Equal(t, int64(3), stockErr.Requested)
+				require.Equal(t, int64(2), stockErr.Available)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "list movements of a warehouse",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "warehouse_id", "product_id", "variant_id", "kind", "quantity", "reason", "actor_id", "order_id", "other_warehouse_id", "created_at"}).
+					AddRow(9, 2, 1, 0, "transfer", 3, "rebalance", 4, nil, 1, time.Now())
+				mock.ExpectQuery("SELECT * FROM stock_movements WHERE product_id=? AND warehouse_id=? AND id<? ORDER BY id DESC LIMIT ?").WithArgs(1, 2, 10, 20).WillReturnRows(rows)
+
+				movements, err := st.ListStockMovements(context.Background(), &ListStockMovementsParams{ProductID: 1, WarehouseID: 2, BeforeID: 10, Limit: 20})
+				require.NoError(t, err)
+				require.Len(t, movements, 1)
+				require.Equal(t, StockTransfer, movements[0].Kind)
+				require.Equal(t, int64(1), *movements[0].OtherWarehouseID)
+				require.Nil(t, movements[0].OrderID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestPromotions(t *testing.T) {
+	orderInsert := "INSERT INTO orders (payment_method, shipping_address, billing_address, shipping_method, tax_price, shipping_price, discount_price, coupon_code, discounts, total_price, user_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	itemInsert := "INSERT INTO order_items (name, quantity, image, price, product_id, variant_id, sku, scheduled_price_id, discount_price, tax_rate, tax_jurisdiction, tax_price, tax_inclusive, order_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	newOrder := func() *Order {
+		return &Order{
+			UserID:        7,
+			CouponCode:    "SAVE10",
+			DiscountPrice: money.New(200, money.DefaultCurrency),
+			Discounts: OrderDiscounts{
+				{PromotionID: 2, Name: "save 10%", Code: "SAVE10", Amount: money.New(200, money.DefaultCurrency)},
+			},
+			Items: []OrderItem{
+				{Name: "test product", Quantity: 1, Price: money.New(1999, money.DefaultCurrency), ProductID: 1},
+			},
+		}
+	}
+	expectStock := func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SELECT id, name, count_in_stock FROM products WHERE id IN (?) ORDER BY id FOR UPDATE").WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "count_in_stock"}).AddRow(1, "test product", 10))
+		mock.ExpectQuery("SELECT * FROM stock_levels WHERE product_id=? AND variant_id=? AND quantity>0 ORDER BY warehouse_id FOR UPDATE").WithArgs(1, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "product_id", "variant_id", "quantity"}).AddRow(MainWarehouseID, 1, 0, 10))
+	}
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "code taken",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO promotions (name, code, type, discount_rate, discount_amount, buy_quantity, get_quantity, product_id, category_id, min_subtotal, starts_at, ends_at, usage_limit, per_customer_limit, active) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
+					WillReturnError(&mysql.MySQLError{Number: mysqlErrDupEntry, Message: "Duplicate entry"})
+
+				code := "SAVE10"
+				_, err := st.CreatePromotion(context.Background(), &Promotion{Name: "save 10%", Code: &code, Type: PercentageOff, DiscountRate: 1000})
+				require.ErrorIs(t, err, ErrPromotionExists)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "delete redeemed promotion",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM promotions WHERE id=?").WithArgs(2).
+					WillReturnError(&mysql.MySQLError{Number: mysqlErrRowIsReferenced, Message: "Cannot delete or update a parent row"})
+
+				err := st.DeletePromotion(context.Background(), 2)
+				require.ErrorIs(t, err, ErrPromotionInUse)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "order redeems its promotions",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectStock(mock)
+				mock.ExpectExec(orderInsert).
+					WithArgs("", nil, nil, "", "0.00", "0.00", "2.00", "SAVE10", `[{"promotion_id":2,"name":"save 10%","code":"SAVE10","amount":{"amount":200,"currency":"USD"}}]`, "0.00", 7).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(itemInsert).WillReturnResult(sqlmock.NewResult(1, 1))
+				userID, orderID := int64(7), int64(1)
+				expectStockMovement(mock, StockMovement{WarehouseID: MainWarehouseID, ProductID: 1, Kind: StockSale, Quantity: -1, Reason: "order 1", ActorID: &userID, OrderID: &orderID})
+				mock.ExpectQuery("SELECT id, name, usage_limit, per_customer_limit FROM promotions WHERE id=? FOR UPDATE").WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "usage_limit", "per_customer_limit"}).AddRow(2, "save 10%", 100, 1))
+				mock.ExpectQuery("SELECT COUNT(*) FROM promotion_redemptions WHERE promotion_id=?").WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(99))
+				mock.ExpectQuery("SELECT COUNT(*) FROM promotion_redemptions WHERE promotion_id=? AND user_id=?").WithArgs(2, 7).
+					WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(0))
+				mock.ExpectExec("INSERT INTO promotion_redemptions (promotion_id, order_id, user_id, amount) VALUES (?, ?, ?, ?)").WithArgs(2, 1, 7, "2.00").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+
+				_, err := st.CreateOrder(context.Background(), newOrder(), nil)
+				require.NoError(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "usage limit reached rolls the order back",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectStock(mock)
+				mock.ExpectExec(orderInsert).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(itemInsert).WillReturnResult(sqlmock.NewResult(1, 1))
+				userID, orderID := int64(7), int64(1)
+				expectStockMovement(mock, StockMovement{WarehouseID: MainWarehouseID, ProductID: 1, Kind: StockSale, Quantity: -1, Reason: "order 1", ActorID: &userID, OrderID: &orderID})
+				mock.ExpectQuery("SELECT id, name, usage_limit, per_customer_limit FROM promotions WHERE id=? FOR UPDATE").WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "usage_limit", "per_customer_limit"}).AddRow(2, "save 10%", 100, 0))
+				mock.ExpectQuery("SELECT COUNT(*) FROM promotion_redemptions WHERE promotion_id=?").WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(100))
+				mock.ExpectRollback()
+
+				_, err := st.CreateOrder(context.Background(), newOrder(), nil)
+				var limitErr *PromotionLimitError
+				require.ErrorAs(t, err, &limitErr)
+				require.False(t, limitErr.PerCustomer)
+				require.Equal(t, int64(2), limitErr.PromotionID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestScheduledPrices(t *testing.T) {
+	orderInsert := "INSERT INTO orders (payment_method, shipping_address, billing_address, shipping_method, tax_price, shipping_price, discount_price, coupon_code, discounts, total_price, user_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	itemInsert := "INSERT INTO order_items (name, quantity, image, price, product_id, variant_id, sku, scheduled_price_id, discount_price, tax_rate, tax_jurisdiction, tax_price, tax_inclusive, order_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	priceLock := "SELECT * FROM product_prices WHERE id=? FOR UPDATE"
+	priceRow := func(price string, startsAt, endsAt time.Time, sold int64) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "product_id", "variant_id", "price", "starts_at", "ends_at", "quantity_cap", "quantity_sold", "created_at", "updated_at"}).
+			AddRow(3, 1, nil, price, startsAt, endsAt, 10, sold, startsAt, nil)
+	}
+	priceID := int64(3)
+	newOrder := func() *Order {
+		return &Order{
+			UserID: 7,
+			Items: []OrderItem{
+				{Name: "test product", Quantity: 2, Price: money.New(999, money.DefaultCurrency), ProductID: 1, ScheduledPriceID: &priceID},
+			},
+		}
+	}
+	expectStock := func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SELECT id, name, count_in_stock FROM products WHERE id IN (?) ORDER BY id FOR UPDATE").WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "count_in_stock"}).AddRow(1, "test product", 10))
+		mock.ExpectQuery("SELECT * FROM stock_levels WHERE product_id=? AND variant_id=? AND quantity>0 ORDER BY warehouse_id FOR UPDATE").WithArgs(1, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "product_id", "variant_id", "quantity"}).AddRow(MainWarehouseID, 1, 0, 10))
+	}
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "list running",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				now := time.Now()
+				mock.ExpectQuery("SELECT * FROM product_prices WHERE product_id IN (?, ?) AND starts_at<=? AND ends_at>? AND (quantity_cap IS NULL OR quantity_sold<quantity_cap) ORDER BY product_id, id").WithArgs(1, 2, now, now).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "variant_id", "price", "starts_at", "ends_at", "quantity_cap", "quantity_sold", "created_at", "updated_at"}).
+						AddRow(3, 1, nil, "9.99", now.Add(-time.Hour), now.Add(time.Hour), 10, 4, now, nil))
+
+				prices, err := st.ListRunningScheduledPrices(context.Background(), []int64{1, 2}, now)
+				require.NoError(t, err)
+				require.Len(t, prices, 1)
+				require.Equal(t, money.New(999, money.DefaultCurrency), prices[0].Price)
+				require.Equal(t, int64(10), *prices[0].QuantityCap)
+				require.Equal(t, int64(4), prices[0].QuantitySold)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "order counts the units sold",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectStock(mock)
+				mock.ExpectExec(orderInsert).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(itemInsert).WithArgs("test product", 2, "", "9.99", 1, nil, "", 3, "0.00", 0, "", "0.00", false, 1).WillReturnResult(sqlmock.NewResult(1, 1))
+				userID, orderID := int64(7), int64(1)
+				expectStockMovement(mock, StockMovement{WarehouseID: MainWarehouseID, ProductID: 1, Kind: StockSale, Quantity: -2, Reason: "order 1", ActorID: &userID, OrderID: &orderID})
+				mock.ExpectQuery(priceLock).WithArgs(3).
+					WillReturnRows(priceRow("9.99", time.Now().Add(-time.Hour), time.Now().Add(time.Hour), 8))
+				mock.ExpectExec("UPDATE product_prices SET quantity_sold=quantity_sold+? WHERE id=?").WithArgs(2, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				_, err := st.CreateOrder(context.Background(), newOrder(), nil)
+				require.NoError(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "sold out rolls the order back",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectStock(mock)
+				mock.ExpectExec(orderInsert).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(itemInsert).WillReturnResult(sqlmock.NewResult(1, 1))
+				userID, orderID := int64(7), int64(1)
+				expectStockMovement(mock, StockMovement{WarehouseID: MainWarehouseID, ProductID: 1, Kind: StockSale, Quantity: -2, Reason: "order 1", ActorID: &userID, OrderID: &orderID})
+				mock.ExpectQuery(priceLock).WithArgs(3).
+					WillReturnRows(priceRow("9.99", time.Now().Add(-time.Hour), time.Now().Add(time.Hour), 9))
+				mock.ExpectRollback()
+
+				_, err := st.CreateOrder(context.Background(), newOrder(), nil)
+				var soldOutErr *ScheduledPriceSoldOutError
+				require.ErrorAs(t, err, &soldOutErr)
+				require.ErrorIs(t, err, ErrScheduledPriceSoldOut)
+				require.Equal(t, int64(1), soldOutErr.Available)
+				require.False(t, soldOutErr.Changed)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "ended sale rolls the order back",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectStock(mock)
+				mock.ExpectExec(orderInsert).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(itemInsert).WillReturnResult(sqlmock.NewResult(1, 1))
+				userID, orderID := int64(7), int64(1)
+				expectStockMovement(mock, StockMovement{WarehouseID: MainWarehouseID, ProductID: 1, Kind: StockSale, Quantity: -2, Reason: "order 1", ActorID: &userID, OrderID: &orderID})
+				mock.ExpectQuery(priceLock).WithArgs(3).
+					WillReturnRows(priceRow("9.99", time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour), 4))
+				mock.ExpectRollback()
+
+				_, err := st.CreateOrder(context.Background(), newOrder(), nil)
+				var soldOutErr *ScheduledPriceSoldOutError
+				require.ErrorAs(t, err, &soldOutErr)
+				require.ErrorIs(t, err, ErrScheduledPriceSoldOut)
+				require.True(t, soldOutErr.Changed)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "changed price rolls the order back",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectStock(mock)
+				mock.ExpectExec(orderInsert).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(itemInsert).WillReturnResult(sqlmock.NewResult(1, 1))
+				userID, orderID := int64(7), int64(1)
+				expectStockMovement(mock, StockMovement{WarehouseID: MainWarehouseID, ProductID: 1, Kind: StockSale, Quantity: -2, Reason: "order 1", ActorID: &userID, OrderID: &orderID})
+				mock.ExpectQuery(priceLock).WithArgs(3).
+					WillReturnRows(priceRow("12.99", time.Now().Add(-time.Hour), time.Now().Add(time.Hour), 4))
+				mock.ExpectRollback()
+
+				_, err := st.CreateOrder(context.Background(), newOrder(), nil)
+				var soldOutErr *ScheduledPriceSoldOutError
+				require.ErrorAs(t, err, &soldOutErr)
+				require.True(t, soldOutErr.Changed)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestTaxRates(t *testing.T) {
+	columns := []string{"id", "country", "region", "tax_class", "name", "rate", "inclusive", "created_at", "updated_at"}
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "jurisdiction and tax class taken",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO tax_rates (country, region, tax_class, name, rate, inclusive) VALUES (?, ?, ?, ?, ?, ?)").
+					WithArgs("US", "CA", DefaultTaxClass, "California", 725, false).
+					WillReturnError(&mysql.MySQLError{Number: mysqlErrDupEntry, Message: "Duplicate entry"})
+
+				_, err := st.CreateTaxRate(context.Background(), &TaxRate{Country: "US", Region: "CA", TaxClass: DefaultTaxClass, Name: "California", Rate: 725})
+				require.ErrorIs(t, err, ErrTaxRateExists)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "applicable rates",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, "", "", "food", "food", 0, false, time.Now(), nil).
+					AddRow(2, "US", "", DefaultTaxClass, "US", 500, false, time.Now(), nil).
+					AddRow(3, "US", "CA", DefaultTaxClass, "California", 725, false, time.Now(), nil)
+				mock.ExpectQuery("SELECT * FROM tax_rates WHERE (country=? AND region IN (?, '')) OR (country='' AND region='') ORDER BY country, region, tax_class").
+					WithArgs("US", "CA").WillReturnRows(rows)
+
+				rates, err := st.ListApplicableTaxRates(context.Background(), "US", "CA")
+				require.NoError(t, err)
+				require.Len(t, rates, 3)
+				require.Equal(t, int64(725), rates[2].Rate)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "exempt unknown user",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE users SET tax_exempt=?, updated_at=? WHERE id=?").
+					WithArgs(true, sqlmock.AnyArg(), 7).WillReturnResult(sqlmock.NewResult(0, 0))
+
+				err := st.SetUserTaxExempt(context.Background(), 7, true)
+				require.ErrorIs(t, err, sql.ErrNoRows)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestAddresses(t *testing.T) {
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "new default address clears the old one",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE addresses SET is_default=false WHERE user_id=?").WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO addresses (user_id, label, name, line1, line2, city, region, postal_code, country, is_default) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
+					WithArgs(7, "home", "test", "1 Main St", "", "Springfield", "", "12345", "US", true).
+					WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectCommit()
+
+				a, err := st.CreateAddress(context.Background(), &UserAddress{UserID: 7, Label: "home", Name: "test", Line1: "1 Main St", City: "Springfield", PostalCode: "12345", Country: "US", IsDefault: true})
+				require.NoError(t, err)
+				require.Equal(t, int64(3), a.ID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "failed inserting address",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO addresses (user_id, label, name, line1, line2, city, region, postal_code, country, is_default) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
+					WillReturnError(fmt.Errorf("error inserting address"))
+				mock.ExpectRollback()
+
+				_, err := st.CreateAddress(context.Background(), &UserAddress{UserID: 7, Country: "US"})
+				require.Error(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestShippingRates(t *testing.T) {
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "zone destinations stored as JSON",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO shipping_zones (name, countries, postal_prefixes) VALUES (?, ?, ?)").
+					WithArgs("US", `["US"]`, "[]").WillReturnResult(sqlmock.NewResult(1, 1))
+
+				z, err := st.CreateShippingZone(context.Background(), &ShippingZone{Name: "US", Countries: StringList{"US"}})
+				require.NoError(t, err)
+				require.Equal(t, int64(1), z.ID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "methods of zones",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "zone_id", "name", "basis", "tiers", "free_above", "active", "created_at", "updated_at"}).
+					AddRow(1, 1, "Standard", "subtotal", `[{"min":0,"price":{"amount":800,"currency":"USD"}}]`, "100.00", true, time.Now(), nil).
+					AddRow(2, 2, "Express", "weight", `[{"min":0,"price":{"amount":1500,"currency":"USD"}},{"min":2000,"price":{"amount":2500,"currency":"USD"}}]`, nil, false, time.Now(), nil)
+				mock.ExpectQuery("SELECT * FROM shipping_methods WHERE zone_id IN (?, ?) ORDER BY zone_id, id").WithArgs(1, 2).WillReturnRows(rows)
+
+				methods, err := st.ListShippingMethods(context.Background(), []int64{1, 2})
+				require.NoError(t, err)
+				require.Len(t, methods, 2)
+				require.Equal(t, ShippingTiers{{Min: 0, Price: money.New(800, money.DefaultCurrency)}}, methods[0].Tiers)
+				require.Equal(t, money.New(10000, money.DefaultCurrency), *methods[0].FreeAbove)
+				require.Equal(t, ByWeight, methods[1].Basis)
+				require.Nil(t, methods[1].FreeAbove)
+				require.False(t, methods[1].Active)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "no zones",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				methods, err := st.ListShippingMethods(context.Background(), nil)
+				require.NoError(t, err)
+				require.Empty(t, methods)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestPayments(t *testing.T) {
+	paymentLock := "SELECT * FROM payments WHERE id=? FOR UPDATE"
+	refundCount := "SELECT COUNT(*) FROM payment_refunds WHERE payment_id=? AND refund_key=?"
+	paymentRow := func(refunded string) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "order_id", "provider", "reference", "status", "amount", "refunded_amount", "message", "created_at", "updated_at"}).
+			AddRow(1, 7, StoreCreditProvider, "", "captured", "25.00", refunded, "", time.Now(), nil)
+	}
+	captured := func() *Payment {
+		return &Payment{ID: 1, OrderID: 7, Provider: StoreCreditProvider, Status: payments.StatusCaptured, Amount: money.New(2500, money.DefaultCurrency), RefundedAmount: money.New(0, money.DefaultCurrency)}
+	}
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "create payment",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("SELECT id FROM orders WHERE id=? FOR UPDATE").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT COUNT(*) FROM payments WHERE order_id=? AND status IN ('pending', 'authorized', 'captured') AND provider NOT IN (?, ?, ?)").WithArgs(1, GiftCardProvider, StoreCreditProvider, LoyaltyPointsProvider).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectExec("INSERT INTO payments (order_id, provider, reference, status, amount, message) VALUES (?, ?, ?, ?, ?, ?)").
+					WithArgs(1, "fake", "", payments.StatusPending, "25.00", "").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+
+				p, err := st.CreatePayment(context.Background(), &Payment{OrderID: 1, Provider: "fake", Status: payments.StatusPending, Amount: money.New(2500, money.DefaultCurrency)})
+				require.NoError(t, err)
+				require.Equal(t, int64(1), p.ID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "payment in progress",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("SELECT id FROM orders WHERE id=? FOR UPDATE").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT COUNT(*) FROM payments WHERE order_id=? AND status IN ('pending', 'authorized', 'captured') AND provider NOT IN (?, ?, ?)").WithArgs(1, GiftCardProvider, StoreCreditProvider, LoyaltyPointsProvider).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectRollback()
+
+				_, err := st.CreatePayment(context.Background(), &Payment{OrderID: 1, Provider: "fake", Status: payments.StatusPending, Amount: money.New(2500, money.DefaultCurrency)})
+				require.ErrorIs(t, err, ErrPaymentInProgress)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "payment by reference",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "order_id", "provider", "reference", "status", "amount", "message", "created_at", "updated_at"}).
+					AddRow(1, 1, "fake", "fake_1", "captured", "25.00", "", time.Now(), nil)
+				mock.ExpectQuery("SELECT * FROM payments WHERE provider=? AND reference=?").WithArgs("fake", "fake_1").WillReturnRows(rows)
+
+				p, err := st.GetPaymentByReference(context.Background(), "fake", "fake_1")
+				require.NoError(t, err)
+				require.Equal(t, payments.StatusCaptured, p.Status)
+				require.Equal(t, money.New(2500, money.DefaultCurrency), p.Amount)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "refund payment with its store credit",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				order := int64(7)
+				mock.ExpectBegin()
+				mock.ExpectQuery(paymentLock).WithArgs(1).WillReturnRows(paymentRow("0.00"))
+				mock.ExpectQuery(refundCount).WithArgs(1, "order 7").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectExec("UPDATE payments SET status=?, refunded_amount=?, updated_at=? WHERE id=?").
+					WithArgs(payments.StatusRefunded, "25.00", sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT balance FROM store_credits WHERE user_id=? FOR UPDATE").WithArgs(3).WillReturnError(sql.ErrNoRows)
+				mock.ExpectExec("INSERT INTO store_credits (user_id, balance) VALUES (?, ?) ON DUPLICATE KEY UPDATE balance=balance+VALUES(balance), updated_at=CURRENT_TIMESTAMP").WithArgs(3, "25.00").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO store_credit_transactions (user_id, kind, amount, order_id, actor_id, reason) VALUES (?, ?, ?, ?, ?, ?)").
+					WithArgs(3, BalanceRefund, "25.00", order, nil, "payment 1 refunded").WillReturnResult(sqlmock.NewResult(4, 1))
+				mock.ExpectExec("INSERT INTO payment_refunds (payment_id, refund_key, amount, status) VALUES (?, ?, ?, ?)").WithArgs(1, "order 7", "25.00", RefundApplied).WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectCommit()
+
+				amount := money.New(2500, money.DefaultCurrency)
+				r := &PaymentRefund{Key: "order 7", Amount: amount, StoreCredit: &StoreCreditTransaction{UserID: 3, Kind: BalanceRefund, Amount: amount, OrderID: &order, Reason: "payment 1 refunded"}}
+				p, err := st.RefundPayment(context.Background(), captured(), r)
+				require.NoError(t, err)
+				require.Equal(t, payments.StatusRefunded, p.Status)
+				require.Equal(t, amount, p.RefundedAmount)
+				require.Equal(t, int64(2), r.ID)
+				require.Equal(t, int64(4), r.StoreCredit.ID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "refund through the provider is recorded pending",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(paymentLock).WithArgs(1).WillReturnRows(paymentRow("0.00"))
+				mock.ExpectQuery(refundCount).WithArgs(1, "return 3").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectExec("UPDATE payments SET status=?, refunded_amount=?, updated_at=? WHERE id=?").
+					WithArgs(payments.StatusCaptured, "10.00", sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO payment_refunds (payment_id, refund_key, amount, status) VALUES (?, ?, ?, ?)").WithArgs(1, "return 3", "10.00", RefundPending).WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectCommit()
+
+				r := &PaymentRefund{Key: "return 3", Amount: money.New(1000, money.DefaultCurrency)}
+				_, err := st.RefundPayment(context.Background(), captured(), r)
+				require.NoError(t, err)
+				require.Equal(t, RefundPending, r.Status)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "refund applied already",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(paymentLock).WithArgs(1).WillReturnRows(paymentRow("25.00"))
+				mock.ExpectQuery(refundCount).WithArgs(1, "order 7").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectRollback()
+
+				_, err := st.RefundPayment(context.Background(), captured(), &PaymentRefund{Key: "order 7", Amount: money.New(2500, money.DefaultCurrency)})
+				require.ErrorIs(t, err, ErrRefundApplied)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "payment refunded since read",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(paymentLock).WithArgs(1).WillReturnRows(paymentRow("10.00"))
+				mock.ExpectQuery(refundCount).WithArgs(1, "return 3").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectRollback()
+
+				p := captured()
+				_, err := st.RefundPayment(context.Background(), p, &PaymentRefund{Key: "return 3", Amount: money.New(1000, money.DefaultCurrency)})
+				require.ErrorIs(t, err, ErrPaymentChanged)
+				require.True(t, p.RefundedAmount.IsZero())
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "apply payment refund",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE payment_refunds SET status=? WHERE id=?").WithArgs(RefundApplied, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				err := st.ApplyPaymentRefund(context.Background(), 2)
+				require.NoError(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "order payment refunds",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT payment_refunds.* FROM payment_refunds JOIN payments ON payments.id=payment_refunds.payment_id WHERE payments.order_id=? ORDER BY payment_refunds.id").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "payment_id", "refund_key", "amount"}).AddRow(2, 1, "return 3", "10.00").AddRow(3, 1, "order 1", "15.00"))
+
+				refunds, err := st.ListOrderPaymentRefunds(context.Background(), 1)
+				require.NoError(t, err)
+				require.Len(t, refunds, 2)
+				require.Equal(t, "return 3", refunds[0].Key)
+				require.Equal(t, money.New(1000, money.DefaultCurrency), refunds[0].Amount)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestGiftCards(t *testing.T) {
+	cardInsert := "INSERT INTO gift_cards (code, initial_amount, balance, expires_at, purchaser_id, order_id, issued_by, note) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	lockCard := "SELECT balance FROM gift_cards WHERE id=? FOR UPDATE"
+	txInsert := "INSERT INTO gift_card_transactions (gift_card_id, kind, amount, order_id, actor_id, reason) VALUES (?, ?, ?, ?, ?, ?)"
+	admin := int64(4)
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "issue",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(cardInsert).WithArgs("ABCD-EFGH", "50.00", "0.00", nil, nil, nil, admin, "goodwill").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(lockCard).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("0.00"))
+				mock.ExpectExec("UPDATE gift_cards SET balance=balance+? WHERE id=?").WithArgs("50.00", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(txInsert).WithArgs(1, BalanceIssue, "50.00", nil, admin, "issued").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+
+				g, err := st.CreateGiftCard(context.Background(), &GiftCard{Code: "ABCD-EFGH", InitialAmount: money.New(5000, money.DefaultCurrency), IssuedBy: &admin, Note: "goodwill"})
+				require.NoError(t, err)
+				require.Equal(t, int64(1), g.ID)
+				require.Equal(t, money.New(5000, money.DefaultCurrency), g.Balance)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "code taken",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(cardInsert).WithArgs("ABCD-EFGH", "50.00", "0.00", nil, nil, nil, nil, "").
+					WillReturnError(&mysql.MySQLError{Number: mysqlErrDupEntry, Message: "Duplicate entry"})
+				mock.ExpectRollback()
+
+				_, err := st.CreateGiftCard(context.Background(), &GiftCard{Code: "ABCD-EFGH", InitialAmount: money.New(5000, money.DefaultCurrency)})
+				require.ErrorIs(t, err, ErrGiftCardExists)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "insufficient balance",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockCard).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("10.00"))
+				mock.ExpectRollback()
+
+				err := st.RecordGiftCardTransaction(context.Background(), &GiftCardTransaction{GiftCardID: 1, Kind: BalanceAdjustment, Amount: money.New(-1500, money.DefaultCurrency), Reason: "correction"})
+				require.ErrorIs(t, err, ErrInsufficientBalance)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "get by code",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "code", "initial_amount", "balance", "expires_at", "purchaser_id", "order_id", "issued_by", "note", "created_at", "updated_at"}).
+					AddRow(1, "ABCD-EFGH", "50.00", "12.50", nil, 2, 7, nil, "", time.Now(), nil)
+				mock.ExpectQuery("SELECT * FROM gift_cards WHERE code=?").WithArgs("ABCD-EFGH").WillReturnRows(rows)
+
+				g, err := st.GetGiftCardByCode(context.Background(), "ABCD-EFGH")
+				require.NoError(t, err)
+				require.Equal(t, money.New(1250, money.DefaultCurrency), g.Balance)
+				require.Equal(t, int64(7), *g.OrderID)
+				require.Nil(t, g.ExpiresAt)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestStoreCredit(t *testing.T) {
+	lockCredit := "SELECT balance FROM store_credits WHERE user_id=? FOR UPDATE"
+	upsert := "INSERT INTO store_credits (user_id, balance) VALUES (?, ?) ON DUPLICATE KEY UPDATE balance=balance+VALUES(balance), updated_at=CURRENT_TIMESTAMP"
+	txInsert := "INSERT INTO store_credit_transactions (user_id, kind, amount, order_id, actor_id, reason) VALUES (?, ?, ?, ?, ?, ?)"
+	order := int64(7)
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "no store credit",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT balance FROM store_credits WHERE user_id=?").WithArgs(1).WillReturnError(sql.ErrNoRows)
+
+				balance, err := st.GetStoreCredit(context.Background(), 1)
+				require.NoError(t, err)
+				require.True(t, balance.IsZero())
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "first refund opens store credit",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockCredit).WithArgs(1).WillReturnError(sql.ErrNoRows)
+				mock.ExpectExec(upsert).WithArgs(1, "20.00").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(txInsert).WithArgs(1, BalanceRefund, "20.00", order, nil, "order 7 refunded").WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectCommit()
+
+				tx := &StoreCreditTransaction{UserID: 1, Kind: BalanceRefund, Amount: money.New(2000, money.DefaultCurrency), OrderID: &order, Reason: "order 7 refunded"}
+				err := st.RecordStoreCreditTransaction(context.Background(), tx)
+				require.NoError(t, err)
+				require.Equal(t, int64(3), tx.ID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "insufficient balance",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockCredit).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("5.00"))
+				mock.ExpectRollback()
+
+				err := st.RecordStoreCreditTransaction(context.Background(), &StoreCreditTransaction{UserID: 1, Kind: BalanceAdjustment, Amount: money.New(-600, money.DefaultCurrency), Reason: "correction"})
+				require.ErrorIs(t, err, ErrInsufficientBalance)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestLoyaltyPoints(t *testing.T) {
+	lock := "SELECT balance FROM loyalty_accounts WHERE user_id=? FOR UPDATE"
+	sums := "SELECT COALESCE(SUM(CASE WHEN points>0 AND expires_at<=? THEN points ELSE 0 END), 0) AS lapsed, COALESCE(SUM(CASE WHEN points<0 THEN -points ELSE 0 END), 0) AS used FROM loyalty_transactions WHERE user_id=?"
+	upsert := "INSERT INTO loyalty_accounts (user_id, balance) VALUES (?, ?) ON DUPLICATE KEY UPDATE balance=balance+VALUES(balance), updated_at=CURRENT_TIMESTAMP"
+	txInsert := "INSERT INTO loyalty_transactions (user_id, kind, points, spend, order_id, reason, expires_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	order := int64(7)
+	expiresAt := time.Date(2026, 6, 22, 10, 0, 0, 0, time.UTC)
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "no points",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT balance FROM loyalty_accounts WHERE user_id=?").WithArgs(1).WillReturnError(sql.ErrNoRows)
+
+				points, err := st.GetLoyaltyBalance(context.Background(), 1)
+				require.NoError(t, err)
+				require.Zero(t, points)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "first points open the account",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(sums).WithArgs(sqlmock.AnyArg(), 1).WillReturnRows(sqlmock.NewRows([]string{"lapsed", "used"}).AddRow(0, 0))
+				mock.ExpectExec(upsert).WithArgs(1, 25).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(txInsert).WithArgs(1, BalanceEarn, 25, "25.00", order, "order 7 delivered", expiresAt).WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectCommit()
+
+				tx := &LoyaltyTransaction{UserID: 1, Kind: BalanceEarn, Points: 25, Spend: money.New(2500, money.DefaultCurrency), OrderID: &order, Reason: "order 7 delivered", ExpiresAt: &expiresAt}
+				err := st.RecordLoyaltyTransaction(context.Background(), tx)
+				require.NoError(t, err)
+				require.Equal(t, int64(3), tx.ID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "lapsed points expire before redeeming",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(100))
+				mock.ExpectQuery(sums).WithArgs(sqlmock.AnyArg(), 1).WillReturnRows(sqlmock.NewRows([]string{"lapsed", "used"}).AddRow(100, 30))
+				mock.ExpectExec(upsert).WithArgs(1, -70).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(txInsert).WithArgs(1, BalanceExpire, -70, "0.00", nil, "points expired", nil).WillReturnResult(sqlmock.NewResult(4, 1))
+				mock.ExpectRollback()
+
+				err := st.RecordLoyaltyTransaction(context.Background(), &LoyaltyTransaction{UserID: 1, Kind: BalanceRedeem, Points: -50, Spend: money.New(0, money.DefaultCurrency), OrderID: &order, Reason: "order 7"})
+				require.ErrorIs(t, err, ErrInsufficientBalance)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "reversal may go below zero",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(10))
+				mock.ExpectQuery(sums).WithArgs(sqlmock.AnyArg(), 1).WillReturnRows(sqlmock.NewRows([]string{"lapsed", "used"}).AddRow(0, 15))
+				mock.ExpectExec(upsert).WithArgs(1, -25).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(txInsert).WithArgs(1, BalanceReverse, -25, "-25.00", order, "order 7 refunded", nil).WillReturnResult(sqlmock.NewResult(5, 1))
+				mock.ExpectCommit()
+
+				err := st.RecordLoyaltyTransaction(context.Background(), &LoyaltyTransaction{UserID: 1, Kind: BalanceReverse, Points: -25, Spend: money.New(-2500, money.DefaultCurrency), OrderID: &order, Reason: "order 7 refunded"})
+				require.NoError(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestInvoices(t *testing.T) {
+	issuedAt := time.Date(2025, 6, 17, 10, 0, 0, 0, time.UTC)
+	usd := func(cents int64) money.Money { return money.New(cents, money.DefaultCurrency) }
+	inv := func() *Invoice {
+		return &Invoice{
+			OrderID:    7,
+			UserID:     1,
+			Seller:     Seller{Name: "Shop", Address: StringList{"1 Main St"}},
+			BuyerName:  "test",
+			BuyerEmail: "test@example.com",
+			Items:      InvoiceLines{{Description: "test product", Quantity: 1, UnitPrice: usd(1999), TaxPrice: usd(0), Total: usd(1999)}},
+			ItemsPrice: usd(1999), DiscountPrice: usd(0), ShippingPrice: usd(1000), TaxPrice: usd(0), TotalPrice: usd(2999),
+			IssuedAt: issuedAt,
+		}
+	}
+	insert := "INSERT INTO invoices (number, order_id, user_id, seller, buyer_name, buyer_email, billing_address, shipping_address, items, items_price, discount_price, shipping_price, tax_price, total_price, issued_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	nextNumber := "UPDATE document_sequences SET last_number=LAST_INSERT_ID(last_number+1) WHERE name=?"
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "numbered invoice",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(nextNumber).WithArgs("invoice").WillReturnResult(sqlmock.NewResult(42, 1))
+				mock.ExpectExec(insert).WithArgs("INV-000042", 7, 1, `{"name":"Shop","address":["1 Main St"]}`, "test", "test@example.com", nil, nil,
+					`[{"description":"test product","quantity":1,"unit_price":{"amount":1999,"currency":"USD"},"tax_rate":0,"tax_price":{"amount":0,"currency":"USD"},"total":{"amount":1999,"currency":"USD"}}]`,
+					"19.99", "0.00", "10.00", "0.00", "29.99", issuedAt).WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectCommit()
+
+				created, err := st.CreateInvoice(context.Background(), inv())
+				require.NoError(t, err)
+				require.Equal(t, int64(3), created.ID)
+				require.Equal(t, "INV-000042", created.Number)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "order already invoiced",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(nextNumber).WithArgs("invoice").WillReturnResult(sqlmock.NewResult(43, 1))
+				mock.ExpectExec(insert).WillReturnError(&mysql.MySQLError{Number: mysqlErrDupEntry})
+				mock.ExpectRollback()
+
+				_, err := st.CreateInvoice(context.Background(), inv())
+				require.ErrorIs(t, err, ErrInvoiceExists)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "numbered credit note",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(nextNumber).WithArgs("credit_note").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO credit_notes (number, invoice_id, reason, items, items_price, discount_price, shipping_price, tax_price, total_price, issued_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
+					WithArgs("CN-000001", 3, "damaged", "[]", "19.99", "0.00", "10.00", "0.00", "29.99", issuedAt).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+
+				cn, err := st.CreateCreditNote(context.Background(), &CreditNote{
+					InvoiceID: 3, Reason: "damaged",
+					ItemsPrice: usd(1999), DiscountPrice: usd(0), ShippingPrice: usd(1000), TaxPrice: usd(0), TotalPrice: usd(2999),
+					IssuedAt: issuedAt,
+				})
+				require.NoError(t, err)
+				require.Equal(t, "CN-000001", cn.Number)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestReturns(t *testing.T) {
+	insertState := "INSERT INTO notification_states (order_id, state, message) VALUES (?, ?, ?)"
+	insertEvent := "INSERT INTO notification_events_queue (user_email, order_status, order_id, return_id, return_status, state_id, attempts) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	newReturn := func() *Return {
+		return &Return{OrderID: 1, OrderItemID: 2, UserID: 1, Quantity: 2, Reason: "too small", Status: ReturnRequested, RefundAmount: money.New(3998, money.DefaultCurrency)}
+	}
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "create return",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT quantity FROM order_items WHERE id=? AND order_id=? FOR UPDATE").WithArgs(2, 1).WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(3))
+				mock.ExpectQuery("SELECT COALESCE(SUM(quantity), 0) FROM returns WHERE order_item_id=? AND status<>'rejected'").WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(1))
+				mock.ExpectExec("INSERT INTO returns (order_id, order_item_id, user_id, quantity, reason, photos_url, status, refund_amount) VALUES (?, ?, ?, ?, ?, ?, ?, ?)").
+					WithArgs(1, 2, 1, 2, "too small", "", ReturnRequested, "39.98").WillReturnResult(sqlmock.NewResult(4, 1))
+				mock.ExpectExec(insertState).WithArgs(1, NotSent, "").WillReturnResult(sqlmock.NewResult(5, 1))
+				mock.ExpectExec(insertEvent).WithArgs("test@example.com", Delivered, 1, 4, ReturnRequested, 5, 0).WillReturnResult(sqlmock.NewResult(7, 1))
+				mock.ExpectCommit()
+
+				r, err := st.CreateReturn(context.Background(), newReturn(), &NotificationEvent{UserEmail: "test@example.com", OrderStatus: Delivered})
+				require.NoError(t, err)
+				require.Equal(t, int64(4), r.ID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "more units than left to return",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT quantity FROM order_items WHERE id=? AND order_id=? FOR UPDATE").WithArgs(2, 1).WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(3))
+				mock.ExpectQuery("SELECT COALESCE(SUM(quantity), 0) FROM returns WHERE order_item_id=? AND status<>'rejected'").WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(2))
+				mock.ExpectRollback()
+
+				_, err := st.CreateReturn(context.Background(), newReturn(), nil)
+				require.ErrorIs(t, err, ErrReturnQuantityExceeded)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "received and restocked",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				items := sqlmock.NewRows([]string{"id", "name", "quantity", "image", "price", "product_id", "variant_id", "sku", "tax_rate", "tax_jurisdiction", "tax_price", "tax_inclusive", "order_id"}).
+					AddRow(2, "test product", 3, "test.jpg", "19.99", 1, 6, "TP-S", 1000, "default", "6.00", false, 1)
+				updatedAt := time.Now()
+
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM returns WHERE id=? FOR UPDATE").WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("approved"))
+				mock.ExpectQuery("SELECT * FROM order_items WHERE id=?").WithArgs(2).WillReturnRows(items)
+				orderID := int64(1)
+				expectStockMovement(mock, StockMovement{WarehouseID: MainWarehouseID, ProductID: 1, VariantID: 6, Kind: StockReturn, Quantity: 2, Reason: "return 4 received", OrderID: &orderID})
+				mock.ExpectExec("UPDATE returns SET status=?, note=?, restocked=?, updated_at=? WHERE id=?").WithArgs(ReturnReceived, "", true, updatedAt, 4).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(insertState).WithArgs(1, NotSent, "").WillReturnResult(sqlmock.NewResult(5, 1))
+				mock.ExpectExec(insertEvent).WithArgs("test@example.com", Delivered, 1, 4, ReturnReceived, 5, 0).WillReturnResult(sqlmock.NewResult(7, 1))
+				mock.ExpectCommit()
+
+				r := newReturn()
+				r.ID = 4
+				r.Status = ReturnReceived
+				r.Restocked = true
+				r.UpdatedAt = &updatedAt
+				_, err := st.UpdateReturnStatus(context.Background(), r, ReturnApproved, &NotificationEvent{UserEmail: "test@example.com", OrderStatus: Delivered})
+				require.NoError(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "refunded with its credit note and loyalty points",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				updatedAt := time.Now()
+				orderID := int64(1)
+
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM returns WHERE id=? FOR UPDATE").WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("received"))
+				mock.ExpectExec("UPDATE returns SET status=?, note=?, restocked=?, updated_at=? WHERE id=?").WithArgs(ReturnRefunded, "", false, updatedAt, 4).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE document_sequences SET last_number=LAST_INSERT_ID(last_number+1) WHERE name=?").WithArgs("credit_note").WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectExec("INSERT INTO credit_notes (number, invoice_id, reason, items, items_price, discount_price, shipping_price, tax_price, total_price, issued_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
+					WithArgs("CN-000002", 3, "return 4: too small", "[]", "39.98", "0.00", "0.00", "0.00", "39.98", updatedAt).WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectQuery("SELECT balance FROM loyalty_accounts WHERE user_id=? FOR UPDATE").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(40))
+				mock.ExpectQuery("SELECT COALESCE(SUM(CASE WHEN points>0 AND expires_at<=? THEN points ELSE 0 END), 0) AS lapsed, COALESCE(SUM(CASE WHEN points<0 THEN -points ELSE 0 END), 0) AS used FROM loyalty_transactions WHERE user_id=?").
+					WithArgs(sqlmock.AnyArg(), 1).WillReturnRows(sqlmock.NewRows([]string{"lapsed", "used"}).AddRow(0, 0))
+				mock.ExpectExec("INSERT INTO loyalty_accounts (user_id, balance) VALUES (?, ?) ON DUPLICATE KEY UPDATE balance=balance+VALUES(balance), updated_at=CURRENT_TIMESTAMP").WithArgs(1, -39).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO loyalty_transactions (user_id, kind, points, spend, order_id, reason, expires_at) VALUES (?, ?, ?, ?, ?, ?, ?)").
+					WithArgs(1, BalanceReverse, -39, "-39.98", orderID, "return 4 refunded", nil).WillReturnResult(sqlmock.NewResult(8, 1))
+				mock.ExpectExec(insertState).WithArgs(1, NotSent, "").WillReturnResult(sqlmock.NewResult(5, 1))
+				mock.ExpectExec(insertEvent).WithArgs("test@example.com", Delivered, 1, 4, ReturnRefunded, 5, 0).WillReturnResult(sqlmock.NewResult(7, 1))
+				mock.ExpectCommit()
+
+				r := newReturn()
+				r.ID = 4
+				r.Status = ReturnRefunded
+				r.UpdatedAt = &updatedAt
+				zero := money.New(0, money.DefaultCurrency)
+				r.CreditNote = &CreditNote{InvoiceID: 3, Reason: "return 4: too small", ItemsPrice: r.RefundAmount, DiscountPrice: zero, ShippingPrice: zero, TaxPrice: zero, TotalPrice: r.RefundAmount, IssuedAt: updatedAt}
+				r.Loyalty = &LoyaltyTransaction{UserID: 1, Kind: BalanceReverse, Points: -39, Spend: r.RefundAmount.Mul(-1), OrderID: &orderID, Reason: "return 4 refunded"}
+				_, err := st.UpdateReturnStatus(context.Background(), r, ReturnReceived, &NotificationEvent{UserEmail: "test@example.com", OrderStatus: Delivered})
+				require.NoError(t, err)
+				require.Equal(t, "CN-000002", r.CreditNote.Number)
+				require.Equal(t, int64(8), r.Loyalty.ID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "return status changed",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM returns WHERE id=? FOR UPDATE").WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("rejected"))
+				mock.ExpectRollback()
+
+				r := newReturn()
+				r.ID = 4
+				r.Status = ReturnApproved
+				_, err := st.UpdateReturnStatus(context.Background(), r, ReturnRequested, nil)
+				require.ErrorIs(t, err, ErrReturnStatusChanged)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestWishlists(t *testing.T) {
+	token := "0123456789abcdef0123456789abcdef"
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "create wishlist",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO wishlists (user_id, name, share_token) VALUES (?, ?, ?)").
+					WithArgs(1, "birthday", nil).WillReturnResult(sqlmock.NewResult(3, 1))
+
+				w, err := st.CreateWishlist(context.Background(), &Wishlist{UserID: 1, Name: "birthday"})
+				require.NoError(t, err)
+				require.Equal(t, int64(3), w.ID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "name already in use",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE wishlists SET name=?, share_token=?, updated_at=? WHERE id=?").
+					WithArgs("birthday", token, nil, 3).WillReturnError(&mysql.MySQLError{Number: mysqlErrDupEntry, Message: "Duplicate entry"})
+
+				_, err := st.UpdateWishlist(context.Background(), &Wishlist{ID: 3, UserID: 1, Name: "birthday", ShareToken: &token})
+				require.ErrorIs(t, err, ErrWishlistExists)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "get shared wishlist",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT * FROM wishlists WHERE share_token=?").WithArgs(token).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "share_token", "created_at", "updated_at"}).AddRow(3, 1, "birthday", token, time.Now(), nil))
+				mock.ExpectQuery("SELECT * FROM wishlist_items WHERE wishlist_id=? ORDER BY id").WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "wishlist_id", "product_id", "variant_id", "created_at"}).AddRow(5, 3, 1, 0, time.Now()))
+
+				w, err := st.GetWishlistByShareToken(context.Background(), token)
+				require.NoError(t, err)
+				require.Equal(t, token, *w.ShareToken)
+				require.Len(t, w.Items, 1)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "list user wishlists",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT * FROM wishlists WHERE user_id=? ORDER BY id").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "share_token", "created_at", "updated_at"}).
+						AddRow(3, 1, "birthday", nil, time.Now(), nil).
+						AddRow(4, 1, "later", nil, time.Now(), nil))
+				mock.ExpectQuery("SELECT wishlist_items.* FROM wishlist_items JOIN wishlists ON wishlists.id=wishlist_items.wishlist_id WHERE wishlists.user_id=? ORDER BY wishlist_items.id").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "wishlist_id", "product_id", "variant_id", "created_at"}).
+						AddRow(5, 4, 1, 0, time.Now()).
+						AddRow(6, 4, 2, 7, time.Now()))
+
+				ws, err := st.ListUserWishlists(context.Background(), 1)
+				require.NoError(t, err)
+				require.Len(t, ws, 2)
+				require.Empty(t, ws[0].Items)
+				require.Len(t, ws[1].Items, 2)
+				require.Equal(t, int64(7), ws[1].Items[1].VariantID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "add item twice",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				add := "INSERT INTO wishlist_items (wishlist_id, product_id, variant_id) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE id=id"
+				mock.ExpectExec(add).WithArgs(3, 1, 0).WillReturnResult(sqlmock.NewResult(5, 1))
+				mock.ExpectExec(add).WithArgs(3, 1, 0).WillReturnResult(sqlmock.NewResult(5, 0))
+
+				require.NoError(t, st.AddWishlistItem(context.Background(), 3, 1, 0))
+				require.NoError(t, st.AddWishlistItem(context.Background(), 3, 1, 0))
+
+				err := mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "remove missing item",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM wishlist_items WHERE wishlist_id=? AND product_id=? AND variant_id=?").
+					WithArgs(3, 1, 0).WillReturnResult(sqlmock.NewResult(0, 0))
+
+				err := st.RemoveWishlistItem(context.Background(), 3, 1, 0)
+				require.ErrorIs(t, err, sql.ErrNoRows)
 
 				err = mock.ExpectationsWereMet()
 				require.NoError(t, err)
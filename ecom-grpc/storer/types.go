@@ -1,58 +1,1045 @@
 package storer
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"github.com/OrkhanMehbaliyev/ecom-golang/payments"
+)
 
 type Product struct {
-	ID           int64      `db:"id"`
-	Name         string     `db:"name"`
-	Image        string     `db:"image"`
-	Category     string     `db:"category"`
-	Description  string     `db:"description"`
-	Rating       int64      `db:"rating"`
-	NumReviews   int64      `db:"num_reviews"`
-	Price        float32    `db:"price"`
-	CountInStock int64      `db:"count_in_stock"`
-	CreatedAt    time.Time  `db:"created_at"`
-	UpdatedAt    *time.Time `db:"updated_at"`
+	ID          int64          `db:"id"`
+	Name        string         `db:"name"`
+	Image       string         `db:"image"`
+	CategoryID  *int64         `db:"category_id"`
+	Description string         `db:"description"`
+	Options     ProductOptions `db:"options"`
+	TaxClass    string         `db:"tax_class"`
+	Weight      int64          `db:"weight"`
+	// GiftCard products sell gift cards worth their price, one per unit
+	// ordered, issued once the order is paid.
+	GiftCard     bool        `db:"gift_card"`
+	Rating       int64       `db:"rating"`
+	NumReviews   int64       `db:"num_reviews"`
+	Price        money.Money `db:"price"`
+	CountInStock int64       `db:"count_in_stock"`
+	CreatedAt    time.Time   `db:"created_at"`
+	UpdatedAt    *time.Time  `db:"updated_at"`
+	// ListPrice is what the product sells at now, the lowest price scheduled
+	// for it that is running or else Price. Only ListProducts fills it in,
+	// and filters and sorts by it.
+	ListPrice money.Money `db:"list_price"`
+}
+
+// ProductOptions are the axes a product's variants differ in, e.g. size and
+// color, stored as a JSON array.
+type ProductOptions []string
+
+func (o *ProductOptions) Scan(src any) error {
+	return scanJSON(src, o)
+}
+
+func (o ProductOptions) Value() (driver.Value, error) {
+	if len(o) == 0 {
+		return nil, nil
+	}
+	return valueJSON(o)
+}
+
+// VariantOptions maps each option of a product to the variant's value, e.g.
+// {"size": "M", "color": "red"}, stored as a JSON object.
+type VariantOptions map[string]string
+
+func (o *VariantOptions) Scan(src any) error {
+	return scanJSON(src, o)
+}
+
+func (o VariantOptions) Value() (driver.Value, error) {
+	if o == nil {
+		return "{}", nil
+	}
+	return valueJSON(o)
+}
+
+func scanJSON(src any, dest any) error {
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dest)
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	default:
+		return fmt.Errorf("error scanning %T into %T", src, dest)
+	}
+}
+
+func valueJSON(v any) (driver.Value, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Variant is one sellable version of a product, such as a T-shirt in size M
+// and red. A product with variants is sold and stocked per variant; its own
+// count_in_stock is kept at the sum of its variants' stock. Price overrides
+// the product's price when set, and Image the product's image when not empty.
+type Variant struct {
+	ID           int64          `db:"id"`
+	ProductID    int64          `db:"product_id"`
+	SKU          string         `db:"sku"`
+	Options      VariantOptions `db:"options"`
+	Price        *money.Money   `db:"price"`
+	Image        string         `db:"image"`
+	CountInStock int64          `db:"count_in_stock"`
+	CreatedAt    time.Time      `db:"created_at"`
+	UpdatedAt    *time.Time     `db:"updated_at"`
+}
+
+// ScheduledPrice is a price a product, or one of its variants when VariantID
+// is set, sells at from StartsAt until EndsAt instead of its own. A flash
+// sale sells no more than QuantityCap units at its price. QuantitySold
+// counts the units ordered at it, and is kept by orders and never written by
+// updates.
+type ScheduledPrice struct {
+	ID           int64       `db:"id"`
+	ProductID    int64       `db:"product_id"`
+	VariantID    *int64      `db:"variant_id"`
+	Price        money.Money `db:"price"`
+	StartsAt     time.Time   `db:"starts_at"`
+	EndsAt       time.Time   `db:"ends_at"`
+	QuantityCap  *int64      `db:"quantity_cap"`
+	QuantitySold int64       `db:"quantity_sold"`
+	CreatedAt    time.Time   `db:"created_at"`
+	UpdatedAt    *time.Time  `db:"updated_at"`
+}
+
+// Running reports whether sp applies at t, that is it has started, not
+// ended and is not sold out.
+func (sp *ScheduledPrice) Running(t time.Time) bool {
+	return !t.Before(sp.StartsAt) && t.Before(sp.EndsAt) && (sp.QuantityCap == nil || sp.QuantitySold < *sp.QuantityCap)
+}
+
+var ErrScheduledPriceSoldOut = errors.New("scheduled price sold out")
+
+// ScheduledPriceSoldOutError reports the scheduled price order lines can no
+// longer be sold at: a flash sale with fewer units left than they order or,
+// when Changed is set, a price that ended or was changed since the order was
+// quoted. Prices are checked again when the order is stored, so concurrent
+// orders cannot sell more than QuantityCap units, nor sell at a price that
+// no longer runs.
+type ScheduledPriceSoldOutError struct {
+	ScheduledPriceID int64
+	ProductID        int64
+	Requested        int64
+	Available        int64
+	Changed          bool
+}
+
+func (e *ScheduledPriceSoldOutError) Error() string {
+	if e.Changed {
+		return fmt.Sprintf("sale price %d of product %d ended or changed, try again", e.ScheduledPriceID, e.ProductID)
+	}
+	return fmt.Sprintf("sale price %d of product %d has %d units left, requested %d", e.ScheduledPriceID, e.ProductID, e.Available, e.Requested)
+}
+
+func (e *ScheduledPriceSoldOutError) Is(target error) bool {
+	return target == ErrScheduledPriceSoldOut
 }
 
+// MainWarehouseID is the warehouse the inventory ledger starts with. It
+// takes the opening stock of new products and variants and the returned
+// items that are restocked.
+const MainWarehouseID int64 = 1
+
+// Warehouse is a location stock is kept in. Code is a short unique name,
+// e.g. "MAIN".
+type Warehouse struct {
+	ID        int64      `db:"id"`
+	Code      string     `db:"code"`
+	Name      string     `db:"name"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
+
+var ErrWarehouseExists = errors.New("warehouse code already taken")
+
+type StockMovementKind string
+
+const (
+	// StockReceipt is stock delivered to a warehouse, including the opening
+	// stock of new products and variants.
+	StockReceipt StockMovementKind = "receipt"
+	// StockSale is stock taken by an order.
+	StockSale StockMovementKind = "sale"
+	// StockRelease is stock an order took that is put back because the order
+	// was cancelled, refunded before it shipped or deleted.
+	StockRelease StockMovementKind = "release"
+	// StockReturn is returned stock put back on the shelf.
+	StockReturn StockMovementKind = "return"
+	// StockAdjustment corrects the stock of a warehouse, e.g. after a count.
+	StockAdjustment StockMovementKind = "adjustment"
+	// StockTransfer moves stock between warehouses, as a movement out of one
+	// and a movement into the other.
+	StockTransfer StockMovementKind = "transfer"
+)
+
+// StockLevel is how many units of a product, or of one of its variants, a
+// warehouse holds. VariantID is 0 for products without variants.
+type StockLevel struct {
+	WarehouseID int64 `db:"warehouse_id"`
+	ProductID   int64 `db:"product_id"`
+	VariantID   int64 `db:"variant_id"`
+	Quantity    int64 `db:"quantity"`
+}
+
+// StockMovement is an entry of the append-only inventory ledger: Quantity
+// units of a product or variant moved into a warehouse, or out of it when
+// negative. ActorID is the user who moved them and is nil for movements the
+// system makes on its own. OrderID is set for sales, releases and returns,
+// and OtherWarehouseID for transfers, naming the warehouse on the other
+// side.
+type StockMovement struct {
+	ID               int64             `db:"id"`
+	WarehouseID      int64             `db:"warehouse_id"`
+	ProductID        int64             `db:"product_id"`
+	VariantID        int64             `db:"variant_id"`
+	Kind             StockMovementKind `db:"kind"`
+	Quantity         int64             `db:"quantity"`
+	Reason           string            `db:"reason"`
+	ActorID          *int64            `db:"actor_id"`
+	OrderID          *int64            `db:"order_id"`
+	OtherWarehouseID *int64            `db:"other_warehouse_id"`
+	CreatedAt        time.Time         `db:"created_at"`
+}
+
+// ListStockMovementsParams selects a page of the movements of a product,
+// newest first, in all warehouses or only in WarehouseID when it is set.
+// BeforeID is the id of the last movement of the previous page. Limit is
+// capped like page sizes.
+type ListStockMovementsParams struct {
+	ProductID   int64
+	WarehouseID int64
+	BeforeID    int64
+	Limit       int
+}
+
+var (
+	ErrSKUExists = errors.New("sku already taken")
+	// ErrVariantInUse is returned when deleting a variant that was ordered.
+	ErrVariantInUse = errors.New("variant is referenced by orders")
+)
+
+// Category is a node of the category tree. Top level categories have no
+// parent; siblings are shown by SortOrder, then name.
+type Category struct {
+	ID        int64      `db:"id"`
+	ParentID  *int64     `db:"parent_id"`
+	Name      string     `db:"name"`
+	Slug      string     `db:"slug"`
+	SortOrder int64      `db:"sort_order"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
+
+var (
+	ErrCategoryExists = errors.New("category slug already taken")
+	// ErrCategoryInUse is returned when deleting a category that still has
+	// subcategories or products.
+	ErrCategoryInUse = errors.New("category has subcategories or products")
+)
+
 type OrderStatus string
 
 const (
-	Pending   OrderStatus = "pending"
-	Shipped   OrderStatus = "shipped"
-	Delivered OrderStatus = "delivered"
+	Pending    OrderStatus = "pending"
+	Paid       OrderStatus = "paid"
+	Processing OrderStatus = "processing"
+	Shipped    OrderStatus = "shipped"
+	Delivered  OrderStatus = "delivered"
+	Cancelled  OrderStatus = "cancelled"
+	Refunded   OrderStatus = "refunded"
 )
 
+// ReservesStock reports whether an order in status s still holds the stock it
+// reserved. Once an order ships the goods have left the warehouse, and once it
+// is cancelled or refunded before shipping the stock has been released.
+func (s OrderStatus) ReservesStock() bool {
+	switch s {
+	case Pending, Paid, Processing:
+		return true
+	default:
+		return false
+	}
+}
+
+// releasesStock reports whether moving an order from one status to another
+// puts its reserved stock back.
+func releasesStock(from, to OrderStatus) bool {
+	return from.ReservesStock() && (to == Cancelled || to == Refunded)
+}
+
+type ActorRole string
+
+const (
+	ActorCustomer ActorRole = "customer"
+	ActorAdmin    ActorRole = "admin"
+	// ActorSystem changes are made by the shop itself, such as marking an
+	// order paid once its payment is captured.
+	ActorSystem ActorRole = "system"
+)
+
+// OrderStatusChange records a single transition of an order's status together
+// with who made it and why. Together with the transition, CreditNote, when
+// set, is issued, GiftCards, the cards bought in the order, are issued,
+// CancelGiftCards takes what is left off those cards and Loyalty, when set,
+// awards or takes back its points; none of them is part of the history.
+// Should the code of one of GiftCards be taken, ErrGiftCardExists is
+// returned and nothing is changed.
+type OrderStatusChange struct {
+	ID              int64               `db:"id"`
+	OrderID         int64               `db:"order_id"`
+	FromStatus      OrderStatus         `db:"from_status"`
+	ToStatus        OrderStatus         `db:"to_status"`
+	Reason          string              `db:"reason"`
+	ActorID         int64               `db:"actor_id"`
+	ActorRole       ActorRole           `db:"actor_role"`
+	CreatedAt       time.Time           `db:"created_at"`
+	CreditNote      *CreditNote         `db:"-"`
+	GiftCards       []*GiftCard         `db:"-"`
+	CancelGiftCards bool                `db:"-"`
+	Loyalty         *LoyaltyTransaction `db:"-"`
+}
+
+// ErrOrderStatusChanged is returned when an order's status no longer matches
+// the status a transition was validated against.
+var ErrOrderStatusChanged = errors.New("order status changed concurrently")
+
+// ErrOrderHasRecords is returned when deleting an order that has payments
+// other than failed ones, an invoice or returns, which must be kept.
+var ErrOrderHasRecords = errors.New("order has payments, an invoice or returns")
+
+// Order is a placed order. DiscountPrice is the sum of Discounts, and every
+// discount that came from a promotion is redeemed when the order is created.
+// TaxPrice is the tax of all items, including tax contained in tax-inclusive
+// prices, so TotalPrice is not always the plain sum of the other prices.
 type Order struct {
+	ID              int64          `db:"id"`
+	PaymentMethod   string         `db:"payment_method"`
+	ShippingAddress *Address       `db:"shipping_address"`
+	BillingAddress  *Address       `db:"billing_address"`
+	ShippingMethod  string         `db:"shipping_method"`
+	TaxPrice        money.Money    `db:"tax_price"`
+	ShippingPrice   money.Money    `db:"shipping_price"`
+	DiscountPrice   money.Money    `db:"discount_price"`
+	CouponCode      string         `db:"coupon_code"`
+	Discounts       OrderDiscounts `db:"discounts"`
+	TotalPrice      money.Money    `db:"total_price"`
+	UserID          int64          `db:"user_id"`
+	Status          OrderStatus    `db:"status"`
+	CreatedAt       time.Time      `db:"created_at"`
+	UpdatedAt       *time.Time     `db:"updated_at"`
+	Items           []OrderItem
+	// Tenders are the parts of the total paid at checkout from gift cards
+	// and store credit. They are recorded as captured payments of the order.
+	Tenders []*Payment
+}
+
+// Address is a postal address, snapshotted into the orders it is used by as
+// JSON. Country is an ISO 3166-1 alpha-2 code and Region a subdivision of it,
+// such as a state, as the tax rates name it.
+type Address struct {
+	Name       string `json:"name"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+}
+
+func (a *Address) Scan(src any) error {
+	return scanJSON(src, a)
+}
+
+func (a Address) Value() (driver.Value, error) {
+	return valueJSON(a)
+}
+
+// OrderDiscount is one line of an order's discount breakdown.
+type OrderDiscount struct {
+	PromotionID int64       `json:"promotion_id"`
+	Name        string      `json:"name"`
+	Code        string      `json:"code,omitempty"`
+	Amount      money.Money `json:"amount"`
+}
+
+// OrderDiscounts is stored as a JSON array.
+type OrderDiscounts []OrderDiscount
+
+func (d *OrderDiscounts) Scan(src any) error {
+	return scanJSON(src, d)
+}
+
+func (d OrderDiscounts) Value() (driver.Value, error) {
+	if len(d) == 0 {
+		return nil, nil
+	}
+	return valueJSON(d)
+}
+
+type OrderItem struct {
+	ID        int64       `db:"id"`
+	Name      string      `db:"name"`
+	Quantity  int64       `db:"quantity"`
+	Image     string      `db:"image"`
+	Price     money.Money `db:"price"`
+	ProductID int64       `db:"product_id"`
+	VariantID *int64      `db:"variant_id"`
+	SKU       string      `db:"sku"`
+	// the scheduled price the line was sold at, if any
+	ScheduledPriceID *int64 `db:"scheduled_price_id"`
+	// the part of the order's item discounts taken off the line; waived
+	// shipping is not spread over lines
+	DiscountPrice money.Money `db:"discount_price"`
+	// the tax applied to the line, kept for auditing: the rate in basis
+	// points and the jurisdiction it was taken from, e.g. "US-CA"
+	TaxRate         int64       `db:"tax_rate"`
+	TaxJurisdiction string      `db:"tax_jurisdiction"`
+	TaxPrice        money.Money `db:"tax_price"`
+	TaxInclusive    bool        `db:"tax_inclusive"`
+	OrderID         int64       `db:"order_id"`
+}
+
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+// InsufficientStockError reports the product, and the variant if it has
+// variants, that made an order fail because fewer units are in stock than
+// were ordered.
+type InsufficientStockError struct {
+	ProductID int64
+	Name      string
+	SKU       string
+	Requested int64
+	Available int64
+}
+
+func (e *InsufficientStockError) Error() string {
+	if e.SKU != "" {
+		return fmt.Sprintf("insufficient stock for product %q (id %d, sku %s): requested %d, available %d", e.Name, e.ProductID, e.SKU, e.Requested, e.Available)
+	}
+	return fmt.Sprintf("insufficient stock for product %q (id %d): requested %d, available %d", e.Name, e.ProductID, e.Requested, e.Available)
+}
+
+func (e *InsufficientStockError) Is(target error) bool {
+	return target == ErrInsufficientStock
+}
+
+type PromotionType string
+
+const (
+	// PercentageOff takes DiscountRate basis points off the scoped items.
+	PercentageOff PromotionType = "percentage"
+	// FixedAmountOff takes DiscountAmount off the scoped items.
+	FixedAmountOff PromotionType = "fixed_amount"
+	FreeShipping   PromotionType = "free_shipping"
+	// BuyXGetY gives GetQuantity of every BuyQuantity+GetQuantity scoped units
+	// away for free, the cheapest ones first.
+	BuyXGetY PromotionType = "buy_x_get_y"
+)
+
+// Promotion is an admin-managed discount. Promotions without a code apply to
+// every qualifying order, those with a code only when it is entered. A
+// promotion is scoped to a product or a category (with its subcategories)
+// when ProductID or CategoryID is set, and to the whole order otherwise.
+// Zero limits and a zero MinSubtotal mean no limit. TimesUsed is read from
+// the redemptions and never written.
+type Promotion struct {
+	ID               int64         `db:"id"`
+	Name             string        `db:"name"`
+	Code             *string       `db:"code"`
+	Type             PromotionType `db:"type"`
+	DiscountRate     int64         `db:"discount_rate"`
+	DiscountAmount   money.Money   `db:"discount_amount"`
+	BuyQuantity      int64         `db:"buy_quantity"`
+	GetQuantity      int64         `db:"get_quantity"`
+	ProductID        *int64        `db:"product_id"`
+	CategoryID       *int64        `db:"category_id"`
+	MinSubtotal      money.Money   `db:"min_subtotal"`
+	StartsAt         *time.Time    `db:"starts_at"`
+	EndsAt           *time.Time    `db:"ends_at"`
+	UsageLimit       int64         `db:"usage_limit"`
+	PerCustomerLimit int64         `db:"per_customer_limit"`
+	Active           bool          `db:"active"`
+	TimesUsed        int64         `db:"times_used"`
+	CreatedAt        time.Time     `db:"created_at"`
+	UpdatedAt        *time.Time    `db:"updated_at"`
+}
+
+// Running reports whether p can be applied at t, limits aside.
+func (p *Promotion) Running(t time.Time) bool {
+	return p.Active && (p.StartsAt == nil || !t.Before(*p.StartsAt)) && (p.EndsAt == nil || t.Before(*p.EndsAt))
+}
+
+var (
+	ErrPromotionExists = errors.New("promotion code already taken")
+	// ErrPromotionInUse is returned when deleting a promotion that was
+	// redeemed; deactivate it instead.
+	ErrPromotionInUse        = errors.New("promotion was redeemed")
+	ErrPromotionLimitReached = errors.New("promotion usage limit reached")
+)
+
+// PromotionLimitError reports the promotion whose usage limit an order would
+// exceed. The limits are checked again when the order is stored, so
+// concurrent orders cannot redeem a promotion more often than allowed.
+type PromotionLimitError struct {
+	PromotionID int64
+	Name        string
+	PerCustomer bool
+}
+
+func (e *PromotionLimitError) Error() string {
+	if e.PerCustomer {
+		return fmt.Sprintf("promotion %q (id %d) was already used the maximum number of times by this customer", e.Name, e.PromotionID)
+	}
+	return fmt.Sprintf("promotion %q (id %d) has reached its usage limit", e.Name, e.PromotionID)
+}
+
+func (e *PromotionLimitError) Is(target error) bool {
+	return target == ErrPromotionLimitReached
+}
+
+// DefaultTaxClass is the tax class of products that were not given one.
+const DefaultTaxClass = "standard"
+
+// TaxRate is the rate, in basis points, charged on products of TaxClass
+// shipped to Country and Region. An empty Region covers the whole country
+// and an empty Country every destination. Inclusive rates are contained in
+// the catalog prices rather than added on top of them.
+type TaxRate struct {
+	ID        int64      `db:"id"`
+	Country   string     `db:"country"`
+	Region    string     `db:"region"`
+	TaxClass  string     `db:"tax_class"`
+	Name      string     `db:"name"`
+	Rate      int64      `db:"rate"`
+	Inclusive bool       `db:"inclusive"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
+
+var ErrTaxRateExists = errors.New("tax rate for this jurisdiction and tax class already exists")
+
+// UserAddress is an address in a user's address book. At most one address
+// of a user is the default.
+type UserAddress struct {
+	ID         int64      `db:"id"`
+	UserID     int64      `db:"user_id"`
+	Label      string     `db:"label"`
+	Name       string     `db:"name"`
+	Line1      string     `db:"line1"`
+	Line2      string     `db:"line2"`
+	City       string     `db:"city"`
+	Region     string     `db:"region"`
+	PostalCode string     `db:"postal_code"`
+	Country    string     `db:"country"`
+	IsDefault  bool       `db:"is_default"`
+	CreatedAt  time.Time  `db:"created_at"`
+	UpdatedAt  *time.Time `db:"updated_at"`
+}
+
+// Address is the postal address part of a, as orders snapshot it.
+func (a *UserAddress) Address() *Address {
+	return &Address{
+		Name:       a.Name,
+		Line1:      a.Line1,
+		Line2:      a.Line2,
+		City:       a.City,
+		Region:     a.Region,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+	}
+}
+
+// StringList is stored as a JSON array, empty rather than NULL.
+type StringList []string
+
+func (l *StringList) Scan(src any) error {
+	return scanJSON(src, l)
+}
+
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	return valueJSON(l)
+}
+
+// ShippingZone groups the destinations that ship at the same rates. An
+// address is in a zone when its country is one of Countries and, if the
+// zone has PostalPrefixes, its postal code starts with one of them. A zone
+// without countries covers every destination no other zone does.
+type ShippingZone struct {
+	ID             int64      `db:"id"`
+	Name           string     `db:"name"`
+	Countries      StringList `db:"countries"`
+	PostalPrefixes StringList `db:"postal_prefixes"`
+	CreatedAt      time.Time  `db:"created_at"`
+	UpdatedAt      *time.Time `db:"updated_at"`
+}
+
+// ShippingBasis is what the tiers of a shipping method are measured in.
+type ShippingBasis string
+
+const (
+	// BySubtotal tiers are in minor units of the items subtotal.
+	BySubtotal ShippingBasis = "subtotal"
+	// ByWeight tiers are in grams.
+	ByWeight ShippingBasis = "weight"
+)
+
+// ShippingTier prices the orders from Min up to the next tier's Min.
+type ShippingTier struct {
+	Min   int64       `json:"min"`
+	Price money.Money `json:"price"`
+}
+
+// ShippingTiers is stored as a JSON array ordered by Min.
+type ShippingTiers []ShippingTier
+
+func (t *ShippingTiers) Scan(src any) error {
+	return scanJSON(src, t)
+}
+
+func (t ShippingTiers) Value() (driver.Value, error) {
+	if t == nil {
+		return "[]", nil
+	}
+	return valueJSON(t)
+}
+
+// ShippingMethod is a way of shipping to a zone, such as standard or
+// express, priced by its tiers. Orders with a subtotal of at least
+// FreeAbove ship for free.
+type ShippingMethod struct {
+	ID        int64         `db:"id"`
+	ZoneID    int64         `db:"zone_id"`
+	Name      string        `db:"name"`
+	Basis     ShippingBasis `db:"basis"`
+	Tiers     ShippingTiers `db:"tiers"`
+	FreeAbove *money.Money  `db:"free_above"`
+	Active    bool          `db:"active"`
+	CreatedAt time.Time     `db:"created_at"`
+	UpdatedAt *time.Time    `db:"updated_at"`
+}
+
+// Payment is one attempt to pay an order through a provider. Reference is
+// the provider's id of the payment, empty until the provider answered.
+type Payment struct {
+	ID        int64           `db:"id"`
+	OrderID   int64           `db:"order_id"`
+	Provider  string          `db:"provider"`
+	Reference string          `db:"reference"`
+	Status    payments.Status `db:"status"`
+	Amount    money.Money     `db:"amount"`
+	// RefundedAmount is what was refunded of a captured payment so far.
+	RefundedAmount money.Money `db:"refunded_amount"`
+	Message        string      `db:"message"`
+	CreatedAt      time.Time   `db:"created_at"`
+	UpdatedAt      *time.Time  `db:"updated_at"`
+}
+
+// ErrPaymentInProgress is returned when paying an order that already has an
+// active payment.
+var ErrPaymentInProgress = errors.New("order has a payment in progress")
+
+// Payments made at checkout from a gift card, from the customer's store
+// credit or with loyalty points are recorded as payments of these providers.
+// Their reference is the gift card's code, the number of points redeemed, and
+// empty for store credit. They never go through the payment provider and are
+// not counted as a payment in progress.
+const (
+	GiftCardProvider      = "gift_card"
+	StoreCreditProvider   = "store_credit"
+	LoyaltyPointsProvider = "loyalty_points"
+)
+
+// IsTender reports whether p was paid from a gift card, store credit or
+// loyalty points.
+func (p *Payment) IsTender() bool {
+	switch p.Provider {
+	case GiftCardProvider, StoreCreditProvider, LoyaltyPointsProvider:
+		return true
+	default:
+		return false
+	}
+}
+
+// LoyaltyPoints returns the number of points redeemed by a loyalty points
+// payment.
+func (p *Payment) LoyaltyPoints() int64 {
+	n, _ := strconv.ParseInt(p.Reference, 10, 64)
+	return n
+}
+
+// PaymentRefund is a refund of part of a captured payment. Key names what
+// it refunds, e.g. "order 5" or "return 3"; a payment is refunded for a key
+// only once, so retrying a refund cannot pay it out twice. Refunds that go
+// to a gift card, store credit or loyalty points carry the transaction that
+// gives the money back, recorded together with the refund, and are applied
+// at once. The others are recorded pending until the payment provider
+// carried them out.
+type PaymentRefund struct {
+	ID          int64                   `db:"id"`
+	PaymentID   int64                   `db:"payment_id"`
+	Key         string                  `db:"refund_key"`
+	Amount      money.Money             `db:"amount"`
+	Status      RefundStatus            `db:"status"`
+	CreatedAt   time.Time               `db:"created_at"`
+	GiftCard    *GiftCardTransaction    `db:"-"`
+	StoreCredit *StoreCreditTransaction `db:"-"`
+	Loyalty     *LoyaltyTransaction     `db:"-"`
+}
+
+type RefundStatus string
+
+const (
+	// RefundPending refunds are recorded but the payment provider has yet to
+	// give the money back.
+	RefundPending RefundStatus = "pending"
+	// RefundApplied refunds gave the money back.
+	RefundApplied RefundStatus = "applied"
+)
+
+// ErrRefundApplied is returned when refunding a payment for a key it was
+// refunded for already.
+var ErrRefundApplied = errors.New("payment already refunded")
+
+// ErrPaymentChanged is returned when a payment is no longer captured, or
+// was refunded since it was read.
+var ErrPaymentChanged = errors.New("payment changed concurrently")
+
+// GiftCard is a prepaid balance that pays for orders at checkout. Cards
+// bought in an order have PurchaserID and OrderID set; cards an admin issued
+// have IssuedBy set. Balance only changes through transactions.
+type GiftCard struct {
 	ID            int64       `db:"id"`
-	PaymentMethod string      `db:"payment_method"`
-	TaxPrice      float64     `db:"tax_price"`
-	ShippingPrice float64     `db:"shipping_price"`
-	TotalPrice    float64     `db:"total_price"`
-	UserID        int64       `db:"user_id"`
-	Status        OrderStatus `db:"status"`
+	Code          string      `db:"code"`
+	InitialAmount money.Money `db:"initial_amount"`
+	Balance       money.Money `db:"balance"`
+	ExpiresAt     *time.Time  `db:"expires_at"`
+	PurchaserID   *int64      `db:"purchaser_id"`
+	OrderID       *int64      `db:"order_id"`
+	IssuedBy      *int64      `db:"issued_by"`
+	Note          string      `db:"note"`
 	CreatedAt     time.Time   `db:"created_at"`
 	UpdatedAt     *time.Time  `db:"updated_at"`
-	Items         []OrderItem
 }
 
-type OrderItem struct {
-	ID        int64   `db:"id"`
-	Name      string  `db:"name"`
-	Quantity  int64   `db:"quantity"`
-	Image     string  `db:"image"`
-	Price     float64 `db:"price"`
-	ProductID int64   `db:"product_id"`
-	OrderID   int64   `db:"order_id"`
+// Expired reports whether the card can no longer be spent at t.
+func (g *GiftCard) Expired(t time.Time) bool {
+	return g.ExpiresAt != nil && !t.Before(*g.ExpiresAt)
+}
+
+type BalanceTransactionKind string
+
+const (
+	// BalanceIssue is the initial balance of a gift card.
+	BalanceIssue BalanceTransactionKind = "issue"
+	// BalanceRedeem is money spent on an order at checkout.
+	BalanceRedeem BalanceTransactionKind = "redeem"
+	// BalanceRefund is money given back by a refunded order or return.
+	BalanceRefund BalanceTransactionKind = "refund"
+	// BalanceAdjustment is money an admin adds or takes off, e.g. to
+	// compensate a customer.
+	BalanceAdjustment BalanceTransactionKind = "adjustment"
+	// BalanceEarn is loyalty points earned by a delivered order.
+	BalanceEarn BalanceTransactionKind = "earn"
+	// BalanceReverse is loyalty points taken back when the order that
+	// earned them is refunded or returned.
+	BalanceReverse BalanceTransactionKind = "reverse"
+	// BalanceExpire is loyalty points that lapsed unspent.
+	BalanceExpire BalanceTransactionKind = "expire"
+)
+
+// GiftCardTransaction is an entry of the append-only ledger of a gift card.
+// Amount is negative for money taken off the card. ActorID is the user who
+// made it and is nil for transactions the system makes on its own.
+type GiftCardTransaction struct {
+	ID         int64                  `db:"id"`
+	GiftCardID int64                  `db:"gift_card_id"`
+	Kind       BalanceTransactionKind `db:"kind"`
+	Amount     money.Money            `db:"amount"`
+	OrderID    *int64                 `db:"order_id"`
+	ActorID    *int64                 `db:"actor_id"`
+	Reason     string                 `db:"reason"`
+	CreatedAt  time.Time              `db:"created_at"`
+}
+
+// StoreCreditTransaction is an entry of the append-only ledger of a user's
+// store credit, like GiftCardTransaction. Store credit is never issued, so
+// its kind is never BalanceIssue.
+type StoreCreditTransaction struct {
+	ID        int64                  `db:"id"`
+	UserID    int64                  `db:"user_id"`
+	Kind      BalanceTransactionKind `db:"kind"`
+	Amount    money.Money            `db:"amount"`
+	OrderID   *int64                 `db:"order_id"`
+	ActorID   *int64                 `db:"actor_id"`
+	Reason    string                 `db:"reason"`
+	CreatedAt time.Time              `db:"created_at"`
 }
 
+// LoyaltyTransaction is an entry of the append-only ledger of a user's
+// loyalty points. Points is negative for points taken off. Points earned or
+// given back expire at ExpiresAt, if set, and points are spent oldest first.
+// Spend is what the customer spent to earn the points, and is negative when
+// they are reversed.
+type LoyaltyTransaction struct {
+	ID        int64                  `db:"id"`
+	UserID    int64                  `db:"user_id"`
+	Kind      BalanceTransactionKind `db:"kind"`
+	Points    int64                  `db:"points"`
+	Spend     money.Money            `db:"spend"`
+	OrderID   *int64                 `db:"order_id"`
+	Reason    string                 `db:"reason"`
+	ExpiresAt *time.Time             `db:"expires_at"`
+	CreatedAt time.Time              `db:"created_at"`
+}
+
+var (
+	ErrGiftCardExists = errors.New("gift card code already taken")
+	// ErrInsufficientBalance is returned when a transaction would take a
+	// gift card, store credit or loyalty points balance below zero.
+	ErrInsufficientBalance = errors.New("insufficient balance")
+)
+
+// Seller is who issues invoices, as printed on them. It is snapshotted into
+// every invoice as JSON.
+type Seller struct {
+	Name    string     `json:"name"`
+	Address StringList `json:"address"`
+	Email   string     `json:"email,omitempty"`
+	TaxID   string     `json:"tax_id,omitempty"`
+}
+
+func (s *Seller) Scan(src any) error {
+	return scanJSON(src, s)
+}
+
+func (s Seller) Value() (driver.Value, error) {
+	return valueJSON(s)
+}
+
+// InvoiceLine is an order line as invoiced. Total is the price times the
+// quantity, which includes TaxPrice when TaxInclusive.
+type InvoiceLine struct {
+	Description     string      `json:"description"`
+	SKU             string      `json:"sku,omitempty"`
+	Quantity        int64       `json:"quantity"`
+	UnitPrice       money.Money `json:"unit_price"`
+	TaxRate         int64       `json:"tax_rate"`
+	TaxJurisdiction string      `json:"tax_jurisdiction,omitempty"`
+	TaxPrice        money.Money `json:"tax_price"`
+	TaxInclusive    bool        `json:"tax_inclusive,omitempty"`
+	Total           money.Money `json:"total"`
+}
+
+// InvoiceLines is stored as a JSON array.
+type InvoiceLines []InvoiceLine
+
+func (l *InvoiceLines) Scan(src any) error {
+	return scanJSON(src, l)
+}
+
+func (l InvoiceLines) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	return valueJSON(l)
+}
+
+// Invoice is issued once per order when it is paid. It snapshots what it
+// shows, so later changes to the order, the customer or the seller leave it
+// untouched. Number is assigned by CreateInvoice.
+type Invoice struct {
+	ID              int64        `db:"id"`
+	Number          string       `db:"number"`
+	OrderID         int64        `db:"order_id"`
+	UserID          int64        `db:"user_id"`
+	Seller          Seller       `db:"seller"`
+	BuyerName       string       `db:"buyer_name"`
+	BuyerEmail      string       `db:"buyer_email"`
+	BillingAddress  *Address     `db:"billing_address"`
+	ShippingAddress *Address     `db:"shipping_address"`
+	Items           InvoiceLines `db:"items"`
+	ItemsPrice      money.Money  `db:"items_price"`
+	DiscountPrice   money.Money  `db:"discount_price"`
+	ShippingPrice   money.Money  `db:"shipping_price"`
+	TaxPrice        money.Money  `db:"tax_price"`
+	TotalPrice      money.Money  `db:"total_price"`
+	IssuedAt        time.Time    `db:"issued_at"`
+}
+
+// CreditNote cancels all or part of an invoice, e.g. when the order is
+// refunded. Its amounts are positive and credited to the buyer. Number is
+// assigned by CreateCreditNote.
+type CreditNote struct {
+	ID            int64        `db:"id"`
+	Number        string       `db:"number"`
+	InvoiceID     int64        `db:"invoice_id"`
+	Reason        string       `db:"reason"`
+	Items         InvoiceLines `db:"items"`
+	ItemsPrice    money.Money  `db:"items_price"`
+	DiscountPrice money.Money  `db:"discount_price"`
+	ShippingPrice money.Money  `db:"shipping_price"`
+	TaxPrice      money.Money  `db:"tax_price"`
+	TotalPrice    money.Money  `db:"total_price"`
+	IssuedAt      time.Time    `db:"issued_at"`
+}
+
+// ErrInvoiceExists is returned when invoicing an order a second time.
+var ErrInvoiceExists = errors.New("order already invoiced")
+
+// ReturnStatus is where a return stands. Customers request returns, which
+// admins approve or reject; approved returns are received back and then
+// refunded.
+type ReturnStatus string
+
+const (
+	ReturnRequested ReturnStatus = "requested"
+	ReturnApproved  ReturnStatus = "approved"
+	ReturnRejected  ReturnStatus = "rejected"
+	ReturnReceived  ReturnStatus = "received"
+	ReturnRefunded  ReturnStatus = "refunded"
+)
+
+// Return is a customer's request to send back Quantity units of an order
+// item. RefundAmount is the item's share of what was paid for those units,
+// fixed when the return is requested.
+type Return struct {
+	ID          int64        `db:"id"`
+	OrderID     int64        `db:"order_id"`
+	OrderItemID int64        `db:"order_item_id"`
+	UserID      int64        `db:"user_id"`
+	Quantity    int64        `db:"quantity"`
+	Reason      string       `db:"reason"`
+	PhotosURL   string       `db:"photos_url"`
+	Status      ReturnStatus `db:"status"`
+	// Note is the admin's comment on the last change, e.g. why the return
+	// was rejected.
+	Note string `db:"note"`
+	// Restocked is set when the received units were put back into stock.
+	Restocked    bool        `db:"restocked"`
+	RefundAmount money.Money `db:"refund_amount"`
+	CreatedAt    time.Time   `db:"created_at"`
+	UpdatedAt    *time.Time  `db:"updated_at"`
+	// CreditNote and Loyalty, when set, are the credit note of a refunded
+	// return and the loyalty points it takes back, recorded together with
+	// the change.
+	CreditNote *CreditNote         `db:"-"`
+	Loyalty    *LoyaltyTransaction `db:"-"`
+}
+
+// ErrReturnQuantityExceeded is returned when returning more units of an order
+// item than are left to return.
+var ErrReturnQuantityExceeded = errors.New("return quantity exceeds the quantity left to return")
+
+// ErrReturnStatusChanged is returned when a return's status no longer matches
+// the status a change was made from.
+var ErrReturnStatusChanged = errors.New("return status changed concurrently")
+
+// Sequences documents are numbered from, and the prefixes of their numbers.
+const (
+	invoiceSequence    = "invoice"
+	creditNoteSequence = "credit_note"
+	invoicePrefix      = "INV"
+	creditNotePrefix   = "CN"
+)
+
+// documentNumber formats the n-th number of a sequence, e.g. INV-000042.
+func documentNumber(prefix string, n int64) string {
+	return fmt.Sprintf("%s-%06d", prefix, n)
+}
+
+// Cart is a customer's persistent shopping cart. Items only reference
+// products; prices and stock are read from the catalog when the cart is
+// shown or checked out.
+type Cart struct {
+	ID        int64      `db:"id"`
+	UserID    int64      `db:"user_id"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+	Items     []CartItem
+}
+
+// CartItem is a quantity of a product, or of one of its variants. VariantID
+// is 0 for products without variants.
+type CartItem struct {
+	ID        int64      `db:"id"`
+	CartID    int64      `db:"cart_id"`
+	ProductID int64      `db:"product_id"`
+	VariantID int64      `db:"variant_id"`
+	Quantity  int64      `db:"quantity"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
+
+// Wishlist is a named list of products a user saved for later. ShareToken
+// is set while the wishlist is shared: anyone who has it can view the list.
+type Wishlist struct {
+	ID         int64      `db:"id"`
+	UserID     int64      `db:"user_id"`
+	Name       string     `db:"name"`
+	ShareToken *string    `db:"share_token"`
+	CreatedAt  time.Time  `db:"created_at"`
+	UpdatedAt  *time.Time `db:"updated_at"`
+	Items      []WishlistItem
+}
+
+// WishlistItem is a product, or one of its variants, on a wishlist.
+// VariantID is 0 for products without variants.
+type WishlistItem struct {
+	ID         int64     `db:"id"`
+	WishlistID int64     `db:"wishlist_id"`
+	ProductID  int64     `db:"product_id"`
+	VariantID  int64     `db:"variant_id"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+var ErrWishlistExists = errors.New("user already has a wishlist with that name")
+
+// Review is a customer's rating of a product they received. Hidden reviews
+// are kept but neither listed to customers nor counted in the product's
+// rating.
+type Review struct {
+	ID        int64      `db:"id"`
+	ProductID int64      `db:"product_id"`
+	UserID    int64      `db:"user_id"`
+	Rating    int64      `db:"rating"`
+	Title     string     `db:"title"`
+	Body      string     `db:"body"`
+	Hidden    bool       `db:"hidden"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
+
+var ErrReviewExists = errors.New("product already reviewed by user")
+
 type User struct {
 	ID        int64      `db:"id"`
 	Name      string     `db:"name"`
 	Email     string     `db:"email"`
 	Password  string     `db:"password"`
 	IsAdmin   bool       `db:"is_admin"`
+	TaxExempt bool       `db:"tax_exempt"`
 	CreatedAt time.Time  `db:"created_at"`
 	UpdatedAt *time.Time `db:"updated_at"`
 }
@@ -95,8 +1082,13 @@ type NotificationEvent struct {
 	UserEmail   string      `db:"user_email"`
 	OrderStatus OrderStatus `db:"order_status"`
 	OrderID     int64       `db:"order_id"`
-	StateID     int64       `db:"state_id"`
-	Attempts    int64       `db:"attempts"`
-	CreatedAt   time.Time   `db:"created_at"`
-	UpdatedAt   *time.Time  `db:"updated_at"`
+	// ReturnID and ReturnStatus are set on events about a return of the
+	// order rather than the order itself.
+	ReturnID     *int64       `db:"return_id"`
+	ReturnStatus ReturnStatus `db:"return_status"`
+	StateID      int64        `db:"state_id"`
+	Attempts     int64        `db:"attempts"`
+	LockedUntil  *time.Time   `db:"locked_until"`
+	CreatedAt    time.Time    `db:"created_at"`
+	UpdatedAt    *time.Time   `db:"updated_at"`
 }
@@ -14,6 +14,15 @@ import (
 	gomail "gopkg.in/mail.v2"
 )
 
+// Constants
+const (
+	pollInterval       = 30 * time.Second
+	claimBatchSize     = 50
+	maxConcurrentSends = 10
+	// claimed events not acknowledged within the lease are sent again
+	claimLease = 5 * time.Minute
+)
+
 type Server struct {
 	client    pb.EcomClient
 	adminInfo *AdminInfo
@@ -32,7 +41,7 @@ func NewServer(client pb.EcomClient, adminInfo *AdminInfo) *Server {
 }
 
 func (s *Server) Run(ctx context.Context) {
-	ticker := time.NewTicker(30 * time.Second)
+	ticker := time.NewTicker(pollInterval)
 	defer ticker.Stop()
 
 	for {
@@ -51,20 +60,44 @@ func (s *Server) Run(ctx context.Context) {
 	}
 }
 
+// processNotificationEvents drains the outbox batch by batch. Delivery is at
+// least once: an event only leaves the queue once its send is acknowledged,
+// so if the relay dies or the acknowledgement fails the event is claimed and
+// sent again after its lease expires.
 func (s *Server) processNotificationEvents(ctx context.Context) error {
-	res, err := s.client.ListNotificationEvents(ctx, &pb.ListNotificationEventsReq{})
-	if err != nil {
-		return err
+	for {
+		res, err := s.client.ClaimNotificationEvents(ctx, &pb.ClaimNotificationEventsReq{
+			Limit:        claimBatchSize,
+			LeaseSeconds: int64(claimLease / time.Second),
+		})
+		if err != nil {
+			return err
+		}
+
+		err = s.sendNotificationEvents(ctx, res.Events)
+		if err != nil {
+			return err
+		}
+
+		if len(res.Events) < claimBatchSize {
+			return nil
+		}
 	}
+}
 
+// sendNotificationEvents sends events concurrently and waits until every
+// outcome has been reported, so batches never overlap.
+func (s *Server) sendNotificationEvents(ctx context.Context, events []*pb.NotificationEvent) error {
 	var wg sync.WaitGroup
-	sem := semaphore.NewWeighted(10)
-	for _, ev := range res.Events {
-		wg.Add(1)
+	defer wg.Wait()
+
+	sem := semaphore.NewWeighted(maxConcurrentSends)
+	for _, ev := range events {
 		if err := sem.Acquire(ctx, 1); err != nil {
 			return err
 		}
 
+		wg.Add(1)
 		go func(ev *pb.NotificationEvent) {
 			defer sem.Release(1)
 			defer wg.Done()
@@ -76,10 +109,6 @@ func (s *Server) processNotificationEvents(ctx context.Context) error {
 		}(ev)
 	}
 
-	go func() {
-		wg.Wait()
-	}()
-
 	return nil
 }
 
@@ -88,7 +117,7 @@ func (s *Server) sendNotification(ctx context.Context, ev *pb.NotificationEvent)
 	m.SetHeader("From", s.adminInfo.Email)
 	m.SetHeader("To", ev.GetUserEmail())
 	m.SetHeader("Subject", "email from ecom")
-	m.SetBody("text/plain", fmt.Sprintf("Order %d is %s", ev.GetOrderId(), strings.ToLower(ev.GetOrderStatus().String())))
+	m.SetBody("text/plain", notificationBody(ev))
 
 	d := gomail.NewDialer("smtp.gmail.com", 587, s.adminInfo.Email, s.adminInfo.Password)
 	d.TLSConfig = &tls.Config{InsecureSkipVerify: true}
@@ -100,6 +129,16 @@ func (s *Server) sendNotification(ctx context.Context, ev *pb.NotificationEvent)
 	return nil
 }
 
+// notificationBody describes what the event reports: a change to the order
+// or to one of its returns.
+func notificationBody(ev *pb.NotificationEvent) string {
+	if ev.GetReturnId() != 0 {
+		return fmt.Sprintf("Return %d for order %d is %s", ev.GetReturnId(), ev.GetOrderId(), ev.GetReturnStatus())
+	}
+
+	return fmt.Sprintf("Order %d is %s", ev.GetOrderId(), strings.ToLower(ev.GetOrderStatus().String()))
+}
+
 func (s *Server) updateNotificationEvent(ctx context.Context, ev *pb.NotificationEvent, err error) error {
 	req := &pb.UpdateNotificationEventReq{
 		Id:      ev.GetId(),
@@ -0,0 +1,91 @@
+// Package invoice renders invoices and credit notes as HTML and PDF. It only
+// lays out a Document; numbering and storing documents is up to the caller.
+package invoice
+
+import (
+	"strings"
+	"time"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+)
+
+// Document is an invoice or a credit note, ready to render.
+type Document struct {
+	// Title is what the document is, e.g. "Invoice" or "Credit note".
+	Title  string
+	Number string
+	// Reference relates the document to another one, e.g. the invoice a
+	// credit note is issued against. Optional.
+	Reference string
+	IssuedAt  time.Time
+	Seller    Party
+	Buyer     Party
+	// ShipTo is omitted when it is empty.
+	ShipTo Party
+	Lines  []Line
+	// Totals are printed below the lines in order, the last one being the
+	// amount due or credited.
+	Totals []Total
+	Notes  string
+}
+
+// Party is the seller or the buyer, printed as a block of lines under Name.
+type Party struct {
+	Name    string
+	Address []string
+	Email   string
+	TaxID   string
+}
+
+func (p Party) IsZero() bool {
+	return p.Name == "" && len(p.Address) == 0 && p.Email == "" && p.TaxID == ""
+}
+
+// Lines returns the block printed for the party.
+func (p Party) Lines() []string {
+	var ls []string
+	if p.Name != "" {
+		ls = append(ls, p.Name)
+	}
+	ls = append(ls, p.Address...)
+	if p.Email != "" {
+		ls = append(ls, p.Email)
+	}
+	if p.TaxID != "" {
+		ls = append(ls, "Tax ID: "+p.TaxID)
+	}
+	return ls
+}
+
+// Line is a line of the document. TaxRate is in basis points (1000 is 10%)
+// and Amount is the line total as charged, i.e. including tax when prices
+// are tax-inclusive.
+type Line struct {
+	Description string
+	Quantity    int64
+	UnitPrice   money.Money
+	TaxRate     int64
+	Tax         money.Money
+	Amount      money.Money
+}
+
+type Total struct {
+	Label  string
+	Amount money.Money
+}
+
+// formatMoney formats m with its currency, e.g. "19.99 USD".
+func formatMoney(m money.Money) string {
+	return m.String() + " " + m.Currency
+}
+
+// formatRate formats a rate in basis points as a percentage, e.g. "8.25%".
+func formatRate(rate int64) string {
+	s := money.New(rate, "").String()
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	return s + "%"
+}
+
+func formatDate(t time.Time) string {
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,78 @@
+package invoice
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+)
+
+var htmlTemplate = template.Must(template.New("document").Funcs(template.FuncMap{
+	"money": formatMoney,
+	"rate":  formatRate,
+	"date":  formatDate,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} {{.Number}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: 14px; color: #222; max-width: 800px; margin: 40px auto; }
+h1 { margin-bottom: 4px; }
+.meta { color: #555; margin-bottom: 24px; }
+.parties { display: flex; gap: 40px; margin-bottom: 24px; }
+.parties div { flex: 1; }
+.parties h2 { font-size: 12px; text-transform: uppercase; color: #555; }
+table { width: 100%; border-collapse: collapse; }
+th, td { padding: 6px 4px; border-bottom: 1px solid #ddd; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+.totals td { border: none; }
+.totals tr:last-child td { font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="meta">
+<div>Number: {{.Number}}</div>
+<div>Date: {{date .IssuedAt}}</div>
+{{- if .Reference}}
+<div>{{.Reference}}</div>
+{{- end}}
+</div>
+<div class="parties">
+<div><h2>From</h2>{{range .Seller.Lines}}<div>{{.}}</div>{{end}}</div>
+<div><h2>Bill to</h2>{{range .Buyer.Lines}}<div>{{.}}</div>{{end}}</div>
+{{- if not .ShipTo.IsZero}}
+<div><h2>Ship to</h2>{{range .ShipTo.Lines}}<div>{{.}}</div>{{end}}</div>
+{{- end}}
+</div>
+<table>
+<thead>
+<tr><th>Description</th><th>Qty</th><th>Unit price</th><th>Tax rate</th><th>Tax</th><th>Amount</th></tr>
+</thead>
+<tbody>
+{{- range .Lines}}
+<tr><td>{{.Description}}</td><td>{{.Quantity}}</td><td>{{money .UnitPrice}}</td><td>{{rate .TaxRate}}</td><td>{{money .Tax}}</td><td>{{money .Amount}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+<table class="totals">
+{{- range .Totals}}
+<tr><td></td><td>{{.Label}}</td><td>{{money .Amount}}</td></tr>
+{{- end}}
+</table>
+{{- if .Notes}}
+<p>{{.Notes}}</p>
+{{- end}}
+</body>
+</html>
+`))
+
+// RenderHTML writes d as a standalone HTML page.
+func RenderHTML(w io.Writer, d *Document) error {
+	err := htmlTemplate.Execute(w, d)
+	if err != nil {
+		return fmt.Errorf("error rendering html: %w", err)
+	}
+
+	return nil
+}
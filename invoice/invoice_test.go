@@ -0,0 +1,98 @@
+package invoice
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDocument(lines int) *Document {
+	d := &Document{
+		Title:    "Invoice",
+		Number:   "INV-000042",
+		IssuedAt: time.Date(2025, 6, 17, 10, 0, 0, 0, time.UTC),
+		Seller:   Party{Name: "Shop (Ltd)", Address: []string{"1 Main St", "Springfield, US"}, TaxID: "US123"},
+		Buyer:    Party{Name: "Zoë <Buyer>", Email: "buyer@example.com"},
+		Totals: []Total{
+			{Label: "Subtotal", Amount: money.New(1999, "USD")},
+			{Label: "Total", Amount: money.New(2199, "USD")},
+		},
+		Notes: "Thank you for your order.",
+	}
+	for i := 0; i < lines; i++ {
+		d.Lines = append(d.Lines, Line{
+			Description: "test product " + strconv.Itoa(i),
+			Quantity:    1,
+			UnitPrice:   money.New(1999, "USD"),
+			TaxRate:     1000,
+			Tax:         money.New(200, "USD"),
+			Amount:      money.New(1999, "USD"),
+		})
+	}
+	return d
+}
+
+func TestRenderHTML(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, RenderHTML(&buf, newTestDocument(2)))
+
+	html := buf.String()
+	require.Contains(t, html, "<title>Invoice INV-000042</title>")
+	require.Contains(t, html, "Date: 2025-06-17")
+	require.Contains(t, html, "Zoë &lt;Buyer&gt;")
+	require.Contains(t, html, "<td>test product 1</td><td>1</td><td>19.99 USD</td><td>10%</td>")
+	require.Contains(t, html, "Tax ID: US123")
+	require.NotContains(t, html, "Ship to")
+}
+
+func TestRenderPDF(t *testing.T) {
+	tcs := []struct {
+		name  string
+		lines int
+		pages int
+	}{
+		{name: "one page", lines: 3, pages: 1},
+		{name: "many lines", lines: 120, pages: 3},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, RenderPDF(&buf, newTestDocument(tc.lines)))
+
+			pdf := buf.Bytes()
+			require.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")))
+			require.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+			require.Contains(t, string(pdf), "/Count "+strconv.Itoa(tc.pages))
+			require.Contains(t, string(pdf), `(Shop \(Ltd\)) Tj`)
+			require.Contains(t, string(pdf), "(Zo\xeb <Buyer>) Tj")
+
+			// every xref entry points at its object
+			m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+			require.NotNil(t, m)
+			xref, err := strconv.Atoi(string(m[1]))
+			require.NoError(t, err)
+			require.True(t, bytes.HasPrefix(pdf[xref:], []byte("xref\n")))
+
+			entries := strings.Split(string(pdf[xref:]), "\n")[3:]
+			for i := 1; i <= 6+2*tc.pages; i++ {
+				off, err := strconv.Atoi(entries[i-1][:10])
+				require.NoError(t, err)
+				require.True(t, bytes.HasPrefix(pdf[off:], []byte(strconv.Itoa(i)+" 0 obj\n")), "object %d", i)
+			}
+		})
+	}
+}
+
+func TestFormatRate(t *testing.T) {
+	require.Equal(t, "10%", formatRate(1000))
+	require.Equal(t, "8.25%", formatRate(825))
+	require.Equal(t, "7.5%", formatRate(750))
+	require.Equal(t, "0%", formatRate(0))
+}
@@ -0,0 +1,280 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Page layout in points, on A4 paper.
+const (
+	pageWidth  = 595
+	pageHeight = 842
+	margin     = 50
+	lineHeight = 14
+	// width of a column in the block of parties
+	partyWidth = 165
+)
+
+// Right edges of the columns of the lines table.
+const (
+	colQuantity  = 320
+	colUnitPrice = 390
+	colTaxRate   = 440
+	colTax       = 490
+	colAmount    = pageWidth - margin
+)
+
+// Fonts, named as in every page's resources. Only the standard 14 fonts are
+// used, so nothing has to be embedded. Courier is monospaced, which is what
+// right-aligned columns rely on.
+const (
+	fontRegular  = "F1"
+	fontBold     = "F2"
+	fontMono     = "F3"
+	fontMonoBold = "F4"
+)
+
+var baseFonts = []string{"Helvetica", "Helvetica-Bold", "Courier", "Courier-Bold"}
+
+// RenderPDF writes d as a PDF document. Lines that do not fit on the first
+// page continue on the following ones.
+func RenderPDF(w io.Writer, d *Document) error {
+	pw := &pdfWriter{}
+	pw.newPage()
+
+	pw.text(fontBold, 18, margin, d.Title)
+	pw.y -= 24
+	pw.text(fontRegular, 10, margin, "Number: "+d.Number)
+	pw.y -= lineHeight
+	pw.text(fontRegular, 10, margin, "Date: "+formatDate(d.IssuedAt))
+	pw.y -= lineHeight
+	if d.Reference != "" {
+		pw.text(fontRegular, 10, margin, d.Reference)
+		pw.y -= lineHeight
+	}
+	pw.y -= lineHeight
+
+	pw.parties(d)
+	pw.linesHeader()
+	for _, l := range d.Lines {
+		if pw.y < margin+lineHeight {
+			pw.newPage()
+			pw.linesHeader()
+		}
+		pw.text(fontRegular, 9, margin, truncate(l.Description, 42))
+		pw.textRight(fontMono, 9, colQuantity, strconv.FormatInt(l.Quantity, 10))
+		pw.textRight(fontMono, 9, colUnitPrice, l.UnitPrice.String())
+		pw.textRight(fontMono, 9, colTaxRate, formatRate(l.TaxRate))
+		pw.textRight(fontMono, 9, colTax, l.Tax.String())
+		pw.textRight(fontMono, 9, colAmount, l.Amount.String())
+		pw.y -= lineHeight
+	}
+	pw.y -= lineHeight
+
+	for i, t := range d.Totals {
+		if pw.y < margin {
+			pw.newPage()
+		}
+		font, mono := fontRegular, fontMono
+		if i == len(d.Totals)-1 {
+			font, mono = fontBold, fontMonoBold
+		}
+		pw.text(font, 10, colUnitPrice-60, t.Label)
+		pw.textRight(mono, 10, colAmount, formatMoney(t.Amount))
+		pw.y -= lineHeight
+	}
+
+	if d.Notes != "" {
+		pw.y -= lineHeight
+		for _, l := range wrap(d.Notes, 95) {
+			if pw.y < margin {
+				pw.newPage()
+			}
+			pw.text(fontRegular, 9, margin, l)
+			pw.y -= 12
+		}
+	}
+
+	_, err := pw.writeTo(w)
+	if err != nil {
+		return fmt.Errorf("error rendering pdf: %w", err)
+	}
+
+	return nil
+}
+
+// pdfWriter lays out text on pages, top to bottom, and serializes them as a
+// minimal PDF.
+type pdfWriter struct {
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	// baseline of the current line
+	y float64
+}
+
+func (pw *pdfWriter) newPage() {
+	pw.page = &bytes.Buffer{}
+	pw.pages = append(pw.pages, pw.page)
+	pw.y = pageHeight - margin
+}
+
+// text writes s on the current line with its left edge at x.
+func (pw *pdfWriter) text(font string, size, x float64, s string) {
+	fmt.Fprintf(pw.page, "BT /%s %.1f Tf %.2f %.2f Td (", font, size, x, pw.y)
+	writePDFString(pw.page, s)
+	pw.page.WriteString(") Tj ET\n")
+}
+
+// textRight writes s in a Courier font on the current line with its right
+// edge at x. Courier glyphs are all 0.6 em wide.
+func (pw *pdfWriter) textRight(font string, size, x float64, s string) {
+	width := float64(utf8.RuneCountInString(s)) * size * 0.6
+	pw.text(font, size, x-width, s)
+}
+
+func (pw *pdfWriter) rule() {
+	fmt.Fprintf(pw.page, "0.5 w %d %.2f m %d %.2f l S\n", margin, pw.y, pageWidth-margin, pw.y)
+}
+
+// parties writes the seller, buyer and ship-to blocks side by side.
+func (pw *pdfWriter) parties(d *Document) {
+	cols := []struct {
+		title string
+		party Party
+	}{
+		{"FROM", d.Seller},
+		{"BILL TO", d.Buyer},
+	}
+	if !d.ShipTo.IsZero() {
+		cols = append(cols, struct {
+			title string
+			party Party
+		}{"SHIP TO", d.ShipTo})
+	}
+
+	top, bottom := pw.y, pw.y
+	for i, c := range cols {
+		x := float64(margin + i*partyWidth)
+		pw.y = top
+		pw.text(fontBold, 9, x, c.title)
+		pw.y -= lineHeight
+		for _, l := range c.party.Lines() {
+			pw.text(fontRegular, 9, x, truncate(l, 32))
+			pw.y -= 12
+		}
+		bottom = min(bottom, pw.y)
+	}
+	pw.y = bottom - lineHeight
+}
+
+func (pw *pdfWriter) linesHeader() {
+	pw.text(fontBold, 9, margin, "Description")
+	pw.textRight(fontMonoBold, 9, colQuantity, "Qty")
+	pw.textRight(fontMonoBold, 9, colUnitPrice, "Price")
+	pw.textRight(fontMonoBold, 9, colTaxRate, "Rate")
+	pw.textRight(fontMonoBold, 9, colTax, "Tax")
+	pw.textRight(fontMonoBold, 9, colAmount, "Amount")
+	pw.y -= 5
+	pw.rule()
+	pw.y -= lineHeight
+}
+
+// writeTo serializes the pages. Objects are numbered: 1 the catalog, 2 the
+// page tree, then the fonts, then a page and its content stream for every
+// page.
+func (pw *pdfWriter) writeTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(format string, args ...any) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n", len(offsets))
+		fmt.Fprintf(&buf, format, args...)
+		buf.WriteString("\nendobj\n")
+	}
+
+	firstPage := 3 + len(baseFonts)
+	kids := make([]string, 0, len(pw.pages))
+	for i := range pw.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", firstPage+2*i))
+	}
+
+	var fonts strings.Builder
+	for i := range baseFonts {
+		fmt.Fprintf(&fonts, "/F%d %d 0 R ", i+1, 3+i)
+	}
+
+	// the comment with high bytes marks the file as binary
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pw.pages))
+	for _, f := range baseFonts {
+		object("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", f)
+	}
+	for i, p := range pw.pages {
+		object("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << %s>> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, fonts.String(), firstPage+2*i+1)
+		object("<< /Length %d >>\nstream\n%sendstream", p.Len(), p.Bytes())
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.WriteTo(w)
+}
+
+// writePDFString writes s as the contents of a PDF literal string in
+// WinAnsiEncoding. Characters the encoding lacks are replaced by '?'.
+func writePDFString(buf *bytes.Buffer, s string) {
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			buf.WriteByte('\\')
+			buf.WriteByte(byte(r))
+		case r < ' ':
+			buf.WriteByte(' ')
+		case r < 0x7f || r >= 0xa0 && r <= 0xff:
+			// WinAnsiEncoding matches Latin-1 in these ranges
+			buf.WriteByte(byte(r))
+		default:
+			buf.WriteByte('?')
+		}
+	}
+}
+
+// truncate shortens s to at most n runes, marking the cut with "...".
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	r := []rune(s)
+	return string(r[:n-3]) + "..."
+}
+
+// wrap splits s into lines of at most n runes, breaking between words.
+func wrap(s string, n int) []string {
+	var lines []string
+	var cur string
+	for _, word := range strings.Fields(s) {
+		switch {
+		case cur == "":
+			cur = word
+		case utf8.RuneCountInString(cur)+1+utf8.RuneCountInString(word) <= n:
+			cur += " " + word
+		default:
+			lines = append(lines, cur)
+			cur = word
+		}
+	}
+	if cur != "" {
+		lines = append(lines, cur)
+	}
+	return lines
+}
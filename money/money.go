@@ -0,0 +1,238 @@
+// Package money represents monetary amounts exactly, as an integer number of
+// minor units (cents for USD) together with an ISO 4217 currency code.
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency the store prices in. Amounts read from the
+// database are in this currency, and only amounts in it can be written back.
+const DefaultCurrency = "USD"
+
+// basisPoints is the denominator of rates passed to MulRate.
+const basisPoints = 10000
+
+var (
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+// currencies that do not use two decimal places
+var minorDigits = map[string]int{
+	"BHD": 3,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"OMR": 3,
+	"VND": 0,
+}
+
+// Money is an amount in the minor unit of its currency, so 19.99 USD is
+// {Amount: 1999, Currency: "USD"}.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Parse reads a decimal string such as "19.99" or "-5" as an amount of
+// currency. It never rounds: more fractional digits than the currency has
+// minor units is an error.
+func Parse(s, currency string) (Money, error) {
+	digits := Digits(currency)
+	str := strings.TrimSpace(s)
+
+	neg := false
+	if strings.HasPrefix(str, "-") || strings.HasPrefix(str, "+") {
+		neg = str[0] == '-'
+		str = str[1:]
+	}
+
+	whole, frac, _ := strings.Cut(str, ".")
+	if whole == "" && frac == "" || len(frac) > digits || !isDigits(whole) || !isDigits(frac) {
+		return Money{}, fmt.Errorf("%w %q for %s", ErrInvalidAmount, s, currency)
+	}
+	frac += strings.Repeat("0", digits-len(frac))
+
+	amount, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w %q for %s", ErrInvalidAmount, s, currency)
+	}
+	if neg {
+		amount = -amount
+	}
+
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Digits returns the number of minor unit digits of currency.
+func Digits(currency string) int {
+	if d, ok := minorDigits[currency]; ok {
+		return d
+	}
+	return 2
+}
+
+// String formats m as a plain decimal, e.g. "19.99".
+func (m Money) String() string {
+	digits := Digits(m.Currency)
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	s := strconv.FormatInt(amount, 10)
+	if digits == 0 {
+		return sign + s
+	}
+	if len(s) <= digits {
+		s = strings.Repeat("0", digits-len(s)+1) + s
+	}
+
+	return sign + s[:len(s)-digits] + "." + s[len(s)-digits:]
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Cmp compares m and o, returning -1, 0 or +1. Both must be in the same
+// currency.
+func (m Money) Cmp(o Money) int {
+	m.mustMatch(o)
+	switch {
+	case m.Amount < o.Amount:
+		return -1
+	case m.Amount > o.Amount:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Add returns m+o. Adding amounts in different currencies is a programming
+// error and panics; the zero Money takes the currency of the other operand.
+func (m Money) Add(o Money) Money {
+	return Money{Amount: m.Amount + o.Amount, Currency: m.mustMatch(o)}
+}
+
+// Sub returns m-o, with the same currency rules as Add.
+func (m Money) Sub(o Money) Money {
+	return Money{Amount: m.Amount - o.Amount, Currency: m.mustMatch(o)}
+}
+
+// Mul returns m multiplied by a whole quantity.
+func (m Money) Mul(n int64) Money {
+	return Money{Amount: m.Amount * n, Currency: m.Currency}
+}
+
+// MulRate returns m multiplied by rate basis points (1000 is 10%), rounded
+// to the nearest minor unit with halves rounded away from zero. This is the
+// rounding rule for tax and percentage discounts.
+func (m Money) MulRate(rate int64) Money {
+	return Money{Amount: divRound(m.Amount*rate, basisPoints), Currency: m.Currency}
+}
+
+// IncludedRate returns the part of m that a rate of rate basis points added
+// on top of a net amount, i.e. the tax contained in a tax-inclusive price,
+// rounded like MulRate.
+func (m Money) IncludedRate(rate int64) Money {
+	return Money{Amount: divRound(m.Amount*rate, basisPoints+rate), Currency: m.Currency}
+}
+
+// MulFrac returns m multiplied by num/den for a positive den, rounded like
+// MulRate. It prorates an amount, e.g. a line's share of an order discount.
+func (m Money) MulFrac(num, den int64) Money {
+	return Money{Amount: divRound(m.Amount*num, den), Currency: m.Currency}
+}
+
+// divRound divides p by a positive d, rounding halves away from zero.
+func divRound(p, d int64) int64 {
+	q, r := p/d, p%d
+	if r < 0 {
+		r = -r
+	}
+	if r*2 >= d {
+		if p < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+
+	return q
+}
+
+func (m Money) mustMatch(o Money) string {
+	switch {
+	case m.Currency == o.Currency, o.Currency == "":
+		return m.Currency
+	case m.Currency == "":
+		return o.Currency
+	default:
+		panic(fmt.Sprintf("money: %v: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency))
+	}
+}
+
+// Scan reads a DECIMAL column as an amount in DefaultCurrency.
+func (m *Money) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case int64:
+		*m = Money{Amount: v, Currency: DefaultCurrency}.Mul(pow10(Digits(DefaultCurrency)))
+		return nil
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("error scanning %T into money", src)
+	}
+
+	parsed, err := Parse(s, DefaultCurrency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+
+	return nil
+}
+
+// Value writes m as a decimal string. Only amounts in DefaultCurrency can be
+// stored, since the price columns carry no currency of their own.
+func (m Money) Value() (driver.Value, error) {
+	if m.Currency != DefaultCurrency && m.Currency != "" {
+		return nil, fmt.Errorf("error storing %s amount: %w with %s", m.Currency, ErrCurrencyMismatch, DefaultCurrency)
+	}
+
+	return m.String(), nil
+}
+
+func pow10(n int) int64 {
+	p := int64(1)
+	for range n {
+		p *= 10
+	}
+	return p
+}
@@ -0,0 +1,111 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tcs := []struct {
+		name     string
+		in       string
+		currency string
+		want     int64
+		wantErr  bool
+	}{
+		{name: "cents", in: "19.99", currency: "USD", want: 1999},
+		{name: "whole", in: "10", currency: "USD", want: 1000},
+		{name: "one digit", in: "0.5", currency: "USD", want: 50},
+		{name: "negative", in: "-0.05", currency: "USD", want: -5},
+		{name: "no minor units", in: "1500", currency: "JPY", want: 1500},
+		{name: "three digits", in: "1.234", currency: "KWD", want: 1234},
+		{name: "too precise", in: "1.234", currency: "USD", wantErr: true},
+		{name: "garbage", in: "1,00", currency: "USD", wantErr: true},
+		{name: "empty", in: "", currency: "USD", wantErr: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := Parse(tc.in, tc.currency)
+			if tc.wantErr {
+				require.ErrorIs(t, err, ErrInvalidAmount)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, New(tc.want, tc.currency), m)
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	require.Equal(t, "19.99", New(1999, "USD").String())
+	require.Equal(t, "0.05", New(5, "USD").String())
+	require.Equal(t, "-0.05", New(-5, "USD").String())
+	require.Equal(t, "1500", New(1500, "JPY").String())
+	require.Equal(t, "1.234", New(1234, "KWD").String())
+}
+
+func TestMulRate(t *testing.T) {
+	tcs := []struct {
+		name   string
+		amount int64
+		rate   int64
+		want   int64
+	}{
+		{name: "exact", amount: 10000, rate: 1000, want: 1000},
+		{name: "rounds down", amount: 8544, rate: 1000, want: 854},
+		{name: "half rounds up", amount: 8545, rate: 1000, want: 855},
+		{name: "negative half rounds away from zero", amount: -8545, rate: 1000, want: -855},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, New(tc.want, "USD"), New(tc.amount, "USD").MulRate(tc.rate))
+		})
+	}
+}
+
+func TestIncludedRate(t *testing.T) {
+	// 120.00 at 20% VAT contains 20.00
+	require.Equal(t, New(2000, "EUR"), New(12000, "EUR").IncludedRate(2000))
+	// 19.99 at 10% contains 1.8172..., rounded to 1.82
+	require.Equal(t, New(182, "USD"), New(1999, "USD").IncludedRate(1000))
+	require.Equal(t, New(0, "USD"), New(1999, "USD").IncludedRate(0))
+}
+
+func TestMulFrac(t *testing.T) {
+	// a third of 10.00 is 3.333..., rounded to 3.33
+	require.Equal(t, New(333, "USD"), New(1000, "USD").MulFrac(1, 3))
+	// two thirds is 6.666..., rounded to 6.67
+	require.Equal(t, New(667, "USD"), New(1000, "USD").MulFrac(2, 3))
+	require.Equal(t, New(-500, "USD"), New(-1000, "USD").MulFrac(1, 2))
+}
+
+func TestArithmetic(t *testing.T) {
+	var total Money
+	total = total.Add(New(1999, "USD").Mul(2))
+	require.Equal(t, New(3998, "USD"), total)
+	require.Equal(t, New(3000, "USD"), total.Sub(New(998, "USD")))
+	require.Equal(t, 1, total.Cmp(New(1, "USD")))
+
+	require.Panics(t, func() { New(1, "USD").Add(New(1, "EUR")) })
+}
+
+func TestScanValue(t *testing.T) {
+	var m Money
+	require.NoError(t, m.Scan([]byte("19.99")))
+	require.Equal(t, New(1999, DefaultCurrency), m)
+	require.NoError(t, m.Scan(int64(5)))
+	require.Equal(t, New(500, DefaultCurrency), m)
+	require.NoError(t, m.Scan(45.5))
+	require.Equal(t, New(4550, DefaultCurrency), m)
+	require.Error(t, m.Scan(nil))
+
+	v, err := New(1999, DefaultCurrency).Value()
+	require.NoError(t, err)
+	require.Equal(t, "19.99", v)
+
+	_, err = New(1999, "EUR").Value()
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+}
@@ -0,0 +1,218 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+)
+
+// Sources the fake provider understands. Any other source is approved.
+const (
+	FakeSourceApprove = "tok_approve"
+	FakeSourceDecline = "tok_decline"
+	// FakeSourcePending payments stay pending for the provider's delay and
+	// are then authorized.
+	FakeSourcePending = "tok_pending"
+	// FakeSourcePendingDecline payments stay pending for the provider's delay
+	// and are then declined.
+	FakeSourcePendingDecline = "tok_pending_decline"
+	// FakeSourceError makes Authorize fail as if the provider was down.
+	FakeSourceError = "tok_error"
+)
+
+const (
+	fakeProviderName = "fake"
+	// how long the fake waits for the notifier to deliver an event
+	fakeNotifyTimeout = 10 * time.Second
+)
+
+// FakeProvider is an in-memory gateway for development and tests. It never
+// moves money, but keeps the state of every payment like a real provider
+// would and settles pending payments asynchronously, reporting the outcome
+// to its notifier.
+type FakeProvider struct {
+	delay    time.Duration
+	notifier Notifier
+
+	mu       sync.Mutex
+	seq      int64
+	payments map[string]*fakePayment
+}
+
+type fakePayment struct {
+	status   Status
+	amount   money.Money
+	captured money.Money
+	refunded money.Money
+	// refundKeys holds the keys the payment was refunded under
+	refundKeys map[string]bool
+}
+
+// NewFakeProvider returns a fake that settles pending payments after delay.
+// notifier may be nil, in which case pending payments still settle but
+// nobody is told.
+func NewFakeProvider(delay time.Duration, notifier Notifier) *FakeProvider {
+	return &FakeProvider{
+		delay:    delay,
+		notifier: notifier,
+		payments: make(map[string]*fakePayment),
+	}
+}
+
+func (fp *FakeProvider) Name() string {
+	return fakeProviderName
+}
+
+func (fp *FakeProvider) Authorize(ctx context.Context, req *AuthorizeReq) (*Result, error) {
+	if req.Amount.Amount <= 0 {
+		return nil, fmt.Errorf("cannot authorize %s: %w", req.Amount, ErrInvalidAmount)
+	}
+	if req.Source == FakeSourceError {
+		return nil, fmt.Errorf("fake provider is unavailable")
+	}
+
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+
+	fp.seq++
+	ref := fmt.Sprintf("fake_%d", fp.seq)
+	p := &fakePayment{
+		status: StatusAuthorized,
+		amount: req.Amount,
+	}
+	fp.payments[ref] = p
+
+	res := &Result{Reference: ref}
+	switch req.Source {
+	case FakeSourceDecline:
+		p.status = StatusDeclined
+		res.Message = "card declined"
+	case FakeSourcePending:
+		p.status = StatusPending
+		time.AfterFunc(fp.delay, func() { fp.settle(ref, StatusAuthorized, "") })
+	case FakeSourcePendingDecline:
+		p.status = StatusPending
+		time.AfterFunc(fp.delay, func() { fp.settle(ref, StatusDeclined, "card declined") })
+	}
+	res.Status = p.status
+
+	return res, nil
+}
+
+// settle decides a pending payment and notifies about it. Payments voided in
+// the meantime are left alone.
+func (fp *FakeProvider) settle(ref string, st Status, msg string) {
+	fp.mu.Lock()
+	p := fp.payments[ref]
+	if p.status != StatusPending {
+		fp.mu.Unlock()
+		return
+	}
+	p.status = st
+	fp.mu.Unlock()
+
+	if fp.notifier == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), fakeNotifyTimeout)
+	defer cancel()
+
+	err := fp.notifier.Notify(ctx, &Event{
+		Provider:  fakeProviderName,
+		Reference: ref,
+		Status:    st,
+		Message:   msg,
+	})
+	if err != nil {
+		log.Printf("error delivering payment event for %s: %v", ref, err)
+	}
+}
+
+// Capture captures up to the authorized amount. The rest of the
+// authorization is released.
+func (fp *FakeProvider) Capture(ctx context.Context, reference string, amount money.Money) (*Result, error) {
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+
+	p, err := fp.payment(reference, StatusAuthorized)
+	if err != nil {
+		return nil, err
+	}
+	if amount.Currency != p.amount.Currency || amount.Amount <= 0 || amount.Amount > p.amount.Amount {
+		return nil, fmt.Errorf("cannot capture %s of %s: %w", amount, p.amount, ErrInvalidAmount)
+	}
+
+	p.status = StatusCaptured
+	p.captured = amount
+
+	return &Result{Reference: reference, Status: p.status}, nil
+}
+
+func (fp *FakeProvider) Void(ctx context.Context, reference string) (*Result, error) {
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+
+	p, err := fp.payment(reference, StatusPending, StatusAuthorized)
+	if err != nil {
+		return nil, err
+	}
+	p.status = StatusVoided
+
+	return &Result{Reference: reference, Status: p.status}, nil
+}
+
+// Refund refunds part or all of a captured payment. The payment stays
+// captured until all of it is refunded. Refunding under a key used before
+// returns the payment as it is.
+func (fp *FakeProvider) Refund(ctx context.Context, reference, key string, amount money.Money) (*Result, error) {
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+
+	p, err := fp.payment(reference, StatusCaptured, StatusRefunded)
+	if err != nil {
+		return nil, err
+	}
+	if p.refundKeys[key] {
+		return &Result{Reference: reference, Status: p.status}, nil
+	}
+	if p.status != StatusCaptured {
+		return nil, fmt.Errorf("payment %s is %s: %w", reference, p.status, ErrInvalidState)
+	}
+	left := p.captured.Sub(p.refunded)
+	if amount.Currency != left.Currency || amount.Amount <= 0 || amount.Amount > left.Amount {
+		return nil, fmt.Errorf("cannot refund %s of %s: %w", amount, left, ErrInvalidAmount)
+	}
+
+	p.refunded = p.refunded.Add(amount)
+	if p.refundKeys == nil {
+		p.refundKeys = make(map[string]bool)
+	}
+	p.refundKeys[key] = true
+	if p.refunded == p.captured {
+		p.status = StatusRefunded
+	}
+
+	return &Result{Reference: reference, Status: p.status}, nil
+}
+
+// payment looks up a payment that must be in one of the given statuses.
+// Callers must hold fp.mu.
+func (fp *FakeProvider) payment(reference string, statuses ...Status) (*fakePayment, error) {
+	p, ok := fp.payments[reference]
+	if !ok {
+		return nil, fmt.Errorf("payment %s: %w", reference, ErrUnknownReference)
+	}
+
+	for _, st := range statuses {
+		if p.status == st {
+			return p, nil
+		}
+	}
+
+	return nil, fmt.Errorf("payment %s is %s: %w", reference, p.status, ErrInvalidState)
+}
@@ -0,0 +1,158 @@
+package payments
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeProvider(t *testing.T) {
+	ctx := context.Background()
+	amount := money.New(2500, "USD")
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *FakeProvider)
+	}{
+		{
+			name: "approve, capture and refund",
+			test: func(t *testing.T, fp *FakeProvider) {
+				res, err := fp.Authorize(ctx, &AuthorizeReq{OrderID: 1, Amount: amount, Source: FakeSourceApprove})
+				require.NoError(t, err)
+				require.Equal(t, StatusAuthorized, res.Status)
+				require.NotEmpty(t, res.Reference)
+
+				res, err = fp.Capture(ctx, res.Reference, amount)
+				require.NoError(t, err)
+				require.Equal(t, StatusCaptured, res.Status)
+
+				_, err = fp.Void(ctx, res.Reference)
+				require.ErrorIs(t, err, ErrInvalidState)
+
+				res, err = fp.Refund(ctx, res.Reference, "first", money.New(1000, "USD"))
+				require.NoError(t, err)
+				require.Equal(t, StatusCaptured, res.Status)
+
+				_, err = fp.Refund(ctx, res.Reference, "second", amount)
+				require.ErrorIs(t, err, ErrInvalidAmount)
+
+				res, err = fp.Refund(ctx, res.Reference, "second", money.New(1500, "USD"))
+				require.NoError(t, err)
+				require.Equal(t, StatusRefunded, res.Status)
+
+				// refunding again under a key refunds nothing
+				res, err = fp.Refund(ctx, res.Reference, "first", money.New(1000, "USD"))
+				require.NoError(t, err)
+				require.Equal(t, StatusRefunded, res.Status)
+				_, err = fp.Refund(ctx, res.Reference, "third", money.New(100, "USD"))
+				require.ErrorIs(t, err, ErrInvalidState)
+			},
+		},
+		{
+			name: "decline",
+			test: func(t *testing.T, fp *FakeProvider) {
+				res, err := fp.Authorize(ctx, &AuthorizeReq{OrderID: 1, Amount: amount, Source: FakeSourceDecline})
+				require.NoError(t, err)
+				require.Equal(t, StatusDeclined, res.Status)
+				require.NotEmpty(t, res.Message)
+
+				_, err = fp.Capture(ctx, res.Reference, amount)
+				require.ErrorIs(t, err, ErrInvalidState)
+			},
+		},
+		{
+			name: "void",
+			test: func(t *testing.T, fp *FakeProvider) {
+				res, err := fp.Authorize(ctx, &AuthorizeReq{OrderID: 1, Amount: amount, Source: FakeSourceApprove})
+				require.NoError(t, err)
+
+				_, err = fp.Capture(ctx, res.Reference, money.New(2600, "USD"))
+				require.ErrorIs(t, err, ErrInvalidAmount)
+
+				res, err = fp.Void(ctx, res.Reference)
+				require.NoError(t, err)
+				require.Equal(t, StatusVoided, res.Status)
+			},
+		},
+		{
+			name: "errors",
+			test: func(t *testing.T, fp *FakeProvider) {
+				_, err := fp.Authorize(ctx, &AuthorizeReq{OrderID: 1, Amount: amount, Source: FakeSourceError})
+				require.Error(t, err)
+
+				_, err = fp.Authorize(ctx, &AuthorizeReq{OrderID: 1, Amount: money.New(0, "USD")})
+				require.ErrorIs(t, err, ErrInvalidAmount)
+
+				_, err = fp.Capture(ctx, "fake_404", amount)
+				require.ErrorIs(t, err, ErrUnknownReference)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.test(t, NewFakeProvider(0, nil))
+		})
+	}
+}
+
+func TestFakeProviderPending(t *testing.T) {
+	ctx := context.Background()
+	amount := money.New(2500, "USD")
+
+	events := make(chan *Event, 1)
+	fp := NewFakeProvider(time.Millisecond, NotifierFunc(func(ctx context.Context, e *Event) error {
+		events <- e
+		return nil
+	}))
+
+	for source, want := range map[string]Status{
+		FakeSourcePending:        StatusAuthorized,
+		FakeSourcePendingDecline: StatusDeclined,
+	} {
+		res, err := fp.Authorize(ctx, &AuthorizeReq{OrderID: 1, Amount: amount, Source: source})
+		require.NoError(t, err)
+		require.Equal(t, StatusPending, res.Status)
+
+		e := <-events
+		require.Equal(t, &Event{Provider: "fake", Reference: res.Reference, Status: want, Message: e.Message}, e)
+	}
+
+	// a payment voided while pending is never settled
+	fp = NewFakeProvider(time.Hour, NotifierFunc(func(ctx context.Context, e *Event) error {
+		t.Errorf("unexpected event %+v", e)
+		return nil
+	}))
+	res, err := fp.Authorize(ctx, &AuthorizeReq{OrderID: 1, Amount: amount, Source: FakeSourcePending})
+	require.NoError(t, err)
+	_, err = fp.Void(ctx, res.Reference)
+	require.NoError(t, err)
+	fp.settle(res.Reference, StatusAuthorized, "")
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var got []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		if !VerifySignature("secret", body, r.Header.Get(SignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		got = body
+	}))
+	defer srv.Close()
+
+	e := &Event{Provider: "fake", Reference: "fake_1", Status: StatusAuthorized}
+	require.NoError(t, NewWebhookNotifier(srv.URL, "secret").Notify(context.Background(), e))
+	require.JSONEq(t, `{"provider":"fake","reference":"fake_1","status":"authorized","message":""}`, string(got))
+
+	require.Error(t, NewWebhookNotifier(srv.URL, "wrong").Notify(context.Background(), e))
+	require.False(t, VerifySignature("secret", got, "not hex"))
+}
@@ -0,0 +1,81 @@
+// Package payments charges orders through a payment provider. Providers
+// authorize an amount first and capture it later, so a payment can still be
+// voided before any money moves.
+package payments
+
+import (
+	"context"
+	"errors"
+
+	"github.com/OrkhanMehbaliyev/ecom-golang/money"
+)
+
+// Status is the state of a payment at the provider.
+type Status string
+
+const (
+	// StatusPending payments wait for the provider to decide. The outcome is
+	// delivered later as an Event.
+	StatusPending    Status = "pending"
+	StatusAuthorized Status = "authorized"
+	StatusCaptured   Status = "captured"
+	StatusDeclined   Status = "declined"
+	StatusVoided     Status = "voided"
+	StatusRefunded   Status = "refunded"
+	// StatusFailed payments could not be sent to the provider at all.
+	StatusFailed Status = "failed"
+)
+
+// IsActive reports whether a payment in status s may still charge, or has
+// charged, the customer.
+func (s Status) IsActive() bool {
+	switch s {
+	case StatusPending, StatusAuthorized, StatusCaptured:
+		return true
+	default:
+		return false
+	}
+}
+
+var (
+	// ErrUnknownReference is returned for a reference the provider never
+	// issued.
+	ErrUnknownReference = errors.New("unknown payment reference")
+	// ErrInvalidState is returned when a payment is not in a state the
+	// operation applies to, e.g. capturing a declined payment.
+	ErrInvalidState = errors.New("invalid payment state")
+	// ErrInvalidAmount is returned when capturing or refunding more than is
+	// left on a payment.
+	ErrInvalidAmount = errors.New("invalid payment amount")
+)
+
+// AuthorizeReq asks to reserve Amount for an order. Source identifies what is
+// charged, e.g. a card token from the provider's checkout.
+type AuthorizeReq struct {
+	OrderID int64
+	Amount  money.Money
+	Source  string
+}
+
+// Result is the outcome of an operation on a payment. Message explains
+// declines.
+type Result struct {
+	Reference string
+	Status    Status
+	Message   string
+}
+
+// Provider is a payment gateway. Declines are results, not errors; errors
+// mean the provider could not be reached or refused the request.
+type Provider interface {
+	// Name identifies the provider in stored payments and webhook routes.
+	Name() string
+	Authorize(ctx context.Context, req *AuthorizeReq) (*Result, error)
+	Capture(ctx context.Context, reference string, amount money.Money) (*Result, error)
+	// Void releases an authorization that was not captured.
+	Void(ctx context.Context, reference string) (*Result, error)
+	// Refund returns amount of a captured payment to the customer. key names
+	// the refund: refunding a payment again under a key it was refunded
+	// under already refunds nothing, so a refund can be retried safely.
+	Refund(ctx context.Context, reference, key string, amount money.Money) (*Result, error)
+}
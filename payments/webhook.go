@@ -0,0 +1,99 @@
+package payments
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of a webhook's body, keyed
+// with the shared webhook secret.
+const SignatureHeader = "X-Payment-Signature"
+
+// Event reports an asynchronous change of a payment at the provider, such as
+// a pending payment being authorized or declined.
+type Event struct {
+	Provider  string `json:"provider"`
+	Reference string `json:"reference"`
+	Status    Status `json:"status"`
+	Message   string `json:"message"`
+}
+
+// Notifier delivers events from a provider to the shop.
+type Notifier interface {
+	Notify(ctx context.Context, e *Event) error
+}
+
+// NotifierFunc adapts a function to a Notifier.
+type NotifierFunc func(ctx context.Context, e *Event) error
+
+func (f NotifierFunc) Notify(ctx context.Context, e *Event) error {
+	return f(ctx, e)
+}
+
+// WebhookNotifier posts events as signed JSON to a webhook URL, the way a
+// hosted provider would.
+type WebhookNotifier struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url, secret string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (wn *WebhookNotifier) Notify(ctx context.Context, e *Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("error marshalling payment event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wn.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(wn.secret, body))
+
+	res, err := wn.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending webhook: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook returned status %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+// Sign returns the signature of a webhook body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether signature was made for body with secret.
+func VerifySignature(secret string, body []byte, signature string) bool {
+	want, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), want)
+}